@@ -0,0 +1,240 @@
+package main
+
+import (
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/provider"
+	"di-matrix-cli/internal/scanner"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	capabilitiesFormat string
+	versionFormat      string
+)
+
+// supportedLanguages lists the languages the analyze and discover commands accept
+//
+//nolint:gochecknoglobals // CLI metadata
+var supportedLanguages = []string{"go", "nodejs", "java", "python", "rust", "ruby", "dotnet"}
+
+// outputFormats lists the output formats of each command
+var outputFormats = map[string][]string{ //nolint:gochecknoglobals // CLI metadata
+	"analyze":      {"html", "csv", "json", "xlsx", "dot", "mermaid", "sarif", "markdown"},
+	"discover":     {"table", "json"},
+	"capabilities": {"table", "json"},
+	"version":      {"text", "json"},
+	"schema":       {"json"},
+	"demo":         {"html"},
+	"diff":         {"markdown", "html", "json"},
+	"serve":        {"html", "json"},
+}
+
+// capabilitiesCmd represents the capabilities command
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Show supported languages, manifests, output formats and integrations",
+	Long: `Report what this build supports: languages, manifest file types and how each
+is parsed, output formats per command and, when a configuration is given, which
+integrations are enabled. Use --format json to check feature availability from scripts.`,
+	RunE: runCapabilities,
+}
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// manifestCapability describes how a manifest file type is handled
+type manifestCapability struct {
+	File     string `json:"file"`
+	Language string `json:"language"`
+	Support  string `json:"support"`        // parsed, ignored or unsupported
+	Note     string `json:"note,omitempty"` // why the file yields no dependencies
+}
+
+// integrationCapability describes an optional integration and whether the configuration enables it
+type integrationCapability struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// capabilities is the machine-readable feature report
+type capabilities struct {
+	Build         buildInfo               `json:"build"`
+	Languages     []string                `json:"languages"`
+	Providers     []string                `json:"providers"`
+	Manifests     []manifestCapability    `json:"manifests"`
+	OutputFormats map[string][]string     `json:"output_formats"`
+	Integrations  []integrationCapability `json:"integrations,omitempty"` // only with --config
+}
+
+func currentBuildInfo() buildInfo {
+	return buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := currentBuildInfo()
+
+	switch versionFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	case "text":
+		fmt.Printf("di-matrix-cli %s\n", info.Version)
+		fmt.Printf("Commit: %s\n", info.Commit)
+		fmt.Printf("Built: %s\n", info.BuildTime)
+		fmt.Printf("Go: %s %s\n", info.GoVersion, info.Platform)
+		fmt.Printf("Languages: %s\n", strings.Join(supportedLanguages, ", "))
+		fmt.Printf("Report formats: %s\n", strings.Join(outputFormats["analyze"], ", "))
+		return nil
+	default:
+		return configError("invalid format '%s'. Supported formats: text, json", versionFormat)
+	}
+}
+
+func runCapabilities(cmd *cobra.Command, args []string) error {
+	if capabilitiesFormat != "table" && capabilitiesFormat != "json" {
+		return configError("invalid format '%s'. Supported formats: table, json", capabilitiesFormat)
+	}
+
+	// The configuration is optional, it adds custom manifests and enabled integrations
+	var cfg *config.Config
+	if configFile != "" {
+		loaded, err := config.LoadConfig(configFile)
+		if err != nil {
+			return configError("failed to load configuration: %w", err)
+		}
+		cfg = loaded
+	}
+
+	report, err := collectCapabilities(cfg)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	if capabilitiesFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeCapabilitiesTable(os.Stdout, report)
+}
+
+// collectCapabilities builds the feature report, including configured manifests and integrations when cfg is set
+func collectCapabilities(cfg *config.Config) (*capabilities, error) {
+	fileScanner := scanner.NewScanner(nil, zap.NewNop())
+	dependencyParser := parser.NewParser()
+	if cfg != nil {
+		configured, manifestParsers, err := newScanner(cfg, nil, zap.NewNop())
+		if err != nil {
+			return nil, err
+		}
+		fileScanner = configured
+		dependencyParser.WithFileAliases(manifestParsers)
+	}
+
+	report := &capabilities{
+		Build:         currentBuildInfo(),
+		Languages:     supportedLanguages,
+		Providers:     provider.Builtin().Names(),
+		OutputFormats: outputFormats,
+	}
+
+	for _, fileType := range fileScanner.SupportedFileTypes() {
+		support, note := dependencyParser.Capability(fileType)
+		report.Manifests = append(report.Manifests, manifestCapability{
+			File:     fileType,
+			Language: fileScanner.DetectLanguageFromFile(fileType),
+			Support:  string(support),
+			Note:     note,
+		})
+	}
+
+	if cfg != nil {
+		report.Integrations = configuredIntegrations(cfg)
+	}
+
+	return report, nil
+}
+
+// configuredIntegrations lists optional integrations and whether the configuration enables them
+func configuredIntegrations(cfg *config.Config) []integrationCapability {
+	pinning := cfg.Policy.Pinning.RequireLockfile || cfg.Policy.Pinning.ForbidFloating
+	gitlabURLs := []string{cfg.GitLab.BaseURL}
+	for _, instance := range cfg.GitLab.Instances {
+		gitlabURLs = append(gitlabURLs, instance.BaseURL)
+	}
+
+	return []integrationCapability{
+		{Name: "gitlab", Enabled: cfg.UsesGitLab(), Detail: strings.Join(gitlabURLs, ", ")},
+		{Name: "gitea", Enabled: cfg.Provider == provider.Gitea, Detail: cfg.Gitea.BaseURL},
+		{Name: "local-directories", Enabled: cfg.Provider == provider.Local},
+		{
+			Name:    "maven-remote-repositories",
+			Enabled: len(cfg.Maven.RemoteRepositories) > 0,
+			Detail:  strings.Join(cfg.Maven.RemoteRepositories, ", "),
+		},
+		{
+			Name:    "package-registries",
+			Enabled: cfg.Registry.Enabled,
+			Detail:  strings.Join([]string{cfg.Registry.GoProxy, cfg.Registry.NPM, cfg.Registry.PyPI}, ", "),
+		},
+		{Name: "osv-vulnerabilities", Enabled: cfg.OSV.Enabled, Detail: cfg.OSV.BaseURL},
+		{Name: "git-submodules", Enabled: cfg.Scanner.ResolveSubmodules},
+		{Name: "service-detection", Enabled: cfg.Scanner.DetectServices},
+		{Name: "pinning-policy", Enabled: pinning},
+		{Name: "offline", Enabled: cfg.Offline || offline, Detail: cfg.Cache.Dir},
+	}
+}
+
+func writeCapabilitiesTable(out io.Writer, report *capabilities) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(writer, "di-matrix-cli %s (%s, %s)\n\n", report.Build.Version, report.Build.GoVersion,
+		report.Build.Platform)
+	_, _ = fmt.Fprintf(writer, "Languages:\t%s\n", strings.Join(report.Languages, ", "))
+	_, _ = fmt.Fprintf(writer, "Providers:\t%s\n\n", strings.Join(report.Providers, ", "))
+
+	_, _ = fmt.Fprintln(writer, "MANIFEST\tLANGUAGE\tSUPPORT\tNOTE")
+	for _, manifest := range report.Manifests {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", manifest.File, manifest.Language, manifest.Support, manifest.Note)
+	}
+
+	_, _ = fmt.Fprintln(writer, "\nCOMMAND\tOUTPUT FORMATS")
+	for _, command := range []string{"analyze", "discover", "diff", "capabilities", "version"} {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\n", command, strings.Join(report.OutputFormats[command], ", "))
+	}
+
+	if report.Integrations == nil {
+		_, _ = fmt.Fprintln(writer, "\nIntegrations: pass --config to report enabled integrations")
+	} else {
+		_, _ = fmt.Fprintln(writer, "\nINTEGRATION\tENABLED\tDETAIL")
+		for _, integration := range report.Integrations {
+			_, _ = fmt.Fprintf(writer, "%s\t%t\t%s\n", integration.Name, integration.Enabled, integration.Detail)
+		}
+	}
+
+	return writer.Flush()
+}
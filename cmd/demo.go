@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/fakegitlab"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// demoToken is the token the demo GitLab accepts
+const demoToken = "demo-token"
+
+var (
+	demoLanguage string
+	demoOutput   string
+	demoServe    string
+)
+
+// demoCmd represents the demo command
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Analyze built-in fixture repositories served by a local fake GitLab",
+	Long: `Start an in-process fake GitLab serving a small fixture organization (Go, Node.js,
+Python and Java services sharing libraries at drifting versions) and analyze it, so the
+report can be evaluated without a GitLab token. No network access is needed.
+
+With --serve, only run the fake GitLab on the given address and print a configuration
+for it, to try analyze, discover or init --interactive against it.`,
+	RunE: runDemo,
+}
+
+func runDemo(cmd *cobra.Command, args []string) error {
+	if demoServe != "" {
+		return serveDemo(cmd.Context())
+	}
+
+	if !slices.Contains(supportedLanguages, demoLanguage) {
+		return configError("invalid language '%s'. Supported languages: %s",
+			demoLanguage, strings.Join(supportedLanguages, ", "))
+	}
+
+	server := fakegitlab.New(demoToken, fakegitlab.Demo()...)
+	defer server.Close()
+	fmt.Printf("🧪 Demo GitLab with %d fixture repositories at %s\n", len(fakegitlab.Demo()), server.URL())
+
+	cfg, err := config.Defaults()
+	if err != nil {
+		return withExitCode(exitFailure, err)
+	}
+	cfg.GitLab = config.GitLabConfig{BaseURL: server.URL(), Token: demoToken}
+	cfg.Repositories = []config.RepositoryConfig{{URL: server.RepositoryURL(fakegitlab.DemoGroup)}}
+	cfg.Internal.Patterns = fakegitlab.DemoInternalPatterns
+	cfg.Output.HTMLFile = demoOutput
+	cfg.Maven.RemoteRepositories = nil // Keep the demo local and deterministic
+	cfg.Registry.Enabled = false
+
+	fmt.Printf("🎯 Analyzing %s projects only\n", demoLanguage)
+	if err := analyze(cfg, demoLanguage); err != nil {
+		return err
+	}
+	fmt.Printf("📄 Demo report: %s\n", demoOutput)
+	return nil
+}
+
+// serveDemo runs the fake GitLab on demoServe until interrupted
+func serveDemo(ctx context.Context) error {
+	listener, err := net.Listen("tcp", demoServe)
+	if err != nil {
+		return configError("failed to listen on %s: %w", demoServe, err)
+	}
+
+	server := fakegitlab.NewListener(listener, demoToken, fakegitlab.Demo()...)
+	defer server.Close()
+
+	fmt.Printf("🧪 Demo GitLab listening at %s, press Ctrl-C to stop. Example configuration:\n\n", server.URL())
+	fmt.Printf("gitlab:\n  base_url: %q\n  token: %q\n\n", server.URL(), demoToken)
+	fmt.Printf("repositories:\n  - url: %q\n\n", server.RepositoryURL(fakegitlab.DemoGroup))
+	fmt.Printf("internal:\n  patterns:\n")
+	for _, pattern := range fakegitlab.DemoInternalPatterns {
+		fmt.Printf("    - %q\n", pattern)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	return nil
+}
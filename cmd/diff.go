@@ -0,0 +1,74 @@
+package main
+
+import (
+	"di-matrix-cli/internal/diff"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat string
+	diffOutput string
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD_REPORT NEW_REPORT",
+	Short: "Compare two JSON reports and list dependency changes per project",
+	Long: `Compare two JSON reports written by analyze --json-output and list the dependencies
+added, removed, upgraded and downgraded in every project, as Markdown, HTML or JSON.
+Projects are matched by ID and dependencies by name, use it for release notes and
+change reviews.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(outputFormats["diff"], diffFormat) {
+		return configError("invalid format '%s'. Supported formats: %s", diffFormat,
+			strings.Join(outputFormats["diff"], ", "))
+	}
+
+	oldProjects, err := diff.LoadReport(args[0])
+	if err != nil {
+		return configError("failed to load old report: %w", err)
+	}
+	newProjects, err := diff.LoadReport(args[1])
+	if err != nil {
+		return configError("failed to load new report: %w", err)
+	}
+	changes := diff.NewReport(args[0], args[1], diff.Compare(oldProjects, newProjects))
+
+	if diffOutput == "" {
+		return changes.Write(cmd.OutOrStdout(), diffFormat)
+	}
+	return writeDiffFile(diffOutput, func(w io.Writer) error {
+		return changes.Write(w, diffFormat)
+	})
+}
+
+// writeDiffFile writes the change report to path, creating its directory
+func writeDiffFile(path string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.Create(path) //nolint:gosec // Path is provided by the user on purpose
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "📄 Dependency changes: %s\n", path)
+	return nil
+}
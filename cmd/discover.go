@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/provider"
+	"di-matrix-cli/internal/usecases"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	discoverLanguage string
+	discoverFormat   string
+)
+
+// discoverCmd represents the discover command
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "List detected projects without parsing dependencies",
+	Long: `Run project detection only and print the detected projects (repository, path,
+language, dependency files) as a table or JSON. Use it to verify monorepo detection
+rules quickly before running a full analysis.`,
+	Args: cobra.NoArgs,
+	RunE: runDiscover,
+}
+
+// discoveredProject is the JSON representation of a detected project
+type discoveredProject struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Repository      string   `json:"repository"`
+	Path            string   `json:"path"`
+	Language        string   `json:"language"`
+	ParentID        string   `json:"parent_id,omitempty"`
+	DependencyFiles []string `json:"dependency_files"`
+}
+
+func runDiscover(cmd *cobra.Command, args []string) error {
+	if discoverFormat != "table" && discoverFormat != "json" {
+		return configError("invalid format '%s'. Supported formats: table, json", discoverFormat)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(cfg.Timeout.AnalysisTimeoutMinutes)*time.Minute)
+	defer cancel()
+
+	// Logs share stdout with the listing, keep them to problems only
+	if discoverFormat == "json" {
+		logger.SetLevel(zap.ErrorLevel)
+	} else {
+		logger.SetLevel(zap.WarnLevel)
+	}
+	l := logger.GetLogger()
+
+	sourceProvider, err := provider.Builtin().New(cfg, l)
+	if err != nil {
+		return configError("failed to create source provider: %w", err)
+	}
+
+	fileScanner, _, err := newScanner(cfg, sourceProvider, l)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	repositoryURLs := make([]string, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		repositoryURLs[i] = repo.URL
+	}
+
+	if err := sourceProvider.CheckPermissions(ctx); err != nil {
+		return gitlabError(err)
+	}
+
+	response, err := usecases.NewDiscoverUseCase(ctx, sourceProvider, fileScanner, l).
+		WithSubmoduleResolution(cfg.Scanner.ResolveSubmodules).
+		WithRepositoryWorkers(cfg.Concurrency.RepositoryWorkers).
+		WithRepositoryTimeout(time.Duration(cfg.Timeout.RepositoryTimeoutMinutes)*time.Minute).
+		Execute(repositoryURLs, discoverLanguage)
+	if err != nil {
+		return gitlabError(fmt.Errorf("failed to discover projects: %w", err))
+	}
+
+	if response.RepositoryCount > 0 && response.FailedRepositories == response.RepositoryCount {
+		return withExitCode(exitFailure, fmt.Errorf("project detection failed in all %d repositories",
+			response.RepositoryCount))
+	}
+
+	if discoverFormat == "json" {
+		err = writeDiscoveredJSON(os.Stdout, response.Projects)
+	} else {
+		err = writeDiscoveredTable(os.Stdout, response.Projects)
+	}
+	if err != nil {
+		return err
+	}
+
+	if response.FailedRepositories > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("project detection failed in %d of %d repositories",
+			response.FailedRepositories, response.RepositoryCount))
+	}
+	return nil
+}
+
+// writeDiscoveredTable prints one row per detected project
+func writeDiscoveredTable(w io.Writer, projects []*domain.Project) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tPATH\tLANGUAGE\tDEPENDENCY FILES")
+	for _, project := range projects {
+		projectPath := project.Path
+		if projectPath == "" {
+			projectPath = "."
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			project.Repository.Name, projectPath, project.Language, strings.Join(dependencyFilePaths(project), ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write projects table: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%d projects detected\n", len(projects))
+	return nil
+}
+
+// writeDiscoveredJSON prints the detected projects as a JSON array
+func writeDiscoveredJSON(w io.Writer, projects []*domain.Project) error {
+	discovered := make([]discoveredProject, 0, len(projects))
+	for _, project := range projects {
+		discovered = append(discovered, discoveredProject{
+			ID:              project.ID,
+			Name:            project.Name,
+			Repository:      project.Repository.Name,
+			Path:            project.Path,
+			Language:        project.Language,
+			ParentID:        project.ParentID,
+			DependencyFiles: dependencyFilePaths(project),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(discovered); err != nil {
+		return fmt.Errorf("failed to encode projects: %w", err)
+	}
+	return nil
+}
+
+func dependencyFilePaths(project *domain.Project) []string {
+	paths := make([]string, 0, len(project.DependencyFiles))
+	for _, file := range project.DependencyFiles {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
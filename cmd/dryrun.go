@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/provider"
+	"di-matrix-cli/internal/usecases"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// dryRun sizes the analysis instead of running it
+var dryRun bool
+
+// estimate expands the configured groups and lists the repository trees without downloading any file, then
+// prints how many repositories, dependency files and source API calls the analysis of lang would take
+func estimate(cfg *config.Config, lang string) error {
+	timeoutMinutes := cfg.Timeout.AnalysisTimeoutMinutes
+	if timeout > 0 {
+		timeoutMinutes = timeout
+	}
+	repositoryTimeoutMinutes := cfg.Timeout.RepositoryTimeoutMinutes
+	if repoTimeout > 0 {
+		repositoryTimeoutMinutes = repoTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutMinutes)*time.Minute)
+	defer cancel()
+	ctx, release := interruptible(ctx)
+	defer release()
+
+	if debug {
+		logger.SetLevel(zap.DebugLevel)
+	}
+	l := logger.GetLogger()
+
+	sourceProvider, err := provider.Builtin().New(cfg, l)
+	if err != nil {
+		return configError("failed to create source provider: %w", err)
+	}
+
+	fileScanner, _, err := newScanner(cfg, sourceProvider, l)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	repositoryURLs := make([]string, len(cfg.Repositories))
+	for i, repo := range cfg.Repositories {
+		repositoryURLs[i] = repo.URL
+	}
+
+	if err := sourceProvider.CheckPermissions(ctx); err != nil {
+		return gitlabError(err)
+	}
+
+	statusln("🧮 Dry run: listing repositories and dependency files without downloading them...")
+	response, err := usecases.NewEstimateUseCase(ctx, sourceProvider, fileScanner, l).
+		WithSubmoduleResolution(cfg.Scanner.ResolveSubmodules).
+		WithRepositoryWorkers(cfg.Concurrency.RepositoryWorkers).
+		WithRepositoryTimeout(time.Duration(repositoryTimeoutMinutes)*time.Minute).
+		Execute(repositoryURLs, lang)
+	if err != nil {
+		return gitlabError(fmt.Errorf("failed to estimate the analysis: %w", err))
+	}
+	lastRun.Estimate = response
+
+	statusf("📦 Repositories: %d\n", response.Repositories)
+	statusf("📄 Dependency files: %d (%d %s)\n", response.DependencyFiles, response.AnalyzedFiles, lang)
+	for _, fileLanguage := range slices.Sorted(maps.Keys(response.Languages)) {
+		statusf("   %s: %d\n", fileLanguage, response.Languages[fileLanguage])
+	}
+	if response.ExcludedFiles > 0 {
+		statusf("🚫 Excluded by the scan limits: %d\n", response.ExcludedFiles)
+	}
+	statusf("🌐 Approximate API calls: %d (%d listing, %d file downloads)\n",
+		response.EstimatedAPICalls, response.ListingCalls, response.ContentCalls)
+	statusln("   Registry and advisory lookups are not included")
+
+	if ctx.Err() != nil {
+		return withExitCode(exitInterrupted, fmt.Errorf("dry run interrupted: %w", ctx.Err()))
+	}
+	if response.FailedRepositories > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("listing failed in %d of %d repositories",
+			response.FailedRepositories, response.Repositories))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/dtrack"
+	"time"
+)
+
+// uploadSBOMs uploads a CycloneDX SBOM of every analyzed project to the configured Dependency-Track server, if any
+func uploadSBOMs(ctx context.Context, cfg *config.Config, projects []*domain.Project) error {
+	if cfg.DependencyTrack.URL == "" || len(projects) == 0 {
+		return nil
+	}
+	client := dtrack.NewClient(cfg.DependencyTrack.URL, cfg.DependencyTrack.APIKey, cfg.Retry.Registry.Policy())
+
+	generatedAt := time.Now()
+	for _, project := range projects {
+		bom := dtrack.NewBOM(project, version, generatedAt)
+		if _, err := client.UploadBOM(ctx, project.Name, cfg.DependencyTrack.ProjectVersion, bom); err != nil {
+			return err
+		}
+	}
+	statusf("🛡️  SBOMs of %d projects uploaded to Dependency-Track (%s)\n", len(projects), cfg.DependencyTrack.URL)
+	return nil
+}
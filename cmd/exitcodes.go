@@ -0,0 +1,66 @@
+package main
+
+import (
+	"di-matrix-cli/internal/gitea"
+	"di-matrix-cli/internal/gitlab"
+	"errors"
+	"fmt"
+)
+
+// Exit codes returned by the CLI, documented in the README for CI pipelines
+const (
+	exitOK           = 0   // Analysis completed for every repository
+	exitFailure      = 1   // Analysis failed as a whole (no repository could be analyzed, report not written)
+	exitConfigError  = 2   // Invalid flags or configuration
+	exitAuthFailure  = 3   // GitLab rejected the token or its scopes
+	exitPartial      = 4   // Report written, but some repositories or projects could not be analyzed
+	exitPolicyFailed = 5   // Report written, but policy violations were found
+	exitInterrupted  = 130 // Interrupted (SIGINT/SIGTERM or timeout), partial report and checkpoint written
+)
+
+// exitError carries the exit code a command failure should terminate the process with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode attaches an exit code to err, nil stays nil
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// configError reports invalid flags or configuration
+func configError(format string, args ...any) error {
+	return withExitCode(exitConfigError, fmt.Errorf(format, args...))
+}
+
+// gitlabError classifies GitLab and Gitea failures as authentication failures or total failures
+func gitlabError(err error) error {
+	if gitlab.IsAuthError(err) || gitea.IsAuthError(err) {
+		return withExitCode(exitAuthFailure, err)
+	}
+	return withExitCode(exitFailure, err)
+}
+
+// exitCode returns the process exit code for the error returned by a command
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var coded *exitError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return exitFailure
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"crypto/sha256"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/state"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// loadIncrementalState reads the state of the previous incremental run. A missing state, or one recorded for
+// another language or other settings, starts over with an empty state so every repository is analyzed.
+func loadIncrementalState(cfg *config.Config, lang string, l *zap.Logger) (*state.State, error) {
+	fingerprint, err := analysisFingerprint(cfg, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := state.Load(stateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		statusf("🔁 Incremental analysis: no state in %s yet, analyzing every repository\n", stateFile)
+		return state.New(lang, fingerprint), nil
+	case err != nil:
+		return nil, err
+	case !previous.Matches(lang, fingerprint):
+		l.Info("Analysis settings changed since the state was recorded", zap.String("path", stateFile))
+		statusf("🔁 Incremental analysis: settings changed since %s was written, analyzing every repository\n",
+			stateFile)
+		return state.New(lang, fingerprint), nil
+	default:
+		statusf("🔁 Incremental analysis: %d repositories recorded in %s\n", len(previous.Repositories), stateFile)
+		return previous, nil
+	}
+}
+
+// analysisFingerprint summarizes the settings that shape the recorded projects: which files are scanned,
+// how they are parsed and how dependencies are classified. Recorded projects are only reused with the same
+// fingerprint, other settings such as report formats or enrichment apply to them on every run.
+func analysisFingerprint(cfg *config.Config, lang string) (string, error) {
+	settings, err := json.Marshal(struct {
+		Version      string
+		Language     string
+		Provider     string
+		Ref          string
+		Repositories []config.RepositoryConfig
+		Internal     config.InternalConfig
+		Exclude      config.ExcludeConfig
+		Scanner      config.ScannerConfig
+		Manifests    []config.ManifestConfig
+		IncludeDev   bool
+	}{
+		Version:      version,
+		Language:     lang,
+		Provider:     cfg.Provider,
+		Ref:          cfg.GitLab.Ref,
+		Repositories: cfg.Repositories,
+		Internal:     cfg.Internal,
+		Exclude:      cfg.Exclude,
+		Scanner:      cfg.Scanner,
+		Manifests:    cfg.Manifests,
+		IncludeDev:   includeDev || cfg.IncludeDev,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode analysis settings: %w", err)
+	}
+	sum := sha256.Sum256(settings)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// saveIncrementalState writes the state of this run for the next incremental one, a failed write only costs
+// the next run a full analysis
+func saveIncrementalState(next *state.State, l *zap.Logger) {
+	if err := next.Save(stateFile); err != nil {
+		l.Warn("Failed to save incremental state", zap.String("path", stateFile), zap.Error(err))
+		return
+	}
+	l.Info("Saved incremental state",
+		zap.String("path", stateFile),
+		zap.Int("repositories", len(next.Repositories)))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/wizard"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	initOutput      string
+	initInteractive bool
+	initForce       bool
+	initBaseURL     string
+	initRepos       []string
+	initDiscover    bool
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a configuration file",
+	Long: `Write a starter configuration file. With --interactive, prompt for the GitLab URL
+and token, verify the token, and pick groups and projects from the ones it can access.
+GITLAB_BASE_URL and GITLAB_TOKEN pre-fill the prompts.
+
+Without --interactive the file is generated from flags: --base-url and --repo set the
+GitLab URL and repositories, --discover adds the top-level groups GITLAB_TOKEN can access.
+Internal domains and npm scopes are pre-filled from the namespaces of the repositories.`,
+	RunE: runInit,
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if !initForce {
+		if _, err := os.Stat(initOutput); err == nil {
+			return configError("%s already exists, use --force to overwrite it", initOutput)
+		}
+	}
+
+	if initInteractive && (len(initRepos) > 0 || initDiscover) {
+		return configError("--repo and --discover cannot be combined with --interactive")
+	}
+
+	defaults := wizard.Defaults{BaseURL: os.Getenv("GITLAB_BASE_URL"), Token: os.Getenv("GITLAB_TOKEN")}
+	if initBaseURL != "" {
+		defaults.BaseURL = strings.TrimSuffix(initBaseURL, "/")
+	}
+
+	result := &wizard.Result{
+		BaseURL:      defaults.BaseURL,
+		Repositories: initRepos,
+	}
+	if result.BaseURL == "" {
+		result.BaseURL = wizard.DefaultBaseURL
+	}
+
+	connect := func(baseURL, token string) (wizard.Browser, error) {
+		return gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
+	}
+
+	if initDiscover {
+		discovered, err := discoverRepositories(result.BaseURL, defaults.Token, connect)
+		if err != nil {
+			return err
+		}
+		result.Repositories = append(result.Repositories, discovered...)
+	}
+	placeholder := len(result.Repositories) == 0
+	if placeholder {
+		result.Repositories = []string{result.BaseURL + "/your-group/your-repo"}
+		result.Placeholder = true
+	}
+
+	if initInteractive {
+		answers, err := wizard.New(cmd.InOrStdin(), cmd.OutOrStdout(), connect).Run(context.Background(), defaults)
+		if errors.Is(err, wizard.ErrAuthentication) {
+			return withExitCode(exitAuthFailure, err)
+		}
+		if err != nil {
+			return gitlabError(err)
+		}
+		result = answers
+	}
+
+	content, err := wizard.Render(result)
+	if err != nil {
+		return err
+	}
+
+	// The file may contain the token
+	if err := os.WriteFile(initOutput, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Configuration written to %s\n", initOutput)
+	if placeholder && !initInteractive {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Edit the repositories, or run 'di-matrix-cli init --interactive --force'")
+	}
+	return nil
+}
+
+// discoverRepositories lists the top-level groups the token can access for a non-interactive init
+func discoverRepositories(baseURL, token string, connect wizard.Connector) ([]string, error) {
+	if token == "" {
+		return nil, configError("--discover needs GITLAB_TOKEN")
+	}
+
+	browser, err := connect(baseURL, token)
+	if err != nil {
+		return nil, configError("failed to create GitLab client: %w", err)
+	}
+	ctx := context.Background()
+	if err := browser.CheckPermissions(ctx); err != nil {
+		return nil, withExitCode(exitAuthFailure, fmt.Errorf("%w: %w", wizard.ErrAuthentication, err))
+	}
+
+	repositories, err := wizard.Discover(ctx, browser)
+	if err != nil {
+		return nil, gitlabError(err)
+	}
+	return repositories, nil
+}
@@ -2,16 +2,37 @@ package main
 
 import (
 	"context"
+	depannotations "di-matrix-cli/internal/annotations"
+	"di-matrix-cli/internal/anonymize"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/checkpoint"
 	"di-matrix-cli/internal/classifier"
 	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/diff"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/eol"
+	"di-matrix-cli/internal/exclude"
 	"di-matrix-cli/internal/generator"
-	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/health"
 	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/maven"
+	"di-matrix-cli/internal/metrics"
+	"di-matrix-cli/internal/osv"
 	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/policy"
+	"di-matrix-cli/internal/provider"
+	"di-matrix-cli/internal/registry"
 	"di-matrix-cli/internal/scanner"
 	"di-matrix-cli/internal/usecases"
+	depversion "di-matrix-cli/internal/version"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -27,12 +48,36 @@ var (
 )
 
 var (
-	configFile string
-	outputFile string
-	title      string
-	debug      bool
-	timeout    int
-	language   string
+	configFile      string
+	outputFile      string
+	title           string
+	debug           bool
+	timeout         int
+	repoTimeout     int
+	language        string
+	matrices        []string
+	formats         []string
+	baseline        string
+	jsonOutput      string
+	offline         bool
+	anonymized      bool
+	transitive      string
+	includeDev      bool
+	checkpointFile  string
+	resume          bool
+	checkpointEvery time.Duration
+	incremental     bool
+	stateFile       string
+	reposFrom       string
+	localDirs       []string
+	gitRef          string
+	cachePath       string
+	noCache         bool
+	annotations     string
+	vulns           bool
+	pushgateway     string
+	failViolations  bool
+	failOnError     bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -50,12 +95,8 @@ scalable worker pools.`,
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Long:  "Display version, commit hash, and build time information.",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("di-matrix-cli %s\n", version)
-		fmt.Printf("Commit: %s\n", commit)
-		fmt.Printf("Built: %s\n", buildTime)
-	},
+	Long:  "Display version, commit hash, build time, Go runtime and supported languages and report formats.",
+	RunE:  runVersion,
 }
 
 // analyzeCmd represents the analyze command
@@ -66,16 +107,40 @@ var analyzeCmd = &cobra.Command{
 dependency matrix report in HTML format using event-driven architecture.
 The analysis runs asynchronously with real-time progress reporting through
 event-driven worker pools.`,
+	Args: cobra.NoArgs,
 	RunE: runAnalyze,
 }
 
 func setupCommands() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(discoverCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(capabilitiesCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(demoCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(serveCmd)
+
+	// Unknown flags and malformed flag values are configuration errors
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(exitConfigError, err)
+	})
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to configuration file (required)")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "",
+		"Path to configuration file (defaults to $DI_MATRIX_CONFIG, optional when $DI_MATRIX_REPOSITORIES is set)")
 	rootCmd.PersistentFlags().BoolP("version", "v", false, "Show version information")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false,
+		"Forbid network calls other than GitLab, serve registry and advisory data from the local cache only")
+	rootCmd.PersistentFlags().StringVar(&gitRef, "ref", "",
+		"Branch or tag to analyze in every repository instead of its default branch (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&cachePath, "cache-dir", "",
+		"Directory of the on-disk cache (overrides config, defaults to the per-user cache directory)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false,
+		"Download every GitLab tree and file and parse every file again instead of reusing earlier runs")
 
 	// Handle --version flag on root command
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
@@ -90,20 +155,130 @@ func setupCommands() {
 
 	// Add pre-run validation for analyze command to check required config flag
 	analyzeCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
-		if configFile == "" {
-			return fmt.Errorf("config flag is required for analyze command")
+		configFile = resolveConfigFile()
+		if configFile == "" && !config.HasEnvRepositories() && reposFrom == "" && len(localDirs) == 0 {
+			return configError("config flag is required for %s command (or set %s, --repos-from or --local)",
+				cmd.Name(), config.RepositoriesEnv)
+		}
+		if reposFrom != "" && len(localDirs) > 0 {
+			return configError("--repos-from and --local cannot be combined")
+		}
+		if gitRef != "" && len(localDirs) > 0 {
+			return configError("--ref and --local cannot be combined, check out the ref instead")
 		}
 		return nil
 	}
 
+	discoverCmd.PreRunE = analyzeCmd.PreRunE
+	serveCmd.PreRunE = analyzeCmd.PreRunE
+	languageList := strings.Join(supportedLanguages, ", ")
+
+	// Discover command flags
+	discoverCmd.Flags().StringVarP(&discoverLanguage, "language", "l", "",
+		"Only list projects of this language ("+languageList+"), all languages when empty")
+	discoverCmd.Flags().StringVarP(&discoverFormat, "format", "f", "table", "Output format: table or json")
+	discoverCmd.Flags().StringVar(&reposFrom, "repos-from", "", reposFromUsage)
+	discoverCmd.Flags().StringSliceVar(&localDirs, "local", nil, localUsage)
+	discoverCmd.Flags().Lookup("local").NoOptDefVal = "."
+
+	// Scan command flags
+	scanCmd.Flags().StringVarP(&scanLanguage, "language", "l", "",
+		"Only list dependencies of projects of this language ("+languageList+"), all languages when empty")
+	scanCmd.Flags().StringVarP(&scanFormat, "format", "f", "table", "Output format: table or json")
+	scanCmd.Flags().BoolVar(&includeDev, "include-dev", false, "List development and test dependencies too")
+
+	// Init command flags
+	initCmd.Flags().StringVarP(&initOutput, "output", "o", "config.yaml", "Configuration file to write")
+	initCmd.Flags().BoolVarP(&initInteractive, "interactive", "i", false,
+		"Prompt for GitLab credentials and pick groups and projects from a live list")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing configuration file")
+	initCmd.Flags().StringVar(&initBaseURL, "base-url", "", "GitLab URL, defaults to GITLAB_BASE_URL or "+
+		"https://gitlab.com")
+	initCmd.Flags().StringSliceVar(&initRepos, "repo", nil, "Repository or group URL to analyze (repeatable)")
+	initCmd.Flags().BoolVar(&initDiscover, "discover", false,
+		"Add the top-level groups GITLAB_TOKEN can access as repositories")
+
+	// Demo command flags
+	demoCmd.Flags().StringVarP(&demoLanguage, "language", "l", "go",
+		"Programming language to analyze ("+languageList+")")
+	demoCmd.Flags().StringVarP(&demoOutput, "output", "o", "demo-matrix.html", "Output HTML file path")
+	demoCmd.Flags().StringVar(&demoServe, "serve", "",
+		"Only serve the fake GitLab on this address (e.g. 127.0.0.1:8929) until interrupted")
+
+	// Serve command flags
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to serve the report and API on")
+	serveCmd.Flags().StringVarP(&serveLanguage, "language", "l", "go",
+		"Programming language to analyze ("+languageList+")")
+	serveCmd.Flags().StringVar(&serveDir, "dir", "di-matrix-reports", "Directory the served reports are written to")
+	serveCmd.Flags().StringVar(&serveSchedule, "schedule", "",
+		"Cron expression rerunning the analysis, e.g. \"0 6 * * *\" or \"@every 6h\" (overrides serve.schedule)")
+	serveCmd.Flags().BoolVar(&incremental, "incremental", false,
+		"Only analyze repositories whose analyzed commit changed since the previous analysis, reuse the rest")
+	serveCmd.Flags().StringVar(&stateFile, "state", "di-matrix-state.json",
+		"State recording the commit and projects of every repository for --incremental")
+
+	// Version and capabilities command flags
+	versionCmd.Flags().StringVarP(&versionFormat, "format", "f", "text", "Output format: text or json")
+	capabilitiesCmd.Flags().StringVarP(&capabilitiesFormat, "format", "f", "table", "Output format: table or json")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "markdown", "Output format: markdown, html or json")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Write the changes to this file instead of stdout")
+
 	// Analyze command flags
-	analyzeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output HTML file path (overrides config)")
+	analyzeCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output HTML file path (overrides config), - writes the json or csv --format report to stdout")
 	analyzeCmd.Flags().StringVarP(&title, "title", "t", "", "Report title (overrides config)")
 	analyzeCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging with verbose output")
+	analyzeCmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
+		"Print no progress or summary lines, logs go to stderr and only warnings unless --debug is set")
+	analyzeCmd.Flags().BoolVar(&noProgress, "no-progress", false,
+		"Do not draw the progress bar, which is only drawn when stderr is a terminal")
+	analyzeCmd.Flags().BoolVar(&jsonSummary, "json", false,
+		"Print a machine-readable run summary to stdout instead of progress lines (implies --quiet)")
 	analyzeCmd.Flags().IntVarP(&timeout, "timeout", "", 0,
 		"Analysis timeout in minutes (overrides config, 0 = use config default)")
+	analyzeCmd.Flags().IntVar(&repoTimeout, "repository-timeout", 0,
+		"Scan timeout of a single repository in minutes, slower repositories are recorded as errors (overrides config)")
 	analyzeCmd.Flags().
-		StringVarP(&language, "language", "l", "python", "Programming language to analyze (go, nodejs, java, python)")
+		StringVarP(&language, "language", "l", "python", "Programming language to analyze ("+languageList+")")
+	analyzeCmd.Flags().StringSliceVar(&formats, "format", nil,
+		"Reports to write: html, csv, json, xlsx, dot, mermaid, sarif, markdown, comma-separated (overrides config)")
+	analyzeCmd.Flags().StringSliceVar(&matrices, "matrices", nil,
+		"Matrices to render: combined, internal, external (overrides config)")
+	analyzeCmd.Flags().StringVar(&baseline, "baseline", "",
+		"Previous JSON report to highlight changes against in the HTML report")
+	analyzeCmd.Flags().StringVar(&jsonOutput, "json-output", "",
+		"Also write the versioned JSON report to this path (overrides config, usable as a later --baseline)")
+	analyzeCmd.Flags().StringVar(&transitive, "transitive", "",
+		"Transitive dependencies in the reports: include or collapse to direct dependencies only (overrides config)")
+	analyzeCmd.Flags().BoolVar(&includeDev, "include-dev", false,
+		"Keep dev and test dependencies in the analysis, only runtime and optional ones are analyzed by default")
+	analyzeCmd.Flags().BoolVar(&anonymized, "anonymize", false,
+		"Replace project, repository and path names with pseudonyms in the report (overrides config)")
+	analyzeCmd.Flags().StringVar(&annotations, "annotations", "",
+		"YAML file of dependency notes, owners and replacements merged into the reports (overrides config)")
+	analyzeCmd.Flags().BoolVar(&vulns, "vulns", false,
+		"Look up known vulnerabilities of every dependency version in OSV.dev (overrides config)")
+	analyzeCmd.Flags().StringVar(&reposFrom, "repos-from", "", reposFromUsage)
+	analyzeCmd.Flags().StringSliceVar(&localDirs, "local", nil, localUsage)
+	analyzeCmd.Flags().Lookup("local").NoOptDefVal = "."
+	analyzeCmd.Flags().StringVar(&checkpointFile, "checkpoint", "di-matrix-checkpoint.json",
+		"Checkpoint written while the analysis runs and when it is interrupted (Ctrl-C, SIGTERM or timeout)")
+	analyzeCmd.Flags().DurationVar(&checkpointEvery, "checkpoint-interval", time.Minute,
+		"Minimum time between two checkpoint saves while the analysis runs, 0 only saves on interruption")
+	analyzeCmd.Flags().BoolVar(&resume, "resume", false,
+		"Resume an interrupted analysis from --checkpoint, skipping the repositories it completed")
+	analyzeCmd.Flags().BoolVar(&incremental, "incremental", false,
+		"Only analyze repositories whose analyzed commit changed since the last incremental run, reuse the rest")
+	analyzeCmd.Flags().StringVar(&stateFile, "state", "di-matrix-state.json",
+		"State recording the commit and projects of every repository for --incremental")
+	analyzeCmd.Flags().BoolVar(&failViolations, "fail-on-violation", false,
+		"Exit with code 5 when policy violations are found, even if policy.fail_on_violation is false")
+	analyzeCmd.Flags().BoolVar(&failOnError, "fail-on-error", false,
+		"Exit with code 4 when any repository, project or dependency file could not be analyzed")
+	analyzeCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Only count the repositories, dependency files and API calls the analysis would take, download no file")
+	analyzeCmd.Flags().StringVar(&pushgateway, "pushgateway", "",
+		"Prometheus Pushgateway URL the metrics of the analysis are pushed to (overrides config)")
 	if err := analyzeCmd.MarkFlagRequired("language"); err != nil {
 		panic(fmt.Sprintf("failed to mark language flag as required: %v", err))
 	}
@@ -115,34 +290,68 @@ func setupCommands() {
 	if err := viper.BindPFlag("output.title", analyzeCmd.Flags().Lookup("title")); err != nil {
 		panic(fmt.Sprintf("failed to bind title flag: %v", err))
 	}
+	if err := viper.BindPFlag("output.json_file", analyzeCmd.Flags().Lookup("json-output")); err != nil {
+		panic(fmt.Sprintf("failed to bind json-output flag: %v", err))
+	}
 	if err := viper.BindPFlag("timeout.analysis_timeout_minutes", analyzeCmd.Flags().Lookup("timeout")); err != nil {
 		panic(fmt.Sprintf("failed to bind timeout flag: %v", err))
 	}
 }
 
+// reposFromUsage describes the --repos-from flag shared by analyze and discover
+const reposFromUsage = "File with one project or group URL per line replacing the configured repositories, - for stdin"
+
+// localUsage describes the --local flag shared by analyze and discover
+const localUsage = "Analyze these checked-out directories instead of GitLab repositories, given as --local=dir1,dir2 " +
+	"(current directory when no value is given), without a token or network calls"
+
+// configFileEnv names the configuration file when --config is not given, used only when the file exists
+// so the Docker image can default to its mount point and still run from environment variables alone
+const configFileEnv = "DI_MATRIX_CONFIG"
+
+// resolveConfigFile returns the --config flag, the existing $DI_MATRIX_CONFIG file or "" for environment-only mode
+func resolveConfigFile() string {
+	if configFile != "" {
+		return configFile
+	}
+	if path := os.Getenv(configFileEnv); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
 func main() {
 	setupCommands()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
-func runAnalyze(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔍 Starting dependency matrix analysis...")
+func runAnalyze(cmd *cobra.Command, args []string) (err error) {
+	if err := configureOutput(); err != nil {
+		return err
+	}
+	if jsonSummary {
+		started := time.Now()
+		defer func() {
+			if summaryErr := printRunSummary(err, started); summaryErr != nil && err == nil {
+				err = summaryErr
+			}
+		}()
+	}
+
+	statusln("🔍 Starting dependency matrix analysis...")
 
 	// Validate language flag
-	validLanguages := map[string]bool{
-		"go":     true,
-		"nodejs": true,
-		"java":   true,
-		"python": true,
-	}
-	if !validLanguages[language] {
-		return fmt.Errorf("invalid language '%s'. Supported languages: go, nodejs, java, python", language)
+	if !slices.Contains(supportedLanguages, language) {
+		return configError("invalid language '%s'. Supported languages: %s",
+			language, strings.Join(supportedLanguages, ", "))
 	}
 
-	fmt.Printf("🎯 Analyzing %s projects only\n", language)
+	statusf("🎯 Analyzing %s projects only\n", language)
 
 	// Handle debug flag manually since it's a boolean
 	if debug {
@@ -150,10 +359,96 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadConfig(configFile)
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(localDirs) > 0 {
+		statusf("📂 Local mode: analyzing %s without GitLab or network calls\n", strings.Join(localDirs, ", "))
+	}
+	if dryRun {
+		return estimate(cfg, language)
+	}
+
+	return analyze(cfg, language)
+}
+
+// loadConfig loads the configuration, repositories listed by --repos-from or directories given by --local
+// replace the configured ones
+func loadConfig() (*config.Config, error) {
+	if len(localDirs) > 0 {
+		cfg, err := config.LoadLocalConfig(configFile, localDirs)
+		if err != nil {
+			return nil, configError("failed to load configuration: %w", err)
+		}
+		applyCacheFlags(cfg)
+		return cfg, nil
+	}
+
+	repositories, err := readRepositoryList(reposFrom)
+	if err != nil {
+		return nil, configError("failed to read repository list: %w", err)
+	}
+
+	cfg, err := config.LoadConfigWithRepositories(configFile, repositories)
+	if err != nil {
+		return nil, configError("failed to load configuration: %w", err)
+	}
+	if gitRef != "" {
+		cfg.GitLab.Ref = gitRef
+	}
+	applyCacheFlags(cfg)
+	return cfg, nil
+}
+
+// applyCacheFlags overrides the cache settings with --cache-dir and --no-cache
+func applyCacheFlags(cfg *config.Config) {
+	if cachePath != "" {
+		cfg.Cache.Dir = cachePath
+	}
+	if noCache {
+		cfg.Cache.GitLab = false
+		cfg.Cache.ParseResults = false
+	}
+}
+
+// readRepositoryList reads the repositories listed in path, "-" reads stdin and "" lists nothing
+func readRepositoryList(path string) ([]config.RepositoryConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	source := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path) //nolint:gosec // Path is provided by the user on purpose
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		source = file
+	}
+
+	repositories, err := config.ReadRepositoryList(source)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, err
+	}
+	if len(repositories) == 0 {
+		return nil, fmt.Errorf("no repositories listed in %s", path)
 	}
+	return repositories, nil
+}
+
+// analyze runs the analysis of lang projects described by cfg, writes the reports and pushes its metrics
+func analyze(cfg *config.Config, lang string) error {
+	return analyzeContext(context.Background(), cfg, lang, func(measured metrics.Analysis) {
+		pushMetrics(cfg, measured)
+	})
+}
+
+// analyzeContext is analyze stopping early, with a partial report, once parent is cancelled.
+// The measurements of complete analyses are handed to record.
+func analyzeContext(parent context.Context, cfg *config.Config, lang string, record func(metrics.Analysis)) error {
+	started := time.Now()
 
 	// Determine timeout duration (CLI flag overrides config)
 	timeoutMinutes := cfg.Timeout.AnalysisTimeoutMinutes
@@ -162,11 +457,22 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	}
 	timeoutDuration := time.Duration(timeoutMinutes) * time.Minute
 
-	fmt.Printf("⏱️  Analysis timeout: %v\n", timeoutDuration)
+	statusf("⏱️  Analysis timeout: %v\n", timeoutDuration)
+
+	repositoryTimeoutMinutes := cfg.Timeout.RepositoryTimeoutMinutes
+	if repoTimeout > 0 {
+		repositoryTimeoutMinutes = repoTimeout
+	}
+	repositoryTimeout := time.Duration(repositoryTimeoutMinutes) * time.Minute
+	if repositoryTimeout > 0 {
+		statusf("⏱️  Repository timeout: %v\n", repositoryTimeout)
+	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
+	// Create context with timeout, cancelled early by Ctrl-C or SIGTERM
+	ctx, cancel := context.WithTimeout(parent, timeoutDuration)
 	defer cancel()
+	ctx, release := interruptible(ctx)
+	defer release()
 
 	// Set debug level if debug flag is enabled
 	if debug {
@@ -176,34 +482,197 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	// Create dependencies
 	l := logger.GetLogger()
 
-	// Initialize GitLab client
-	gitlabClient, err := gitlab.NewClient(cfg.GitLab.BaseURL, cfg.GitLab.Token, l)
+	// Initialize the source provider selected by the configuration
+	sourceProvider, err := provider.Builtin().New(cfg, l)
 	if err != nil {
-		return fmt.Errorf("failed to create GitLab client: %w", err)
+		return configError("failed to create source provider: %w", err)
+	}
+	if cfg.UsesGitLab() && cfg.GitLab.Ref != "" {
+		statusf("🌿 Analyzing %s in every repository instead of the default branch\n", cfg.GitLab.Ref)
 	}
 
 	// Initialize scanner
-	fileScanner := scanner.NewScanner(gitlabClient, l)
+	fileScanner, manifestParsers, err := newScanner(cfg, sourceProvider, l)
+	if err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	// Offline mode keeps every non-GitLab lookup on the local cache
+	offlineMode := offline || cfg.Offline
+	cacheDir := cfg.Cache.Directory()
+	enrichmentCache := cache.New(cacheDir)
+	parserRepositories := cfg.Maven.RemoteRepositories
+	if offlineMode {
+		parserRepositories = nil
+		statusf("📴 Offline mode: enrichment served from %s\n", cacheDir)
+	}
 
 	// Initialize parser
-	dependencyParser := parser.NewParser()
+	dependencyParser := parser.NewParser().
+		WithFileAliases(manifestParsers).
+		WithMavenRepositories(parserRepositories)
+	if cfg.Cache.ParseResults {
+		dependencyParser.WithCache(enrichmentCache)
+	}
+	warnUnparsedFileTypes(fileScanner, lang, dependencyParser, l)
 
-	// Initialize classifier with internal patterns
-	dependencyClassifier := classifier.NewClassifier(cfg.Internal.Patterns)
+	// Initialize classifier with internal patterns, git dependencies on internal hosts count as internal
+	internalHosts := append([]string{}, cfg.Internal.Domains...)
+	if baseURL, err := url.Parse(cfg.SourceBaseURL()); err == nil && baseURL.Host != "" {
+		internalHosts = append(internalHosts, baseURL.Host)
+	}
+	dependencyClassifier := classifier.NewClassifier(cfg.Internal.Patterns).WithInternalHosts(internalHosts)
 
 	// Initialize generator
-	reportGenerator := generator.NewGenerator(cfg.Output.HTMLFile)
+	reportFormats, err := selectReportFormats(cfg)
+	if err != nil {
+		return err
+	}
+	matrixScopes := cfg.Output.Matrices
+	if len(matrices) > 0 {
+		matrixScopes = matrices
+	}
+	for _, scope := range matrixScopes {
+		if !generator.IsValidMatrixScope(scope) {
+			return configError("invalid matrix '%s'. Supported matrices: combined, internal, external", scope)
+		}
+	}
+	transitiveDependencies := cfg.Output.Transitive
+	if transitive != "" {
+		transitiveDependencies = transitive
+	}
+	if transitiveDependencies != "" && transitiveDependencies != "include" && transitiveDependencies != "collapse" {
+		return configError("invalid transitive mode '%s'. Supported modes: include, collapse", transitiveDependencies)
+	}
+	scanVulnerabilities := vulns || cfg.OSV.Enabled
+	reportGenerator := generator.NewGenerator(cfg.Output.HTMLFile).
+		WithSortBy(cfg.Output.SortBy).
+		WithMatrixScopes(matrixScopes).
+		WithPrereleasePolicy(depversion.PrereleasePolicy(cfg.Policy.Prereleases)).
+		WithOffline(offlineMode).
+		WithVulnerabilityScan(scanVulnerabilities).
+		WithTransitiveCollapsed(transitiveDependencies == "collapse").
+		WithMatrixMode(cfg.Output.MatrixMode.Cell, cfg.Output.MatrixMode.Compare).
+		WithTemplate(cfg.Output.TemplateFile, cfg.Output.AssetsDir).
+		WithVirtualization(cfg.Output.VirtualizeCells).
+		WithCSVOutput(cfg.Output.CSVFile).
+		WithXLSXOutput(cfg.Output.XLSXFile).
+		WithGraphOutput(cfg.Output.DOTFile, cfg.Output.MermaidFile, cfg.Output.GraphInternalOnly).
+		WithSARIFOutput(cfg.Output.SARIFFile).
+		WithMarkdownOutput(cfg.Output.MarkdownFile).
+		WithJSONOutput(cfg.Output.JSONFile)
+	annotationsFile := cfg.Output.AnnotationsFile
+	if annotations != "" {
+		annotationsFile = annotations
+	}
+	if annotationsFile != "" {
+		dependencyAnnotations, err := depannotations.Load(annotationsFile)
+		if err != nil {
+			return configError("failed to load annotations: %w", err)
+		}
+		reportGenerator.WithAnnotations(dependencyAnnotations)
+		statusf("📝 Dependency annotations: %d from %s\n", len(dependencyAnnotations), annotationsFile)
+	}
+	if anonymized || cfg.Output.Anonymize {
+		reportGenerator.WithAnonymizer(anonymize.New(cfg.Output.AnonymizeSalt))
+		statusln("🕶️  Anonymized report: project and repository names are replaced with pseudonyms")
+	}
+	var baselineProjects []*domain.Project
+	if baseline != "" {
+		baselineProjects, err = diff.LoadReport(baseline)
+		if err != nil {
+			return configError("failed to load baseline report: %w", err)
+		}
+		reportGenerator.WithBaseline(baselineProjects)
+		statusf("📊 Comparing against baseline: %s\n", baseline)
+	}
+
+	// Patterns were validated with the configuration
+	excludedDependencies, err := exclude.Compile(cfg.Exclude.Dependencies)
+	if err != nil {
+		return configError("invalid dependency exclusions: %w", err)
+	}
+	if !excludedDependencies.Empty() {
+		statusf("🚫 Excluded dependencies: %s\n", strings.Join(cfg.Exclude.Dependencies, ", "))
+	}
+
+	// Lists were validated with the configuration
+	dependencyLists, err := policy.NewListCheck(cfg.Policy.Denylist, cfg.Policy.Allowlist)
+	if err != nil {
+		return configError("invalid dependency lists: %w", err)
+	}
 
 	// Create analyze use case with dependency injection
 	analyzeUseCase := usecases.NewAnalyzeUseCase(
 		ctx,
-		gitlabClient,
+		sourceProvider,
 		fileScanner,
 		dependencyParser,
 		dependencyClassifier,
 		reportGenerator,
 		l,
-	)
+	).WithSubmoduleResolution(
+		cfg.Scanner.ResolveSubmodules,
+	).WithManagedVersionResolver(
+		maven.NewResolver(l).
+			WithRemoteRepositories(cfg.Maven.RemoteRepositories).
+			WithCache(enrichmentCache).
+			WithOffline(offlineMode).
+			WithRetryPolicy(cfg.Retry.Registry.Policy()),
+	).WithPolicyChecks(
+		policy.NewPinningCheck(cfg.Policy.Pinning.RequireLockfile, cfg.Policy.Pinning.ForbidFloating),
+		dependencyLists,
+	).WithHealthWeights(health.Weights{
+		Drift:           cfg.Health.Weights.Drift,
+		Vulnerabilities: cfg.Health.Weights.Vulnerabilities,
+		Deprecated:      cfg.Health.Weights.Deprecated,
+		Pinning:         cfg.Health.Weights.Pinning,
+		Lockfile:        cfg.Health.Weights.Lockfile,
+	}).WithPrereleasePolicy(
+		depversion.PrereleasePolicy(cfg.Policy.Prereleases),
+	).WithOutputFormats(reportFormats...).WithDevDependencies(includeDev || cfg.IncludeDev).
+		WithExcludedDependencies(excludedDependencies).
+		WithRepositoryWorkers(cfg.Concurrency.RepositoryWorkers).
+		WithRepositoryTimeout(repositoryTimeout).
+		WithProjectWorkers(cfg.Concurrency.ParserWorkers).
+		WithDependencyFileWorkers(cfg.Concurrency.FileParserWorkers)
+
+	if cfg.Registry.Enabled {
+		analyzeUseCase.WithLatestVersionResolver(newLatestVersionResolver(cfg, enrichmentCache, offlineMode, l))
+	}
+	if cfg.EOL.Enabled {
+		analyzeUseCase.WithEndOfLifeChecker(newEndOfLifeChecker(cfg, enrichmentCache, offlineMode, l))
+	}
+	if scanVulnerabilities {
+		analyzeUseCase.WithVulnerabilityScanner(
+			osv.NewScanner(osv.NewClient(cfg.OSV.BaseURL, cfg.Retry.Registry.Policy()), l).
+				WithCache(enrichmentCache, time.Duration(cfg.OSV.CacheTTLHours)*time.Hour).
+				WithConcurrency(cfg.OSV.Workers).
+				WithOffline(offlineMode),
+		)
+		statusf("🛡️  Vulnerability scanning: advisories from %s\n", cfg.OSV.BaseURL)
+	}
+
+	if resume {
+		resumed, err := checkpoint.Load(checkpointFile)
+		if err != nil {
+			return configError("failed to load checkpoint: %w", err)
+		}
+		analyzeUseCase.WithCheckpoint(resumed)
+		statusf("⏯️  Resuming from %s: %d repositories already analyzed, %d already scanned\n",
+			checkpointFile, len(resumed.Repositories), len(resumed.Scanned))
+	}
+	if checkpointEvery > 0 {
+		analyzeUseCase.WithPeriodicCheckpoint(checkpointFile, checkpointEvery)
+	}
+
+	if incremental {
+		previous, err := loadIncrementalState(cfg, lang, l)
+		if err != nil {
+			return configError("failed to load incremental state: %w", err)
+		}
+		analyzeUseCase.WithIncrementalState(previous)
+	}
 
 	// Extract repository URLs from config
 	repositoryURLs := make([]string, len(cfg.Repositories))
@@ -211,19 +680,371 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		repositoryURLs[i] = repo.URL
 	}
 
-	response, err := analyzeUseCase.Execute(repositoryURLs, language)
+	// Fail fast with a distinct exit code when the token is rejected
+	if err := sourceProvider.CheckPermissions(ctx); err != nil {
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("analysis interrupted before it started: %w", ctx.Err()))
+		}
+		return gitlabError(err)
+	}
+
+	// Dependencies built by any project of the configured groups are internal
+	if cfg.Internal.FromGroups && cfg.UsesGitLab() {
+		locations, err := classifier.ProjectLocations(ctx, sourceProvider, repositoryURLs)
+		if err != nil {
+			l.Warn("Failed to list every project of the configured groups", zap.Error(err))
+		}
+		dependencyClassifier.WithInternalProjects(locations)
+		statusf("🏷️  Internal projects from GitLab groups: %d\n", len(locations))
+	}
+
+	restoreLogs := withProgress(analyzeUseCase)
+	response, err := analyzeUseCase.Execute(repositoryURLs, lang)
+	restoreLogs()
 	if err != nil {
-		return fmt.Errorf("failed to analyze dependency matrix: %w", err)
+		if ctx.Err() != nil {
+			return withExitCode(exitInterrupted, fmt.Errorf("analysis interrupted before any repository was analyzed: %w",
+				ctx.Err()))
+		}
+		return gitlabError(fmt.Errorf("failed to analyze dependency matrix: %w", err))
+	}
+
+	lastRun.Analysis = response
+	lastRun.Reports = make(map[string]string, len(reportFormats))
+	for _, format := range reportFormats {
+		lastRun.Reports[format] = reportPath(cfg, format)
+	}
+
+	// Repositories analyzed before an interruption are recorded too
+	if response.State != nil {
+		saveIncrementalState(response.State, l)
+	}
+	if response.Interrupted {
+		return interruptedOutcome(response)
+	}
+	if record != nil {
+		record(analysisMetrics(response, lang, time.Since(started), sourceProvider))
+	}
+	if resume || checkpointEvery > 0 {
+		// The analysis is complete, a later --resume must not skip repositories anymore
+		if err := os.Remove(checkpointFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			l.Warn("Failed to remove checkpoint", zap.String("path", checkpointFile), zap.Error(err))
+		}
 	}
 
 	l.Info("Analysis completed successfully", zap.Any("response", response))
 
 	// Print summary
-	fmt.Println("\n🎉 Analysis completed successfully!")
-	fmt.Printf("📈 Summary:\n")
-	fmt.Printf("  • Total Projects: %d\n", response.TotalProjects)
-	fmt.Printf("  • Total Dependencies: %d\n", response.TotalDependencies)
-	fmt.Printf("  • Internal Dependencies: %d\n", response.InternalCount)
-	fmt.Printf("  • External Dependencies: %d\n", response.ExternalCount)
+	statusln("\n🎉 Analysis completed successfully!")
+	for _, format := range reportFormats {
+		statusf("📄 %s report: %s\n", strings.ToUpper(format), reportPath(cfg, format))
+	}
+	statusf("📈 Summary:\n")
+	statusf("  • Total Projects: %d\n", response.TotalProjects)
+	if response.UnchangedRepositories > 0 {
+		statusf("  • Unchanged Repositories: %d (reused from %s)\n", response.UnchangedRepositories, stateFile)
+	}
+	statusf("  • Total Dependencies: %d\n", response.TotalDependencies)
+	statusf("  • Internal Dependencies: %d\n", response.InternalCount)
+	statusf("  • External Dependencies: %d\n", response.ExternalCount)
+	statusf("  • Floating Dependencies: %d\n", response.FloatingCount)
+	statusf("  • Projects Without Lockfile: %d\n", response.ProjectsWithoutLockfile)
+	if response.ConflictCount > 0 {
+		statusf("  • Version Conflicts: %d (see Conflicts in the report)\n", response.ConflictCount)
+	}
+	if response.ConstraintMismatchCount > 0 {
+		statusf("  • Constraint Mismatches: %d (resolved versions outside declared constraints)\n",
+			response.ConstraintMismatchCount)
+	}
+	if response.WarningCount > 0 {
+		statusf("  • Files Without Dependencies: %d (see Scan Warnings in the report)\n", response.WarningCount)
+	}
+	if response.VulnerableCount > 0 {
+		statusf("  • Vulnerable Dependencies: %d (see Vulnerabilities in the report)\n", response.VulnerableCount)
+	}
+	if response.StaleCount > 0 {
+		statusf("  • Stale Dependencies: %d (offline mode, missing from the local cache)\n", response.StaleCount)
+	}
+	if response.FallbackCount > 0 {
+		statusf("  • Degraded Lockfiles: %d (unparsable, declared versions from the manifest used instead)\n",
+			response.FallbackCount)
+	}
+	if response.Coverage < 100 {
+		statusf("  • Manifest Coverage: %.1f%% of dependency files analyzed (see Manifest Coverage in the report)\n",
+			response.Coverage)
+	}
+	if len(response.Errors) > 0 {
+		statusf("  • Analysis Errors: %d (see Analysis Errors in the report)\n", len(response.Errors))
+	}
+	if response.FailedRepositories > 0 || response.FailedProjects > 0 {
+		statusf("  • Failed: %d of %d repositories, %d of %d projects (see logs)\n",
+			response.FailedRepositories, response.RepositoryCount, response.FailedProjects, response.TotalProjects)
+	}
+
+	if err := publishReports(ctx, cfg, l); err != nil {
+		return gitlabError(fmt.Errorf("failed to publish reports: %w", err))
+	}
+	uploaded, err := uploadReports(ctx, cfg, reportFormats)
+	if err != nil {
+		return withExitCode(exitFailure, fmt.Errorf("failed to upload reports: %w", err))
+	}
+	lastRun.Uploads = uploaded
+	if err := uploadSBOMs(ctx, cfg, response.Projects); err != nil {
+		return withExitCode(exitFailure, fmt.Errorf("failed to upload SBOMs to Dependency-Track: %w", err))
+	}
+	notifyWebhooks(ctx, cfg, response, baselineProjects, uploaded[domain.FormatHTML])
+
+	return analysisOutcome(response, failViolations || cfg.Policy.FailOnViolation, failOnError)
+}
+
+// selectReportFormats applies the output flags to cfg and returns the report formats to write.
+// A JSON output path, configured or given by --json-output, also writes the JSON report, publishing to GitLab Pages
+// or a wiki the HTML or Markdown report.
+func selectReportFormats(cfg *config.Config) ([]string, error) {
+	toStdout := outputFile == generator.Stdout
+	if outputFile != "" && !toStdout {
+		cfg.Output.HTMLFile = outputFile
+	}
+	if jsonOutput != "" {
+		cfg.Output.JSONFile = jsonOutput
+	}
+	if title != "" {
+		cfg.Output.Title = title
+	}
+
+	selected := cfg.Output.Formats
+	if len(formats) > 0 {
+		selected = formats
+	}
+	if toStdout {
+		// A single stream report keeps stdout parsable
+		if len(formats) == 0 {
+			selected = []string{domain.FormatJSON}
+		}
+		switch {
+		case len(selected) == 1 && selected[0] == domain.FormatJSON:
+			cfg.Output.JSONFile = generator.Stdout
+		case len(selected) == 1 && selected[0] == domain.FormatCSV:
+			cfg.Output.CSVFile = generator.Stdout
+		default:
+			return nil, configError("--output - writes a single json or csv report, select it with --format")
+		}
+	}
+	for _, format := range selected {
+		switch format {
+		case domain.FormatHTML, domain.FormatCSV, domain.FormatJSON, domain.FormatXLSX,
+			domain.FormatDOT, domain.FormatMermaid, domain.FormatSARIF, domain.FormatMarkdown:
+		default:
+			return nil, configError("invalid format '%s'. Supported formats: "+
+				"html, csv, json, xlsx, dot, mermaid, sarif, markdown", format)
+		}
+	}
+	if cfg.Output.JSONFile != "" && !slices.Contains(selected, domain.FormatJSON) {
+		selected = append(slices.Clone(selected), domain.FormatJSON)
+	}
+	if slices.Contains(selected, domain.FormatJSON) && cfg.Output.JSONFile == "" {
+		cfg.Output.JSONFile = "dependency-matrix.json"
+	}
+	if cfg.Output.Publish.Pages.Project != "" && !slices.Contains(selected, domain.FormatHTML) {
+		selected = append(slices.Clone(selected), domain.FormatHTML)
+	}
+	if cfg.Output.Publish.Wiki.Project != "" && !slices.Contains(selected, domain.FormatMarkdown) {
+		selected = append(slices.Clone(selected), domain.FormatMarkdown)
+	}
+	return selected, nil
+}
+
+// reportPath returns the path the report of a format is written to
+func reportPath(cfg *config.Config, format string) string {
+	switch format {
+	case domain.FormatCSV:
+		return cfg.Output.CSVFile
+	case domain.FormatJSON:
+		return cfg.Output.JSONFile
+	case domain.FormatXLSX:
+		return cfg.Output.XLSXFile
+	case domain.FormatDOT:
+		return cfg.Output.DOTFile
+	case domain.FormatMermaid:
+		return cfg.Output.MermaidFile
+	case domain.FormatSARIF:
+		return cfg.Output.SARIFFile
+	case domain.FormatMarkdown:
+		return cfg.Output.MarkdownFile
+	default:
+		return cfg.Output.HTMLFile
+	}
+}
+
+// interruptible cancels ctx on SIGINT or SIGTERM so the analysis can stop gracefully, release must be called
+// once the analysis is over. The signal handler is removed after the first signal, a second one terminates immediately.
+func interruptible(parent context.Context) (context.Context, func()) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	finished := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-finished:
+			return
+		}
+		stop()
+
+		select {
+		case <-finished:
+			// Cancelled by release
+		default:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				fmt.Fprintln(os.Stderr, "\n⏱️  Analysis timed out, writing a partial report...")
+			} else {
+				fmt.Fprintln(os.Stderr, "\n⏹️  Interrupted, finishing in-flight work and writing a partial report "+
+					"(press Ctrl-C again to quit immediately)...")
+			}
+		}
+	}()
+
+	return ctx, func() {
+		close(finished)
+		stop()
+	}
+}
+
+// interruptedOutcome saves the checkpoint of an interrupted analysis and maps it to its exit code
+func interruptedOutcome(response *usecases.AnalyzeResponse) error {
+	if err := response.Checkpoint.Save(checkpointFile); err != nil {
+		return withExitCode(exitFailure, err)
+	}
+	lastRun.Checkpoint = checkpointFile
+
+	statusln("\n⏹️  Analysis interrupted, partial report written")
+	statusf("  • Analyzed: %d of %d repositories (%d projects, %d dependencies)\n",
+		response.RepositoryCount-response.PendingRepositories, response.RepositoryCount,
+		response.TotalProjects, response.TotalDependencies)
+	statusf("  • Checkpoint: %s, continue with --resume\n", checkpointFile)
+
+	return withExitCode(exitInterrupted, fmt.Errorf("analysis interrupted: %d of %d repositories pending",
+		response.PendingRepositories, response.RepositoryCount))
+}
+
+// analysisOutcome maps a completed analysis to the command error and its exit code,
+// violations only fail the command when failOnViolations is set and files that could not be analyzed
+// only when failOnError is set
+func analysisOutcome(response *usecases.AnalyzeResponse, failOnViolations, failOnError bool) error {
+	allRepositoriesFailed := response.RepositoryCount > 0 && response.FailedRepositories == response.RepositoryCount
+	allProjectsFailed := response.TotalProjects > 0 && response.FailedProjects == response.TotalProjects
+	if allRepositoriesFailed || allProjectsFailed {
+		return withExitCode(exitFailure, fmt.Errorf("analysis failed for every repository or project"))
+	}
+
+	if len(response.Violations) > 0 {
+		statusf("\n🚫 Policy violations: %d\n", len(response.Violations))
+		for _, violation := range response.Violations {
+			statusf("  • [%s] %s\n", violation.Rule, violation.Message)
+		}
+	}
+	if len(response.Violations) > 0 && failOnViolations {
+		return withExitCode(exitPolicyFailed, fmt.Errorf("%d policy violations found", len(response.Violations)))
+	}
+
+	if response.FailedRepositories > 0 || response.FailedProjects > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("analysis incomplete: %d repositories and %d projects failed",
+			response.FailedRepositories, response.FailedProjects))
+	}
+	if len(response.Errors) > 0 && failOnError {
+		return withExitCode(exitPartial, fmt.Errorf("analysis incomplete: %d analysis errors", len(response.Errors)))
+	}
 	return nil
 }
+
+// warnUnparsedFileTypes reports the file types of the analyzed language that have no effective parser.
+// Analyses of every language rely on the per-file report warnings of the files actually detected.
+func warnUnparsedFileTypes(fileScanner *scanner.Scanner, lang string, dependencyParser *parser.Parser, l *zap.Logger) {
+	if lang == "" {
+		return
+	}
+	for _, fileType := range fileScanner.SupportedFileTypes() {
+		if fileScanner.DetectLanguageFromFile(fileType) != lang {
+			continue
+		}
+		capability, reason := dependencyParser.Capability(fileType)
+		if capability == domain.FileUnsupported {
+			l.Warn("Detected file type has no parser, matching files will yield no dependencies",
+				zap.String("file_type", fileType),
+				zap.String("reason", reason))
+		}
+	}
+}
+
+// newScanner builds the scanner from configuration and returns the parser aliases of custom manifests
+func newScanner(
+	cfg *config.Config,
+	sourceProvider domain.SourceProvider,
+	l *zap.Logger,
+) (*scanner.Scanner, map[string]string, error) {
+	// Resolve custom manifest filenames to languages and built-in parsers
+	manifestLanguages := make(map[string]string, len(cfg.Manifests))
+	manifestParsers := make(map[string]string, len(cfg.Manifests))
+	for _, manifest := range cfg.Manifests {
+		parserFile, err := parser.ResolveParserFile(manifest.Language, manifest.Filename, manifest.Parser)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid manifest mapping '%s': %w", manifest.Filename, err)
+		}
+		manifestLanguages[manifest.Filename] = manifest.Language
+		manifestParsers[manifest.Filename] = parserFile
+	}
+
+	fileScanner := scanner.NewScanner(sourceProvider, l).
+		WithScanLimits(cfg.Scanner.MaxDepth, cfg.Scanner.IgnoreDirs).
+		WithPathFilters(repositoryPathFilters(cfg.Repositories)).
+		WithVendoredDirsSkipped(cfg.Scanner.SkipVendored).
+		WithServiceDetection(cfg.Scanner.DetectServices).
+		WithManifestMappings(manifestLanguages).
+		WithFileFetcherWorkers(cfg.Concurrency.FileFetcherWorkers)
+
+	return fileScanner, manifestParsers, nil
+}
+
+// repositoryPathFilters collects the scanned and skipped subdirectories of repository and group entries
+// keyed by their URL
+func repositoryPathFilters(repositories []config.RepositoryConfig) map[string]scanner.PathFilter {
+	filters := make(map[string]scanner.PathFilter)
+	for _, repo := range repositories {
+		if repo.URL != "" && (len(repo.Paths) > 0 || len(repo.ExcludePaths) > 0) {
+			filters[repo.URL] = scanner.PathFilter{Include: repo.Paths, Exclude: repo.ExcludePaths}
+		}
+	}
+	return filters
+}
+
+// newEndOfLifeChecker builds the endoflife.date checker with the configured product mappings
+func newEndOfLifeChecker(cfg *config.Config, store *cache.Store, offline bool, l *zap.Logger) *eol.Checker {
+	var products []eol.Product
+	for _, mapping := range cfg.EOL.Products {
+		// Mappings were validated with the configuration
+		if product, err := eol.NewProduct(mapping.Dependency, mapping.Product); err == nil {
+			products = append(products, product)
+		}
+	}
+	return eol.NewChecker(eol.NewClient(cfg.EOL.BaseURL, cfg.Retry.Registry.Policy()), l).
+		WithProducts(products...).
+		WithCache(store, time.Duration(cfg.EOL.CacheTTLHours)*time.Hour).
+		WithOffline(offline)
+}
+
+// newLatestVersionResolver builds the package registry clients of the ecosystems with a public registry
+func newLatestVersionResolver(
+	cfg *config.Config,
+	store *cache.Store,
+	offline bool,
+	l *zap.Logger,
+) *registry.Resolver {
+	policy := cfg.Retry.Registry.Policy()
+	return registry.NewResolver(l).
+		WithClient("go-modules", registry.NewGoProxy(cfg.Registry.GoProxy, policy)).
+		WithClient("npm", registry.NewNPM(cfg.Registry.NPM, policy)).
+		WithClient("pip", registry.NewPyPI(cfg.Registry.PyPI, policy)).
+		WithClient("maven", registry.NewMaven(cfg.Maven.RemoteRepositories, policy)).
+		WithCache(store, time.Duration(cfg.Registry.CacheTTLHours)*time.Hour).
+		WithConcurrency(cfg.Registry.Workers).
+		WithOffline(offline)
+}
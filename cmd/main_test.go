@@ -111,6 +111,31 @@ func (m *MockReportGenerator) GenerateJSON(ctx context.Context, projects []*doma
 	return args.Error(0)
 }
 
+func (m *MockReportGenerator) GenerateXLSX(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateDOT(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateMermaid(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateSARIF(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateMarkdown(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
 // Test helper to create a temporary config file
 func createTempConfig(t *testing.T, content string) string {
 	t.Helper()
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/metrics"
+	"di-matrix-cli/internal/usecases"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// pushTimeout bounds how long pushing the metrics of an analysis may take
+const pushTimeout = 10 * time.Second
+
+// analysisMetrics collects the measurements of a finished analysis
+func analysisMetrics(
+	response *usecases.AnalyzeResponse,
+	lang string,
+	duration time.Duration,
+	source domain.SourceProvider,
+) metrics.Analysis {
+	measured := metrics.Analysis{
+		Language:     lang,
+		Projects:     response.TotalProjects,
+		Dependencies: response.TotalDependencies,
+		Internal:     response.InternalCount,
+		External:     response.ExternalCount,
+		Outdated:     response.OutdatedCount,
+		Vulnerable:   response.VulnerableCount,
+		Ecosystems:   response.Ecosystems,
+		Duration:     duration,
+		FinishedAt:   time.Now(),
+	}
+	if counter, ok := source.(domain.APICallCounter); ok {
+		measured.APICalls = counter.APICalls()
+	}
+	return measured
+}
+
+// pushMetrics pushes the measurements of an analysis to the configured Pushgateway, if any.
+// A failed push is reported without failing the analysis.
+func pushMetrics(cfg *config.Config, measured metrics.Analysis) {
+	gateway := cfg.Metrics.PushgatewayURL
+	if pushgateway != "" {
+		gateway = pushgateway
+	}
+	if gateway == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+	client := &http.Client{Timeout: pushTimeout}
+	if err := metrics.Push(ctx, client, gateway, cfg.Metrics.Job, measured); err != nil {
+		logger.GetLogger().Warn("Failed to push metrics", zap.String("pushgateway", gateway), zap.Error(err))
+		statusf("⚠️  Metrics not pushed to %s: %v\n", gateway, err)
+		return
+	}
+	statusf("📡 Metrics pushed to %s (job %s)\n", gateway, cfg.Metrics.Job)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/notify"
+	"di-matrix-cli/internal/usecases"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// notifyTimeout bounds how long posting a notification may take
+const notifyTimeout = 10 * time.Second
+
+// notifyWebhooks posts the summary of an analysis to the configured Slack and Teams webhooks, if any. Dependencies
+// outdated since the baseline are listed when one was given, reportURL links the report unless one is configured.
+// A failed post is reported without failing the analysis.
+func notifyWebhooks(
+	ctx context.Context,
+	cfg *config.Config,
+	response *usecases.AnalyzeResponse,
+	baselineProjects []*domain.Project,
+	reportURL string,
+) {
+	webhooks := map[string]string{
+		notify.Slack: cfg.Notifications.SlackWebhookURL,
+		notify.Teams: cfg.Notifications.TeamsWebhookURL,
+	}
+	summary := notify.NewSummary(cfg.Output.Title, response.Projects, baselineProjects, response.Violations)
+	summary.Failed = response.FailedRepositories + response.FailedProjects
+	summary.MaxItems = cfg.Notifications.MaxItems
+	summary.ReportURL = reportURL
+	if cfg.Notifications.ReportURL != "" {
+		summary.ReportURL = cfg.Notifications.ReportURL
+	}
+
+	client := &http.Client{Timeout: notifyTimeout}
+	for _, kind := range []string{notify.Slack, notify.Teams} {
+		if webhooks[kind] == "" {
+			continue
+		}
+		if err := notify.Post(ctx, client, kind, webhooks[kind], summary); err != nil {
+			logger.GetLogger().Warn("Failed to post notification", zap.String("webhook", kind), zap.Error(err))
+			statusf("⚠️  %s notification not posted: %v\n", kind, err)
+			continue
+		}
+		statusf("💬 Summary posted to %s\n", kind)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"di-matrix-cli/internal/generator"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/progress"
+	"di-matrix-cli/internal/usecases"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"go.uber.org/zap"
+)
+
+var (
+	quiet       bool
+	jsonSummary bool
+	noProgress  bool
+)
+
+// showProgress draws the progress bar of analyze runs, set by configureOutput
+var showProgress bool //nolint:gochecknoglobals // Set once per command run
+
+// status receives the progress and summary lines of an analysis, discarded by --quiet and --json
+var status io.Writer = os.Stdout //nolint:gochecknoglobals // Replaced once per command run
+
+// statusf prints a progress or summary line
+func statusf(format string, args ...any) {
+	_, _ = fmt.Fprintf(status, format, args...)
+}
+
+// statusln prints a progress or summary line
+func statusln(args ...any) {
+	_, _ = fmt.Fprintln(status, args...)
+}
+
+// reportsToStdout reports whether --output - or --json-output - writes a report to stdout
+func reportsToStdout() bool {
+	return outputFile == generator.Stdout || jsonOutput == generator.Stdout
+}
+
+// configureOutput keeps stdout free for a report or the run summary: progress lines are dropped
+// and logs go to stderr, only warnings unless --debug is set
+func configureOutput() error {
+	if jsonSummary && reportsToStdout() {
+		return configError("--json cannot be combined with a report written to stdout")
+	}
+	if !quiet && !jsonSummary && !reportsToStdout() {
+		showProgress = !noProgress && isatty.IsTerminal(os.Stderr.Fd())
+		return nil
+	}
+
+	status = io.Discard
+	logger.SetOutput(os.Stderr)
+	if !debug {
+		logger.SetLevel(zap.WarnLevel)
+	}
+	return nil
+}
+
+// withProgress draws the progress of the analysis on stderr when enabled, logs are written above the bar.
+// The returned function restores the log output.
+func withProgress(analyzeUseCase *usecases.AnalyzeUseCase) func() {
+	if !showProgress {
+		return func() {}
+	}
+
+	bar := progress.New(os.Stderr)
+	analyzeUseCase.WithProgress(bar)
+	logger.SetOutput(bar.Wrap(os.Stdout))
+	return func() { logger.SetOutput(os.Stdout) }
+}
+
+// runSummary is the machine-readable result of an analyze run printed by --json
+type runSummary struct {
+	Status          string                     `json:"status"` // success, partial, policy_failed, interrupted or failed
+	ExitCode        int                        `json:"exit_code"`
+	Error           string                     `json:"error,omitempty"`
+	DurationSeconds float64                    `json:"duration_seconds"`
+	Reports         map[string]string          `json:"reports,omitempty"` // Report path by format
+	Uploads         map[string]string          `json:"uploads,omitempty"` // Uploaded report URL by format
+	Checkpoint      string                     `json:"checkpoint,omitempty"`
+	Analysis        *usecases.AnalyzeResponse  `json:"analysis,omitempty"`
+	Estimate        *usecases.EstimateResponse `json:"estimate,omitempty"` // Set by --dry-run
+}
+
+// summaryStatus names the outcome of an exit code
+func summaryStatus(code int) string {
+	switch code {
+	case exitOK:
+		return "success"
+	case exitPartial:
+		return "partial"
+	case exitPolicyFailed:
+		return "policy_failed"
+	case exitInterrupted:
+		return "interrupted"
+	default:
+		return "failed"
+	}
+}
+
+// lastRun collects the analysis, reports and uploads of the current analyze run for its --json summary
+var lastRun runSummary //nolint:gochecknoglobals // Filled while the command runs
+
+// printRunSummary prints the --json summary of a run that ended with err, started at started
+func printRunSummary(err error, started time.Time) error {
+	summary := lastRun
+	summary.ExitCode = exitCode(err)
+	summary.Status = summaryStatus(summary.ExitCode)
+	if err != nil {
+		summary.Error = err.Error()
+	}
+	summary.DurationSeconds = time.Since(started).Seconds()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	return nil
+}
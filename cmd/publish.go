@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/gitlab"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// publishMessage is the message of the commits publishing the HTML report
+const publishMessage = "Publish dependency matrix report"
+
+// publishReports pushes the written reports to the GitLab Pages branch and the wiki page configured, if any
+func publishReports(ctx context.Context, cfg *config.Config, l *zap.Logger) error {
+	pages, wiki := cfg.Output.Publish.Pages, cfg.Output.Publish.Wiki
+	if pages.Project == "" && wiki.Project == "" {
+		return nil
+	}
+
+	token, err := cfg.GitLab.ResolveToken()
+	if err != nil {
+		return err
+	}
+	client, err := gitlab.NewClient(cfg.GitLab.BaseURL, token, gitlab.TokenType(cfg.GitLab.TokenType), l)
+	if err != nil {
+		return err
+	}
+	client.WithRetryPolicies(gitlab.RetryPolicies{
+		Metadata: cfg.Retry.Metadata.Policy(),
+		Tree:     cfg.Retry.Tree.Policy(),
+		Content:  cfg.Retry.Content.Policy(),
+	})
+
+	if pages.Project != "" {
+		files, err := pagesFiles(cfg.Output.HTMLFile, cfg.Output.AssetsDir, pages.Directory)
+		if err != nil {
+			return err
+		}
+		if err := client.CommitFiles(ctx, pages.Project, pages.Branch, publishMessage, files); err != nil {
+			return err
+		}
+		statusf("🌐 HTML report published to branch %s of %s (%s)\n",
+			pages.Branch, pages.Project, path.Join(pages.Directory, "index.html"))
+	}
+
+	if wiki.Project != "" {
+		content, err := os.ReadFile(cfg.Output.MarkdownFile)
+		if err != nil {
+			return fmt.Errorf("failed to read Markdown report: %w", err)
+		}
+		page := strings.Trim(wiki.Page, "/")
+		if err := client.SaveWikiPage(ctx, wiki.Project, page, string(content)); err != nil {
+			return err
+		}
+		statusf("📚 Markdown report published to wiki page %s of %s\n", page, wiki.Project)
+	}
+	return nil
+}
+
+// pagesFiles returns the files publishing the HTML report commits, keyed by their path in the repository:
+// the report as <directory>/index.html and the theme assets under <directory>/assets as the report links them
+func pagesFiles(htmlFile, assetsDir, directory string) (map[string][]byte, error) {
+	directory = strings.Trim(directory, "/")
+	report, err := os.ReadFile(htmlFile) //nolint:gosec // Report path is set by the configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML report: %w", err)
+	}
+	files := map[string][]byte{path.Join(directory, "index.html"): report}
+	if assetsDir == "" {
+		return files, nil
+	}
+
+	err = filepath.WalkDir(assetsDir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(assetsDir, file)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(file) //nolint:gosec // Asset paths are set by the configuration
+		if err != nil {
+			return err
+		}
+		files[path.Join(directory, "assets", filepath.ToSlash(rel))] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assets: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/classifier"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/maven"
+	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/provider"
+	"di-matrix-cli/internal/usecases"
+	depversion "di-matrix-cli/internal/version"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	scanLanguage string
+	scanFormat   string
+)
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan <repository-url>",
+	Short: "Print the dependencies of a single repository",
+	Long: `Analyze one repository, group or checked-out directory ad hoc and print its dependencies
+as a table or JSON instead of writing reports. No config file is needed: the GitLab token
+is read from GITLAB_TOKEN (and the instance from GITLAB_BASE_URL), a --config file or
+$DI_MATRIX_CONFIG is used when given. Projects of every language are listed unless
+--language is set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScan,
+}
+
+// scannedDependency is the JSON representation of a dependency of a scanned project
+type scannedDependency struct {
+	Repository    string `json:"repository"`
+	Project       string `json:"project"`
+	Path          string `json:"path"`
+	Language      string `json:"language"`
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latest_version,omitempty"`
+	Outdated      bool   `json:"outdated"`
+	Ecosystem     string `json:"ecosystem"`
+	Scope         string `json:"scope"`
+	Direct        bool   `json:"direct"`
+	Internal      bool   `json:"internal"`
+	VulnCount     int    `json:"vuln_count,omitempty"`
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	if scanFormat != "table" && scanFormat != "json" {
+		return configError("invalid format '%s'. Supported formats: table, json", scanFormat)
+	}
+	if scanLanguage != "" && !slices.Contains(supportedLanguages, scanLanguage) {
+		return configError("invalid language '%s'. Supported languages: %s",
+			scanLanguage, strings.Join(supportedLanguages, ", "))
+	}
+
+	cfg, err := loadScanConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(cfg.Timeout.AnalysisTimeoutMinutes)*time.Minute)
+	defer cancel()
+	ctx, release := interruptible(ctx)
+	defer release()
+
+	// Logs share stdout with the listing, keep them to problems only
+	if scanFormat == "json" {
+		logger.SetLevel(zap.ErrorLevel)
+	} else {
+		logger.SetLevel(zap.WarnLevel)
+	}
+	l := logger.GetLogger()
+
+	analyzeUseCase, err := newScanUseCase(ctx, cfg, l)
+	if err != nil {
+		return err
+	}
+
+	response, err := analyzeUseCase.Execute([]string{args[0]}, scanLanguage)
+	if err != nil {
+		return gitlabError(fmt.Errorf("failed to scan %s: %w", args[0], err))
+	}
+	if response.Interrupted {
+		return withExitCode(exitInterrupted, fmt.Errorf("scan of %s interrupted", args[0]))
+	}
+	if response.RepositoryCount > 0 && response.FailedRepositories == response.RepositoryCount {
+		return withExitCode(exitFailure, fmt.Errorf("project detection failed in %s", args[0]))
+	}
+
+	if scanFormat == "json" {
+		err = writeScannedJSON(os.Stdout, response.Projects)
+	} else {
+		err = writeScannedTable(os.Stdout, response.Projects)
+	}
+	if err != nil {
+		return err
+	}
+
+	if response.FailedProjects > 0 || response.FailedRepositories > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("%d projects of %s could not be parsed",
+			response.FailedProjects, args[0]))
+	}
+	return nil
+}
+
+// loadScanConfig loads the configuration with target as the only repository. An existing directory is analyzed
+// like --local, anything else is a repository or group URL of the configured provider.
+func loadScanConfig(target string) (*config.Config, error) {
+	configFile = resolveConfigFile()
+
+	var cfg *config.Config
+	var err error
+	if info, statErr := os.Stat(target); statErr == nil && info.IsDir() {
+		cfg, err = config.LoadLocalConfig(configFile, []string{target})
+	} else {
+		parsed, parseErr := url.Parse(target)
+		if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, configError("%q is neither a repository URL nor a directory", target)
+		}
+		cfg, err = config.LoadConfigWithRepositories(configFile, []config.RepositoryConfig{{URL: target}})
+	}
+	if err != nil {
+		return nil, configError("failed to load configuration: %w", err)
+	}
+
+	if gitRef != "" {
+		cfg.GitLab.Ref = gitRef
+		cfg.Gitea.Ref = gitRef
+	}
+	applyCacheFlags(cfg)
+	return cfg, nil
+}
+
+// newScanUseCase builds an analysis without reports: detection, parsing, classification and latest versions
+func newScanUseCase(ctx context.Context, cfg *config.Config, l *zap.Logger) (*usecases.AnalyzeUseCase, error) {
+	sourceProvider, err := provider.Builtin().New(cfg, l)
+	if err != nil {
+		return nil, configError("failed to create source provider: %w", err)
+	}
+	if err := sourceProvider.CheckPermissions(ctx); err != nil {
+		return nil, gitlabError(err)
+	}
+
+	fileScanner, manifestParsers, err := newScanner(cfg, sourceProvider, l)
+	if err != nil {
+		return nil, withExitCode(exitConfigError, err)
+	}
+
+	offlineMode := offline || cfg.Offline
+	enrichmentCache := cache.New(cfg.Cache.Directory())
+	parserRepositories := cfg.Maven.RemoteRepositories
+	if offlineMode {
+		parserRepositories = nil
+	}
+	dependencyParser := parser.NewParser().
+		WithFileAliases(manifestParsers).
+		WithMavenRepositories(parserRepositories)
+	if cfg.Cache.ParseResults {
+		dependencyParser.WithCache(enrichmentCache)
+	}
+
+	internalHosts := append([]string{}, cfg.Internal.Domains...)
+	if baseURL, err := url.Parse(cfg.SourceBaseURL()); err == nil && baseURL.Host != "" {
+		internalHosts = append(internalHosts, baseURL.Host)
+	}
+	dependencyClassifier := classifier.NewClassifier(cfg.Internal.Patterns).WithInternalHosts(internalHosts)
+
+	// Patterns were validated with the configuration
+	excludedDependencies, err := exclude.Compile(cfg.Exclude.Dependencies)
+	if err != nil {
+		return nil, configError("invalid dependency exclusions: %w", err)
+	}
+
+	analyzeUseCase := usecases.NewAnalyzeUseCase(
+		ctx, sourceProvider, fileScanner, dependencyParser, dependencyClassifier, nil, l,
+	).WithoutReports().WithSubmoduleResolution(
+		cfg.Scanner.ResolveSubmodules,
+	).WithManagedVersionResolver(
+		maven.NewResolver(l).
+			WithRemoteRepositories(cfg.Maven.RemoteRepositories).
+			WithCache(enrichmentCache).
+			WithOffline(offlineMode).
+			WithRetryPolicy(cfg.Retry.Registry.Policy()),
+	).WithPrereleasePolicy(
+		depversion.PrereleasePolicy(cfg.Policy.Prereleases),
+	).WithDevDependencies(includeDev || cfg.IncludeDev).
+		WithExcludedDependencies(excludedDependencies).
+		WithProjectWorkers(cfg.Concurrency.ParserWorkers).
+		WithDependencyFileWorkers(cfg.Concurrency.FileParserWorkers)
+
+	if cfg.Registry.Enabled {
+		analyzeUseCase.WithLatestVersionResolver(newLatestVersionResolver(cfg, enrichmentCache, offlineMode, l))
+	}
+	return analyzeUseCase, nil
+}
+
+// scannedDependencies flattens the dependencies of the projects in project and name order
+func scannedDependencies(projects []*domain.Project) []scannedDependency {
+	var dependencies []scannedDependency
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			dependencies = append(dependencies, scannedDependency{
+				Repository:    project.Repository.Name,
+				Project:       project.Name,
+				Path:          project.Path,
+				Language:      project.Language,
+				Name:          dep.Name,
+				Version:       dep.Version,
+				LatestVersion: dep.LatestVersion,
+				Outdated:      dep.LatestVersion != "" && depversion.IsOutdated(dep.Version, dep.LatestVersion),
+				Ecosystem:     dep.Ecosystem,
+				Scope:         dep.DependencyScope(),
+				Direct:        dep.Direct,
+				Internal:      dep.IsInternal,
+				VulnCount:     dep.VulnCount,
+			})
+		}
+	}
+	slices.SortStableFunc(dependencies, func(a, b scannedDependency) int {
+		if order := strings.Compare(a.Repository+"\x00"+a.Path, b.Repository+"\x00"+b.Path); order != 0 {
+			return order
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return dependencies
+}
+
+// writeScannedTable prints one row per dependency
+func writeScannedTable(w io.Writer, projects []*domain.Project) error {
+	dependencies := scannedDependencies(projects)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tDEPENDENCY\tVERSION\tLATEST\tSCOPE\tTYPE")
+	outdated := 0
+	for _, dep := range dependencies {
+		latest := dep.LatestVersion
+		if dep.Outdated {
+			latest += " ⬆"
+			outdated++
+		}
+		kind := "transitive"
+		if dep.Direct {
+			kind = "direct"
+		}
+		if dep.Internal {
+			kind += ", internal"
+		}
+		projectPath := dep.Path
+		if projectPath == "" {
+			projectPath = "."
+		}
+		fmt.Fprintf(tw, "%s:%s\t%s\t%s\t%s\t%s\t%s\n",
+			dep.Repository, projectPath, dep.Name, dep.Version, latest, dep.Scope, kind)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write dependencies table: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%d dependencies in %d projects, %d outdated\n", len(dependencies), len(projects), outdated)
+	return nil
+}
+
+// writeScannedJSON prints the dependencies as a JSON array
+func writeScannedJSON(w io.Writer, projects []*domain.Project) error {
+	dependencies := scannedDependencies(projects)
+	if dependencies == nil {
+		dependencies = []scannedDependency{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(dependencies); err != nil {
+		return fmt.Errorf("failed to encode dependencies: %w", err)
+	}
+	return nil
+}
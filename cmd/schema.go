@@ -0,0 +1,25 @@
+package main
+
+import (
+	"di-matrix-cli/internal/report"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the JSON report",
+	Long: `Print the JSON Schema the analyze --json-output report conforms to. Reports carry a
+schema_version: minor versions only add optional fields, a field is removed, renamed or
+retyped only with a new major version.`,
+	RunE: runSchema,
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if _, err := cmd.OutOrStdout().Write(report.Schema()); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	return nil
+}
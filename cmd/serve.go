@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/logger"
+	"di-matrix-cli/internal/schedule"
+	"di-matrix-cli/internal/server"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// serveShutdownTimeout bounds how long in-flight HTTP requests may take once the server stops
+	serveShutdownTimeout = 10 * time.Second
+	// serveReadHeaderTimeout bounds how long a client may take to send request headers
+	serveReadHeaderTimeout = 10 * time.Second
+)
+
+var (
+	serveAddr     string
+	serveLanguage string
+	serveDir      string
+	serveSchedule string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the dependency matrix over HTTP and rerun the analysis on demand",
+	Long: `Analyze the configured repositories and serve the HTML report with a REST API,
+so a team can host a continuously updated matrix internally:
+
+  GET  /              the HTML report
+  GET  /api/summary   portfolio statistics and the state of the analyses
+  GET  /api/projects  analyzed projects with their dependencies (JSON report layout)
+  GET  /api/matrix    the version every project uses of every dependency
+  POST /api/analyze   rerun the analysis, 409 while one is running
+  GET  /api/reports   reports of earlier analyses kept in the history
+  GET  /reports/ID    an archived report, ID.html or ID.json
+  GET  /metrics       Prometheus metrics of the server and the latest analysis
+
+The reports are written to --dir, the latest one is served again after a restart.
+With --schedule (or serve.schedule) the analysis also reruns on a cron schedule,
+and the reports of the last serve.keep_reports analyses are kept in --dir/history.`,
+	RunE: runServe,
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(supportedLanguages, serveLanguage) {
+		return configError("invalid language '%s'. Supported languages: %s",
+			serveLanguage, strings.Join(supportedLanguages, ", "))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if serveSchedule != "" {
+		cfg.Serve.Schedule = serveSchedule
+	}
+	var sched *schedule.Schedule
+	if cfg.Serve.Schedule != "" {
+		if sched, err = schedule.Parse(cfg.Serve.Schedule); err != nil {
+			return configError("%w", err)
+		}
+	}
+	if err := os.MkdirAll(serveDir, 0o750); err != nil {
+		return configError("failed to create report directory: %w", err)
+	}
+	cfg.Output.HTMLFile = filepath.Join(serveDir, "index.html")
+	cfg.Output.JSONFile = filepath.Join(serveDir, "report.json")
+	cfg.Output.Formats = []string{domain.FormatHTML, domain.FormatJSON}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return configError("failed to listen on %s: %w", serveAddr, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	l := logger.GetLogger()
+	var srv *server.Server
+	srv = server.New(ctx, func(ctx context.Context) error {
+		// Every run starts from the loaded configuration, analyze applies its flags to the copy
+		runCfg := *cfg
+		return analyzeContext(ctx, &runCfg, serveLanguage, srv.Record)
+	}, cfg.Output.HTMLFile, cfg.Output.JSONFile, l)
+	srv.WithHistory(filepath.Join(serveDir, "history"), cfg.Serve.KeepReports)
+	if err := srv.Load(); err == nil {
+		fmt.Printf("📄 Serving the report of the previous analysis from %s until the new one finishes\n", serveDir)
+	}
+	srv.Trigger()
+	if sched != nil {
+		go srv.RunSchedule(ctx, sched)
+		fmt.Printf("⏰ Rerunning the analysis on schedule %q, next run at %s\n", sched.String(),
+			sched.Next(time.Now()).Format(time.RFC3339))
+	}
+
+	httpServer := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: serveReadHeaderTimeout}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("🌐 Serving the %s dependency matrix at http://%s, press Ctrl-C to stop\n", serveLanguage, listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return withExitCode(exitFailure, fmt.Errorf("failed to serve: %w", err))
+	}
+
+	// Let a running analysis write its partial report before exiting
+	srv.Wait()
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"di-matrix-cli/internal/generator"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Print the embedded HTML report template",
+	Long: `Print the Go template the HTML report is rendered with, a starting point for a custom template
+set as output.template_file. Files of output.assets_dir are copied to assets/ next to the report:
+templates link them from .Assets, and the embedded template links the stylesheets among them.`,
+	RunE: runTemplate,
+}
+
+func runTemplate(cmd *cobra.Command, args []string) error {
+	if _, err := fmt.Fprint(cmd.OutOrStdout(), generator.Template()); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/generator"
+	"di-matrix-cli/internal/upload"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// uploadTimeout bounds how long uploading a single report may take
+const uploadTimeout = 5 * time.Minute
+
+// uploadReports archives the reports written in formats to the configured object storage, if any, and returns
+// their URLs by format. The reports of a run share a timestamp in their keys, so earlier runs are kept.
+func uploadReports(ctx context.Context, cfg *config.Config, formats []string) (map[string]string, error) {
+	if cfg.Output.Upload == "" {
+		return nil, nil
+	}
+	target, err := upload.Parse(cfg.Output.Upload)
+	if err != nil {
+		return nil, err
+	}
+	store, err := upload.NewStore(target, &http.Client{Timeout: uploadTimeout}, os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadedAt := time.Now()
+	uploaded := make(map[string]string, len(formats))
+	for _, format := range formats {
+		path := reportPath(cfg, format)
+		if path == generator.Stdout {
+			continue
+		}
+		content, err := os.ReadFile(path) //nolint:gosec // Report paths are set by the configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s report: %w", format, err)
+		}
+		key := target.Key(uploadedAt, filepath.Base(path))
+		if err := store.Put(ctx, key, upload.ContentType(path), content); err != nil {
+			return nil, err
+		}
+		uploaded[format] = store.URL(key)
+		statusf("☁️  %s report uploaded to %s\n", strings.ToUpper(format), uploaded[format])
+	}
+	return uploaded, nil
+}
@@ -0,0 +1,68 @@
+package annotations
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/parser"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set maps dependency names to annotations. Names are matched as normalized by the parser,
+// so "Django" annotates the PyPI package "django" and "@Company/UI" the npm package "@company/ui".
+type Set map[string]domain.Annotation
+
+// file is the layout of an annotations file
+type file struct {
+	Annotations Set `yaml:"annotations"`
+}
+
+// Load reads an annotations file:
+//
+//	annotations:
+//	  github.com/go-resty/resty/v2:
+//	    note: "Retries hide outages"
+//	    owner: "platform-team"
+//	    replacement: "use internal http-kit instead of resty"
+func Load(path string) (Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var parsed file
+	if err := decoder.Decode(&parsed); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse annotations file %s: %w", path, err)
+	}
+
+	for name, annotation := range parsed.Annotations {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("annotations file %s: dependency name must not be empty", path)
+		}
+		if annotation == (domain.Annotation{}) {
+			return nil, fmt.Errorf("annotations file %s: %s needs a note, owner or replacement", path, name)
+		}
+	}
+	return parsed.Annotations, nil
+}
+
+// Lookup returns the annotation of a dependency name within ecosystem
+func (s Set) Lookup(ecosystem, name string) (domain.Annotation, bool) {
+	if annotation, ok := s[name]; ok {
+		return annotation, true
+	}
+	for key, annotation := range s {
+		if parser.NormalizeName(ecosystem, key) == name {
+			return annotation, true
+		}
+	}
+	return domain.Annotation{}, false
+}
@@ -0,0 +1,67 @@
+package annotations_test
+
+import (
+	"di-matrix-cli/internal/annotations"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "annotations.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	set, err := annotations.Load(writeFile(t, `
+annotations:
+  github.com/go-resty/resty/v2:
+    note: "Retries hide outages"
+    owner: "platform-team"
+    replacement: "use internal http-kit instead of resty"
+  Django_REST_framework:
+    owner: "web-team"
+`))
+	require.NoError(t, err)
+	require.Len(t, set, 2)
+
+	resty, ok := set.Lookup("go-modules", "github.com/go-resty/resty/v2")
+	require.True(t, ok)
+	assert.Equal(t, "platform-team", resty.Owner)
+	assert.Equal(t, "use internal http-kit instead of resty", resty.Replacement)
+
+	drf, ok := set.Lookup("pip", "django-rest-framework")
+	require.True(t, ok, "Names are matched as the parser normalizes them")
+	assert.Equal(t, "web-team", drf.Owner)
+
+	_, ok = set.Lookup("npm", "express")
+	assert.False(t, ok)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"unknown field":    "annotations:\n  express:\n    notes: \"typo\"\n",
+		"empty annotation": "annotations:\n  express: {}\n",
+		"not a map":        "annotations: [express]\n",
+	}
+	for name, content := range tests {
+		_, err := annotations.Load(writeFile(t, content))
+		assert.Error(t, err, name)
+	}
+
+	_, err := annotations.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	set, err := annotations.Load(writeFile(t, ""))
+	require.NoError(t, err, "An empty file has no annotations")
+	assert.Empty(t, set)
+}
@@ -0,0 +1,194 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"di-matrix-cli/internal/domain"
+	"encoding/hex"
+	"path"
+)
+
+// pseudonymLength is the number of hex characters kept from the keyed hash
+const pseudonymLength = 10
+
+// Anonymizer replaces project, repository and path names with stable pseudonyms.
+// Dependency names, versions and every statistic derived from them stay intact.
+type Anonymizer struct {
+	key []byte
+}
+
+// New creates an anonymizer keyed by salt. The same salt yields the same pseudonyms across runs,
+// an empty salt uses a random key so pseudonyms cannot be linked between reports.
+func New(salt string) *Anonymizer {
+	key := []byte(salt)
+	if salt == "" {
+		key = make([]byte, sha256.Size)
+		_, _ = rand.Read(key)
+	}
+	return &Anonymizer{key: key}
+}
+
+// Projects returns anonymized copies of projects, the originals are left untouched.
+// Manifest contents and declared module names are dropped since they name the service.
+func (a *Anonymizer) Projects(projects []*domain.Project) []*domain.Project {
+	if projects == nil {
+		return nil
+	}
+
+	anonymized := make([]*domain.Project, 0, len(projects))
+	for _, project := range projects {
+		anonymized = append(anonymized, a.project(project))
+	}
+	return anonymized
+}
+
+func (a *Anonymizer) project(project *domain.Project) *domain.Project {
+	copied := *project
+	copied.ID = a.pseudonym("project", project.ID)
+	copied.Name = copied.ID
+	copied.ModuleName = ""
+	copied.Path = a.path(project.Path)
+	copied.Repository = domain.Repository{
+		Name:          a.repository(project.Repository),
+		DefaultBranch: project.Repository.DefaultBranch,
+		Ref:           project.Repository.Ref,
+	}
+	if project.Service != "" {
+		copied.Service = a.pseudonym("service", project.Service)
+	}
+	if project.ParentID != "" {
+		copied.ParentID = a.pseudonym("project", project.ParentID)
+	}
+
+	copied.Members = nil
+	for _, member := range project.Members {
+		copied.Members = append(copied.Members, a.pseudonym("project", member))
+	}
+
+	copied.DependencyFiles = nil
+	for _, file := range project.DependencyFiles {
+		copied.DependencyFiles = append(copied.DependencyFiles, &domain.DependencyFile{
+			Path:         a.filePath(file.Path),
+			Language:     file.Language,
+			LastModified: file.LastModified,
+		})
+	}
+
+	copied.Warnings = nil
+	for _, warning := range project.Warnings {
+		warning.File = a.filePath(warning.File)
+		if warning.Fallback != "" {
+			warning.Fallback = a.filePath(warning.Fallback)
+		}
+		if warning.Capability == domain.FileParsed {
+			// Parser errors quote paths and manifest contents
+			warning.Message = "the file could not be parsed"
+		}
+		copied.Warnings = append(copied.Warnings, warning)
+	}
+
+	return &copied
+}
+
+// Coverage returns anonymized copies of repository coverage, repository pseudonyms match those of Projects
+func (a *Anonymizer) Coverage(coverage []domain.RepositoryCoverage) []domain.RepositoryCoverage {
+	if coverage == nil {
+		return nil
+	}
+
+	anonymized := make([]domain.RepositoryCoverage, 0, len(coverage))
+	for _, repository := range coverage {
+		copied := repository
+		copied.Repository = domain.Repository{
+			Name:          a.repository(repository.Repository),
+			DefaultBranch: repository.Repository.DefaultBranch,
+			Ref:           repository.Repository.Ref,
+		}
+		copied.Gaps = nil
+		for _, gap := range repository.Gaps {
+			gap.File = a.filePath(gap.File)
+			if gap.Reason == domain.GapUnparsed {
+				gap.Message = "the file could not be parsed"
+			}
+			copied.Gaps = append(copied.Gaps, gap)
+		}
+		anonymized = append(anonymized, copied)
+	}
+	return anonymized
+}
+
+// Issues returns anonymized copies of analysis errors or warnings, messages may name private paths and are generalized
+func (a *Anonymizer) Issues(issues []domain.AnalysisIssue) []domain.AnalysisIssue {
+	if issues == nil {
+		return nil
+	}
+
+	anonymized := make([]domain.AnalysisIssue, 0, len(issues))
+	for _, issue := range issues {
+		issue.Repository = domain.Repository{
+			Name:          a.repository(issue.Repository),
+			DefaultBranch: issue.Repository.DefaultBranch,
+			Ref:           issue.Repository.Ref,
+		}
+		if issue.Project != "." {
+			issue.Project = a.path(issue.Project)
+		}
+		if issue.File != "" {
+			issue.File = a.filePath(issue.File)
+		}
+		issue.Message = "the " + issue.Stage + " stage failed"
+		anonymized = append(anonymized, issue)
+	}
+	return anonymized
+}
+
+// Violations returns anonymized copies of policy violations, project pseudonyms match those of Projects.
+// Messages of project-level rules name the project and are replaced.
+func (a *Anonymizer) Violations(violations []domain.PolicyViolation) []domain.PolicyViolation {
+	if violations == nil {
+		return nil
+	}
+
+	anonymized := make([]domain.PolicyViolation, 0, len(violations))
+	for _, violation := range violations {
+		violation.ProjectID = a.pseudonym("project", violation.ProjectID)
+		if violation.Dependency == "" {
+			violation.Message = "the project violates " + violation.Rule
+		}
+		anonymized = append(anonymized, violation)
+	}
+	return anonymized
+}
+
+// repository pseudonymizes a repository name
+func (a *Anonymizer) repository(repo domain.Repository) string {
+	return a.pseudonym("repo", repo.URL+"\x00"+repo.Name)
+}
+
+// path pseudonymizes a directory, the repository root stays empty
+func (a *Anonymizer) path(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if dir = path.Clean(dir); dir == "." {
+		return dir
+	}
+	return a.pseudonym("dir", dir)
+}
+
+// filePath keeps the manifest file name, which identifies the ecosystem, and pseudonymizes its directory
+func (a *Anonymizer) filePath(filePath string) string {
+	dir, name := path.Split(filePath)
+	if dir = path.Clean(dir); dir == "." || dir == "/" {
+		return name
+	}
+	return a.path(dir) + "/" + name
+}
+
+// pseudonym returns kind followed by a keyed hash of value
+func (a *Anonymizer) pseudonym(kind, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind + "\x00" + value))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:pseudonymLength]
+}
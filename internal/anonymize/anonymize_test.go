@@ -0,0 +1,153 @@
+package anonymize_test
+
+import (
+	"di-matrix-cli/internal/anonymize"
+	"di-matrix-cli/internal/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func billingProjects() []*domain.Project {
+	gin := &domain.Dependency{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go-modules"}
+	return []*domain.Project{
+		{
+			ID:         "repo-42-services-billing-go",
+			Name:       "billing-service Go (services/billing)",
+			Repository: domain.Repository{ID: 42, Name: "billing-service", URL: "https://gitlab.company.com/fin/billing"},
+			Path:       "services/billing",
+			Language:   "go",
+			ModuleName: "gitlab.company.com/fin/billing",
+			Service:    "billing-api",
+			ParentID:   "repo-42-root-go",
+			DependencyFiles: []*domain.DependencyFile{
+				{Path: "services/billing/go.mod", Language: "go", Content: []byte("module gitlab.company.com/fin/billing")},
+			},
+			Dependencies: []*domain.Dependency{gin},
+			Warnings: []domain.FileWarning{
+				{File: "services/billing/go.sum", Capability: domain.FileParsed, Message: "services/billing/go.sum: bad"},
+				{
+					File:       "services/billing/package-lock.json",
+					Capability: domain.FileParsed,
+					Message:    "services/billing/package-lock.json: bad",
+					Fallback:   "services/billing/package.json",
+				},
+			},
+		},
+	}
+}
+
+func TestAnonymizer_Projects(t *testing.T) {
+	t.Parallel()
+
+	projects := billingProjects()
+	anonymized := anonymize.New("salt").Projects(projects)
+	require.Len(t, anonymized, 1)
+	project := anonymized[0]
+
+	assert.True(t, strings.HasPrefix(project.ID, "project-"))
+	assert.Equal(t, project.ID, project.Name)
+	assert.True(t, strings.HasPrefix(project.Repository.Name, "repo-"))
+	assert.Empty(t, project.Repository.URL)
+	assert.Empty(t, project.Repository.WebURL)
+	assert.Zero(t, project.Repository.ID)
+	assert.True(t, strings.HasPrefix(project.Path, "dir-"))
+	assert.Equal(t, project.Path+"/go.mod", project.DependencyFiles[0].Path)
+	assert.Nil(t, project.DependencyFiles[0].Content)
+	assert.Empty(t, project.ModuleName)
+	assert.True(t, strings.HasPrefix(project.Service, "service-"))
+	assert.True(t, strings.HasPrefix(project.ParentID, "project-"))
+	assert.Equal(t, "the file could not be parsed", project.Warnings[0].Message)
+	assert.Equal(t, project.Path+"/package.json", project.Warnings[1].Fallback)
+
+	// Dependency data stays intact and the input is not modified
+	assert.Equal(t, "github.com/gin-gonic/gin", project.Dependencies[0].Name)
+	assert.Equal(t, "v1.9.1", project.Dependencies[0].Version)
+	assert.Equal(t, "billing-service", projects[0].Repository.Name)
+	assert.Equal(t, "services/billing/go.mod", projects[0].DependencyFiles[0].Path)
+
+	for _, value := range []string{project.ID, project.Name, project.Repository.Name, project.Path, project.Service} {
+		assert.NotContains(t, value, "billing")
+	}
+}
+
+func TestAnonymizer_StablePseudonyms(t *testing.T) {
+	t.Parallel()
+
+	first := anonymize.New("salt").Projects(billingProjects())[0]
+	second := anonymize.New("salt").Projects(billingProjects())[0]
+	other := anonymize.New("other-salt").Projects(billingProjects())[0]
+	random := anonymize.New("").Projects(billingProjects())[0]
+
+	assert.Equal(t, first.ID, second.ID, "The same salt yields the same pseudonyms")
+	assert.NotEqual(t, first.ID, other.ID)
+	assert.NotEqual(t, first.ID, random.ID)
+}
+
+func TestAnonymizer_Coverage(t *testing.T) {
+	t.Parallel()
+
+	coverage := []domain.RepositoryCoverage{{
+		Repository: domain.Repository{ID: 42, Name: "billing-service", URL: "https://gitlab.company.com/fin/billing"},
+		Analyzed:   2,
+		Gaps: []domain.CoverageGap{
+			{File: "services/billing/go.mod", Reason: domain.GapUnparsed, Message: "services/billing/go.mod: bad"},
+		},
+	}}
+	anonymizer := anonymize.New("salt")
+	anonymized := anonymizer.Coverage(coverage)
+	require.Len(t, anonymized, 1)
+
+	// Pseudonyms match the ones of the project rows
+	project := anonymizer.Projects(billingProjects())[0]
+	assert.Equal(t, project.Repository.Name, anonymized[0].Repository.Name)
+	assert.Empty(t, anonymized[0].Repository.URL)
+	assert.Equal(t, 2, anonymized[0].Analyzed)
+	assert.Equal(t, project.DependencyFiles[0].Path, anonymized[0].Gaps[0].File)
+	assert.NotContains(t, anonymized[0].Gaps[0].Message, "billing")
+	assert.Equal(t, "services/billing/go.mod", coverage[0].Gaps[0].File, "Input is not modified")
+}
+
+func TestAnonymizer_Violations(t *testing.T) {
+	t.Parallel()
+
+	violations := []domain.PolicyViolation{
+		{Rule: "pinning.lockfile", ProjectID: "repo-42-services-billing-go", Message: "project billing-service has no lockfile"},
+		{Rule: "dependency.denied", ProjectID: "repo-42-services-billing-go", Dependency: "lodash",
+			Message: "lodash 4.17.20 is denied by \"lodash@<4.17.21\""},
+	}
+	anonymizer := anonymize.New("salt")
+	anonymized := anonymizer.Violations(violations)
+	require.Len(t, anonymized, 2)
+
+	project := anonymizer.Projects(billingProjects())[0]
+	assert.Equal(t, project.ID, anonymized[0].ProjectID)
+	assert.NotContains(t, anonymized[0].Message, "billing")
+	assert.Equal(t, violations[1].Message, anonymized[1].Message, "dependency names are kept")
+	assert.Equal(t, "repo-42-services-billing-go", violations[0].ProjectID, "Input is not modified")
+}
+
+func TestAnonymizer_Issues(t *testing.T) {
+	t.Parallel()
+
+	issues := []domain.AnalysisIssue{{
+		Repository: domain.Repository{ID: 42, Name: "billing-service", URL: "https://gitlab.company.com/fin/billing"},
+		Project:    "services/billing",
+		File:       "services/billing/go.mod",
+		Stage:      domain.StageParse,
+		Message:    "services/billing/go.mod: bad",
+	}}
+	anonymizer := anonymize.New("salt")
+	anonymized := anonymizer.Issues(issues)
+	require.Len(t, anonymized, 1)
+
+	project := anonymizer.Projects(billingProjects())[0]
+	assert.Equal(t, project.Repository.Name, anonymized[0].Repository.Name)
+	assert.Empty(t, anonymized[0].Repository.URL)
+	assert.Equal(t, project.Path, anonymized[0].Project)
+	assert.Equal(t, project.DependencyFiles[0].Path, anonymized[0].File)
+	assert.NotContains(t, anonymized[0].Message, "billing")
+	assert.Equal(t, "services/billing", issues[0].Project, "Input is not modified")
+}
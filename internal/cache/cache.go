@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Store is a file-based cache of downloaded enrichment data (remote POMs, registry and advisory responses)
+// shared between runs, so offline runs can serve what earlier online runs fetched
+type Store struct {
+	dir string
+}
+
+// New creates a cache rooted at dir, the directory is created on first write
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the per-user cache directory, falling back to the temporary directory
+func DefaultDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "di-matrix-cli")
+}
+
+// Dir returns the cache root directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Get returns the cached data of key within namespace
+func (s *Store) Get(namespace, key string) ([]byte, bool) {
+	data, err := os.ReadFile(s.path(namespace, key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores data for key within namespace, replacing any previous entry atomically
+func (s *Store) Put(namespace, key string, data []byte) error {
+	target := s.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file name that is safe regardless of the characters in the key
+func (s *Store) path(namespace, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, namespace, hex.EncodeToString(sum[:]))
+}
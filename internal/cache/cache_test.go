@@ -0,0 +1,30 @@
+package cache_test
+
+import (
+	"di-matrix-cli/internal/cache"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_GetPut(t *testing.T) {
+	t.Parallel()
+
+	store := cache.New(filepath.Join(t.TempDir(), "cache"))
+
+	_, ok := store.Get("maven", "org/example/bom/1.0/bom-1.0.pom")
+	assert.False(t, ok)
+
+	require.NoError(t, store.Put("maven", "org/example/bom/1.0/bom-1.0.pom", []byte("v1")))
+	require.NoError(t, store.Put("maven", "org/example/bom/1.0/bom-1.0.pom", []byte("v2")))
+
+	data, ok := store.Get("maven", "org/example/bom/1.0/bom-1.0.pom")
+	require.True(t, ok)
+	assert.Equal(t, "v2", string(data))
+
+	// Namespaces keep equal keys apart
+	_, ok = store.Get("npm", "org/example/bom/1.0/bom-1.0.pom")
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package checkpoint
+
+import (
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// formatVersion is bumped whenever the checkpoint layout changes, older checkpoints are rejected
+const formatVersion = 1
+
+// Checkpoint records the repositories an interrupted analysis finished, with their projects,
+// so a resumed run only analyzes the rest
+type Checkpoint struct {
+	Version      int               `json:"version"`
+	Language     string            `json:"language"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Repositories []string          `json:"completed_repositories"` // Repository URLs
+	Projects     []*domain.Project `json:"projects"`               // Projects of the completed repositories
+
+	// Repositories whose projects were detected but not all processed, a resumed run processes them without
+	// scanning the repositories again
+	Scanned         []string          `json:"scanned_repositories,omitempty"`
+	ScannedProjects []*domain.Project `json:"scanned_projects,omitempty"` // Detected projects with file contents
+}
+
+// New creates an empty checkpoint for an analysis of language
+func New(language string) *Checkpoint {
+	return &Checkpoint{Version: formatVersion, Language: language}
+}
+
+// Load reads a checkpoint written by Save
+func Load(path string) (*Checkpoint, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // Path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Version != formatVersion {
+		return nil, fmt.Errorf("checkpoint %s has unsupported version %d", path, checkpoint.Version)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint atomically so an interrupted write never leaves a corrupt file
+func (c *Checkpoint) Save(path string) error {
+	c.CreatedAt = time.Now().UTC()
+
+	content, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Detected returns the detected projects of the scanned repositories by repository URL
+func (c *Checkpoint) Detected() map[string][]*domain.Project {
+	detected := make(map[string][]*domain.Project, len(c.Scanned))
+	for _, url := range c.Scanned {
+		detected[url] = []*domain.Project{}
+	}
+	for _, project := range c.ScannedProjects {
+		if projects, ok := detected[project.Repository.URL]; ok {
+			detected[project.Repository.URL] = append(projects, project)
+		}
+	}
+	return detected
+}
+
+// Completed reports whether the repository at url was fully analyzed
+func (c *Checkpoint) Completed(url string) bool {
+	for _, completed := range c.Repositories {
+		if completed == url {
+			return true
+		}
+	}
+	return false
+}
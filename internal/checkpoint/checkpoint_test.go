@@ -0,0 +1,69 @@
+package checkpoint_test
+
+import (
+	"di-matrix-cli/internal/checkpoint"
+	"di-matrix-cli/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "checkpoint.json")
+	saved := checkpoint.New("go")
+	saved.Repositories = []string{"https://gitlab.com/group/api"}
+	saved.Projects = []*domain.Project{{
+		ID:              "repo-1-root-go",
+		Repository:      domain.Repository{URL: "https://gitlab.com/group/api"},
+		DependencyFiles: []*domain.DependencyFile{{Path: "go.mod", Content: []byte("module api")}},
+		Dependencies:    []*domain.Dependency{{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", IsInternal: false}},
+	}}
+	require.NoError(t, saved.Save(path))
+
+	loaded, err := checkpoint.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "go", loaded.Language)
+	assert.False(t, loaded.CreatedAt.IsZero())
+	assert.True(t, loaded.Completed("https://gitlab.com/group/api"))
+	assert.False(t, loaded.Completed("https://gitlab.com/group/web"))
+	require.Len(t, loaded.Projects, 1)
+	assert.Equal(t, "module api", string(loaded.Projects[0].DependencyFiles[0].Content))
+	assert.Equal(t, "v1.9.1", loaded.Projects[0].Dependencies[0].Version)
+}
+
+func TestCheckpoint_Detected(t *testing.T) {
+	t.Parallel()
+
+	saved := checkpoint.New("go")
+	saved.Scanned = []string{"https://gitlab.com/group/web"}
+	saved.ScannedProjects = []*domain.Project{
+		{ID: "repo-2-root-go", Repository: domain.Repository{URL: "https://gitlab.com/group/web"}},
+		{ID: "repo-2-tools-go", Repository: domain.Repository{URL: "https://gitlab.com/group/web"}},
+	}
+
+	detected := saved.Detected()
+	assert.Len(t, detected["https://gitlab.com/group/web"], 2)
+	assert.False(t, saved.Completed("https://gitlab.com/group/web"), "Scanned repositories are not completed")
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	unsupported := filepath.Join(dir, "unsupported.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte(`{"version": 1,`), 0o600))
+	require.NoError(t, os.WriteFile(unsupported, []byte(`{"version": 99, "language": "go"}`), 0o600))
+
+	_, err := checkpoint.Load(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+	_, err = checkpoint.Load(corrupt)
+	require.Error(t, err)
+	_, err = checkpoint.Load(unsupported)
+	require.ErrorContains(t, err, "unsupported version 99")
+}
@@ -10,6 +10,8 @@ import (
 // Classifier determines if dependencies are internal or external
 type Classifier struct {
 	internalPatterns []string
+	internalHosts    []string // Hosts with optional path prefix, e.g. "gitlab.company.com/group"
+	internalProjects []string // Project locations, e.g. "gitlab.company.com/group/project"
 }
 
 // NewClassifier creates a new dependency classifier
@@ -19,6 +21,13 @@ func NewClassifier(internalPatterns []string) *Classifier {
 	}
 }
 
+// WithInternalHosts treats dependencies installed from git repositories on these hosts as internal.
+// Entries may carry a path prefix, e.g. "gitlab.company.com/group".
+func (c *Classifier) WithInternalHosts(hosts []string) *Classifier {
+	c.internalHosts = hosts
+	return c
+}
+
 // ClassifyDependencies classifies a list of dependencies
 func (c *Classifier) ClassifyDependencies(
 	ctx context.Context,
@@ -51,7 +60,7 @@ func (c *Classifier) IsInternal(ctx context.Context, dependency *domain.Dependen
 		}
 	}
 
-	return false
+	return c.isInternalProject(dependency.Name) || c.isInternalSource(dependency.Source)
 }
 
 // matchesPattern checks if a dependency name matches a given pattern
@@ -443,3 +443,30 @@ func TestClassifier_EdgeCases(t *testing.T) {
 		assert.True(t, result)
 	})
 }
+
+func TestClassifier_IsInternal_Sources(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := classifier.NewClassifier(nil).WithInternalHosts([]string{"gitlab.company.com", "github.com/company"})
+
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{"", false},
+		{"file:../shared", true},
+		{"link:./packages/ui", true},
+		{"workspace:*", true},
+		{"git+ssh://git@gitlab.company.com:team/lib.git#v1.0.0", true},
+		{"git+https://gitlab.company.com:8443/team/lib.git", true},
+		{"github:company/tool#v3", true},
+		{"github:other/tool", false},
+		{"https://github.com/companyx/tool.git", false},
+		{"npm:bar@^2", false},
+	}
+
+	for _, tt := range tests {
+		dep := &domain.Dependency{Name: "pkg", Source: tt.source, Ecosystem: "npm"}
+		assert.Equal(t, tt.expected, c.IsInternal(ctx, dep), tt.source)
+	}
+}
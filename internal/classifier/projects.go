@@ -0,0 +1,61 @@
+package classifier
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// ProjectLocations lists the projects below the given group and project URLs as "host/path" locations,
+// e.g. "gitlab.company.com/platform/auth", the import path of Go modules published from them
+func ProjectLocations(ctx context.Context, provider domain.SourceProvider, urls []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var locations []string
+	for _, url := range urls {
+		repositories, err := provider.GetRepositoriesList(ctx, url)
+		if err != nil {
+			return locations, fmt.Errorf("failed to list projects of %s: %w", url, err)
+		}
+		for _, repo := range repositories {
+			location := sourceLocation(repo.WebURL)
+			if location != "" && !seen[location] {
+				seen[location] = true
+				locations = append(locations, location)
+			}
+		}
+	}
+	return locations, nil
+}
+
+// WithInternalProjects treats dependencies built by these projects as internal, without maintaining patterns:
+// Go modules at or below a project location and npm packages named after a project and scoped to one of
+// its groups ("@platform/auth" for "gitlab.company.com/platform/auth")
+func (c *Classifier) WithInternalProjects(locations []string) *Classifier {
+	c.internalProjects = locations
+	return c
+}
+
+// isInternalProject reports whether a dependency name maps to one of the internal projects
+func (c *Classifier) isInternalProject(name string) bool {
+	for _, location := range c.internalProjects {
+		if name == location || strings.HasPrefix(name, location+"/") {
+			return true
+		}
+
+		scope, pkg, scoped := strings.Cut(strings.TrimPrefix(name, "@"), "/")
+		if !scoped || !strings.HasPrefix(name, "@") {
+			continue
+		}
+		segments := strings.Split(location, "/")
+		if len(segments) < 3 || !strings.EqualFold(pkg, segments[len(segments)-1]) {
+			continue
+		}
+		for _, group := range segments[1 : len(segments)-1] {
+			if strings.EqualFold(scope, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
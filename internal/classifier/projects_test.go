@@ -0,0 +1,80 @@
+package classifier_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/classifier"
+	"di-matrix-cli/internal/domain"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// groupProvider lists fixed repositories per group URL
+type groupProvider struct {
+	domain.SourceProvider
+	groups map[string][]*domain.Repository
+}
+
+func (p *groupProvider) GetRepositoriesList(_ context.Context, url string) ([]*domain.Repository, error) {
+	repositories, ok := p.groups[url]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return repositories, nil
+}
+
+func TestProjectLocations(t *testing.T) {
+	t.Parallel()
+
+	provider := &groupProvider{groups: map[string][]*domain.Repository{
+		"https://gitlab.company.com/platform": {
+			{Name: "auth", WebURL: "https://gitlab.company.com/platform/auth"},
+			{Name: "billing", WebURL: "https://gitlab.company.com/platform/payments/billing"},
+		},
+		"https://gitlab.company.com/platform/auth": {
+			{Name: "auth", WebURL: "https://gitlab.company.com/platform/auth"},
+		},
+	}}
+
+	locations, err := classifier.ProjectLocations(context.Background(), provider, []string{
+		"https://gitlab.company.com/platform", "https://gitlab.company.com/platform/auth",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gitlab.company.com/platform/auth", "gitlab.company.com/platform/payments/billing"},
+		locations)
+
+	locations, err = classifier.ProjectLocations(context.Background(), provider, []string{
+		"https://gitlab.company.com/platform/auth", "https://gitlab.company.com/missing",
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"gitlab.company.com/platform/auth"}, locations, "projects listed so far are kept")
+}
+
+func TestClassifier_IsInternal_Projects(t *testing.T) {
+	t.Parallel()
+
+	c := classifier.NewClassifier(nil).WithInternalProjects([]string{
+		"gitlab.company.com/platform/auth", "gitlab.company.com/platform/payments/billing",
+	})
+	tests := []struct {
+		name     string
+		internal bool
+	}{
+		{"gitlab.company.com/platform/auth", true},
+		{"gitlab.company.com/platform/auth/v2", true},
+		{"gitlab.company.com/platform/authz", false},
+		{"gitlab.company.com/platform/payments/billing/client", true},
+		{"@platform/auth", true},
+		{"@payments/billing", true},
+		{"@Platform/Billing", true},
+		{"@other/auth", false},
+		{"auth", false},
+		{"github.com/gin-gonic/gin", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.internal, c.IsInternal(context.Background(), &domain.Dependency{Name: tt.name}), tt.name)
+	}
+}
@@ -0,0 +1,67 @@
+package classifier
+
+import (
+	"strings"
+)
+
+// hostedGitShorthands maps npm hosted git shorthands to their hosts
+var hostedGitShorthands = map[string]string{ //nolint:gochecknoglobals // lookup table
+	"github:":    "github.com/",
+	"gitlab:":    "gitlab.com/",
+	"bitbucket:": "bitbucket.org/",
+}
+
+// isInternalSource reports whether a dependency comes from the local file system
+// or from a git repository on an internal host
+func (c *Classifier) isInternalSource(source string) bool {
+	if source == "" {
+		return false
+	}
+
+	for _, prefix := range []string{"file:", "link:", "workspace:", "./", "../", "/", "~/"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+
+	location := sourceLocation(source)
+	for _, host := range c.internalHosts {
+		host = strings.TrimSuffix(host, "/")
+		if host != "" && (location == host || strings.HasPrefix(location, host+"/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sourceLocation reduces a git URL or shorthand to "host/path",
+// e.g. "git+ssh://git@gitlab.company.com:team/lib.git#v1" becomes "gitlab.company.com/team/lib"
+func sourceLocation(source string) string {
+	location, _, _ := strings.Cut(source, "#")
+
+	for shorthand, host := range hostedGitShorthands {
+		if strings.HasPrefix(location, shorthand) {
+			return host + strings.TrimSuffix(strings.TrimPrefix(location, shorthand), ".git")
+		}
+	}
+
+	location = strings.TrimPrefix(location, "git+")
+	scp := !strings.Contains(location, "://")
+	if _, rest, found := strings.Cut(location, "://"); found {
+		location = rest
+	}
+	if _, rest, found := strings.Cut(location, "@"); found {
+		location = rest
+	}
+	if scp {
+		// scp-like "gitlab.company.com:team/lib.git"
+		location = strings.Replace(location, ":", "/", 1)
+	} else if host, path, found := strings.Cut(location, "/"); found {
+		// Drop ports, "gitlab.company.com:2222/team/lib"
+		host, _, _ = strings.Cut(host, ":")
+		location = host + "/" + path
+	}
+
+	return strings.TrimSuffix(location, ".git")
+}
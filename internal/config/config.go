@@ -1,68 +1,510 @@
 package config
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/eol"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/policy"
+	"di-matrix-cli/internal/retry"
+	"di-matrix-cli/internal/schedule"
+	"di-matrix-cli/internal/upload"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"os/exec"
+	"path"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the main configuration structure
 type Config struct {
+	// Source code host the repositories are read from: gitlab, gitea (Gitea or Forgejo) or local (directories on disk)
+	Provider     string             `yaml:"provider"     mapstructure:"provider"`
 	GitLab       GitLabConfig       `yaml:"gitlab"       mapstructure:"gitlab"`
+	Gitea        GiteaConfig        `yaml:"gitea"        mapstructure:"gitea"`
 	Repositories []RepositoryConfig `yaml:"repositories" mapstructure:"repositories"`
 	Internal     InternalConfig     `yaml:"internal"     mapstructure:"internal"`
+	Exclude      ExcludeConfig      `yaml:"exclude"      mapstructure:"exclude"`
 	Output       OutputConfig       `yaml:"output"       mapstructure:"output"`
 	Timeout      TimeoutConfig      `yaml:"timeout"      mapstructure:"timeout"`
+	Policy       PolicyConfig       `yaml:"policy"       mapstructure:"policy"`
+	Health       HealthConfig       `yaml:"health"       mapstructure:"health"`
+	Scanner      ScannerConfig      `yaml:"scanner"      mapstructure:"scanner"`
+	Manifests    []ManifestConfig   `yaml:"manifests"    mapstructure:"manifests"`
+	Concurrency  ConcurrencyConfig  `yaml:"concurrency"  mapstructure:"concurrency"`
+	Maven        MavenConfig        `yaml:"maven"        mapstructure:"maven"`
+	Registry     RegistryConfig     `yaml:"registry"     mapstructure:"registry"`
+	OSV          OSVConfig          `yaml:"osv"          mapstructure:"osv"`
+	EOL          EOLConfig          `yaml:"eol"          mapstructure:"eol"`
+	Cache        CacheConfig        `yaml:"cache"        mapstructure:"cache"`
+	Retry        RetryConfig        `yaml:"retry"        mapstructure:"retry"`
+	Serve        ServeConfig        `yaml:"serve"        mapstructure:"serve"`
+	Metrics      MetricsConfig      `yaml:"metrics"      mapstructure:"metrics"`
+	// Forbid network calls other than GitLab, enrichment is served from the cache only
+	Offline bool `yaml:"offline" mapstructure:"offline"`
+	// Keep dev and test dependencies in the analysis, only runtime and optional ones are analyzed otherwise
+	IncludeDev bool `yaml:"include_dev" mapstructure:"include_dev"`
+	// Chat notifications posted after every complete analysis of the analyze command
+	Notifications NotificationsConfig `yaml:"notifications" mapstructure:"notifications"`
+	// Dependency-Track server the SBOM of every analyzed project is uploaded to after every complete analysis
+	DependencyTrack DependencyTrackConfig `yaml:"dependency_track" mapstructure:"dependency_track"`
 }
 
 // GitLabConfig represents GitLab connection settings
 type GitLabConfig struct {
 	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
 	Token   string `yaml:"token"    mapstructure:"token"`
+	// How the token authenticates, one of the GitLab token type constants. A job token defaults to CI_JOB_TOKEN
+	// and the base URL to CI_SERVER_URL, so the tool runs in GitLab CI without a long-lived access token.
+	TokenType string `yaml:"token_type" mapstructure:"token_type"`
+	// Where the token is read from instead of token: a file, e.g. a mounted Kubernetes or Docker secret, or the
+	// output of a shell command, e.g. "vault kv get -field=token secret/gitlab". Surrounding whitespace is trimmed.
+	TokenFile    string `yaml:"token_file"    mapstructure:"token_file"`
+	TokenCommand string `yaml:"token_command" mapstructure:"token_command"`
+	// Branch or tag analyzed in every repository instead of its default branch and the per-repository branch
+	Ref string `yaml:"ref" mapstructure:"ref"`
+	// Group entries only: leave archived projects out and keep forks, empty repositories are always left out
+	SkipArchived bool `yaml:"skip_archived" mapstructure:"skip_archived"`
+	IncludeForks bool `yaml:"include_forks" mapstructure:"include_forks"`
+	// Further GitLab instances, repositories on their hosts are read with their own credentials and the
+	// remaining repositories from base_url. Every other GitLab setting applies to all instances.
+	Instances []GitLabInstanceConfig `yaml:"instances" mapstructure:"instances"`
+}
+
+// GitLabInstanceConfig represents the connection settings of a further GitLab instance
+type GitLabInstanceConfig struct {
+	BaseURL      string `yaml:"base_url"      mapstructure:"base_url"`
+	Token        string `yaml:"token"         mapstructure:"token"`
+	TokenType    string `yaml:"token_type"    mapstructure:"token_type"`
+	TokenFile    string `yaml:"token_file"    mapstructure:"token_file"`
+	TokenCommand string `yaml:"token_command" mapstructure:"token_command"`
+}
+
+// GiteaConfig represents Gitea or Forgejo connection settings, repository entries are repository URLs or
+// organization and user URLs expanding to their repositories
+type GiteaConfig struct {
+	BaseURL      string `yaml:"base_url"      mapstructure:"base_url"`
+	Token        string `yaml:"token"         mapstructure:"token"`
+	TokenFile    string `yaml:"token_file"    mapstructure:"token_file"`
+	TokenCommand string `yaml:"token_command" mapstructure:"token_command"`
+	// Branch or tag analyzed in every repository instead of its default branch and the per-repository branch
+	Ref string `yaml:"ref" mapstructure:"ref"`
+	// Owner entries only: leave archived repositories out and keep forks, empty repositories are always left out
+	SkipArchived bool `yaml:"skip_archived" mapstructure:"skip_archived"`
+	IncludeForks bool `yaml:"include_forks" mapstructure:"include_forks"`
 }
 
 // RepositoryConfig represents a repository to analyze
 type RepositoryConfig struct {
-	URL    string   `yaml:"url"              mapstructure:"url"`
-	ID     int      `yaml:"id,omitempty"     mapstructure:"id"`
-	Name   string   `yaml:"name,omitempty"   mapstructure:"name"`
-	Branch string   `yaml:"branch,omitempty" mapstructure:"branch"`
-	Paths  []string `yaml:"paths,omitempty"  mapstructure:"paths"`
+	URL  string `yaml:"url"            mapstructure:"url"`
+	ID   int    `yaml:"id,omitempty"   mapstructure:"id"`
+	Name string `yaml:"name,omitempty" mapstructure:"name"`
+	// Branch or tag to analyze instead of the default branch, on group entries for every project below
+	Branch string `yaml:"branch,omitempty" mapstructure:"branch"`
+	// Subdirectories scanned for dependency files ("services/api", "services/*") and directory globs skipped
+	// ("examples", "docs/samples"), on group entries for every project below
+	Paths        []string `yaml:"paths,omitempty"         mapstructure:"paths"`
+	ExcludePaths []string `yaml:"exclude_paths,omitempty" mapstructure:"exclude_paths"`
+
+	// Group entries only: maximum subgroup depth (0 = unlimited) and subgroup or project path globs
+	// relative to the group, e.g. include "platform/*" but exclude "platform/sandbox/*"
+	SubgroupDepth    int      `yaml:"subgroup_depth,omitempty"    mapstructure:"subgroup_depth"`
+	IncludeSubgroups []string `yaml:"include_subgroups,omitempty" mapstructure:"include_subgroups"`
+	ExcludeSubgroups []string `yaml:"exclude_subgroups,omitempty" mapstructure:"exclude_subgroups"`
+	// Group entries only: GitLab topics a project must have one of and a glob its name or path must match
+	Topics      []string `yaml:"topics,omitempty"       mapstructure:"topics"`
+	NamePattern string   `yaml:"name_pattern,omitempty" mapstructure:"name_pattern"`
+}
+
+// UsesGitLab reports whether repositories are read from GitLab, the default provider
+func (c Config) UsesGitLab() bool {
+	return c.Provider == "" || c.Provider == GitLabProvider
+}
+
+// SourceBaseURL returns the base URL of the source code host the repositories are read from, empty for
+// directories on disk
+func (c Config) SourceBaseURL() string {
+	switch {
+	case c.UsesGitLab():
+		return c.GitLab.BaseURL
+	case c.Provider == GiteaProvider:
+		return c.Gitea.BaseURL
+	default:
+		return ""
+	}
+}
+
+// HasGroupFilter reports whether the entry limits how its group expands
+func (r RepositoryConfig) HasGroupFilter() bool {
+	return r.SubgroupDepth > 0 || len(r.IncludeSubgroups) > 0 || len(r.ExcludeSubgroups) > 0 ||
+		len(r.Topics) > 0 || r.NamePattern != ""
 }
 
 // InternalConfig represents internal dependency classification settings
 type InternalConfig struct {
 	Domains  []string `yaml:"domains"  mapstructure:"domains"`
 	Patterns []string `yaml:"patterns" mapstructure:"patterns"`
+	// Classify dependencies built by any project of the configured GitLab groups as internal
+	FromGroups bool `yaml:"from_groups" mapstructure:"from_groups"`
+}
+
+// ExcludeConfig represents dependencies left out of the analysis
+type ExcludeConfig struct {
+	// Dependency name globs ("@types/*", "types-*") or regular expressions enclosed in slashes ("/^test-/")
+	Dependencies []string `yaml:"dependencies" mapstructure:"dependencies"`
 }
 
 // OutputConfig represents output settings
 type OutputConfig struct {
-	HTMLFile string `yaml:"html_file" mapstructure:"html_file"`
-	Title    string `yaml:"title"     mapstructure:"title"`
+	// Reports to write: "html", "csv", "json", "xlsx", "dot", "mermaid", "sarif", "markdown"
+	Formats     []string `yaml:"formats"      mapstructure:"formats"`
+	HTMLFile    string   `yaml:"html_file"    mapstructure:"html_file"`
+	CSVFile     string   `yaml:"csv_file"     mapstructure:"csv_file"`
+	XLSXFile    string   `yaml:"xlsx_file"    mapstructure:"xlsx_file"`
+	DOTFile     string   `yaml:"dot_file"     mapstructure:"dot_file"`
+	MermaidFile string   `yaml:"mermaid_file" mapstructure:"mermaid_file"`
+	// SARIF log of vulnerable and outdated dependencies and policy violations for code scanning dashboards
+	SARIFFile string `yaml:"sarif_file" mapstructure:"sarif_file"`
+	// Markdown summary of the dependencies and policy violations, for wikis and merge request comments
+	MarkdownFile string `yaml:"markdown_file" mapstructure:"markdown_file"`
+	// Only show internal dependencies in the dot and mermaid graphs
+	GraphInternalOnly bool `yaml:"graph_internal_only" mapstructure:"graph_internal_only"`
+	// Versioned JSON report, setting it also writes the json format
+	JSONFile string   `yaml:"json_file" mapstructure:"json_file"`
+	Title    string   `yaml:"title"     mapstructure:"title"`
+	SortBy   string   `yaml:"sort_by"   mapstructure:"sort_by"`  // "repository" or "health"
+	Matrices []string `yaml:"matrices"  mapstructure:"matrices"` // "combined", "internal", "external"
+	// "include" lists transitive dependencies next to direct ones, "collapse" leaves them out of the reports
+	Transitive string `yaml:"transitive" mapstructure:"transitive"`
+	// Replace project, repository and path names with pseudonyms, dependency names stay intact
+	Anonymize bool `yaml:"anonymize" mapstructure:"anonymize"`
+	// Key for stable pseudonyms across reports, empty uses a random key per run
+	AnonymizeSalt string `yaml:"anonymize_salt" mapstructure:"anonymize_salt"`
+	// YAML file of dependency notes, owners and replacements merged into the reports, empty to skip
+	AnnotationsFile string `yaml:"annotations_file" mapstructure:"annotations_file"`
+	// Go template rendering the HTML report instead of the embedded one (see `di-matrix-cli template`), empty for
+	// the embedded template
+	TemplateFile string `yaml:"template_file" mapstructure:"template_file"`
+	// Directory copied to assets/ next to the HTML report, its stylesheets theme the embedded template
+	AssetsDir string `yaml:"assets_dir" mapstructure:"assets_dir"`
+	// Matrices with more project × dependency cells render with virtual scrolling instead of a static table, 0 never
+	VirtualizeCells int `yaml:"virtualize_cells" mapstructure:"virtualize_cells"`
+	// What matrix cells show and which version they are compared against to be marked outdated
+	MatrixMode MatrixModeConfig `yaml:"matrix_mode" mapstructure:"matrix_mode"`
+	// Push the reports to GitLab after every complete analysis, so they are always at the same URL
+	Publish PublishConfig `yaml:"publish" mapstructure:"publish"`
+	// Object storage the reports of every complete analysis are archived to under timestamped keys:
+	// "s3://bucket/prefix", "gs://bucket/prefix" or "azblob://container/prefix", empty uploads nothing
+	Upload string `yaml:"upload" mapstructure:"upload"`
+}
+
+// PublishConfig represents publishing the reports through the GitLab API, with the gitlab credentials
+type PublishConfig struct {
+	Pages PagesPublishConfig `yaml:"pages" mapstructure:"pages"`
+	Wiki  WikiPublishConfig  `yaml:"wiki"  mapstructure:"wiki"`
+}
+
+// PagesPublishConfig represents committing the HTML report to the branch a GitLab Pages pipeline deploys
+type PagesPublishConfig struct {
+	// Project URL or path ("platform/dependency-matrix"), empty publishes nothing
+	Project   string `yaml:"project"   mapstructure:"project"`
+	Branch    string `yaml:"branch"    mapstructure:"branch"`    // Created from the default branch when missing
+	Directory string `yaml:"directory" mapstructure:"directory"` // The report is committed as <directory>/index.html
+}
+
+// WikiPublishConfig represents uploading the Markdown report to a project wiki page
+type WikiPublishConfig struct {
+	// Project URL or path whose wiki holds the page, empty publishes nothing
+	Project string `yaml:"project" mapstructure:"project"`
+	Page    string `yaml:"page"    mapstructure:"page"` // Page slug ("reports/dependency-matrix"), created when missing
+}
+
+// MatrixModeConfig represents matrix cell semantics
+type MatrixModeConfig struct {
+	// "resolved" (lockfile version), "constraint" (declared constraint) or "both"
+	Cell string `yaml:"cell" mapstructure:"cell"`
+	// "newest" (in use or registry latest), "portfolio" (newest in use) or "registry" (registry latest)
+	Compare string `yaml:"compare" mapstructure:"compare"`
+}
+
+// ScannerConfig represents dependency file discovery limits
+type ScannerConfig struct {
+	MaxDepth   int      `yaml:"max_depth"   mapstructure:"max_depth"`   // 0 means unlimited
+	IgnoreDirs []string `yaml:"ignore_dirs" mapstructure:"ignore_dirs"` // Directory globs to skip
+	// Skip vendor/, node_modules/, .venv/, dist/ and bower_components/
+	SkipVendored bool `yaml:"skip_vendored" mapstructure:"skip_vendored"`
+	// Treat Dockerfile and compose build context directories as project boundaries
+	DetectServices bool `yaml:"detect_services" mapstructure:"detect_services"`
+	// Analyze git submodules hosted on the same GitLab as separate repositories
+	ResolveSubmodules bool `yaml:"resolve_submodules" mapstructure:"resolve_submodules"`
+}
+
+// ManifestConfig maps a custom manifest filename to a language and optionally a built-in parser
+type ManifestConfig struct {
+	Filename string `yaml:"filename"         mapstructure:"filename"`
+	Language string `yaml:"language"         mapstructure:"language"`
+	Parser   string `yaml:"parser,omitempty" mapstructure:"parser"` // Built-in manifest name or "none"
+}
+
+// ConcurrencyConfig represents worker pool sizes
+type ConcurrencyConfig struct {
+	// Repositories scanned concurrently
+	RepositoryWorkers int `yaml:"repository_workers" mapstructure:"repository_workers"`
+	// Manifest downloads per repository
+	FileFetcherWorkers int `yaml:"file_fetcher_workers" mapstructure:"file_fetcher_workers"`
+	// Projects whose dependency files are parsed concurrently
+	ParserWorkers int `yaml:"parser_workers" mapstructure:"parser_workers"`
+	// Dependency files parsed concurrently within a project
+	FileParserWorkers int `yaml:"file_parser_workers" mapstructure:"file_parser_workers"`
+	// Pages of a GitLab group listing fetched concurrently
+	PaginationWorkers int `yaml:"pagination_workers" mapstructure:"pagination_workers"`
+	// GitLab API requests in flight across all workers, 0 means unlimited
+	MaxConcurrentRequests int `yaml:"max_concurrent_requests" mapstructure:"max_concurrent_requests"`
+}
+
+// MavenConfig represents Maven version resolution settings
+type MavenConfig struct {
+	// Repositories to fetch parent POMs and imported BOMs from when they are not in the analyzed repositories,
+	// an empty list keeps resolution offline
+	RemoteRepositories []string `yaml:"remote_repositories" mapstructure:"remote_repositories"`
+}
+
+// RegistryConfig represents the package registries the latest versions of external dependencies are looked up in,
+// Maven artifacts are looked up in maven.remote_repositories
+type RegistryConfig struct {
+	Enabled       bool   `yaml:"enabled"         mapstructure:"enabled"`
+	GoProxy       string `yaml:"go_proxy"        mapstructure:"go_proxy"`        // Go module proxy
+	NPM           string `yaml:"npm"             mapstructure:"npm"`             // npm registry
+	PyPI          string `yaml:"pypi"            mapstructure:"pypi"`            // PyPI-compatible index with the JSON API
+	Workers       int    `yaml:"workers"         mapstructure:"workers"`         // Concurrent lookups
+	CacheTTLHours int    `yaml:"cache_ttl_hours" mapstructure:"cache_ttl_hours"` // How long looked-up versions are reused
+}
+
+// EOLConfig represents end-of-life checks of frameworks and runtimes against endoflife.date
+type EOLConfig struct {
+	Enabled       bool               `yaml:"enabled"         mapstructure:"enabled"`
+	BaseURL       string             `yaml:"base_url"        mapstructure:"base_url"`        // endoflife.date API
+	Products      []EOLProductConfig `yaml:"products"        mapstructure:"products"`        // Mappings before the built-in ones
+	CacheTTLHours int                `yaml:"cache_ttl_hours" mapstructure:"cache_ttl_hours"` // How long cycles are reused
+}
+
+// EOLProductConfig maps dependencies to the endoflife.date product whose release cycles they follow
+type EOLProductConfig struct {
+	Dependency string `yaml:"dependency" mapstructure:"dependency"` // Name glob or /regex/, "com.acme:platform-*"
+	Product    string `yaml:"product"    mapstructure:"product"`    // endoflife.date product, "spring-boot"
+}
+
+// OSVConfig represents vulnerability scanning against the OSV.dev advisory database
+type OSVConfig struct {
+	Enabled       bool   `yaml:"enabled"         mapstructure:"enabled"`         // Same as --vulns
+	BaseURL       string `yaml:"base_url"        mapstructure:"base_url"`        // OSV API
+	Workers       int    `yaml:"workers"         mapstructure:"workers"`         // Concurrent advisory downloads
+	CacheTTLHours int    `yaml:"cache_ttl_hours" mapstructure:"cache_ttl_hours"` // How long results are reused
+}
+
+// CacheConfig represents the on-disk cache of downloaded enrichment data, GitLab responses and parse results
+type CacheConfig struct {
+	Dir          string `yaml:"dir"           mapstructure:"dir"`           // Empty uses the per-user cache directory
+	ParseResults bool   `yaml:"parse_results" mapstructure:"parse_results"` // Reuse parse results of identical files across runs
+	// Reuse repository trees and file contents of commits already analyzed
+	GitLab bool `yaml:"gitlab" mapstructure:"gitlab"`
+}
+
+// Directory returns the cache root directory, the per-user cache directory unless configured
+func (c CacheConfig) Directory() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return cache.DefaultDir()
+}
+
+// RetryConfig represents retry policies per operation class. Authentication failures are never retried.
+type RetryConfig struct {
+	Metadata RetryPolicyConfig `yaml:"metadata" mapstructure:"metadata"` // GitLab users, groups and projects
+	Tree     RetryPolicyConfig `yaml:"tree"     mapstructure:"tree"`     // GitLab repository tree listing
+	Content  RetryPolicyConfig `yaml:"content"  mapstructure:"content"`  // GitLab file content downloads
+	Registry RetryPolicyConfig `yaml:"registry" mapstructure:"registry"` // Registry and advisory lookups (POMs, versions, OSV)
+}
+
+// RetryPolicyConfig represents how one operation class is retried
+type RetryPolicyConfig struct {
+	Retries        int `yaml:"retries"         mapstructure:"retries"`         // Attempts after the first one
+	BackoffMs      int `yaml:"backoff_ms"      mapstructure:"backoff_ms"`      // First wait, doubled per retry
+	TimeoutSeconds int `yaml:"timeout_seconds" mapstructure:"timeout_seconds"` // Per attempt, 0 = unbounded
+}
+
+// Policy converts the settings to a retry policy
+func (r RetryPolicyConfig) Policy() retry.Policy {
+	return retry.Policy{
+		Retries: r.Retries,
+		Backoff: time.Duration(r.BackoffMs) * time.Millisecond,
+		Timeout: time.Duration(r.TimeoutSeconds) * time.Second,
+	}
+}
+
+// ServeConfig represents the scheduled analyses of the serve command
+type ServeConfig struct {
+	// Cron expression rerunning the analysis ("0 6 * * *", "@daily", "@every 6h"), empty only runs on demand
+	Schedule string `yaml:"schedule" mapstructure:"schedule"`
+	// Analyses whose reports are kept in the history next to the latest one, 0 keeps none
+	KeepReports int `yaml:"keep_reports" mapstructure:"keep_reports"`
+}
+
+// NotificationsConfig represents the Slack and Microsoft Teams incoming webhooks analysis summaries are posted to
+type NotificationsConfig struct {
+	// Incoming webhook URLs, secrets best set through SLACK_WEBHOOK_URL and TEAMS_WEBHOOK_URL, empty posts nothing
+	SlackWebhookURL string `yaml:"slack_webhook_url" mapstructure:"slack_webhook_url"`
+	TeamsWebhookURL string `yaml:"teams_webhook_url" mapstructure:"teams_webhook_url"`
+	// Report link of the notifications, the uploaded HTML report when empty
+	ReportURL string `yaml:"report_url" mapstructure:"report_url"`
+	// New outdated dependencies and policy violations listed, the rest is counted
+	MaxItems int `yaml:"max_items" mapstructure:"max_items"`
+}
+
+// DependencyTrackConfig represents uploading a CycloneDX SBOM per analyzed project to OWASP Dependency-Track,
+// which creates a project named after each analyzed project on its first upload
+type DependencyTrackConfig struct {
+	// API server ("https://dtrack.example.com"), empty uploads nothing
+	URL string `yaml:"url" mapstructure:"url"`
+	// Key of a team with the BOM_UPLOAD and PROJECT_CREATION_UPLOAD permissions, best set through
+	// DEPENDENCY_TRACK_API_KEY
+	APIKey string `yaml:"api_key" mapstructure:"api_key"`
+	// Version of the Dependency-Track projects, every analysis replaces the SBOM of this version
+	ProjectVersion string `yaml:"project_version" mapstructure:"project_version"`
+}
+
+// MetricsConfig represents pushing analysis metrics to a Prometheus Pushgateway from the analyze command
+type MetricsConfig struct {
+	// Pushgateway receiving the metrics of every analysis ("http://pushgateway:9091"), empty pushes nothing
+	PushgatewayURL string `yaml:"pushgateway_url" mapstructure:"pushgateway_url"`
+	// Job the metrics are grouped under, together with the analyzed language
+	Job string `yaml:"job" mapstructure:"job"`
 }
 
 // TimeoutConfig represents timeout configuration
 type TimeoutConfig struct {
 	AnalysisTimeoutMinutes int `yaml:"analysis_timeout_minutes" mapstructure:"analysis_timeout_minutes"`
+	// Scan time of a single repository, slower repositories are recorded as errors, 0 means no limit
+	RepositoryTimeoutMinutes int `yaml:"repository_timeout_minutes" mapstructure:"repository_timeout_minutes"`
+}
+
+// PolicyConfig represents policy enforcement settings
+type PolicyConfig struct {
+	Pinning PinningPolicyConfig `yaml:"pinning" mapstructure:"pinning"`
+	// Whether pre-release versions may count as the highest version: never, in_use or always
+	Prereleases string `yaml:"prereleases" mapstructure:"prereleases"`
+	// Dependencies that must not be used, "name" or "name@range" with glob or /regex/ names ("lodash@<4.17.21")
+	Denylist []string `yaml:"denylist" mapstructure:"denylist"`
+	// External dependencies that may be used, in the denylist syntax; empty allows every dependency
+	Allowlist []string `yaml:"allowlist" mapstructure:"allowlist"`
+	// Exit with a distinct code when violations are found, they are only reported otherwise
+	FailOnViolation bool `yaml:"fail_on_violation" mapstructure:"fail_on_violation"`
+}
+
+// PinningPolicyConfig represents the reproducible builds policy
+type PinningPolicyConfig struct {
+	RequireLockfile bool `yaml:"require_lockfile" mapstructure:"require_lockfile"`
+	ForbidFloating  bool `yaml:"forbid_floating"  mapstructure:"forbid_floating"`
+}
+
+// HealthConfig represents per-project health score settings
+type HealthConfig struct {
+	Weights HealthWeightsConfig `yaml:"weights" mapstructure:"weights"`
+}
+
+// HealthWeightsConfig represents the weight of each health score component
+type HealthWeightsConfig struct {
+	Drift           float64 `yaml:"drift"           mapstructure:"drift"`
+	Vulnerabilities float64 `yaml:"vulnerabilities" mapstructure:"vulnerabilities"`
+	Deprecated      float64 `yaml:"deprecated"      mapstructure:"deprecated"`
+	Pinning         float64 `yaml:"pinning"         mapstructure:"pinning"`
+	Lockfile        float64 `yaml:"lockfile"        mapstructure:"lockfile"`
+}
+
+// Names of the built-in source providers
+const (
+	GitLabProvider = "gitlab" // Default
+	GiteaProvider  = "gitea"  // Gitea and Forgejo
+	LocalProvider  = "local"  // Directories on disk
+)
+
+// GitLab token types, by the header the token is sent in
+const (
+	PrivateToken = "private" // Personal, project or group access token, default
+	JobToken     = "job"     // CI_JOB_TOKEN of a running GitLab CI job
+	OAuthToken   = "oauth"   // OAuth2 access token
+)
+
+// RepositoriesEnv lists repository or group URLs separated by commas or whitespace.
+// When set it replaces the configured repositories and allows running without a config file.
+const RepositoriesEnv = "DI_MATRIX_REPOSITORIES"
+
+// HasEnvRepositories reports whether repositories are configured through the environment
+func HasEnvRepositories() bool {
+	return strings.TrimSpace(os.Getenv(RepositoriesEnv)) != ""
 }
 
-// LoadConfig loads configuration from file and environment variables
+// LoadConfig loads configuration from file and environment variables.
+// An empty path loads the environment only, which requires DI_MATRIX_REPOSITORIES.
 func LoadConfig(configPath string) (*Config, error) {
-	if configPath == "" {
-		return nil, fmt.Errorf("config path is required")
+	return LoadConfigWithRepositories(configPath, nil)
+}
+
+// LoadConfigWithRepositories loads configuration like LoadConfig, repositories replace the configured
+// and environment ones unless nil. An empty path then loads the environment only.
+func LoadConfigWithRepositories(configPath string, repositories []RepositoryConfig) (*Config, error) {
+	if configPath == "" && !HasEnvRepositories() && repositories == nil {
+		return nil, fmt.Errorf("config path is required (or set %s to configure through the environment)",
+			RepositoriesEnv)
+	}
+
+	return load(configPath, func(config *Config) {
+		switch {
+		case repositories != nil:
+			config.Repositories = repositories
+		case HasEnvRepositories():
+			config.Repositories = envRepositories(os.Getenv(RepositoriesEnv))
+		}
+	})
+}
+
+// LoadLocalConfig loads configuration for analyzing directories on disk: the directories replace the configured
+// repositories, the local provider replaces GitLab and nothing else is fetched over the network (offline mode).
+// The config file is optional.
+func LoadLocalConfig(configPath string, dirs []string) (*Config, error) {
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("at least one directory is required")
 	}
 
+	return load(configPath, func(config *Config) {
+		config.Provider = LocalProvider
+		config.Offline = true
+		config.Repositories = make([]RepositoryConfig, 0, len(dirs))
+		for _, dir := range dirs {
+			config.Repositories = append(config.Repositories, RepositoryConfig{URL: dir})
+		}
+	})
+}
+
+// load reads the config file and the environment, applies override and validates the result
+func load(configPath string, override func(config *Config)) (*Config, error) {
 	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file does not exist: %s", configPath)
+	if configPath != "" {
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			return nil, fmt.Errorf("config file does not exist: %s", configPath)
+		}
 	}
 
 	// Create a new Viper instance to avoid data races in concurrent tests
 	v := viper.New()
-	v.SetConfigFile(configPath)
 	v.SetConfigType("yaml")
 
 	// Set default values
@@ -75,15 +517,34 @@ func LoadConfig(configPath string) (*Config, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Bind environment variables to config keys
+	_ = v.BindEnv("provider", "DI_MATRIX_PROVIDER")
 	_ = v.BindEnv("gitlab.base_url", "GITLAB_BASE_URL")
 	_ = v.BindEnv("gitlab.token", "GITLAB_TOKEN")
+	_ = v.BindEnv("gitlab.token_type", "GITLAB_TOKEN_TYPE")
+	_ = v.BindEnv("gitlab.token_file", "GITLAB_TOKEN_FILE")
+	_ = v.BindEnv("gitlab.token_command", "GITLAB_TOKEN_COMMAND")
+	_ = v.BindEnv("gitlab.ref", "GITLAB_REF")
+	_ = v.BindEnv("gitea.base_url", "GITEA_BASE_URL")
+	_ = v.BindEnv("gitea.token", "GITEA_TOKEN")
+	_ = v.BindEnv("gitea.token_file", "GITEA_TOKEN_FILE")
+	_ = v.BindEnv("gitea.token_command", "GITEA_TOKEN_COMMAND")
 	_ = v.BindEnv("output.html_file", "OUTPUT_HTML_FILE")
 	_ = v.BindEnv("output.title", "OUTPUT_TITLE")
+	_ = v.BindEnv("notifications.slack_webhook_url", "SLACK_WEBHOOK_URL")
+	_ = v.BindEnv("notifications.teams_webhook_url", "TEAMS_WEBHOOK_URL")
+	_ = v.BindEnv("dependency_track.url", "DEPENDENCY_TRACK_URL")
+	_ = v.BindEnv("dependency_track.api_key", "DEPENDENCY_TRACK_API_KEY")
 	_ = v.BindEnv("timeout.analysis_timeout_minutes", "ANALYSIS_TIMEOUT_MINUTES")
+	_ = v.BindEnv("timeout.repository_timeout_minutes", "REPOSITORY_TIMEOUT_MINUTES")
+	_ = v.BindEnv("internal.domains", "DI_MATRIX_INTERNAL_DOMAINS")
+	_ = v.BindEnv("internal.patterns", "DI_MATRIX_INTERNAL_PATTERNS")
 
 	// Read config file
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
 	}
 
 	// Unmarshal into struct
@@ -91,6 +552,9 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	applyCIJob(&config.GitLab, v.InConfig("gitlab.base_url") || os.Getenv("GITLAB_BASE_URL") != "")
+
+	override(&config)
 
 	// Validate configuration
 	if err := validateConfig(config); err != nil {
@@ -100,11 +564,178 @@ func LoadConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
+// Defaults returns the built-in configuration without reading a file or the environment.
+// GitLab credentials and repositories are left for the caller to fill in.
+func Defaults() (*Config, error) {
+	v := viper.New()
+	setDefaultValues(v)
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &config, nil
+}
+
+// tokenCommandTimeout bounds gitlab.token_command, e.g. a secret manager waiting for an interactive login
+const tokenCommandTimeout = 30 * time.Second
+
+// ResolveToken returns the GitLab token, read from token_file or the output of token_command unless token
+// (or GITLAB_TOKEN) is set. The file and the command are only read when a client is created, so loading
+// the configuration never runs the command.
+func (c GitLabConfig) ResolveToken() (string, error) {
+	return resolveToken("gitlab", c.Token, c.TokenFile, c.TokenCommand)
+}
+
+// InstanceToken returns the token of the further instance at index i like ResolveToken
+func (c GitLabConfig) InstanceToken(i int) (string, error) {
+	instance := c.Instances[i]
+	return resolveToken(fmt.Sprintf("gitlab.instances[%d]", i), instance.Token, instance.TokenFile, instance.TokenCommand)
+}
+
+// ResolveToken returns the Gitea token, read from token_file or the output of token_command unless token
+// (or GITEA_TOKEN) is set
+func (c GiteaConfig) ResolveToken() (string, error) {
+	return resolveToken("gitea", c.Token, c.TokenFile, c.TokenCommand)
+}
+
+// resolveToken returns the token of the settings at key: token itself when set, otherwise the content of the
+// token file or the output of the token command
+func resolveToken(key, token, tokenFile, tokenCommand string) (string, error) {
+	switch {
+	case token != "":
+		return token, nil
+	case tokenFile != "":
+		content, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s.token_file: %w", key, err)
+		}
+		token = strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("%s.token_file %s is empty", key, tokenFile)
+		}
+	case tokenCommand != "":
+		ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		command := exec.CommandContext(ctx, "sh", "-c", tokenCommand)
+		command.Stderr = &stderr
+		output, err := command.Output()
+		if err != nil {
+			// The output may hold part of the token, stderr is the command's own diagnostics
+			return "", fmt.Errorf("%s.token_command failed: %w: %s", key, err, strings.TrimSpace(stderr.String()))
+		}
+		token = strings.TrimSpace(string(output))
+		if token == "" {
+			return "", fmt.Errorf("%s.token_command printed no token", key)
+		}
+	}
+	return token, nil
+}
+
+// applyCIJob fills in the token and, unless a base URL is set explicitly, the instance of the running
+// GitLab CI job when a job token is configured without a token source. Job tokens are valid on the instance
+// of their job only.
+func applyCIJob(gitlab *GitLabConfig, explicitBaseURL bool) {
+	for i := range gitlab.Instances {
+		instance := &gitlab.Instances[i]
+		if instance.TokenType == JobToken && !hasToken(instance.Token, instance.TokenFile, instance.TokenCommand) {
+			instance.Token = os.Getenv("CI_JOB_TOKEN")
+		}
+	}
+
+	if gitlab.TokenType != JobToken {
+		return
+	}
+	if !hasToken(gitlab.Token, gitlab.TokenFile, gitlab.TokenCommand) {
+		gitlab.Token = os.Getenv("CI_JOB_TOKEN")
+	}
+	if serverURL := os.Getenv("CI_SERVER_URL"); serverURL != "" && !explicitBaseURL {
+		gitlab.BaseURL = serverURL
+	}
+}
+
+// hasToken reports whether any token source is set
+func hasToken(token, tokenFile, tokenCommand string) bool {
+	return token != "" || tokenFile != "" || tokenCommand != ""
+}
+
+// envRepositories splits a DI_MATRIX_REPOSITORIES value into repository entries
+func envRepositories(value string) []RepositoryConfig {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	repositories := make([]RepositoryConfig, 0, len(fields))
+	for _, repositoryURL := range fields {
+		repositories = append(repositories, RepositoryConfig{URL: repositoryURL})
+	}
+	return repositories
+}
+
+// ReadRepositoryList parses a repository list with one project or group URL per line,
+// blank lines and lines starting with # are skipped
+func ReadRepositoryList(r io.Reader) ([]RepositoryConfig, error) {
+	repositories := []RepositoryConfig{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" || strings.HasPrefix(value, "#") {
+			continue
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("line %d: %q is not a project or group URL", line, value)
+		}
+		repositories = append(repositories, RepositoryConfig{URL: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	return repositories, nil
+}
+
 // setDefaultValues sets default configuration values
 func setDefaultValues(v *viper.Viper) {
+	// Source provider defaults
+	v.SetDefault("provider", GitLabProvider)
+	v.SetDefault("gitlab.base_url", "https://gitlab.com")
+	v.SetDefault("gitlab.token_type", PrivateToken)
+	v.SetDefault("gitlab.skip_archived", true)
+	v.SetDefault("gitlab.include_forks", false)
+	v.SetDefault("gitea.skip_archived", true)
+	v.SetDefault("gitea.include_forks", false)
+
 	// Output defaults
+	v.SetDefault("output.formats", []string{"html"})
 	v.SetDefault("output.html_file", "dependency-matrix.html")
+	v.SetDefault("output.csv_file", "dependency-matrix.csv")
+	v.SetDefault("output.xlsx_file", "dependency-matrix.xlsx")
+	v.SetDefault("output.dot_file", "dependency-graph.dot")
+	v.SetDefault("output.mermaid_file", "dependency-graph.mmd")
+	v.SetDefault("output.sarif_file", "dependency-matrix.sarif")
+	v.SetDefault("output.markdown_file", "dependency-matrix.md")
+	v.SetDefault("output.graph_internal_only", false)
+	v.SetDefault("output.json_file", "")
 	v.SetDefault("output.title", "Dependency Matrix Report")
+	v.SetDefault("output.sort_by", "repository")
+	v.SetDefault("output.transitive", "include")
+	v.SetDefault("output.matrix_mode.cell", "resolved")
+	v.SetDefault("output.matrix_mode.compare", "newest")
+	v.SetDefault("output.matrices", []string{"combined"})
+	v.SetDefault("output.anonymize", false)
+	v.SetDefault("output.anonymize_salt", "")
+	v.SetDefault("output.annotations_file", "")
+	v.SetDefault("output.template_file", "")
+	v.SetDefault("output.assets_dir", "")
+	v.SetDefault("output.virtualize_cells", 50000)
+	v.SetDefault("output.publish.pages.project", "")
+	v.SetDefault("output.publish.pages.branch", "pages")
+	v.SetDefault("output.publish.pages.directory", "public")
+	v.SetDefault("output.publish.wiki.project", "")
+	v.SetDefault("output.publish.wiki.page", "dependency-matrix")
+	v.SetDefault("output.upload", "")
 
 	// Repository defaults
 	v.SetDefault("repositories", []RepositoryConfig{})
@@ -112,46 +743,272 @@ func setDefaultValues(v *viper.Viper) {
 	// Internal classification defaults
 	v.SetDefault("internal.domains", []string{})
 	v.SetDefault("internal.patterns", []string{})
+	v.SetDefault("internal.from_groups", false)
+
+	// Exclusion defaults (every dependency analyzed)
+	v.SetDefault("exclude.dependencies", []string{})
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
 
 	// Concurrency defaults
-	v.SetDefault("concurrency.repository_workers", 4)
+	v.SetDefault("concurrency.repository_workers", 10)
 	v.SetDefault("concurrency.file_fetcher_workers", 8)
-	v.SetDefault("concurrency.parser_workers", 6)
-	v.SetDefault("concurrency.generator_workers", 2)
-	v.SetDefault("concurrency.queue_buffer_size", 50)
-	v.SetDefault("concurrency.max_concurrent_repos", 10)
-	v.SetDefault("concurrency.max_concurrent_files", 20)
-	v.SetDefault("concurrency.max_concurrent_parsers", 15)
+	v.SetDefault("concurrency.max_concurrent_requests", 10)
+	v.SetDefault("concurrency.parser_workers", 5)
+	v.SetDefault("concurrency.file_parser_workers", 3)
+	v.SetDefault("concurrency.pagination_workers", 5)
 
 	// Timeout defaults (10 minutes as per user preference for console operations)
 	v.SetDefault("timeout.analysis_timeout_minutes", 10)
+	v.SetDefault("timeout.repository_timeout_minutes", 0)
+
+	// Scanner defaults (no depth limit, vendored trees skipped)
+	v.SetDefault("scanner.max_depth", 0)
+	v.SetDefault("scanner.ignore_dirs", []string{})
+	v.SetDefault("scanner.skip_vendored", true)
+	v.SetDefault("scanner.detect_services", false)
+	v.SetDefault("scanner.resolve_submodules", false)
+
+	// Maven defaults (parent POMs and BOMs fetched from Maven Central)
+	v.SetDefault("maven.remote_repositories", []string{"https://repo.maven.apache.org/maven2"})
+
+	// Registry defaults (latest versions from the public registries, looked up at most once a day)
+	v.SetDefault("registry.enabled", true)
+	v.SetDefault("registry.go_proxy", "https://proxy.golang.org")
+	v.SetDefault("registry.npm", "https://registry.npmjs.org")
+	v.SetDefault("registry.pypi", "https://pypi.org")
+	v.SetDefault("registry.workers", 8)
+	v.SetDefault("registry.cache_ttl_hours", 24)
+
+	// Vulnerability scanning defaults (off, enabled with --vulns)
+	v.SetDefault("osv.enabled", false)
+	v.SetDefault("osv.base_url", "https://api.osv.dev")
+	v.SetDefault("osv.workers", 8)
+	v.SetDefault("osv.cache_ttl_hours", 24)
+
+	// End-of-life defaults (release cycles from endoflife.date, looked up at most once a day)
+	v.SetDefault("eol.enabled", true)
+	v.SetDefault("eol.base_url", "https://endoflife.date")
+	v.SetDefault("eol.products", []EOLProductConfig{})
+	v.SetDefault("eol.cache_ttl_hours", 24)
+
+	// Network defaults (online, per-user cache directory)
+	v.SetDefault("offline", false)
+	v.SetDefault("include_dev", false)
+	v.SetDefault("cache.dir", "")
+	v.SetDefault("cache.parse_results", true)
+	v.SetDefault("cache.gitlab", true)
+
+	// Serve defaults (analysis on demand only)
+	v.SetDefault("serve.schedule", "")
+	v.SetDefault("serve.keep_reports", 10)
+
+	// Metrics defaults (nothing pushed)
+	v.SetDefault("metrics.pushgateway_url", "")
+	v.SetDefault("metrics.job", "di-matrix-cli")
+
+	// Notification defaults (nothing posted)
+	v.SetDefault("notifications.slack_webhook_url", "")
+	v.SetDefault("notifications.teams_webhook_url", "")
+	v.SetDefault("notifications.report_url", "")
+	v.SetDefault("notifications.max_items", 10)
+
+	// Dependency-Track defaults
+	v.SetDefault("dependency_track.url", "")
+	v.SetDefault("dependency_track.api_key", "")
+	v.SetDefault("dependency_track.project_version", "latest")
+
+	// Retry defaults (transient failures: network errors, timeouts, 429 and 5xx responses)
+	v.SetDefault("retry.metadata.retries", 3)
+	v.SetDefault("retry.metadata.backoff_ms", 500)
+	v.SetDefault("retry.metadata.timeout_seconds", 30)
+	v.SetDefault("retry.tree.retries", 3)
+	v.SetDefault("retry.tree.backoff_ms", 500)
+	v.SetDefault("retry.tree.timeout_seconds", 60)
+	v.SetDefault("retry.content.retries", 3)
+	v.SetDefault("retry.content.backoff_ms", 500)
+	v.SetDefault("retry.content.timeout_seconds", 30)
+	v.SetDefault("retry.registry.retries", 2)
+	v.SetDefault("retry.registry.backoff_ms", 500)
+	v.SetDefault("retry.registry.timeout_seconds", 15)
+
+	// Policy defaults (report only, nothing enforced)
+	v.SetDefault("policy.pinning.require_lockfile", false)
+	v.SetDefault("policy.pinning.forbid_floating", false)
+	v.SetDefault("policy.prereleases", "in_use")
+	v.SetDefault("policy.denylist", []string{})
+	v.SetDefault("policy.allowlist", []string{})
+	v.SetDefault("policy.fail_on_violation", true)
+
+	// Health score weights
+	v.SetDefault("health.weights.drift", 3)
+	v.SetDefault("health.weights.vulnerabilities", 4)
+	v.SetDefault("health.weights.deprecated", 2)
+	v.SetDefault("health.weights.pinning", 1)
+	v.SetDefault("health.weights.lockfile", 1)
 }
 
 // validateConfig validates the configuration
 func validateConfig(config Config) error {
-	if config.GitLab.BaseURL == "" {
-		return fmt.Errorf("gitlab.base_url is required")
+	if config.UsesGitLab() {
+		if config.GitLab.BaseURL == "" {
+			return fmt.Errorf("gitlab.base_url is required")
+		}
+
+		gitlab := config.GitLab
+		err := validateGitLabToken("gitlab", gitlab.TokenType, gitlab.Token, gitlab.TokenFile, gitlab.TokenCommand)
+		if err != nil {
+			return err
+		}
+
+		if err := validateGitLabInstances(config.GitLab); err != nil {
+			return err
+		}
 	}
 
-	if config.GitLab.Token == "" {
-		return fmt.Errorf("gitlab.token is required")
+	if config.Provider == GiteaProvider {
+		gitea := config.Gitea
+		parsed, err := url.Parse(gitea.BaseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("gitea.base_url must be an http or https URL, got %q", gitea.BaseURL)
+		}
+		if err := validateTokenSources("gitea", gitea.Token, gitea.TokenFile, gitea.TokenCommand); err != nil {
+			return err
+		}
+		if !hasToken(gitea.Token, gitea.TokenFile, gitea.TokenCommand) {
+			return fmt.Errorf("gitea.token is required (or gitea.token_file, gitea.token_command)")
+		}
 	}
 
 	if len(config.Repositories) == 0 {
 		return fmt.Errorf("at least one repository must be configured")
 	}
 
-	if config.Output.HTMLFile == "" {
-		return fmt.Errorf("output.html_file is required")
+	if err := validateOutputFormats(config.Output); err != nil {
+		return err
 	}
 
 	if config.Output.Title == "" {
 		return fmt.Errorf("output.title is required")
 	}
 
+	if config.Output.SortBy != "" && config.Output.SortBy != "repository" && config.Output.SortBy != "health" {
+		return fmt.Errorf("output.sort_by must be one of: repository, health")
+	}
+
+	if config.Output.Transitive != "" && config.Output.Transitive != "include" &&
+		config.Output.Transitive != "collapse" {
+		return fmt.Errorf("output.transitive must be one of: include, collapse")
+	}
+
+	if config.Output.VirtualizeCells < 0 {
+		return fmt.Errorf("output.virtualize_cells must not be negative")
+	}
+
+	if err := validateMatrixMode(config.Output.MatrixMode); err != nil {
+		return err
+	}
+
+	if err := validatePublish(config); err != nil {
+		return err
+	}
+
+	if config.Output.Upload != "" {
+		if _, err := upload.Parse(config.Output.Upload); err != nil {
+			return fmt.Errorf("output.upload: %w", err)
+		}
+	}
+
+	for _, matrix := range config.Output.Matrices {
+		if matrix != "combined" && matrix != "internal" && matrix != "external" {
+			return fmt.Errorf("output.matrices entries must be one of: combined, internal, external (got %q)", matrix)
+		}
+	}
+
+	if err := validatePolicy(config.Policy); err != nil {
+		return err
+	}
+
+	if _, err := exclude.Compile(config.Exclude.Dependencies); err != nil {
+		return fmt.Errorf("exclude.dependencies: %w", err)
+	}
+
+	workers := []struct {
+		key   string
+		value int
+	}{
+		{"repository_workers", config.Concurrency.RepositoryWorkers},
+		{"file_fetcher_workers", config.Concurrency.FileFetcherWorkers},
+		{"parser_workers", config.Concurrency.ParserWorkers},
+		{"file_parser_workers", config.Concurrency.FileParserWorkers},
+		{"pagination_workers", config.Concurrency.PaginationWorkers},
+	}
+	for _, pool := range workers {
+		if pool.value < 1 {
+			return fmt.Errorf("concurrency.%s must be at least 1", pool.key)
+		}
+	}
+
+	if config.Timeout.RepositoryTimeoutMinutes < 0 {
+		return fmt.Errorf("timeout.repository_timeout_minutes must not be negative")
+	}
+
+	if config.Concurrency.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("concurrency.max_concurrent_requests must not be negative")
+	}
+
+	if err := validateHealth(config.Health); err != nil {
+		return err
+	}
+
+	if err := validateRetry(config.Retry); err != nil {
+		return err
+	}
+
+	if err := validateRegistry(config.Registry); err != nil {
+		return err
+	}
+
+	if err := validateOSV(config.OSV); err != nil {
+		return err
+	}
+
+	if err := validateEOL(config.EOL); err != nil {
+		return err
+	}
+
+	if err := validateServe(config.Serve); err != nil {
+		return err
+	}
+
+	if err := validateMetrics(config.Metrics); err != nil {
+		return err
+	}
+
+	if err := validateNotifications(config.Notifications); err != nil {
+		return err
+	}
+
+	if err := validateDependencyTrack(config.DependencyTrack); err != nil {
+		return err
+	}
+
+	if config.Scanner.MaxDepth < 0 {
+		return fmt.Errorf("scanner.max_depth must not be negative")
+	}
+
+	for i, manifest := range config.Manifests {
+		if manifest.Filename == "" {
+			return fmt.Errorf("manifests[%d] must have a filename", i)
+		}
+		switch manifest.Language {
+		case "go", "nodejs", "java", "python", "rust", "ruby", "dotnet":
+		default:
+			return fmt.Errorf("manifests[%d] language must be one of: go, nodejs, java, python, rust, ruby, dotnet", i)
+		}
+	}
+
 	// Validate repositories
 	for i, repo := range config.Repositories {
 		if repo.URL == "" && repo.ID <= 0 {
@@ -160,7 +1017,337 @@ func validateConfig(config Config) error {
 		if repo.URL != "" && repo.ID > 0 {
 			return fmt.Errorf("repository[%d] should not have both url and id specified", i)
 		}
+		if err := validateSubgroups(repo); err != nil {
+			return fmt.Errorf("repository[%d] %w", i, err)
+		}
+		if err := validatePaths(repo); err != nil {
+			return fmt.Errorf("repository[%d] %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateSubgroups validates the group expansion settings of a repository entry
+func validateSubgroups(repo RepositoryConfig) error {
+	if repo.SubgroupDepth < 0 {
+		return fmt.Errorf("subgroup_depth must not be negative")
+	}
+	for _, glob := range append(append([]string{}, repo.IncludeSubgroups...), repo.ExcludeSubgroups...) {
+		if _, err := path.Match(strings.Trim(glob, "/"), ""); err != nil {
+			return fmt.Errorf("has an invalid subgroup glob %q: %w", glob, err)
+		}
+	}
+	if _, err := path.Match(repo.NamePattern, ""); err != nil {
+		return fmt.Errorf("has an invalid name_pattern %q: %w", repo.NamePattern, err)
+	}
+	return nil
+}
+
+// validatePaths validates the scanned and skipped subdirectories of a repository entry
+func validatePaths(repo RepositoryConfig) error {
+	for _, glob := range append(append([]string{}, repo.Paths...), repo.ExcludePaths...) {
+		if _, err := path.Match(strings.Trim(glob, "/"), ""); err != nil {
+			return fmt.Errorf("has an invalid path glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// validateMatrixMode validates the matrix cell semantics
+func validateMatrixMode(config MatrixModeConfig) error {
+	switch config.Cell {
+	case "", "resolved", "constraint", "both":
+	default:
+		return fmt.Errorf("output.matrix_mode.cell must be one of: resolved, constraint, both")
+	}
+	switch config.Compare {
+	case "", "newest", "portfolio", "registry":
+	default:
+		return fmt.Errorf("output.matrix_mode.compare must be one of: newest, portfolio, registry")
+	}
+	return nil
+}
+
+// validatePublish validates the GitLab Pages and wiki publishing settings
+func validatePublish(config Config) error {
+	pages, wiki := config.Output.Publish.Pages, config.Output.Publish.Wiki
+	if pages.Project == "" && wiki.Project == "" {
+		return nil
+	}
+	gitlab := config.GitLab
+	if gitlab.BaseURL == "" || !hasToken(gitlab.Token, gitlab.TokenFile, gitlab.TokenCommand) {
+		return fmt.Errorf("output.publish requires gitlab.base_url and gitlab.token")
+	}
+	if pages.Project != "" {
+		if pages.Branch == "" {
+			return fmt.Errorf("output.publish.pages.branch is required")
+		}
+		directory := path.Clean("/" + pages.Directory)
+		if path.IsAbs(pages.Directory) || directory != "/"+strings.Trim(pages.Directory, "/") {
+			return fmt.Errorf("output.publish.pages.directory must be a relative path inside the repository, got %q",
+				pages.Directory)
+		}
+	}
+	if wiki.Project != "" && strings.Trim(wiki.Page, "/") == "" {
+		return fmt.Errorf("output.publish.wiki.page is required")
+	}
+	return nil
+}
+
+// validatePolicy validates the policy settings
+func validatePolicy(config PolicyConfig) error {
+	switch config.Prereleases {
+	case "", "never", "in_use", "always":
+	default:
+		return fmt.Errorf("policy.prereleases must be one of: never, in_use, always")
+	}
+	if _, err := policy.NewListCheck(config.Denylist, config.Allowlist); err != nil {
+		return fmt.Errorf("policy.%w", err)
+	}
+	return nil
+}
+
+// validateRegistry validates the package registry settings
+func validateRegistry(registry RegistryConfig) error {
+	if !registry.Enabled {
+		return nil
+	}
+	if registry.Workers < 1 {
+		return fmt.Errorf("registry.workers must be at least 1")
+	}
+	if registry.CacheTTLHours < 0 {
+		return fmt.Errorf("registry.cache_ttl_hours must not be negative")
+	}
+	return nil
+}
+
+// validateOSV validates the vulnerability scanning settings, they are checked even when disabled
+// because --vulns enables scanning after the configuration is loaded
+func validateOSV(osv OSVConfig) error {
+	if osv.Workers < 1 {
+		return fmt.Errorf("osv.workers must be at least 1")
+	}
+	if osv.CacheTTLHours < 0 {
+		return fmt.Errorf("osv.cache_ttl_hours must not be negative")
+	}
+	return nil
+}
+
+// validateEOL validates the end-of-life settings and the product mappings
+func validateEOL(config EOLConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.CacheTTLHours < 0 {
+		return fmt.Errorf("eol.cache_ttl_hours must not be negative")
+	}
+	for _, mapping := range config.Products {
+		if _, err := eol.NewProduct(mapping.Dependency, mapping.Product); err != nil {
+			return fmt.Errorf("eol.products: %w", err)
+		}
 	}
+	return nil
+}
 
+// validateServe validates the schedule and history of the serve command
+func validateServe(serve ServeConfig) error {
+	if serve.Schedule != "" {
+		if _, err := schedule.Parse(serve.Schedule); err != nil {
+			return fmt.Errorf("serve.schedule: %w", err)
+		}
+	}
+	if serve.KeepReports < 0 {
+		return fmt.Errorf("serve.keep_reports must not be negative")
+	}
+	return nil
+}
+
+// validateMetrics validates the Pushgateway settings
+func validateMetrics(metrics MetricsConfig) error {
+	if metrics.PushgatewayURL != "" {
+		parsed, err := url.Parse(metrics.PushgatewayURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("metrics.pushgateway_url must be an http or https URL, got %q", metrics.PushgatewayURL)
+		}
+	}
+	if strings.TrimSpace(metrics.Job) == "" {
+		return fmt.Errorf("metrics.job must not be empty")
+	}
+	return nil
+}
+
+// validateNotifications validates the webhook URLs and the listed items of notifications
+func validateNotifications(notifications NotificationsConfig) error {
+	for name, webhook := range map[string]string{
+		"notifications.slack_webhook_url": notifications.SlackWebhookURL,
+		"notifications.teams_webhook_url": notifications.TeamsWebhookURL,
+	} {
+		if webhook == "" {
+			continue
+		}
+		// The URL is a secret, errors leave it out
+		parsed, err := url.Parse(webhook)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("%s must be an https URL", name)
+		}
+	}
+	if notifications.MaxItems < 0 {
+		return fmt.Errorf("notifications.max_items must not be negative")
+	}
+	return nil
+}
+
+// validateGitLabToken validates the token sources and token type of the GitLab settings at key
+func validateGitLabToken(key, tokenType, token, tokenFile, tokenCommand string) error {
+	switch tokenType {
+	case "", PrivateToken, JobToken, OAuthToken:
+	default:
+		return fmt.Errorf("%s.token_type must be one of: %s, %s, %s", key, PrivateToken, JobToken, OAuthToken)
+	}
+
+	if err := validateTokenSources(key, token, tokenFile, tokenCommand); err != nil {
+		return err
+	}
+	if !hasToken(token, tokenFile, tokenCommand) {
+		if tokenType == JobToken {
+			return fmt.Errorf("%s.token is required (CI_JOB_TOKEN is set in GitLab CI jobs only)", key)
+		}
+		return fmt.Errorf("%[1]s.token is required (or %[1]s.token_file, %[1]s.token_command)", key)
+	}
+	return nil
+}
+
+// validateTokenSources rejects settings reading the token from both a file and a command. A token set in the
+// file or the environment takes precedence over either, so neither is read then.
+func validateTokenSources(key, token, tokenFile, tokenCommand string) error {
+	if token == "" && tokenFile != "" && tokenCommand != "" {
+		return fmt.Errorf("only one of %[1]s.token_file and %[1]s.token_command may be set", key)
+	}
+	return nil
+}
+
+// validateGitLabInstances validates the further GitLab instances, each on a host of its own
+func validateGitLabInstances(gitlab GitLabConfig) error {
+	hosts := make(map[string]bool, len(gitlab.Instances)+1)
+	if parsed, err := url.Parse(gitlab.BaseURL); err == nil {
+		hosts[parsed.Host+strings.TrimSuffix(parsed.Path, "/")] = true
+	}
+
+	for i, instance := range gitlab.Instances {
+		key := fmt.Sprintf("gitlab.instances[%d]", i)
+		parsed, err := url.Parse(instance.BaseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s.base_url must be an http or https URL, got %q", key, instance.BaseURL)
+		}
+		endpoint := parsed.Host + strings.TrimSuffix(parsed.Path, "/")
+		if hosts[endpoint] {
+			return fmt.Errorf("%s.base_url %s is configured more than once", key, instance.BaseURL)
+		}
+		hosts[endpoint] = true
+
+		err = validateGitLabToken(key, instance.TokenType, instance.Token, instance.TokenFile, instance.TokenCommand)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDependencyTrack validates the Dependency-Track server settings when a server is set
+func validateDependencyTrack(dtrack DependencyTrackConfig) error {
+	if dtrack.URL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(dtrack.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("dependency_track.url must be an http or https URL, got %q", dtrack.URL)
+	}
+	if dtrack.APIKey == "" {
+		return fmt.Errorf("dependency_track.api_key is required with dependency_track.url (or DEPENDENCY_TRACK_API_KEY)")
+	}
+	if strings.TrimSpace(dtrack.ProjectVersion) == "" {
+		return fmt.Errorf("dependency_track.project_version must not be empty")
+	}
+	return nil
+}
+
+// validateRetry validates the retry policies
+func validateRetry(retry RetryConfig) error {
+	policies := map[string]RetryPolicyConfig{
+		"metadata": retry.Metadata,
+		"tree":     retry.Tree,
+		"content":  retry.Content,
+		"registry": retry.Registry,
+	}
+	for _, class := range []string{"metadata", "tree", "content", "registry"} {
+		policy := policies[class]
+		if policy.Retries < 0 || policy.BackoffMs < 0 || policy.TimeoutSeconds < 0 {
+			return fmt.Errorf("retry.%s settings must not be negative", class)
+		}
+	}
+	return nil
+}
+
+// validateHealth checks that no health score component has a negative weight
+func validateHealth(health HealthConfig) error {
+	weights := map[string]float64{
+		"drift":           health.Weights.Drift,
+		"vulnerabilities": health.Weights.Vulnerabilities,
+		"deprecated":      health.Weights.Deprecated,
+		"pinning":         health.Weights.Pinning,
+		"lockfile":        health.Weights.Lockfile,
+	}
+	for _, component := range []string{"drift", "vulnerabilities", "deprecated", "pinning", "lockfile"} {
+		if weights[component] < 0 {
+			return fmt.Errorf("health.weights.%s must not be negative", component)
+		}
+	}
+	return nil
+}
+
+// reportFormats lists the formats output.formats accepts
+const reportFormats = "html, csv, json, xlsx, dot, mermaid, sarif, markdown"
+
+// validateOutputFormats checks the report formats and that every selected format has an output path
+func validateOutputFormats(output OutputConfig) error {
+	if len(output.Formats) == 0 {
+		return fmt.Errorf("output.formats must list at least one of: %s", reportFormats)
+	}
+	for _, format := range output.Formats {
+		switch format {
+		case "html":
+			if output.HTMLFile == "" {
+				return fmt.Errorf("output.html_file is required")
+			}
+		case "csv":
+			if output.CSVFile == "" {
+				return fmt.Errorf("output.csv_file is required for the csv format")
+			}
+		case "xlsx":
+			if output.XLSXFile == "" {
+				return fmt.Errorf("output.xlsx_file is required for the xlsx format")
+			}
+		case "dot":
+			if output.DOTFile == "" {
+				return fmt.Errorf("output.dot_file is required for the dot format")
+			}
+		case "mermaid":
+			if output.MermaidFile == "" {
+				return fmt.Errorf("output.mermaid_file is required for the mermaid format")
+			}
+		case "sarif":
+			if output.SARIFFile == "" {
+				return fmt.Errorf("output.sarif_file is required for the sarif format")
+			}
+		case "markdown":
+			if output.MarkdownFile == "" {
+				return fmt.Errorf("output.markdown_file is required for the markdown format")
+			}
+		case "json":
+		default:
+			return fmt.Errorf("output.formats entries must be one of: %s (got %q)", reportFormats, format)
+		}
+	}
 	return nil
 }
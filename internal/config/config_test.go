@@ -3,6 +3,9 @@ package config_test
 import (
 	"di-matrix-cli/internal/config"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -170,9 +173,27 @@ func clearConfigEnvVars(t *testing.T) {
 	envVars := []string{
 		"GITLAB_BASE_URL",
 		"GITLAB_TOKEN",
+		"GITLAB_TOKEN_TYPE",
+		"GITLAB_TOKEN_FILE",
+		"GITEA_BASE_URL",
+		"GITEA_TOKEN",
+		"GITEA_TOKEN_FILE",
+		"GITEA_TOKEN_COMMAND",
+		"GITLAB_TOKEN_COMMAND",
+		"CI_JOB_TOKEN",
+		"CI_SERVER_URL",
+		"GITLAB_REF",
 		"OUTPUT_HTML_FILE",
 		"OUTPUT_TITLE",
 		"ANALYSIS_TIMEOUT_MINUTES",
+		"DI_MATRIX_REPOSITORIES",
+		"DI_MATRIX_INTERNAL_DOMAINS",
+		"DI_MATRIX_INTERNAL_PATTERNS",
+		"DI_MATRIX_PROVIDER",
+		"SLACK_WEBHOOK_URL",
+		"TEAMS_WEBHOOK_URL",
+		"DEPENDENCY_TRACK_URL",
+		"DEPENDENCY_TRACK_API_KEY",
 	}
 
 	for _, envVar := range envVars {
@@ -231,6 +252,7 @@ output:
 
 timeout:
   analysis_timeout_minutes: 15
+  repository_timeout_minutes: 3
 `
 
 	tmpFile := createTempConfigFile(t, configContent)
@@ -244,6 +266,1072 @@ timeout:
 	if cfg.Timeout.AnalysisTimeoutMinutes != 15 {
 		t.Errorf("Expected timeout 15 minutes, got %d", cfg.Timeout.AnalysisTimeoutMinutes)
 	}
+	if cfg.Timeout.RepositoryTimeoutMinutes != 3 {
+		t.Errorf("Expected repository timeout 3 minutes, got %d", cfg.Timeout.RepositoryTimeoutMinutes)
+	}
+
+	invalid := createTempConfigFile(t, strings.Replace(configContent, "repository_timeout_minutes: 3",
+		"repository_timeout_minutes: -1", 1))
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "repository_timeout_minutes") {
+		t.Errorf("Expected timeout.repository_timeout_minutes validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_RetryPolicies(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	// Aggressive content retries, the other classes keep their defaults
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - id: 1
+    name: "test-repo"
+
+output:
+  html_file: "test.html"
+  title: "Test"
+
+retry:
+  content:
+    retries: 8
+    backoff_ms: 2000
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := cfg.Retry.Content
+	if content.Retries != 8 || content.BackoffMs != 2000 || content.TimeoutSeconds != 30 {
+		t.Errorf("Expected configured content retries with the default timeout, got %+v", content)
+	}
+	if cfg.Retry.Metadata.Retries != 3 || cfg.Retry.Registry.TimeoutSeconds != 15 {
+		t.Errorf("Expected default metadata and registry policies, got %+v", cfg.Retry)
+	}
+	if !cfg.Registry.Enabled || cfg.Registry.NPM != "https://registry.npmjs.org" || cfg.Registry.CacheTTLHours != 24 {
+		t.Errorf("Expected package registries enabled by default, got %+v", cfg.Registry)
+	}
+	if cfg.OSV.Enabled || cfg.OSV.BaseURL != "https://api.osv.dev" || cfg.OSV.Workers != 8 {
+		t.Errorf("Expected vulnerability scanning disabled by default, got %+v", cfg.OSV)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`  tree:
+    retries: -1
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "retry.tree") {
+		t.Errorf("Expected retry.tree validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_SubgroupFilters(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+output:
+  html_file: "test.html"
+  title: "Test"
+
+repositories:
+  - url: "https://gitlab.com/acme"
+    subgroup_depth: 2
+    include_subgroups: ["platform/*"]
+    exclude_subgroups: ["platform/sandbox/*"]
+    paths: ["services/*"]
+    exclude_paths: ["examples/"]
+  - url: "https://gitlab.com/acme/web"
+    topics: ["frontend"]
+    name_pattern: "*-app"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	repo := cfg.Repositories[0]
+	if repo.SubgroupDepth != 2 || len(repo.IncludeSubgroups) != 1 || repo.ExcludeSubgroups[0] != "platform/sandbox/*" {
+		t.Errorf("Expected subgroup settings, got %+v", repo)
+	}
+	if !repo.HasGroupFilter() {
+		t.Errorf("Expected the entry to filter its group")
+	}
+	if len(repo.Paths) != 1 || len(repo.ExcludePaths) != 1 || repo.ExcludePaths[0] != "examples/" {
+		t.Errorf("Expected path filters, got %v %v", repo.Paths, repo.ExcludePaths)
+	}
+	if web := cfg.Repositories[1]; !web.HasGroupFilter() || web.Topics[0] != "frontend" || web.NamePattern != "*-app" {
+		t.Errorf("Expected topic and name filters, got %+v", web)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`  - url: "https://gitlab.com/other"
+    exclude_subgroups: ["platform/[sandbox"]
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "repository[2]") {
+		t.Errorf("Expected repository[2] glob validation error, got: %v", err)
+	}
+
+	invalidPaths := createTempConfigFile(t, configContent+`  - url: "https://gitlab.com/other"
+    paths: ["services/[api"]
+`)
+	defer os.Remove(invalidPaths)
+
+	if _, err := config.LoadConfig(invalidPaths); err == nil || !strings.Contains(err.Error(), "path glob") {
+		t.Errorf("Expected repository[2] path glob validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_ExcludedDependencies(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+output:
+  html_file: "test.html"
+  title: "Test"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent+`
+exclude:
+  dependencies: ["@types/*", "/^types-/"]
+`)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Exclude.Dependencies) != 2 || cfg.Exclude.Dependencies[0] != "@types/*" {
+		t.Errorf("Expected the exclusion patterns, got %v", cfg.Exclude.Dependencies)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+exclude:
+  dependencies: ["/(types/"]
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "exclude.dependencies") {
+		t.Errorf("Expected exclude.dependencies validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Serve(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Serve.Schedule != "" || cfg.Serve.KeepReports != 10 {
+		t.Errorf("Expected on-demand analyses keeping 10 reports by default, got %+v", cfg.Serve)
+	}
+
+	scheduled := createTempConfigFile(t, configContent+`
+serve:
+  schedule: "0 6 * * 1-5"
+  keep_reports: 3
+`)
+	defer os.Remove(scheduled)
+
+	cfg, err = config.LoadConfig(scheduled)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Serve.Schedule != "0 6 * * 1-5" || cfg.Serve.KeepReports != 3 {
+		t.Errorf("Expected the configured schedule, got %+v", cfg.Serve)
+	}
+
+	for _, invalid := range []string{"schedule: \"0 25 * * *\"", "keep_reports: -1"} {
+		invalidFile := createTempConfigFile(t, configContent+"\nserve:\n  "+invalid+"\n")
+		defer os.Remove(invalidFile)
+
+		if _, err := config.LoadConfig(invalidFile); err == nil || !strings.Contains(err.Error(), "serve.") {
+			t.Errorf("Expected serve validation error for %s, got: %v", invalid, err)
+		}
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_PolicyLists(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Policy.Denylist) != 0 || len(cfg.Policy.Allowlist) != 0 || !cfg.Policy.FailOnViolation {
+		t.Errorf("Expected empty lists and failing on violations by default, got %+v", cfg.Policy)
+	}
+
+	lists := createTempConfigFile(t, configContent+`
+policy:
+  denylist:
+    - "lodash@<4.17.21"
+    - "github.com/pkg/errors"
+  allowlist:
+    - "@types/*"
+  fail_on_violation: false
+`)
+	defer os.Remove(lists)
+
+	cfg, err = config.LoadConfig(lists)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Policy.Denylist) != 2 || cfg.Policy.Allowlist[0] != "@types/*" || cfg.Policy.FailOnViolation {
+		t.Errorf("Expected the configured lists, got %+v", cfg.Policy)
+	}
+
+	invalid := createTempConfigFile(t, configContent+"\npolicy:\n  allowlist: [\"lodash@not a range\"]\n")
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "policy.allowlist") {
+		t.Errorf("Expected policy.allowlist validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Metrics(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Metrics.PushgatewayURL != "" || cfg.Metrics.Job != "di-matrix-cli" {
+		t.Errorf("Expected no Pushgateway and the default job, got %+v", cfg.Metrics)
+	}
+
+	pushed := createTempConfigFile(t, configContent+`
+metrics:
+  pushgateway_url: "http://pushgateway:9091"
+  job: "dependency-matrix"
+`)
+	defer os.Remove(pushed)
+
+	cfg, err = config.LoadConfig(pushed)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Metrics.PushgatewayURL != "http://pushgateway:9091" || cfg.Metrics.Job != "dependency-matrix" {
+		t.Errorf("Expected the configured Pushgateway, got %+v", cfg.Metrics)
+	}
+
+	for _, invalid := range []string{"pushgateway_url: \"pushgateway:9091\"", "job: \"\""} {
+		invalidFile := createTempConfigFile(t, configContent+"\nmetrics:\n  "+invalid+"\n")
+		defer os.Remove(invalidFile)
+
+		if _, err := config.LoadConfig(invalidFile); err == nil || !strings.Contains(err.Error(), "metrics.") {
+			t.Errorf("Expected metrics validation error for %s, got: %v", invalid, err)
+		}
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_EOL(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cfg.EOL.Enabled || cfg.EOL.BaseURL != "https://endoflife.date" || len(cfg.EOL.Products) != 0 {
+		t.Errorf("Expected end-of-life checks against endoflife.date, got %+v", cfg.EOL)
+	}
+
+	mapped := createTempConfigFile(t, configContent+`
+eol:
+  products:
+    - dependency: "com.acme:platform-*"
+      product: "spring-boot"
+`)
+	defer os.Remove(mapped)
+
+	cfg, err = config.LoadConfig(mapped)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []config.EOLProductConfig{{Dependency: "com.acme:platform-*", Product: "spring-boot"}}
+	if !reflect.DeepEqual(cfg.EOL.Products, expected) {
+		t.Errorf("Expected product mappings %+v, got %+v", expected, cfg.EOL.Products)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+eol:
+  products:
+    - dependency: "com.acme:platform-*"
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "eol.products") {
+		t.Errorf("Expected eol.products validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_MatrixMode(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Output.MatrixMode.Cell != "resolved" || cfg.Output.MatrixMode.Compare != "newest" {
+		t.Errorf("Expected resolved versions compared against the newest by default, got %+v", cfg.Output.MatrixMode)
+	}
+
+	configured := createTempConfigFile(t, configContent+`
+output:
+  matrix_mode:
+    cell: "both"
+    compare: "registry"
+`)
+	defer os.Remove(configured)
+
+	cfg, err = config.LoadConfig(configured)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Output.MatrixMode.Cell != "both" || cfg.Output.MatrixMode.Compare != "registry" {
+		t.Errorf("Expected configured matrix mode, got %+v", cfg.Output.MatrixMode)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+output:
+  matrix_mode:
+    compare: "lowest"
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "output.matrix_mode.compare") {
+		t.Errorf("Expected output.matrix_mode.compare validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_VirtualizeCells(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Output.VirtualizeCells != 50000 {
+		t.Errorf("Expected matrices above 50000 cells to be virtualized by default, got %d", cfg.Output.VirtualizeCells)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+output:
+  virtualize_cells: -1
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "output.virtualize_cells") {
+		t.Errorf("Expected output.virtualize_cells validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_HealthWeights(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	valid := createTempConfigFile(t, configContent+`
+health:
+  weights:
+    pinning: 0
+`)
+	defer os.Remove(valid)
+
+	cfg, err := config.LoadConfig(valid)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Health.Weights.Pinning != 0 || cfg.Health.Weights.Drift != 3 {
+		t.Errorf("Expected pinning weight 0 and default drift weight 3, got %+v", cfg.Health.Weights)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+health:
+  weights:
+    vulnerabilities: -4
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil ||
+		!strings.Contains(err.Error(), "health.weights.vulnerabilities must not be negative") {
+		t.Errorf("Expected health.weights.vulnerabilities validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Publish(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent+`
+output:
+  publish:
+    pages:
+      project: "acme/dependency-matrix"
+    wiki:
+      project: "https://gitlab.com/acme/handbook"
+`)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	pages := cfg.Output.Publish.Pages
+	if pages.Project != "acme/dependency-matrix" || pages.Branch != "pages" || pages.Directory != "public" {
+		t.Errorf("Expected the report published to public/ of the pages branch by default, got %+v", pages)
+	}
+	if cfg.Output.Publish.Wiki.Page != "dependency-matrix" {
+		t.Errorf("Expected the dependency-matrix wiki page by default, got %q", cfg.Output.Publish.Wiki.Page)
+	}
+	if cfg.Output.MarkdownFile != "dependency-matrix.md" {
+		t.Errorf("Expected dependency-matrix.md, got %q", cfg.Output.MarkdownFile)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+output:
+  publish:
+    pages:
+      project: "acme/dependency-matrix"
+      directory: "../public"
+`)
+	defer os.Remove(invalid)
+
+	_, err = config.LoadConfig(invalid)
+	if err == nil || !strings.Contains(err.Error(), "output.publish.pages.directory") {
+		t.Errorf("Expected output.publish.pages.directory validation error, got: %v", err)
+	}
+
+	// Publishing goes through the GitLab API even when repositories are read from disk
+	local := createTempConfigFile(t, `
+provider: "local"
+
+repositories:
+  - url: "/srv/checkouts"
+
+output:
+  publish:
+    wiki:
+      project: "acme/handbook"
+`)
+	defer os.Remove(local)
+
+	if _, err := config.LoadConfig(local); err == nil || !strings.Contains(err.Error(), "gitlab.token") {
+		t.Errorf("Expected output.publish to require GitLab credentials, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Upload(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent+`
+output:
+  upload: "s3://reports/dependency-matrix"
+`)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Output.Upload != "s3://reports/dependency-matrix" {
+		t.Errorf("Expected the S3 upload URL, got %q", cfg.Output.Upload)
+	}
+
+	invalid := createTempConfigFile(t, configContent+`
+output:
+  upload: "ftp://reports/dependency-matrix"
+`)
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "output.upload") {
+		t.Errorf("Expected output.upload validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Notifications(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+
+	tmpFile := createTempConfigFile(t, configContent+`
+notifications:
+  teams_webhook_url: "https://acme.webhook.office.com/webhookb2/abc"
+`)
+	defer os.Remove(tmpFile)
+
+	// Webhook URLs are secrets, usually given by the environment
+	t.Setenv("SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T0/B0/secret")
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Notifications.SlackWebhookURL != "https://hooks.slack.com/services/T0/B0/secret" {
+		t.Errorf("Expected the Slack webhook from SLACK_WEBHOOK_URL, got %q", cfg.Notifications.SlackWebhookURL)
+	}
+	if cfg.Notifications.TeamsWebhookURL != "https://acme.webhook.office.com/webhookb2/abc" {
+		t.Errorf("Expected the Teams webhook from the file, got %q", cfg.Notifications.TeamsWebhookURL)
+	}
+	if cfg.Notifications.MaxItems != 10 {
+		t.Errorf("Expected 10 listed items by default, got %d", cfg.Notifications.MaxItems)
+	}
+
+	t.Setenv("SLACK_WEBHOOK_URL", "http://hooks.slack.com/services/T0/B0/secret")
+	_, err = config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "notifications.slack_webhook_url") {
+		t.Errorf("Expected notifications.slack_webhook_url validation error, got: %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected the webhook URL left out of the error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_TokenSources(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "gitlab-token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.com"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+`
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	t.Setenv("GITLAB_TOKEN_FILE", tokenFile)
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token, err := cfg.GitLab.ResolveToken(); err != nil || token != "file-token" {
+		t.Errorf("Expected the token from the file, got %q (%v)", token, err)
+	}
+
+	t.Setenv("GITLAB_TOKEN_FILE", "")
+	t.Setenv("GITLAB_TOKEN_COMMAND", "echo '  command-token  '")
+	cfg, err = config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token, err := cfg.GitLab.ResolveToken(); err != nil || token != "command-token" {
+		t.Errorf("Expected the token printed by the command, got %q (%v)", token, err)
+	}
+
+	// The command only runs when the token is needed, loading the configuration succeeds
+	t.Setenv("GITLAB_TOKEN_COMMAND", "echo partial; echo 'permission denied' >&2; exit 2")
+	cfg, err = config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, err = cfg.GitLab.ResolveToken()
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected the command's error output, got: %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "partial") {
+		t.Errorf("Expected the command's output left out of the error, got: %v", err)
+	}
+
+	// An explicit token wins over the file and the command
+	t.Setenv("GITLAB_TOKEN_FILE", tokenFile)
+	t.Setenv("GITLAB_TOKEN", "env-token")
+	cfg, err = config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token, err := cfg.GitLab.ResolveToken(); err != nil || token != "env-token" {
+		t.Errorf("Expected the token from GITLAB_TOKEN, got %q (%v)", token, err)
+	}
+
+	t.Setenv("GITLAB_TOKEN", "")
+	_, err = config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Errorf("Expected a conflicting token sources error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_GitLabInstances(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+gitlab:
+  base_url: "https://gitlab.example.com"
+  token: "self-hosted-token"
+
+repositories:
+  - url: "https://gitlab.example.com/acme/api"
+  - url: "https://gitlab.com/acme/web"
+`
+	tmpFile := createTempConfigFile(t, strings.Replace(configContent, `  token: "self-hosted-token"
+`, `  token: "self-hosted-token"
+  instances:
+    - base_url: "https://gitlab.com"
+      token_command: "echo saas-token"
+`, 1))
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.GitLab.Instances) != 1 {
+		t.Fatalf("Expected one further instance, got %+v", cfg.GitLab.Instances)
+	}
+	if token, err := cfg.GitLab.InstanceToken(0); err != nil || token != "saas-token" {
+		t.Errorf("Expected the instance token from its command, got %q (%v)", token, err)
+	}
+
+	for _, tc := range []struct {
+		instance string
+		err      string
+	}{
+		{`{base_url: "gitlab.com", token: "t"}`, "gitlab.instances[0].base_url must be an http or https URL"},
+		{`{base_url: "https://gitlab.example.com/", token: "t"}`, "configured more than once"},
+		{`{base_url: "https://gitlab.com"}`, "gitlab.instances[0].token is required"},
+		{`{base_url: "https://gitlab.com", token: "t", token_type: "cookie"}`, "gitlab.instances[0].token_type"},
+	} {
+		invalidFile := createTempConfigFile(t, strings.Replace(configContent, `  token: "self-hosted-token"
+`, `  token: "self-hosted-token"
+  instances: [`+tc.instance+`]
+`, 1))
+		_, err := config.LoadConfig(invalidFile)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Expected %q validation error for %s, got: %v", tc.err, tc.instance, err)
+		}
+		os.Remove(invalidFile)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Gitea(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	tmpFile := createTempConfigFile(t, `
+provider: "gitea"
+gitea:
+  base_url: "https://forgejo.example.com"
+
+repositories:
+  - url: "https://forgejo.example.com/platform"
+`)
+	defer os.Remove(tmpFile)
+
+	_, err := config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "gitea.token is required") {
+		t.Errorf("Expected gitea.token validation error, got: %v", err)
+	}
+
+	// No GitLab token is needed for Gitea
+	t.Setenv("GITEA_TOKEN", "gitea-token")
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Gitea.Token != "gitea-token" || !cfg.Gitea.SkipArchived {
+		t.Errorf("Expected the token from GITEA_TOKEN and archived repositories skipped, got %+v", cfg.Gitea)
+	}
+	if cfg.SourceBaseURL() != "https://forgejo.example.com" {
+		t.Errorf("Expected the Gitea base URL as source, got %q", cfg.SourceBaseURL())
+	}
+
+	t.Setenv("GITEA_BASE_URL", "forgejo.example.com")
+	_, err = config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "gitea.base_url") {
+		t.Errorf("Expected gitea.base_url validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_JobToken(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	tmpFile := createTempConfigFile(t, `
+gitlab:
+  token_type: "job"
+
+repositories:
+  - url: "https://gitlab.example.com/acme/service"
+`)
+	defer os.Remove(tmpFile)
+
+	_, err := config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "CI_JOB_TOKEN") {
+		t.Errorf("Expected gitlab.token validation error outside CI, got: %v", err)
+	}
+
+	// Variables GitLab sets in every CI job
+	t.Setenv("CI_JOB_TOKEN", "job-token")
+	t.Setenv("CI_SERVER_URL", "https://gitlab.example.com")
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.GitLab.Token != "job-token" || cfg.GitLab.BaseURL != "https://gitlab.example.com" {
+		t.Errorf("Expected the job token and instance of the CI job, got %q at %q", cfg.GitLab.Token, cfg.GitLab.BaseURL)
+	}
+
+	// An explicit base URL wins over the instance of the job
+	t.Setenv("GITLAB_BASE_URL", "https://gitlab-proxy.example.com")
+	cfg, err = config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.GitLab.BaseURL != "https://gitlab-proxy.example.com" {
+		t.Errorf("Expected the base URL from GITLAB_BASE_URL, got %q", cfg.GitLab.BaseURL)
+	}
+
+	t.Setenv("GITLAB_TOKEN_TYPE", "cookie")
+	_, err = config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "gitlab.token_type") {
+		t.Errorf("Expected gitlab.token_type validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_DependencyTrack(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	tmpFile := createTempConfigFile(t, `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/service"
+
+dependency_track:
+  url: "https://dtrack.example.com"
+`)
+	defer os.Remove(tmpFile)
+
+	_, err := config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "dependency_track.api_key") {
+		t.Errorf("Expected dependency_track.api_key validation error, got: %v", err)
+	}
+
+	t.Setenv("DEPENDENCY_TRACK_API_KEY", "odt_secret")
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.DependencyTrack.APIKey != "odt_secret" {
+		t.Errorf("Expected the API key from DEPENDENCY_TRACK_API_KEY, got %q", cfg.DependencyTrack.APIKey)
+	}
+	if cfg.DependencyTrack.ProjectVersion != "latest" {
+		t.Errorf("Expected project version latest by default, got %q", cfg.DependencyTrack.ProjectVersion)
+	}
+
+	t.Setenv("DEPENDENCY_TRACK_URL", "dtrack.example.com")
+	_, err = config.LoadConfig(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), "dependency_track.url") {
+		t.Errorf("Expected dependency_track.url validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_Provider(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	// Local directories need no GitLab token
+	configContent := `
+provider: "local"
+
+output:
+  html_file: "test.html"
+  title: "Test"
+
+repositories:
+  - url: "/srv/checkouts"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Provider != "local" || cfg.UsesGitLab() {
+		t.Errorf("Expected the local provider, got %q", cfg.Provider)
+	}
+
+	// The default provider is GitLab, which requires a token
+	gitlabConfig := createTempConfigFile(t, strings.Replace(configContent, `provider: "local"`, "", 1))
+	defer os.Remove(gitlabConfig)
+
+	if _, err := config.LoadConfig(gitlabConfig); err == nil || !strings.Contains(err.Error(), "gitlab.token") {
+		t.Errorf("Expected gitlab.token validation error, got: %v", err)
+	}
+}
+
+func TestLoadConfig_OutputFormats(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	configContent := `
+provider: "local"
+
+output:
+  formats: ["csv", "json"]
+  html_file: ""
+  title: "Test"
+
+repositories:
+  - url: "/srv/checkouts"
+`
+
+	tmpFile := createTempConfigFile(t, configContent)
+	defer os.Remove(tmpFile)
+
+	cfg, err := config.LoadConfig(tmpFile)
+	if err != nil {
+		t.Fatalf("Expected no HTML file to be required without the html format, got: %v", err)
+	}
+	if len(cfg.Output.Formats) != 2 || cfg.Output.CSVFile != "dependency-matrix.csv" {
+		t.Errorf("Expected csv and json formats with the default CSV path, got %+v", cfg.Output)
+	}
+
+	invalid := createTempConfigFile(t, strings.Replace(configContent, `"csv", "json"`, `"html", "pdf"`, 1))
+	defer os.Remove(invalid)
+
+	if _, err := config.LoadConfig(invalid); err == nil || !strings.Contains(err.Error(), "output.") {
+		t.Errorf("Expected output validation error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadLocalConfig(t *testing.T) {
+	// Clear environment variables that might interfere with tests
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	// No config file, token or repositories needed
+	cfg, err := config.LoadLocalConfig("", []string{".", "../platform"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []config.RepositoryConfig{{URL: "."}, {URL: "../platform"}}
+	if !reflect.DeepEqual(cfg.Repositories, expected) {
+		t.Errorf("Expected directories as repositories, got %v", cfg.Repositories)
+	}
+	if cfg.Provider != config.LocalProvider || !cfg.Offline {
+		t.Errorf("Expected the local provider in offline mode, got %q offline=%t", cfg.Provider, cfg.Offline)
+	}
+
+	// Settings other than the source of repositories still come from the config file
+	tmpFile := createTempConfigFile(t, `
+gitlab:
+  base_url: "https://gitlab.com"
+  token: "test-token"
+
+repositories:
+  - url: "https://gitlab.com/acme/billing"
+
+output:
+  html_file: "local.html"
+  title: "Workspace"
+`)
+	defer os.Remove(tmpFile)
+
+	cfg, err = config.LoadLocalConfig(tmpFile, []string{"."})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Repositories) != 1 || cfg.Repositories[0].URL != "." || cfg.Output.HTMLFile != "local.html" {
+		t.Errorf("Expected the directory with the configured output, got %v %+v", cfg.Repositories, cfg.Output)
+	}
+
+	if _, err := config.LoadLocalConfig("", nil); err == nil {
+		t.Errorf("Expected an error without directories")
+	}
 }
 
 //nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
@@ -282,3 +1370,141 @@ output:
 		t.Errorf("Expected timeout 20 minutes from environment variable, got %d", cfg.Timeout.AnalysisTimeoutMinutes)
 	}
 }
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfig_EnvironmentOnly(t *testing.T) {
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	if _, err := config.LoadConfig(""); err == nil {
+		t.Fatal("Expected error without config path and DI_MATRIX_REPOSITORIES")
+	}
+
+	t.Setenv("DI_MATRIX_REPOSITORIES", "https://gitlab.com/group/api,\nhttps://gitlab.com/platform  ")
+	t.Setenv("GITLAB_TOKEN", "env-token")
+	t.Setenv("DI_MATRIX_INTERNAL_PATTERNS", "@company/,com.company.")
+	t.Setenv("OUTPUT_HTML_FILE", "/reports/matrix.html")
+
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.GitLab.BaseURL != "https://gitlab.com" || cfg.GitLab.Token != "env-token" {
+		t.Errorf("Expected default base URL and token from environment, got %q and %q",
+			cfg.GitLab.BaseURL, cfg.GitLab.Token)
+	}
+
+	expected := []config.RepositoryConfig{{URL: "https://gitlab.com/group/api"}, {URL: "https://gitlab.com/platform"}}
+	if !reflect.DeepEqual(cfg.Repositories, expected) {
+		t.Errorf("Expected repositories %v, got %v", expected, cfg.Repositories)
+	}
+
+	if !reflect.DeepEqual(cfg.Internal.Patterns, []string{"@company/", "com.company."}) {
+		t.Errorf("Expected internal patterns from environment, got %v", cfg.Internal.Patterns)
+	}
+
+	if cfg.Output.HTMLFile != "/reports/matrix.html" || !cfg.Scanner.SkipVendored {
+		t.Errorf("Expected output from environment and remaining defaults, got %+v %+v", cfg.Output, cfg.Scanner)
+	}
+}
+
+//nolint:paralleltest // Cannot use t.Parallel() with t.Setenv()
+func TestLoadConfigWithRepositories(t *testing.T) {
+	clearConfigEnvVars(t)
+	defer restoreConfigEnvVars(t)
+
+	t.Setenv("DI_MATRIX_REPOSITORIES", "https://gitlab.com/from-env")
+	t.Setenv("GITLAB_TOKEN", "env-token")
+
+	listed := []config.RepositoryConfig{{URL: "https://gitlab.com/group/generated"}}
+	cfg, err := config.LoadConfigWithRepositories("", listed)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Repositories, listed) {
+		t.Errorf("Expected listed repositories to replace the environment ones, got %v", cfg.Repositories)
+	}
+}
+
+func TestReadRepositoryList(t *testing.T) {
+	t.Parallel()
+
+	list := `# generated by list-services.sh
+https://gitlab.com/group/api
+
+  https://gitlab.com/platform
+`
+	repositories, err := config.ReadRepositoryList(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []config.RepositoryConfig{{URL: "https://gitlab.com/group/api"}, {URL: "https://gitlab.com/platform"}}
+	if !reflect.DeepEqual(repositories, expected) {
+		t.Errorf("Expected repositories %v, got %v", expected, repositories)
+	}
+
+	_, err = config.ReadRepositoryList(strings.NewReader("https://gitlab.com/group/api\ngroup/web\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected error for line 2, got: %v", err)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Defaults()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Output.HTMLFile != "dependency-matrix.html" || cfg.Concurrency.FileFetcherWorkers != 8 {
+		t.Errorf("Expected built-in defaults, got %+v %+v", cfg.Output, cfg.Concurrency)
+	}
+
+	if cfg.IncludeDev {
+		t.Error("Expected dev and test dependencies to be left out by default")
+	}
+
+	if cfg.Concurrency.MaxConcurrentRequests != 10 {
+		t.Errorf("Expected 10 concurrent GitLab requests by default, got %d", cfg.Concurrency.MaxConcurrentRequests)
+	}
+
+	expected := config.ConcurrencyConfig{
+		RepositoryWorkers:     10,
+		FileFetcherWorkers:    8,
+		ParserWorkers:         5,
+		FileParserWorkers:     3,
+		PaginationWorkers:     5,
+		MaxConcurrentRequests: 10,
+	}
+	if cfg.Concurrency != expected {
+		t.Errorf("Expected default worker pools %+v, got %+v", expected, cfg.Concurrency)
+	}
+
+	if !cfg.GitLab.SkipArchived || cfg.GitLab.IncludeForks {
+		t.Errorf("Expected archived projects and forks to be left out of groups by default, got %+v", cfg.GitLab)
+	}
+
+	if cfg.Internal.FromGroups {
+		t.Error("Expected internal projects not to be derived from GitLab groups by default")
+	}
+
+	if cfg.Output.Transitive != "include" {
+		t.Errorf("Expected transitive dependencies to be included by default, got %q", cfg.Output.Transitive)
+	}
+
+	if len(cfg.Output.Formats) != 1 || cfg.Output.Formats[0] != "html" {
+		t.Errorf("Expected only the HTML report by default, got %v", cfg.Output.Formats)
+	}
+
+	if !cfg.Cache.ParseResults || !cfg.Cache.GitLab {
+		t.Errorf("Expected parse results to be cached across runs by default, got %+v", cfg.Cache)
+	}
+
+	if cfg.GitLab.Token != "" || len(cfg.Repositories) != 0 {
+		t.Errorf("Expected no credentials or repositories, got %+v %v", cfg.GitLab, cfg.Repositories)
+	}
+}
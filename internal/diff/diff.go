@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"di-matrix-cli/internal/domain"
+	reportschema "di-matrix-cli/internal/report"
+	"di-matrix-cli/internal/version"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// KindAdded marks a dependency that is new since the baseline
+	KindAdded = "added"
+	// KindRemoved marks a dependency that disappeared since the baseline
+	KindRemoved = "removed"
+	// KindUpgraded marks a dependency whose version increased
+	KindUpgraded = "upgraded"
+	// KindDowngraded marks a dependency whose version decreased
+	KindDowngraded = "downgraded"
+)
+
+// LoadReport reads the projects from a JSON report written by the generator
+func LoadReport(path string) ([]*domain.Project, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // Path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+
+	var report struct {
+		SchemaVersion string            `json:"schema_version"`
+		Projects      []*domain.Project `json:"projects"`
+	}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+
+	// Reports written before schema_version existed share the 1.x project layout
+	if major, _, _ := strings.Cut(report.SchemaVersion, "."); major != "" && major != reportschema.SchemaMajor {
+		return nil, fmt.Errorf("report %s uses unsupported schema version %s, expected %s.x",
+			path, report.SchemaVersion, reportschema.SchemaMajor)
+	}
+
+	// Reports mark transitive dependencies since 1.7, everything else they list is direct
+	var transitive struct {
+		Projects []struct {
+			Dependencies []struct {
+				Transitive bool `json:"transitive"`
+			} `json:"dependencies"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(content, &transitive); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	for i, project := range report.Projects {
+		for j, dep := range project.Dependencies {
+			dep.Direct = !transitive.Projects[i].Dependencies[j].Transitive
+		}
+	}
+
+	return report.Projects, nil
+}
+
+// Compare returns dependency changes between a baseline and the current projects.
+// Projects are matched by ID, dependencies by name. Results are sorted by project and dependency.
+func Compare(baseline, current []*domain.Project) []domain.DependencyChange {
+	baselineProjects := indexProjects(baseline)
+	currentProjects := indexProjects(current)
+
+	var changes []domain.DependencyChange
+
+	for id, project := range currentProjects {
+		oldDeps := indexDependencies(baselineProjects[id])
+		newDeps := indexDependencies(project)
+
+		for name, dep := range newDeps {
+			oldDep, existed := oldDeps[name]
+			switch {
+			case !existed:
+				changes = append(changes, newChange(project, name, KindAdded, "", dep.Version))
+			case version.Compare(dep.Version, oldDep.Version) > 0:
+				changes = append(changes, newChange(project, name, KindUpgraded, oldDep.Version, dep.Version))
+			case version.Compare(dep.Version, oldDep.Version) < 0:
+				changes = append(changes, newChange(project, name, KindDowngraded, oldDep.Version, dep.Version))
+			}
+		}
+
+		for name, oldDep := range oldDeps {
+			if _, exists := newDeps[name]; !exists {
+				changes = append(changes, newChange(project, name, KindRemoved, oldDep.Version, ""))
+			}
+		}
+	}
+
+	// Projects that disappeared entirely lose all of their dependencies
+	for id, project := range baselineProjects {
+		if _, exists := currentProjects[id]; exists {
+			continue
+		}
+		for name, oldDep := range indexDependencies(project) {
+			changes = append(changes, newChange(project, name, KindRemoved, oldDep.Version, ""))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ProjectID != changes[j].ProjectID {
+			return changes[i].ProjectID < changes[j].ProjectID
+		}
+		return changes[i].Dependency < changes[j].Dependency
+	})
+
+	return changes
+}
+
+// Summarize counts changes by kind
+func Summarize(changes []domain.DependencyChange) map[string]int {
+	counts := map[string]int{KindAdded: 0, KindRemoved: 0, KindUpgraded: 0, KindDowngraded: 0}
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+	return counts
+}
+
+func newChange(project *domain.Project, name, kind, oldVersion, newVersion string) domain.DependencyChange {
+	return domain.DependencyChange{
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Dependency:  name,
+		Kind:        kind,
+		OldVersion:  oldVersion,
+		NewVersion:  newVersion,
+	}
+}
+
+func indexProjects(projects []*domain.Project) map[string]*domain.Project {
+	index := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		index[project.ID] = project
+	}
+	return index
+}
+
+// indexDependencies maps dependency names to the highest version the project uses
+func indexDependencies(project *domain.Project) map[string]*domain.Dependency {
+	index := make(map[string]*domain.Dependency)
+	if project == nil {
+		return index
+	}
+	for _, dep := range project.Dependencies {
+		if existing, exists := index[dep.Name]; exists && version.Compare(dep.Version, existing.Version) <= 0 {
+			continue
+		}
+		index[dep.Name] = dep
+	}
+	return index
+}
@@ -0,0 +1,101 @@
+package diff_test
+
+import (
+	"di-matrix-cli/internal/diff"
+	"di-matrix-cli/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	baseline := []*domain.Project{
+		{
+			ID: "api",
+			Dependencies: []*domain.Dependency{
+				{Name: "gin", Version: "v1.9.0"},
+				{Name: "zap", Version: "v1.27.0"},
+				{Name: "viper", Version: "v1.20.0"},
+				{Name: "cobra", Version: "v1.10.0"},
+			},
+		},
+		{
+			ID:           "legacy",
+			Dependencies: []*domain.Dependency{{Name: "logrus", Version: "v1.9.0"}},
+		},
+	}
+	current := []*domain.Project{
+		{
+			ID: "api",
+			Dependencies: []*domain.Dependency{
+				{Name: "gin", Version: "v1.9.1"},
+				{Name: "zap", Version: "v1.26.0"},
+				{Name: "cobra", Version: "v1.10.0"},
+				{Name: "testify", Version: "v1.11.1"},
+			},
+		},
+	}
+
+	changes := diff.Compare(baseline, current)
+
+	require.Len(t, changes, 5)
+	assert.Equal(t, domain.DependencyChange{
+		ProjectID: "api", Dependency: "gin", Kind: diff.KindUpgraded, OldVersion: "v1.9.0", NewVersion: "v1.9.1",
+	}, changes[0])
+	assert.Equal(t, diff.KindAdded, changes[1].Kind)
+	assert.Equal(t, "testify", changes[1].Dependency)
+	assert.Equal(t, diff.KindRemoved, changes[2].Kind)
+	assert.Equal(t, "viper", changes[2].Dependency)
+	assert.Equal(t, diff.KindDowngraded, changes[3].Kind)
+	assert.Equal(t, "zap", changes[3].Dependency)
+	assert.Equal(t, "legacy", changes[4].ProjectID)
+	assert.Equal(t, diff.KindRemoved, changes[4].Kind)
+
+	summary := diff.Summarize(changes)
+	assert.Equal(t, 1, summary[diff.KindAdded])
+	assert.Equal(t, 2, summary[diff.KindRemoved])
+	assert.Equal(t, 1, summary[diff.KindUpgraded])
+	assert.Equal(t, 1, summary[diff.KindDowngraded])
+}
+
+func TestLoadReport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	content := `{"projects": [{"id": "api", "dependencies": [{"name": "gin", "version": "v1.9.0"}]}], "title": "x"}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	projects, err := diff.LoadReport(path)
+
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "api", projects[0].ID)
+	assert.Equal(t, "v1.9.0", projects[0].Dependencies[0].Version)
+	assert.True(t, projects[0].Dependencies[0].Direct, "dependencies not marked transitive are direct")
+
+	_, err = diff.LoadReport(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestLoadReport_SchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current.json")
+	future := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(current, []byte(`{"schema_version": "1.9", "projects": [{"id": "api"}]}`), 0o600))
+	require.NoError(t, os.WriteFile(future, []byte(`{"schema_version": "2.0", "projects": [{"id": "api"}]}`), 0o600))
+
+	projects, err := diff.LoadReport(current)
+	require.NoError(t, err, "Newer minor versions only add fields")
+	assert.Len(t, projects, 1)
+
+	_, err = diff.LoadReport(future)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported schema version 2.0")
+}
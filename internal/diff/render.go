@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"di-matrix-cli/internal/domain"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Output formats of a change report
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+//go:embed changes.html
+var changesTemplate string
+
+// kinds lists the change kinds in the order reports present them
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var kinds = []string{KindAdded, KindRemoved, KindUpgraded, KindDowngraded}
+
+// ProjectChanges are the dependency changes of a single project
+type ProjectChanges struct {
+	ID      string                    `json:"project_id"`
+	Name    string                    `json:"project_name"`
+	Changes []domain.DependencyChange `json:"changes"`
+}
+
+// Report lists the dependency changes between two analyses per project, for release notes and change reviews
+type Report struct {
+	Old      string           `json:"old"`      // Path of the earlier JSON report
+	New      string           `json:"new"`      // Path of the later JSON report
+	Summary  map[string]int   `json:"summary"`  // Number of changes by kind
+	Projects []ProjectChanges `json:"projects"` // Projects with changes, in the order of Compare
+}
+
+// NewReport groups changes returned by Compare by project. Projects without a name are shown by ID.
+func NewReport(oldPath, newPath string, changes []domain.DependencyChange) *Report {
+	report := &Report{Old: oldPath, New: newPath, Summary: Summarize(changes), Projects: []ProjectChanges{}}
+	for _, change := range changes {
+		last := len(report.Projects) - 1
+		if last < 0 || report.Projects[last].ID != change.ProjectID {
+			name := change.ProjectName
+			if name == "" {
+				name = change.ProjectID
+			}
+			report.Projects = append(report.Projects, ProjectChanges{ID: change.ProjectID, Name: name})
+			last++
+		}
+		report.Projects[last].Changes = append(report.Projects[last].Changes, change)
+	}
+	return report
+}
+
+// Write renders the report to w in format: markdown, html or json
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	case FormatHTML:
+		return r.writeHTML(w)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	default:
+		return fmt.Errorf("unsupported change report format %q", format)
+	}
+}
+
+// writeMarkdown renders a summary line and a table of changes per project
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Dependency changes\n\n")
+	fmt.Fprintf(&b, "Comparing `%s` with `%s`: ", r.Old, r.New)
+	for i, kind := range kinds {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%d %s", r.Summary[kind], kind)
+	}
+	b.WriteString(".\n")
+
+	if len(r.Projects) == 0 {
+		b.WriteString("\nNo dependency changes.\n")
+	}
+	for _, project := range r.Projects {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownCell(project.Name))
+		b.WriteString("| Dependency | Change | Old version | New version |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, change := range project.Changes {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", markdownCell(change.Dependency), change.Kind,
+				markdownCell(change.OldVersion), markdownCell(change.NewVersion))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write change report: %w", err)
+	}
+	return nil
+}
+
+// markdownCell escapes the characters that would end a Markdown table cell
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
+
+// writeHTML renders the report as a standalone page
+func (r *Report) writeHTML(w io.Writer) error {
+	tmpl, err := template.New("changes").Funcs(template.FuncMap{
+		"kindClass": func(kind string) string {
+			switch kind {
+			case KindAdded, KindUpgraded:
+				return "text-green-700"
+			case KindRemoved, KindDowngraded:
+				return "text-red-700"
+			default:
+				return ""
+			}
+		},
+	}).Parse(changesTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := struct {
+		*Report
+		Kinds []string
+	}{Report: r, Kinds: kinds}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
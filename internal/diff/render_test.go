@@ -0,0 +1,89 @@
+package diff_test
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/diff"
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func changeReport() *diff.Report {
+	return diff.NewReport("old.json", "new.json", []domain.DependencyChange{
+		{ProjectID: "api", ProjectName: "api Go", Dependency: "gin", Kind: diff.KindUpgraded,
+			OldVersion: "v1.9.0", NewVersion: "v1.9.1"},
+		{ProjectID: "api", ProjectName: "api Go", Dependency: "testify", Kind: diff.KindAdded, NewVersion: "v1.11.1"},
+		{ProjectID: "legacy", Dependency: "a|b", Kind: diff.KindRemoved, OldVersion: "v1.9.0"},
+	})
+}
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	report := changeReport()
+
+	require.Len(t, report.Projects, 2)
+	assert.Equal(t, "api Go", report.Projects[0].Name)
+	assert.Len(t, report.Projects[0].Changes, 2)
+	assert.Equal(t, "legacy", report.Projects[1].Name, "projects without a name are shown by ID")
+	assert.Equal(t, 1, report.Summary[diff.KindAdded])
+	assert.Equal(t, 0, report.Summary[diff.KindDowngraded])
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Parallel()
+
+	t.Run("markdown", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, changeReport().Write(&out, diff.FormatMarkdown))
+
+		assert.Contains(t, out.String(),
+			"Comparing `old.json` with `new.json`: 1 added, 1 removed, 1 upgraded, 0 downgraded.")
+		assert.Contains(t, out.String(), "## api Go\n")
+		assert.Contains(t, out.String(), "| gin | upgraded | v1.9.0 | v1.9.1 |\n")
+		assert.Contains(t, out.String(), `| a\|b | removed | v1.9.0 |  |`)
+	})
+
+	t.Run("markdown without changes", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, diff.NewReport("old.json", "new.json", nil).Write(&out, diff.FormatMarkdown))
+		assert.Contains(t, out.String(), "No dependency changes.")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, changeReport().Write(&out, diff.FormatHTML))
+
+		assert.Contains(t, out.String(), "<h2 class=\"text-lg font-semibold px-4 py-3 border-b\">api Go</h2>")
+		assert.Contains(t, out.String(), "testify")
+		assert.Contains(t, out.String(), "text-red-700\">removed")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, changeReport().Write(&out, diff.FormatJSON))
+
+		var decoded diff.Report
+		require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+		assert.Equal(t, "old.json", decoded.Old)
+		require.Len(t, decoded.Projects, 2)
+		assert.Equal(t, "v1.9.1", decoded.Projects[0].Changes[0].NewVersion)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, changeReport().Write(&bytes.Buffer{}, "pdf"))
+	})
+}
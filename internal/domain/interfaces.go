@@ -2,8 +2,9 @@ package domain
 
 import "context"
 
-type GitlabClient interface {
-	// checks if the token has enough permissions
+// SourceProvider gives access to the repositories of a source code host (GitLab, a local directory)
+type SourceProvider interface {
+	// checks if the credentials are accepted and have enough permissions
 	CheckPermissions(ctx context.Context) error
 
 	// returns list of repositories, including repositories in subgroups
@@ -16,16 +17,90 @@ type GitlabClient interface {
 	GetFileContent(ctx context.Context, repoURL string, filePath string) ([]byte, error)
 }
 
+// SubmoduleResolver is optionally implemented by a SourceProvider to list git submodules
+type SubmoduleResolver interface {
+	// returns submodules of the repository that can be analyzed as repositories of their own
+	GetSubmodules(ctx context.Context, repoURL string) ([]Submodule, error)
+}
+
+// RevisionResolver is optionally implemented by a SourceProvider to tell which commit a repository is analyzed at
+type RevisionResolver interface {
+	// returns the SHA of the commit the analyzed ref of the repository points at
+	HeadCommit(ctx context.Context, repoURL string) (string, error)
+}
+
+// APICallCounter is optionally implemented by a SourceProvider to tell how many API requests it sent
+type APICallCounter interface {
+	// returns the API requests sent so far keyed by operation, retries included
+	APICalls() map[string]int
+}
+
+// FileContentCoster is optionally implemented by a SourceProvider whose file downloads take more than one API request
+type FileContentCoster interface {
+	// returns the API requests GetFileContent sends for one file of the repository, retries excluded
+	FileContentCalls(repoURL string) int
+}
+
 type RepositoryScanner interface {
 	// detects projects in the repository, scanning for dependency files with
 	DetectProjects(ctx context.Context, repo *Repository) ([]*Project, error)
 }
 
+// SkippedFileReporter is optionally implemented by a RepositoryScanner to account for the dependency files
+// it saw but did not put into any project
+type SkippedFileReporter interface {
+	// returns the files of the repository skipped by the last DetectProjects call
+	SkippedFiles(repoURL string) []SkippedFile
+}
+
+// DependencyFileLister lists the dependency files of a repository without downloading them, to size an analysis
+type DependencyFileLister interface {
+	// returns the dependency files the scan would download, without content, and the ones the scan limits exclude
+	ListDependencyFiles(ctx context.Context, repo *Repository) ([]*DependencyFile, []SkippedFile, error)
+}
+
 type DependencyParser interface {
 	// parses a dependency file and extracts dependencies
 	ParseFile(ctx context.Context, file *DependencyFile) ([]*Dependency, error)
 }
 
+// CapabilityReporter is optionally implemented by a DependencyParser to describe its coverage
+type CapabilityReporter interface {
+	// reports how the parser handles a dependency file and why it yields no dependencies
+	Capability(filePath string) (FileCapability, string)
+}
+
+// FallbackResolver is optionally implemented by a DependencyParser whose lockfiles have a sibling manifest.
+// The manifest's declarations are merged into the lockfile's resolved versions.
+type FallbackResolver interface {
+	// returns the manifest whose declared dependencies stand in for an unparsable lockfile, "" when there is none
+	Fallback(filePath string) string
+}
+
+// ManagedVersionResolver fills dependency versions that are managed outside the declaring manifest
+type ManagedVersionResolver interface {
+	// resolves versions inherited from parent manifests or imported BOMs and returns how many were filled
+	ResolveManagedVersions(ctx context.Context, projects []*Project) int
+}
+
+// LatestVersionResolver looks up the latest releases of dependencies in their package registries
+type LatestVersionResolver interface {
+	// sets the latest released version of dependencies and returns how many were set
+	ResolveLatestVersions(ctx context.Context, projects []*Project) int
+}
+
+// EndOfLifeChecker looks up whether the release cycles of dependency versions still receive support
+type EndOfLifeChecker interface {
+	// marks dependencies whose release cycle reached its end of life and returns how many were marked
+	CheckEndOfLife(ctx context.Context, projects []*Project) int
+}
+
+// VulnerabilityScanner looks up the known vulnerabilities of dependency versions in an advisory database
+type VulnerabilityScanner interface {
+	// sets the vulnerabilities of dependencies and returns how many dependencies are vulnerable
+	ScanVulnerabilities(ctx context.Context, projects []*Project) int
+}
+
 type DependencyClassifier interface {
 	// classifies a list of dependencies
 	ClassifyDependencies(ctx context.Context, dependencies []*Dependency) ([]*Dependency, error)
@@ -33,6 +108,18 @@ type DependencyClassifier interface {
 	IsInternal(ctx context.Context, dependency *Dependency) bool
 }
 
+// Report formats written by a ReportGenerator
+const (
+	FormatHTML     = "html"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatXLSX     = "xlsx"
+	FormatDOT      = "dot"
+	FormatMermaid  = "mermaid"
+	FormatSARIF    = "sarif"
+	FormatMarkdown = "markdown"
+)
+
 type ReportGenerator interface {
 	// generates an HTML report from projects
 	GenerateHTML(ctx context.Context, projects []*Project) error
@@ -40,4 +127,64 @@ type ReportGenerator interface {
 	GenerateCSV(ctx context.Context, projects []*Project) error
 	// generates a JSON report from projects
 	GenerateJSON(ctx context.Context, projects []*Project) error
+	// generates an Excel workbook from projects
+	GenerateXLSX(ctx context.Context, projects []*Project) error
+	// generates a Graphviz DOT graph of projects and their dependencies
+	GenerateDOT(ctx context.Context, projects []*Project) error
+	// generates a Mermaid flowchart of projects and their dependencies
+	GenerateMermaid(ctx context.Context, projects []*Project) error
+	// generates a SARIF log of vulnerable and outdated dependencies and policy violations
+	GenerateSARIF(ctx context.Context, projects []*Project) error
+	// generates a Markdown report of the dependencies and policy violations, for wikis
+	GenerateMarkdown(ctx context.Context, projects []*Project) error
+}
+
+// CoverageRecorder is optionally implemented by a ReportGenerator to show how much of each repository was analyzed
+type CoverageRecorder interface {
+	// records the coverage of the analyzed repositories for the reports generated next
+	RecordCoverage(coverage []RepositoryCoverage)
+}
+
+// ViolationRecorder is optionally implemented by a ReportGenerator to list policy violations in the reports
+type ViolationRecorder interface {
+	// records the policy violations of the analyzed projects for the reports generated next
+	RecordViolations(violations []PolicyViolation)
+}
+
+// IssueRecorder is optionally implemented by a ReportGenerator to list what the analysis failed on
+type IssueRecorder interface {
+	// records the errors and warnings of the analysis for the reports generated next
+	RecordIssues(errors, warnings []AnalysisIssue)
+}
+
+// IncompleteReportMarker is optionally implemented by a ReportGenerator to flag reports of interrupted analyses
+type IncompleteReportMarker interface {
+	// marks the reports generated next as partial, the reason is shown to readers
+	MarkIncomplete(reason string)
+}
+
+// Stages of an analysis followed by a ProgressReporter, in order
+const (
+	StageDiscover = "discover" // Listing the repositories of the configured groups and projects
+	StageScan     = "scan"     // Detecting projects in the repositories
+	StageParse    = "parse"    // Parsing and classifying the dependencies of the projects
+	StageEnrich   = "enrich"   // Resolving managed and latest versions, end of life and advisories
+	StageReport   = "report"   // Writing the reports
+)
+
+// ProgressReporter follows an analysis through its stages, calls may come from several goroutines
+type ProgressReporter interface {
+	// starts a stage of total units, finishing the previous one
+	StartStage(stage string, total int)
+	// records n more finished units of the current stage
+	Advance(n int)
+	// finishes the last stage
+	Finish()
+}
+
+type PolicyCheck interface {
+	// returns the rule family this check enforces, e.g. "pinning"
+	Name() string
+	// evaluates analyzed projects and returns every violation found
+	Evaluate(ctx context.Context, projects []*Project) []PolicyViolation
 }
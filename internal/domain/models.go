@@ -1,23 +1,46 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Repository struct {
-	ID            int    `json:"id"`             // GitLab project ID
-	Name          string `json:"name"`           // "user-service"
-	URL           string `json:"url"`            // GitLab project URL
-	DefaultBranch string `json:"default_branch"` // "main"
-	WebURL        string `json:"web_url"`        // Browser URL
+	ID            int      `json:"id"`               // Provider project ID (GitLab project ID)
+	Name          string   `json:"name"`             // "user-service"
+	URL           string   `json:"url"`              // Project URL or directory passed to the source provider
+	DefaultBranch string   `json:"default_branch"`   // "main"
+	Ref           string   `json:"ref,omitempty"`    // Branch or tag analyzed when configured, e.g. "release-1.2"
+	WebURL        string   `json:"web_url"`          // Browser URL
+	Topics        []string `json:"topics,omitempty"` // Provider topics, e.g. "backend", "payments"
 }
 
 type Project struct {
-	ID              string            `json:"id"`         // Generated: "repo-123-backend-go"
-	Name            string            `json:"name"`       // "User Service Backend"
-	Repository      Repository        `json:"repository"` // Parent repository
-	Path            string            `json:"path"`       // "backend/" or "" for root
-	Language        string            `json:"language"`   // "go", "nodejs", "java", "python"
+	ID              string            `json:"id"`                    // Generated: "repo-123-backend-go"
+	Name            string            `json:"name"`                  // "User Service Backend"
+	Repository      Repository        `json:"repository"`            // Parent repository
+	Path            string            `json:"path"`                  // "backend/" or "" for root
+	Language        string            `json:"language"`              // "go", "nodejs", "java", "python", "rust", "ruby", "dotnet"
+	ModuleName      string            `json:"module_name,omitempty"` // Declared module/package name, if any
+	Service         string            `json:"service,omitempty"`     // Dockerfile/compose service owning the project
 	DependencyFiles []*DependencyFile `json:"dependency_files"`
 	Dependencies    []*Dependency     `json:"dependencies"`
+	HasLockfile     bool              `json:"has_lockfile"` // true when versions are locked by a lockfile
+	Health          *HealthScore      `json:"health,omitempty"`
+	ParentID        string            `json:"parent_id,omitempty"` // Workspace root project ID for members
+	Members         []string          `json:"members,omitempty"`   // Member project IDs for workspace roots
+	Warnings        []FileWarning     `json:"warnings,omitempty"`  // Detected files that yielded no dependencies
+}
+
+type HealthScore struct {
+	Score float64 `json:"score"` // 0 (unhealthy) to 100 (healthy)
+
+	// Penalty ratios (0..1) for each component that contributed to the score
+	Drift           float64 `json:"drift"`           // share of dependencies behind the portfolio max
+	Vulnerabilities float64 `json:"vulnerabilities"` // share of dependencies with known vulnerabilities
+	Deprecated      float64 `json:"deprecated"`      // share of deprecated or end-of-life dependencies
+	Pinning         float64 `json:"pinning"`         // share of floating dependencies
+	Lockfile        float64 `json:"lockfile"`        // 1 when a required lockfile is missing
 }
 
 type DependencyFile struct {
@@ -32,8 +55,238 @@ type Dependency struct {
 	Version       string `json:"version"`        // "v1.9.1"
 	LatestVersion string `json:"latest_version"` // "v1.9.2" - latest available version
 	Constraint    string `json:"constraint"`     // "^1.9.0"
-	MinVersion    string `json:"min_version"`    // "1.9.0"
-	MaxVersion    string `json:"max_version"`    // "2.0.0"
+	MinVersion    string `json:"min_version"`    // "1.9.0", lowest version the constraint allows
+	MaxVersion    string `json:"max_version"`    // "2.0.0", first excluded version, the pin itself when pinned
 	IsInternal    bool   `json:"is_internal"`    // true/false
+	IsFloating    bool   `json:"is_floating"`    // true for "latest", "*" or unbounded ranges
+	IsDeprecated  bool   `json:"is_deprecated"`  // true when the package is deprecated upstream
+	VulnCount     int    `json:"vuln_count"`     // number of known vulnerabilities
 	Ecosystem     string `json:"ecosystem"`      // "go-modules", "npm", "maven"
+
+	// How the package numbers its releases: "semver", "calver" or "other", empty when unknown
+	VersioningScheme string `json:"versioning_scheme,omitempty"`
+
+	// All versions the project resolves this package to, set only when there is more than one
+	ConflictVersions []string `json:"conflict_versions,omitempty"` // ["4.17.20", "4.17.21"]
+
+	// Set when the resolved version falls outside a constraint declared for the package, e.g. a stale lockfile
+	ConstraintMismatch bool   `json:"constraint_mismatch,omitempty"`
+	DeclaredConstraint string `json:"declared_constraint,omitempty"` // "^5.0.0"
+
+	// Name as written in the dependency file when normalization changed it, registries, advisory databases
+	// and SBOMs get this spelling while Name merges the spellings of one package
+	DeclaredName string `json:"declared_name,omitempty"` // "Django", "Newtonsoft.Json"
+
+	// Raw specifier of dependencies not installed from the registry (npm aliases, git URLs, local paths)
+	Source string `json:"source,omitempty"` // "npm:bar@^2", "git+ssh://git@gitlab.company.com/team/lib.git#v1.2.0"
+
+	// Replacement target of a go.mod replace directive, a module path with version or a local directory
+	ReplacedBy string `json:"replaced_by,omitempty"` // "gitlab.company.com/forks/gin v1.9.1-fork.1", "../gin"
+
+	// Why the registry withdrew the version when IsDeprecated is set
+	Deprecation string `json:"deprecation,omitempty"` // "deprecated: use node-fetch instead", "yanked: broken wheel"
+
+	// Set when the release cycle of the version no longer receives support, according to endoflife.date
+	IsEndOfLife bool   `json:"is_end_of_life,omitempty"`
+	EndOfLife   string `json:"end_of_life,omitempty"` // "2024-06-30", end of support of the release cycle when known
+
+	// Set in offline mode when enrichment needed the network and the local cache had no data
+	Stale bool `json:"stale,omitempty"`
+
+	// Declared by the project itself rather than pulled in by another dependency
+	Direct bool `json:"direct"`
+	// Names of the dependencies pulling this one in, from the lockfile dependency graph
+	Parents []string `json:"parents,omitempty"` // ["express", "body-parser"]
+
+	// What the dependency is needed for, one of the Scope constants, empty is runtime
+	Scope string `json:"scope,omitempty"` // "dev"
+
+	// Known advisories affecting the resolved version, VulnCount is their number
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// Dependency scopes
+const (
+	ScopeRuntime  = "runtime"  // Needed to run the project
+	ScopeDev      = "dev"      // Development and build tooling: devDependencies, Poetry groups, annotation processors
+	ScopeTest     = "test"     // Tests only: Maven test scope, Gradle test configurations
+	ScopeOptional = "optional" // Optional features: optionalDependencies, Python extras, optional Maven dependencies
+)
+
+// DependencyScope returns the scope of the dependency, runtime when none was recorded
+func (d *Dependency) DependencyScope() string {
+	if d.Scope == "" {
+		return ScopeRuntime
+	}
+	return d.Scope
+}
+
+// LookupName returns the name the package is looked up by in registries and advisory databases, as declared
+func (d *Dependency) LookupName() string {
+	if d.DeclaredName != "" {
+		return d.DeclaredName
+	}
+	return d.Name
+}
+
+// IsDevelopment reports whether the dependency is only needed to develop or test the project
+func (d *Dependency) IsDevelopment() bool {
+	return d.Scope == ScopeDev || d.Scope == ScopeTest
+}
+
+// IsPinned reports whether the declared constraint allows a single version
+func (d *Dependency) IsPinned() bool {
+	return d.MinVersion != "" && d.MinVersion == d.MaxVersion
+}
+
+// Vulnerability severities from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+// Vulnerability is a published security advisory affecting a dependency version
+type Vulnerability struct {
+	ID       string   `json:"id"`                // "GHSA-35jh-r3h4-6jhm"
+	Aliases  []string `json:"aliases,omitempty"` // ["CVE-2021-23337"]
+	Severity string   `json:"severity"`          // One of the Severity constants
+	Summary  string   `json:"summary,omitempty"` // "Command Injection in lodash"
+}
+
+// CVE returns the CVE identifier of the advisory, or its own ID when it has none
+func (v Vulnerability) CVE() string {
+	if strings.HasPrefix(v.ID, "CVE-") {
+		return v.ID
+	}
+	for _, alias := range v.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			return alias
+		}
+	}
+	return v.ID
+}
+
+// SeverityRank orders severities, higher is more severe and unknown severities rank lowest
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MaxSeverity returns the highest severity of the dependency's vulnerabilities, empty without vulnerabilities
+func (d *Dependency) MaxSeverity() string {
+	maxSeverity := ""
+	for _, vulnerability := range d.Vulnerabilities {
+		if maxSeverity == "" || SeverityRank(vulnerability.Severity) > SeverityRank(maxSeverity) {
+			maxSeverity = vulnerability.Severity
+		}
+	}
+	return maxSeverity
+}
+
+// Annotation is team knowledge about a dependency carried into the report
+type Annotation struct {
+	Note        string `json:"note,omitempty"        yaml:"note"`        // "Unmaintained since 2023"
+	Owner       string `json:"owner,omitempty"       yaml:"owner"`       // "platform-team"
+	Replacement string `json:"replacement,omitempty" yaml:"replacement"` // "use internal http-kit instead of resty"
+}
+
+type PolicyViolation struct {
+	Rule       string `json:"rule"`                 // "pinning.floating"
+	ProjectID  string `json:"project_id"`           // "repo-123-backend-go"
+	Dependency string `json:"dependency,omitempty"` // "express", empty for project-level rules
+	Message    string `json:"message"`              // Human readable explanation
+}
+
+type Submodule struct {
+	Path string `json:"path"` // "libs/shared"
+	URL  string `json:"url"`  // "https://gitlab.com/group/shared"
+}
+
+// FileCapability describes how the parser handles a detected dependency file
+type FileCapability string
+
+const (
+	FileParsed      FileCapability = "parsed"      // dependencies are extracted from the file
+	FileIgnored     FileCapability = "ignored"     // the file is understood but carries no dependencies (go.sum)
+	FileUnsupported FileCapability = "unsupported" // no parser exists for the file (setup.py)
+)
+
+type FileWarning struct {
+	File       string         `json:"file"`               // "backend/build.gradle"
+	Capability FileCapability `json:"capability"`         // "unsupported", "ignored" or "parsed" when parsing failed
+	Message    string         `json:"message"`            // Human readable explanation
+	Fallback   string         `json:"fallback,omitempty"` // Sibling manifest used instead of the unparsable lockfile
+}
+
+// CoverageGapReason tells why a detected dependency file was not analyzed
+type CoverageGapReason string
+
+const (
+	GapExcluded        CoverageGapReason = "excluded"         // outside the scan limits (depth, ignored or vendored dirs)
+	GapUnavailable     CoverageGapReason = "unavailable"      // the file content could not be downloaded
+	GapUnknownLanguage CoverageGapReason = "unknown_language" // no language parser claims the file
+	GapUnsupported     CoverageGapReason = "unsupported"      // no parser exists for the file (setup.py)
+	GapUnparsed        CoverageGapReason = "unparsed"         // the parser rejected the file
+)
+
+// SkippedFile is a dependency file the scanner saw but did not hand to the parser
+type SkippedFile struct {
+	File     string            `json:"file"`     // "node_modules/left-pad/package.json"
+	Language string            `json:"language"` // Detected from the file name
+	Reason   CoverageGapReason `json:"reason"`   // "excluded" or "unavailable"
+}
+
+// CoverageGap is a dependency file of a repository that did not contribute to the report
+type CoverageGap struct {
+	File    string            `json:"file"`
+	Reason  CoverageGapReason `json:"reason"`
+	Message string            `json:"message,omitempty"` // Parser error or unsupported file explanation
+}
+
+// RepositoryCoverage is the share of a repository's dependency files that the analysis covered
+type RepositoryCoverage struct {
+	Repository Repository    `json:"repository"`
+	Analyzed   int           `json:"analyzed"`       // Dependency files handed to the parser and understood
+	Excluded   int           `json:"excluded"`       // Skipped on purpose by the scan limits, not counted as gaps
+	Gaps       []CoverageGap `json:"gaps,omitempty"` // Dependency files that should have been analyzed but were not
+}
+
+// AnalysisIssue is a repository, project or dependency file the analysis failed on or only partly understood
+type AnalysisIssue struct {
+	Repository Repository `json:"repository"`
+	Project    string     `json:"project,omitempty"` // Project directory, "." for the repository root
+	File       string     `json:"file,omitempty"`    // Dependency file, absent for repository and project issues
+	Stage      string     `json:"stage"`             // StageScan or StageParse
+	Message    string     `json:"message"`
+}
+
+// Percent returns the analyzed share of the dependency files that were not excluded on purpose
+func (c RepositoryCoverage) Percent() float64 {
+	total := c.Analyzed + len(c.Gaps)
+	if total == 0 {
+		return 100
+	}
+	return float64(c.Analyzed) * 100 / float64(total)
+}
+
+type DependencyChange struct {
+	ProjectID   string `json:"project_id"`            // "repo-123-backend-go"
+	ProjectName string `json:"project_name"`          // "user-service Go (backend)"
+	Dependency  string `json:"dependency"`            // "github.com/gin-gonic/gin"
+	Kind        string `json:"kind"`                  // "added", "removed", "upgraded", "downgraded"
+	OldVersion  string `json:"old_version,omitempty"` // "v1.9.0"
+	NewVersion  string `json:"new_version,omitempty"` // "v1.9.1"
 }
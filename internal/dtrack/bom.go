@@ -0,0 +1,191 @@
+// Package dtrack uploads the dependencies of analyzed projects to OWASP Dependency-Track as CycloneDX SBOMs
+package dtrack
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// specVersion is the CycloneDX specification the SBOMs follow, supported since Dependency-Track 4.11
+const specVersion = "1.5"
+
+// BOM is a CycloneDX SBOM in its JSON encoding
+type BOM struct {
+	BOMFormat    string       `json:"bomFormat"`
+	SpecVersion  string       `json:"specVersion"`
+	Version      int          `json:"version"`
+	Metadata     Metadata     `json:"metadata"`
+	Components   []Component  `json:"components"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Metadata describes the SBOM: when and by what it was made, and the project it lists the components of
+type Metadata struct {
+	Timestamp string    `json:"timestamp"`
+	Tools     Tools     `json:"tools"`
+	Component Component `json:"component"`
+}
+
+// Tools lists the tools that made the SBOM
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component is a package, or the analyzed project in the metadata
+type Component struct {
+	Type    string `json:"type"` // "application" or "library"
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Group   string `json:"group,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Scope   string `json:"scope,omitempty"` // "required", "optional" or "excluded"
+	PURL    string `json:"purl,omitempty"`
+}
+
+// Dependency lists the components a component depends on directly
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// purlTypes maps dependency ecosystems to package URL types
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var purlTypes = map[string]string{
+	"go-modules": "golang",
+	"npm":        "npm",
+	"pip":        "pypi",
+	"pypi":       "pypi",
+	"maven":      "maven",
+	"cargo":      "cargo",
+	"bundler":    "gem",
+	"nuget":      "nuget",
+}
+
+// NewBOM lists the dependencies of a project, made at a time by the given version of the tool.
+// Direct dependencies hang off the project and transitive ones off their parents, as the lockfile graph says.
+func NewBOM(project *domain.Project, toolVersion string, at time.Time) BOM {
+	bom := BOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: specVersion,
+		Version:     1,
+		Metadata: Metadata{
+			Timestamp: at.UTC().Format(time.RFC3339),
+			Tools: Tools{Components: []Component{
+				{Type: "application", Name: "di-matrix-cli", Version: toolVersion},
+			}},
+			Component: Component{Type: "application", BOMRef: project.ID, Name: project.Name},
+		},
+		Components:   []Component{},
+		Dependencies: []Dependency{},
+	}
+
+	refs := make(map[string]string, len(project.Dependencies)) // Dependency names to their component
+	edges := map[string][]string{project.ID: {}}
+	order := []string{project.ID}
+	for _, dep := range project.Dependencies {
+		component := newComponent(dep)
+		if _, seen := edges[component.BOMRef]; seen {
+			continue
+		}
+		bom.Components = append(bom.Components, component)
+		edges[component.BOMRef] = []string{}
+		order = append(order, component.BOMRef)
+		if _, ok := refs[dep.Name]; !ok {
+			refs[dep.Name] = component.BOMRef
+		}
+	}
+
+	for _, dep := range project.Dependencies {
+		ref := bomRef(dep)
+		parents := []string{project.ID}
+		if !dep.Direct && len(dep.Parents) > 0 {
+			parents = parents[:0]
+			for _, parent := range dep.Parents {
+				if parentRef, ok := refs[parent]; ok {
+					parents = append(parents, parentRef)
+				}
+			}
+		}
+		for _, parent := range parents {
+			if !slices.Contains(edges[parent], ref) {
+				edges[parent] = append(edges[parent], ref)
+			}
+		}
+	}
+	for _, ref := range order {
+		bom.Dependencies = append(bom.Dependencies, Dependency{Ref: ref, DependsOn: edges[ref]})
+	}
+	return bom
+}
+
+// newComponent returns the component of a dependency. Only exact versions go into the package URL, so
+// Dependency-Track matches advisories against releases rather than ranges.
+func newComponent(dep *domain.Dependency) Component {
+	// Package URLs carry the name as declared, registries may not know its normalized spelling
+	name := dep.LookupName()
+	component := Component{Type: "library", Name: name, Version: dep.Version, Scope: scope(dep.Scope)}
+	switch dep.Ecosystem {
+	case "maven":
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			component.Group, component.Name = group, artifact
+		}
+	case "npm":
+		if group, name, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(group, "@") {
+			component.Group, component.Name = group, name
+		}
+	}
+
+	// Git, local and aliased dependencies are not the registry package of their name
+	if purlType, ok := purlTypes[dep.Ecosystem]; ok && dep.Source == "" {
+		component.PURL = purl(purlType, component.Group, component.Name, dep.Version)
+	}
+	component.BOMRef = bomRef(dep)
+	return component
+}
+
+// bomRef identifies the component of a dependency within an SBOM
+func bomRef(dep *domain.Dependency) string {
+	return dep.Ecosystem + ":" + dep.Name + "@" + dep.Version
+}
+
+// purl returns the package URL of a package, without a version unless it is exact
+func purl(purlType, group, name, release string) string {
+	if purlType == "pypi" {
+		// PyPI names are case insensitive and treat underscores as dashes
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+	segments := strings.Split(name, "/")
+	if group != "" {
+		segments = append([]string{group}, segments...)
+	}
+	for i, segment := range segments {
+		segments[i] = purlEscape(segment)
+	}
+	result := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version.IsExact(release) {
+		result += "@" + purlEscape(release)
+	}
+	return result
+}
+
+// purlEscape percent-encodes a package URL segment, including the @ of npm scopes and the + of build metadata
+func purlEscape(segment string) string {
+	return strings.NewReplacer("@", "%40", "+", "%2B").Replace(url.PathEscape(segment))
+}
+
+// scope returns the CycloneDX scope of a dependency scope: development and test tooling is not shipped
+func scope(depScope string) string {
+	switch depScope {
+	case domain.ScopeDev, domain.ScopeTest:
+		return "excluded"
+	case domain.ScopeOptional:
+		return "optional"
+	default:
+		return "required"
+	}
+}
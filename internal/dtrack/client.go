@@ -0,0 +1,87 @@
+package dtrack
+
+import (
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/retry"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client uploads SBOMs to the REST API of a Dependency-Track server under a retry policy
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+	policy  retry.Policy
+}
+
+// NewClient creates a client of the Dependency-Track API server at baseURL, authenticated with an API key
+func NewClient(baseURL, apiKey string, policy retry.Policy) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, http: &http.Client{}, policy: policy}
+}
+
+// bomUpload is the body of PUT /api/v1/bom
+type bomUpload struct {
+	ProjectName    string `json:"projectName"`
+	ProjectVersion string `json:"projectVersion"`
+	AutoCreate     bool   `json:"autoCreate"`
+	BOM            string `json:"bom"` // Base64 encoded
+}
+
+// UploadBOM uploads the SBOM of a project version, creating the project when Dependency-Track does not know it yet,
+// which takes the PROJECT_CREATION_UPLOAD permission besides BOM_UPLOAD. Dependency-Track processes SBOMs
+// asynchronously, the returned token identifies the processing.
+func (c *Client) UploadBOM(ctx context.Context, projectName, projectVersion string, bom BOM) (string, error) {
+	encoded, err := json.Marshal(bom)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+	payload, err := json.Marshal(bomUpload{
+		ProjectName:    projectName,
+		ProjectVersion: projectVersion,
+		AutoCreate:     true,
+		BOM:            base64.StdEncoding.EncodeToString(encoded),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SBOM upload: %w", err)
+	}
+
+	var response struct {
+		Token string `json:"token"`
+	}
+	err = c.policy.Do(ctx, retry.IsTransientHTTP, func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/api/v1/bom",
+			bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Api-Key", c.apiKey)
+
+		resp, err := c.http.Do(request)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &retry.StatusError{
+				Status: resp.StatusCode, URL: request.URL.String(), Body: strings.TrimSpace(string(content)),
+			}
+		}
+		return json.Unmarshal(content, &response)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload SBOM of %s: %w", projectName, err)
+	}
+	return response.Token, nil
+}
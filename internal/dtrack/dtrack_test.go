@@ -0,0 +1,121 @@
+package dtrack_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/dtrack"
+	"di-matrix-cli/internal/retry"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProject() *domain.Project {
+	return &domain.Project{
+		ID:   "repo-1-web",
+		Name: "Web",
+		Dependencies: []*domain.Dependency{
+			{Name: "@babel/core", Version: "7.24.0", Ecosystem: "npm", Direct: true, Scope: domain.ScopeDev},
+			{Name: "express", Version: "^4.18.0", Ecosystem: "npm", Direct: true},
+			{Name: "body-parser", Version: "1.20.2", Ecosystem: "npm", Parents: []string{"express"}},
+			{Name: "org.slf4j:slf4j-api", Version: "2.0.13", Ecosystem: "maven", Direct: true},
+			{Name: "my-fork", Version: "1.0.0", Ecosystem: "npm", Direct: true, Source: "git"},
+		},
+	}
+}
+
+func TestNewBOM(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bom := dtrack.NewBOM(testProject(), "1.2.3", at)
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "2026-03-01T12:00:00Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "Web", bom.Metadata.Component.Name)
+	assert.Equal(t, "1.2.3", bom.Metadata.Tools.Components[0].Version)
+	require.Len(t, bom.Components, 5)
+
+	babel := bom.Components[0]
+	assert.Equal(t, "@babel", babel.Group)
+	assert.Equal(t, "core", babel.Name)
+	assert.Equal(t, "excluded", babel.Scope)
+	assert.Equal(t, "pkg:npm/%40babel/core@7.24.0", babel.PURL)
+
+	assert.Equal(t, "pkg:npm/express", bom.Components[1].PURL, "ranges stay out of the package URL")
+	assert.Equal(t, "pkg:maven/org.slf4j/slf4j-api@2.0.13", bom.Components[3].PURL)
+	assert.Empty(t, bom.Components[4].PURL, "git dependencies are not the registry package")
+
+	edges := make(map[string][]string, len(bom.Dependencies))
+	for _, dependency := range bom.Dependencies {
+		edges[dependency.Ref] = dependency.DependsOn
+	}
+	assert.Len(t, edges["repo-1-web"], 4)
+	assert.Equal(t, []string{"npm:body-parser@1.20.2"}, edges["npm:express@^4.18.0"])
+}
+
+func TestUploadBOM(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/bom", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		var upload struct {
+			ProjectName    string `json:"projectName"`
+			ProjectVersion string `json:"projectVersion"`
+			AutoCreate     bool   `json:"autoCreate"`
+			BOM            string `json:"bom"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&upload))
+		assert.Equal(t, "Web", upload.ProjectName)
+		assert.Equal(t, "latest", upload.ProjectVersion)
+		assert.True(t, upload.AutoCreate)
+		decoded, err := base64.StdEncoding.DecodeString(upload.BOM)
+		assert.NoError(t, err)
+		assert.Contains(t, string(decoded), `"bomFormat":"CycloneDX"`)
+
+		_, _ = w.Write([]byte(`{"token":"abc-123"}`))
+	}))
+	defer server.Close()
+
+	client := dtrack.NewClient(server.URL+"/", "secret", retry.Policy{Retries: 1})
+	bom := dtrack.NewBOM(testProject(), "dev", time.Now())
+	token, err := client.UploadBOM(context.Background(), "Web", "latest", bom)
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", token)
+	assert.Equal(t, int32(2), attempts.Load(), "a 503 is retried")
+}
+
+func TestUploadBOMRejected(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "missing PROJECT_CREATION_UPLOAD", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := dtrack.NewClient(server.URL, "secret", retry.Policy{Retries: 3})
+	_, err := client.UploadBOM(context.Background(), "Web", "latest", dtrack.NewBOM(testProject(), "dev", time.Now()))
+	require.Error(t, err)
+	var status *retry.StatusError
+	require.ErrorAs(t, err, &status)
+	assert.Equal(t, http.StatusForbidden, status.Status)
+	assert.Equal(t, "missing PROJECT_CREATION_UPLOAD", status.Body)
+	assert.Equal(t, int32(1), attempts.Load(), "client errors are not retried")
+}
@@ -0,0 +1,222 @@
+package eol
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/version"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultTTL is how long the release cycles of a product are reused before endoflife.date is asked again
+const DefaultTTL = 24 * time.Hour
+
+// cacheNamespace groups release cycles in the on-disk cache
+const cacheNamespace = "eol"
+
+// defaultProducts maps frameworks and runtimes shipped as dependencies to their endoflife.date product
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var defaultProducts = [][2]string{
+	{"@angular/core", "angular"},
+	{"react", "react"},
+	{"vue", "vue"},
+	{"electron", "electron"},
+	{"/^[Dd]jango$/", "django"},
+	{"rails", "rails"},
+	{"org.springframework.boot:spring-boot*", "spring-boot"},
+	{"org.springframework:spring-core", "spring-framework"},
+	{"org.apache.logging.log4j:log4j-core", "log4j"},
+	{"Microsoft.AspNetCore.*", "dotnet"},
+}
+
+// Product maps dependencies to the endoflife.date product whose release cycles they follow
+type Product struct {
+	Dependency string // Name glob or regular expression enclosed in slashes, "org.springframework.boot:spring-boot*"
+	Product    string // "spring-boot"
+	names      *exclude.Matcher
+}
+
+// NewProduct maps dependencies with a name matching the glob or /regex/ dependency to product
+func NewProduct(dependency, product string) (Product, error) {
+	if strings.TrimSpace(product) == "" {
+		return Product{}, fmt.Errorf("dependency %q: missing product", dependency)
+	}
+	names, err := exclude.Compile([]string{dependency})
+	if err != nil {
+		return Product{}, fmt.Errorf("dependency %q: %w", dependency, err)
+	}
+	if names.Empty() {
+		return Product{}, fmt.Errorf("product %q: missing dependency", product)
+	}
+	return Product{Dependency: dependency, Product: product, names: names}, nil
+}
+
+// cacheEntry is cached release cycles, none record a product endoflife.date does not track
+type cacheEntry struct {
+	Cycles    []Cycle   `json:"cycles"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Checker marks dependencies whose release cycle reached its end of life according to endoflife.date.
+// Every product is looked up once per run, results are kept in the on-disk cache for later and offline runs.
+type Checker struct {
+	client   *Client
+	products []Product
+	logger   *zap.Logger
+	store    *cache.Store
+	ttl      time.Duration
+	offline  bool
+}
+
+// NewChecker creates a checker querying client for the built-in frameworks and runtimes
+func NewChecker(client *Client, logger *zap.Logger) *Checker {
+	checker := &Checker{client: client, logger: logger, ttl: DefaultTTL}
+	for _, mapping := range defaultProducts {
+		product, err := NewProduct(mapping[0], mapping[1])
+		if err != nil {
+			panic(err) // The built-in table is static
+		}
+		checker.products = append(checker.products, product)
+	}
+	return checker
+}
+
+// WithProducts maps more dependencies to products, they take precedence over the built-in mappings
+func (c *Checker) WithProducts(products ...Product) *Checker {
+	c.products = slices.Concat(products, c.products)
+	return c
+}
+
+// WithCache keeps release cycles in store for ttl, offline runs use cached cycles of any age
+func (c *Checker) WithCache(store *cache.Store, ttl time.Duration) *Checker {
+	c.store = store
+	c.ttl = ttl
+	return c
+}
+
+// WithOffline serves release cycles from the cache only, dependencies missing from it are marked stale
+func (c *Checker) WithOffline(offline bool) *Checker {
+	c.offline = offline
+	return c
+}
+
+// CheckEndOfLife marks external dependencies of a mapped product whose release cycle no longer receives support
+// and returns how many were marked. Versions that are ranges cannot be placed in a cycle and are skipped.
+func (c *Checker) CheckEndOfLife(ctx context.Context, projects []*domain.Project) int {
+	cycles := make(map[string][]Cycle)
+	stale := make(map[string]bool)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			product, ok := c.productFor(dep)
+			if !ok {
+				continue
+			}
+			if _, seen := cycles[product]; !seen && !stale[product] {
+				cycles[product], stale[product] = c.lookup(ctx, product)
+			}
+		}
+	}
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	ended := 0
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			product, ok := c.productFor(dep)
+			if !ok {
+				continue
+			}
+			if stale[product] {
+				dep.Stale = true
+				continue
+			}
+			cycle, found := cycleOf(cycles[product], dep.Version)
+			if found && cycle.EndedBy(today) {
+				dep.IsEndOfLife = true
+				dep.EndOfLife = cycle.EndOfLife
+				ended++
+			}
+		}
+	}
+
+	c.logger.Info("Checked release cycles for end of life",
+		zap.Int("product_count", len(cycles)),
+		zap.Int("end_of_life_dependencies", ended))
+	return ended
+}
+
+// productFor returns the product of a dependency, false for dependencies that are not checked
+func (c *Checker) productFor(dep *domain.Dependency) (string, bool) {
+	if dep.IsInternal || dep.IsFloating || dep.Source != "" || !version.IsExact(dep.Version) {
+		return "", false
+	}
+	for _, product := range c.products {
+		if product.names.Matches(dep.Name) {
+			return product.Product, true
+		}
+	}
+	return "", false
+}
+
+// cycleOf returns the most specific cycle the version belongs to: "3.2.5" is in "3.2" rather than "3"
+func cycleOf(cycles []Cycle, version string) (Cycle, bool) {
+	version = strings.TrimPrefix(version, "v")
+	var best Cycle
+	found := false
+	for _, cycle := range cycles {
+		inCycle := version == cycle.Name ||
+			strings.HasPrefix(version, cycle.Name+".") || strings.HasPrefix(version, cycle.Name+"-")
+		if inCycle && (!found || len(cycle.Name) > len(best.Name)) {
+			best, found = cycle, true
+		}
+	}
+	return best, found
+}
+
+// lookup returns the release cycles of the product from the cache or endoflife.date, and whether they are stale
+// because offline mode found nothing in the cache. A failed lookup falls back to an expired cache entry.
+func (c *Checker) lookup(ctx context.Context, product string) ([]Cycle, bool) {
+	var cached cacheEntry
+	hit := c.cached(product, &cached)
+	if hit && (c.offline || time.Since(cached.FetchedAt) < c.ttl) {
+		return cached.Cycles, false
+	}
+	if c.offline {
+		return nil, true
+	}
+
+	cycles, err := c.client.Cycles(ctx, product)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		c.logger.Debug("Failed to look up release cycles", zap.String("product", product), zap.Error(err))
+		return cached.Cycles, false
+	}
+	c.put(product, cacheEntry{Cycles: cycles, FetchedAt: time.Now()})
+	return cycles, false
+}
+
+// cached reads the cache entry of the product
+func (c *Checker) cached(product string, entry *cacheEntry) bool {
+	if c.store == nil {
+		return false
+	}
+	data, ok := c.store.Get(cacheNamespace, product)
+	return ok && json.Unmarshal(data, entry) == nil
+}
+
+// put writes the cache entry of the product, a failed write only costs a lookup next time
+func (c *Checker) put(product string, entry cacheEntry) {
+	if c.store == nil {
+		return
+	}
+	if data, err := json.Marshal(entry); err == nil {
+		_ = c.store.Put(cacheNamespace, product, data)
+	}
+}
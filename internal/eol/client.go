@@ -0,0 +1,103 @@
+package eol
+
+import (
+	"context"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the public endoflife.date API
+const DefaultBaseURL = "https://endoflife.date"
+
+// ErrNotFound is returned for products endoflife.date does not track
+var ErrNotFound = errors.New("product not found")
+
+// Cycle is a release cycle of a product, "3.2" of Spring Boot or "18" of React
+type Cycle struct {
+	Name      string `json:"cycle"`
+	Ended     bool   `json:"ended,omitempty"`    // Support ended without a known date
+	EndOfLife string `json:"eol_date,omitempty"` // "2024-06-30", end of support, past or announced
+}
+
+// EndedBy reports whether support of the cycle ended on or before the day ("2006-01-02")
+func (c Cycle) EndedBy(day string) bool {
+	return c.Ended || (c.EndOfLife != "" && c.EndOfLife <= day)
+}
+
+// Client queries the endoflife.date API under a retry policy
+type Client struct {
+	baseURL string
+	http    *http.Client
+	policy  retry.Policy
+}
+
+// isTransient reports whether a failed request may succeed when retried, unknown products are final
+func isTransient(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return false
+	}
+	return retry.IsTransientHTTP(err)
+}
+
+// NewClient creates a client of an endoflife.date API such as DefaultBaseURL
+func NewClient(baseURL string, policy retry.Policy) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: &http.Client{}, policy: policy}
+}
+
+// Cycles returns the release cycles of the product, ErrNotFound when endoflife.date does not track it
+func (c *Client) Cycles(ctx context.Context, product string) ([]Cycle, error) {
+	target := c.baseURL + "/api/" + url.PathEscape(product) + ".json"
+	var content []byte
+	err := c.policy.Do(ctx, isTransient, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return ErrNotFound
+		case resp.StatusCode != http.StatusOK:
+			return &retry.StatusError{Status: resp.StatusCode, URL: target}
+		}
+		content, err = io.ReadAll(resp.Body)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cycles are strings or numbers, end of life is a date or a boolean
+	var documents []struct {
+		Cycle json.RawMessage `json:"cycle"`
+		EOL   json.RawMessage `json:"eol"`
+	}
+	if err := json.Unmarshal(content, &documents); err != nil {
+		return nil, fmt.Errorf("invalid release cycles of %s: %w", product, err)
+	}
+	cycles := make([]Cycle, 0, len(documents))
+	for _, document := range documents {
+		cycle := Cycle{Name: strings.Trim(string(document.Cycle), `"`)}
+		var date string
+		if err := json.Unmarshal(document.EOL, &date); err == nil {
+			cycle.EndOfLife = date
+		} else {
+			cycle.Ended = string(document.EOL) == "true"
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles, nil
+}
@@ -0,0 +1,154 @@
+package eol_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/eol"
+	"di-matrix-cli/internal/retry"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newEndOfLifeServer serves release cycles of Spring Boot and React and counts requests
+func newEndOfLifeServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/spring-boot.json", func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[
+			{"cycle":"3.3","eol":"2999-06-30"},
+			{"cycle":"3","eol":false},
+			{"cycle":"2.7","eol":"2023-11-24"},
+			{"cycle":"2.6","eol":true}
+		]`))
+	})
+	mux.HandleFunc("/api/react.json", func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[{"cycle":18,"eol":false},{"cycle":"16","eol":true}]`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Cycles(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newEndOfLifeServer(t, &requests)
+	client := eol.NewClient(server.URL+"/", retry.Policy{})
+
+	cycles, err := client.Cycles(context.Background(), "spring-boot")
+	require.NoError(t, err)
+	assert.Equal(t, []eol.Cycle{
+		{Name: "3.3", EndOfLife: "2999-06-30"},
+		{Name: "3"},
+		{Name: "2.7", EndOfLife: "2023-11-24"},
+		{Name: "2.6", Ended: true},
+	}, cycles)
+
+	cycles, err = client.Cycles(context.Background(), "react")
+	require.NoError(t, err)
+	assert.Equal(t, "18", cycles[0].Name, "numeric cycles are read as names")
+
+	_, err = client.Cycles(context.Background(), "unknown")
+	assert.True(t, errors.Is(err, eol.ErrNotFound))
+}
+
+func TestCycle_EndedBy(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, eol.Cycle{Ended: true}.EndedBy("2025-01-01"))
+	assert.True(t, eol.Cycle{EndOfLife: "2025-01-01"}.EndedBy("2025-01-01"))
+	assert.False(t, eol.Cycle{EndOfLife: "2025-01-02"}.EndedBy("2025-01-01"), "announced end of life")
+	assert.False(t, eol.Cycle{}.EndedBy("2025-01-01"))
+}
+
+func springProjects() []*domain.Project {
+	return []*domain.Project{
+		{ID: "billing", Dependencies: []*domain.Dependency{
+			{Name: "org.springframework.boot:spring-boot-starter-web", Version: "2.7.18", Ecosystem: "maven"},
+			{Name: "org.springframework.boot:spring-boot", Version: "3.3.1", Ecosystem: "maven"},
+			{Name: "com.company:platform-bom", Version: "2.6.0", Ecosystem: "maven"},
+		}},
+		{ID: "web", Dependencies: []*domain.Dependency{
+			{Name: "react", Version: "16.14.0", Ecosystem: "npm"},
+			{Name: "react", Version: "^16.14.0", Ecosystem: "npm"},
+			{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+		}},
+	}
+}
+
+func TestChecker_CheckEndOfLife(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newEndOfLifeServer(t, &requests)
+	platform, err := eol.NewProduct("com.company:platform-*", "spring-boot")
+	require.NoError(t, err)
+
+	projects := springProjects()
+	ended := eol.NewChecker(eol.NewClient(server.URL, retry.Policy{}), zap.NewNop()).
+		WithProducts(platform).
+		CheckEndOfLife(context.Background(), projects)
+
+	assert.Equal(t, 3, ended)
+	starter := projects[0].Dependencies[0]
+	assert.True(t, starter.IsEndOfLife, "starters follow the Spring Boot cycles")
+	assert.Equal(t, "2023-11-24", starter.EndOfLife)
+	assert.False(t, projects[0].Dependencies[1].IsEndOfLife, "3.3 is in its most specific cycle, supported")
+	assert.True(t, projects[0].Dependencies[2].IsEndOfLife, "configured mappings apply")
+	assert.Empty(t, projects[0].Dependencies[2].EndOfLife, "the cycle ended without a date")
+	assert.True(t, projects[1].Dependencies[0].IsEndOfLife)
+	assert.False(t, projects[1].Dependencies[1].IsEndOfLife, "ranges are not placed in a cycle")
+	assert.False(t, projects[1].Dependencies[2].IsEndOfLife, "unmapped dependencies are not checked")
+	assert.Equal(t, int32(2), requests.Load(), "each product is looked up once")
+}
+
+func TestChecker_Offline(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newEndOfLifeServer(t, &requests)
+	store := cache.New(t.TempDir())
+	eol.NewChecker(eol.NewClient(server.URL, retry.Policy{}), zap.NewNop()).
+		WithCache(store, time.Hour).
+		CheckEndOfLife(context.Background(), springProjects()[:1])
+	require.Equal(t, int32(1), requests.Load())
+
+	projects := springProjects()
+	eol.NewChecker(eol.NewClient(server.URL, retry.Policy{}), zap.NewNop()).
+		WithCache(store, 0).
+		WithOffline(true).
+		CheckEndOfLife(context.Background(), projects)
+
+	assert.Equal(t, int32(1), requests.Load(), "offline mode never calls endoflife.date")
+	assert.True(t, projects[0].Dependencies[0].IsEndOfLife, "cached cycles of any age are used")
+	assert.True(t, projects[1].Dependencies[0].Stale, "products missing from the cache are stale")
+	assert.False(t, projects[1].Dependencies[0].IsEndOfLife)
+}
+
+func TestNewProduct(t *testing.T) {
+	t.Parallel()
+
+	_, err := eol.NewProduct("/[/", "spring-boot")
+	assert.Error(t, err)
+	_, err = eol.NewProduct("", "spring-boot")
+	assert.Error(t, err)
+	_, err = eol.NewProduct("com.company:platform", " ")
+	assert.Error(t, err)
+}
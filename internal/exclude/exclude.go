@@ -0,0 +1,64 @@
+package exclude
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Matcher matches dependency names against exclusion patterns
+type Matcher struct {
+	globs   []string
+	regexps []*regexp.Regexp
+}
+
+// Compile builds a matcher from patterns. Patterns enclosed in slashes are regular expressions
+// ("/^types-.*$/"), anything else is a glob matched against the whole name ("@types/*", "types-*").
+func Compile(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expression, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+			}
+			m.regexps = append(m.regexps, expression)
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		m.globs = append(m.globs, pattern)
+	}
+	return m, nil
+}
+
+// Empty reports whether the matcher has no patterns, a nil matcher is empty
+func (m *Matcher) Empty() bool {
+	return m == nil || (len(m.globs) == 0 && len(m.regexps) == 0)
+}
+
+// Matches reports whether a dependency name matches one of the patterns
+func (m *Matcher) Matches(name string) bool {
+	if m.Empty() {
+		return false
+	}
+	for _, glob := range m.globs {
+		if matched, err := path.Match(glob, name); err == nil && matched {
+			return true
+		}
+	}
+	for _, expression := range m.regexps {
+		if expression.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package exclude_test
+
+import (
+	"di-matrix-cli/internal/exclude"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_Matches(t *testing.T) {
+	t.Parallel()
+
+	m, err := exclude.Compile([]string{"@types/*", "types-*", "/^github\\.com/company/fixtures(/|$)/", " "})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		excluded bool
+	}{
+		{"@types/node", true},
+		{"@types/react-dom", true},
+		{"@typescript-eslint/parser", false},
+		{"types-requests", true},
+		{"requests", false},
+		{"github.com/company/fixtures", true},
+		{"github.com/company/fixtures/http", true},
+		{"github.com/company/fixtures-gen", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.excluded, m.Matches(tt.name), tt.name)
+	}
+}
+
+func TestMatcher_Empty(t *testing.T) {
+	t.Parallel()
+
+	var nilMatcher *exclude.Matcher
+	assert.True(t, nilMatcher.Empty())
+	assert.False(t, nilMatcher.Matches("lodash"))
+
+	m, err := exclude.Compile(nil)
+	require.NoError(t, err)
+	assert.True(t, m.Empty())
+}
+
+func TestCompile_InvalidPatterns(t *testing.T) {
+	t.Parallel()
+
+	_, err := exclude.Compile([]string{"/(unclosed/"})
+	require.Error(t, err)
+
+	_, err = exclude.Compile([]string{"[a-"})
+	require.Error(t, err)
+}
@@ -0,0 +1,177 @@
+package fakegitlab
+
+// DemoGroup is the top-level group of the demo repositories, its URL covers every one of them
+const DemoGroup = "acme"
+
+// DemoInternalPatterns classify the demo's own libraries as internal
+var DemoInternalPatterns = []string{"gitlab.acme.example/", "@acme/", "com.acme."} //nolint:gochecknoglobals // Fixture data
+
+// Demo returns fixture repositories of a small organization: Go, Node.js, Python and Java services
+// that share libraries at drifting versions, a monorepo and a floating dependency without lockfile.
+func Demo() []Repository {
+	return []Repository{
+		{
+			Path: "acme/platform/billing-service",
+			Files: map[string]string{
+				"README.md": "# billing-service\n",
+				"go.mod": `module gitlab.acme.example/platform/billing-service
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	go.uber.org/zap v1.26.0
+	gitlab.acme.example/platform/http-kit v1.4.0
+)
+`,
+				"web/package.json": `{
+  "name": "@acme/billing-web",
+  "version": "1.0.0",
+  "dependencies": {
+    "@acme/ui": "^2.1.0",
+    "react": "^18.2.0"
+  }
+}
+`,
+				"web/package-lock.json": `{
+  "name": "@acme/billing-web",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "": {
+      "name": "@acme/billing-web",
+      "version": "1.0.0",
+      "dependencies": {
+        "@acme/ui": "^2.1.0",
+        "react": "^18.2.0"
+      }
+    },
+    "node_modules/@acme/ui": {
+      "version": "2.1.0"
+    },
+    "node_modules/react": {
+      "version": "18.2.0"
+    }
+  }
+}
+`,
+			},
+		},
+		{
+			Path: "acme/platform/orders-service",
+			Files: map[string]string{
+				"go.mod": `module gitlab.acme.example/platform/orders-service
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.10.0
+	go.uber.org/zap v1.27.0
+	gitlab.acme.example/platform/http-kit v1.2.0
+	github.com/go-resty/resty/v2 v2.11.0
+)
+`,
+				"scripts/requirements.txt": "requests==2.31.0\nPyYAML==6.0.1\n",
+			},
+		},
+		{
+			Path: "acme/platform/http-kit",
+			Files: map[string]string{
+				"go.mod": `module gitlab.acme.example/platform/http-kit
+
+go 1.22
+
+require go.uber.org/zap v1.27.0
+`,
+			},
+		},
+		{
+			Path: "acme/web/storefront",
+			Files: map[string]string{
+				"package.json": `{
+  "name": "@acme/storefront",
+  "version": "3.2.0",
+  "dependencies": {
+    "@acme/ui": "^2.3.0",
+    "express": "^4.19.2",
+    "lodash": "^4.17.21",
+    "react": "^18.3.1"
+  }
+}
+`,
+				"package-lock.json": `{
+  "name": "@acme/storefront",
+  "version": "3.2.0",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "": {
+      "name": "@acme/storefront",
+      "version": "3.2.0",
+      "dependencies": {
+        "@acme/ui": "^2.3.0",
+        "express": "^4.19.2",
+        "lodash": "^4.17.21",
+        "react": "^18.3.1"
+      }
+    },
+    "node_modules/@acme/ui": {
+      "version": "2.3.0"
+    },
+    "node_modules/express": {
+      "version": "4.19.2"
+    },
+    "node_modules/lodash": {
+      "version": "4.17.21"
+    },
+    "node_modules/react": {
+      "version": "18.3.1"
+    }
+  }
+}
+`,
+			},
+		},
+		{
+			Path: "acme/web/admin-panel",
+			Files: map[string]string{
+				"package.json": `{
+  "name": "@acme/admin-panel",
+  "version": "0.9.0",
+  "dependencies": {
+    "express": "latest",
+    "lodash": "4.17.20"
+  }
+}
+`,
+			},
+		},
+		{
+			Path: "acme/data/reporting",
+			Files: map[string]string{
+				"requirements.txt": "requests==2.28.2\npandas==2.1.4\nPyYAML==5.4.1\n",
+				"etl/pom.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.acme.data</groupId>
+  <artifactId>reporting-etl</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>32.1.3-jre</version>
+    </dependency>
+    <dependency>
+      <groupId>com.acme.commons</groupId>
+      <artifactId>acme-commons</artifactId>
+      <version>1.8.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`,
+			},
+		},
+	}
+}
@@ -0,0 +1,425 @@
+package fakegitlab
+
+import (
+	"crypto/sha1" //nolint:gosec // Mimics git object names
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// apiPrefix is the path prefix of the GitLab REST API
+const apiPrefix = "/api/v4/"
+
+// defaultPerPage matches the GitLab page size when per_page is not given
+const defaultPerPage = 20
+
+// Repository is a fixture repository served by the fake server
+type Repository struct {
+	Path          string            // Full path such as "acme/platform/billing-service"
+	DefaultBranch string            // Defaults to "main"
+	Files         map[string]string // File path to content
+	// Other branches and tags, ref to file path to content
+	Refs map[string]map[string]string
+
+	Archived bool   // Listed in groups only when archived projects are asked for
+	ForkOf   string // Full path of the project this one was forked from
+	Empty    bool   // No commits and no default branch
+	Topics   []string
+}
+
+// Server is an in-memory GitLab API serving fixture repositories. Every namespace above a repository
+// is a group, so group URLs expand to the repositories below them like on a real instance.
+// It implements the endpoints the analyzer, the discover command and the init wizard use.
+type Server struct {
+	token    string
+	projects []*project
+	groups   []*group
+	server   *httptest.Server
+}
+
+type project struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	FullPath      string   `json:"path_with_namespace"`
+	DefaultBranch string   `json:"default_branch"`
+	WebURL        string   `json:"web_url"`
+	Archived      bool     `json:"archived"`
+	EmptyRepo     bool     `json:"empty_repo"`
+	ForkedFrom    *fork    `json:"forked_from_project,omitempty"`
+	Topics        []string `json:"topics"`
+	files         map[string]string
+	refs          map[string]map[string]string
+}
+
+type fork struct {
+	FullPath string `json:"path_with_namespace"`
+}
+
+type group struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	FullPath string `json:"full_path"`
+	WebURL   string `json:"web_url"`
+}
+
+type treeItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+}
+
+// New starts a fake GitLab API on a random local port that accepts only token.
+// An empty token accepts any request. Call Close when done.
+func New(token string, repositories ...Repository) *Server {
+	s := &Server{token: token}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.Start()
+	s.load(repositories) // Web URLs need the address, no request can arrive before New returns
+	return s
+}
+
+// NewListener is like New but serves on listener, e.g. a fixed address for the demo command
+func NewListener(listener net.Listener, token string, repositories ...Repository) *Server {
+	s := &Server{token: token}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	_ = s.server.Listener.Close()
+	s.server.Listener = listener
+	s.server.Start()
+	s.load(repositories)
+	return s
+}
+
+// URL returns the base URL of the instance, usable as gitlab.base_url
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// RepositoryURL returns the web URL of a repository or group path, usable as a repositories entry
+func (s *Server) RepositoryURL(fullPath string) string {
+	return s.server.URL + "/" + fullPath
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// load indexes repositories as projects and their parent namespaces as groups, IDs follow path order
+func (s *Server) load(repositories []Repository) {
+	sorted := append([]Repository(nil), repositories...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	groupPaths := make(map[string]bool)
+	for _, repository := range sorted {
+		for dir := path.Dir(repository.Path); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			groupPaths[dir] = true
+		}
+
+		branch := repository.DefaultBranch
+		if branch == "" && !repository.Empty {
+			branch = "main"
+		}
+		var forkedFrom *fork
+		if repository.ForkOf != "" {
+			forkedFrom = &fork{FullPath: repository.ForkOf}
+		}
+		s.projects = append(s.projects, &project{
+			ID:            len(s.projects) + 1,
+			Name:          path.Base(repository.Path),
+			Path:          path.Base(repository.Path),
+			FullPath:      repository.Path,
+			DefaultBranch: branch,
+			WebURL:        s.RepositoryURL(repository.Path),
+			Archived:      repository.Archived,
+			EmptyRepo:     repository.Empty,
+			ForkedFrom:    forkedFrom,
+			Topics:        repository.Topics,
+			files:         repository.Files,
+			refs:          repository.Refs,
+		})
+	}
+
+	paths := make([]string, 0, len(groupPaths))
+	for groupPath := range groupPaths {
+		paths = append(paths, groupPath)
+	}
+	sort.Strings(paths)
+	for _, groupPath := range paths {
+		s.groups = append(s.groups, &group{
+			ID:       len(s.groups) + 1,
+			Name:     path.Base(groupPath),
+			Path:     path.Base(groupPath),
+			FullPath: groupPath,
+			WebURL:   s.RepositoryURL(groupPath),
+		})
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.Header.Get("PRIVATE-TOKEN") != s.token && r.Header.Get("JOB-TOKEN") != s.token &&
+		r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	// Project and file paths arrive URL-encoded in one segment, split before decoding
+	escaped, ok := strings.CutPrefix(r.URL.EscapedPath(), apiPrefix)
+	if r.Method != http.MethodGet || !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	segments := strings.Split(escaped, "/")
+	for i, segment := range segments {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			segments[i] = decoded
+		}
+	}
+
+	switch {
+	case len(segments) == 1 && segments[0] == "user":
+		writeJSON(w, map[string]interface{}{"id": 1, "username": "demo", "name": "Demo User"})
+	case len(segments) == 1 && segments[0] == "groups":
+		writePage(w, r, filterBySearch(s.groups, r, func(g *group) string { return g.FullPath }))
+	case len(segments) == 1 && segments[0] == "projects":
+		writePage(w, r, filterBySearch(s.projects, r, func(p *project) string { return p.FullPath }))
+	case len(segments) == 2 && segments[0] == "groups":
+		writeFound(w, s.group(segments[1]))
+	case len(segments) == 3 && segments[0] == "groups" && segments[2] == "projects":
+		s.serveGroupProjects(w, r, segments[1])
+	case len(segments) == 2 && segments[0] == "projects":
+		writeFound(w, s.project(segments[1]))
+	case len(segments) == 4 && segments[0] == "projects" && segments[2] == "repository" && segments[3] == "tree":
+		s.serveTree(w, r, segments[1])
+	case len(segments) == 5 && segments[0] == "projects" && segments[2] == "repository" && segments[3] == "files":
+		s.serveFile(w, r, segments[1], segments[4])
+	case len(segments) == 5 && segments[0] == "projects" && segments[2] == "repository" && segments[3] == "commits":
+		s.serveCommit(w, segments[1], segments[4])
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) serveGroupProjects(w http.ResponseWriter, r *http.Request, id string) {
+	g := s.group(id)
+	if g == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	subgroups := r.URL.Query().Get("include_subgroups") == "true"
+	archived := r.URL.Query().Get("archived")
+	var projects []*project
+	for _, p := range s.projects {
+		if archived != "" && archived != strconv.FormatBool(p.Archived) {
+			continue
+		}
+		dir := path.Dir(p.FullPath)
+		if dir == g.FullPath || (subgroups && strings.HasPrefix(dir, g.FullPath+"/")) {
+			projects = append(projects, p)
+		}
+	}
+	writePage(w, r, projects)
+}
+
+func (s *Server) serveTree(w http.ResponseWriter, r *http.Request, id string) {
+	p := s.project(id)
+	if p == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	files, ok := p.filesAt(r.URL.Query().Get("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	filePaths := make([]string, 0, len(files))
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	items := make([]treeItem, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		items = append(items, treeItem{
+			ID:   strconv.Itoa(len(items) + 1),
+			Name: path.Base(filePath),
+			Type: "blob",
+			Path: filePath,
+			Mode: "100644",
+		})
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, id, filePath string) {
+	p := s.project(id)
+	if p == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	ref := r.URL.Query().Get("ref")
+	files, ok := p.filesAt(ref)
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	content, ok := files[filePath]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	if ref == "" {
+		ref = p.DefaultBranch
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"file_name": path.Base(filePath),
+		"file_path": filePath,
+		"size":      len(content),
+		"encoding":  "base64",
+		"content":   base64.StdEncoding.EncodeToString([]byte(content)),
+		"ref":       ref,
+	})
+}
+
+// serveCommit resolves a branch, tag or commit SHA to a commit whose SHA is derived from the files at it
+func (s *Server) serveCommit(w http.ResponseWriter, id, ref string) {
+	p := s.project(id)
+	if p == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	files, ok := p.filesAt(ref)
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	sha := commitSHA(files)
+	writeJSON(w, map[string]interface{}{"id": sha, "short_id": sha[:8]})
+}
+
+// commitSHA derives a stable commit SHA from file paths and contents
+func commitSHA(files map[string]string) string {
+	filePaths := make([]string, 0, len(files))
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	hash := sha1.New() //nolint:gosec // Mimics git object names, not used for security
+	for _, filePath := range filePaths {
+		_, _ = hash.Write([]byte(filePath + "\x00" + files[filePath] + "\x00"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// filesAt returns the files of the project at a branch, tag or commit SHA, the default branch when ref is empty
+func (p *project) filesAt(ref string) (map[string]string, bool) {
+	if ref == "" || ref == p.DefaultBranch || ref == commitSHA(p.files) {
+		return p.files, true
+	}
+	if files, ok := p.refs[ref]; ok {
+		return files, true
+	}
+	for _, files := range p.refs {
+		if commitSHA(files) == ref {
+			return files, true
+		}
+	}
+	return nil, false
+}
+
+// project finds a project by numeric ID or full path
+func (s *Server) project(id string) *project {
+	for _, p := range s.projects {
+		if strconv.Itoa(p.ID) == id || p.FullPath == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// group finds a group by numeric ID or full path
+func (s *Server) group(id string) *group {
+	for _, g := range s.groups {
+		if strconv.Itoa(g.ID) == id || g.FullPath == id {
+			return g
+		}
+	}
+	return nil
+}
+
+// filterBySearch keeps the items whose path contains the search query parameter
+func filterBySearch[T any](items []T, r *http.Request, itemPath func(T) string) []T {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	if search == "" {
+		return items
+	}
+
+	var found []T
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(itemPath(item)), search) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+// writePage writes the requested page of items with GitLab pagination headers
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+
+	totalPages := (len(items) + perPage - 1) / perPage
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+
+	w.Header().Set("X-Page", strconv.Itoa(page))
+	w.Header().Set("X-Per-Page", strconv.Itoa(perPage))
+	w.Header().Set("X-Total", strconv.Itoa(len(items)))
+	w.Header().Set("X-Total-Pages", strconv.Itoa(max(totalPages, 1)))
+	if page < totalPages {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+
+	writeJSON(w, append([]T{}, items[start:end]...))
+}
+
+// writeFound writes item or 404 when it is nil
+func writeFound[T any](w http.ResponseWriter, item *T) {
+	if item == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, item)
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": strconv.Itoa(status) + " " + http.StatusText(status)})
+}
@@ -0,0 +1,88 @@
+package fakegitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/gitlab"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServer_ServesGitLabClient(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("secret", fakegitlab.Demo()...)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "secret", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, client.CheckPermissions(ctx))
+
+	repositories, err := client.GetRepositoriesList(ctx, server.RepositoryURL(fakegitlab.DemoGroup))
+	require.NoError(t, err)
+	assert.Len(t, repositories, len(fakegitlab.Demo()), "Groups include subgroup projects")
+
+	subgroup, err := client.GetRepositoriesList(ctx, server.RepositoryURL("acme/web"))
+	require.NoError(t, err)
+	assert.Len(t, subgroup, 2)
+
+	single, err := client.GetRepositoriesList(ctx, server.RepositoryURL("acme/platform/billing-service"))
+	require.NoError(t, err)
+	require.Len(t, single, 1)
+	assert.Equal(t, "billing-service", single[0].Name)
+	assert.Equal(t, "main", single[0].DefaultBranch)
+
+	files, err := client.GetFilesList(ctx, single[0].URL)
+	require.NoError(t, err)
+	assert.Contains(t, files, "web/package-lock.json")
+
+	content, err := client.GetFileContent(ctx, single[0].URL, "web/package.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"@acme/ui": "^2.1.0"`)
+
+	_, err = client.GetFileContent(ctx, single[0].URL, "missing.txt")
+	require.Error(t, err)
+
+	locations, err := client.ListLocations(ctx, "web")
+	require.NoError(t, err)
+	require.NotEmpty(t, locations)
+	assert.Equal(t, gitlab.Location{Kind: gitlab.LocationGroup, Path: "acme/web", URL: server.RepositoryURL("acme/web")},
+		locations[0])
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("secret", fakegitlab.Demo()...)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "wrong", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	err = client.CheckPermissions(context.Background())
+	require.Error(t, err)
+	assert.True(t, gitlab.IsAuthError(err))
+}
+
+func TestServer_Pagination(t *testing.T) {
+	t.Parallel()
+
+	files := make(map[string]string)
+	for i := range 250 {
+		files["pkg/"+string(rune('a'+i%26))+"/"+string(rune('a'+i/26))+"/go.mod"] = "module x\n"
+	}
+	server := fakegitlab.New("", fakegitlab.Repository{Path: "big/monorepo", Files: files})
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "any", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	listed, err := client.GetFilesList(context.Background(), server.RepositoryURL("big/monorepo"))
+	require.NoError(t, err)
+	assert.Len(t, listed, 250, "Every tree page is fetched")
+}
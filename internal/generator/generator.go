@@ -2,151 +2,344 @@ package generator
 
 import (
 	"context"
+	"di-matrix-cli/internal/annotations"
+	"di-matrix-cli/internal/anonymize"
+	"di-matrix-cli/internal/diff"
 	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/graph"
+	"di-matrix-cli/internal/report"
+	"di-matrix-cli/internal/version"
 	_ "embed"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //go:embed template.html
 var templateContent string
 
-// versionRegex matches semantic version patterns (e.g., 1.2.3, v1.2.3, 1.2.3-beta.1)
-var versionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([a-zA-Z0-9.-]+))?(?:\+([a-zA-Z0-9.-]+))?$`)
+// Stdout is the CSV or JSON output path writing the report to standard output instead of a file
+const Stdout = "-"
+
+const (
+	// MatrixScopeCombined includes every dependency in one matrix
+	MatrixScopeCombined = "combined"
+	// MatrixScopeInternal restricts the matrix to internal dependencies
+	MatrixScopeInternal = "internal"
+	// MatrixScopeExternal restricts the matrix to external dependencies
+	MatrixScopeExternal = "external"
+)
+
+// What matrix cells show
+const (
+	// MatrixCellResolved shows the resolved version, from the lockfile when there is one
+	MatrixCellResolved = "resolved"
+	// MatrixCellConstraint shows the declared constraint, the resolved version when none was declared
+	MatrixCellConstraint = "constraint"
+	// MatrixCellBoth shows the resolved version with the declared constraint below it
+	MatrixCellBoth = "both"
+)
+
+// Versions matrix cells are compared against to mark them outdated
+const (
+	// MatrixCompareNewest compares against the newest of the versions in use and the registry's latest release
+	MatrixCompareNewest = "newest"
+	// MatrixComparePortfolio compares against the highest version in use across the projects
+	MatrixComparePortfolio = "portfolio"
+	// MatrixCompareRegistry compares against the registry's latest release
+	MatrixCompareRegistry = "registry"
+)
+
+// matrixScopeTitles maps matrix scopes to their report headings
+var matrixScopeTitles = map[string]string{ //nolint:gochecknoglobals // Read-only lookup table
+	MatrixScopeCombined: "All Dependencies",
+	MatrixScopeInternal: "Internal Dependencies",
+	MatrixScopeExternal: "External Dependencies",
+}
+
+// IsValidMatrixScope reports whether the scope is a known matrix preset
+func IsValidMatrixScope(scope string) bool {
+	_, ok := matrixScopeTitles[scope]
+	return ok
+}
 
 // Generator creates HTML reports from project dependencies
 type Generator struct {
-	outputPath string
+	outputPath   string
+	csvPath      string
+	xlsxPath     string
+	dotPath      string
+	mermaidPath  string
+	jsonPath     string
+	sarifPath    string
+	markdownPath string
+	sortBy       string
+	matrixScopes []string
+	baseline     []*domain.Project
+	prereleases  version.PrereleasePolicy
+	cellContent  string // One of the MatrixCell constants
+	compareTo    string // One of the MatrixCompare constants
+	templatePath string // Custom HTML template, empty for the embedded one
+	assetsDir    string // Files copied next to the HTML report
+	offline      bool
+	vulnScan     bool
+	internalOnly bool // Leave external dependencies out of the DOT and Mermaid graphs
+	collapse     bool // Leave transitive dependencies out of the reports
+	anonymizer   *anonymize.Anonymizer
+	incomplete   string
+	annotations  annotations.Set
+	coverage     []domain.RepositoryCoverage
+	violations   []domain.PolicyViolation
+	errors       []domain.AnalysisIssue
+	warnings     []domain.AnalysisIssue
+	// Matrices with more cells render with virtual scrolling, 0 never
+	virtualizeCells int
 }
 
 // NewGenerator creates a new report generator
 func NewGenerator(outputPath string) *Generator {
 	return &Generator{
-		outputPath: outputPath,
+		outputPath:   outputPath,
+		matrixScopes: []string{MatrixScopeCombined},
+		cellContent:  MatrixCellResolved,
+		compareTo:    MatrixCompareNewest,
+		// Static tables of this size already take seconds to lay out
+		virtualizeCells: DefaultVirtualizeCells,
 	}
 }
 
-// VersionInfo represents parsed version information
-type VersionInfo struct {
-	Major      int
-	Minor      int
-	Patch      int
-	PreRelease string
-	Build      string
-	Original   string
+// WithSortBy sets the project row order: "repository" (default) or "health" (lowest score first)
+func (g *Generator) WithSortBy(sortBy string) *Generator {
+	g.sortBy = sortBy
+	return g
 }
 
-// parseVersion parses a version string into VersionInfo
-func parseVersion(version string) *VersionInfo {
-	if version == "" {
-		return nil
+// WithPrereleasePolicy controls whether pre-release versions may count as the maximum a cell is compared against
+func (g *Generator) WithPrereleasePolicy(policy version.PrereleasePolicy) *Generator {
+	g.prereleases = policy
+	return g
+}
+
+// WithMatrixMode sets what matrix cells show (resolved, constraint, both) and which version they are compared
+// against (newest, portfolio, registry), empty values keep the resolved version compared against the newest
+func (g *Generator) WithMatrixMode(cell, compare string) *Generator {
+	if cell != "" {
+		g.cellContent = cell
+	}
+	if compare != "" {
+		g.compareTo = compare
 	}
+	return g
+}
 
-	matches := versionRegex.FindStringSubmatch(version)
-	if len(matches) < 4 {
-		return &VersionInfo{Original: version}
+// WithMatrixScopes sets which matrices the HTML report renders (combined, internal, external).
+// An empty list keeps the combined matrix.
+func (g *Generator) WithMatrixScopes(scopes []string) *Generator {
+	if len(scopes) > 0 {
+		g.matrixScopes = scopes
 	}
+	return g
+}
 
-	major, _ := strconv.Atoi(matches[1])
-	minor, _ := strconv.Atoi(matches[2])
-	patch, _ := strconv.Atoi(matches[3])
+// WithBaseline sets projects from a previous JSON report to highlight changes against
+func (g *Generator) WithBaseline(baseline []*domain.Project) *Generator {
+	g.baseline = baseline
+	return g
+}
 
-	preRelease := ""
-	build := ""
-	if len(matches) > 4 && matches[4] != "" {
-		preRelease = matches[4]
-	}
-	if len(matches) > 5 && matches[5] != "" {
-		build = matches[5]
-	}
+// WithOffline notes in the report that enrichment was served from local caches only
+func (g *Generator) WithOffline(offline bool) *Generator {
+	g.offline = offline
+	return g
+}
 
-	return &VersionInfo{
-		Major:      major,
-		Minor:      minor,
-		Patch:      patch,
-		PreRelease: preRelease,
-		Build:      build,
-		Original:   version,
-	}
+// WithVulnerabilityScan notes in the report that advisories were looked up, so no findings read as none known
+func (g *Generator) WithVulnerabilityScan(enabled bool) *Generator {
+	g.vulnScan = enabled
+	return g
 }
 
-// compareVersions compares two versions and returns:
-// -1 if v1 < v2
-// 0 if v1 == v2
-// 1 if v1 > v2
-func compareVersions(v1, v2 string) int {
-	info1 := parseVersion(v1)
-	info2 := parseVersion(v2)
+// WithJSONOutput sets the path GenerateJSON writes the versioned JSON report to, instead of the output path
+func (g *Generator) WithJSONOutput(path string) *Generator {
+	g.jsonPath = path
+	return g
+}
 
-	if info1 == nil || info2 == nil {
-		// If we can't parse versions, do string comparison
-		return strings.Compare(v1, v2)
-	}
+// WithCSVOutput sets the path GenerateCSV writes to, instead of the output path
+func (g *Generator) WithCSVOutput(path string) *Generator {
+	g.csvPath = path
+	return g
+}
 
-	// Compare major version
-	if info1.Major != info2.Major {
-		if info1.Major < info2.Major {
-			return -1
-		}
-		return 1
+// WithXLSXOutput sets the path GenerateXLSX writes the workbook to, instead of the output path
+func (g *Generator) WithXLSXOutput(path string) *Generator {
+	g.xlsxPath = path
+	return g
+}
+
+// WithGraphOutput sets the paths GenerateDOT and GenerateMermaid write to, instead of the output path.
+// With internalOnly the graphs only show internal dependencies.
+func (g *Generator) WithGraphOutput(dotPath, mermaidPath string, internalOnly bool) *Generator {
+	g.dotPath = dotPath
+	g.mermaidPath = mermaidPath
+	g.internalOnly = internalOnly
+	return g
+}
+
+// WithTransitiveCollapsed leaves dependencies pulled in by other dependencies out of every report,
+// only the dependencies projects declare themselves remain
+func (g *Generator) WithTransitiveCollapsed(collapse bool) *Generator {
+	g.collapse = collapse
+	return g
+}
+
+// WithAnonymizer replaces project, repository and path names with pseudonyms in every report,
+// dependency names and statistics stay intact
+func (g *Generator) WithAnonymizer(anonymizer *anonymize.Anonymizer) *Generator {
+	g.anonymizer = anonymizer
+	return g
+}
+
+// WithAnnotations merges team notes, owners and replacement recommendations of dependencies
+// into the matrix tooltips and the JSON report. Anonymized reports leave them out.
+func (g *Generator) WithAnnotations(set annotations.Set) *Generator {
+	g.annotations = set
+	return g
+}
+
+// annotation returns the annotation of a dependency as reports show it
+func (g *Generator) annotation(dep *domain.Dependency) (domain.Annotation, bool) {
+	if g.anonymizer != nil || len(g.annotations) == 0 {
+		return domain.Annotation{}, false
 	}
+	return g.annotations.Lookup(dep.Ecosystem, dep.Name)
+}
 
-	// Compare minor version
-	if info1.Minor != info2.Minor {
-		if info1.Minor < info2.Minor {
-			return -1
-		}
-		return 1
+// MarkIncomplete flags the reports as covering only part of the repositories, reason tells readers why
+func (g *Generator) MarkIncomplete(reason string) {
+	g.incomplete = reason
+}
+
+// RecordCoverage shows the share of each repository's dependency files that were analyzed in the reports
+func (g *Generator) RecordCoverage(coverage []domain.RepositoryCoverage) {
+	g.coverage = coverage
+}
+
+// reportCoverage returns the coverage as reports show it, anonymized when configured
+func (g *Generator) reportCoverage() []domain.RepositoryCoverage {
+	if g.anonymizer == nil {
+		return g.coverage
 	}
+	return g.anonymizer.Coverage(g.coverage)
+}
 
-	// Compare patch version
-	if info1.Patch != info2.Patch {
-		if info1.Patch < info2.Patch {
-			return -1
-		}
-		return 1
+// RecordIssues lists the repositories, projects and files the analysis failed on in the reports
+func (g *Generator) RecordIssues(errors, warnings []domain.AnalysisIssue) {
+	g.errors, g.warnings = errors, warnings
+}
+
+// reportIssues returns the errors and warnings as reports show them, anonymized when configured
+func (g *Generator) reportIssues() ([]domain.AnalysisIssue, []domain.AnalysisIssue) {
+	if g.anonymizer == nil {
+		return g.errors, g.warnings
 	}
+	return g.anonymizer.Issues(g.errors), g.anonymizer.Issues(g.warnings)
+}
 
-	// Compare pre-release versions
-	if info1.PreRelease == "" && info2.PreRelease == "" {
-		return 0
+// RecordViolations lists the policy violations of the analyzed projects in the HTML report
+func (g *Generator) RecordViolations(violations []domain.PolicyViolation) {
+	g.violations = violations
+}
+
+// violationRow is a policy violation with the project it was found in, nil when the project is not reported
+type violationRow struct {
+	Project   *domain.Project
+	Violation domain.PolicyViolation
+}
+
+// reportViolations returns the violations as reports show them next to their projects, anonymized when configured
+func (g *Generator) reportViolations(projects []*domain.Project) []violationRow {
+	violations := g.violations
+	if g.anonymizer != nil {
+		violations = g.anonymizer.Violations(violations)
 	}
-	if info1.PreRelease == "" {
-		return 1 // v1 is stable, v2 is pre-release
+
+	byID := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
 	}
-	if info2.PreRelease == "" {
-		return -1 // v2 is stable, v1 is pre-release
+	rows := make([]violationRow, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, violationRow{Project: byID[violation.ProjectID], Violation: violation})
 	}
+	return rows
+}
 
-	// Both are pre-release, compare lexicographically
-	return strings.Compare(info1.PreRelease, info2.PreRelease)
+// driftRow is a dependency used at several versions with the projects using each version
+type driftRow struct {
+	report.Drift
+	Projects [][]*domain.Project // Projects using each version, in the order of Versions
 }
 
-// findMaxVersion finds the maximum version among all versions of a dependency
-func findMaxVersion(versions []string) string {
-	if len(versions) == 0 {
-		return ""
+// reportDrift lists the dependencies used at more than one version, the widest spread first
+func reportDrift(projects []*domain.Project) []driftRow {
+	byID := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
 	}
 
-	maxVersion := versions[0]
-	for _, version := range versions[1:] {
-		if compareVersions(version, maxVersion) > 0 {
-			maxVersion = version
+	drifts := report.NewDrift(projects)
+	rows := make([]driftRow, 0, len(drifts))
+	for _, drift := range drifts {
+		row := driftRow{Drift: drift, Projects: make([][]*domain.Project, len(drift.Versions))}
+		for i, used := range drift.Versions {
+			for _, id := range used.Projects {
+				row.Projects[i] = append(row.Projects[i], byID[id])
+			}
 		}
+		rows = append(rows, row)
 	}
+	return rows
+}
 
-	return maxVersion
+// reportProjects returns projects as they appear in reports, anonymized when configured
+func (g *Generator) reportProjects(projects []*domain.Project) []*domain.Project {
+	if g.collapse {
+		projects = directDependencies(projects)
+	}
+	if g.anonymizer == nil {
+		return projects
+	}
+	return g.anonymizer.Projects(projects)
+}
+
+// directDependencies returns copies of projects without their transitive dependencies
+func directDependencies(projects []*domain.Project) []*domain.Project {
+	direct := make([]*domain.Project, 0, len(projects))
+	for _, project := range projects {
+		copied := *project
+		copied.Dependencies = nil
+		for _, dep := range project.Dependencies {
+			if dep.Direct {
+				copied.Dependencies = append(copied.Dependencies, dep)
+			}
+		}
+		direct = append(direct, &copied)
+	}
+	return direct
 }
 
-// OutputPath returns the output path
+// OutputPath returns the output path of the HTML report
 func (g *Generator) OutputPath() string {
 	return g.outputPath
 }
@@ -157,6 +350,16 @@ func (g *Generator) GenerateSummary(ctx context.Context, projects []*domain.Proj
 	internalExternal := map[string]int{"internal": 0, "external": 0}
 	ecosystems := make(map[string]int)
 	totalDependencies := 0
+	floatingDependencies := 0
+	projectsWithoutLockfile := 0
+	healthTotal := 0.0
+	scoredProjects := 0
+	vulnerableDependencies := 0
+	var pinningIssues []map[string]interface{}
+	var fileWarnings []map[string]interface{}
+	var vulnerabilities []map[string]interface{}
+	var versionConflicts []map[string]interface{}
+	var lifecycleIssues []map[string]interface{}
 
 	// Count dependencies and categorize
 	for _, project := range projects {
@@ -166,9 +369,26 @@ func (g *Generator) GenerateSummary(ctx context.Context, projects []*domain.Proj
 		}
 
 		// Count dependencies
+		var floating []string
+		conflicting := make(map[string]bool)
 		for _, dep := range project.Dependencies {
 			totalDependencies++
 
+			// Collect packages the project resolves to several versions, once per package
+			if len(dep.ConflictVersions) > 1 && !conflicting[dep.Name] {
+				conflicting[dep.Name] = true
+				versionConflicts = append(versionConflicts, map[string]interface{}{
+					"project":    project,
+					"dependency": dep.Name,
+					"versions":   dep.ConflictVersions,
+				})
+			}
+
+			if dep.IsFloating {
+				floatingDependencies++
+				floating = append(floating, dep.Name)
+			}
+
 			// Count internal/external
 			if dep.IsInternal {
 				internalExternal["internal"]++
@@ -180,15 +400,82 @@ func (g *Generator) GenerateSummary(ctx context.Context, projects []*domain.Proj
 			if dep.Ecosystem != "" {
 				ecosystems[dep.Ecosystem]++
 			}
+
+			// Collect versions withdrawn upstream or out of support, migrations to plan
+			if dep.IsDeprecated || dep.IsEndOfLife {
+				lifecycleIssues = append(lifecycleIssues, map[string]interface{}{
+					"project":    project,
+					"dependency": dep,
+				})
+			}
+
+			// Collect known vulnerabilities per project and dependency
+			if len(dep.Vulnerabilities) > 0 {
+				vulnerableDependencies++
+			}
+			for _, vulnerability := range dep.Vulnerabilities {
+				vulnerabilities = append(vulnerabilities, map[string]interface{}{
+					"project":       project,
+					"dependency":    dep,
+					"vulnerability": vulnerability,
+				})
+			}
+		}
+
+		if project.Health != nil {
+			healthTotal += project.Health.Score
+			scoredProjects++
+		}
+
+		// Collect pinning compliance issues per project
+		if !project.HasLockfile {
+			projectsWithoutLockfile++
+		}
+		if len(floating) > 0 || !project.HasLockfile {
+			pinningIssues = append(pinningIssues, map[string]interface{}{
+				"project":      project,
+				"has_lockfile": project.HasLockfile,
+				"floating":     floating,
+			})
+		}
+
+		// Collect detected files that yielded no dependencies
+		for _, warning := range project.Warnings {
+			fileWarnings = append(fileWarnings, map[string]interface{}{
+				"project": project,
+				"warning": warning,
+			})
 		}
 	}
 
+	// Most severe first, projects keep their order within a severity
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		left := vulnerabilities[i]["vulnerability"].(domain.Vulnerability)
+		right := vulnerabilities[j]["vulnerability"].(domain.Vulnerability)
+		return domain.SeverityRank(left.Severity) > domain.SeverityRank(right.Severity)
+	})
+
+	averageHealth := 0.0
+	if scoredProjects > 0 {
+		averageHealth = math.Round(healthTotal/float64(scoredProjects)*10) / 10
+	}
+
 	return map[string]interface{}{
-		"total_projects":     len(projects),
-		"total_dependencies": totalDependencies,
-		"languages":          languages,
-		"internal_external":  internalExternal,
-		"ecosystems":         ecosystems,
+		"average_health":            averageHealth,
+		"total_projects":            len(projects),
+		"total_dependencies":        totalDependencies,
+		"languages":                 languages,
+		"internal_external":         internalExternal,
+		"ecosystems":                ecosystems,
+		"floating_dependencies":     floatingDependencies,
+		"projects_without_lockfile": projectsWithoutLockfile,
+		"pinning_issues":            pinningIssues,
+		"file_warnings":             fileWarnings,
+		"vulnerable_dependencies":   vulnerableDependencies,
+		"vulnerabilities":           vulnerabilities,
+		"lifecycle_issues":          lifecycleIssues,
+		"version_conflicts":         versionConflicts,
+		"internal_links":            internalAdjacency(projects),
 	}
 }
 
@@ -256,7 +543,7 @@ func (g *Generator) collectAllDependencies(projects []*domain.Project) (map[stri
 		for _, dep := range project.Dependencies {
 			// Keep the latest version if we have multiple instances of the same dependency
 			if existingDep, exists := allDependencySet[dep.Name]; !exists ||
-				dep.LatestVersion > existingDep.LatestVersion {
+				version.Compare(dep.LatestVersion, existingDep.LatestVersion) > 0 {
 				allDependencySet[dep.Name] = dep
 			}
 		}
@@ -276,29 +563,72 @@ func (g *Generator) createProjectDependencyMap(projects []*domain.Project) map[s
 	for _, project := range projects {
 		allProjectDeps[project.ID] = make(map[string]*domain.Dependency)
 		for _, dep := range project.Dependencies {
+			// When a project resolves the same package several times, show the highest version
+			if existing, exists := allProjectDeps[project.ID][dep.Name]; exists &&
+				version.Compare(dep.Version, existing.Version) <= 0 {
+				continue
+			}
 			allProjectDeps[project.ID][dep.Name] = dep
 		}
 	}
 	return allProjectDeps
 }
 
-// findMaxVersionsForDependencies finds the maximum version for each dependency
-func (g *Generator) findMaxVersionsForDependencies(
+// collectVersionsForDependencies collects the distinct versions of each dependency used across projects.
+// Equivalent spellings such as "v1.2" and "1.2.0" count once.
+func (g *Generator) collectVersionsForDependencies(
 	dependencies []string,
 	projects []*domain.Project,
 	projectDeps map[string]map[string]*domain.Dependency,
-) map[string]string {
-	maxVersions := make(map[string]string)
+) map[string][]string {
+	versions := make(map[string][]string)
 	for _, depName := range dependencies {
-		var versions []string
+		seen := make(map[string]bool)
 		for _, project := range projects {
-			if dep, exists := projectDeps[project.ID][depName]; exists && dep.Version != "" {
-				versions = append(versions, dep.Version)
+			dep, exists := projectDeps[project.ID][depName]
+			if !exists || dep.Version == "" {
+				continue
+			}
+			canonical := version.Canonical(dep.Version)
+			if !seen[canonical] {
+				seen[canonical] = true
+				versions[depName] = append(versions[depName], canonical)
 			}
 		}
-		maxVersions[depName] = findMaxVersion(versions)
 	}
-	return maxVersions
+	return versions
+}
+
+// dependencyScopeOrder lists the dependency scopes in the order the report shows them
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var dependencyScopeOrder = []string{domain.ScopeRuntime, domain.ScopeDev, domain.ScopeTest, domain.ScopeOptional}
+
+// collectScopes returns the distinct scopes of the dependencies in report order
+func collectScopes(dependencies []*domain.Dependency) []string {
+	used := make(map[string]bool)
+	for _, dep := range dependencies {
+		used[dep.DependencyScope()] = true
+	}
+	var scopes []string
+	for _, scope := range dependencyScopeOrder {
+		if used[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// collectEcosystems returns the distinct ecosystems of the dependencies in alphabetical order
+func collectEcosystems(dependencies []*domain.Dependency) []string {
+	var ecosystems []string
+	for _, dep := range dependencies {
+		if dep.Ecosystem != "" && !slices.Contains(ecosystems, dep.Ecosystem) {
+			ecosystems = append(ecosystems, dep.Ecosystem)
+		}
+	}
+	slices.Sort(ecosystems)
+	return ecosystems
 }
 
 // createCombinedMatrix creates a combined matrix for all projects
@@ -312,36 +642,86 @@ func (g *Generator) createCombinedMatrix(projects []*domain.Project) ([]map[stri
 	// Sort dependencies by type (internal first) and then alphabetically
 	allDependencies = g.sortDependencies(allDependencies, allProjectDeps)
 
-	// Find maximum version for each dependency across all projects
-	maxVersions := g.findMaxVersionsForDependencies(allDependencies, projects, allProjectDeps)
+	// Collect versions of each dependency across all projects to find the maximum per cell
+	depVersions := g.collectVersionsForDependencies(allDependencies, projects, allProjectDeps)
 
 	// Convert to dependency objects with name and latest_version
 	var dependencyObjects []map[string]interface{}
 	for _, depName := range allDependencies {
 		dep := allDependencySet[depName]
-		dependencyObjects = append(dependencyObjects, map[string]interface{}{
-			"name":           dep.Name,
-			"latest_version": dep.LatestVersion,
-		})
+		var uses []*domain.Dependency
+		for _, project := range projects {
+			if used, exists := allProjectDeps[project.ID][depName]; exists {
+				uses = append(uses, used)
+			}
+		}
+		dependencyObject := map[string]interface{}{
+			"name":              dep.Name,
+			"ecosystem":         dep.Ecosystem,
+			"is_internal":       dep.IsInternal,
+			"latest_version":    dep.LatestVersion,
+			"versioning_scheme": dep.VersioningScheme,
+			"version_count":     len(depVersions[dep.Name]),
+			"scopes":            collectScopes(uses),
+		}
+		if annotation, ok := g.annotation(dep); ok {
+			dependencyObject["annotation"] = annotationText(annotation)
+			dependencyObject["has_replacement"] = annotation.Replacement != ""
+		}
+		dependencyObjects = append(dependencyObjects, dependencyObject)
 	}
 
+	// Index changes since the baseline by project and dependency
+	changes := g.baselineChangeIndex(projects)
+
+	// Internal libraries built by analyzed projects link to them
+	providers := graph.Providers(projects)
+
 	// Create combined matrix data
 	combinedMatrix := make([][]interface{}, len(projects))
 	for i, project := range projects {
 		combinedMatrix[i] = make([]interface{}, len(allDependencies))
 		for j, depName := range allDependencies {
 			if dep, exists := allProjectDeps[project.ID][depName]; exists {
-				maxVersion := maxVersions[depName]
-				isOutdated := maxVersion != "" && dep.Version != "" && compareVersions(dep.Version, maxVersion) < 0
+				maxVersion := g.comparedVersion(depVersions[depName], allDependencySet[depName].LatestVersion, dep.Version)
+				isOutdated := version.IsOutdated(dep.Version, maxVersion)
+				drift := driftLevel(dep.Version, maxVersion, isOutdated)
+
+				change := changes[project.ID+"\x00"+depName]
 
 				combinedMatrix[i][j] = map[string]interface{}{
-					"version":        dep.Version,
-					"latest_version": dep.LatestVersion,
-					"constraint":     dep.Constraint,
-					"is_internal":    dep.IsInternal,
-					"ecosystem":      dep.Ecosystem,
-					"max_version":    maxVersion,
-					"is_outdated":    isOutdated,
+					"change":              change.Kind,
+					"previous_version":    change.OldVersion,
+					"version":             dep.Version,
+					"label":               g.cellLabel(dep),
+					"label_constraint":    g.cellConstraint(dep),
+					"latest_version":      dep.LatestVersion,
+					"constraint":          dep.Constraint,
+					"is_range":            !dep.IsPinned() && !dep.IsFloating && (dep.MinVersion != "" || dep.MaxVersion != ""),
+					"is_internal":         dep.IsInternal,
+					"ecosystem":           dep.Ecosystem,
+					"max_version":         maxVersion,
+					"is_outdated":         isOutdated,
+					"drift":               drift,
+					"is_floating":         dep.IsFloating,
+					"has_conflict":        len(dep.ConflictVersions) > 1,
+					"conflict_versions":   dep.ConflictVersions,
+					"constraint_mismatch": dep.ConstraintMismatch,
+					"declared_constraint": dep.DeclaredConstraint,
+					"replaced_by":         dep.ReplacedBy,
+					"provider":            graph.Provider(providers, project, dep),
+					"source":              dep.Source,
+					"stale":               dep.Stale,
+					"is_deprecated":       dep.IsDeprecated,
+					"deprecation":         dep.Deprecation,
+					"is_end_of_life":      dep.IsEndOfLife,
+					"end_of_life":         dep.EndOfLife,
+					"transitive":          !dep.Direct,
+					"parents":             dep.Parents,
+					"scope":               dep.DependencyScope(),
+					"vuln_count":          dep.VulnCount,
+					"vuln_severity":       dep.MaxSeverity(),
+					"vuln_ids":            vulnerabilityIDs(dep.Vulnerabilities),
 				}
 			} else {
 				combinedMatrix[i][j] = nil
@@ -352,13 +732,78 @@ func (g *Generator) createCombinedMatrix(projects []*domain.Project) ([]map[stri
 	return dependencyObjects, combinedMatrix
 }
 
-// sortProjectsByRepositoryName sorts projects by repository name first, then by project path
-func (g *Generator) sortProjectsByRepositoryName(projects []*domain.Project) []*domain.Project {
+// comparedVersion returns the version a cell on current is compared against under the matrix mode
+func (g *Generator) comparedVersion(inUse []string, latest, current string) string {
+	switch g.compareTo {
+	case MatrixComparePortfolio:
+		return version.MaxFor(inUse, current, g.prereleases)
+	case MatrixCompareRegistry:
+		// Packages the registries do not know keep the highest version in use as their latest version
+		if latest != "" {
+			return latest
+		}
+		return version.MaxFor(inUse, current, g.prereleases)
+	default:
+		// The latest release counts as a version to catch up with, even if no project uses it yet
+		return version.MaxFor(append(slices.Clone(inUse), latest), current, g.prereleases)
+	}
+}
+
+// cellLabel returns the version text of a matrix cell
+func (g *Generator) cellLabel(dep *domain.Dependency) string {
+	if g.cellContent == MatrixCellConstraint && dep.Constraint != "" {
+		return dep.Constraint
+	}
+	return dep.Version
+}
+
+// cellConstraint returns the constraint shown below the version of a matrix cell, empty when it adds nothing
+func (g *Generator) cellConstraint(dep *domain.Dependency) string {
+	if g.cellContent != MatrixCellBoth || dep.Constraint == dep.Version {
+		return ""
+	}
+	return dep.Constraint
+}
+
+// driftLevel grades an outdated cell for the drift heatmap: "major" when the major version differs
+// from the maximum, "minor" for any other lag including ranges, "" when the cell is current
+func driftLevel(current, maxVersion string, outdated bool) string {
+	if !outdated {
+		return ""
+	}
+	currentParsed, maxParsed := version.Parse(current), version.Parse(maxVersion)
+	if currentParsed != nil && maxParsed != nil && currentParsed.Major() < maxParsed.Major() {
+		return "major"
+	}
+	return "minor"
+}
+
+// baselineChangeIndex compares projects with the baseline and indexes changes by project ID and dependency name
+func (g *Generator) baselineChangeIndex(projects []*domain.Project) map[string]domain.DependencyChange {
+	index := make(map[string]domain.DependencyChange)
+	if g.baseline == nil {
+		return index
+	}
+	for _, change := range diff.Compare(g.reportProjects(g.baseline), projects) {
+		index[change.ProjectID+"\x00"+change.Dependency] = change
+	}
+	return index
+}
+
+// sortProjects sorts projects by repository name first, then by project path,
+// optionally ordering by health score before anything else
+func (g *Generator) sortProjects(projects []*domain.Project) []*domain.Project {
 	sortedProjects := make([]*domain.Project, len(projects))
 	copy(sortedProjects, projects)
 
 	sort.Slice(sortedProjects, func(i, j int) bool {
-		// First sort by repository name
+		// Optionally put the least healthy projects first
+		if g.sortBy == "health" {
+			if scoreI, scoreJ := healthScore(sortedProjects[i]), healthScore(sortedProjects[j]); scoreI != scoreJ {
+				return scoreI < scoreJ
+			}
+		}
+		// Then sort by repository name
 		if sortedProjects[i].Repository.Name != sortedProjects[j].Repository.Name {
 			return sortedProjects[i].Repository.Name < sortedProjects[j].Repository.Name
 		}
@@ -369,13 +814,21 @@ func (g *Generator) sortProjectsByRepositoryName(projects []*domain.Project) []*
 	return sortedProjects
 }
 
+// healthScore returns the project's health score, treating unscored projects as fully healthy
+func healthScore(project *domain.Project) float64 {
+	if project.Health == nil {
+		return 100
+	}
+	return project.Health.Score
+}
+
 // GenerateMatrix creates a simple dependency matrix for all projects
 func (g *Generator) GenerateMatrix(ctx context.Context, projects []*domain.Project) map[string]interface{} {
 	// Filter out projects with zero dependencies
 	filteredProjects := g.filterProjectsWithDependencies(projects)
 
-	// Sort projects by repository name
-	sortedProjects := g.sortProjectsByRepositoryName(filteredProjects)
+	// Sort projects by health (optional), repository name and path
+	sortedProjects := g.sortProjects(filteredProjects)
 
 	// Create combined matrix
 	allDependencies, combinedMatrix := g.createCombinedMatrix(sortedProjects)
@@ -387,8 +840,71 @@ func (g *Generator) GenerateMatrix(ctx context.Context, projects []*domain.Proje
 	}
 }
 
+// templateFuncs returns helper functions available to the HTML template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"mul100": func(v float64) float64 { return v * 100 },
+		"inc":    func(v int) int { return v + 1 },
+	}
+}
+
+// GenerateScopedMatrix creates a dependency matrix restricted to internal or external dependencies.
+// Projects without dependencies in scope are left out; the given projects are not modified.
+func (g *Generator) GenerateScopedMatrix(
+	ctx context.Context,
+	projects []*domain.Project,
+	scope string,
+) map[string]interface{} {
+	if scope == MatrixScopeCombined || scope == "" {
+		return g.GenerateMatrix(ctx, projects)
+	}
+
+	wantInternal := scope == MatrixScopeInternal
+	scopedProjects := make([]*domain.Project, 0, len(projects))
+	for _, project := range projects {
+		scoped := *project
+		scoped.Dependencies = nil
+		for _, dep := range project.Dependencies {
+			if dep.IsInternal == wantInternal {
+				scoped.Dependencies = append(scoped.Dependencies, dep)
+			}
+		}
+		scopedProjects = append(scopedProjects, &scoped)
+	}
+
+	return g.GenerateMatrix(ctx, scopedProjects)
+}
+
+// scopedMatrix is a titled matrix rendered as one table in the HTML report
+type scopedMatrix struct {
+	Scope  string
+	Title  string
+	Matrix map[string]interface{}
+	// Set for matrices too large for a static table, rendered with virtual scrolling instead
+	Virtual *virtualMatrix
+}
+
+// generateScopedMatrices builds one matrix per configured scope
+func (g *Generator) generateScopedMatrices(ctx context.Context, projects []*domain.Project) []scopedMatrix {
+	matrices := make([]scopedMatrix, 0, len(g.matrixScopes))
+	for _, scope := range g.matrixScopes {
+		matrix := scopedMatrix{
+			Scope:  scope,
+			Title:  matrixScopeTitles[scope],
+			Matrix: g.GenerateScopedMatrix(ctx, projects, scope),
+		}
+		if g.virtualize(matrix.Matrix) {
+			matrix.Virtual = newVirtualMatrix(matrix.Matrix)
+		}
+		matrices = append(matrices, matrix)
+	}
+	return matrices
+}
+
 // GenerateHTML creates an HTML report from projects
 func (g *Generator) GenerateHTML(ctx context.Context, projects []*domain.Project) error {
+	projects = g.reportProjects(projects)
+
 	// Create output directory if it doesn't exist
 	dir := filepath.Dir(g.outputPath)
 	if err := os.MkdirAll(dir, 0o750); err != nil {
@@ -398,24 +914,74 @@ func (g *Generator) GenerateHTML(ctx context.Context, projects []*domain.Project
 	// Generate summary statistics
 	summary := g.GenerateSummary(ctx, projects)
 
-	// Generate matrix data
-	matrix := g.GenerateMatrix(ctx, projects)
+	// Generate matrix data for every configured scope
+	matrices := g.generateScopedMatrices(ctx, projects)
+
+	// Compare with the baseline report, if any
+	var baseline map[string]interface{}
+	if g.baseline != nil {
+		changes := diff.Compare(g.reportProjects(g.baseline), projects)
+		baseline = map[string]interface{}{
+			"changes": changes,
+			"counts":  diff.Summarize(changes),
+		}
+	}
+
+	// Theme files next to the report, linked by custom templates and, for stylesheets, the embedded one
+	assets, err := g.copyAssets(dir)
+	if err != nil {
+		return err
+	}
+
+	// Dependency scopes and ecosystems in use, filterable in the matrix when there are several
+	var dependencies []*domain.Dependency
+	for _, project := range projects {
+		dependencies = append(dependencies, project.Dependencies...)
+	}
+
+	errors, _ := g.reportIssues()
 
 	// Create template data
 	data := struct {
-		Projects []*domain.Project
-		Summary  map[string]interface{}
-		Matrix   map[string]interface{}
-		Title    string
+		Projects   []*domain.Project
+		Summary    map[string]interface{}
+		Matrix     map[string]interface{}
+		Matrices   []scopedMatrix
+		Baseline   map[string]interface{}
+		Scopes     []string
+		Ecosystems []string
+		Offline    bool
+		VulnScan   bool
+		Incomplete string
+		Coverage   []domain.RepositoryCoverage
+		Errors     []domain.AnalysisIssue
+		Violations []violationRow
+		Drift      []driftRow
+		Assets     []string
+		Styles     []string
+		Title      string
 	}{
-		Projects: projects,
-		Summary:  summary,
-		Matrix:   matrix,
-		Title:    "Dependency Matrix Report",
+		Projects:   projects,
+		Summary:    summary,
+		Matrix:     matrices[0].Matrix,
+		Matrices:   matrices,
+		Baseline:   baseline,
+		Scopes:     collectScopes(dependencies),
+		Ecosystems: collectEcosystems(dependencies),
+		Offline:    g.offline,
+		VulnScan:   g.vulnScan,
+		Incomplete: g.incomplete,
+		Coverage:   g.reportCoverage(),
+		Errors:     errors,
+		Violations: g.reportViolations(projects),
+		Drift:      reportDrift(projects),
+		Assets:     assets,
+		Styles:     stylesheets(assets),
+		Title:      "Dependency Matrix Report",
 	}
 
-	// Parse embedded template
-	tmpl, err := template.New("report").Parse(templateContent)
+	// Parse the custom template, or the embedded one
+	tmpl, err := g.parseTemplate()
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -437,16 +1003,15 @@ func (g *Generator) GenerateHTML(ctx context.Context, projects []*domain.Project
 
 // GenerateCSV creates a CSV report from projects
 func (g *Generator) GenerateCSV(ctx context.Context, projects []*domain.Project) error {
-	// Create output directory if it doesn't exist
-	dir := filepath.Dir(g.outputPath)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	projects = g.reportProjects(projects)
+	path := g.outputPath
+	if g.csvPath != "" {
+		path = g.csvPath
 	}
 
-	// Create output file
-	file, err := os.Create(g.outputPath)
+	file, err := createStreamOutput(path)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 	defer file.Close()
 
@@ -465,6 +1030,10 @@ func (g *Generator) GenerateCSV(ctx context.Context, projects []*domain.Project)
 		"Constraint",
 		"Is Internal",
 		"Ecosystem",
+		"Vulnerabilities",
+		"Max Severity",
+		"Advisories",
+		"Lifecycle",
 	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
@@ -483,6 +1052,10 @@ func (g *Generator) GenerateCSV(ctx context.Context, projects []*domain.Project)
 				dependency.Constraint,
 				strconv.FormatBool(dependency.IsInternal),
 				dependency.Ecosystem,
+				strconv.Itoa(dependency.VulnCount),
+				dependency.MaxSeverity(),
+				strings.Join(vulnerabilityIDs(dependency.Vulnerabilities), " "),
+				lifecycle(dependency),
 			}
 			if err := writer.Write(record); err != nil {
 				return fmt.Errorf("failed to write CSV record: %w", err)
@@ -493,32 +1066,20 @@ func (g *Generator) GenerateCSV(ctx context.Context, projects []*domain.Project)
 	return nil
 }
 
-// GenerateJSON creates a JSON report from projects
+// GenerateJSON creates a JSON report from projects, see internal/report for its versioned schema
 func (g *Generator) GenerateJSON(ctx context.Context, projects []*domain.Project) error {
-	// Create output directory if it doesn't exist
-	dir := filepath.Dir(g.outputPath)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Generate summary statistics
-	summary := g.GenerateSummary(ctx, projects)
-
-	// Create report data structure
-	reportData := struct {
-		Projects []*domain.Project      `json:"projects"`
-		Summary  map[string]interface{} `json:"summary"`
-		Title    string                 `json:"title"`
-	}{
-		Projects: projects,
-		Summary:  summary,
-		Title:    "Dependency Matrix Report",
+	path := g.outputPath
+	if g.jsonPath != "" {
+		path = g.jsonPath
 	}
+	return g.writeJSONReport(path, g.reportProjects(projects))
+}
 
-	// Create output file
-	file, err := os.Create(g.outputPath)
+// writeJSONReport writes the versioned JSON report of projects to path
+func (g *Generator) writeJSONReport(path string, projects []*domain.Project) error {
+	file, err := createStreamOutput(path)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 	defer file.Close()
 
@@ -527,9 +1088,81 @@ func (g *Generator) GenerateJSON(ctx context.Context, projects []*domain.Project
 	encoder.SetIndent("", "  ")
 
 	// Encode data to JSON
-	if err := encoder.Encode(reportData); err != nil {
+	result := report.New("Dependency Matrix Report", projects, time.Now())
+	result.Incomplete = g.incomplete
+	result.WithCoverage(g.reportCoverage())
+	result.WithIssues(g.reportIssues())
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if annotation, ok := g.annotation(dep); ok {
+				result.Annotate(dep.Name, annotation)
+			}
+		}
+	}
+	if err := encoder.Encode(result); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
 	return nil
 }
+
+// createStreamOutput creates the CSV or JSON report file and its directory, Stdout writes to standard output
+func createStreamOutput(path string) (io.WriteCloser, error) {
+	if path == Stdout {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return file, nil
+}
+
+// nopCloser keeps standard output open after a report was written to it
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// vulnerabilityIDs lists advisories by CVE identifier where one exists
+func vulnerabilityIDs(vulnerabilities []domain.Vulnerability) []string {
+	ids := make([]string, 0, len(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		ids = append(ids, vulnerability.CVE())
+	}
+	return ids
+}
+
+// lifecycle describes why a dependency needs migrating: "deprecated", "end-of-life", both or neither
+func lifecycle(dep *domain.Dependency) string {
+	var statuses []string
+	if dep.IsDeprecated {
+		statuses = append(statuses, "deprecated")
+	}
+	if dep.IsEndOfLife {
+		statuses = append(statuses, "end-of-life")
+	}
+	return strings.Join(statuses, " ")
+}
+
+// annotationText renders an annotation as one tooltip line
+func annotationText(annotation domain.Annotation) string {
+	var parts []string
+	if annotation.Note != "" {
+		parts = append(parts, annotation.Note)
+	}
+	if annotation.Owner != "" {
+		parts = append(parts, "Owner: "+annotation.Owner)
+	}
+	if annotation.Replacement != "" {
+		parts = append(parts, "Replacement: "+annotation.Replacement)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -2,9 +2,15 @@ package generator_test
 
 import (
 	"context"
+	"di-matrix-cli/internal/annotations"
+	"di-matrix-cli/internal/anonymize"
+	"di-matrix-cli/internal/diff"
 	"di-matrix-cli/internal/domain"
 	"di-matrix-cli/internal/generator"
+	"di-matrix-cli/internal/report"
+	"di-matrix-cli/internal/sarif"
 	"encoding/csv"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -53,6 +59,27 @@ func TestGenerateSummary(t *testing.T) {
 	assert.Equal(t, 3, internalExternal["external"])
 }
 
+func TestGenerateSummary_PinningCompliance(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+	ctx := context.Background()
+
+	projects := createTestProjects()
+	projects[0].HasLockfile = true
+	projects[1].HasLockfile = false
+	projects[1].Dependencies[1].IsFloating = true
+
+	summary := gen.GenerateSummary(ctx, projects)
+
+	assert.Equal(t, 1, summary["floating_dependencies"])
+	assert.Equal(t, 1, summary["projects_without_lockfile"])
+
+	issues := summary["pinning_issues"].([]map[string]interface{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, projects[1], issues[0]["project"])
+	assert.Equal(t, []string{"react"}, issues[0]["floating"])
+}
+
 // createSameRepositoryTestProjects creates test projects from the same repository with different paths
 func createSameRepositoryTestProjects() []*domain.Project {
 	return []*domain.Project{
@@ -306,6 +333,425 @@ func TestGenerateMatrix(t *testing.T) {
 	assert.Nil(t, project2Row[authIndex])
 }
 
+func TestGenerateMatrix_OutdatedUsesConstraints(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+
+	projects := []*domain.Project{
+		{
+			ID: "a", Repository: domain.Repository{Name: "a"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"}},
+		},
+		{
+			ID: "b", Repository: domain.Repository{Name: "b"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{{Name: "lodash", Version: "^4.17.0", Ecosystem: "npm"}},
+		},
+		{
+			ID: "c", Repository: domain.Repository{Name: "c"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{{Name: "lodash", Version: "4.9", Ecosystem: "npm"}},
+		},
+	}
+
+	matrix := gen.GenerateMatrix(context.Background(), projects)
+	cells := matrix["matrix"].([][]interface{})
+
+	assert.Equal(t, "4.17.21", cells[0][0].(map[string]interface{})["max_version"], "Ranges never become the max")
+	assert.Equal(t, false, cells[0][0].(map[string]interface{})["is_outdated"])
+	assert.Equal(t, false, cells[1][0].(map[string]interface{})["is_outdated"], "Range allows the max version")
+	assert.Equal(t, true, cells[2][0].(map[string]interface{})["is_outdated"], "Two-segment versions compare numerically")
+}
+
+func TestGenerateMatrix_OutdatedAgainstLatestRelease(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+
+	projects := []*domain.Project{
+		{
+			ID: "a", Repository: domain.Repository{Name: "a"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.21", LatestVersion: "4.17.21", Ecosystem: "npm"},
+			},
+		},
+		{
+			ID: "b", Repository: domain.Repository{Name: "b"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.21", LatestVersion: "4.18.0", Ecosystem: "npm"},
+			},
+		},
+	}
+
+	matrix := gen.GenerateMatrix(context.Background(), projects)
+	cells := matrix["matrix"].([][]interface{})
+
+	for i := range projects {
+		cell := cells[i][0].(map[string]interface{})
+		assert.Equal(t, "4.18.0", cell["max_version"], "The registry's latest release counts although no project uses it")
+		assert.Equal(t, true, cell["is_outdated"])
+	}
+}
+
+func TestGenerateMatrix_MatrixMode(t *testing.T) {
+	t.Parallel()
+
+	projects := []*domain.Project{
+		{
+			ID: "a", Repository: domain.Repository{Name: "a"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.20", Constraint: "^4.17.0", LatestVersion: "4.18.0", Ecosystem: "npm"},
+			},
+		},
+		{
+			ID: "b", Repository: domain.Repository{Name: "b"}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.21", Constraint: "4.17.21", LatestVersion: "4.18.0", Ecosystem: "npm"},
+			},
+		},
+	}
+	cell := func(gen *generator.Generator, row int) map[string]interface{} {
+		matrix := gen.GenerateMatrix(context.Background(), projects)
+		return matrix["matrix"].([][]interface{})[row][0].(map[string]interface{})
+	}
+
+	resolved := cell(generator.NewGenerator("/tmp/test.html"), 0)
+	assert.Equal(t, "4.17.20", resolved["label"], "cells show resolved versions by default")
+	assert.Empty(t, resolved["label_constraint"])
+	assert.Equal(t, "4.18.0", resolved["max_version"])
+
+	constraint := generator.NewGenerator("/tmp/test.html").WithMatrixMode(generator.MatrixCellConstraint, "")
+	assert.Equal(t, "^4.17.0", cell(constraint, 0)["label"])
+
+	both := generator.NewGenerator("/tmp/test.html").WithMatrixMode(generator.MatrixCellBoth, "")
+	assert.Equal(t, "4.17.20", cell(both, 0)["label"])
+	assert.Equal(t, "^4.17.0", cell(both, 0)["label_constraint"])
+	assert.Empty(t, cell(both, 1)["label_constraint"], "constraints equal to the version are not repeated")
+
+	portfolio := generator.NewGenerator("/tmp/test.html").WithMatrixMode("", generator.MatrixComparePortfolio)
+	assert.Equal(t, "4.17.21", cell(portfolio, 0)["max_version"], "unused releases are not caught up with")
+	assert.Equal(t, true, cell(portfolio, 0)["is_outdated"])
+	assert.Equal(t, false, cell(portfolio, 1)["is_outdated"])
+
+	registry := generator.NewGenerator("/tmp/test.html").WithMatrixMode("", generator.MatrixCompareRegistry)
+	assert.Equal(t, "4.18.0", cell(registry, 1)["max_version"])
+	assert.Equal(t, true, cell(registry, 1)["is_outdated"])
+}
+
+func TestGenerateMatrix_DriftLevels(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+
+	projects := make([]*domain.Project, 0, 3)
+	for i, v := range []string{"5.0.0", "4.17.21", "5.0.0-rc.1"} {
+		name := string(rune('a' + i))
+		projects = append(projects, &domain.Project{
+			ID: name, Repository: domain.Repository{Name: name}, Language: "nodejs",
+			Dependencies: []*domain.Dependency{{Name: "lodash", Version: v, Ecosystem: "npm"}},
+		})
+	}
+
+	cells := gen.GenerateMatrix(context.Background(), projects)["matrix"].([][]interface{})
+	drift := func(row int) interface{} { return cells[row][0].(map[string]interface{})["drift"] }
+
+	assert.Equal(t, "", drift(0), "Current versions carry no drift")
+	assert.Equal(t, "major", drift(1))
+	assert.Equal(t, "minor", drift(2), "A pre-release of the same major version lags within it")
+}
+
+func TestGenerateMatrix_VersionConflict(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+	ctx := context.Background()
+
+	conflictVersions := []string{"4.17.20", "4.17.21"}
+	projects := []*domain.Project{
+		{
+			ID:         "web",
+			Repository: domain.Repository{Name: "web"},
+			Language:   "nodejs",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.21", Ecosystem: "npm", ConflictVersions: conflictVersions},
+				{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", ConflictVersions: conflictVersions},
+			},
+		},
+	}
+
+	matrix := gen.GenerateMatrix(ctx, projects)
+	matrixData := matrix["matrix"].([][]interface{})
+	require.Len(t, matrixData, 1)
+	require.Len(t, matrixData[0], 1)
+
+	cell := matrixData[0][0].(map[string]interface{})
+	assert.Equal(t, "4.17.21", cell["version"], "Highest resolved version should be displayed")
+	assert.Equal(t, true, cell["has_conflict"])
+	assert.Equal(t, conflictVersions, cell["conflict_versions"])
+}
+
+func TestGenerateMatrix_CanonicalVersionCount(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+
+	projects := []*domain.Project{
+		{ID: "a", Repository: domain.Repository{Name: "a"}, Dependencies: []*domain.Dependency{
+			{Name: "github.com/pkg/errors", Version: "v0.9"},
+		}},
+		{ID: "b", Repository: domain.Repository{Name: "b"}, Dependencies: []*domain.Dependency{
+			{Name: "github.com/pkg/errors", Version: "0.9.0"},
+		}},
+		{ID: "c", Repository: domain.Repository{Name: "c"}, Dependencies: []*domain.Dependency{
+			{Name: "github.com/pkg/errors", Version: "0.9.1"},
+		}},
+	}
+
+	matrix := gen.GenerateMatrix(context.Background(), projects)
+	dependencies := matrix["dependencies"].([]map[string]interface{})
+	require.Len(t, dependencies, 1)
+	assert.Equal(t, 2, dependencies[0]["version_count"], "v0.9 and 0.9.0 are the same version")
+}
+
+func TestGenerateMatrix_ConstraintMismatch(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+	ctx := context.Background()
+
+	projects := []*domain.Project{
+		{
+			ID:         "web",
+			Repository: domain.Repository{Name: "web"},
+			Language:   "nodejs",
+			Dependencies: []*domain.Dependency{
+				{
+					Name: "express", Version: "4.18.2", Ecosystem: "npm",
+					ConstraintMismatch: true, DeclaredConstraint: "^5.0.0",
+				},
+			},
+		},
+	}
+
+	matrix := gen.GenerateMatrix(ctx, projects)
+	cell := matrix["matrix"].([][]interface{})[0][0].(map[string]interface{})
+	assert.Equal(t, true, cell["constraint_mismatch"])
+	assert.Equal(t, "^5.0.0", cell["declared_constraint"])
+}
+
+func TestGenerateMatrix_SortByHealth(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html").WithSortBy("health")
+	ctx := context.Background()
+
+	projects := createTestProjects()
+	projects[0].Health = &domain.HealthScore{Score: 90}
+	projects[1].Health = &domain.HealthScore{Score: 40}
+
+	matrix := gen.GenerateMatrix(ctx, projects)
+	matrixProjects := matrix["projects"].([]*domain.Project)
+
+	require.Len(t, matrixProjects, 2)
+	assert.Equal(t, "test-project-2", matrixProjects[0].ID, "Least healthy project should come first")
+
+	summary := gen.GenerateSummary(ctx, projects)
+	assert.InDelta(t, 65.0, summary["average_health"], 0.001)
+}
+
+func TestGenerateHTML_HealthScore(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	gen := generator.NewGenerator(outputPath)
+
+	projects := createTestProjects()
+	projects[0].Health = &domain.HealthScore{Score: 72.5, Drift: 0.5}
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Health 72.5")
+	assert.Contains(t, content, "Drift 50%")
+	assert.Contains(t, content, `data-health="72.5"`)
+}
+
+func TestGenerateHTML_FileWarnings(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	gen := generator.NewGenerator(outputPath)
+
+	projects := createTestProjects()
+	projects[1].Warnings = []domain.FileWarning{
+		{File: "android/build.gradle", Capability: domain.FileUnsupported, Message: "no parser is available for build.gradle"},
+		{File: "web/package-lock.json", Capability: domain.FileParsed, Message: "unexpected EOF", Fallback: "web/package.json"},
+	}
+
+	summary := gen.GenerateSummary(context.Background(), projects)
+	assert.Len(t, summary["file_warnings"], 2)
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Scan Warnings")
+	assert.Contains(t, content, "android/build.gradle")
+	assert.Contains(t, content, "no parser is available for build.gradle")
+	assert.Contains(t, content, "Degraded data: declared dependencies from web/package.json are used instead")
+}
+
+func TestGenerateHTML_Offline(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	projects := createTestProjects()
+	projects[0].Dependencies[0].Stale = true
+
+	require.NoError(t, generator.NewGenerator(outputPath).GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.NotContains(t, content, "Generated in offline mode")
+
+	require.NoError(t, generator.NewGenerator(outputPath).WithOffline(true).GenerateHTML(context.Background(), projects))
+	content = verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Generated in offline mode")
+	assert.Contains(t, content, "enrichment data was not in the local cache")
+}
+
+func TestGenerateHTML_Vulnerabilities(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	projects := createTestProjects()
+	require.NoError(t, generator.NewGenerator(outputPath).WithVulnerabilityScan(true).
+		GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "No known vulnerabilities in the resolved versions")
+
+	dep := projects[0].Dependencies[0]
+	dep.Vulnerabilities = []domain.Vulnerability{
+		{ID: "GHSA-29mw-wpgm-hmr9", Aliases: []string{"CVE-2020-28500"}, Severity: domain.SeverityMedium},
+		{ID: "GHSA-35jh-r3h4-6jhm", Aliases: []string{"CVE-2021-23337"}, Severity: domain.SeverityHigh,
+			Summary: "Command Injection in lodash"},
+	}
+	dep.VulnCount = 2
+
+	summary := generator.NewGenerator(outputPath).GenerateSummary(context.Background(), projects)
+	assert.Equal(t, 1, summary["vulnerable_dependencies"])
+	rows := summary["vulnerabilities"].([]map[string]interface{})
+	require.Len(t, rows, 2)
+	assert.Equal(t, "GHSA-35jh-r3h4-6jhm", rows[0]["vulnerability"].(domain.Vulnerability).ID, "Most severe first")
+
+	require.NoError(t, generator.NewGenerator(outputPath).WithVulnerabilityScan(true).
+		GenerateHTML(context.Background(), projects))
+	content = verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Known vulnerabilities: CVE-2020-28500, CVE-2021-23337")
+	assert.Contains(t, content, "Command Injection in lodash")
+	assert.Contains(t, content, "https://osv.dev/vulnerability/GHSA-35jh-r3h4-6jhm")
+}
+
+func TestGenerateHTML_Anonymized(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	projects := createTestProjects()
+	gen := generator.NewGenerator(outputPath).WithAnonymizer(anonymize.New("salt"))
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	for _, project := range projects {
+		assert.NotContains(t, content, ">"+project.Repository.Name+"<")
+		if project.Repository.WebURL != "" {
+			assert.NotContains(t, content, project.Repository.WebURL)
+		}
+		for _, dep := range project.Dependencies {
+			assert.Contains(t, content, dep.Name)
+		}
+	}
+}
+
+func TestGenerateScopedMatrix(t *testing.T) {
+	t.Parallel()
+	gen := generator.NewGenerator("/tmp/test.html")
+	ctx := context.Background()
+	projects := createTestProjects()
+
+	internal := gen.GenerateScopedMatrix(ctx, projects, generator.MatrixScopeInternal)
+	internalDeps := internal["dependencies"].([]map[string]interface{})
+	require.Len(t, internalDeps, 1)
+	assert.Equal(t, "internal/company/auth", internalDeps[0]["name"])
+	assert.Len(t, internal["projects"].([]*domain.Project), 1, "Projects without internal deps are dropped")
+
+	external := gen.GenerateScopedMatrix(ctx, projects, generator.MatrixScopeExternal)
+	assert.Len(t, external["dependencies"].([]map[string]interface{}), 3)
+	assert.Len(t, external["projects"].([]*domain.Project), 2)
+
+	// Source projects must stay untouched
+	assert.Len(t, projects[0].Dependencies, 2)
+
+	assert.True(t, generator.IsValidMatrixScope("internal"))
+	assert.False(t, generator.IsValidMatrixScope("vendored"))
+}
+
+func TestGenerateHTML_MultipleMatrices(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	gen := generator.NewGenerator(outputPath).
+		WithMatrixScopes([]string{generator.MatrixScopeInternal, generator.MatrixScopeExternal})
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Internal Dependencies")
+	assert.Contains(t, content, "External Dependencies")
+	assert.Equal(t, 2, strings.Count(content, `class="matrix-body"`))
+
+	// Matrices are tabs, each grid labelled by its title
+	assert.Contains(t, content, `<div role="tablist"`)
+	assert.Equal(t, 2, strings.Count(content, `role="tab" id=`))
+	assert.Contains(t, content, `aria-controls="panel-internal"`)
+	assert.Contains(t, content, `aria-label="External Dependencies"`)
+}
+
+func TestGenerateHTML_Accessibility(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	require.NoError(t, generator.NewGenerator(outputPath).GenerateHTML(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `role="grid"`)
+	assert.NotContains(t, content, `<div role="tablist"`, "A single matrix needs no tabs")
+	assert.Contains(t, content, "Skip to dependency matrix")
+	assert.Contains(t, content, `<th scope="row" tabindex="-1"`)
+	assert.Equal(t, 1, strings.Count(content, `tabindex="0"`), "Only one grid cell is in the tab order")
+	assert.Contains(t, content, `<span class="sr-only">external</span>`)
+	assert.Contains(t, content, "(opens in a new tab)")
+}
+
+func TestGenerateHTML_Baseline(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+
+	baseline := createTestProjects()
+	baseline[0].Dependencies[0].Version = "v1.8.0"          // gin upgraded since baseline
+	baseline[1].Dependencies = baseline[1].Dependencies[:1] // react is new
+	baseline[1].Dependencies = append(baseline[1].Dependencies, &domain.Dependency{Name: "lodash", Version: "4.17.21"})
+
+	gen := generator.NewGenerator(outputPath).WithBaseline(baseline)
+	projects := createTestProjects()
+
+	matrix := gen.GenerateMatrix(context.Background(), projects)
+	var ginCell map[string]interface{}
+	for j, dep := range matrix["dependencies"].([]map[string]interface{}) {
+		if dep["name"] == "github.com/gin-gonic/gin" {
+			ginCell = matrix["matrix"].([][]interface{})[0][j].(map[string]interface{})
+		}
+	}
+	require.NotNil(t, ginCell)
+	assert.Equal(t, "upgraded", ginCell["change"])
+	assert.Equal(t, "v1.8.0", ginCell["previous_version"])
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Changes Since Baseline")
+	assert.Contains(t, content, "New: 1")
+	assert.Contains(t, content, "Removed: 1")
+	assert.Contains(t, content, "Upgraded: 1")
+	assert.Contains(t, content, "▲ v1.8.0")
+	assert.Contains(t, content, "★ new")
+}
+
 // Helper function to verify file creation and basic content
 func verifyFileCreated(t *testing.T, outputPath string) string {
 	// Check if file was created
@@ -463,6 +909,48 @@ func TestGenerateJSON(t *testing.T) {
 	verifyJSONProjectData(t, jsonContent)
 }
 
+func TestGenerateSARIF(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	sarifPath := filepath.Join(dir, "reports", "matrix.sarif")
+
+	gen := generator.NewGenerator(filepath.Join(dir, "report.html")).WithSARIFOutput(sarifPath)
+	gen.RecordViolations([]domain.PolicyViolation{
+		{Rule: "pinning.floating", ProjectID: "test-project-1", Dependency: "github.com/gin-gonic/gin", Message: "floating"},
+	})
+	require.NoError(t, gen.GenerateSARIF(context.Background(), createTestProjects()))
+
+	var log sarif.Log
+	require.NoError(t, json.Unmarshal([]byte(verifyFileCreated(t, sarifPath)), &log))
+	require.Len(t, log.Runs, 1)
+	var rules []string
+	for _, result := range log.Runs[0].Results {
+		rules = append(rules, result.RuleID)
+	}
+	assert.Contains(t, rules, "pinning.floating")
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	markdownPath := filepath.Join(dir, "reports", "matrix.md")
+
+	gen := generator.NewGenerator(filepath.Join(dir, "report.html")).WithMarkdownOutput(markdownPath)
+	gen.RecordViolations([]domain.PolicyViolation{
+		{Rule: "lockfile.missing", ProjectID: "test-project-2", Message: "No lockfile | committed"},
+	})
+	require.NoError(t, gen.GenerateMarkdown(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, markdownPath)
+	assert.Contains(t, content, "2 projects, 4 dependencies (1 internal, 3 external).")
+	assert.Contains(t, content, "| internal/company/auth | go-modules | internal | v1.0.0 | `v1.0.0` (Test Project 1) |")
+	assert.Contains(t, content, "| express | npm | external | **4.19.0** | `4.18.2` (Test Project 2) |",
+		"latest releases ahead of every version in use are bold")
+	assert.Less(t, strings.Index(content, "internal/company/auth"), strings.Index(content, "express"),
+		"internal dependencies first")
+	assert.Contains(t, content, "| Test Project 2 | lockfile.missing | No lockfile \\| committed |")
+}
+
 func TestGenerateHTML_EmptyProjects(t *testing.T) {
 	t.Parallel()
 	tempDir := t.TempDir()
@@ -636,6 +1124,10 @@ func TestGenerateCSV_SpecialCharacters(t *testing.T) {
 		"Constraint",
 		"Is Internal",
 		"Ecosystem",
+		"Vulnerabilities",
+		"Max Severity",
+		"Advisories",
+		"Lifecycle",
 	}, records[0])
 
 	// Verify data integrity - check that special characters are preserved
@@ -791,3 +1283,461 @@ func createTestProjects() []*domain.Project {
 		},
 	}
 }
+
+func TestGenerate_OutputPaths(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	jsonPath := filepath.Join(dir, "reports", "report.json")
+	csvPath := filepath.Join(dir, "reports", "report.csv")
+
+	projects := createTestProjects()
+	gen := generator.NewGenerator(outputPath).WithJSONOutput(jsonPath).WithCSVOutput(csvPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	assert.NoFileExists(t, jsonPath, "Every format writes only its own artifact")
+
+	require.NoError(t, gen.GenerateCSV(context.Background(), projects))
+	require.NoError(t, gen.GenerateJSON(context.Background(), projects))
+
+	assert.Contains(t, verifyFileCreated(t, outputPath), "<html")
+	assert.Contains(t, verifyFileCreated(t, csvPath), "Project ID,Project Name")
+	content := verifyFileCreated(t, jsonPath)
+	assert.Contains(t, content, `"schema_version": "`+report.SchemaVersion+`"`)
+
+	loaded, err := diff.LoadReport(jsonPath)
+	require.NoError(t, err)
+	assert.Len(t, loaded, len(projects))
+}
+
+func TestGenerateHTML_Incomplete(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	jsonPath := filepath.Join(dir, "report.json")
+
+	gen := generator.NewGenerator(outputPath).WithJSONOutput(jsonPath)
+	gen.MarkIncomplete("the analysis was interrupted, 3 of 5 repositories were not analyzed")
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	require.NoError(t, gen.GenerateJSON(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Incomplete report:")
+	assert.Contains(t, content, "3 of 5 repositories were not analyzed")
+	assert.Contains(t, verifyFileCreated(t, jsonPath),
+		`"incomplete": "the analysis was interrupted, 3 of 5 repositories were not analyzed"`)
+
+	// Complete reports carry neither
+	complete := filepath.Join(dir, "complete.html")
+	require.NoError(t, generator.NewGenerator(complete).GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, complete), "Incomplete report:")
+}
+
+func TestGenerateHTML_Annotations(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	jsonPath := filepath.Join(dir, "report.json")
+
+	set := annotations.Set{
+		"github.com/gin-gonic/gin": {Owner: "platform-team", Replacement: "use internal http-kit instead of gin"},
+		"left-pad":                 {Note: "Not used by any project"},
+	}
+	gen := generator.NewGenerator(outputPath).WithJSONOutput(jsonPath).WithAnnotations(set)
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	require.NoError(t, gen.GenerateJSON(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Owner: platform-team · Replacement: use internal http-kit instead of gin")
+	assert.NotContains(t, content, "Not used by any project")
+
+	var written report.Report
+	require.NoError(t, json.Unmarshal([]byte(verifyFileCreated(t, jsonPath)), &written))
+	require.Len(t, written.Annotations, 1, "Only dependencies in the report are annotated")
+	assert.Equal(t, "platform-team", written.Annotations["github.com/gin-gonic/gin"].Owner)
+
+	// Anonymized reports are shared outside, team knowledge stays inside
+	anonymizedPath := filepath.Join(dir, "anonymized.html")
+	require.NoError(t, generator.NewGenerator(anonymizedPath).
+		WithAnnotations(set).
+		WithAnonymizer(anonymize.New("salt")).
+		GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, anonymizedPath), "platform-team")
+}
+
+func TestGenerateHTML_Coverage(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	jsonPath := filepath.Join(dir, "report.json")
+
+	gen := generator.NewGenerator(outputPath).WithJSONOutput(jsonPath)
+	gen.RecordCoverage([]domain.RepositoryCoverage{{
+		Repository: domain.Repository{Name: "billing-service", URL: "https://gitlab.com/fin/billing"},
+		Analyzed:   3,
+		Excluded:   2,
+		Gaps: []domain.CoverageGap{
+			{File: "legacy/go.mod", Reason: domain.GapUnparsed, Message: "legacy/go.mod: unexpected EOF"},
+		},
+	}})
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	require.NoError(t, gen.GenerateJSON(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Manifest Coverage")
+	assert.Contains(t, content, "legacy/go.mod")
+	assert.Contains(t, content, "75.0%")
+
+	var written report.Report
+	require.NoError(t, json.Unmarshal([]byte(verifyFileCreated(t, jsonPath)), &written))
+	require.Len(t, written.Coverage, 1)
+	assert.Equal(t, "billing-service", written.Coverage[0].Repository)
+	assert.Equal(t, 2, written.Coverage[0].Excluded)
+	assert.InDelta(t, 75.0, written.Coverage[0].Percent, 0.001)
+	require.Len(t, written.Coverage[0].Gaps, 1)
+	assert.Equal(t, "unparsed", written.Coverage[0].Gaps[0].Reason)
+}
+
+func TestGenerateHTML_Violations(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+
+	gen := generator.NewGenerator(outputPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, outputPath), "Policy Violations", "no section without violations")
+
+	gen.RecordViolations([]domain.PolicyViolation{{
+		Rule:       "dependency.denied",
+		ProjectID:  "test-project-1",
+		Dependency: "github.com/gin-gonic/gin",
+		Message:    "github.com/gin-gonic/gin v1.9.1 is denied by \"github.com/gin-gonic/*\"",
+	}})
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Policy Violations")
+	assert.Contains(t, content, "dependency.denied")
+	assert.Contains(t, content, "test-repo-1", "violations name their project")
+}
+
+func TestGenerate_Lifecycle(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	csvPath := filepath.Join(dir, "report.csv")
+	jsonPath := filepath.Join(dir, "report.json")
+
+	gen := generator.NewGenerator(outputPath).WithCSVOutput(csvPath).WithJSONOutput(jsonPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, outputPath), "Deprecated and End-of-Life Dependencies",
+		"no section without deprecated dependencies")
+
+	projects := createTestProjects()
+	gin := projects[0].Dependencies[0]
+	gin.IsDeprecated, gin.Deprecation = true, "deprecated: moved to gin/v2"
+	gin.IsEndOfLife, gin.EndOfLife = true, "2024-06-30"
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	require.NoError(t, gen.GenerateCSV(context.Background(), projects))
+	require.NoError(t, gen.GenerateJSON(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Deprecated and End-of-Life Dependencies")
+	assert.Contains(t, content, `title="deprecated: moved to gin/v2">deprecated</span>`)
+	assert.Contains(t, content, "Release cycle out of support since 2024-06-30")
+	assert.Contains(t, verifyFileCreated(t, csvPath), "deprecated end-of-life")
+
+	var written report.Report
+	require.NoError(t, json.Unmarshal([]byte(verifyFileCreated(t, jsonPath)), &written))
+	assert.Equal(t, 1, written.Summary.DeprecatedDependencies)
+	assert.Equal(t, 1, written.Summary.EndOfLifeDependencies)
+	assert.Equal(t, "2024-06-30", written.Projects[0].Dependencies[0].EndOfLife)
+}
+
+func TestGenerateHTML_CustomTemplate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "out", "report.html")
+
+	assets := filepath.Join(dir, "branding")
+	require.NoError(t, os.MkdirAll(filepath.Join(assets, "img"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(assets, "theme.css"), []byte("body { color: teal; }"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(assets, "img", "logo.svg"), []byte("<svg/>"), 0o600))
+
+	templatePath := filepath.Join(dir, "report.tmpl.html")
+	require.NoError(t, os.WriteFile(templatePath, []byte(
+		`<h1>Acme {{.Title}}</h1>{{range .Projects}}<p>{{.Name}}</p>{{end}}{{range .Assets}}<a href="{{.}}"></a>{{end}}`,
+	), 0o600))
+
+	gen := generator.NewGenerator(outputPath).WithTemplate(templatePath, assets)
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "<h1>Acme Dependency Matrix Report</h1>")
+	assert.Contains(t, content, "<p>Test Project 1</p>")
+	assert.Contains(t, content, `<a href="assets/img/logo.svg"></a>`)
+	assert.FileExists(t, filepath.Join(dir, "out", "assets", "img", "logo.svg"))
+
+	themed := filepath.Join(dir, "themed.html")
+	require.NoError(t, generator.NewGenerator(themed).WithTemplate("", assets).
+		GenerateHTML(context.Background(), createTestProjects()))
+	assert.Contains(t, verifyFileCreated(t, themed), `<link rel="stylesheet" href="assets/theme.css">`,
+		"the embedded template links the stylesheets")
+
+	require.NoError(t, os.WriteFile(templatePath, []byte(`{{.Missing`), 0o600))
+	assert.Error(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	assert.Error(t, generator.NewGenerator(outputPath).WithTemplate(filepath.Join(dir, "missing.html"), "").
+		GenerateHTML(context.Background(), createTestProjects()))
+}
+
+func TestGenerateHTML_Drift(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+
+	gen := generator.NewGenerator(outputPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, outputPath), "Version Drift", "no section without drift")
+
+	projects := createTestProjects()
+	projects[1].Dependencies = append(projects[1].Dependencies, &domain.Dependency{
+		Name: "github.com/gin-gonic/gin", Version: "v1.8.0", Ecosystem: "go-modules",
+	})
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "Version Drift")
+	assert.Regexp(t, `v1\.9\.1</span>:\s+test-repo-1`, content, "versions list the projects using them")
+	assert.Regexp(t, `v1\.8\.0</span>:\s+test-repo-2`, content)
+}
+
+func TestGenerateHTML_Ref(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+	jsonPath := filepath.Join(dir, "report.json")
+
+	projects := createTestProjects()
+	projects[0].Repository.DefaultBranch = "main"
+	projects[0].Repository.Ref = "release-1.2"
+	gen := generator.NewGenerator(outputPath).WithJSONOutput(jsonPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	require.NoError(t, gen.GenerateJSON(context.Background(), projects))
+
+	assert.Contains(t, verifyFileCreated(t, outputPath), "ref: release-1.2")
+
+	var written report.Report
+	require.NoError(t, json.Unmarshal([]byte(verifyFileCreated(t, jsonPath)), &written))
+	refs := make(map[string]string)
+	for _, project := range written.Projects {
+		refs[project.Repository.Name] = project.Repository.Ref
+	}
+	assert.Equal(t, "release-1.2", refs["test-repo-1"])
+}
+
+func TestGenerate_TransitiveDependencies(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	projects := []*domain.Project{{
+		ID:         "web",
+		Name:       "Web",
+		Repository: domain.Repository{Name: "web"},
+		Dependencies: []*domain.Dependency{
+			{Name: "express", Version: "4.18.2", Ecosystem: "npm", Direct: true},
+			{Name: "body-parser", Version: "1.20.1", Ecosystem: "npm", Parents: []string{"express"}},
+		},
+	}}
+
+	included := generator.NewGenerator(filepath.Join(dir, "included.html")).
+		WithJSONOutput(filepath.Join(dir, "included.json"))
+	require.NoError(t, included.GenerateHTML(context.Background(), projects))
+	require.NoError(t, included.GenerateJSON(context.Background(), projects))
+	assert.Contains(t, verifyFileCreated(t, filepath.Join(dir, "included.html")), "Transitive dependency via express")
+
+	loaded, err := diff.LoadReport(filepath.Join(dir, "included.json"))
+	require.NoError(t, err)
+	require.Len(t, loaded[0].Dependencies, 2)
+	assert.True(t, loaded[0].Dependencies[0].Direct, "direct dependencies survive the JSON round trip")
+	assert.False(t, loaded[0].Dependencies[1].Direct)
+
+	collapsed := generator.NewGenerator(filepath.Join(dir, "collapsed.csv")).WithTransitiveCollapsed(true)
+	require.NoError(t, collapsed.GenerateCSV(context.Background(), projects))
+	content := verifyFileCreated(t, filepath.Join(dir, "collapsed.csv"))
+	assert.Contains(t, content, "express")
+	assert.NotContains(t, content, "body-parser")
+	assert.Len(t, projects[0].Dependencies, 2, "collapsing leaves the analyzed projects intact")
+}
+
+func TestGenerateHTML_DependencyScopes(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "scopes.html")
+	projects := []*domain.Project{
+		{ID: "web", Name: "Web", Repository: domain.Repository{Name: "web"}, Dependencies: []*domain.Dependency{
+			{Name: "react", Version: "18.2.0", Ecosystem: "npm", Scope: domain.ScopeRuntime},
+			{Name: "jest", Version: "29.7.0", Ecosystem: "npm", Scope: domain.ScopeDev},
+		}},
+		{ID: "admin", Name: "Admin", Repository: domain.Repository{Name: "admin"}, Dependencies: []*domain.Dependency{
+			{Name: "jest", Version: "29.7.0", Ecosystem: "npm"},
+		}},
+	}
+
+	gen := generator.NewGenerator(outputPath)
+	matrix := gen.GenerateMatrix(context.Background(), projects)
+	for _, dep := range matrix["dependencies"].([]map[string]interface{}) {
+		if dep["name"] == "jest" {
+			assert.Equal(t, []string{"runtime", "dev"}, dep["scopes"], "dependencies without a scope are runtime")
+		}
+	}
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `id="scope-filter"`)
+	assert.Contains(t, content, `<input type="checkbox" value="dev" checked`)
+	assert.Contains(t, content, `data-scope="dev"`)
+	assert.Contains(t, content, `data-scopes="runtime dev"`)
+}
+
+func TestGenerateHTML_Filters(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "filters.html")
+	projects := []*domain.Project{
+		{ID: "web", Name: "Web", Repository: domain.Repository{Name: "web"}, Path: "frontend",
+			Dependencies: []*domain.Dependency{{Name: "react", Version: "18.2.0", Ecosystem: "npm"}}},
+		{ID: "api", Name: "API", Repository: domain.Repository{Name: "api"}, Dependencies: []*domain.Dependency{
+			{Name: "github.com/acme/kit", Version: "v1.2.0", Ecosystem: "go-modules", IsInternal: true},
+		}},
+	}
+
+	require.NoError(t, generator.NewGenerator(outputPath).GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `id="project-search"`)
+	assert.Contains(t, content, `id="dependency-search"`)
+	assert.Contains(t, content, `<option value="go-modules">go-modules</option>`)
+	assert.Contains(t, content, `id="origin-filter"`)
+	assert.Contains(t, content, `data-name="github.com/acme/kit" data-ecosystem="go-modules" data-internal="true"`)
+	assert.Contains(t, content, `data-name="react" data-ecosystem="npm" data-internal="false"`)
+	assert.Contains(t, content, `data-project="web frontend"`)
+	assert.Contains(t, content, `data-version="18.2.0"`)
+	assert.Contains(t, content, `onclick="sortByColumn(this)"`)
+	assert.NotContains(t, content, `id="scope-filter"`, "no scope filter with a single scope")
+}
+
+func TestGenerateHTML_Virtualized(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.html")
+
+	require.NoError(t, generator.NewGenerator(outputPath).GenerateHTML(context.Background(), createTestProjects()))
+	assert.NotContains(t, verifyFileCreated(t, outputPath), `class="virtual-matrix`, "small matrices stay static")
+
+	projects := createTestProjects()
+	projects[0].Dependencies[0].Scope = domain.ScopeDev
+	require.NoError(t, generator.NewGenerator(outputPath).WithVirtualization(1).
+		GenerateHTML(context.Background(), projects))
+
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `class="virtual-matrix`)
+	assert.NotContains(t, content, `class="matrix-body"`, "no static table")
+
+	start := strings.Index(content, `<script type="application/json" id="matrix-data-combined">`)
+	require.NotEqual(t, -1, start)
+	data := content[strings.Index(content[start:], ">")+start+1:]
+	data = data[:strings.Index(data, "</script>")]
+
+	var matrix struct {
+		Dependencies []struct {
+			Name     string `json:"name"`
+			Internal bool   `json:"internal"`
+		} `json:"dependencies"`
+		Projects []struct {
+			Name   string  `json:"name"`
+			Path   string  `json:"path"`
+			Health float64 `json:"health"`
+		} `json:"projects"`
+		Cells []map[string]struct {
+			Label  string   `json:"v"`
+			Scope  string   `json:"s"`
+			Badges []string `json:"b"`
+		} `json:"cells"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(data), &matrix), "the matrix is embedded as JSON")
+	assert.Len(t, matrix.Projects, 2)
+	assert.Equal(t, "test-repo-1", matrix.Projects[0].Name)
+	assert.NotEmpty(t, matrix.Dependencies)
+	require.Len(t, matrix.Cells, 2)
+
+	found := false
+	for _, cells := range matrix.Cells {
+		for _, cell := range cells {
+			if cell.Label == "v1.9.1" && cell.Scope == domain.ScopeDev {
+				found = true
+				assert.Contains(t, cell.Badges, domain.ScopeDev)
+			}
+		}
+	}
+	assert.True(t, found, "cells carry versions, scopes and markers")
+}
+
+func TestGenerateHTML_VersionRanges(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "ranges.html")
+	projects := []*domain.Project{
+		{ID: "web", Name: "Web", Repository: domain.Repository{Name: "web"}, Dependencies: []*domain.Dependency{
+			{Name: "react", Version: "^18.2.0", Constraint: "^18.2.0", MinVersion: "18.2.0", MaxVersion: "19.0.0",
+				Ecosystem: "npm"},
+			{Name: "lodash", Version: "4.17.21", Constraint: "4.17.21", MinVersion: "4.17.21", MaxVersion: "4.17.21",
+				Ecosystem: "npm"},
+		}},
+	}
+
+	gen := generator.NewGenerator(outputPath)
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `title="Version range: ^18.2.0">range</span>`)
+	assert.NotContains(t, content, `title="Version range: 4.17.21"`, "pinned versions are not ranges")
+}
+
+func TestGenerateHTML_Conflicts(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "conflicts.html")
+	versions := []string{"2.1.2", "2.1.3"}
+	projects := []*domain.Project{
+		{ID: "web", Name: "Web", Repository: domain.Repository{Name: "web"}, Dependencies: []*domain.Dependency{
+			{Name: "ms", Version: "2.1.2", Ecosystem: "npm", ConflictVersions: versions},
+			{Name: "ms", Version: "2.1.3", Ecosystem: "npm", ConflictVersions: versions},
+			{Name: "debug", Version: "4.3.4", Ecosystem: "npm"},
+		}},
+	}
+
+	gen := generator.NewGenerator(outputPath)
+	conflicts := gen.GenerateSummary(context.Background(), projects)["version_conflicts"]
+	assert.Len(t, conflicts, 1, "a package is listed once per project")
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, "<h2 class=\"text-lg font-semibold text-gray-800\">Conflicts</h2>")
+	assert.Contains(t, content, "2.1.2, 2.1.3")
+}
+
+func TestGenerateHTML_InternalLinks(t *testing.T) {
+	t.Parallel()
+	outputPath := filepath.Join(t.TempDir(), "links.html")
+	projects := []*domain.Project{
+		{ID: "auth", Name: "Auth", ModuleName: "gitlab.company.com/auth", Repository: domain.Repository{Name: "auth"},
+			Dependencies: []*domain.Dependency{{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go-modules"}}},
+		{ID: "billing", Name: "Billing", Repository: domain.Repository{Name: "billing"}, Dependencies: []*domain.Dependency{
+			{Name: "gitlab.company.com/auth", Version: "v1.2.0", Ecosystem: "go-modules", IsInternal: true},
+		}},
+	}
+
+	gen := generator.NewGenerator(outputPath)
+	links, ok := gen.GenerateSummary(context.Background(), projects)["internal_links"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, links, 2)
+	assert.Equal(t, "auth", links[0]["project"].(*domain.Project).ID)
+
+	require.NoError(t, gen.GenerateHTML(context.Background(), projects))
+	content := verifyFileCreated(t, outputPath)
+	assert.Contains(t, content, `<tr id="internal-auth">`)
+	assert.Contains(t, content, `<a href="#internal-auth"`, "the matrix cell links to the project building the library")
+	assert.Contains(t, content, `<a class="text-blue-700 underline" href="#internal-billing">billing</a>`)
+}
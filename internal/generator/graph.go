@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/graph"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// GenerateDOT creates a Graphviz DOT graph of projects and the dependencies they use
+func (g *Generator) GenerateDOT(ctx context.Context, projects []*domain.Project) error {
+	dependencyGraph := graph.Build(g.reportProjects(projects), g.internalOnly)
+	return writeGraph(g.graphPath(g.dotPath), dependencyGraph.WriteDOT)
+}
+
+// GenerateMermaid creates a Mermaid flowchart of projects and the dependencies they use
+func (g *Generator) GenerateMermaid(ctx context.Context, projects []*domain.Project) error {
+	dependencyGraph := graph.Build(g.reportProjects(projects), g.internalOnly)
+	return writeGraph(g.graphPath(g.mermaidPath), dependencyGraph.WriteMermaid)
+}
+
+// graphPath returns path, or the output path when it is not set
+func (g *Generator) graphPath(path string) string {
+	if path == "" {
+		return g.outputPath
+	}
+	return path
+}
+
+// writeGraph writes a graph to path with the writer of its format
+func writeGraph(path string, write func(io.Writer) error) error {
+	// Create output directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Create output file
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	if err := write(file); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	return nil
+}
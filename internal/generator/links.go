@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/graph"
+)
+
+// internalLink is an analyzed project consuming the library another analyzed project builds
+type internalLink struct {
+	Project *domain.Project // The other end of the link
+	Version string          // Version of the library in use
+}
+
+// internalAdjacency lists, for every project linked to another, the analyzed projects whose libraries it uses
+// and the analyzed projects using its own library, in project order
+func internalAdjacency(projects []*domain.Project) []map[string]interface{} {
+	providers := graph.Providers(projects)
+	dependsOn := make(map[string][]internalLink)
+	usedBy := make(map[string][]internalLink)
+	linked := make(map[string]bool)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			provider := graph.Provider(providers, project, dep)
+			if provider == nil || linked[project.ID+"\x00"+provider.ID] {
+				continue
+			}
+			linked[project.ID+"\x00"+provider.ID] = true
+			dependsOn[project.ID] = append(dependsOn[project.ID], internalLink{Project: provider, Version: dep.Version})
+			usedBy[provider.ID] = append(usedBy[provider.ID], internalLink{Project: project, Version: dep.Version})
+		}
+	}
+
+	var adjacency []map[string]interface{}
+	for _, project := range projects {
+		if len(dependsOn[project.ID]) == 0 && len(usedBy[project.ID]) == 0 {
+			continue
+		}
+		adjacency = append(adjacency, map[string]interface{}{
+			"project":    project,
+			"depends_on": dependsOn[project.ID],
+			"used_by":    usedBy[project.ID],
+		})
+	}
+	return adjacency
+}
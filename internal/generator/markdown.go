@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WithMarkdownOutput sets the path GenerateMarkdown writes to, instead of the output path
+func (g *Generator) WithMarkdownOutput(path string) *Generator {
+	g.markdownPath = path
+	return g
+}
+
+// markdownRow is a dependency of the Markdown report with the projects using each of its versions
+type markdownRow struct {
+	name      string
+	ecosystem string
+	internal  bool
+	latest    string
+	versions  []string            // Highest version first
+	projects  map[string][]string // Canonical version -> project names
+	outdated  bool                // A project uses a version behind latest
+	vulnCount int
+}
+
+// GenerateMarkdown creates a Markdown report: the summary, one table row per dependency with the projects
+// using each version, and the policy violations. Wikis render it without scripts or styles.
+func (g *Generator) GenerateMarkdown(ctx context.Context, projects []*domain.Project) error {
+	projects = g.reportProjects(projects)
+	path := g.outputPath
+	if g.markdownPath != "" {
+		path = g.markdownPath
+	}
+
+	// Create output directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	summary := g.GenerateSummary(ctx, projects)
+	var b strings.Builder
+	b.WriteString("# Dependency Matrix Report\n\n")
+	if g.incomplete != "" {
+		fmt.Fprintf(&b, "> **Partial report:** %s\n\n", markdownText(g.incomplete))
+	}
+	counts, _ := summary["internal_external"].(map[string]int)
+	fmt.Fprintf(&b, "%d projects, %d dependencies (%d internal, %d external).\n",
+		summary["total_projects"], summary["total_dependencies"], counts["internal"], counts["external"])
+
+	rows := markdownRows(projects)
+	b.WriteString("\n## Dependencies\n\n")
+	if len(rows) == 0 {
+		b.WriteString("No dependencies.\n")
+	} else {
+		b.WriteString("| Dependency | Ecosystem | Type | Latest | Versions in use |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+	}
+	for _, row := range rows {
+		kind := "external"
+		if row.internal {
+			kind = "internal"
+		}
+		name := markdownText(row.name)
+		if row.vulnCount > 0 {
+			name += fmt.Sprintf(" ⚠ %d", row.vulnCount)
+		}
+		latest := markdownText(row.latest)
+		if row.outdated {
+			latest = "**" + latest + "**"
+		}
+		used := make([]string, 0, len(row.versions))
+		for _, depVersion := range row.versions {
+			shown := depVersion
+			if shown == "" {
+				shown = "unknown"
+			}
+			used = append(used, fmt.Sprintf("`%s` (%s)", markdownText(shown),
+				markdownText(strings.Join(row.projects[version.Canonical(depVersion)], ", "))))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", name, markdownText(row.ecosystem), kind, latest,
+			strings.Join(used, "<br>"))
+	}
+
+	if violations := g.reportViolations(projects); len(violations) > 0 {
+		b.WriteString("\n## Policy Violations\n\n")
+		b.WriteString("| Project | Rule | Message |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, row := range violations {
+			project := row.Violation.ProjectID
+			if row.Project != nil {
+				project = row.Project.Name
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownText(project), markdownText(row.Violation.Rule),
+				markdownText(row.Violation.Message))
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+	return nil
+}
+
+// markdownRows groups the dependencies of projects by ecosystem and name, internal dependencies first
+func markdownRows(projects []*domain.Project) []*markdownRow {
+	type key struct{ ecosystem, name string }
+
+	rows := make(map[key]*markdownRow)
+	var keys []key
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			k := key{dep.Ecosystem, dep.Name}
+			row, ok := rows[k]
+			if !ok {
+				row = &markdownRow{
+					name:      dep.Name,
+					ecosystem: dep.Ecosystem,
+					internal:  dep.IsInternal,
+					projects:  make(map[string][]string),
+				}
+				rows[k] = row
+				keys = append(keys, k)
+			}
+			if version.Compare(dep.LatestVersion, row.latest) > 0 {
+				row.latest = dep.LatestVersion
+			}
+			row.vulnCount += dep.VulnCount
+
+			canonical := version.Canonical(dep.Version)
+			if _, seen := row.projects[canonical]; !seen {
+				row.versions = append(row.versions, dep.Version)
+			}
+			// A project listing the dependency more than once uses it once
+			names := row.projects[canonical]
+			if n := len(names); n == 0 || names[n-1] != project.Name {
+				row.projects[canonical] = append(names, project.Name)
+			}
+		}
+	}
+
+	result := make([]*markdownRow, 0, len(keys))
+	for _, k := range keys {
+		row := rows[k]
+		sort.SliceStable(row.versions, func(i, j int) bool {
+			return version.Compare(row.versions[i], row.versions[j]) > 0
+		})
+		for _, depVersion := range row.versions {
+			row.outdated = row.outdated || version.IsOutdated(depVersion, row.latest)
+		}
+		result = append(result, row)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].internal != result[j].internal {
+			return result[i].internal
+		}
+		return strings.ToLower(result[i].name) < strings.ToLower(result[j].name)
+	})
+	return result
+}
+
+// markdownText escapes the characters that would end a Markdown table cell or inline code
+func markdownText(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "`", "'").Replace(value)
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/sarif"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithSARIFOutput sets the path GenerateSARIF writes to, instead of the output path
+func (g *Generator) WithSARIFOutput(path string) *Generator {
+	g.sarifPath = path
+	return g
+}
+
+// GenerateSARIF creates a SARIF log of vulnerable and outdated dependencies and policy violations
+func (g *Generator) GenerateSARIF(ctx context.Context, projects []*domain.Project) error {
+	path := g.outputPath
+	if g.sarifPath != "" {
+		path = g.sarifPath
+	}
+
+	// Create output directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Create output file
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	violations := g.violations
+	if g.anonymizer != nil {
+		violations = g.anonymizer.Violations(violations)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sarif.New(g.reportProjects(projects), violations)); err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+	return nil
+}
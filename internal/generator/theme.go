@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// assetsDirName is the directory next to the HTML report the assets are copied to
+const assetsDirName = "assets"
+
+// Template returns the embedded HTML report template, a starting point for custom templates
+func Template() string {
+	return templateContent
+}
+
+// WithTemplate renders the HTML report with the Go template at templatePath instead of the embedded one, and
+// copies the files of assetsDir next to the report. Empty values keep the embedded template and copy nothing.
+func (g *Generator) WithTemplate(templatePath, assetsDir string) *Generator {
+	g.templatePath = templatePath
+	g.assetsDir = assetsDir
+	return g
+}
+
+// parseTemplate parses the custom template when one is configured, the embedded template otherwise
+func (g *Generator) parseTemplate() (*template.Template, error) {
+	content := templateContent
+	if g.templatePath != "" {
+		custom, err := os.ReadFile(g.templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		content = string(custom)
+	}
+	return template.New("report").Funcs(templateFuncs()).Parse(content)
+}
+
+// copyAssets copies the assets directory to the assets/ directory next to the report and returns the paths of
+// the copied files relative to the report, with forward slashes as the report links them
+func (g *Generator) copyAssets(reportDir string) ([]string, error) {
+	if g.assetsDir == "" {
+		return nil, nil
+	}
+	target := filepath.Join(reportDir, assetsDirName)
+	source, sourceErr := filepath.Abs(g.assetsDir)
+	destination, destinationErr := filepath.Abs(target)
+	inPlace := sourceErr == nil && destinationErr == nil && source == destination
+
+	var assets []string
+	err := filepath.WalkDir(g.assetsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(g.assetsDir, path)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, assetsDirName+"/"+filepath.ToSlash(rel))
+		// Assets already next to the report are linked where they are
+		if inPlace {
+			return nil
+		}
+		return copyAsset(path, filepath.Join(target, rel))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy assets: %w", err)
+	}
+	return assets, nil
+}
+
+// copyAsset copies the file at from to to, creating the directories of to
+func copyAsset(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o750); err != nil {
+		return err
+	}
+	source, err := os.Open(from) //nolint:gosec // Asset paths are set by the configuration
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.Create(to) //nolint:gosec // Asset paths are set by the configuration
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+// stylesheets returns the CSS files among the assets, the embedded template links them to theme the report
+func stylesheets(assets []string) []string {
+	var sheets []string
+	for _, asset := range assets {
+		if strings.EqualFold(filepath.Ext(asset), ".css") {
+			sheets = append(sheets, asset)
+		}
+	}
+	return sheets
+}
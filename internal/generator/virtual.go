@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// DefaultVirtualizeCells is the matrix size, in project × dependency cells, above which the HTML report
+// renders a matrix with virtual scrolling rather than as a static table
+const DefaultVirtualizeCells = 50000
+
+// virtualMatrix is a matrix the HTML report embeds as JSON and renders with virtual scrolling: only the cells
+// in view are in the page, so browsers stay responsive with thousands of dependencies
+type virtualMatrix struct {
+	Dependencies []virtualDependency   `json:"dependencies"`
+	Projects     []virtualProject      `json:"projects"`
+	Cells        []map[int]virtualCell `json:"cells"` // Per project, by dependency column
+}
+
+// virtualDependency is a column of a virtual matrix
+type virtualDependency struct {
+	Name      string   `json:"name"`
+	Ecosystem string   `json:"ecosystem"`
+	Internal  bool     `json:"internal"`
+	Latest    string   `json:"latest,omitempty"`
+	Versions  int      `json:"versions"` // Distinct versions in use
+	Scopes    []string `json:"scopes"`
+}
+
+// virtualProject is a row of a virtual matrix
+type virtualProject struct {
+	Name   string  `json:"name"` // Repository name
+	Path   string  `json:"path"` // "root" for the repository root
+	URL    string  `json:"url,omitempty"`
+	Search string  `json:"search"` // Text the project search matches
+	Health float64 `json:"health"`
+}
+
+// virtualCell is a used dependency of a virtual matrix, with short keys as there are many of them
+type virtualCell struct {
+	Label    string   `json:"v"`
+	Version  string   `json:"r,omitempty"` // Resolved version sorted by, when the label is something else
+	Title    string   `json:"t"`
+	Drift    string   `json:"d,omitempty"`
+	Internal bool     `json:"i,omitempty"`
+	Scope    string   `json:"s"`
+	Badges   []string `json:"b,omitempty"` // Markers the static table shows below the version
+}
+
+// WithVirtualization renders matrices with more than cells project × dependency cells with virtual scrolling,
+// 0 always renders static tables
+func (g *Generator) WithVirtualization(cells int) *Generator {
+	g.virtualizeCells = cells
+	return g
+}
+
+// virtualize reports whether a matrix is too large for a static table
+func (g *Generator) virtualize(matrix map[string]interface{}) bool {
+	projects, _ := matrix["projects"].([]*domain.Project)
+	dependencies, _ := matrix["dependencies"].([]map[string]interface{})
+	return g.virtualizeCells > 0 && len(projects)*len(dependencies) > g.virtualizeCells
+}
+
+// newVirtualMatrix converts a matrix built by GenerateMatrix for virtual rendering
+func newVirtualMatrix(matrix map[string]interface{}) *virtualMatrix {
+	projects, _ := matrix["projects"].([]*domain.Project)
+	dependencies, _ := matrix["dependencies"].([]map[string]interface{})
+	rows, _ := matrix["matrix"].([][]interface{})
+
+	virtual := &virtualMatrix{
+		Dependencies: make([]virtualDependency, 0, len(dependencies)),
+		Projects:     make([]virtualProject, 0, len(projects)),
+		Cells:        make([]map[int]virtualCell, 0, len(rows)),
+	}
+	for _, dep := range dependencies {
+		column := virtualDependency{}
+		column.Name, _ = dep["name"].(string)
+		column.Ecosystem, _ = dep["ecosystem"].(string)
+		column.Internal, _ = dep["is_internal"].(bool)
+		column.Latest, _ = dep["latest_version"].(string)
+		column.Versions, _ = dep["version_count"].(int)
+		column.Scopes, _ = dep["scopes"].([]string)
+		virtual.Dependencies = append(virtual.Dependencies, column)
+	}
+	for _, project := range projects {
+		path := project.Path
+		if path == "" {
+			path = "root"
+		}
+		virtual.Projects = append(virtual.Projects, virtualProject{
+			Name:   project.Repository.Name,
+			Path:   path,
+			URL:    project.Repository.WebURL,
+			Search: strings.TrimSpace(strings.Join([]string{project.Repository.Name, project.Path, project.Service}, " ")),
+			Health: healthScore(project),
+		})
+	}
+	for _, row := range rows {
+		cells := make(map[int]virtualCell)
+		for column, value := range row {
+			if cell, ok := value.(map[string]interface{}); ok {
+				cells[column] = newVirtualCell(cell)
+			}
+		}
+		virtual.Cells = append(virtual.Cells, cells)
+	}
+	return virtual
+}
+
+// newVirtualCell converts a matrix cell, keeping what the static table shows in the cell itself
+func newVirtualCell(cell map[string]interface{}) virtualCell {
+	text := func(key string) string {
+		value, _ := cell[key].(string)
+		return value
+	}
+	flag := func(key string) bool {
+		value, _ := cell[key].(bool)
+		return value
+	}
+
+	virtual := virtualCell{
+		Label:    text("label"),
+		Drift:    text("drift"),
+		Internal: flag("is_internal"),
+		Scope:    text("scope"),
+	}
+	if resolved := text("version"); resolved != virtual.Label {
+		virtual.Version = resolved
+	}
+	virtual.Title = "Current version: " + text("version")
+	if flag("is_outdated") {
+		virtual.Title += " (outdated - max: " + text("max_version") + ")"
+	}
+
+	if constraint := text("label_constraint"); constraint != "" {
+		virtual.Badges = append(virtual.Badges, constraint)
+	}
+	if count, _ := cell["vuln_count"].(int); count > 0 {
+		virtual.Badges = append(virtual.Badges, fmt.Sprintf("⚠ %d %s", count, text("vuln_severity")))
+	}
+	for _, marker := range []struct {
+		key   string
+		badge string
+	}{
+		{"is_deprecated", "deprecated"},
+		{"is_end_of_life", "EOL"},
+		{"has_conflict", "conflict"},
+		{"transitive", "transitive"},
+		{"stale", "stale"},
+		{"is_range", "range"},
+		{"is_floating", "floating"},
+	} {
+		if flag(marker.key) {
+			virtual.Badges = append(virtual.Badges, marker.badge)
+		}
+	}
+	if virtual.Scope != domain.ScopeRuntime {
+		virtual.Badges = append(virtual.Badges, virtual.Scope)
+	}
+	if flag("constraint_mismatch") {
+		virtual.Badges = append(virtual.Badges, "≠ "+text("declared_constraint"))
+	}
+	if change := text("change"); change != "" {
+		virtual.Badges = append(virtual.Badges, change)
+	}
+	return virtual
+}
@@ -0,0 +1,385 @@
+package generator
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Workbook sheet names, ecosystem sheets are named after their ecosystem
+const (
+	sheetMatrix  = "Matrix"
+	sheetSummary = "Summary"
+	// sheetStatus is a hidden copy of the matrix layout holding the status of each cell,
+	// the conditional formatting of the matrix sheet reads it since versions alone cannot tell
+	sheetStatus = "Cell Status"
+)
+
+// Matrix cell statuses in the hidden status sheet
+const (
+	cellOutdated = "outdated"
+	cellInternal = "internal"
+	cellCurrent  = "current"
+)
+
+// xlsxStyles are the style IDs registered in a workbook
+type xlsxStyles struct {
+	header   int
+	outdated int // Conditional style of outdated cells, light red
+	internal int // Conditional style of internal cells, light blue
+}
+
+// ecosystemRow is one dependency of an ecosystem sheet
+type ecosystemRow struct {
+	name     string
+	internal bool
+	latest   string
+	versions []string
+	projects int
+	outdated int
+}
+
+// GenerateXLSX creates an Excel workbook from projects: the combined matrix, the summary and one sheet
+// per ecosystem. Outdated and internal cells are highlighted with conditional formatting.
+func (g *Generator) GenerateXLSX(ctx context.Context, projects []*domain.Project) error {
+	projects = g.reportProjects(projects)
+	path := g.outputPath
+	if g.xlsxPath != "" {
+		path = g.xlsxPath
+	}
+
+	// Create output directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	styles, err := newXLSXStyles(workbook)
+	if err != nil {
+		return fmt.Errorf("failed to create workbook styles: %w", err)
+	}
+
+	matrix := g.GenerateMatrix(ctx, projects)
+	if err := workbook.SetSheetName("Sheet1", sheetMatrix); err != nil {
+		return fmt.Errorf("failed to create matrix sheet: %w", err)
+	}
+	if err := writeMatrixSheet(workbook, matrix, styles); err != nil {
+		return fmt.Errorf("failed to write matrix sheet: %w", err)
+	}
+	if err := g.writeSummarySheet(workbook, g.GenerateSummary(ctx, projects), styles); err != nil {
+		return fmt.Errorf("failed to write summary sheet: %w", err)
+	}
+	if err := writeEcosystemSheets(workbook, matrix, styles); err != nil {
+		return fmt.Errorf("failed to write ecosystem sheets: %w", err)
+	}
+
+	if err := workbook.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return nil
+}
+
+// newXLSXStyles registers the header and conditional styles of the workbook
+func newXLSXStyles(workbook *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+	if styles.header, err = workbook.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return styles, err
+	}
+	if styles.outdated, err = workbook.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "9C0006"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}},
+	}); err != nil {
+		return styles, err
+	}
+	styles.internal, err = workbook.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "1F4E78"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	return styles, err
+}
+
+// writeMatrixSheet writes one row per project and one column per dependency with the version in use.
+// The status of every cell goes to the hidden status sheet the conditional formatting refers to.
+func writeMatrixSheet(workbook *excelize.File, matrix map[string]interface{}, styles xlsxStyles) error {
+	dependencies, _ := matrix["dependencies"].([]map[string]interface{})
+	projects, _ := matrix["projects"].([]*domain.Project)
+	cells, _ := matrix["matrix"].([][]interface{})
+
+	if _, err := workbook.NewSheet(sheetStatus); err != nil {
+		return err
+	}
+	if err := workbook.SetSheetVisible(sheetStatus, false); err != nil {
+		return err
+	}
+
+	header := []interface{}{"Repository", "Project"}
+	for _, dependency := range dependencies {
+		header = append(header, dependency["name"])
+	}
+	if err := writeHeader(workbook, sheetMatrix, header, styles); err != nil {
+		return err
+	}
+
+	for i, project := range projects {
+		versions := []interface{}{project.Repository.Name, project.Name}
+		statuses := []interface{}{"", ""}
+		for _, cell := range cells[i] {
+			dep, ok := cell.(map[string]interface{})
+			if !ok {
+				versions = append(versions, "")
+				statuses = append(statuses, "")
+				continue
+			}
+			versions = append(versions, xlsxCellText(dep))
+			statuses = append(statuses, matrixCellStatus(dep))
+		}
+		row, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := workbook.SetSheetRow(sheetMatrix, row, &versions); err != nil {
+			return err
+		}
+		if err := workbook.SetSheetRow(sheetStatus, row, &statuses); err != nil {
+			return err
+		}
+	}
+
+	if err := workbook.SetPanes(sheetMatrix, &excelize.Panes{
+		Freeze: true, XSplit: 2, YSplit: 1, TopLeftCell: "C2", ActivePane: "bottomRight",
+	}); err != nil {
+		return err
+	}
+	if len(dependencies) == 0 || len(projects) == 0 {
+		return nil
+	}
+
+	rangeRef, err := cellRange(3, 2, len(dependencies)+2, len(projects)+1)
+	if err != nil {
+		return err
+	}
+	status := fmt.Sprintf("'%s'!C2", sheetStatus)
+	return workbook.SetConditionalFormat(sheetMatrix, rangeRef, []excelize.ConditionalFormatOptions{
+		{Type: "formula", Criteria: fmt.Sprintf(`%s="%s"`, status, cellOutdated), Format: &styles.outdated},
+		{Type: "formula", Criteria: fmt.Sprintf(`%s="%s"`, status, cellInternal), Format: &styles.internal},
+	})
+}
+
+// xlsxCellText returns the text of a matrix cell, the declared constraint in parentheses when shown
+func xlsxCellText(cell map[string]interface{}) string {
+	label, _ := cell["label"].(string)
+	if constraint, _ := cell["label_constraint"].(string); constraint != "" {
+		return label + " (" + constraint + ")"
+	}
+	return label
+}
+
+// matrixCellStatus returns the highlight of a matrix cell, outdated takes precedence over internal
+func matrixCellStatus(cell map[string]interface{}) string {
+	if outdated, _ := cell["is_outdated"].(bool); outdated {
+		return cellOutdated
+	}
+	if internal, _ := cell["is_internal"].(bool); internal {
+		return cellInternal
+	}
+	return cellCurrent
+}
+
+// writeSummarySheet writes the report totals and the dependency counts per language and ecosystem
+func (g *Generator) writeSummarySheet(
+	workbook *excelize.File,
+	summary map[string]interface{},
+	styles xlsxStyles,
+) error {
+	if _, err := workbook.NewSheet(sheetSummary); err != nil {
+		return err
+	}
+	internalExternal, _ := summary["internal_external"].(map[string]int)
+
+	rows := [][]interface{}{
+		{"Metric", "Value"},
+		{"Projects", summary["total_projects"]},
+		{"Dependencies", summary["total_dependencies"]},
+		{"Internal Dependencies", internalExternal["internal"]},
+		{"External Dependencies", internalExternal["external"]},
+		{"Floating Dependencies", summary["floating_dependencies"]},
+		{"Projects Without Lockfile", summary["projects_without_lockfile"]},
+		{"Average Health Score", summary["average_health"]},
+	}
+	if g.vulnScan {
+		rows = append(rows, []interface{}{"Vulnerable Dependencies", summary["vulnerable_dependencies"]})
+	}
+	if g.incomplete != "" {
+		rows = append(rows, []interface{}{"Incomplete", g.incomplete})
+	}
+
+	rows = append(rows, nil, []interface{}{"Language", "Projects"})
+	languages, _ := summary["languages"].(map[string]int)
+	for _, language := range sortedKeys(languages) {
+		rows = append(rows, []interface{}{language, languages[language]})
+	}
+	rows = append(rows, nil, []interface{}{"Ecosystem", "Dependencies"})
+	ecosystems, _ := summary["ecosystems"].(map[string]int)
+	for _, ecosystem := range sortedKeys(ecosystems) {
+		rows = append(rows, []interface{}{ecosystem, ecosystems[ecosystem]})
+	}
+
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := workbook.SetSheetRow(sheetSummary, cell, &row); err != nil {
+			return err
+		}
+		// Every table starts with a header row after a blank line
+		if i == 0 || rows[i-1] == nil {
+			if err := workbook.SetCellStyle(sheetSummary, cell, "B"+cell[1:], styles.header); err != nil {
+				return err
+			}
+		}
+	}
+	return workbook.SetColWidth(sheetSummary, "A", "A", 30)
+}
+
+// writeEcosystemSheets writes one sheet per ecosystem listing its dependencies, the versions in use
+// and how many projects lag behind
+func writeEcosystemSheets(workbook *excelize.File, matrix map[string]interface{}, styles xlsxStyles) error {
+	byEcosystem := ecosystemRows(matrix)
+	for _, ecosystem := range sortedKeys(byEcosystem) {
+		if _, err := workbook.NewSheet(ecosystem); err != nil {
+			return err
+		}
+		header := []interface{}{
+			"Dependency", "Type", "Latest Version", "Versions In Use", "Projects", "Outdated Projects",
+		}
+		if err := writeHeader(workbook, ecosystem, header, styles); err != nil {
+			return err
+		}
+
+		rows := byEcosystem[ecosystem]
+		for i, dependency := range rows {
+			kind := "external"
+			if dependency.internal {
+				kind = cellInternal
+			}
+			values := []interface{}{
+				dependency.name, kind, dependency.latest, joinVersions(dependency.versions),
+				dependency.projects, dependency.outdated,
+			}
+			cell, err := excelize.CoordinatesToCellName(1, i+2)
+			if err != nil {
+				return err
+			}
+			if err := workbook.SetSheetRow(ecosystem, cell, &values); err != nil {
+				return err
+			}
+		}
+
+		if err := workbook.SetColWidth(ecosystem, "A", "A", 40); err != nil {
+			return err
+		}
+		rangeRef, err := cellRange(1, 2, len(header), len(rows)+1)
+		if err != nil {
+			return err
+		}
+		if err := workbook.SetConditionalFormat(ecosystem, rangeRef, []excelize.ConditionalFormatOptions{
+			{Type: "formula", Criteria: "$F2>0", Format: &styles.outdated},
+			{Type: "formula", Criteria: fmt.Sprintf(`$B2="%s"`, cellInternal), Format: &styles.internal},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ecosystemRows groups the matrix columns by ecosystem, dependencies keep the matrix order
+func ecosystemRows(matrix map[string]interface{}) map[string][]ecosystemRow {
+	dependencies, _ := matrix["dependencies"].([]map[string]interface{})
+	cells, _ := matrix["matrix"].([][]interface{})
+
+	byEcosystem := make(map[string][]ecosystemRow)
+	for j, dependency := range dependencies {
+		row := ecosystemRow{}
+		row.name, _ = dependency["name"].(string)
+		row.latest, _ = dependency["latest_version"].(string)
+		ecosystem := ""
+		for i := range cells {
+			cell, ok := cells[i][j].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ecosystem, _ = cell["ecosystem"].(string)
+			row.internal, _ = cell["is_internal"].(bool)
+			row.projects++
+			if outdated, _ := cell["is_outdated"].(bool); outdated {
+				row.outdated++
+			}
+			if cellVersion, _ := cell["version"].(string); !slices.Contains(row.versions, cellVersion) {
+				row.versions = append(row.versions, cellVersion)
+			}
+		}
+		if ecosystem == "" {
+			ecosystem = "other"
+		}
+		byEcosystem[ecosystem] = append(byEcosystem[ecosystem], row)
+	}
+	return byEcosystem
+}
+
+// joinVersions lists versions from newest to oldest
+func joinVersions(versions []string) string {
+	sorted := slices.Clone(versions)
+	sort.SliceStable(sorted, func(i, j int) bool { return version.Compare(sorted[i], sorted[j]) > 0 })
+	return strings.Join(sorted, ", ")
+}
+
+// writeHeader writes a bold header row and makes it the autofilter of the sheet
+func writeHeader(workbook *excelize.File, sheet string, header []interface{}, styles xlsxStyles) error {
+	if err := workbook.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	last, err := excelize.CoordinatesToCellName(len(header), 1)
+	if err != nil {
+		return err
+	}
+	if err := workbook.SetCellStyle(sheet, "A1", last, styles.header); err != nil {
+		return err
+	}
+	return workbook.AutoFilter(sheet, "A1:"+last, nil)
+}
+
+// cellRange returns the A1 reference of the rectangle between two cells given as column and row numbers
+func cellRange(fromCol, fromRow, toCol, toRow int) (string, error) {
+	from, err := excelize.CoordinatesToCellName(fromCol, fromRow)
+	if err != nil {
+		return "", err
+	}
+	to, err := excelize.CoordinatesToCellName(toCol, toRow)
+	if err != nil {
+		return "", err
+	}
+	return from + ":" + to, nil
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
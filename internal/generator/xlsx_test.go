@@ -0,0 +1,58 @@
+package generator_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/generator"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestGenerateXLSX(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "reports", "matrix.xlsx")
+
+	gen := generator.NewGenerator(filepath.Join(dir, "matrix.html")).WithXLSXOutput(path)
+	require.NoError(t, gen.GenerateXLSX(context.Background(), createTestProjects()))
+
+	workbook, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = workbook.Close() })
+
+	assert.Equal(t, []string{"Matrix", "Cell Status", "Summary", "go-modules", "npm"}, workbook.GetSheetList())
+	visible, err := workbook.GetSheetVisible("Cell Status")
+	require.NoError(t, err)
+	assert.False(t, visible, "cell statuses only drive the conditional formatting")
+
+	matrix, err := workbook.GetRows("Matrix")
+	require.NoError(t, err)
+	require.Len(t, matrix, 3)
+	assert.Equal(t, []string{"Repository", "Project", "internal/company/auth", "express",
+		"github.com/gin-gonic/gin", "react"}, matrix[0])
+	assert.Equal(t, []string{"test-repo-1", "Test Project 1", "v1.0.0", "", "v1.9.1"}, matrix[1])
+
+	statuses, err := workbook.GetRows("Cell Status")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "", "internal", "", "outdated"}, statuses[1])
+
+	formats, err := workbook.GetConditionalFormats("Matrix")
+	require.NoError(t, err)
+	require.Len(t, formats["C2:F3"], 2)
+	assert.Equal(t, `'Cell Status'!C2="outdated"`, formats["C2:F3"][0].Criteria)
+
+	summary, err := workbook.GetRows("Summary")
+	require.NoError(t, err)
+	assert.Contains(t, summary, []string{"Dependencies", "4"})
+	assert.Contains(t, summary, []string{"Internal Dependencies", "1"})
+	assert.Contains(t, summary, []string{"npm", "2"})
+
+	npm, err := workbook.GetRows("npm")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dependency", "Type", "Latest Version", "Versions In Use", "Projects",
+		"Outdated Projects"}, npm[0])
+	assert.Equal(t, []string{"express", "external", "4.19.0", "4.18.2", "1", "1"}, npm[1])
+}
@@ -0,0 +1,447 @@
+// Package gitea reads repositories from Gitea and Forgejo instances through their REST API
+package gitea
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Page sizes of listings, the default maximums of Gitea instances
+const (
+	repositoriesPageSize = 50
+	treePageSize         = 1000
+)
+
+// symlinkMode is the git file mode of symbolic links
+const symlinkMode = "120000"
+
+// maxFileSize bounds downloaded files, dependency files are far smaller
+const maxFileSize = 32 << 20
+
+// Client reads repositories of a Gitea or Forgejo instance, the API of both is the same
+type Client struct {
+	baseURL      string
+	token        string
+	http         *http.Client
+	policy       retry.Policy
+	logger       *zap.Logger
+	refs         map[string]string // Owner or repository path -> branch or tag to analyze
+	ref          string            // Branch or tag analyzed in every repository, overrides refs
+	skipArchived bool              // Leave archived repositories out of owner entries
+	includeForks bool              // Keep forks in owner entries
+
+	revisionsMu sync.Mutex        // Guards revisions
+	revisions   map[string]string // Repository path -> commit analyzed in this run
+
+	callsMu sync.Mutex     // Guards calls
+	calls   map[string]int // Operation -> API requests sent, retries included
+}
+
+// NewClient creates a client of the instance at baseURL authenticating with an access token
+func NewClient(baseURL, token string, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		token:        token,
+		http:         &http.Client{},
+		policy:       retry.Policy{Retries: 3, Backoff: 500 * time.Millisecond, Timeout: 30 * time.Second},
+		logger:       logger,
+		skipArchived: true,
+		revisions:    make(map[string]string),
+		calls:        make(map[string]int),
+	}
+}
+
+// WithRetryPolicy sets how API calls are retried
+func (c *Client) WithRetryPolicy(policy retry.Policy) *Client {
+	c.policy = policy
+	return c
+}
+
+// WithRefs sets the branch or tag analyzed per repository or owner entry keyed by URL,
+// an owner entry's ref applies to every repository of the owner
+func (c *Client) WithRefs(refs map[string]string) *Client {
+	c.refs = make(map[string]string, len(refs))
+	for entryURL, ref := range refs {
+		if entryPath, err := c.repositoryPath(entryURL); err == nil && ref != "" {
+			c.refs[entryPath] = ref
+		}
+	}
+	return c
+}
+
+// WithRef analyzes ref in every repository instead of its default branch, taking precedence over WithRefs
+func (c *Client) WithRef(ref string) *Client {
+	c.ref = ref
+	return c
+}
+
+// WithArchivedSkipped leaves archived repositories out of owner entries
+func (c *Client) WithArchivedSkipped(skip bool) *Client {
+	c.skipArchived = skip
+	return c
+}
+
+// WithForksIncluded keeps forks in owner entries
+func (c *Client) WithForksIncluded(include bool) *Client {
+	c.includeForks = include
+	return c
+}
+
+// IsAuthError reports whether err was caused by Gitea rejecting the token (401) or its scopes (403)
+func IsAuthError(err error) bool {
+	var status *retry.StatusError
+	return errors.As(err, &status) &&
+		(status.Status == http.StatusUnauthorized || status.Status == http.StatusForbidden)
+}
+
+// isNotFound reports whether err is a 404 answer
+func isNotFound(err error) bool {
+	var status *retry.StatusError
+	return errors.As(err, &status) && status.Status == http.StatusNotFound
+}
+
+// get sends a GET request to an API path under the retry policy and returns the response body
+func (c *Client) get(ctx context.Context, operation, apiPath string, query url.Values) ([]byte, error) {
+	target := c.baseURL + "/api/v1" + apiPath
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body []byte
+	var lastErr error
+	err := c.policy.Do(ctx, retry.IsTransientHTTP, func(ctx context.Context) error {
+		if lastErr != nil {
+			c.logger.Warn("Retrying Gitea call", zap.String("operation", operation), zap.Error(lastErr))
+		}
+		c.callsMu.Lock()
+		c.calls[operation]++
+		c.callsMu.Unlock()
+
+		body, lastErr = c.do(ctx, target)
+		return lastErr
+	})
+	return body, err
+}
+
+// do sends one authenticated GET request
+func (c *Client) do(ctx context.Context, target string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "token "+c.token)
+	request.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &retry.StatusError{Status: resp.StatusCode, URL: target, Body: errorMessage(content)}
+	}
+	return content, nil
+}
+
+// errorMessage returns the message of a Gitea error body, which is JSON with a message field
+func errorMessage(body []byte) string {
+	var answer struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &answer) == nil && answer.Message != "" {
+		return answer.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// getJSON sends a GET request and decodes the JSON answer into result
+func (c *Client) getJSON(ctx context.Context, operation, apiPath string, query url.Values, result any) error {
+	body, err := c.get(ctx, operation, apiPath, query)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode %s answer: %w", operation, err)
+	}
+	return nil
+}
+
+// APICalls returns the API requests sent so far keyed by operation, retries included
+func (c *Client) APICalls() map[string]int {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+	return maps.Clone(c.calls)
+}
+
+// CheckPermissions verifies the token by reading the user it belongs to
+func (c *Client) CheckPermissions(ctx context.Context) error {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.getJSON(ctx, "current user", "/user", nil, &user); err != nil {
+		return fmt.Errorf("failed to verify token permissions: %w", err)
+	}
+	c.logger.Debug("Successfully verified token permissions", zap.String("username", user.Login))
+	return nil
+}
+
+// apiRepository is a repository as the API describes it
+type apiRepository struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	FullName      string   `json:"full_name"`
+	HTMLURL       string   `json:"html_url"`
+	DefaultBranch string   `json:"default_branch"`
+	Topics        []string `json:"topics"`
+	Archived      bool     `json:"archived"`
+	Fork          bool     `json:"fork"`
+	Empty         bool     `json:"empty"`
+}
+
+// GetRepositoriesList returns the repository of a repository URL, or the repositories of an organization or user URL
+func (c *Client) GetRepositoriesList(ctx context.Context, repoURL string) ([]*domain.Repository, error) {
+	repoPath, err := c.repositoryPath(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(repoPath, "/") {
+		var repo apiRepository
+		if err := c.getJSON(ctx, "get repository", "/repos/"+escapePath(repoPath), nil, &repo); err != nil {
+			return nil, fmt.Errorf("failed to get repository %s: %w", repoPath, err)
+		}
+		return []*domain.Repository{c.repository(repo)}, nil
+	}
+
+	// An owner is an organization or a user
+	repos, err := c.listRepositories(ctx, "/orgs/"+url.PathEscape(repoPath)+"/repos")
+	if isNotFound(err) {
+		repos, err = c.listRepositories(ctx, "/users/"+url.PathEscape(repoPath)+"/repos")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %w", repoPath, err)
+	}
+
+	repositories := make([]*domain.Repository, 0, len(repos))
+	for _, repo := range repos {
+		switch {
+		case repo.Empty:
+			c.logger.Debug("Skipping empty repository", zap.String("repository", repo.FullName))
+		case repo.Archived && c.skipArchived:
+			c.logger.Debug("Skipping archived repository", zap.String("repository", repo.FullName))
+		case repo.Fork && !c.includeForks:
+			c.logger.Debug("Skipping fork", zap.String("repository", repo.FullName))
+		default:
+			repositories = append(repositories, c.repository(repo))
+		}
+	}
+	return repositories, nil
+}
+
+// listRepositories reads every page of a repository listing
+func (c *Client) listRepositories(ctx context.Context, apiPath string) ([]apiRepository, error) {
+	var all []apiRepository
+	for page := 1; ; page++ {
+		query := url.Values{"page": {strconv.Itoa(page)}, "limit": {strconv.Itoa(repositoriesPageSize)}}
+		var repos []apiRepository
+		if err := c.getJSON(ctx, "list repositories", apiPath, query, &repos); err != nil {
+			return nil, err
+		}
+		// Instances cap limit at their MAX_RESPONSE_ITEMS, a short page is not necessarily the last one
+		if len(repos) == 0 {
+			return all, nil
+		}
+		all = append(all, repos...)
+	}
+}
+
+// repository converts an API repository to a domain repository analyzed at its configured ref
+func (c *Client) repository(repo apiRepository) *domain.Repository {
+	result := &domain.Repository{
+		ID:            repo.ID,
+		Name:          repo.Name,
+		URL:           repo.HTMLURL,
+		DefaultBranch: repo.DefaultBranch,
+		WebURL:        repo.HTMLURL,
+		Topics:        repo.Topics,
+	}
+	if ref := c.refFor(repo.FullName, repo.DefaultBranch); ref != repo.DefaultBranch {
+		result.Ref = ref
+	}
+	return result
+}
+
+// refFor returns the branch or tag analyzed in a repository, the repository entry wins over its owner's
+func (c *Client) refFor(repoPath, defaultBranch string) string {
+	if c.ref != "" {
+		return c.ref
+	}
+	if ref, ok := c.refs[repoPath]; ok {
+		return ref
+	}
+	owner, _, _ := strings.Cut(repoPath, "/")
+	if ref, ok := c.refs[owner]; ok {
+		return ref
+	}
+	return defaultBranch
+}
+
+// HeadCommit returns the SHA of the commit the analyzed ref of the repository points at
+func (c *Client) HeadCommit(ctx context.Context, repoURL string) (string, error) {
+	repoPath, err := c.repositoryPath(repoURL)
+	if err != nil {
+		return "", err
+	}
+	return c.revision(ctx, repoPath)
+}
+
+// revision resolves the ref analyzed in a repository to a commit, once per run so every file is read
+// from the same commit even when the branch moves during the analysis
+func (c *Client) revision(ctx context.Context, repoPath string) (string, error) {
+	c.revisionsMu.Lock()
+	sha, ok := c.revisions[repoPath]
+	c.revisionsMu.Unlock()
+	if ok {
+		return sha, nil
+	}
+
+	ref := c.ref
+	if ref == "" {
+		var repo apiRepository
+		if err := c.getJSON(ctx, "get repository", "/repos/"+escapePath(repoPath), nil, &repo); err != nil {
+			return "", fmt.Errorf("failed to get repository %s: %w", repoPath, err)
+		}
+		ref = c.refFor(repoPath, repo.DefaultBranch)
+	}
+
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	apiPath := "/repos/" + escapePath(repoPath) + "/git/commits/" + url.PathEscape(ref)
+	if err := c.getJSON(ctx, "get commit", apiPath, nil, &commit); err != nil {
+		return "", fmt.Errorf("failed to resolve %s of %s: %w", ref, repoPath, err)
+	}
+
+	c.revisionsMu.Lock()
+	c.revisions[repoPath] = commit.SHA
+	c.revisionsMu.Unlock()
+	return commit.SHA, nil
+}
+
+// GetFilesList returns the paths of the files in the repository at its analyzed ref, symbolic links left out
+func (c *Client) GetFilesList(ctx context.Context, repoURL string) ([]string, error) {
+	repoPath, err := c.repositoryPath(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	sha, err := c.revision(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	apiPath := "/repos/" + escapePath(repoPath) + "/git/trees/" + url.PathEscape(sha)
+	for page, listed := 1, 0; ; page++ {
+		query := url.Values{
+			"recursive": {"true"},
+			"page":      {strconv.Itoa(page)},
+			"per_page":  {strconv.Itoa(treePageSize)},
+		}
+		var tree struct {
+			Tree []struct {
+				Path string `json:"path"`
+				Type string `json:"type"`
+				Mode string `json:"mode"`
+			} `json:"tree"`
+			Truncated  bool `json:"truncated"`
+			TotalCount int  `json:"total_count"`
+		}
+		if err := c.getJSON(ctx, "list tree", apiPath, query, &tree); err != nil {
+			return nil, fmt.Errorf("failed to list files of %s: %w", repoPath, err)
+		}
+
+		for _, entry := range tree.Tree {
+			if entry.Type == "blob" && entry.Mode != symlinkMode {
+				files = append(files, entry.Path)
+			}
+		}
+		listed += len(tree.Tree)
+		if !tree.Truncated || len(tree.Tree) == 0 || listed >= tree.TotalCount {
+			return files, nil
+		}
+	}
+}
+
+// GetFileContent returns the content of a file at the analyzed commit of the repository
+func (c *Client) GetFileContent(ctx context.Context, repoURL, filePath string) ([]byte, error) {
+	repoPath, err := c.repositoryPath(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	sha, err := c.revision(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	apiPath := "/repos/" + escapePath(repoPath) + "/raw/" + escapePath(filePath)
+	content, err := c.get(ctx, "get file", apiPath, url.Values{"ref": {sha}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file %s of %s: %w", filePath, repoPath, err)
+	}
+	return content, nil
+}
+
+// repositoryPath extracts the owner or owner/repository path from a repository URL on the instance
+func (c *Client) repositoryPath(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+
+	repoPath := parsed.Path
+	if base, err := url.Parse(c.baseURL); err == nil && strings.EqualFold(base.Host, parsed.Host) {
+		// Instances served below a path, e.g. https://example.com/gitea
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" && strings.HasPrefix(repoPath, prefix+"/") {
+			repoPath = strings.TrimPrefix(repoPath, prefix)
+		}
+	}
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if repoPath == "" {
+		return "", fmt.Errorf("no path found in URL: %s", repoURL)
+	}
+
+	segments := strings.Split(repoPath, "/")
+	if len(segments) > 2 {
+		// Browser URLs of files and branches, e.g. owner/repo/src/branch/main
+		segments = segments[:2]
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// escapePath escapes every segment of a slash separated path
+func escapePath(value string) string {
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
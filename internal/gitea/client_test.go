@@ -0,0 +1,193 @@
+package gitea_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/gitea"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeGitea serves the endpoints the client uses for an organization "acme" with three repositories,
+// listed two per page like an instance with MAX_RESPONSE_ITEMS = 2
+type fakeGitea struct {
+	server *httptest.Server
+	trees  atomic.Int32 // Tree pages served
+}
+
+func newFakeGitea(t *testing.T) *fakeGitea {
+	t.Helper()
+
+	fake := &fakeGitea{}
+	repos := []map[string]any{
+		{"id": 1, "name": "api", "full_name": "acme/api", "default_branch": "main", "topics": []string{"backend"}},
+		{"id": 2, "name": "legacy", "full_name": "acme/legacy", "default_branch": "main", "archived": true},
+		{"id": 3, "name": "fork", "full_name": "acme/fork", "default_branch": "main", "fork": true},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"token is required"}`))
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1")
+		switch {
+		case path == "/user":
+			writeJSON(w, map[string]any{"login": "ci-bot"})
+		case path == "/orgs/acme/repos":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			start, end := min(2*(page-1), len(repos)), min(2*page, len(repos))
+			for _, repo := range repos[start:end] {
+				repo["html_url"] = fake.server.URL + "/" + repo["full_name"].(string)
+			}
+			writeJSON(w, repos[start:end])
+		case path == "/orgs/jane/repos":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"GetOrgByName"}`))
+		case path == "/users/jane/repos":
+			if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 1 {
+				writeJSON(w, []any{})
+				return
+			}
+			writeJSON(w, []map[string]any{{
+				"id": 4, "name": "dotfiles", "full_name": "jane/dotfiles", "default_branch": "main",
+				"html_url": fake.server.URL + "/jane/dotfiles",
+			}})
+		case path == "/repos/acme/api":
+			writeJSON(w, map[string]any{"id": 1, "name": "api", "full_name": "acme/api", "default_branch": "main",
+				"html_url": fake.server.URL + "/acme/api"})
+		case path == "/repos/acme/api/git/commits/main":
+			writeJSON(w, map[string]any{"sha": "abc123"})
+		case path == "/repos/acme/api/git/trees/abc123":
+			// Two pages of two entries each
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			fake.trees.Add(1)
+			entries := [][]map[string]any{
+				{
+					{"path": "go.mod", "type": "blob", "mode": "100644"},
+					{"path": "web", "type": "tree", "mode": "040000"},
+				},
+				{
+					{"path": "web/package.json", "type": "blob", "mode": "100644"},
+					{"path": "web/link.json", "type": "blob", "mode": "120000"},
+				},
+			}
+			writeJSON(w, map[string]any{"tree": entries[page-1], "truncated": page == 1, "total_count": 4})
+		case path == "/repos/acme/api/raw/web/package.json":
+			if r.URL.Query().Get("ref") != "abc123" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"web"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	})
+	fake.server = httptest.NewServer(mux)
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func TestClient_ReadsRepositories(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeGitea(t)
+	client := gitea.NewClient(fake.server.URL, "secret", zap.NewNop())
+	ctx := context.Background()
+
+	require.NoError(t, client.CheckPermissions(ctx))
+
+	repos, err := client.GetRepositoriesList(ctx, fake.server.URL+"/acme")
+	require.NoError(t, err)
+	require.Len(t, repos, 1, "Archived repositories and forks are left out")
+	assert.Equal(t, "api", repos[0].Name)
+	assert.Equal(t, []string{"backend"}, repos[0].Topics)
+
+	repos, err = client.GetRepositoriesList(ctx, fake.server.URL+"/jane")
+	require.NoError(t, err)
+	require.Len(t, repos, 1, "Owners that are no organization are users")
+	assert.Equal(t, "dotfiles", repos[0].Name)
+
+	repos, err = client.GetRepositoriesList(ctx, fake.server.URL+"/acme/api.git")
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+
+	files, err := client.GetFilesList(ctx, repos[0].URL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "web/package.json"}, files, "Directories and symbolic links are left out")
+	assert.Equal(t, int32(2), fake.trees.Load())
+
+	content, err := client.GetFileContent(ctx, repos[0].URL, "web/package.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"web"}`, string(content))
+
+	sha, err := client.HeadCommit(ctx, repos[0].URL)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sha)
+	assert.Equal(t, 1, client.APICalls()["get commit"], "The analyzed commit is resolved once per run")
+}
+
+func TestClient_OwnerFilters(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeGitea(t)
+	client := gitea.NewClient(fake.server.URL, "secret", zap.NewNop()).
+		WithArchivedSkipped(false).
+		WithForksIncluded(true).
+		WithRefs(map[string]string{fake.server.URL + "/acme": "release"})
+
+	repos, err := client.GetRepositoriesList(context.Background(), fake.server.URL+"/acme")
+	require.NoError(t, err)
+	require.Len(t, repos, 3, "Pages shorter than the requested limit are not the last one")
+	for _, repo := range repos {
+		assert.Equal(t, "release", repo.Ref, "The owner entry's ref applies to its repositories")
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeGitea(t)
+	err := gitea.NewClient(fake.server.URL, "wrong", zap.NewNop()).CheckPermissions(context.Background())
+	require.Error(t, err)
+	assert.True(t, gitea.IsAuthError(err))
+	assert.Contains(t, err.Error(), "token is required")
+
+	_, err = gitea.NewClient(fake.server.URL, "secret", zap.NewNop()).
+		GetRepositoriesList(context.Background(), fake.server.URL+"/acme/missing")
+	require.Error(t, err)
+	assert.False(t, gitea.IsAuthError(err))
+
+	var attempts atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"login":"ci-bot"}`)
+	}))
+	defer flaky.Close()
+
+	client := gitea.NewClient(flaky.URL, "secret", zap.NewNop()).WithRetryPolicy(retry.Policy{Retries: 1})
+	require.NoError(t, client.CheckPermissions(context.Background()))
+	assert.Equal(t, 2, client.APICalls()["current user"], "Server errors are retried")
+}
@@ -0,0 +1,94 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.uber.org/zap"
+)
+
+// Location kinds returned by ListLocations
+const (
+	LocationGroup   = "group"
+	LocationProject = "project"
+)
+
+// maxBrowsePages caps listing to keep onboarding responsive on large instances, narrow with a search instead
+const maxBrowsePages = 5
+
+// Location is a group or project the token can access, suitable as a repositories entry
+type Location struct {
+	Kind string // LocationGroup or LocationProject
+	Path string // Full path such as group/subgroup/project
+	URL  string // Web URL used in the configuration
+}
+
+// ListLocations returns the groups and projects the token is a member of, optionally filtered by search.
+// Groups come first, each kind sorted by path.
+func (c *Client) ListLocations(ctx context.Context, search string) ([]Location, error) {
+	c.logger.Debug("Starting ListLocations", zap.String("search", search))
+
+	var searchOpt *string
+	if search != "" {
+		searchOpt = gitlab.Ptr(search)
+	}
+
+	var groups []Location
+	for page := 1; page != 0 && page <= maxBrowsePages; {
+		var found []*gitlab.Group
+		var resp *gitlab.Response
+		err := c.call(ctx, "list groups", c.retries.Metadata, func(ctx context.Context) (err error) {
+			found, resp, err = c.client.Groups.ListGroups(&gitlab.ListGroupsOptions{
+				ListOptions:    gitlab.ListOptions{Page: page, PerPage: 100},
+				Search:         searchOpt,
+				MinAccessLevel: gitlab.Ptr(gitlab.GuestPermissions),
+			}, gitlab.WithContext(ctx))
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups: %w", err)
+		}
+		for _, group := range found {
+			groups = append(groups, Location{Kind: LocationGroup, Path: group.FullPath, URL: group.WebURL})
+		}
+		page = resp.NextPage
+	}
+
+	var projects []Location
+	for page := 1; page != 0 && page <= maxBrowsePages; {
+		var found []*gitlab.Project
+		var resp *gitlab.Response
+		err := c.call(ctx, "list projects", c.retries.Metadata, func(ctx context.Context) (err error) {
+			found, resp, err = c.client.Projects.ListProjects(&gitlab.ListProjectsOptions{
+				ListOptions: gitlab.ListOptions{Page: page, PerPage: 100},
+				Search:      searchOpt,
+				Membership:  gitlab.Ptr(true),
+				Archived:    gitlab.Ptr(false),
+				Simple:      gitlab.Ptr(true),
+			}, gitlab.WithContext(ctx))
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
+		for _, project := range found {
+			projects = append(projects, Location{
+				Kind: LocationProject,
+				Path: project.PathWithNamespace,
+				URL:  project.WebURL,
+			})
+		}
+		page = resp.NextPage
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Path < groups[j].Path })
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+
+	c.logger.Debug("Completed ListLocations",
+		zap.Int("groups", len(groups)),
+		zap.Int("projects", len(projects)))
+
+	return append(groups, projects...), nil
+}
@@ -0,0 +1,144 @@
+package gitlab
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.uber.org/zap"
+)
+
+// Cache namespaces of GitLab responses. Entries are keyed by project ID and commit SHA, the content
+// of a commit never changes, so they are reused until the analyzed ref moves to another commit.
+// Entries are never revalidated, so no ETags are kept: a hit sends no request at all. The project and commit
+// lookups resolving the ref have to reach GitLab on every run to notice a moved branch, a conditional request
+// would save only their small response bodies, not the requests.
+const (
+	treeNamespace    = "gitlab-trees"
+	contentNamespace = "gitlab-files"
+)
+
+// revision is a project with the commit its analyzed ref points at
+type revision struct {
+	projectID int
+	sha       string
+}
+
+// key returns the cache key of the revision, extended with a file path for contents
+func (r revision) key(filePath string) string {
+	return strconv.Itoa(r.projectID) + "@" + r.sha + ":" + filePath
+}
+
+// WithCache stores repository trees and file contents in store keyed by project ID and commit SHA.
+// Analyzed refs are resolved once per run, so unchanged repositories are listed and downloaded from the cache.
+func (c *Client) WithCache(store *cache.Store) *Client {
+	c.cache = store
+	return c
+}
+
+// revision resolves the ref analyzed in the project to a commit, once per run so every file is read
+// from the same commit even when the branch moves during the analysis
+func (c *Client) revision(ctx context.Context, projectPath string) (revision, error) {
+	c.revisionsMu.Lock()
+	resolved, ok := c.revisions[projectPath]
+	c.revisionsMu.Unlock()
+	if ok {
+		return resolved, nil
+	}
+
+	project, err := c.getProject(ctx, projectPath)
+	if err != nil {
+		return revision{}, fmt.Errorf("failed to get project %s: %w", projectPath, err)
+	}
+	ref := c.refFor(projectPath, project.DefaultBranch)
+
+	var commit *gitlab.Commit
+	err = c.call(ctx, "get commit", c.retries.Metadata, func(ctx context.Context) (err error) {
+		commit, _, err = c.client.Commits.GetCommit(projectPath, ref, nil, gitlab.WithContext(ctx))
+		return err
+	})
+	if err != nil {
+		return revision{}, fmt.Errorf("failed to resolve %s of project %s: %w", ref, projectPath, err)
+	}
+
+	resolved = revision{projectID: project.ID, sha: commit.ID}
+	c.revisionsMu.Lock()
+	c.revisions[projectPath] = resolved
+	c.revisionsMu.Unlock()
+	c.logger.Debug("Resolved analyzed commit",
+		zap.String("project_path", projectPath),
+		zap.String("ref", ref),
+		zap.String("sha", resolved.sha))
+	return resolved, nil
+}
+
+// HeadCommit returns the SHA of the commit the analyzed ref of the repository points at
+func (c *Client) HeadCommit(ctx context.Context, repoURL string) (string, error) {
+	projectPath, err := c.ExtractProjectPath(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract project path from URL %s: %w", repoURL, err)
+	}
+
+	rev, err := c.revision(ctx, projectPath)
+	if err != nil {
+		return "", err
+	}
+	return rev.sha, nil
+}
+
+// cachedFilesList returns the file paths of the analyzed commit, listing the tree only on a cache miss
+func (c *Client) cachedFilesList(ctx context.Context, projectPath string) ([]string, error) {
+	rev, err := c.revision(ctx, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, ok := c.cache.Get(treeNamespace, rev.key("")); ok {
+		var files []string
+		if err := json.Unmarshal(data, &files); err == nil {
+			c.logger.Debug("Repository tree served from cache", zap.String("project_path", projectPath))
+			return files, nil
+		}
+	}
+
+	files, err := c.listTree(ctx, projectPath, rev.sha)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(files); err == nil {
+		c.store(treeNamespace, rev.key(""), data)
+	}
+	return files, nil
+}
+
+// cachedFileContent returns a file of the analyzed commit, downloading it only on a cache miss
+func (c *Client) cachedFileContent(ctx context.Context, projectPath, filePath string) ([]byte, error) {
+	rev, err := c.revision(ctx, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if content, ok := c.cache.Get(contentNamespace, rev.key(filePath)); ok {
+		c.logger.Debug("File content served from cache",
+			zap.String("project_path", projectPath),
+			zap.String("file_path", filePath))
+		return content, nil
+	}
+
+	content, err := c.downloadFile(ctx, projectPath, filePath, rev.sha)
+	if err != nil {
+		return nil, err
+	}
+	c.store(contentNamespace, rev.key(filePath), content)
+	return content, nil
+}
+
+// store writes a cache entry, a failed write only costs the next run a download
+func (c *Client) store(namespace, key string, data []byte) {
+	if err := c.cache.Put(namespace, key, data); err != nil {
+		c.logger.Warn("Failed to cache GitLab response", zap.String("namespace", namespace), zap.Error(err))
+	}
+}
@@ -0,0 +1,104 @@
+package gitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/gitlab"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClient_WithCache(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("", fakegitlab.Repository{
+		Path:  "acme/api",
+		Files: map[string]string{"go.mod": "module api", "web/package.json": "{}"},
+		Refs:  map[string]map[string]string{"release": {"go.mod": "module api // release"}},
+	})
+	defer server.Close()
+
+	// Count the tree and file downloads reaching the instance
+	var downloads atomic.Int32
+	target, err := url.Parse(server.URL())
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/repository/tree") || strings.Contains(r.URL.Path, "/repository/files/") {
+			downloads.Add(1)
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+
+	store := cache.New(t.TempDir())
+	analyze := func(ref string) ([]string, string) {
+		t.Helper()
+		client, err := gitlab.NewClient(counting.URL, "token", gitlab.PrivateToken, zap.NewNop())
+		require.NoError(t, err)
+		client.WithCache(store).WithRef(ref)
+
+		files, err := client.GetFilesList(context.Background(), counting.URL+"/acme/api")
+		require.NoError(t, err)
+		content, err := client.GetFileContent(context.Background(), counting.URL+"/acme/api", "go.mod")
+		require.NoError(t, err)
+		return files, string(content)
+	}
+
+	files, content := analyze("")
+	assert.Equal(t, []string{"go.mod", "web/package.json"}, files)
+	assert.Equal(t, "module api", content)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	// A repeated run on the same commit is served from the cache
+	files, content = analyze("")
+	assert.Equal(t, []string{"go.mod", "web/package.json"}, files)
+	assert.Equal(t, "module api", content)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	// Another commit is downloaded again
+	files, content = analyze("release")
+	assert.Equal(t, []string{"go.mod"}, files)
+	assert.Equal(t, "module api // release", content)
+	assert.Equal(t, int32(4), downloads.Load())
+}
+
+func TestClient_HeadCommit(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("", fakegitlab.Repository{
+		Path:  "acme/api",
+		Files: map[string]string{"go.mod": "module api"},
+		Refs:  map[string]map[string]string{"release": {"go.mod": "module api // release"}},
+	})
+	defer server.Close()
+
+	headCommit := func(ref string) string {
+		t.Helper()
+		client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+		require.NoError(t, err)
+		commit, err := client.WithRef(ref).HeadCommit(context.Background(), server.URL()+"/acme/api")
+		require.NoError(t, err)
+		return commit
+	}
+
+	head := headCommit("")
+	assert.Len(t, head, 40)
+	assert.Equal(t, head, headCommit(""), "the same content is the same commit")
+	assert.NotEqual(t, head, headCommit("release"))
+
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	_, err = client.HeadCommit(context.Background(), server.URL()+"/acme/missing")
+	require.Error(t, err)
+}
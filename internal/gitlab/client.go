@@ -2,40 +2,221 @@ package gitlab
 
 import (
 	"context"
+	"di-matrix-cli/internal/cache"
 	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/retry"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 	"go.uber.org/zap"
 )
 
+// symlinkMode is the git file mode of symbolic links
+const symlinkMode = "120000"
+
+// defaultPageWorkers is the number of group listing pages fetched concurrently
+const defaultPageWorkers = 5
+
+// RetryPolicies configures retries per class of GitLab call
+type RetryPolicies struct {
+	Metadata retry.Policy // Users, groups and projects
+	Tree     retry.Policy // Repository tree listing
+	Content  retry.Policy // File content downloads
+}
+
+// DefaultRetryPolicies returns the policies used unless configured otherwise
+func DefaultRetryPolicies() RetryPolicies {
+	return RetryPolicies{
+		Metadata: retry.Policy{Retries: 3, Backoff: 500 * time.Millisecond, Timeout: 30 * time.Second},
+		Tree:     retry.Policy{Retries: 3, Backoff: 500 * time.Millisecond, Timeout: 60 * time.Second},
+		Content:  retry.Policy{Retries: 3, Backoff: 500 * time.Millisecond, Timeout: 30 * time.Second},
+	}
+}
+
+// TokenType selects how a token authenticates to GitLab
+type TokenType string
+
+// Token types, by the header the token is sent in
+const (
+	PrivateToken TokenType = "private" // Personal, project or group access token (PRIVATE-TOKEN), the default
+	JobToken     TokenType = "job"     // CI_JOB_TOKEN of a running GitLab CI job (JOB-TOKEN)
+	OAuthToken   TokenType = "oauth"   // OAuth2 access token (Authorization: Bearer)
+)
+
 // Client handles GitLab API operations
 type Client struct {
-	baseURL string
-	token   string
-	client  *gitlab.Client
-	logger  *zap.Logger
+	baseURL   string
+	token     string
+	tokenType TokenType
+	client    *gitlab.Client
+	retries   RetryPolicies
+	logger    *zap.Logger
+
+	groupFilters map[string]GroupFilter // Group path -> projects its entry expands to
+	refs         map[string]string      // Project or group path -> branch or tag to analyze
+	ref          string                 // Branch or tag analyzed in every repository, overrides refs
+	skipArchived bool                   // Leave archived projects out of group entries
+	includeForks bool                   // Keep forks in group entries
+	requests     chan struct{}          // Slots of the requests in flight, nil means unlimited
+	pageWorkers  int                    // Group listing pages fetched concurrently
+
+	cache       *cache.Store        // Trees and file contents of commits, nil disables caching
+	revisionsMu sync.Mutex          // Guards revisions
+	revisions   map[string]revision // Project path -> commit analyzed in this run
+
+	callsMu sync.Mutex     // Guards calls
+	calls   map[string]int // Operation -> API requests sent, retries included
 }
 
-// NewClient creates a new GitLab client
-func NewClient(baseURL, token string, logger *zap.Logger) (*Client, error) {
-	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
+// NewClient creates a new GitLab client authenticating with a token of the given type, empty is a private token
+func NewClient(baseURL, token string, tokenType TokenType, logger *zap.Logger) (*Client, error) {
+	// Retries are applied per operation class by the client instead of the library
+	options := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(baseURL), gitlab.WithoutRetries()}
+
+	var client *gitlab.Client
+	var err error
+	switch tokenType {
+	case "", PrivateToken:
+		tokenType = PrivateToken
+		client, err = gitlab.NewClient(token, options...)
+	case JobToken:
+		client, err = gitlab.NewJobClient(token, options...)
+	case OAuthToken:
+		client, err = gitlab.NewOAuthClient(token, options...)
+	default:
+		return nil, fmt.Errorf("unknown GitLab token type '%s'", tokenType)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
 	return &Client{
-		baseURL: baseURL,
-		token:   token,
-		client:  client,
-		logger:  logger,
+		baseURL:      baseURL,
+		token:        token,
+		tokenType:    tokenType,
+		client:       client,
+		retries:      DefaultRetryPolicies(),
+		logger:       logger,
+		skipArchived: true,
+		pageWorkers:  defaultPageWorkers,
+		revisions:    make(map[string]revision),
+		calls:        make(map[string]int),
 	}, nil
 }
 
+// WithRetryPolicies sets how metadata, tree listing and file content calls are retried
+func (c *Client) WithRetryPolicies(policies RetryPolicies) *Client {
+	c.retries = policies
+	return c
+}
+
+// WithMaxConcurrentRequests limits the API requests in flight across every caller of the client,
+// scanner and pagination workers included (0 means unlimited)
+func (c *Client) WithMaxConcurrentRequests(limit int) *Client {
+	c.requests = nil
+	if limit > 0 {
+		c.requests = make(chan struct{}, limit)
+	}
+	return c
+}
+
+// WithPaginationWorkers sets the number of pages of a group listing fetched concurrently
+func (c *Client) WithPaginationWorkers(workers int) *Client {
+	if workers > 0 {
+		c.pageWorkers = workers
+	}
+	return c
+}
+
+// acquire waits for a request slot, it returns a function releasing it
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.requests == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.requests <- struct{}{}:
+		return func() { <-c.requests }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// call runs a GitLab API call under policy. Only transient failures are retried:
+// network errors, timeouts, rate limiting and server errors, never authentication failures.
+// Each attempt holds a request slot, waiting between retries does not.
+func (c *Client) call(
+	ctx context.Context,
+	operation string,
+	policy retry.Policy,
+	request func(ctx context.Context) error,
+) error {
+	var lastErr error
+	return policy.Do(ctx, isTransient, func(ctx context.Context) error {
+		if lastErr != nil {
+			c.logger.Warn("Retrying GitLab call",
+				zap.String("operation", operation),
+				zap.Error(lastErr))
+		}
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+		c.callsMu.Lock()
+		c.calls[operation]++
+		c.callsMu.Unlock()
+		lastErr = request(ctx)
+		return lastErr
+	})
+}
+
+// APICalls returns the API requests sent so far keyed by operation, retries included
+func (c *Client) APICalls() map[string]int {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+	return maps.Clone(c.calls)
+}
+
+// FileContentCalls returns the API requests downloading one file takes. Without the cache every download
+// looks the project up for its default branch first, with it the branch is resolved once per project.
+func (c *Client) FileContentCalls(string) int {
+	if c.cache == nil {
+		return 2
+	}
+	return 1
+}
+
+// isTransient reports whether a failed GitLab call may succeed when retried
+func isTransient(err error) bool {
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return false
+	}
+	var response *gitlab.ErrorResponse
+	if errors.As(err, &response) && response.Response != nil {
+		status := response.Response.StatusCode
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// getProject retrieves project metadata by path
+func (c *Client) getProject(ctx context.Context, projectPath string) (*gitlab.Project, error) {
+	var project *gitlab.Project
+	err := c.call(ctx, "get project", c.retries.Metadata, func(ctx context.Context) (err error) {
+		project, _, err = c.client.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
+		return err
+	})
+	return project, err
+}
+
 // GetRepository retrieves a repository by URL or ID
 func (c *Client) GetRepository(ctx context.Context, identifier string) (*domain.Repository, error) {
 	c.logger.Debug("Starting GetRepository", zap.String("identifier", identifier))
@@ -52,7 +233,7 @@ func (c *Client) GetRepository(ctx context.Context, identifier string) (*domain.
 
 	// Get project from GitLab API
 	c.logger.Debug("Calling GitLab API to get project", zap.String("project_path", projectPath))
-	project, _, err := c.client.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
+	project, err := c.getProject(ctx, projectPath)
 	if err != nil {
 		c.logger.Error("Failed to get project from API",
 			zap.String("project_path", projectPath),
@@ -64,13 +245,7 @@ func (c *Client) GetRepository(ctx context.Context, identifier string) (*domain.
 		zap.Int("project_id", project.ID))
 
 	// Convert to domain.Repository
-	repo := &domain.Repository{
-		ID:            project.ID,
-		Name:          project.Name,
-		URL:           project.WebURL,
-		DefaultBranch: project.DefaultBranch,
-		WebURL:        project.WebURL,
-	}
+	repo := c.repository(project)
 
 	c.logger.Debug("Completed GetRepository", zap.String("project_name", repo.Name))
 
@@ -81,9 +256,19 @@ func (c *Client) GetRepository(ctx context.Context, identifier string) (*domain.
 func (c *Client) CheckPermissions(ctx context.Context) error {
 	c.logger.Debug("Starting CheckPermissions")
 
+	// Job tokens have no user behind them, GitLab rejects /user; the first project request tells instead
+	if c.tokenType == JobToken {
+		c.logger.Debug("Skipping token verification for a CI job token")
+		return nil
+	}
+
 	// Try to get current user to verify token permissions
 	c.logger.Debug("Calling GitLab API to verify token permissions")
-	user, _, err := c.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	var user *gitlab.User
+	err := c.call(ctx, "current user", c.retries.Metadata, func(ctx context.Context) (err error) {
+		user, _, err = c.client.Users.CurrentUser(gitlab.WithContext(ctx))
+		return err
+	})
 	if err != nil {
 		c.logger.Error("Failed to verify token permissions", zap.Error(err))
 		return fmt.Errorf("failed to verify token permissions: %w", err)
@@ -96,6 +281,11 @@ func (c *Client) CheckPermissions(ctx context.Context) error {
 	return nil
 }
 
+// IsAuthError reports whether err was caused by GitLab rejecting the token (401) or its scopes (403)
+func IsAuthError(err error) bool {
+	return gitlab.HasStatusCode(err, http.StatusUnauthorized) || gitlab.HasStatusCode(err, http.StatusForbidden)
+}
+
 // GetRepositoriesList returns a list of repositories from a group or project URL
 func (c *Client) GetRepositoriesList(ctx context.Context, repoURL string) ([]*domain.Repository, error) {
 	c.logger.Debug("Starting GetRepositoriesList", zap.String("repo_url", repoURL))
@@ -112,19 +302,27 @@ func (c *Client) GetRepositoriesList(ctx context.Context, repoURL string) ([]*do
 
 	// Check if it's a group by trying to get group info first
 	c.logger.Debug("Checking if path is a group", zap.String("path", path))
-	group, _, err := c.client.Groups.GetGroup(path, nil, gitlab.WithContext(ctx))
+	var group *gitlab.Group
+	err = c.call(ctx, "get group", c.retries.Metadata, func(ctx context.Context) (err error) {
+		group, _, err = c.client.Groups.GetGroup(path, nil, gitlab.WithContext(ctx))
+		return err
+	})
 	if err == nil {
 		c.logger.Debug("Path is a group, fetching group projects",
 			zap.String("group_name", group.Name),
 			zap.Int("group_id", group.ID))
-		// It's a group, get all projects in the group
-		return c.getGroupProjects(ctx, group.ID)
+		// It's a group, get all projects in the group and its subgroups the entry asks for
+		repos, err := c.getGroupProjects(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		return c.filterGroupProjects(path, repos), nil
 	}
 	c.logger.Debug("Path is not a group, trying as single project", zap.String("path", path))
 
 	// If not a group, try to get as a single project
 	c.logger.Debug("Calling GitLab API to get single project", zap.String("path", path))
-	project, _, err := c.client.Projects.GetProject(path, nil, gitlab.WithContext(ctx))
+	project, err := c.getProject(ctx, path)
 	if err != nil {
 		c.logger.Error("Failed to get project or group",
 			zap.String("path", path),
@@ -137,13 +335,7 @@ func (c *Client) GetRepositoriesList(ctx context.Context, repoURL string) ([]*do
 		zap.Int("project_id", project.ID))
 
 	// Convert single project to repository list
-	repo := &domain.Repository{
-		ID:            project.ID,
-		Name:          project.Name,
-		URL:           project.WebURL,
-		DefaultBranch: project.DefaultBranch,
-		WebURL:        project.WebURL,
-	}
+	repo := c.repository(project)
 
 	c.logger.Debug("Completed GetRepositoriesList for single project",
 		zap.String("project_name", repo.Name))
@@ -165,9 +357,13 @@ func (c *Client) GetFilesList(ctx context.Context, repoURL string) ([]string, er
 	}
 	c.logger.Debug("Extracted project path", zap.String("project_path", projectPath))
 
+	if c.cache != nil {
+		return c.cachedFilesList(ctx, projectPath)
+	}
+
 	// Get project to determine default branch
 	c.logger.Debug("Getting project info to determine default branch", zap.String("project_path", projectPath))
-	project, _, err := c.client.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
+	project, err := c.getProject(ctx, projectPath)
 	if err != nil {
 		c.logger.Error("Failed to get project",
 			zap.String("project_path", projectPath),
@@ -178,10 +374,14 @@ func (c *Client) GetFilesList(ctx context.Context, repoURL string) ([]string, er
 		zap.String("project_name", project.Name),
 		zap.String("default_branch", project.DefaultBranch))
 
-	// Get repository tree with pagination
+	return c.listTree(ctx, projectPath, c.refFor(projectPath, project.DefaultBranch))
+}
+
+// listTree returns the file paths of the repository tree at ref, following pagination
+func (c *Client) listTree(ctx context.Context, projectPath, ref string) ([]string, error) {
 	c.logger.Debug("Starting repository tree traversal",
 		zap.String("project_path", projectPath),
-		zap.String("default_branch", project.DefaultBranch))
+		zap.String("ref", ref))
 
 	var allFiles []string
 	page := 1
@@ -193,26 +393,32 @@ func (c *Client) GetFilesList(ctx context.Context, repoURL string) ([]string, er
 			zap.Int("page", page),
 			zap.Int("per_page", perPage))
 
-		tree, _, err := c.client.Repositories.ListTree(projectPath, &gitlab.ListTreeOptions{
-			Recursive: gitlab.Ptr(true),
-			Ref:       gitlab.Ptr(project.DefaultBranch),
-			ListOptions: gitlab.ListOptions{
-				Page:    page,
-				PerPage: perPage,
-			},
-		}, gitlab.WithContext(ctx))
+		var tree []*gitlab.TreeNode
+		err := c.call(ctx, "list tree", c.retries.Tree, func(ctx context.Context) (err error) {
+			tree, _, err = c.client.Repositories.ListTree(projectPath, &gitlab.ListTreeOptions{
+				Recursive: gitlab.Ptr(true),
+				Ref:       gitlab.Ptr(ref),
+				ListOptions: gitlab.ListOptions{
+					Page:    page,
+					PerPage: perPage,
+				},
+			}, gitlab.WithContext(ctx))
+			return err
+		})
 		if err != nil {
 			c.logger.Error("Failed to get repository tree",
 				zap.String("project_path", projectPath),
 				zap.Int("page", page),
 				zap.Error(err))
-			return nil, fmt.Errorf("failed to get repository tree for %s: %w", projectPath, err)
+			return nil, fmt.Errorf("failed to get repository tree for %s at %s: %w", projectPath, ref, err)
 		}
 
 		// Extract file paths (exclude directories)
 		filesInPage := 0
 		for _, item := range tree {
-			if item.Type == "blob" { // blob = file, tree = directory
+			// blob = file, tree = directory, commit = submodule
+			// Symlinks are blobs with mode 120000, skip them so linked manifests are not counted twice
+			if item.Type == "blob" && item.Mode != symlinkMode {
 				allFiles = append(allFiles, item.Path)
 				filesInPage++
 			}
@@ -260,9 +466,13 @@ func (c *Client) GetFileContent(ctx context.Context, repoURL, filePath string) (
 	}
 	c.logger.Debug("Extracted project path", zap.String("project_path", projectPath))
 
+	if c.cache != nil {
+		return c.cachedFileContent(ctx, projectPath, filePath)
+	}
+
 	// Get project to determine default branch
 	c.logger.Debug("Getting project info for file access", zap.String("project_path", projectPath))
-	project, _, err := c.client.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
+	project, err := c.getProject(ctx, projectPath)
 	if err != nil {
 		c.logger.Error("Failed to get project",
 			zap.String("project_path", projectPath),
@@ -273,15 +483,23 @@ func (c *Client) GetFileContent(ctx context.Context, repoURL, filePath string) (
 		zap.String("project_name", project.Name),
 		zap.String("default_branch", project.DefaultBranch))
 
-	// Get file content
+	return c.downloadFile(ctx, projectPath, filePath, c.refFor(projectPath, project.DefaultBranch))
+}
+
+// downloadFile returns the decoded content of a file at ref
+func (c *Client) downloadFile(ctx context.Context, projectPath, filePath, ref string) ([]byte, error) {
 	c.logger.Debug("Fetching file content",
 		zap.String("project_path", projectPath),
 		zap.String("file_path", filePath),
-		zap.String("ref", project.DefaultBranch))
+		zap.String("ref", ref))
 
-	file, _, err := c.client.RepositoryFiles.GetFile(projectPath, filePath, &gitlab.GetFileOptions{
-		Ref: gitlab.Ptr(project.DefaultBranch),
-	}, gitlab.WithContext(ctx))
+	var file *gitlab.File
+	err := c.call(ctx, "get file", c.retries.Content, func(ctx context.Context) (err error) {
+		file, _, err = c.client.RepositoryFiles.GetFile(projectPath, filePath, &gitlab.GetFileOptions{
+			Ref: gitlab.Ptr(ref),
+		}, gitlab.WithContext(ctx))
+		return err
+	})
 	if err != nil {
 		c.logger.Error("Failed to get file content",
 			zap.String("project_path", projectPath),
@@ -327,13 +545,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 		zap.Int("group_id", groupID),
 		zap.Int("per_page", perPage))
 
-	firstPage, resp, err := c.client.Groups.ListGroupProjects(groupID, &gitlab.ListGroupProjectsOptions{
-		ListOptions: gitlab.ListOptions{
-			Page:    1,
-			PerPage: perPage,
-		},
-		IncludeSubGroups: gitlab.Ptr(true),
-	}, gitlab.WithContext(ctx))
+	firstPage, resp, err := c.listGroupProjects(ctx, groupID, 1, perPage)
 	if err != nil {
 		c.logger.Error("Failed to get first page of projects",
 			zap.Int("group_id", groupID),
@@ -352,7 +564,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 		c.logger.Debug("Single page detected, returning results",
 			zap.Int("group_id", groupID),
 			zap.Int("total_projects", len(firstPage)))
-		return c.ConvertProjectsToRepositories(firstPage), nil
+		return c.groupRepositories(groupID, firstPage), nil
 	}
 
 	// Calculate total pages from response headers
@@ -361,7 +573,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 		c.logger.Debug("Only one page total, returning results",
 			zap.Int("group_id", groupID),
 			zap.Int("total_projects", len(firstPage)))
-		return c.ConvertProjectsToRepositories(firstPage), nil
+		return c.groupRepositories(groupID, firstPage), nil
 	}
 
 	c.logger.Debug("Multi-page group detected, starting concurrent fetch",
@@ -371,7 +583,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 		zap.Int("total_projects", resp.TotalItems))
 
 	// Use worker pool pattern for concurrent pagination
-	const maxWorkers = 5                     // Limit concurrent requests to avoid overwhelming the API
+	maxWorkers := c.pageWorkers              // Limit concurrent requests to avoid overwhelming the API
 	pageChan := make(chan int, totalPages-1) // Channel for page numbers (skip page 1, already fetched)
 	resultChan := make(chan []*domain.Repository, totalPages-1)
 	errorChan := make(chan error, totalPages-1)
@@ -412,13 +624,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 					errorChan <- ctx.Err()
 					return
 				default:
-					projects, _, err := c.client.Groups.ListGroupProjects(groupID, &gitlab.ListGroupProjectsOptions{
-						ListOptions: gitlab.ListOptions{
-							Page:    page,
-							PerPage: perPage,
-						},
-						IncludeSubGroups: gitlab.Ptr(true),
-					}, gitlab.WithContext(ctx))
+					projects, _, err := c.listGroupProjects(ctx, groupID, page, perPage)
 					if err != nil {
 						c.logger.Error("Worker failed to get page",
 							zap.Int("group_id", groupID),
@@ -435,7 +641,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 						zap.Int("page", page),
 						zap.Int("projects_count", len(projects)))
 
-					resultChan <- c.ConvertProjectsToRepositories(projects)
+					resultChan <- c.groupRepositories(groupID, projects)
 				}
 			}
 
@@ -458,7 +664,7 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 		zap.Int("expected_results", totalPages-1))
 
 	var allRepos []*domain.Repository
-	allRepos = append(allRepos, c.ConvertProjectsToRepositories(firstPage)...) // Add first page results
+	allRepos = append(allRepos, c.groupRepositories(groupID, firstPage)...) // Add first page results
 
 	c.logger.Debug("Added first page results",
 		zap.Int("group_id", groupID),
@@ -499,21 +705,52 @@ func (c *Client) getGroupProjects(ctx context.Context, groupID int) ([]*domain.R
 	return allRepos, nil
 }
 
+// listGroupProjects retrieves one page of the projects within a group and its subgroups
+func (c *Client) listGroupProjects(
+	ctx context.Context,
+	groupID, page, perPage int,
+) ([]*gitlab.Project, *gitlab.Response, error) {
+	var projects []*gitlab.Project
+	var resp *gitlab.Response
+	err := c.call(ctx, "list group projects", c.retries.Metadata, func(ctx context.Context) (err error) {
+		projects, resp, err = c.client.Groups.ListGroupProjects(groupID, &gitlab.ListGroupProjectsOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    page,
+				PerPage: perPage,
+			},
+			IncludeSubGroups: gitlab.Ptr(true),
+			Archived:         archivedOption(c.skipArchived),
+		}, gitlab.WithContext(ctx))
+		return err
+	})
+	return projects, resp, err
+}
+
 // ConvertProjectsToRepositories converts GitLab projects to domain repositories
 func (c *Client) ConvertProjectsToRepositories(projects []*gitlab.Project) []*domain.Repository {
 	repos := make([]*domain.Repository, 0, len(projects))
 	for _, project := range projects {
-		repos = append(repos, &domain.Repository{
-			ID:            project.ID,
-			Name:          project.Name,
-			URL:           project.WebURL,
-			DefaultBranch: project.DefaultBranch,
-			WebURL:        project.WebURL,
-		})
+		repos = append(repos, c.repository(project))
 	}
 	return repos
 }
 
+// repository converts a GitLab project to a domain repository analyzed at its configured ref
+func (c *Client) repository(project *gitlab.Project) *domain.Repository {
+	repo := &domain.Repository{
+		ID:            project.ID,
+		Name:          project.Name,
+		URL:           project.WebURL,
+		DefaultBranch: project.DefaultBranch,
+		WebURL:        project.WebURL,
+		Topics:        project.Topics,
+	}
+	if ref := c.refFor(project.PathWithNamespace, project.DefaultBranch); ref != project.DefaultBranch {
+		repo.Ref = ref
+	}
+	return repo
+}
+
 // ExtractProjectPath extracts the project path from a GitLab URL
 func (c *Client) ExtractProjectPath(gitlabURL string) (string, error) {
 	// Parse the URL
@@ -523,7 +760,14 @@ func (c *Client) ExtractProjectPath(gitlabURL string) (string, error) {
 	}
 
 	// Extract path segments after the base URL and remove trailing slash
-	path := strings.TrimPrefix(parsedURL.Path, "/")
+	path := parsedURL.Path
+	if base, err := url.Parse(c.baseURL); err == nil && strings.EqualFold(base.Host, parsedURL.Host) {
+		// Instances served below a path, e.g. https://example.com/gitlab
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" && strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+		}
+	}
+	path = strings.TrimPrefix(path, "/")
 	path = strings.TrimSuffix(path, "/")
 	if path == "" {
 		return "", fmt.Errorf("no path found in URL: %s", gitlabURL)
@@ -4,9 +4,17 @@ import (
 	"context"
 	"di-matrix-cli/internal/domain"
 	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/retry"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,7 +35,7 @@ func validateGitLabToken(t *testing.T) (string, string) {
 	}
 
 	// Quick validation: try to create a client and check permissions
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	// Test token validity with a simple permission check
@@ -49,7 +57,7 @@ func TestGitlabClient_CheckPermissions(t *testing.T) {
 	// Validate GitLab token and skip if invalid
 	token, baseURL := validateGitLabToken(t)
 
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	t.Run("successful permission check", func(t *testing.T) {
@@ -60,7 +68,7 @@ func TestGitlabClient_CheckPermissions(t *testing.T) {
 
 	t.Run("invalid token should fail", func(t *testing.T) {
 		t.Parallel()
-		invalidClient, err := gitlab.NewClient(baseURL, "invalid-token", zap.NewNop())
+		invalidClient, err := gitlab.NewClient(baseURL, "invalid-token", gitlab.PrivateToken, zap.NewNop())
 		require.NoError(t, err) // Client creation should succeed even with invalid token
 		err = invalidClient.CheckPermissions(context.Background())
 		require.Error(t, err)
@@ -68,13 +76,187 @@ func TestGitlabClient_CheckPermissions(t *testing.T) {
 	})
 }
 
+func TestIsAuthError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		status int
+		auth   bool
+	}{
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusNotFound, false},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(`{"message":"rejected"}`))
+		}))
+
+		client, err := gitlab.NewClient(server.URL, "token", gitlab.PrivateToken, zap.NewNop())
+		require.NoError(t, err)
+
+		err = client.CheckPermissions(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, tc.auth, gitlab.IsAuthError(err), "status %d", tc.status)
+		server.Close()
+	}
+
+	assert.False(t, gitlab.IsAuthError(errors.New("network unreachable")))
+}
+
+func TestClient_TokenTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		tokenType gitlab.TokenType
+		header    string
+		value     string
+	}{
+		{gitlab.PrivateToken, "PRIVATE-TOKEN", "token"},
+		{gitlab.JobToken, "JOB-TOKEN", "token"},
+		{gitlab.OAuthToken, "Authorization", "Bearer token"},
+	} {
+		var header atomic.Value
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header.Store(r.Header.Get(tc.header))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1,"name":"api","default_branch":"main"}`))
+		}))
+
+		client, err := gitlab.NewClient(server.URL, "token", tc.tokenType, zap.NewNop())
+		require.NoError(t, err)
+
+		_, err = client.GetRepository(context.Background(), server.URL+"/acme/api")
+		require.NoError(t, err)
+		assert.Equal(t, tc.value, header.Load(), "token type %s", tc.tokenType)
+		server.Close()
+	}
+
+	_, err := gitlab.NewClient("https://gitlab.com", "token", "cookie", zap.NewNop())
+	require.Error(t, err)
+}
+
+func TestClient_RetryPolicies(t *testing.T) {
+	t.Parallel()
+
+	var userCalls, fileCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v4/user":
+			userCalls.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+		case strings.Contains(r.URL.Path, "/repository/files/"):
+			// The first two downloads fail like an overloaded instance
+			if fileCalls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"encoding":"base64","content":"` +
+				base64.StdEncoding.EncodeToString([]byte("module api")) + `"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":1,"name":"api","default_branch":"main"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL, "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	policies := gitlab.DefaultRetryPolicies()
+	policies.Metadata = retry.Policy{Retries: 3, Backoff: time.Millisecond}
+	policies.Content = retry.Policy{Retries: 2, Backoff: time.Millisecond}
+	client.WithRetryPolicies(policies)
+
+	content, err := client.GetFileContent(context.Background(), server.URL+"/group/api", "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module api", string(content))
+	assert.Equal(t, int32(3), fileCalls.Load())
+
+	// Rejected tokens are never retried
+	err = client.CheckPermissions(context.Background())
+	require.Error(t, err)
+	assert.True(t, gitlab.IsAuthError(err))
+	assert.Equal(t, int32(1), userCalls.Load())
+
+	// Without content retries the flaky download fails
+	fileCalls.Store(0)
+	policies.Content = retry.Policy{}
+	_, err = client.WithRetryPolicies(policies).GetFileContent(context.Background(), server.URL+"/group/api", "go.mod")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), fileCalls.Load())
+
+	// Every attempt counts as an API call
+	calls := client.APICalls()
+	assert.Equal(t, 4, calls["get file"])
+	assert.Equal(t, 1, calls["current user"])
+}
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	arrived, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "blocked") {
+			close(arrived)
+			<-release
+		}
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"encoding":"base64","content":"` +
+			base64.StdEncoding.EncodeToString([]byte("module api")) + `"}`))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL, "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	client.WithMaxConcurrentRequests(2)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetFileContent(context.Background(), server.URL+"/group/api", "go.mod")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), peak.Load())
+
+	// Callers waiting for a slot give up with their context
+	client.WithMaxConcurrentRequests(1)
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		_, _ = client.GetFileContent(context.Background(), server.URL+"/group/api", "blocked.mod")
+	}()
+	<-arrived
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.GetFileContent(ctx, server.URL+"/group/api", "go.mod")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+	<-blocked
+}
+
 func TestGitlabClient_GetRepositoriesList(t *testing.T) {
 	t.Parallel()
 
 	// Validate GitLab token and skip if invalid
 	token, baseURL := validateGitLabToken(t)
 
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	t.Run("get single project", func(t *testing.T) {
@@ -143,7 +325,7 @@ func TestGitlabClient_GetFilesList(t *testing.T) {
 	// Validate GitLab token and skip if invalid
 	token, baseURL := validateGitLabToken(t)
 
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	t.Run("get files list from public project", func(t *testing.T) {
@@ -195,7 +377,7 @@ func TestGitlabClient_GetFileContent(t *testing.T) {
 	// Validate GitLab token and skip if invalid
 	token, baseURL := validateGitLabToken(t)
 
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	t.Run("get file content from public project", func(t *testing.T) {
@@ -250,7 +432,7 @@ func TestGitlabClient_GetRepository(t *testing.T) {
 	// Validate GitLab token and skip if invalid
 	token, baseURL := validateGitLabToken(t)
 
-	client, err := gitlab.NewClient(baseURL, token, zap.NewNop())
+	client, err := gitlab.NewClient(baseURL, token, gitlab.PrivateToken, zap.NewNop())
 	require.NoError(t, err)
 
 	t.Run("get repository by URL", func(t *testing.T) {
@@ -293,10 +475,10 @@ func TestGitlabClient_GetRepository(t *testing.T) {
 	})
 }
 
-// Test that the actual Client struct implements the GitlabClient interface
+// Test that the actual Client struct implements the SourceProvider interface
 func TestClient_ImplementsGitlabClientInterface(t *testing.T) {
 	t.Parallel()
-	var _ domain.GitlabClient = &gitlab.Client{}
+	var _ domain.SourceProvider = &gitlab.Client{}
 }
 
 // Test convertProjectsToRepositories converts GitLab projects correctly
@@ -0,0 +1,164 @@
+package gitlab
+
+import (
+	"di-matrix-cli/internal/domain"
+	"path"
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.uber.org/zap"
+)
+
+// GroupFilter limits which projects a group entry expands to. Paths are relative to the group,
+// e.g. "platform/billing-service" for a project in the platform subgroup.
+type GroupFilter struct {
+	MaxDepth    int      // Maximum subgroup depth (0 = unlimited, 1 = the group and its direct subgroups)
+	Include     []string // Globs of subgroups or projects to keep, empty keeps every project
+	Exclude     []string // Globs of subgroups or projects to drop, applied after Include
+	Topics      []string // Projects must have one of these topics, empty keeps every project
+	NamePattern string   // Glob the project name or path must match, e.g. "*-service"
+}
+
+// WithGroupFilters sets the filters of group entries keyed by their URL, entries without a filter
+// expand to every project of the group and its subgroups
+func (c *Client) WithGroupFilters(filters map[string]GroupFilter) *Client {
+	c.groupFilters = make(map[string]GroupFilter, len(filters))
+	for groupURL, filter := range filters {
+		if groupPath, err := c.ExtractProjectPath(groupURL); err == nil {
+			c.groupFilters[groupPath] = filter
+		}
+	}
+	return c
+}
+
+// WithArchivedSkipped toggles leaving archived projects out of group entries, they are skipped by default.
+// Projects configured by their own URL are analyzed whether archived or not.
+func (c *Client) WithArchivedSkipped(skip bool) *Client {
+	c.skipArchived = skip
+	return c
+}
+
+// WithForksIncluded toggles keeping forks in group entries, they are skipped by default
+func (c *Client) WithForksIncluded(include bool) *Client {
+	c.includeForks = include
+	return c
+}
+
+// archivedOption asks GitLab for unarchived projects only when archived ones are skipped
+func archivedOption(skipArchived bool) *bool {
+	if skipArchived {
+		return gitlab.Ptr(false)
+	}
+	return nil
+}
+
+// groupRepositories converts the projects of a group page to repositories, leaving out archived projects
+// and forks unless asked for and empty repositories, which have no default branch to analyze
+func (c *Client) groupRepositories(groupID int, projects []*gitlab.Project) []*domain.Repository {
+	kept := make([]*gitlab.Project, 0, len(projects))
+	for _, project := range projects {
+		if reason := c.skipReason(project); reason != "" {
+			c.logger.Debug("Skipping group project",
+				zap.Int("group_id", groupID),
+				zap.String("project_path", project.PathWithNamespace),
+				zap.String("reason", reason))
+			continue
+		}
+		kept = append(kept, project)
+	}
+	return c.ConvertProjectsToRepositories(kept)
+}
+
+// skipReason returns why a group project is left out ("archived", "fork" or "empty"), "" to keep it
+func (c *Client) skipReason(project *gitlab.Project) string {
+	switch {
+	case c.skipArchived && project.Archived:
+		return "archived"
+	case !c.includeForks && project.ForkedFromProject != nil:
+		return "fork"
+	case project.EmptyRepo || project.DefaultBranch == "":
+		return "empty"
+	default:
+		return ""
+	}
+}
+
+// Allows reports whether the project at relativePath within the group passes the filter
+func (f GroupFilter) Allows(relativePath string) bool {
+	segments := strings.Split(strings.Trim(relativePath, "/"), "/")
+	if f.MaxDepth > 0 && len(segments)-1 > f.MaxDepth {
+		return false
+	}
+
+	if len(f.Include) > 0 && !matchesAnyPrefix(segments, f.Include) {
+		return false
+	}
+	return !matchesAnyPrefix(segments, f.Exclude)
+}
+
+// Selects reports whether a project has one of the filter's topics and a name or path matching its pattern,
+// both compared case-insensitively
+func (f GroupFilter) Selects(repo *domain.Repository) bool {
+	if len(f.Topics) > 0 && !slices.ContainsFunc(repo.Topics, func(topic string) bool {
+		return slices.ContainsFunc(f.Topics, func(wanted string) bool { return strings.EqualFold(topic, wanted) })
+	}) {
+		return false
+	}
+
+	if f.NamePattern == "" {
+		return true
+	}
+	pattern := strings.ToLower(f.NamePattern)
+	for _, name := range []string{repo.Name, path.Base(strings.TrimRight(repo.WebURL, "/"))} {
+		if matched, err := path.Match(pattern, strings.ToLower(name)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterGroupProjects drops the projects of a group entry that its filter rejects
+func (c *Client) filterGroupProjects(groupPath string, repos []*domain.Repository) []*domain.Repository {
+	filter, ok := c.groupFilters[groupPath]
+	if !ok {
+		return repos
+	}
+
+	kept := make([]*domain.Repository, 0, len(repos))
+	for _, repo := range repos {
+		projectPath, err := c.ExtractProjectPath(repo.WebURL)
+		if err != nil || !strings.HasPrefix(projectPath, groupPath+"/") {
+			kept = append(kept, repo)
+			continue
+		}
+		if filter.Allows(strings.TrimPrefix(projectPath, groupPath+"/")) && filter.Selects(repo) {
+			kept = append(kept, repo)
+		}
+	}
+
+	c.logger.Debug("Applied group filter",
+		zap.String("group_path", groupPath),
+		zap.Int("projects", len(repos)),
+		zap.Int("kept", len(kept)))
+	return kept
+}
+
+// matchesAnyPrefix reports whether a glob matches a leading part of the path split into segments,
+// so "platform/*" matches every project below a direct subgroup of platform
+func matchesAnyPrefix(segments []string, globs []string) bool {
+	for _, glob := range globs {
+		glob = strings.Trim(glob, "/")
+		if glob == "" {
+			continue
+		}
+		globDepth := strings.Count(glob, "/") + 1
+		if globDepth > len(segments) {
+			continue
+		}
+		if matched, err := path.Match(glob, strings.Join(segments[:globDepth], "/")); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package gitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/gitlab"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGroupFilter_Allows(t *testing.T) {
+	t.Parallel()
+
+	filter := gitlab.GroupFilter{Include: []string{"platform/*", "tools"}, Exclude: []string{"platform/sandbox/*"}}
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"platform/billing-service", true},
+		{"platform/payments/gateway", true},
+		{"platform/sandbox/playground", false},
+		{"platform/sandbox/deep/experiment", false},
+		{"tools/linter", true},
+		{"web/storefront", false},
+		{"root-app", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, filter.Allows(tt.path), tt.path)
+	}
+
+	depth := gitlab.GroupFilter{MaxDepth: 1}
+	assert.True(t, depth.Allows("root-app"))
+	assert.True(t, depth.Allows("platform/billing-service"))
+	assert.False(t, depth.Allows("platform/payments/gateway"))
+	assert.True(t, gitlab.GroupFilter{}.Allows("platform/payments/gateway"), "The zero filter keeps everything")
+}
+
+func TestGroupFilter_Selects(t *testing.T) {
+	t.Parallel()
+
+	billing := &domain.Repository{
+		Name:   "Billing Service",
+		WebURL: "https://gitlab.com/acme/billing-service",
+		Topics: []string{"Backend", "payments"},
+	}
+	storefront := &domain.Repository{Name: "storefront", WebURL: "https://gitlab.com/acme/storefront"}
+
+	assert.True(t, gitlab.GroupFilter{}.Selects(storefront), "The zero filter keeps everything")
+	assert.True(t, gitlab.GroupFilter{Topics: []string{"backend"}}.Selects(billing))
+	assert.False(t, gitlab.GroupFilter{Topics: []string{"backend"}}.Selects(storefront))
+	assert.True(t, gitlab.GroupFilter{NamePattern: "*-service"}.Selects(billing), "Matched against the path")
+	assert.True(t, gitlab.GroupFilter{NamePattern: "billing *"}.Selects(billing), "Matched against the name")
+	assert.False(t, gitlab.GroupFilter{NamePattern: "*-service"}.Selects(storefront))
+	assert.False(t, gitlab.GroupFilter{Topics: []string{"frontend"}, NamePattern: "*-service"}.Selects(billing))
+}
+
+func TestClient_GetRepositoriesList_GroupFilters(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("",
+		fakegitlab.Repository{Path: "acme/root-app"},
+		fakegitlab.Repository{Path: "acme/platform/billing-service"},
+		fakegitlab.Repository{Path: "acme/platform/payments/gateway"},
+		fakegitlab.Repository{Path: "acme/platform/sandbox/playground"},
+		fakegitlab.Repository{Path: "acme/web/storefront"},
+	)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	client.WithGroupFilters(map[string]gitlab.GroupFilter{
+		server.RepositoryURL("acme/platform") + "/": {MaxDepth: 1},
+		server.RepositoryURL("acme"): {
+			Include: []string{"platform/*"},
+			Exclude: []string{"platform/sandbox/*"},
+		},
+	})
+
+	names := func(repoURL string) []string {
+		repos, err := client.GetRepositoriesList(context.Background(), repoURL)
+		require.NoError(t, err)
+		var result []string
+		for _, repo := range repos {
+			result = append(result, repo.Name)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{"billing-service", "gateway"}, names(server.RepositoryURL("acme")))
+	assert.ElementsMatch(t, []string{"billing-service", "gateway", "playground"},
+		names(server.RepositoryURL("acme/platform")), "Trailing slashes of entry URLs are ignored")
+	assert.ElementsMatch(t, []string{"storefront"}, names(server.RepositoryURL("acme/web")), "Unfiltered group")
+}
+
+func TestClient_GetRepositoriesList_TopicsAndNamePattern(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("",
+		fakegitlab.Repository{Path: "acme/billing-service", Topics: []string{"backend"}},
+		fakegitlab.Repository{Path: "acme/platform/auth-service", Topics: []string{"backend", "security"}},
+		fakegitlab.Repository{Path: "acme/platform/docs-site", Topics: []string{"backend"}},
+		fakegitlab.Repository{Path: "acme/storefront", Topics: []string{"frontend"}},
+	)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	client.WithGroupFilters(map[string]gitlab.GroupFilter{
+		server.RepositoryURL("acme"): {Topics: []string{"backend"}, NamePattern: "*-service"},
+	})
+
+	repos, err := client.GetRepositoriesList(context.Background(), server.RepositoryURL("acme"))
+	require.NoError(t, err)
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	assert.ElementsMatch(t, []string{"billing-service", "auth-service"}, names)
+}
+
+func TestClient_GetRepositoriesList_SkipsArchivedForksAndEmpty(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("",
+		fakegitlab.Repository{Path: "acme/api"},
+		fakegitlab.Repository{Path: "acme/legacy", Archived: true},
+		fakegitlab.Repository{Path: "acme/team/api-fork", ForkOf: "acme/api"},
+		fakegitlab.Repository{Path: "acme/team/placeholder", Empty: true},
+	)
+	defer server.Close()
+
+	names := func(client *gitlab.Client, repoURL string) []string {
+		repos, err := client.GetRepositoriesList(context.Background(), repoURL)
+		require.NoError(t, err)
+		var result []string
+		for _, repo := range repos {
+			result = append(result, repo.Name)
+		}
+		return result
+	}
+
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, names(client, server.RepositoryURL("acme")), "Skipped by default")
+	assert.Equal(t, []string{"legacy"}, names(client, server.RepositoryURL("acme/legacy")),
+		"Projects configured by their own URL are kept")
+
+	client.WithArchivedSkipped(false).WithForksIncluded(true)
+	assert.ElementsMatch(t, []string{"api", "legacy", "api-fork"}, names(client, server.RepositoryURL("acme")),
+		"Empty repositories have nothing to analyze")
+}
@@ -0,0 +1,95 @@
+package gitlab
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Instances reads repositories from several GitLab instances, routing every repository URL to the client
+// of the instance whose base URL it starts with
+type Instances struct {
+	clients []*Client // The first one reads URLs of unknown hosts
+}
+
+// NewInstances routes repository URLs to clients, URLs matching no base URL go to the first client
+func NewInstances(primary *Client, others ...*Client) *Instances {
+	return &Instances{clients: append([]*Client{primary}, others...)}
+}
+
+// clientFor returns the client of the instance hosting repoURL, the most specific base URL wins
+func (i *Instances) clientFor(repoURL string) *Client {
+	target, err := url.Parse(repoURL)
+	if err != nil {
+		return i.clients[0]
+	}
+
+	best, bestLength := i.clients[0], -1
+	for _, client := range i.clients {
+		base, err := url.Parse(client.baseURL)
+		if err != nil || !strings.EqualFold(base.Host, target.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(base.Path, "/")
+		if prefix != "" && target.Path != prefix && !strings.HasPrefix(target.Path, prefix+"/") {
+			continue
+		}
+		if len(prefix) > bestLength {
+			best, bestLength = client, len(prefix)
+		}
+	}
+	return best
+}
+
+// CheckPermissions verifies the token of every instance
+func (i *Instances) CheckPermissions(ctx context.Context) error {
+	for _, client := range i.clients {
+		if err := client.CheckPermissions(ctx); err != nil {
+			return fmt.Errorf("%s: %w", client.baseURL, err)
+		}
+	}
+	return nil
+}
+
+// GetRepositoriesList returns the repositories of a group or project URL from the instance hosting it
+func (i *Instances) GetRepositoriesList(ctx context.Context, repoURL string) ([]*domain.Repository, error) {
+	return i.clientFor(repoURL).GetRepositoriesList(ctx, repoURL)
+}
+
+// GetFilesList returns the file paths of a repository from the instance hosting it
+func (i *Instances) GetFilesList(ctx context.Context, repoURL string) ([]string, error) {
+	return i.clientFor(repoURL).GetFilesList(ctx, repoURL)
+}
+
+// GetFileContent returns the content of a file from the instance hosting its repository
+func (i *Instances) GetFileContent(ctx context.Context, repoURL, filePath string) ([]byte, error) {
+	return i.clientFor(repoURL).GetFileContent(ctx, repoURL, filePath)
+}
+
+// GetSubmodules returns the submodules of a repository from the instance hosting it
+func (i *Instances) GetSubmodules(ctx context.Context, repoURL string) ([]domain.Submodule, error) {
+	return i.clientFor(repoURL).GetSubmodules(ctx, repoURL)
+}
+
+// HeadCommit returns the commit a repository is analyzed at from the instance hosting it
+func (i *Instances) HeadCommit(ctx context.Context, repoURL string) (string, error) {
+	return i.clientFor(repoURL).HeadCommit(ctx, repoURL)
+}
+
+// FileContentCalls returns the API requests downloading one file takes on the instance hosting repoURL
+func (i *Instances) FileContentCalls(repoURL string) int {
+	return i.clientFor(repoURL).FileContentCalls(repoURL)
+}
+
+// APICalls returns the API requests sent to all instances keyed by operation, retries included
+func (i *Instances) APICalls() map[string]int {
+	calls := make(map[string]int)
+	for _, client := range i.clients {
+		for operation, count := range client.APICalls() {
+			calls[operation] += count
+		}
+	}
+	return calls
+}
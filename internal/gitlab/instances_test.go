@@ -0,0 +1,67 @@
+package gitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/gitlab"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInstances_RoutesByBaseURL(t *testing.T) {
+	t.Parallel()
+
+	selfHosted := fakegitlab.New("self-hosted-token",
+		fakegitlab.Repository{Path: "acme/api", Files: map[string]string{"go.mod": "module api\n"}})
+	defer selfHosted.Close()
+	saas := fakegitlab.New("saas-token",
+		fakegitlab.Repository{Path: "acme/web", Files: map[string]string{"package.json": "{}"}})
+	defer saas.Close()
+
+	primary, err := gitlab.NewClient(selfHosted.URL(), "self-hosted-token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	other, err := gitlab.NewClient(saas.URL(), "saas-token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	instances := gitlab.NewInstances(primary, other)
+	ctx := context.Background()
+
+	require.NoError(t, instances.CheckPermissions(ctx))
+
+	api, err := instances.GetRepositoriesList(ctx, selfHosted.RepositoryURL("acme/api"))
+	require.NoError(t, err)
+	require.Len(t, api, 1)
+	web, err := instances.GetRepositoriesList(ctx, saas.RepositoryURL("acme"))
+	require.NoError(t, err)
+	require.Len(t, web, 1, "Groups expand on the instance hosting them")
+
+	files, err := instances.GetFilesList(ctx, web[0].URL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"package.json"}, files)
+
+	content, err := instances.GetFileContent(ctx, api[0].URL, "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module api\n", string(content))
+
+	calls := instances.APICalls()
+	assert.Equal(t, primary.APICalls()["get project"]+other.APICalls()["get project"], calls["get project"])
+}
+
+func TestInstances_CheckPermissionsNamesInstance(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("secret")
+	defer server.Close()
+
+	primary, err := gitlab.NewClient(server.URL(), "secret", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+	other, err := gitlab.NewClient(server.URL()+"/mirror", "wrong", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	err = gitlab.NewInstances(primary, other).CheckPermissions(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), server.URL()+"/mirror")
+	assert.True(t, gitlab.IsAuthError(err))
+}
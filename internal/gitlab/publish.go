@@ -0,0 +1,149 @@
+package gitlab
+
+import (
+	"context"
+	"di-matrix-cli/internal/retry"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.uber.org/zap"
+)
+
+// writePolicy returns the policy of calls changing a project. They are never retried: a commit or page edit
+// that reached GitLab before the failure would be applied twice.
+func (c *Client) writePolicy() retry.Policy {
+	return retry.Policy{Timeout: c.retries.Content.Timeout}
+}
+
+// CommitFiles commits files, keyed by their path in the repository, to branch of the project at projectURL in a
+// single commit. Files already on the branch are updated, the branch is created from the default branch when
+// missing.
+func (c *Client) CommitFiles(ctx context.Context, projectURL, branch, message string, files map[string][]byte) error {
+	projectPath, err := c.ExtractProjectPath(projectURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract project path from URL %s: %w", projectURL, err)
+	}
+
+	options := &gitlab.CreateCommitOptions{
+		Branch:        gitlab.Ptr(branch),
+		CommitMessage: gitlab.Ptr(message),
+	}
+	err = c.call(ctx, "get branch", c.retries.Metadata, func(ctx context.Context) error {
+		_, _, err := c.client.Branches.GetBranch(projectPath, branch, gitlab.WithContext(ctx))
+		return err
+	})
+	branchExists := err == nil
+	if errors.Is(err, gitlab.ErrNotFound) {
+		project, err := c.getProject(ctx, projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get project %s: %w", projectPath, err)
+		}
+		// Empty repositories have no default branch, the commit creates the branch on its own
+		if project.DefaultBranch != "" {
+			options.StartBranch = gitlab.Ptr(project.DefaultBranch)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get branch %s of project %s: %w", branch, projectPath, err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		action := gitlab.FileCreate
+		if branchExists {
+			exists, err := c.fileExists(ctx, projectPath, path, branch)
+			if err != nil {
+				return err
+			}
+			if exists {
+				action = gitlab.FileUpdate
+			}
+		}
+		options.Actions = append(options.Actions, &gitlab.CommitActionOptions{
+			Action:   gitlab.Ptr(action),
+			FilePath: gitlab.Ptr(path),
+			Content:  gitlab.Ptr(base64.StdEncoding.EncodeToString(files[path])),
+			Encoding: gitlab.Ptr("base64"),
+		})
+	}
+
+	err = c.call(ctx, "create commit", c.writePolicy(), func(ctx context.Context) error {
+		_, _, err := c.client.Commits.CreateCommit(projectPath, options, gitlab.WithContext(ctx))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit to branch %s of project %s: %w", branch, projectPath, err)
+	}
+
+	c.logger.Debug("Committed files",
+		zap.String("project_path", projectPath),
+		zap.String("branch", branch),
+		zap.Int("files", len(files)))
+	return nil
+}
+
+// fileExists reports whether the file at filePath exists at ref of the project
+func (c *Client) fileExists(ctx context.Context, projectPath, filePath, ref string) (bool, error) {
+	err := c.call(ctx, "get file metadata", c.retries.Metadata, func(ctx context.Context) error {
+		_, _, err := c.client.RepositoryFiles.GetFileMetaData(projectPath, filePath, &gitlab.GetFileMetaDataOptions{
+			Ref: gitlab.Ptr(ref),
+		}, gitlab.WithContext(ctx))
+		return err
+	})
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get file %s from project %s: %w", filePath, projectPath, err)
+	}
+	return true, nil
+}
+
+// SaveWikiPage writes content to the Markdown wiki page at slug of the project at projectURL, creating the page
+// when missing
+func (c *Client) SaveWikiPage(ctx context.Context, projectURL, slug, content string) error {
+	projectPath, err := c.ExtractProjectPath(projectURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract project path from URL %s: %w", projectURL, err)
+	}
+
+	err = c.call(ctx, "get wiki page", c.retries.Metadata, func(ctx context.Context) error {
+		_, _, err := c.client.Wikis.GetWikiPage(projectPath, slug, nil, gitlab.WithContext(ctx))
+		return err
+	})
+	switch {
+	case errors.Is(err, gitlab.ErrNotFound):
+		// The slug of a new page is derived from its title
+		err = c.call(ctx, "create wiki page", c.writePolicy(), func(ctx context.Context) error {
+			_, _, err := c.client.Wikis.CreateWikiPage(projectPath, &gitlab.CreateWikiPageOptions{
+				Title:   gitlab.Ptr(slug),
+				Content: gitlab.Ptr(content),
+				Format:  gitlab.Ptr(gitlab.WikiFormatMarkdown),
+			}, gitlab.WithContext(ctx))
+			return err
+		})
+	case err == nil:
+		err = c.call(ctx, "edit wiki page", c.writePolicy(), func(ctx context.Context) error {
+			_, _, err := c.client.Wikis.EditWikiPage(projectPath, slug, &gitlab.EditWikiPageOptions{
+				Content: gitlab.Ptr(content),
+				Format:  gitlab.Ptr(gitlab.WikiFormatMarkdown),
+			}, gitlab.WithContext(ctx))
+			return err
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save wiki page %s of project %s: %w", slug, projectPath, err)
+	}
+
+	c.logger.Debug("Saved wiki page",
+		zap.String("project_path", projectPath),
+		zap.String("slug", slug),
+		zap.Int("content_size_bytes", len(content)))
+	return nil
+}
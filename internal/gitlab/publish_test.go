@@ -0,0 +1,117 @@
+package gitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/gitlab"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClient_CommitFiles(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var commits []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/acme/site/repository/branches/pages":
+			_, _ = w.Write([]byte(`{"name":"pages"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/acme/new/repository/branches/pages":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Branch Not Found"}`))
+		case r.Method == http.MethodHead && r.URL.Path == "/api/v4/projects/acme/site/repository/files/public/index.html":
+			w.Header().Set("X-Gitlab-File-Name", "index.html")
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/acme/new":
+			_, _ = w.Write([]byte(`{"id":2,"name":"new","default_branch":"main"}`))
+		case r.Method == http.MethodPost:
+			var commit map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&commit))
+			mu.Lock()
+			commits = append(commits, commit)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"id":"abc"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL, "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	files := map[string][]byte{
+		"public/index.html":        []byte("<html></html>"),
+		"public/assets/theme.css":  []byte("body{}"),
+		"public/assets/images/a.b": {0x01},
+	}
+	require.NoError(t, client.CommitFiles(context.Background(), server.URL+"/acme/site", "pages", "Publish", files))
+	require.NoError(t, client.CommitFiles(context.Background(), "acme/new", "pages", "Publish", files))
+
+	require.Len(t, commits, 2)
+	existing := commits[0]
+	assert.Equal(t, "pages", existing["branch"])
+	assert.Nil(t, existing["start_branch"], "the branch exists")
+	actions, _ := existing["actions"].([]interface{})
+	require.Len(t, actions, 3)
+	index, _ := actions[2].(map[string]interface{})
+	assert.Equal(t, "public/index.html", index["file_path"])
+	assert.Equal(t, "update", index["action"], "files on the branch are updated")
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("<html></html>")), index["content"])
+	asset, _ := actions[1].(map[string]interface{})
+	assert.Equal(t, "create", asset["action"])
+
+	created := commits[1]
+	assert.Equal(t, "main", created["start_branch"], "missing branches start from the default branch")
+}
+
+func TestClient_SaveWikiPage(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]string
+		if r.Method != http.MethodGet {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "# Matrix", body["content"])
+			assert.Equal(t, "markdown", body["format"])
+		}
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+body["title"])
+		mu.Unlock()
+
+		if r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/projects/acme%2Fhandbook/wikis/reports%2Fmatrix" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Wiki Page Not Found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"slug":"matrix"}`))
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL, "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, client.SaveWikiPage(context.Background(), "acme/handbook", "reports/matrix", "# Matrix"))
+	require.NoError(t, client.SaveWikiPage(context.Background(), "acme/handbook", "matrix", "# Matrix"))
+
+	assert.Equal(t, []string{
+		"GET /api/v4/projects/acme%2Fhandbook/wikis/reports%2Fmatrix ",
+		"POST /api/v4/projects/acme%2Fhandbook/wikis reports/matrix",
+		"GET /api/v4/projects/acme%2Fhandbook/wikis/matrix ",
+		"PUT /api/v4/projects/acme%2Fhandbook/wikis/matrix ",
+	}, requests)
+}
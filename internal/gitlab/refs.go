@@ -0,0 +1,37 @@
+package gitlab
+
+import (
+	"path"
+)
+
+// WithRefs sets the branch or tag analyzed per repository or group entry keyed by URL,
+// a group entry's ref applies to every project below it. Entries without a ref use the default branch.
+func (c *Client) WithRefs(refs map[string]string) *Client {
+	c.refs = make(map[string]string, len(refs))
+	for entryURL, ref := range refs {
+		if entryPath, err := c.ExtractProjectPath(entryURL); err == nil && ref != "" {
+			c.refs[entryPath] = ref
+		}
+	}
+	return c
+}
+
+// WithRef analyzes ref in every repository instead of its default branch, taking precedence over WithRefs
+func (c *Client) WithRef(ref string) *Client {
+	c.ref = ref
+	return c
+}
+
+// refFor returns the branch or tag analyzed in the project at projectPath
+func (c *Client) refFor(projectPath, defaultBranch string) string {
+	if c.ref != "" {
+		return c.ref
+	}
+	// The project entry wins over the entries of the groups above it
+	for entryPath := projectPath; entryPath != "." && entryPath != "/"; entryPath = path.Dir(entryPath) {
+		if ref, ok := c.refs[entryPath]; ok {
+			return ref
+		}
+	}
+	return defaultBranch
+}
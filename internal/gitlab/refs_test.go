@@ -0,0 +1,72 @@
+package gitlab_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/gitlab"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClient_Refs(t *testing.T) {
+	t.Parallel()
+
+	release := map[string]map[string]string{
+		"release-1.2": {"go.mod": "module example.com/billing // release"},
+	}
+	server := fakegitlab.New("",
+		fakegitlab.Repository{
+			Path:  "acme/platform/billing-service",
+			Files: map[string]string{"go.mod": "module example.com/billing", "go.sum": ""},
+			Refs:  release,
+		},
+		fakegitlab.Repository{
+			Path:  "acme/platform/payments",
+			Files: map[string]string{"go.mod": "module example.com/payments"},
+			Refs:  map[string]map[string]string{"v2.0.0": {"go.mod": "module example.com/payments // v2"}},
+		},
+		fakegitlab.Repository{Path: "acme/web", Files: map[string]string{"package.json": "{}"}},
+	)
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	// The project entry wins over the group entry above it, other projects keep their default branch
+	client.WithRefs(map[string]string{
+		server.RepositoryURL("acme/platform"):                 "v2.0.0",
+		server.RepositoryURL("acme/platform/billing-service"): "release-1.2",
+	})
+
+	repos, err := client.GetRepositoriesList(ctx, server.RepositoryURL("acme"))
+	require.NoError(t, err)
+	refs := make(map[string]string)
+	for _, repo := range repos {
+		refs[repo.Name] = repo.Ref
+	}
+	assert.Equal(t, map[string]string{"billing-service": "release-1.2", "payments": "v2.0.0", "web": ""}, refs)
+
+	billingURL := server.RepositoryURL("acme/platform/billing-service")
+	files, err := client.GetFilesList(ctx, billingURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod"}, files)
+
+	content, err := client.GetFileContent(ctx, billingURL, "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module example.com/billing // release", string(content))
+
+	content, err = client.GetFileContent(ctx, server.RepositoryURL("acme/platform/payments"), "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module example.com/payments // v2", string(content))
+
+	// A global ref overrides the entries, repositories without it fail
+	client.WithRef("release-1.2")
+	_, err = client.GetFilesList(ctx, billingURL)
+	require.NoError(t, err)
+	_, err = client.GetFilesList(ctx, server.RepositoryURL("acme/web"))
+	assert.ErrorContains(t, err, "at release-1.2")
+}
@@ -0,0 +1,107 @@
+package gitlab
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/domain"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// scpLikeURLRegex matches scp-like git remotes such as git@gitlab.com:group/repo.git
+var scpLikeURLRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// GetSubmodules returns the submodules declared in .gitmodules that live on this GitLab instance
+func (c *Client) GetSubmodules(ctx context.Context, repoURL string) ([]domain.Submodule, error) {
+	content, err := c.GetFileContent(ctx, repoURL, ".gitmodules")
+	if err != nil {
+		// Most repositories have no .gitmodules file
+		c.logger.Debug("No .gitmodules found", zap.String("repo_url", repoURL), zap.Error(err))
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var submodules []domain.Submodule
+	for _, submodule := range ParseGitmodules(content, repoURL) {
+		submoduleURL, err := url.Parse(submodule.URL)
+		if err != nil || submoduleURL.Host != baseURL.Host {
+			c.logger.Info("Skipping submodule hosted outside the GitLab instance",
+				zap.String("repo_url", repoURL),
+				zap.String("path", submodule.Path),
+				zap.String("url", submodule.URL))
+			continue
+		}
+		submodules = append(submodules, submodule)
+	}
+
+	return submodules, nil
+}
+
+// ParseGitmodules parses a .gitmodules file and normalizes submodule URLs to https web URLs.
+// Relative URLs are resolved against the parent repository URL.
+func ParseGitmodules(content []byte, parentURL string) []domain.Submodule {
+	var submodules []domain.Submodule
+	var current *domain.Submodule
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[submodule") {
+			submodules = append(submodules, domain.Submodule{})
+			current = &submodules[len(submodules)-1]
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if current == nil || !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "path":
+			current.Path = strings.TrimSpace(value)
+		case "url":
+			current.URL = normalizeSubmoduleURL(strings.TrimSpace(value), parentURL)
+		}
+	}
+
+	return submodules
+}
+
+// normalizeSubmoduleURL converts ssh, scp-like and relative git remotes to https web URLs
+func normalizeSubmoduleURL(remote, parentURL string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	switch {
+	case strings.HasPrefix(remote, "./") || strings.HasPrefix(remote, "../"):
+		base, err := url.Parse(strings.TrimSuffix(parentURL, "/") + "/")
+		if err != nil {
+			return remote
+		}
+		ref, err := url.Parse(remote)
+		if err != nil {
+			return remote
+		}
+		return strings.TrimSuffix(base.ResolveReference(ref).String(), "/")
+	case strings.HasPrefix(remote, "ssh://") || strings.HasPrefix(remote, "git://"):
+		parsed, err := url.Parse(remote)
+		if err != nil {
+			return remote
+		}
+		return "https://" + parsed.Hostname() + parsed.Path
+	case strings.Contains(remote, "://"):
+		return remote
+	}
+
+	if match := scpLikeURLRegex.FindStringSubmatch(remote); match != nil {
+		return "https://" + match[1] + "/" + strings.TrimPrefix(match[2], "/")
+	}
+	return remote
+}
@@ -0,0 +1,42 @@
+package gitlab_test
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/gitlab"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitmodules(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`[submodule "shared"]
+	path = libs/shared
+	url = ../shared.git
+[submodule "proto"]
+	path = proto
+	url = git@gitlab.com:platform/proto.git
+[submodule "ssh"]
+	path = third_party/ssh
+	url = ssh://git@gitlab.com/platform/ssh-lib.git
+[submodule "external"]
+	path = third_party/lib
+	url = https://github.com/acme/lib.git
+`)
+
+	submodules := gitlab.ParseGitmodules(content, "https://gitlab.com/group/app")
+
+	assert.Equal(t, []domain.Submodule{
+		{Path: "libs/shared", URL: "https://gitlab.com/group/shared"},
+		{Path: "proto", URL: "https://gitlab.com/platform/proto"},
+		{Path: "third_party/ssh", URL: "https://gitlab.com/platform/ssh-lib"},
+		{Path: "third_party/lib", URL: "https://github.com/acme/lib"},
+	}, submodules)
+}
+
+func TestClient_ImplementsSubmoduleResolver(t *testing.T) {
+	t.Parallel()
+
+	var _ domain.SubmoduleResolver = (*gitlab.Client)(nil)
+}
@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Node kinds
+const (
+	KindProject  = "project"
+	KindInternal = "internal" // Internal dependency not built by any analyzed project
+	KindExternal = "external"
+)
+
+// Node is a project or a dependency
+type Node struct {
+	ID    string // Stable identifier within the graph, "n1", "n2", ...
+	Label string
+	Kind  string
+}
+
+// Edge is a project using a dependency, or another project when the dependency is built by an analyzed project
+type Edge struct {
+	From    string
+	To      string
+	Version string
+	Project bool // Project-to-project edge of an internal library
+}
+
+// Graph links projects to the dependencies they use
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Build creates the graph of projects and their dependencies. An internal dependency whose name is the module name
+// of another analyzed project becomes an edge between the two projects. With internalOnly, external dependencies
+// are left out so the graph shows which internal libraries are consumed by which services.
+func Build(projects []*domain.Project, internalOnly bool) *Graph {
+	sorted := make([]*domain.Project, len(projects))
+	copy(sorted, projects)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	graph := &Graph{}
+	nodes := make(map[string]string) // Project ID or dependency key -> node ID
+	add := func(key, label, kind string) string {
+		if id, ok := nodes[key]; ok {
+			return id
+		}
+		id := fmt.Sprintf("n%d", len(graph.Nodes)+1)
+		nodes[key] = id
+		graph.Nodes = append(graph.Nodes, Node{ID: id, Label: label, Kind: kind})
+		return id
+	}
+
+	modules := Providers(sorted)
+	for _, project := range sorted {
+		add("project\x00"+project.ID, project.Name, KindProject)
+	}
+
+	for _, project := range sorted {
+		from := nodes["project\x00"+project.ID]
+		dependencies := make([]*domain.Dependency, len(project.Dependencies))
+		copy(dependencies, project.Dependencies)
+		sort.SliceStable(dependencies, func(i, j int) bool { return dependencies[i].Name < dependencies[j].Name })
+
+		for _, dep := range dependencies {
+			if internalOnly && !dep.IsInternal {
+				continue
+			}
+			if provider := Provider(modules, project, dep); provider != nil {
+				to := nodes["project\x00"+provider.ID]
+				graph.Edges = append(graph.Edges, Edge{From: from, To: to, Version: dep.Version, Project: true})
+				continue
+			}
+			kind := KindExternal
+			if dep.IsInternal {
+				kind = KindInternal
+			}
+			to := add("dependency\x00"+dep.Ecosystem+"\x00"+dep.Name, dep.Name, kind)
+			graph.Edges = append(graph.Edges, Edge{From: from, To: to, Version: dep.Version})
+		}
+	}
+	return graph
+}
+
+// Providers maps module names to the analyzed project building them,
+// the project with the lowest ID when several declare the same module
+func Providers(projects []*domain.Project) map[string]*domain.Project {
+	providers := make(map[string]*domain.Project)
+	for _, project := range projects {
+		if project.ModuleName == "" {
+			continue
+		}
+		if existing, ok := providers[project.ModuleName]; !ok || project.ID < existing.ID {
+			providers[project.ModuleName] = project
+		}
+	}
+	return providers
+}
+
+// Provider returns the analyzed project building an internal dependency of project,
+// nil for external dependencies, dependencies no analyzed project builds and the project itself
+func Provider(providers map[string]*domain.Project, project *domain.Project, dep *domain.Dependency) *domain.Project {
+	provider, ok := providers[dep.Name]
+	if !ok || !dep.IsInternal || provider.ID == project.ID {
+		return nil
+	}
+	return provider
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", style=filled];\n")
+	for _, node := range g.Nodes {
+		shape, color := "ellipse", "#f3f4f6"
+		switch node.Kind {
+		case KindProject:
+			shape, color = "box", "#dbeafe"
+		case KindInternal:
+			color = "#dcfce7"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s, fillcolor=%q];\n", node.ID, dotString(node.Label), shape, color)
+	}
+	for _, edge := range g.Edges {
+		attributes := "label=" + dotString(edge.Version)
+		if edge.Project {
+			attributes += ", color=\"#15803d\", penwidth=2"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", edge.From, edge.To, attributes)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		if node.Kind == KindProject {
+			fmt.Fprintf(&b, "  %s[%s]:::%s\n", node.ID, mermaidString(node.Label), node.Kind)
+		} else {
+			fmt.Fprintf(&b, "  %s(%s):::%s\n", node.ID, mermaidString(node.Label), node.Kind)
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Project {
+			arrow = "==>"
+		}
+		if edge.Version == "" {
+			fmt.Fprintf(&b, "  %s %s %s\n", edge.From, arrow, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", edge.From, arrow, mermaidString(edge.Version), edge.To)
+		}
+	}
+	b.WriteString("  classDef project fill:#dbeafe,stroke:#1d4ed8\n")
+	b.WriteString("  classDef internal fill:#dcfce7,stroke:#15803d\n")
+	b.WriteString("  classDef external fill:#f3f4f6,stroke:#6b7280\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotString quotes a DOT identifier
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
+
+// mermaidString quotes a Mermaid label, double quotes are written as entity codes
+func mermaidString(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
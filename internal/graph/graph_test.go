@@ -0,0 +1,89 @@
+package graph_test
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/graph"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphProjects() []*domain.Project {
+	return []*domain.Project{
+		{ID: "repo-2-go-billing", Name: "billing", ModuleName: "gitlab.company.com/billing", Dependencies: []*domain.Dependency{
+			{Name: "gitlab.company.com/auth", Version: "v1.2.0", Ecosystem: "go-modules", IsInternal: true},
+			{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go-modules"},
+		}},
+		{ID: "repo-1-go-auth", Name: "auth", ModuleName: "gitlab.company.com/auth", Dependencies: []*domain.Dependency{
+			{Name: "gitlab.company.com/crypto", Version: "v0.3.0", Ecosystem: "go-modules", IsInternal: true},
+			{Name: "github.com/gin-gonic/gin", Version: "v1.9.0", Ecosystem: "go-modules"},
+		}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	g := graph.Build(graphProjects(), false)
+
+	assert.Equal(t, []graph.Node{
+		{ID: "n1", Label: "auth", Kind: graph.KindProject},
+		{ID: "n2", Label: "billing", Kind: graph.KindProject},
+		{ID: "n3", Label: "github.com/gin-gonic/gin", Kind: graph.KindExternal},
+		{ID: "n4", Label: "gitlab.company.com/crypto", Kind: graph.KindInternal},
+	}, g.Nodes)
+	assert.Contains(t, g.Edges, graph.Edge{From: "n2", To: "n1", Version: "v1.2.0", Project: true},
+		"internal dependencies built by an analyzed project link the projects")
+	assert.Contains(t, g.Edges, graph.Edge{From: "n1", To: "n3", Version: "v1.9.0"})
+	assert.Len(t, g.Edges, 4)
+
+	internal := graph.Build(graphProjects(), true)
+	assert.Len(t, internal.Nodes, 3, "external dependencies are left out")
+	assert.Len(t, internal.Edges, 2)
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	t.Parallel()
+
+	projects := graphProjects()
+	projects[0].Name = `billing "v2"`
+	var out strings.Builder
+	require.NoError(t, graph.Build(projects, true).WriteDOT(&out))
+
+	dot := out.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph dependencies {\n"))
+	assert.Contains(t, dot, `n2 [label="billing \"v2\"", shape=box, fillcolor="#dbeafe"];`)
+	assert.Contains(t, dot, `n2 -> n1 [label="v1.2.0", color="#15803d", penwidth=2];`)
+	assert.Contains(t, dot, `n1 -> n3 [label="v0.3.0"];`)
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	require.NoError(t, graph.Build(graphProjects(), true).WriteMermaid(&out))
+
+	mermaid := out.String()
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Contains(t, mermaid, `n1["auth"]:::project`)
+	assert.Contains(t, mermaid, `n3("gitlab.company.com/crypto"):::internal`)
+	assert.Contains(t, mermaid, `n2 ==>|"v1.2.0"| n1`)
+	assert.Contains(t, mermaid, "classDef internal")
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	projects := graphProjects()
+	billing, auth := projects[0], projects[1]
+	providers := graph.Providers(projects)
+
+	assert.Same(t, auth, graph.Provider(providers, billing, billing.Dependencies[0]))
+	assert.Nil(t, graph.Provider(providers, billing, billing.Dependencies[1]), "external dependencies have no provider")
+	assert.Nil(t, graph.Provider(providers, auth, &domain.Dependency{Name: auth.ModuleName, IsInternal: true}),
+		"a project does not provide its own dependencies")
+	assert.Nil(t, graph.Provider(providers, billing, &domain.Dependency{Name: auth.ModuleName}),
+		"only internal dependencies link projects")
+}
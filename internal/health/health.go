@@ -0,0 +1,123 @@
+package health
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"math"
+)
+
+// Weights controls how much each component contributes to the composite score
+type Weights struct {
+	Drift           float64
+	Vulnerabilities float64
+	Deprecated      float64
+	Pinning         float64
+	Lockfile        float64
+}
+
+// DefaultWeights returns the weights used when none are configured
+func DefaultWeights() Weights {
+	return Weights{
+		Drift:           3,
+		Vulnerabilities: 4,
+		Deprecated:      2,
+		Pinning:         1,
+		Lockfile:        1,
+	}
+}
+
+func (w Weights) total() float64 {
+	return w.Drift + w.Vulnerabilities + w.Deprecated + w.Pinning + w.Lockfile
+}
+
+// Scorer computes per-project health scores
+type Scorer struct {
+	weights     Weights
+	prereleases version.PrereleasePolicy
+}
+
+// NewScorer creates a new health scorer, falling back to default weights when all are zero
+func NewScorer(weights Weights) *Scorer {
+	if weights.total() <= 0 {
+		weights = DefaultWeights()
+	}
+	return &Scorer{weights: weights}
+}
+
+// WithPrereleasePolicy controls whether pre-release versions may count as the highest version when measuring drift
+func (s *Scorer) WithPrereleasePolicy(policy version.PrereleasePolicy) *Scorer {
+	s.prereleases = policy
+	return s
+}
+
+// ScoreProjects sets the Health field of every project.
+// Drift is measured against the highest version of each dependency across all given projects.
+func (s *Scorer) ScoreProjects(projects []*domain.Project) {
+	versions := portfolioVersions(projects)
+	for _, project := range projects {
+		maxVersions := make(map[string]string, len(project.Dependencies))
+		for _, dep := range project.Dependencies {
+			maxVersions[dep.Name] = version.MaxFor(versions[dep.Name], dep.Version, s.prereleases)
+		}
+		project.Health = s.Score(project, maxVersions)
+	}
+}
+
+// Score computes the health score of a single project
+func (s *Scorer) Score(project *domain.Project, maxVersions map[string]string) *domain.HealthScore {
+	score := &domain.HealthScore{}
+
+	if !project.HasLockfile {
+		score.Lockfile = 1
+	}
+
+	if total := len(project.Dependencies); total > 0 {
+		var outdated, vulnerable, deprecated, floating int
+		for _, dep := range project.Dependencies {
+			if version.IsOutdated(dep.Version, maxVersions[dep.Name]) {
+				outdated++
+			}
+			if dep.VulnCount > 0 {
+				vulnerable++
+			}
+			if dep.IsDeprecated || dep.IsEndOfLife {
+				deprecated++
+			}
+			if dep.IsFloating {
+				floating++
+			}
+		}
+
+		score.Drift = ratio(outdated, total)
+		score.Vulnerabilities = ratio(vulnerable, total)
+		score.Deprecated = ratio(deprecated, total)
+		score.Pinning = ratio(floating, total)
+	}
+
+	penalty := s.weights.Drift*score.Drift +
+		s.weights.Vulnerabilities*score.Vulnerabilities +
+		s.weights.Deprecated*score.Deprecated +
+		s.weights.Pinning*score.Pinning +
+		s.weights.Lockfile*score.Lockfile
+
+	score.Score = math.Round(100*(1-penalty/s.weights.total())*10) / 10
+
+	return score
+}
+
+// portfolioVersions collects the versions of every dependency across projects
+func portfolioVersions(projects []*domain.Project) map[string][]string {
+	versions := make(map[string][]string)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Version != "" {
+				versions[dep.Name] = append(versions[dep.Name], dep.Version)
+			}
+		}
+	}
+	return versions
+}
+
+func ratio(part, total int) float64 {
+	return float64(part) / float64(total)
+}
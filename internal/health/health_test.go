@@ -0,0 +1,98 @@
+package health_test
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/health"
+	"di-matrix-cli/internal/version"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreProjects(t *testing.T) {
+	t.Parallel()
+
+	healthy := &domain.Project{
+		ID:          "healthy",
+		HasLockfile: true,
+		Dependencies: []*domain.Dependency{
+			{Name: "express", Version: "4.19.0"},
+			{Name: "react", Version: "18.3.0"},
+		},
+	}
+	unhealthy := &domain.Project{
+		ID:          "unhealthy",
+		HasLockfile: false,
+		Dependencies: []*domain.Dependency{
+			{Name: "express", Version: "4.18.0", VulnCount: 2},
+			{Name: "react", Version: "18.3.0", IsFloating: true, IsDeprecated: true},
+		},
+	}
+
+	scorer := health.NewScorer(health.DefaultWeights())
+	scorer.ScoreProjects([]*domain.Project{healthy, unhealthy})
+
+	require.NotNil(t, healthy.Health)
+	assert.InDelta(t, 100.0, healthy.Health.Score, 0.001)
+
+	require.NotNil(t, unhealthy.Health)
+	assert.InDelta(t, 0.5, unhealthy.Health.Drift, 0.001)
+	assert.InDelta(t, 0.5, unhealthy.Health.Vulnerabilities, 0.001)
+	assert.InDelta(t, 0.5, unhealthy.Health.Deprecated, 0.001)
+	assert.InDelta(t, 0.5, unhealthy.Health.Pinning, 0.001)
+	assert.InDelta(t, 1.0, unhealthy.Health.Lockfile, 0.001)
+	// penalty = (3*0.5 + 4*0.5 + 2*0.5 + 1*0.5 + 1*1) / 11 = 6/11
+	assert.InDelta(t, 45.5, unhealthy.Health.Score, 0.001)
+}
+
+func TestScoreProjects_PrereleasePolicy(t *testing.T) {
+	t.Parallel()
+
+	newProjects := func() (*domain.Project, *domain.Project) {
+		stable := &domain.Project{
+			ID: "stable", HasLockfile: true,
+			Dependencies: []*domain.Dependency{{Name: "django", Version: "4.2.0"}},
+		}
+		candidate := &domain.Project{
+			ID: "candidate", HasLockfile: true,
+			Dependencies: []*domain.Dependency{{Name: "django", Version: "5.0.0-rc.1"}},
+		}
+		return stable, candidate
+	}
+
+	stable, candidate := newProjects()
+	health.NewScorer(health.DefaultWeights()).
+		WithPrereleasePolicy(version.PrereleaseAlways).
+		ScoreProjects([]*domain.Project{stable, candidate})
+	assert.InDelta(t, 1.0, stable.Health.Drift, 0.001, "Stable projects drift behind release candidates")
+
+	stable, candidate = newProjects()
+	health.NewScorer(health.DefaultWeights()).
+		WithPrereleasePolicy(version.PrereleaseInUse).
+		ScoreProjects([]*domain.Project{stable, candidate})
+	assert.InDelta(t, 0.0, stable.Health.Drift, 0.001)
+	assert.InDelta(t, 0.0, candidate.Health.Drift, 0.001)
+}
+
+func TestNewScorer_ZeroWeightsUseDefaults(t *testing.T) {
+	t.Parallel()
+
+	project := &domain.Project{HasLockfile: false}
+	score := health.NewScorer(health.Weights{}).Score(project, nil)
+
+	// Only the lockfile component applies: penalty = 1/11
+	assert.InDelta(t, 90.9, score.Score, 0.001)
+}
+
+func TestScore_CustomWeights(t *testing.T) {
+	t.Parallel()
+
+	project := &domain.Project{
+		HasLockfile:  false,
+		Dependencies: []*domain.Dependency{{Name: "requests", Version: "2.31.0"}},
+	}
+	score := health.NewScorer(health.Weights{Lockfile: 1, Drift: 1}).Score(project, nil)
+
+	assert.InDelta(t, 50.0, score.Score, 0.001)
+}
@@ -0,0 +1,182 @@
+package local
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Provider reads repositories from directories on disk, e.g. checkouts prepared by a CI job.
+// A directory is a repository, unless it is no git checkout itself but contains checkouts:
+// then it is expanded like a group to the checkouts directly below it.
+type Provider struct {
+	logger *zap.Logger
+}
+
+// NewProvider creates a new local directory provider
+func NewProvider(logger *zap.Logger) *Provider {
+	return &Provider{logger: logger}
+}
+
+// CheckPermissions has nothing to verify, unreadable directories fail when they are listed
+func (p *Provider) CheckPermissions(_ context.Context) error {
+	return nil
+}
+
+// GetRepositoriesList returns the repository of a directory, or the checkouts it contains
+func (p *Provider) GetRepositoriesList(_ context.Context, repoURL string) ([]*domain.Repository, error) {
+	dir, err := directory(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if isCheckout(dir) {
+		return []*domain.Repository{repository(dir)}, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var repositories []*domain.Repository
+	for _, entry := range entries {
+		if entry.IsDir() && isCheckout(filepath.Join(dir, entry.Name())) {
+			repositories = append(repositories, repository(filepath.Join(dir, entry.Name())))
+		}
+	}
+	if len(repositories) == 0 {
+		// A plain directory without checkouts is analyzed as it is
+		return []*domain.Repository{repository(dir)}, nil
+	}
+
+	p.logger.Debug("Expanded directory to checkouts",
+		zap.String("dir", dir),
+		zap.Int("repositories", len(repositories)))
+	return repositories, nil
+}
+
+// GetFilesList returns the slash separated paths of the files in the repository directory.
+// Git metadata and symbolic links are skipped, so linked manifests are not counted twice.
+func (p *Provider) GetFilesList(ctx context.Context, repoURL string) ([]string, error) {
+	dir, err := directory(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relative))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", dir, err)
+	}
+
+	p.logger.Debug("Listed repository files", zap.String("dir", dir), zap.Int("files", len(files)))
+	return files, nil
+}
+
+// GetFileContent returns the content of a file within the repository directory
+func (p *Provider) GetFileContent(_ context.Context, repoURL string, filePath string) ([]byte, error) {
+	dir, err := directory(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.FromSlash(filePath)
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("file %s is outside of the repository %s", filePath, dir)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return content, nil
+}
+
+// directory resolves a repository URL, a path or a file:// URL, to an absolute directory
+func directory(repoURL string) (string, error) {
+	dir := repoURL
+	if strings.HasPrefix(repoURL, "file://") {
+		parsed, err := url.Parse(repoURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid file URL %s: %w", repoURL, err)
+		}
+		dir = parsed.Path
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory %s: %w", repoURL, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open directory %s: %w", repoURL, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", repoURL)
+	}
+	return dir, nil
+}
+
+// isCheckout reports whether dir is a git working tree (.git is a directory, or a file in worktrees and submodules)
+func isCheckout(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// repository describes the repository of an absolute directory
+func repository(dir string) *domain.Repository {
+	// Project IDs are derived from repository IDs, keep them stable across runs
+	hash := fnv.New32a()
+	hash.Write([]byte(dir))
+
+	return &domain.Repository{
+		ID:            int(hash.Sum32() & 0x7fffffff),
+		Name:          filepath.Base(dir),
+		URL:           dir,
+		DefaultBranch: currentBranch(dir),
+		WebURL:        (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String(),
+	}
+}
+
+// currentBranch returns the branch checked out in dir, "" when it is not a checkout or HEAD is detached
+func currentBranch(dir string) string {
+	head, err := os.ReadFile(filepath.Join(dir, ".git", "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+	if !ok {
+		return ""
+	}
+	return ref
+}
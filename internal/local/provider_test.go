@@ -0,0 +1,84 @@
+package local_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/local"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// writeFile creates a file with its parent directories
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// checkout creates a directory that looks like a git working tree on branch
+func checkout(t *testing.T, dir string, branch string) {
+	t.Helper()
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/"+branch+"\n")
+}
+
+func TestProvider_GetRepositoriesList(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	checkout(t, filepath.Join(root, "billing"), "main")
+	checkout(t, filepath.Join(root, "web"), "develop")
+	writeFile(t, filepath.Join(root, "notes", "README.md"), "not a checkout")
+
+	provider := local.NewProvider(zap.NewNop())
+
+	// A directory of checkouts expands like a group
+	repos, err := provider.GetRepositoriesList(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "billing", repos[0].Name)
+	assert.Equal(t, filepath.Join(root, "billing"), repos[0].URL)
+	assert.Equal(t, "main", repos[0].DefaultBranch)
+	assert.Equal(t, "develop", repos[1].DefaultBranch)
+	assert.NotEqual(t, repos[0].ID, repos[1].ID)
+
+	// A checkout is a single repository, also when given as a file:// URL
+	single, err := provider.GetRepositoriesList(context.Background(), "file://"+filepath.ToSlash(repos[0].URL))
+	require.NoError(t, err)
+	require.Len(t, single, 1)
+	assert.Equal(t, repos[0].ID, single[0].ID, "IDs are stable")
+
+	// A plain directory is analyzed as it is
+	plain, err := provider.GetRepositoriesList(context.Background(), filepath.Join(root, "notes"))
+	require.NoError(t, err)
+	require.Len(t, plain, 1)
+	assert.Empty(t, plain[0].DefaultBranch)
+
+	_, err = provider.GetRepositoriesList(context.Background(), filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestProvider_Files(t *testing.T) {
+	t.Parallel()
+	repo := t.TempDir()
+	checkout(t, repo, "main")
+	writeFile(t, filepath.Join(repo, "go.mod"), "module example.com/svc\n")
+	writeFile(t, filepath.Join(repo, "web", "package.json"), `{"name": "web"}`)
+	require.NoError(t, os.Symlink(filepath.Join(repo, "go.mod"), filepath.Join(repo, "linked.mod")))
+
+	provider := local.NewProvider(zap.NewNop())
+
+	files, err := provider.GetFilesList(context.Background(), repo)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go.mod", "web/package.json"}, files,
+		"Git metadata and symbolic links are skipped")
+
+	content, err := provider.GetFileContent(context.Background(), repo, "web/package.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "web"}`, string(content))
+
+	_, err = provider.GetFileContent(context.Background(), repo, "../secrets.env")
+	assert.ErrorContains(t, err, "outside of the repository")
+}
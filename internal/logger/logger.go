@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"sync"
 
@@ -10,10 +11,29 @@ import (
 
 type Logger struct {
 	atomicLevel zap.AtomicLevel
+	output      *output
 	logger      *zap.Logger
 	mu          sync.RWMutex
 }
 
+// output forwards log entries to a destination that can be replaced after loggers were handed out
+type output struct {
+	mu sync.RWMutex
+	w  zapcore.WriteSyncer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.w.Write(p)
+}
+
+func (o *output) Sync() error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.w.Sync()
+}
+
 var (
 	instance *Logger   //nolint:gochecknoglobals // Singleton pattern for logger
 	once     sync.Once //nolint:gochecknoglobals // Singleton pattern for logger
@@ -23,6 +43,7 @@ func GetLogger() *zap.Logger {
 	once.Do(func() {
 		instance = &Logger{
 			atomicLevel: zap.NewAtomicLevelAt(zap.InfoLevel),
+			output:      &output{w: zapcore.AddSync(os.Stdout)},
 		}
 
 		encoderCfg := zap.NewDevelopmentEncoderConfig()
@@ -33,7 +54,7 @@ func GetLogger() *zap.Logger {
 
 		core := zapcore.NewCore(
 			zapcore.NewConsoleEncoder(encoderCfg),
-			zapcore.AddSync(os.Stdout),
+			instance.output,
 			instance.atomicLevel,
 		)
 
@@ -53,3 +74,12 @@ func SetLevel(level zapcore.Level) {
 	defer instance.mu.Unlock()
 	instance.atomicLevel.SetLevel(level)
 }
+
+// SetOutput sends log entries to w instead of stdout, also for loggers returned earlier
+func SetOutput(w io.Writer) {
+	GetLogger()
+
+	instance.output.mu.Lock()
+	defer instance.output.mu.Unlock()
+	instance.output.w = zapcore.AddSync(w)
+}
@@ -1,7 +1,9 @@
 package logger_test
 
 import (
+	"bytes"
 	"di-matrix-cli/internal/logger"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -98,3 +100,15 @@ func TestLoggerLevelChanges(t *testing.T) {
 	log.Warn("Warning message (should be filtered)")
 	log.Error("Error message (should work)")
 }
+
+//nolint:paralleltest // Redirects the shared logger
+func TestSetOutput(t *testing.T) {
+	log := logger.GetLogger()
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(os.Stdout)
+
+	log.Error("Redirected message")
+	assert.Contains(t, buf.String(), "Redirected message", "Loggers returned earlier follow the new output")
+}
@@ -0,0 +1,162 @@
+package maven
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// maxParentDepth bounds parent POM chains, guarding against cycles
+const maxParentDepth = 10
+
+// index resolves managed versions across the pom.xml files of one repository
+type index struct {
+	byPath       map[string]*pom
+	byCoordinate map[string]string // "groupid:artifactid" -> repository path
+	remote       *remoteRepositories
+}
+
+// newIndex parses the pom.xml files of a repository keyed by path, skipping unparsable ones
+func newIndex(poms map[string][]byte, remote *remoteRepositories) *index {
+	idx := &index{
+		byPath:       make(map[string]*pom),
+		byCoordinate: make(map[string]string),
+		remote:       remote,
+	}
+	for filePath, content := range poms {
+		project, err := parsePOM(content)
+		if err != nil {
+			continue
+		}
+		idx.byPath[filePath] = project
+		idx.byCoordinate[coordinate(project.groupID(), project.ArtifactID)] = filePath
+	}
+	return idx
+}
+
+// effectiveModel returns the managed versions ("groupid:artifactid" -> version) and properties of the pom at filePath,
+// including those inherited from parent POMs and imported BOMs
+func (idx *index) effectiveModel(ctx context.Context, filePath string) (map[string]string, map[string]string) {
+	project, ok := idx.byPath[filePath]
+	if !ok {
+		return map[string]string{}, map[string]string{}
+	}
+	return idx.resolve(ctx, project, filePath, make(map[string]bool))
+}
+
+func (idx *index) resolve(
+	ctx context.Context,
+	project *pom,
+	filePath string,
+	seen map[string]bool,
+) (map[string]string, map[string]string) {
+	// Lineage from the project up to its top-most resolvable parent
+	lineage := []*pom{project}
+	current, currentPath := project, filePath
+	for range maxParentDepth {
+		parent, parentPath := idx.parentOf(ctx, current, currentPath)
+		if parent == nil {
+			break
+		}
+		lineage = append(lineage, parent)
+		current, currentPath = parent, parentPath
+	}
+
+	props := inheritedProperties(lineage)
+
+	// Explicitly managed versions win over imported BOMs, children over parents
+	managed := make(map[string]string)
+	var imports []pomDependency
+	for _, model := range lineage {
+		for _, dep := range model.DependencyManagement.Dependencies {
+			if dep.isBOMImport() {
+				imports = append(imports, dep)
+				continue
+			}
+			key := coordinate(interpolate(dep.GroupID, props), interpolate(dep.ArtifactID, props))
+			if _, exists := managed[key]; !exists {
+				managed[key] = interpolate(dep.Version, props)
+			}
+		}
+	}
+
+	for _, bom := range imports {
+		groupID := interpolate(bom.GroupID, props)
+		artifactID := interpolate(bom.ArtifactID, props)
+		bomVersion := interpolate(bom.Version, props)
+		key := coordinate(groupID, artifactID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		bomProject, bomPath := idx.lookup(ctx, groupID, artifactID, bomVersion)
+		if bomProject == nil {
+			continue
+		}
+		bomManaged, _ := idx.resolve(ctx, bomProject, bomPath, seen)
+		for name, version := range bomManaged {
+			if _, exists := managed[name]; !exists {
+				managed[name] = version
+			}
+		}
+	}
+
+	return managed, props
+}
+
+// inheritedProperties merges the properties of a lineage, children overriding parents,
+// together with the built-in project.* properties of its first element
+func inheritedProperties(lineage []*pom) map[string]string {
+	props := make(map[string]string)
+	for i := len(lineage) - 1; i >= 0; i-- {
+		for name, value := range lineage[i].Properties {
+			props[name] = value
+		}
+	}
+
+	project := lineage[0]
+	props["project.groupId"] = project.groupID()
+	props["project.artifactId"] = project.ArtifactID
+	props["project.version"] = project.version()
+	props["project.parent.groupId"] = project.Parent.GroupID
+	props["project.parent.version"] = project.Parent.Version
+
+	return props
+}
+
+// parentOf finds the parent POM through its relativePath, the repository index or remote repositories
+func (idx *index) parentOf(ctx context.Context, project *pom, filePath string) (*pom, string) {
+	if project.Parent.ArtifactID == "" {
+		return nil, ""
+	}
+
+	// relativePath defaults to ../pom.xml, an empty element disables the lookup
+	relativePath := "../pom.xml"
+	if project.Parent.RelativePath != nil {
+		relativePath = strings.TrimSpace(*project.Parent.RelativePath)
+	}
+	if relativePath != "" && filePath != "" {
+		if !strings.HasSuffix(relativePath, ".xml") {
+			relativePath = path.Join(relativePath, "pom.xml")
+		}
+		parentPath := path.Join(path.Dir(filePath), relativePath)
+		if parent, ok := idx.byPath[parentPath]; ok &&
+			coordinate(parent.groupID(), parent.ArtifactID) == coordinate(project.Parent.GroupID, project.Parent.ArtifactID) {
+			return parent, parentPath
+		}
+	}
+
+	return idx.lookup(ctx, project.Parent.GroupID, project.Parent.ArtifactID, project.Parent.Version)
+}
+
+// lookup finds a POM by coordinates in the repository, falling back to remote repositories
+func (idx *index) lookup(ctx context.Context, groupID, artifactID, version string) (*pom, string) {
+	if filePath, ok := idx.byCoordinate[coordinate(groupID, artifactID)]; ok {
+		return idx.byPath[filePath], filePath
+	}
+	if idx.remote == nil || version == "" || strings.Contains(version, "${") {
+		return nil, ""
+	}
+	return idx.remote.fetch(ctx, groupID, artifactID, version), ""
+}
@@ -0,0 +1,117 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// maxInterpolationDepth bounds nested ${...} property references
+const maxInterpolationDepth = 10
+
+// pom is the subset of a Maven project model needed to resolve managed versions
+type pom struct {
+	GroupID    string     `xml:"groupId"`
+	ArtifactID string     `xml:"artifactId"`
+	Version    string     `xml:"version"`
+	Parent     pomParent  `xml:"parent"`
+	Properties properties `xml:"properties"`
+
+	DependencyManagement struct {
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+}
+
+type pomParent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Scope      string `xml:"scope"`
+}
+
+// properties collects arbitrary <properties> children
+type properties map[string]string
+
+func (p *properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(properties)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &element); err != nil {
+				return err
+			}
+			(*p)[element.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// parsePOM decodes a pom.xml document
+func parsePOM(content []byte) (*pom, error) {
+	var project pom
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse pom.xml: %w", err)
+	}
+	return &project, nil
+}
+
+// groupID returns the project groupId, inherited from the parent when omitted
+func (p *pom) groupID() string {
+	if p.GroupID != "" {
+		return p.GroupID
+	}
+	return p.Parent.GroupID
+}
+
+// version returns the project version, inherited from the parent when omitted
+func (p *pom) version() string {
+	if p.Version != "" {
+		return p.Version
+	}
+	return p.Parent.Version
+}
+
+// isBOMImport reports whether a managed dependency imports another POM's dependencyManagement
+func (d pomDependency) isBOMImport() bool {
+	return d.Scope == "import" && d.Type == "pom"
+}
+
+// coordinate returns the lowercased "groupId:artifactId" key used for dependency names
+func coordinate(groupID, artifactID string) string {
+	return strings.ToLower(groupID + ":" + artifactID)
+}
+
+// interpolate expands ${...} references using the given properties, leaving unknown references in place
+func interpolate(value string, props map[string]string) string {
+	for range maxInterpolationDepth {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			return value
+		}
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			return value
+		}
+		name := value[start+2 : start+end]
+		replacement, ok := props[name]
+		if !ok {
+			return value
+		}
+		value = value[:start] + replacement + value[start+end+1:]
+	}
+	return value
+}
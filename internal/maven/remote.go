@@ -0,0 +1,142 @@
+package maven
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/retry"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultRetryPolicy returns the policy used for POM downloads unless configured otherwise
+func DefaultRetryPolicy() retry.Policy {
+	return retry.Policy{Retries: 2, Backoff: 500 * time.Millisecond, Timeout: 15 * time.Second}
+}
+
+// cacheNamespace groups downloaded POMs in the on-disk cache
+const cacheNamespace = "maven"
+
+// remoteRepositories downloads parent POMs and BOMs from Maven repositories, caching results including misses.
+// Downloads are kept in the on-disk store when set, offline lookups are served from it only.
+type remoteRepositories struct {
+	urls    []string
+	client  *http.Client
+	policy  retry.Policy
+	store   *cache.Store
+	offline bool
+
+	downloads     singleflight.Group // Concurrent lookups of one POM share its download
+	mu            sync.Mutex         // Guards cache and offlineMisses, never held during a download
+	cache         map[string]*pom
+	offlineMisses int // Lookups that would have needed the network
+}
+
+func newRemoteRepositories(urls []string, store *cache.Store, offline bool, policy retry.Policy) *remoteRepositories {
+	return &remoteRepositories{
+		urls:    urls,
+		client:  &http.Client{},
+		policy:  policy,
+		store:   store,
+		offline: offline,
+		cache:   make(map[string]*pom),
+	}
+}
+
+// misses returns how many lookups failed because the network was not allowed
+func (r *remoteRepositories) misses() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.offlineMisses
+}
+
+// fetch returns the POM for the coordinates from the first repository that has it, or nil
+func (r *remoteRepositories) fetch(ctx context.Context, groupID, artifactID, version string) *pom {
+	artifactPath := fmt.Sprintf("%s/%s/%s/%s-%s.pom",
+		strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID, version)
+
+	r.mu.Lock()
+	project, cached := r.cache[artifactPath]
+	r.mu.Unlock()
+
+	if !cached {
+		loaded, _, _ := r.downloads.Do(artifactPath, func() (any, error) {
+			project := r.load(ctx, artifactPath)
+			r.mu.Lock()
+			r.cache[artifactPath] = project
+			r.mu.Unlock()
+			return project, nil
+		})
+		project = loaded.(*pom)
+	}
+	if project == nil && r.offline {
+		r.mu.Lock()
+		r.offlineMisses++
+		r.mu.Unlock()
+	}
+
+	return project
+}
+
+// load reads the POM from the on-disk cache or, when online, downloads and caches it
+func (r *remoteRepositories) load(ctx context.Context, artifactPath string) *pom {
+	if r.store != nil {
+		if content, ok := r.store.Get(cacheNamespace, artifactPath); ok {
+			if project, err := parsePOM(content); err == nil {
+				return project
+			}
+		}
+	}
+	if r.offline {
+		return nil
+	}
+
+	for _, repositoryURL := range r.urls {
+		content, err := r.download(ctx, strings.TrimSuffix(repositoryURL, "/")+"/"+artifactPath)
+		if err != nil {
+			continue
+		}
+		project, err := parsePOM(content)
+		if err != nil {
+			continue
+		}
+		if r.store != nil {
+			// Released POMs never change, a failed write only costs a download next time
+			_ = r.store.Put(cacheNamespace, artifactPath, content)
+		}
+		return project
+	}
+	return nil
+}
+
+// download fetches url under the retry policy
+func (r *remoteRepositories) download(ctx context.Context, url string) ([]byte, error) {
+	var content []byte
+	err := r.policy.Do(ctx, retry.IsTransientHTTP, func(ctx context.Context) (err error) {
+		content, err = r.get(ctx, url)
+		return err
+	})
+	return content, err
+}
+
+func (r *remoteRepositories) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &retry.StatusError{Status: resp.StatusCode, URL: url}
+	}
+	return io.ReadAll(resp.Body)
+}
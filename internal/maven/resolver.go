@@ -0,0 +1,167 @@
+package maven
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/retry"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Resolver fills Java dependency versions managed by parent POMs and imported BOMs,
+// which a single pom.xml does not carry
+type Resolver struct {
+	logger  *zap.Logger
+	urls    []string // Empty keeps resolution within the analyzed repositories
+	store   *cache.Store
+	offline bool
+	policy  retry.Policy
+	remote  *remoteRepositories // Created on first use, keeps downloads across calls
+}
+
+// NewResolver creates a resolver that only uses POMs found in the analyzed repositories
+func NewResolver(logger *zap.Logger) *Resolver {
+	return &Resolver{logger: logger, policy: DefaultRetryPolicy()}
+}
+
+// WithRemoteRepositories fetches parent POMs and BOMs missing from the repository
+// from these Maven repositories, an empty list keeps resolution offline
+func (r *Resolver) WithRemoteRepositories(urls []string) *Resolver {
+	r.urls = urls
+	r.remote = nil
+	return r
+}
+
+// WithCache keeps downloaded POMs in store so later and offline runs can reuse them
+func (r *Resolver) WithCache(store *cache.Store) *Resolver {
+	r.store = store
+	r.remote = nil
+	return r
+}
+
+// WithOffline serves remote POMs from the cache only, dependencies left unresolved by a cache miss are marked stale
+func (r *Resolver) WithOffline(offline bool) *Resolver {
+	r.offline = offline
+	r.remote = nil
+	return r
+}
+
+// WithRetryPolicy sets how remote POM downloads are retried, a missing POM is never retried
+func (r *Resolver) WithRetryPolicy(policy retry.Policy) *Resolver {
+	r.policy = policy
+	r.remote = nil
+	return r
+}
+
+// ResolveManagedVersions fills empty and property-based versions of Java dependencies
+// and returns the number of dependencies resolved
+func (r *Resolver) ResolveManagedVersions(ctx context.Context, projects []*domain.Project) int {
+	// POMs of one repository can reference each other as parents and BOMs
+	pomsByRepository := make(map[string]map[string][]byte)
+	for _, project := range projects {
+		for _, file := range pomFiles(project) {
+			if pomsByRepository[project.Repository.URL] == nil {
+				pomsByRepository[project.Repository.URL] = make(map[string][]byte)
+			}
+			pomsByRepository[project.Repository.URL][file.Path] = file.Content
+		}
+	}
+
+	if r.remote == nil && len(r.urls) > 0 {
+		r.remote = newRemoteRepositories(r.urls, r.store, r.offline, r.policy)
+	}
+	remote := r.remote
+
+	indexes := make(map[string]*index, len(pomsByRepository))
+	resolved, stale := 0, 0
+	for _, project := range projects {
+		files := pomFiles(project)
+		if len(files) == 0 {
+			continue
+		}
+		idx, ok := indexes[project.Repository.URL]
+		if !ok {
+			idx = newIndex(pomsByRepository[project.Repository.URL], remote)
+			indexes[project.Repository.URL] = idx
+		}
+
+		for _, file := range files {
+			missesBefore := remoteMisses(remote)
+			managed, props := idx.effectiveModel(ctx, file.Path)
+			resolved += applyManagedVersions(project.Dependencies, managed, props)
+			if remoteMisses(remote) > missesBefore {
+				stale += markUnresolvedStale(project.Dependencies)
+			}
+		}
+	}
+
+	if resolved > 0 {
+		r.logger.Info("Resolved managed Maven versions", zap.Int("dependency_count", resolved))
+	}
+	if stale > 0 {
+		r.logger.Warn("Offline mode left managed Maven versions unresolved, POMs are missing from the cache",
+			zap.Int("dependency_count", stale))
+	}
+
+	return resolved
+}
+
+// applyManagedVersions sets versions of dependencies left empty or unexpanded by the manifest parser
+func applyManagedVersions(dependencies []*domain.Dependency, managed, props map[string]string) int {
+	resolved := 0
+	for _, dep := range dependencies {
+		var version string
+		switch {
+		case dep.Version == "":
+			version = managed[dep.Name]
+		case strings.Contains(dep.Version, "${"):
+			version = interpolate(dep.Version, props)
+		}
+		if version == "" || strings.Contains(version, "${") {
+			continue
+		}
+
+		dep.Version = version
+		dep.Constraint = version
+		dep.MinVersion = version
+		resolved++
+	}
+	return resolved
+}
+
+// remoteMisses returns the offline cache misses of remote, zero without remote repositories
+func remoteMisses(remote *remoteRepositories) int {
+	if remote == nil {
+		return 0
+	}
+	return remote.misses()
+}
+
+// markUnresolvedStale flags dependencies whose version is still missing or unexpanded
+func markUnresolvedStale(dependencies []*domain.Dependency) int {
+	marked := 0
+	for _, dep := range dependencies {
+		if dep.Version == "" || strings.Contains(dep.Version, "${") {
+			dep.Stale = true
+			marked++
+		}
+	}
+	return marked
+}
+
+// pomFiles returns the pom.xml manifests of a Java project
+func pomFiles(project *domain.Project) []*domain.DependencyFile {
+	if project.Language != "java" {
+		return nil
+	}
+	var files []*domain.DependencyFile
+	for _, file := range project.DependencyFiles {
+		if path.Base(file.Path) == "pom.xml" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,271 @@
+package maven_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/maven"
+	"di-matrix-cli/internal/retry"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+const parentPOM = `<project>
+	<groupId>com.company</groupId>
+	<artifactId>platform-parent</artifactId>
+	<version>1.0.0</version>
+	<packaging>pom</packaging>
+	<properties>
+		<jackson.version>2.15.2</jackson.version>
+	</properties>
+	<dependencyManagement>
+		<dependencies>
+			<dependency>
+				<groupId>com.fasterxml.jackson.core</groupId>
+				<artifactId>jackson-databind</artifactId>
+				<version>${jackson.version}</version>
+			</dependency>
+			<dependency>
+				<groupId>com.company</groupId>
+				<artifactId>platform-bom</artifactId>
+				<version>${project.version}</version>
+				<type>pom</type>
+				<scope>import</scope>
+			</dependency>
+		</dependencies>
+	</dependencyManagement>
+</project>`
+
+const bomPOM = `<project>
+	<groupId>com.company</groupId>
+	<artifactId>platform-bom</artifactId>
+	<version>1.0.0</version>
+	<dependencyManagement>
+		<dependencies>
+			<dependency>
+				<groupId>com.company</groupId>
+				<artifactId>auth-client</artifactId>
+				<version>3.2.1</version>
+			</dependency>
+			<dependency>
+				<groupId>com.fasterxml.jackson.core</groupId>
+				<artifactId>jackson-databind</artifactId>
+				<version>2.10.0</version>
+			</dependency>
+		</dependencies>
+	</dependencyManagement>
+</project>`
+
+const servicePOM = `<project>
+	<parent>
+		<groupId>com.company</groupId>
+		<artifactId>platform-parent</artifactId>
+		<version>1.0.0</version>
+	</parent>
+	<artifactId>orders</artifactId>
+	<dependencies>
+		<dependency>
+			<groupId>com.fasterxml.jackson.core</groupId>
+			<artifactId>jackson-databind</artifactId>
+		</dependency>
+		<dependency>
+			<groupId>com.company</groupId>
+			<artifactId>auth-client</artifactId>
+		</dependency>
+	</dependencies>
+</project>`
+
+func javaProject(path string, files map[string]string, deps ...*domain.Dependency) *domain.Project {
+	project := &domain.Project{
+		ID:           "repo-1-" + path + "-java",
+		Repository:   domain.Repository{ID: 1, URL: "https://gitlab.com/company/platform"},
+		Path:         path,
+		Language:     "java",
+		Dependencies: deps,
+	}
+	for filePath, content := range files {
+		project.DependencyFiles = append(project.DependencyFiles, &domain.DependencyFile{
+			Path:     filePath,
+			Language: "java",
+			Content:  []byte(content),
+		})
+	}
+	return project
+}
+
+func TestResolveManagedVersions_RepositoryParentAndBOM(t *testing.T) {
+	t.Parallel()
+
+	jackson := &domain.Dependency{Name: "com.fasterxml.jackson.core:jackson-databind", Ecosystem: "maven"}
+	auth := &domain.Dependency{Name: "com.company:auth-client", Ecosystem: "maven"}
+	pinned := &domain.Dependency{Name: "junit:junit", Version: "4.13.2", Ecosystem: "maven"}
+	projects := []*domain.Project{
+		javaProject("", map[string]string{"pom.xml": parentPOM}),
+		javaProject("bom", map[string]string{"bom/pom.xml": bomPOM}),
+		javaProject("orders", map[string]string{"orders/pom.xml": servicePOM}, jackson, auth, pinned),
+	}
+
+	resolved := maven.NewResolver(zap.NewNop()).ResolveManagedVersions(context.Background(), projects)
+
+	assert.Equal(t, 2, resolved)
+	assert.Equal(t, "2.15.2", jackson.Version, "Explicitly managed versions win over imported BOMs")
+	assert.Equal(t, "2.15.2", jackson.Constraint)
+	assert.Equal(t, "3.2.1", auth.Version)
+	assert.Equal(t, "4.13.2", pinned.Version)
+}
+
+func TestResolveManagedVersions_RemoteBOM(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/com/company/platform-bom/1.0.0/platform-bom-1.0.0.pom" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(bomPOM))
+	}))
+	defer server.Close()
+
+	service := `<project>
+	<groupId>com.company</groupId>
+	<artifactId>orders</artifactId>
+	<version>1.0.0</version>
+	<properties>
+		<lib.version>5.0.0</lib.version>
+	</properties>
+	<dependencyManagement>
+		<dependencies>
+			<dependency>
+				<groupId>com.company</groupId>
+				<artifactId>platform-bom</artifactId>
+				<version>1.0.0</version>
+				<type>pom</type>
+				<scope>import</scope>
+			</dependency>
+		</dependencies>
+	</dependencyManagement>
+</project>`
+	auth := &domain.Dependency{Name: "com.company:auth-client", Ecosystem: "maven"}
+	lib := &domain.Dependency{Name: "com.company:lib", Version: "${lib.version}", Ecosystem: "maven"}
+	projects := []*domain.Project{javaProject("", map[string]string{"pom.xml": service}, auth, lib)}
+
+	offline := maven.NewResolver(zap.NewNop()).ResolveManagedVersions(context.Background(), projects)
+	assert.Equal(t, 1, offline, "Only the property reference resolves without remote repositories")
+	assert.Empty(t, auth.Version)
+
+	resolver := maven.NewResolver(zap.NewNop()).WithRemoteRepositories([]string{server.URL + "/"})
+	resolved := resolver.ResolveManagedVersions(context.Background(), projects)
+
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, "3.2.1", auth.Version)
+	assert.Equal(t, "5.0.0", lib.Version)
+
+	resolver.ResolveManagedVersions(context.Background(), projects)
+	assert.Equal(t, int32(1), requests.Load(), "Remote POMs are cached")
+}
+
+func TestResolveManagedVersions_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	var parentRequests, bomRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/com/company/platform-parent/1.0.0/platform-parent-1.0.0.pom":
+			if parentRequests.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway) // A flaky proxy in front of the registry
+				return
+			}
+			_, _ = w.Write([]byte(parentPOM))
+		default:
+			bomRequests.Add(1)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service := `<project>
+	<parent>
+		<groupId>com.company</groupId>
+		<artifactId>platform-parent</artifactId>
+		<version>1.0.0</version>
+	</parent>
+	<artifactId>orders</artifactId>
+</project>`
+	jackson := &domain.Dependency{Name: "com.fasterxml.jackson.core:jackson-databind", Ecosystem: "maven"}
+	projects := []*domain.Project{javaProject("", map[string]string{"pom.xml": service}, jackson)}
+
+	resolved := maven.NewResolver(zap.NewNop()).
+		WithRemoteRepositories([]string{server.URL}).
+		WithRetryPolicy(retry.Policy{Retries: 2, Backoff: time.Millisecond}).
+		ResolveManagedVersions(context.Background(), projects)
+
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, "2.15.2", jackson.Version)
+	assert.Equal(t, int32(2), parentRequests.Load(), "The bad gateway is retried")
+	assert.Equal(t, int32(1), bomRequests.Load(), "A missing POM is not retried")
+}
+
+func TestResolveManagedVersions_OfflineCache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(bomPOM))
+	}))
+	defer server.Close()
+
+	service := `<project>
+	<groupId>com.company</groupId>
+	<artifactId>orders</artifactId>
+	<version>1.0.0</version>
+	<dependencyManagement>
+		<dependencies>
+			<dependency>
+				<groupId>com.company</groupId>
+				<artifactId>platform-bom</artifactId>
+				<version>1.0.0</version>
+				<type>pom</type>
+				<scope>import</scope>
+			</dependency>
+		</dependencies>
+	</dependencyManagement>
+</project>`
+	newProjects := func() (*domain.Dependency, []*domain.Project) {
+		auth := &domain.Dependency{Name: "com.company:auth-client", Ecosystem: "maven"}
+		return auth, []*domain.Project{javaProject("", map[string]string{"pom.xml": service}, auth)}
+	}
+	repositories := []string{server.URL}
+
+	// Offline with an empty cache leaves the version unresolved and marks it stale
+	auth, projects := newProjects()
+	emptyCache := cache.New(t.TempDir())
+	maven.NewResolver(zap.NewNop()).WithRemoteRepositories(repositories).WithCache(emptyCache).WithOffline(true).
+		ResolveManagedVersions(context.Background(), projects)
+	assert.Empty(t, auth.Version)
+	assert.True(t, auth.Stale)
+	assert.Zero(t, requests.Load(), "Offline mode never reaches the network")
+
+	// An online run fills the cache, a later offline run is served from it
+	store := cache.New(t.TempDir())
+	auth, projects = newProjects()
+	maven.NewResolver(zap.NewNop()).WithRemoteRepositories(repositories).WithCache(store).
+		ResolveManagedVersions(context.Background(), projects)
+	assert.Equal(t, "3.2.1", auth.Version)
+	assert.Equal(t, int32(1), requests.Load())
+
+	auth, projects = newProjects()
+	maven.NewResolver(zap.NewNop()).WithRemoteRepositories(repositories).WithCache(store).WithOffline(true).
+		ResolveManagedVersions(context.Background(), projects)
+	assert.Equal(t, "3.2.1", auth.Version)
+	assert.False(t, auth.Stale)
+	assert.Equal(t, int32(1), requests.Load())
+}
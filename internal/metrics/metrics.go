@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of the Prometheus text exposition format written by Write
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types of the exposition format
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// Sample is one value of a metric family
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Family is a named metric with its samples
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Analysis holds the measurements of one analysis
+type Analysis struct {
+	Language     string
+	Projects     int
+	Dependencies int
+	Internal     int
+	External     int
+	Outdated     int            // Behind the highest version known to the analysis
+	Vulnerable   int            // With known vulnerabilities
+	Ecosystems   map[string]int // Dependencies per ecosystem
+	Duration     time.Duration
+	APICalls     map[string]int // Source provider API requests per operation
+	FinishedAt   time.Time
+}
+
+// Families returns the metric families of the analysis, labelled with its language
+func (a Analysis) Families() []Family {
+	language := map[string]string{"language": a.Language}
+	gauge := func(name, help string, value float64) Family {
+		return Family{Name: name, Help: help, Type: Gauge, Samples: []Sample{{Labels: language, Value: value}}}
+	}
+
+	internalRatio := 0.0
+	if a.Dependencies > 0 {
+		internalRatio = float64(a.Internal) / float64(a.Dependencies)
+	}
+	return []Family{
+		gauge("di_matrix_projects", "Projects analyzed.", float64(a.Projects)),
+		gauge("di_matrix_dependencies", "Dependencies across all projects.", float64(a.Dependencies)),
+		gauge("di_matrix_internal_dependencies", "Dependencies on internal packages.", float64(a.Internal)),
+		gauge("di_matrix_external_dependencies", "Dependencies on external packages.", float64(a.External)),
+		gauge("di_matrix_internal_ratio", "Share of dependencies on internal packages.", internalRatio),
+		gauge("di_matrix_outdated_dependencies",
+			"Dependencies behind the latest release or a newer version used by another project.", float64(a.Outdated)),
+		gauge("di_matrix_vulnerable_dependencies", "Dependencies with known vulnerabilities.", float64(a.Vulnerable)),
+		gauge("di_matrix_analysis_duration_seconds", "Duration of the analysis.", a.Duration.Seconds()),
+		gauge("di_matrix_analysis_timestamp_seconds", "Time the analysis finished.",
+			float64(a.FinishedAt.UnixMilli())/1000),
+		labelled("di_matrix_ecosystem_dependencies", "Dependencies per ecosystem.", a.Language, "ecosystem",
+			a.Ecosystems),
+		labelled("di_matrix_api_calls", "Source provider API requests sent by the analysis, retries included.",
+			a.Language, "operation", a.APICalls),
+	}
+}
+
+// labelled builds a gauge family with a sample per key of values
+func labelled(name, help, language, label string, values map[string]int) Family {
+	family := Family{Name: name, Help: help, Type: Gauge, Samples: make([]Sample, 0, len(values))}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		family.Samples = append(family.Samples, Sample{
+			Labels: map[string]string{"language": language, label: key},
+			Value:  float64(values[key]),
+		})
+	}
+	return family
+}
+
+// Write writes families in the Prometheus text exposition format, families without samples are left out
+func Write(w io.Writer, families ...Family) error {
+	var out strings.Builder
+	for _, family := range families {
+		if len(family.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&out, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+		fmt.Fprintf(&out, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			out.WriteString(family.Name)
+			writeLabels(&out, sample.Labels)
+			out.WriteByte(' ')
+			out.WriteString(formatValue(sample.Value))
+			out.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// writeLabels writes labels sorted by name, nothing when there are none
+func writeLabels(out *strings.Builder, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		fmt.Fprintf(out, "%s=\"%s\"", name, escapeLabel(labels[name]))
+	}
+	out.WriteByte('}')
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Push replaces the metrics of the job grouped by language on a Prometheus Pushgateway
+func Push(ctx context.Context, client *http.Client, gateway, job string, analysis Analysis) error {
+	var body bytes.Buffer
+	if err := Write(&body, analysis.Families()...); err != nil {
+		return err
+	}
+
+	target := strings.TrimRight(gateway, "/") + "/metrics/job/" + url.PathEscape(job)
+	if analysis.Language != "" {
+		target += "/language/" + url.PathEscape(analysis.Language)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, target, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create Pushgateway request: %w", err)
+	}
+	request.Header.Set("Content-Type", ContentType)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to push metrics: Pushgateway answered %s", response.Status)
+	}
+	return nil
+}
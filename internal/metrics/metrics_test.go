@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/metrics"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analysis() metrics.Analysis {
+	return metrics.Analysis{
+		Language:     "go",
+		Projects:     3,
+		Dependencies: 40,
+		Internal:     10,
+		External:     30,
+		Outdated:     7,
+		Ecosystems:   map[string]int{"npm": 4, "go": 36},
+		Duration:     1500 * time.Millisecond,
+		APICalls:     map[string]int{"get file": 12, "list tree": 3},
+		FinishedAt:   time.Date(2026, time.October, 16, 6, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	require.NoError(t, metrics.Write(&out, analysis().Families()...))
+	text := out.String()
+
+	assert.Contains(t, text, "# HELP di_matrix_dependencies Dependencies across all projects.\n"+
+		"# TYPE di_matrix_dependencies gauge\n"+
+		"di_matrix_dependencies{language=\"go\"} 40\n")
+	assert.Contains(t, text, "di_matrix_outdated_dependencies{language=\"go\"} 7\n")
+	assert.Contains(t, text, "di_matrix_internal_ratio{language=\"go\"} 0.25\n")
+	assert.Contains(t, text, "di_matrix_analysis_duration_seconds{language=\"go\"} 1.5\n")
+	assert.Contains(t, text, "di_matrix_analysis_timestamp_seconds{language=\"go\"} 1.7921304e+09\n")
+	assert.Contains(t, text, "di_matrix_ecosystem_dependencies{ecosystem=\"go\",language=\"go\"} 36\n"+
+		"di_matrix_ecosystem_dependencies{ecosystem=\"npm\",language=\"go\"} 4\n", "samples sorted by label")
+	assert.Contains(t, text, "di_matrix_api_calls{language=\"go\",operation=\"get file\"} 12\n")
+}
+
+func TestWrite_Escaping(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	require.NoError(t, metrics.Write(&out,
+		metrics.Family{Name: "empty", Help: "Left out.", Type: metrics.Gauge},
+		metrics.Family{Name: "runs_total", Help: "Runs\nin total.", Type: metrics.Counter, Samples: []metrics.Sample{
+			{Labels: map[string]string{"path": `C:\reports "latest"`}, Value: 2},
+			{Value: 1},
+		}},
+	))
+	assert.Equal(t, "# HELP runs_total Runs\\nin total.\n"+
+		"# TYPE runs_total counter\n"+
+		"runs_total{path=\"C:\\\\reports \\\"latest\\\"\"} 2\n"+
+		"runs_total 1\n", out.String())
+}
+
+func TestPush(t *testing.T) {
+	t.Parallel()
+
+	var method, path, contentType, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, contentType = r.Method, r.URL.Path, r.Header.Get("Content-Type")
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		if strings.Contains(r.URL.Path, "rejected") {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer gateway.Close()
+
+	require.NoError(t, metrics.Push(context.Background(), gateway.Client(), gateway.URL+"/", "di matrix", analysis()))
+	assert.Equal(t, http.MethodPut, method, "metrics of the previous push are replaced")
+	assert.Equal(t, "/metrics/job/di matrix/language/go", path)
+	assert.Equal(t, metrics.ContentType, contentType)
+	assert.Contains(t, body, "di_matrix_projects{language=\"go\"} 3\n")
+
+	err := metrics.Push(context.Background(), gateway.Client(), gateway.URL, "rejected", analysis())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
@@ -0,0 +1,192 @@
+// Package notify posts analysis summaries to Slack and Microsoft Teams incoming webhooks
+package notify
+
+import (
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Webhook kinds
+const (
+	Slack = "slack"
+	Teams = "teams"
+)
+
+// DefaultMaxItems is how many new outdated dependencies and policy violations a notification lists by default
+const DefaultMaxItems = 10
+
+// Summary is what a notification tells about an analysis
+type Summary struct {
+	Title        string
+	Projects     int
+	Dependencies int
+	Failed       int // Repositories and projects that could not be analyzed
+	Outdated     int // Dependencies behind the latest release of their registry
+	// Outdated dependencies that were not outdated at the same version in the baseline, nil without a baseline
+	NewOutdated []string
+	Violations  []string
+	ReportURL   string
+	MaxItems    int // Items listed per section, the rest is counted
+}
+
+// NewSummary summarizes the analyzed projects and their policy violations. With baseline projects, from the report
+// of an earlier analysis, the dependencies that became outdated since are listed.
+func NewSummary(title string, projects, baseline []*domain.Project, violations []domain.PolicyViolation) Summary {
+	summary := Summary{Title: title, Projects: len(projects), Violations: []string{}, MaxItems: DefaultMaxItems}
+
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = projectName(project)
+		summary.Dependencies += len(project.Dependencies)
+	}
+
+	known := make(map[string]bool)
+	for _, project := range baseline {
+		for _, dep := range project.Dependencies {
+			if version.IsOutdated(dep.Version, dep.LatestVersion) {
+				known[outdatedKey(project.ID, dep)] = true
+			}
+		}
+	}
+	if baseline != nil {
+		summary.NewOutdated = []string{}
+	}
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if !version.IsOutdated(dep.Version, dep.LatestVersion) {
+				continue
+			}
+			summary.Outdated++
+			if baseline != nil && !known[outdatedKey(project.ID, dep)] {
+				summary.NewOutdated = append(summary.NewOutdated,
+					fmt.Sprintf("%s %s → %s (%s)", dep.Name, dep.Version, dep.LatestVersion, names[project.ID]))
+			}
+		}
+	}
+
+	for _, violation := range violations {
+		name, ok := names[violation.ProjectID]
+		if !ok {
+			continue
+		}
+		summary.Violations = append(summary.Violations,
+			fmt.Sprintf("%s: %s (%s)", violation.Rule, violation.Message, name))
+	}
+	return summary
+}
+
+// projectName returns the name of a project as notifications show it
+func projectName(project *domain.Project) string {
+	if project.Name != "" {
+		return project.Name
+	}
+	return project.ID
+}
+
+// outdatedKey identifies a dependency version of a project across analyses
+func outdatedKey(projectID string, dep *domain.Dependency) string {
+	return strings.Join([]string{projectID, dep.Ecosystem, dep.Name, version.Canonical(dep.Version)}, "\x00")
+}
+
+// Post sends the summary to an incoming webhook of kind, Slack or Teams
+func Post(ctx context.Context, client *http.Client, kind, webhookURL string, summary Summary) error {
+	var payload interface{}
+	switch kind {
+	case Slack:
+		payload = slackMessage(summary)
+	case Teams:
+		payload = teamsMessage(summary)
+	default:
+		return fmt.Errorf("unsupported webhook kind %q", kind)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s notification: %w", kind, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		// The error would quote the webhook URL, a secret
+		return fmt.Errorf("failed to create %s notification request", kind)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post %s notification: %w", kind, unwrapURLError(err))
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to post %s notification: the webhook answered %s", kind, response.Status)
+	}
+	return nil
+}
+
+// fact is a labeled count of a summary
+type fact struct {
+	label string
+	value string
+}
+
+// section is a list of a summary under its heading
+type section struct {
+	heading string
+	items   []string
+}
+
+// facts returns the counts of the summary
+func (s Summary) facts() []fact {
+	outdated := strconv.Itoa(s.Outdated)
+	if s.NewOutdated != nil {
+		outdated += fmt.Sprintf(" (%d new)", len(s.NewOutdated))
+	}
+	facts := []fact{
+		{"Projects analyzed", strconv.Itoa(s.Projects)},
+		{"Dependencies", strconv.Itoa(s.Dependencies)},
+		{"Outdated dependencies", outdated},
+		{"Policy violations", strconv.Itoa(len(s.Violations))},
+	}
+	if s.Failed > 0 {
+		facts = append(facts, fact{"Failed", strconv.Itoa(s.Failed)})
+	}
+	return facts
+}
+
+// sections returns the non-empty lists of the summary, at most MaxItems items each with the rest counted
+func (s Summary) sections() []section {
+	var sections []section
+	for _, list := range []section{
+		{"New outdated dependencies", s.NewOutdated},
+		{"Policy violations", s.Violations},
+	} {
+		if len(list.items) == 0 {
+			continue
+		}
+		if s.MaxItems > 0 && len(list.items) > s.MaxItems {
+			more := fmt.Sprintf("… and %d more", len(list.items)-s.MaxItems)
+			list.items = append(list.items[:s.MaxItems:s.MaxItems], more)
+		}
+		sections = append(sections, list)
+	}
+	return sections
+}
+
+// unwrapURLError drops the URL, a secret for webhooks, from errors of HTTP clients
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
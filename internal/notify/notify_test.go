@@ -0,0 +1,148 @@
+package notify_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/notify"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProjects(ginVersion string) []*domain.Project {
+	return []*domain.Project{
+		{
+			ID:   "repo-1-api",
+			Name: "API",
+			Dependencies: []*domain.Dependency{
+				{Name: "github.com/gin-gonic/gin", Version: ginVersion, LatestVersion: "v1.10.0", Ecosystem: "go-modules"},
+				{Name: "github.com/google/uuid", Version: "v1.6.0", LatestVersion: "v1.6.0", Ecosystem: "go-modules"},
+			},
+		},
+		{
+			ID: "repo-2-web",
+			Dependencies: []*domain.Dependency{
+				{Name: "express", Version: "4.18.0", LatestVersion: "5.1.0", Ecosystem: "npm"},
+			},
+		},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	t.Parallel()
+
+	violations := []domain.PolicyViolation{
+		{Rule: "pinning.floating", ProjectID: "repo-2-web", Message: "express is not pinned"},
+		{Rule: "pinning.floating", ProjectID: "repo-9-gone", Message: "left out with its project"},
+	}
+
+	summary := notify.NewSummary("Matrix", testProjects("v1.9.1"), nil, violations)
+	assert.Equal(t, 2, summary.Projects)
+	assert.Equal(t, 3, summary.Dependencies)
+	assert.Equal(t, 2, summary.Outdated)
+	assert.Nil(t, summary.NewOutdated, "nothing is new without a baseline")
+	assert.Equal(t, []string{"pinning.floating: express is not pinned (repo-2-web)"}, summary.Violations)
+	assert.Equal(t, notify.DefaultMaxItems, summary.MaxItems)
+
+	// gin was outdated at v1.9.1 already, the upgrade to v1.9.2 is still behind
+	summary = notify.NewSummary("Matrix", testProjects("v1.9.2"), testProjects("v1.9.1"), nil)
+	assert.Equal(t, []string{"github.com/gin-gonic/gin v1.9.2 → v1.10.0 (API)"}, summary.NewOutdated)
+	assert.Empty(t, summary.Violations)
+
+	summary = notify.NewSummary("Matrix", testProjects("v1.9.1"), testProjects("v1.9.1"), nil)
+	assert.NotNil(t, summary.NewOutdated)
+	assert.Empty(t, summary.NewOutdated)
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+
+	summary := notify.NewSummary("Matrix <nightly>", testProjects("v1.9.2"), testProjects("v1.9.1"), nil)
+	summary.Violations = []string{"a", "b", "c"}
+	summary.MaxItems = 2
+	summary.ReportURL = "https://reports.example.com/matrix.html"
+
+	tests := []struct {
+		kind  string
+		check func(t *testing.T, payload map[string]interface{})
+	}{
+		{
+			kind: notify.Slack,
+			check: func(t *testing.T, payload map[string]interface{}) {
+				assert.Equal(t, "Matrix <nightly>", payload["text"])
+				blocks, _ := payload["blocks"].([]interface{})
+				require.Len(t, blocks, 2)
+				section, _ := blocks[1].(map[string]interface{})
+				text, _ := section["text"].(map[string]interface{})
+				body, _ := text["text"].(string)
+				assert.Contains(t, body, "*Outdated dependencies:* 2 (1 new)")
+				assert.Contains(t, body, "• github.com/gin-gonic/gin v1.9.2 → v1.10.0 (API)")
+				assert.Contains(t, body, "• b\n• … and 1 more")
+				assert.Contains(t, body, "<https://reports.example.com/matrix.html|Open the report>")
+			},
+		},
+		{
+			kind: notify.Teams,
+			check: func(t *testing.T, payload map[string]interface{}) {
+				attachments, _ := payload["attachments"].([]interface{})
+				require.Len(t, attachments, 1)
+				attachment, _ := attachments[0].(map[string]interface{})
+				assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+				card, _ := attachment["content"].(map[string]interface{})
+				assert.Equal(t, "AdaptiveCard", card["type"])
+				body, _ := card["body"].([]interface{})
+				require.Len(t, body, 6)
+				violations, _ := body[5].(map[string]interface{})
+				assert.Equal(t, "- a\n- b\n- … and 1 more", violations["text"])
+				actions, _ := card["actions"].([]interface{})
+				require.Len(t, actions, 1)
+				action, _ := actions[0].(map[string]interface{})
+				assert.Equal(t, summary.ReportURL, action["url"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			t.Parallel()
+
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			}))
+			defer server.Close()
+
+			require.NoError(t, notify.Post(context.Background(), server.Client(), tt.kind, server.URL, summary))
+			tt.check(t, payload)
+		})
+	}
+}
+
+func TestPost_Failure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	summary := notify.NewSummary("Matrix", nil, nil, nil)
+	webhook := server.URL + "/services/secret"
+	err := notify.Post(context.Background(), server.Client(), notify.Slack, webhook, summary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.NotContains(t, err.Error(), "secret")
+
+	server.Close()
+	err = notify.Post(context.Background(), server.Client(), notify.Teams, webhook, summary)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret", "the webhook URL is left out of client errors")
+
+	assert.Error(t, notify.Post(context.Background(), server.Client(), "discord", webhook, summary))
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"strings"
+)
+
+// slackMessage renders the summary as a Slack message with a plain text fallback for notifications
+func slackMessage(summary Summary) map[string]interface{} {
+	var b strings.Builder
+	for _, fact := range summary.facts() {
+		b.WriteString("*" + fact.label + ":* " + slackText(fact.value) + "\n")
+	}
+	for _, list := range summary.sections() {
+		b.WriteString("\n*" + list.heading + "*\n")
+		for _, item := range list.items {
+			b.WriteString("• " + slackText(item) + "\n")
+		}
+	}
+	if summary.ReportURL != "" {
+		b.WriteString("\n<" + summary.ReportURL + "|Open the report>\n")
+	}
+
+	return map[string]interface{}{
+		"text": summary.Title,
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "header",
+				"text": map[string]interface{}{"type": "plain_text", "text": summary.Title},
+			},
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{"type": "mrkdwn", "text": b.String()},
+			},
+		},
+	}
+}
+
+// slackText escapes the characters Slack reads as control sequences
+func slackText(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
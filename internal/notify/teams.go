@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"strings"
+)
+
+// teamsMessage renders the summary as an Adaptive Card, which Teams workflow and connector webhooks both accept
+func teamsMessage(summary Summary) map[string]interface{} {
+	facts := make([]interface{}, 0, len(summary.facts()))
+	for _, fact := range summary.facts() {
+		facts = append(facts, map[string]interface{}{"title": fact.label, "value": fact.value})
+	}
+	body := []interface{}{
+		map[string]interface{}{"type": "TextBlock", "text": summary.Title, "size": "Medium", "weight": "Bolder",
+			"wrap": true},
+		map[string]interface{}{"type": "FactSet", "facts": facts},
+	}
+	for _, list := range summary.sections() {
+		body = append(body,
+			map[string]interface{}{"type": "TextBlock", "text": list.heading, "weight": "Bolder", "wrap": true},
+			map[string]interface{}{"type": "TextBlock", "text": "- " + strings.Join(list.items, "\n- "), "wrap": true},
+		)
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if summary.ReportURL != "" {
+		card["actions"] = []interface{}{
+			map[string]interface{}{"type": "Action.OpenUrl", "title": "Open the report", "url": summary.ReportURL},
+		}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the public OSV.dev API
+const DefaultBaseURL = "https://api.osv.dev"
+
+// maxBatchSize is the most queries the querybatch endpoint accepts per request
+const maxBatchSize = 1000
+
+// Query asks for the vulnerabilities of one package version, Ecosystem is an OSV ecosystem such as "PyPI"
+type Query struct {
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+// Client queries the OSV.dev API under a retry policy
+type Client struct {
+	baseURL string
+	http    *http.Client
+	policy  retry.Policy
+}
+
+// NewClient creates a client of an OSV API such as DefaultBaseURL
+func NewClient(baseURL string, policy retry.Policy) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: &http.Client{}, policy: policy}
+}
+
+// batchRequest is the body of POST /v1/querybatch
+type batchRequest struct {
+	Queries []batchQuery `json:"queries"`
+}
+
+type batchQuery struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version   string `json:"version"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+// batchResponse lists the vulnerability IDs of each query in request order
+type batchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+		NextPageToken string `json:"next_page_token"`
+	} `json:"results"`
+}
+
+// QueryBatch returns the IDs of the vulnerabilities affecting each query, in query order.
+// Queries are sent in batches of the API's maximum size, paginated results are followed.
+func (c *Client) QueryBatch(ctx context.Context, queries []Query) ([][]string, error) {
+	ids := make([][]string, len(queries))
+	pending := make([]int, len(queries)) // Indexes of queries with more results to fetch
+	tokens := make([]string, len(queries))
+	for i := range queries {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		batch := pending[:min(len(pending), maxBatchSize)]
+		pending = pending[len(batch):]
+
+		request := batchRequest{Queries: make([]batchQuery, len(batch))}
+		for i, index := range batch {
+			request.Queries[i].Package.Name = queries[index].Name
+			request.Queries[i].Package.Ecosystem = queries[index].Ecosystem
+			request.Queries[i].Version = queries[index].Version
+			request.Queries[i].PageToken = tokens[index]
+		}
+
+		var response batchResponse
+		if err := c.post(ctx, "/v1/querybatch", request, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Results) != len(batch) {
+			return nil, fmt.Errorf("querybatch returned %d results for %d queries", len(response.Results), len(batch))
+		}
+		for i, result := range response.Results {
+			index := batch[i]
+			for _, vuln := range result.Vulns {
+				ids[index] = append(ids[index], vuln.ID)
+			}
+			if result.NextPageToken != "" {
+				tokens[index] = result.NextPageToken
+				pending = append(pending, index)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// vulnerability is the part of an OSV record shown in the report
+type vulnerability struct {
+	ID               string   `json:"id"`
+	Aliases          []string `json:"aliases"`
+	Summary          string   `json:"summary"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// Vulnerability returns the advisory with the given ID
+func (c *Client) Vulnerability(ctx context.Context, id string) (domain.Vulnerability, error) {
+	var record vulnerability
+	if err := c.get(ctx, "/v1/vulns/"+url.PathEscape(id), &record); err != nil {
+		return domain.Vulnerability{}, err
+	}
+	return domain.Vulnerability{
+		ID:       record.ID,
+		Aliases:  record.Aliases,
+		Severity: normalizeSeverity(record.DatabaseSpecific.Severity),
+		Summary:  record.Summary,
+	}, nil
+}
+
+// normalizeSeverity maps the GitHub advisory severities (CRITICAL, HIGH, MODERATE, LOW) to domain severities.
+// Advisories of other databases (Go, PyPA, RustSec) only carry CVSS vectors and remain unknown.
+func normalizeSeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return domain.SeverityCritical
+	case "HIGH":
+		return domain.SeverityHigh
+	case "MODERATE", "MEDIUM":
+		return domain.SeverityMedium
+	case "LOW":
+		return domain.SeverityLow
+	default:
+		return domain.SeverityUnknown
+	}
+}
+
+// post sends body as JSON and decodes the JSON response into result
+func (c *Client) post(ctx context.Context, path string, body, result any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, path, payload, result)
+}
+
+// get decodes the JSON response of path into result
+func (c *Client) get(ctx context.Context, path string, result any) error {
+	return c.do(ctx, http.MethodGet, path, nil, result)
+}
+
+// do runs a request, retrying transient failures
+func (c *Client) do(ctx context.Context, method, path string, payload []byte, result any) error {
+	endpoint := c.baseURL + path
+	var content []byte
+	err := c.policy.Do(ctx, retry.IsTransientHTTP, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return &retry.StatusError{Status: resp.StatusCode, URL: endpoint}
+		}
+		content, err = io.ReadAll(resp.Body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package osv_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/osv"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// advisories are the OSV records served by the fake API
+var advisories = map[string]string{
+	"GHSA-35jh-r3h4-6jhm": `{"id":"GHSA-35jh-r3h4-6jhm","aliases":["CVE-2021-23337"],` +
+		`"summary":"Command Injection in lodash","database_specific":{"severity":"HIGH"}}`,
+	"GHSA-29mw-wpgm-hmr9": `{"id":"GHSA-29mw-wpgm-hmr9","aliases":["CVE-2020-28500"],` +
+		`"summary":"ReDoS in lodash","database_specific":{"severity":"MODERATE"}}`,
+	"PYSEC-2023-74": `{"id":"PYSEC-2023-74","aliases":["CVE-2023-32681","GHSA-j8r2-6x86-q33q"]}`,
+	"GHSA-j8r2-6x86-q33q": `{"id":"GHSA-j8r2-6x86-q33q","aliases":["CVE-2023-32681"],` +
+		`"summary":"Unintended leak of Proxy-Authorization header","database_specific":{"severity":"MODERATE"}}`,
+	"GO-2023-1571": `{"id":"GO-2023-1571","aliases":["CVE-2022-41723"],"summary":"Denial of service in x/net"}`,
+}
+
+// affected maps "ecosystem:name@version" to the advisories served by the fake querybatch endpoint
+var affected = map[string][]string{
+	"npm:lodash@4.17.20":          {"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"},
+	"PyPI:requests@2.30.0":        {"PYSEC-2023-74", "GHSA-j8r2-6x86-q33q"},
+	"Go:golang.org/x/net@0.6.0":   {"GO-2023-1571"},
+	"npm:@company/internal@1.0.0": {"GHSA-35jh-r3h4-6jhm"},
+	"npm:forked@4.17.20":          {"GHSA-35jh-r3h4-6jhm"},
+	"npm:express@^4.17.0":         {"GHSA-35jh-r3h4-6jhm"},
+}
+
+// fakeOSV serves the OSV API from the fixtures, paginating after the first advisory of every query
+type fakeOSV struct {
+	batches atomic.Int32
+	lookups atomic.Int32
+}
+
+func (f *fakeOSV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if id, ok := strings.CutPrefix(r.URL.Path, "/v1/vulns/"); ok {
+		f.lookups.Add(1)
+		record, found := advisories[id]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(record))
+		return
+	}
+
+	f.batches.Add(1)
+	var request struct {
+		Queries []struct {
+			Package struct {
+				Name      string `json:"name"`
+				Ecosystem string `json:"ecosystem"`
+			} `json:"package"`
+			Version   string `json:"version"`
+			PageToken string `json:"page_token"`
+		} `json:"queries"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	type result struct {
+		Vulns         []map[string]string `json:"vulns,omitempty"`
+		NextPageToken string              `json:"next_page_token,omitempty"`
+	}
+	var response struct {
+		Results []result `json:"results"`
+	}
+	for _, query := range request.Queries {
+		ids := affected[query.Package.Ecosystem+":"+query.Package.Name+"@"+query.Version]
+		var res result
+		switch {
+		case query.PageToken == "" && len(ids) > 1:
+			res.Vulns = []map[string]string{{"id": ids[0]}}
+			res.NextPageToken = "page-2"
+		case query.PageToken == "page-2":
+			for _, id := range ids[1:] {
+				res.Vulns = append(res.Vulns, map[string]string{"id": id})
+			}
+		default:
+			for _, id := range ids {
+				res.Vulns = append(res.Vulns, map[string]string{"id": id})
+			}
+		}
+		response.Results = append(response.Results, res)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func newFakeOSV(t *testing.T) (*fakeOSV, *osv.Client) {
+	t.Helper()
+	fake := &fakeOSV{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, osv.NewClient(server.URL, retry.Policy{})
+}
+
+func TestClient_QueryBatch(t *testing.T) {
+	t.Parallel()
+	fake, client := newFakeOSV(t)
+
+	ids, err := client.QueryBatch(context.Background(), []osv.Query{
+		{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"},
+		{Ecosystem: "crates.io", Name: "serde", Version: "1.0.0"},
+		{Ecosystem: "Go", Name: "golang.org/x/net", Version: "0.6.0"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"}, nil, {"GO-2023-1571"}}, ids)
+	assert.Equal(t, int32(2), fake.batches.Load(), "paginated results are fetched in a second batch")
+}
+
+func TestClient_Vulnerability(t *testing.T) {
+	t.Parallel()
+	_, client := newFakeOSV(t)
+
+	advisory, err := client.Vulnerability(context.Background(), "GHSA-29mw-wpgm-hmr9")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Vulnerability{
+		ID:       "GHSA-29mw-wpgm-hmr9",
+		Aliases:  []string{"CVE-2020-28500"},
+		Severity: domain.SeverityMedium,
+		Summary:  "ReDoS in lodash",
+	}, advisory)
+	assert.Equal(t, "CVE-2020-28500", advisory.CVE())
+
+	advisory, err = client.Vulnerability(context.Background(), "GO-2023-1571")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SeverityUnknown, advisory.Severity, "Go advisories have no database severity")
+
+	_, err = client.Vulnerability(context.Background(), "GHSA-none")
+	assert.Error(t, err)
+}
+
+func scanProjects() []*domain.Project {
+	return []*domain.Project{
+		{ID: "web", Dependencies: []*domain.Dependency{
+			{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			{Name: "@company/internal", Version: "1.0.0", Ecosystem: "npm", IsInternal: true},
+			{Name: "forked", Version: "4.17.20", Ecosystem: "npm", Source: "git+https://github.com/company/forked.git"},
+			{Name: "express", Version: "^4.17.0", Ecosystem: "npm"},
+		}},
+		{ID: "api", Dependencies: []*domain.Dependency{
+			{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			{Name: "requests", Version: "2.30.0", Ecosystem: "pip"},
+			{Name: "golang.org/x/net", Version: "v0.6.0", Ecosystem: "go-modules"},
+		}},
+	}
+}
+
+func TestScanner_ScanVulnerabilities(t *testing.T) {
+	t.Parallel()
+	fake, client := newFakeOSV(t)
+	projects := scanProjects()
+
+	vulnerable := osv.NewScanner(client, zap.NewNop()).ScanVulnerabilities(context.Background(), projects)
+
+	assert.Equal(t, 4, vulnerable)
+	lodash := projects[0].Dependencies[0]
+	assert.Equal(t, 2, lodash.VulnCount)
+	assert.Equal(t, domain.SeverityHigh, lodash.MaxSeverity())
+	assert.Equal(t, 2, projects[1].Dependencies[0].VulnCount, "every project using the version is annotated")
+
+	for _, dep := range projects[0].Dependencies[1:] {
+		assert.Zero(t, dep.VulnCount, "%s is internal, from git or a range and not looked up", dep.Name)
+	}
+
+	requests := projects[1].Dependencies[1]
+	require.Len(t, requests.Vulnerabilities, 1, "aliased PYSEC and GHSA records are one vulnerability")
+	assert.Equal(t, "CVE-2023-32681", requests.Vulnerabilities[0].CVE())
+	assert.Equal(t, domain.SeverityMedium, requests.Vulnerabilities[0].Severity)
+
+	net := projects[1].Dependencies[2]
+	require.Len(t, net.Vulnerabilities, 1, "Go versions are queried without the v prefix")
+	assert.Equal(t, domain.SeverityUnknown, net.MaxSeverity())
+
+	assert.Equal(t, int32(5), fake.lookups.Load(), "each advisory is fetched once")
+}
+
+func TestScanner_CacheAndOffline(t *testing.T) {
+	t.Parallel()
+	store := cache.New(t.TempDir())
+	fake, client := newFakeOSV(t)
+	osv.NewScanner(client, zap.NewNop()).
+		WithCache(store, time.Hour).
+		ScanVulnerabilities(context.Background(), scanProjects()[:1])
+	require.Equal(t, int32(2), fake.lookups.Load())
+
+	t.Run("fresh entries are reused", func(t *testing.T) {
+		t.Parallel()
+		fresh, freshClient := newFakeOSV(t)
+		projects := scanProjects()[:1]
+
+		osv.NewScanner(freshClient, zap.NewNop()).
+			WithCache(store, time.Hour).
+			ScanVulnerabilities(context.Background(), projects)
+
+		assert.Equal(t, int32(0), fresh.batches.Load())
+		assert.Equal(t, int32(0), fresh.lookups.Load())
+		assert.Equal(t, domain.SeverityHigh, projects[0].Dependencies[0].MaxSeverity())
+	})
+
+	t.Run("offline mode marks cache misses stale", func(t *testing.T) {
+		t.Parallel()
+		offline, offlineClient := newFakeOSV(t)
+		projects := scanProjects()
+
+		osv.NewScanner(offlineClient, zap.NewNop()).
+			WithCache(store, 0).
+			WithOffline(true).
+			ScanVulnerabilities(context.Background(), projects)
+
+		assert.Equal(t, int32(0), offline.batches.Load()+offline.lookups.Load(), "offline mode never calls OSV")
+		assert.Equal(t, 2, projects[1].Dependencies[0].VulnCount, "cached results of any age are used")
+		assert.True(t, projects[1].Dependencies[1].Stale)
+		assert.Zero(t, projects[1].Dependencies[1].VulnCount)
+	})
+}
@@ -0,0 +1,303 @@
+package osv
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTTL is how long query results and advisories are reused before OSV is asked again
+	DefaultTTL = 24 * time.Hour
+	// DefaultWorkers is the number of concurrent advisory downloads
+	DefaultWorkers = 8
+)
+
+// Cache namespaces of the vulnerability IDs per package version and of the advisories
+const (
+	queryNamespace    = "osv-query"
+	advisoryNamespace = "osv-vuln"
+)
+
+// ecosystems maps dependency ecosystems to OSV ecosystem names
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var ecosystems = map[string]string{
+	"go-modules": "Go",
+	"npm":        "npm",
+	"pip":        "PyPI",
+	"maven":      "Maven",
+	"cargo":      "crates.io",
+	"bundler":    "RubyGems",
+	"nuget":      "NuGet",
+}
+
+// queryEntry is a cached query result, the IDs of the vulnerabilities affecting a package version
+type queryEntry struct {
+	IDs       []string  `json:"ids"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// advisoryEntry is a cached advisory
+type advisoryEntry struct {
+	Vulnerability domain.Vulnerability `json:"vulnerability"`
+	FetchedAt     time.Time            `json:"fetched_at"`
+}
+
+// Scanner sets the known vulnerabilities of dependency versions from the OSV database.
+// Every package version is queried once per run, results are kept in the on-disk cache for later and offline runs.
+type Scanner struct {
+	client  *Client
+	logger  *zap.Logger
+	store   *cache.Store
+	ttl     time.Duration
+	offline bool
+	workers int
+}
+
+// NewScanner creates a scanner querying client
+func NewScanner(client *Client, logger *zap.Logger) *Scanner {
+	return &Scanner{client: client, logger: logger, ttl: DefaultTTL, workers: DefaultWorkers}
+}
+
+// WithCache keeps query results and advisories in store for ttl, offline runs use cached data of any age
+func (s *Scanner) WithCache(store *cache.Store, ttl time.Duration) *Scanner {
+	s.store = store
+	s.ttl = ttl
+	return s
+}
+
+// WithOffline serves vulnerabilities from the cache only, dependencies missing from it are marked stale
+func (s *Scanner) WithOffline(offline bool) *Scanner {
+	s.offline = offline
+	return s
+}
+
+// WithConcurrency sets the number of concurrent advisory downloads
+func (s *Scanner) WithConcurrency(workers int) *Scanner {
+	if workers > 0 {
+		s.workers = workers
+	}
+	return s
+}
+
+// ScanVulnerabilities sets the vulnerabilities of external dependencies with an exact version
+// and returns how many dependencies are vulnerable. Ranges cannot be matched against advisories
+// and internal, git and local dependencies are not in the database, those are skipped.
+func (s *Scanner) ScanVulnerabilities(ctx context.Context, projects []*domain.Project) int {
+	var queries []Query
+	indexes := make(map[Query]int)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			query, ok := queryFor(dep)
+			if _, seen := indexes[query]; ok && !seen {
+				indexes[query] = len(queries)
+				queries = append(queries, query)
+			}
+		}
+	}
+
+	ids, stale := s.vulnerabilityIDs(ctx, queries)
+	advisories := s.advisories(ctx, ids)
+
+	vulnerable := 0
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			query, ok := queryFor(dep)
+			if !ok {
+				continue
+			}
+			index := indexes[query]
+			if stale[index] {
+				dep.Stale = true
+				continue
+			}
+			dep.Vulnerabilities = merge(ids[index], advisories)
+			dep.VulnCount = len(dep.Vulnerabilities)
+			if dep.VulnCount > 0 {
+				vulnerable++
+			}
+		}
+	}
+
+	s.logger.Info("Scanned dependencies for known vulnerabilities",
+		zap.Int("package_versions", len(queries)),
+		zap.Int("advisories", len(advisories)),
+		zap.Int("vulnerable_dependencies", vulnerable))
+	return vulnerable
+}
+
+// queryFor returns the OSV query of a dependency, false for dependencies that cannot be looked up
+func queryFor(dep *domain.Dependency) (Query, bool) {
+	ecosystem, ok := ecosystems[dep.Ecosystem]
+	if !ok || dep.IsInternal || dep.IsFloating || dep.Source != "" || !version.IsExact(dep.Version) {
+		return Query{}, false
+	}
+	depVersion := dep.Version
+	if ecosystem == "Go" {
+		// OSV lists Go versions without the v prefix of module versions
+		depVersion = strings.TrimPrefix(depVersion, "v")
+	}
+	return Query{Ecosystem: ecosystem, Name: dep.LookupName(), Version: depVersion}, true
+}
+
+// vulnerabilityIDs returns the vulnerability IDs of each query from the cache or OSV,
+// and which queries are stale because offline mode found nothing in the cache.
+// A failed batch falls back to expired cache entries, an outdated answer beats none.
+func (s *Scanner) vulnerabilityIDs(ctx context.Context, queries []Query) ([][]string, map[int]bool) {
+	ids := make([][]string, len(queries))
+	stale := make(map[int]bool)
+	var missing []int
+	for i, query := range queries {
+		var cached queryEntry
+		hit := s.cached(queryNamespace, queryKey(query), &cached)
+		if hit {
+			ids[i] = cached.IDs
+		}
+		if hit && (s.offline || time.Since(cached.FetchedAt) < s.ttl) {
+			continue
+		}
+		if s.offline {
+			stale[i] = true
+			continue
+		}
+		missing = append(missing, i)
+	}
+	if len(missing) == 0 {
+		return ids, stale
+	}
+
+	batch := make([]Query, len(missing))
+	for i, index := range missing {
+		batch[i] = queries[index]
+	}
+	results, err := s.client.QueryBatch(ctx, batch)
+	if err != nil {
+		s.logger.Warn("Failed to query OSV, vulnerabilities are incomplete",
+			zap.Int("package_versions", len(batch)),
+			zap.Error(err))
+		return ids, stale
+	}
+	for i, index := range missing {
+		ids[index] = results[i]
+		s.put(queryNamespace, queryKey(queries[index]), queryEntry{IDs: results[i], FetchedAt: time.Now()})
+	}
+	return ids, stale
+}
+
+// advisories returns the advisories of the given vulnerability IDs from the cache or OSV.
+// Advisories that cannot be fetched are reported by ID only, with an unknown severity.
+func (s *Scanner) advisories(ctx context.Context, ids [][]string) map[string]domain.Vulnerability {
+	advisories := make(map[string]domain.Vulnerability)
+	var missing []string
+	for _, queryIDs := range ids {
+		for _, id := range queryIDs {
+			if _, seen := advisories[id]; seen {
+				continue
+			}
+			advisories[id] = domain.Vulnerability{ID: id, Severity: domain.SeverityUnknown}
+
+			var cached advisoryEntry
+			hit := s.cached(advisoryNamespace, id, &cached)
+			if hit {
+				advisories[id] = cached.Vulnerability
+			}
+			if !s.offline && (!hit || time.Since(cached.FetchedAt) >= s.ttl) {
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, s.workers)
+	for _, id := range missing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			advisory, err := s.client.Vulnerability(ctx, id)
+			if err != nil {
+				s.logger.Debug("Failed to fetch advisory", zap.String("id", id), zap.Error(err))
+				return
+			}
+			s.put(advisoryNamespace, id, advisoryEntry{Vulnerability: advisory, FetchedAt: time.Now()})
+			mu.Lock()
+			advisories[id] = advisory
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return advisories
+}
+
+// merge returns the advisories of ids, collapsing advisories of different databases that alias each other
+// (a GHSA and a PYSEC record of the same CVE) into one with the highest known severity
+func merge(ids []string, advisories map[string]domain.Vulnerability) []domain.Vulnerability {
+	var merged []domain.Vulnerability
+	index := make(map[string]int) // ID or alias -> position in merged
+	for _, id := range ids {
+		advisory := advisories[id]
+		position, duplicate := index[advisory.ID]
+		for _, alias := range advisory.Aliases {
+			if !duplicate {
+				position, duplicate = index[alias]
+			}
+		}
+
+		if duplicate {
+			existing := &merged[position]
+			if domain.SeverityRank(advisory.Severity) > domain.SeverityRank(existing.Severity) {
+				existing.Severity = advisory.Severity
+			}
+			if existing.Summary == "" {
+				existing.Summary = advisory.Summary
+			}
+		} else {
+			position = len(merged)
+			merged = append(merged, advisory)
+		}
+		index[advisory.ID] = position
+		for _, alias := range advisory.Aliases {
+			index[alias] = position
+		}
+	}
+	return merged
+}
+
+// queryKey is the cache key of a query
+func queryKey(query Query) string {
+	return query.Ecosystem + ":" + query.Name + "@" + query.Version
+}
+
+// cached decodes the on-disk cache entry of key into entry
+func (s *Scanner) cached(namespace, key string, entry any) bool {
+	if s.store == nil {
+		return false
+	}
+	data, ok := s.store.Get(namespace, key)
+	return ok && json.Unmarshal(data, entry) == nil
+}
+
+// put writes entry to the on-disk cache, a failed write only costs a lookup next time
+func (s *Scanner) put(namespace, key string, entry any) {
+	if s.store == nil {
+		return
+	}
+	if data, err := json.Marshal(entry); err == nil {
+		_ = s.store.Put(namespace, key, data)
+	}
+}
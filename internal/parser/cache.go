@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// parseCacheNamespace groups parse results in the on-disk cache
+const parseCacheNamespace = "parse"
+
+// parseCacheVersion is part of every key, bump it when the parser output for the same content changes
+const parseCacheVersion = "6"
+
+// parseCache keeps parse results keyed by file content, so identical lockfiles
+// (forks, template repositories) are parsed once per run and, with a store, once across runs
+type parseCache struct {
+	store *cache.Store // Nil keeps results for the current run only
+
+	mu      sync.Mutex
+	entries map[string][]byte // Key -> JSON encoded dependencies
+}
+
+func newParseCache() *parseCache {
+	return &parseCache{entries: make(map[string][]byte)}
+}
+
+// WithCache keeps parse results in store so later runs reuse them, nil limits reuse to the current run
+func (p *Parser) WithCache(store *cache.Store) *Parser {
+	p.results.store = store
+	return p
+}
+
+// cacheKey identifies the parse result of a file: its content, the parser it is handled by
+// and the settings that change the output (remote Maven repositories for pom.xml)
+func (p *Parser) cacheKey(file *domain.DependencyFile) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		parseCacheVersion,
+		file.Language,
+		p.getFileName(file.Path),
+		strconv.FormatBool(p.mavenOffline),
+		strings.Join(p.mavenRepositories, " "),
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(file.Content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// get returns fresh copies of the cached dependencies, callers annotate them per project
+func (c *parseCache) get(key string) ([]*domain.Dependency, bool) {
+	c.mu.Lock()
+	data, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok && c.store != nil {
+		if data, ok = c.store.Get(parseCacheNamespace, key); ok {
+			c.mu.Lock()
+			c.entries[key] = data
+			c.mu.Unlock()
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var dependencies []*domain.Dependency
+	if err := json.Unmarshal(data, &dependencies); err != nil {
+		// A corrupt entry is parsed again and overwritten
+		return nil, false
+	}
+	return dependencies, true
+}
+
+func (c *parseCache) put(key string, dependencies []*domain.Dependency) {
+	data, err := json.Marshal(dependencies)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries[key] = data
+	c.mu.Unlock()
+
+	if c.store != nil {
+		// A failed write only costs parsing the file again next run
+		_ = c.store.Put(parseCacheNamespace, key, data)
+	}
+}
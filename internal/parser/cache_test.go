@@ -0,0 +1,75 @@
+package parser_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/parser"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_ParseFile_CachedByContent(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	content := []byte("requests==2.31.0\nflask==3.0.0\n")
+	first := &domain.DependencyFile{Path: "api/requirements.txt", Language: "python", Content: content}
+	fork := &domain.DependencyFile{Path: "requirements.txt", Language: "python", Content: content}
+
+	parsed, err := p.ParseFile(context.Background(), first)
+	require.NoError(t, err)
+	require.Len(t, parsed, 2)
+	parsed[0].IsInternal = true
+
+	reused, err := p.ParseFile(context.Background(), fork)
+	require.NoError(t, err)
+	require.Len(t, reused, 2)
+	assert.Equal(t, parsed[0].Name, reused[0].Name)
+	assert.False(t, reused[0].IsInternal, "Cached results are fresh copies")
+
+}
+
+func TestParser_ParseFile_CachedAcrossRuns(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	file := &domain.DependencyFile{Path: "go.mod", Language: "go", Content: []byte(
+		"module example.com/svc\n\ngo 1.22\n\nrequire go.uber.org/zap v1.27.0\n")}
+
+	_, err := parser.NewParser().WithCache(cache.New(dir)).ParseFile(context.Background(), file)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "parse"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// A later run serves the stored entry instead of parsing the file
+	entry := filepath.Join(dir, "parse", entries[0].Name())
+	require.NoError(t, os.WriteFile(entry, []byte(`[{"name": "from-cache", "version": "1.0.0"}]`), 0o600))
+
+	reused, err := parser.NewParser().WithCache(cache.New(dir)).ParseFile(context.Background(), file)
+	require.NoError(t, err)
+	require.Len(t, reused, 1)
+	assert.Equal(t, "from-cache", reused[0].Name)
+}
+
+func TestParser_ParseFile_FailuresNotCached(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	p := parser.NewParser().WithCache(cache.New(dir))
+	file := &domain.DependencyFile{Path: "package-lock.json", Language: "nodejs", Content: []byte(`{"packages": `)}
+
+	for range 2 {
+		_, err := p.ParseFile(context.Background(), file)
+		require.Error(t, err)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "parse"))
+	assert.True(t, os.IsNotExist(err))
+}
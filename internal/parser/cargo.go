@@ -0,0 +1,215 @@
+package parser
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// cratesIORegistries are the sources of crates.io in Cargo.lock, the git and the sparse index
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var cratesIORegistries = map[string]bool{
+	"registry+https://github.com/rust-lang/crates.io-index": true,
+	"sparse+https://index.crates.io/":                       true,
+}
+
+// cargoDependency is a Cargo.toml dependency, either a version string or a table
+type cargoDependency struct {
+	Version   string
+	Package   string // Real crate name of a renamed dependency
+	Path      string
+	Git       string
+	Tag       string
+	Rev       string
+	Workspace bool // Inherited from [workspace.dependencies] of the workspace root
+}
+
+// UnmarshalTOML decodes both `serde = "1.0"` and `serde = { version = "1.0", features = ["derive"] }`
+func (d *cargoDependency) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case string:
+		d.Version = v
+	case map[string]any:
+		d.Version, _ = v["version"].(string)
+		d.Package, _ = v["package"].(string)
+		d.Path, _ = v["path"].(string)
+		d.Git, _ = v["git"].(string)
+		d.Tag, _ = v["tag"].(string)
+		d.Rev, _ = v["rev"].(string)
+		d.Workspace, _ = v["workspace"].(bool)
+	default:
+		return fmt.Errorf("unexpected dependency value %v", value)
+	}
+	return nil
+}
+
+// cargoDependencyTables are the dependency tables of a Cargo.toml or one of its [target.'cfg(...)'] sections
+type cargoDependencyTables struct {
+	Dependencies      map[string]cargoDependency `toml:"dependencies"`
+	DevDependencies   map[string]cargoDependency `toml:"dev-dependencies"`
+	BuildDependencies map[string]cargoDependency `toml:"build-dependencies"`
+}
+
+// cargoManifest is the part of a Cargo.toml declaring dependencies
+type cargoManifest struct {
+	cargoDependencyTables
+
+	Target    map[string]cargoDependencyTables `toml:"target"`
+	Workspace struct {
+		Dependencies map[string]cargoDependency `toml:"dependencies"`
+	} `toml:"workspace"`
+}
+
+// cargoDeclaredPackages lists the crates declared by a Cargo.toml. Trivy only parses Cargo.lock,
+// so the manifest is decoded here. Dependencies inherited with `workspace = true` are skipped,
+// their versions are declared once in [workspace.dependencies] of the workspace root.
+func cargoDeclaredPackages(content []byte) ([]ftypes.Package, error) {
+	var manifest cargoManifest
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+
+	tables := []map[string]cargoDependency{
+		manifest.Dependencies, manifest.DevDependencies, manifest.BuildDependencies, manifest.Workspace.Dependencies,
+	}
+	targets := make([]string, 0, len(manifest.Target))
+	for target := range manifest.Target {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		section := manifest.Target[target]
+		tables = append(tables, section.Dependencies, section.DevDependencies, section.BuildDependencies)
+	}
+
+	var packages []ftypes.Package
+	seen := make(map[string]bool)
+	for _, table := range tables {
+		keys := make([]string, 0, len(table))
+		for key := range table {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			dep := table[key]
+			name := dep.crateName(key)
+			if dep.Workspace || seen[name] {
+				continue
+			}
+			seen[name] = true
+			packages = append(packages, cargoPackage(name, dep))
+		}
+	}
+	return packages, nil
+}
+
+// cargoScopes returns the scopes of crates only declared in [dev-dependencies] or [build-dependencies],
+// build scripts are development tooling
+func cargoScopes(content []byte) map[string]string {
+	var manifest cargoManifest
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return nil
+	}
+
+	sections := []cargoDependencyTables{manifest.cargoDependencyTables}
+	for _, section := range manifest.Target {
+		sections = append(sections, section)
+	}
+
+	runtime := make(map[string]bool)
+	scopes := make(map[string]string)
+	for _, section := range sections {
+		for key, dep := range section.Dependencies {
+			runtime[dep.crateName(key)] = true
+		}
+		for _, table := range []map[string]cargoDependency{section.DevDependencies, section.BuildDependencies} {
+			for key, dep := range table {
+				scopes[dep.crateName(key)] = domain.ScopeDev
+			}
+		}
+	}
+	for name := range runtime {
+		delete(scopes, name)
+	}
+	return scopes
+}
+
+// crateName returns the real name of the crate declared under key, which differs for renamed dependencies
+func (d *cargoDependency) crateName(key string) string {
+	if d.Package != "" {
+		return d.Package
+	}
+	return key
+}
+
+// cargoPackage converts a declared dependency, recording git and path sources as external references
+func cargoPackage(name string, dep cargoDependency) ftypes.Package {
+	pkg := ftypes.Package{Name: name, Version: dep.Version}
+	switch {
+	case dep.Git != "":
+		if pkg.Version == "" {
+			pkg.Version = dep.Tag
+		}
+		if pkg.Version == "" {
+			pkg.Version = dep.Rev
+		}
+		pkg.ExternalReferences = []ftypes.ExternalRef{{Type: ftypes.RefVCS, URL: "git+" + dep.Git}}
+	case dep.Path != "":
+		pkg.ExternalReferences = []ftypes.ExternalRef{{Type: ftypes.RefOther, URL: "file:" + dep.Path}}
+	}
+	return pkg
+}
+
+// cargoLockPackages drops the local crates of a Cargo.lock (workspace members and path dependencies,
+// listed without a source) and records the sources of crates not coming from crates.io
+func cargoLockPackages(content []byte, packages []ftypes.Package) ([]ftypes.Package, error) {
+	var lockfile struct {
+		Packages []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+			Source  string `toml:"source"`
+		} `toml:"package"`
+	}
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&lockfile); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+
+	sources := make(map[string]string, len(lockfile.Packages))
+	for _, pkg := range lockfile.Packages {
+		sources[pkg.Name+"@"+pkg.Version] = pkg.Source
+	}
+
+	result := make([]ftypes.Package, 0, len(packages))
+	for _, pkg := range packages {
+		source := sources[pkg.Name+"@"+pkg.Version]
+		if source == "" {
+			continue
+		}
+		if !cratesIORegistries[source] {
+			refType := ftypes.RefOther
+			if strings.HasPrefix(source, "git+") {
+				refType = ftypes.RefVCS
+			}
+			pkg.ExternalReferences = []ftypes.ExternalRef{{Type: refType, URL: source}}
+		}
+		result = append(result, pkg)
+	}
+	return result, nil
+}
+
+// applyCargoSources records the git, path and alternative registry sources of crates,
+// dependencies and packages are in the same order
+func applyCargoSources(packages []ftypes.Package, dependencies []*domain.Dependency) {
+	for i, dep := range dependencies {
+		if refs := packages[i].ExternalReferences; len(refs) > 0 {
+			dep.Source = refs[0].URL
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"slices"
+	"strings"
+	"unicode"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// declaredConstraints returns the version constraints of manifests whose parsed packages carry no version,
+// keyed by normalized package name
+func declaredConstraints(ecosystem, fileName string, content []byte) map[string]string {
+	switch fileName {
+	case "pyproject.toml":
+		return pyprojectConstraints(ecosystem, content)
+	default:
+		return nil
+	}
+}
+
+// dependencyConstraint returns the version constraint a package is declared with. Manifests keep the declared
+// range as the version, except for Cargo.toml where a bare version is a caret requirement (git crates keep
+// their tag or revision); lockfiles pin the resolved version.
+func dependencyConstraint(ecosystem, fileName string, pkg *ftypes.Package, constraints map[string]string) string {
+	if constraint, ok := constraints[NormalizeName(ecosystem, pkg.Name)]; ok {
+		return constraint
+	}
+	if fileName == "Cargo.toml" && pkg.Version != "" && unicode.IsDigit(rune(pkg.Version[0])) &&
+		!slices.ContainsFunc(pkg.ExternalReferences, func(ref ftypes.ExternalRef) bool { return ref.Type == ftypes.RefVCS }) {
+		return "^" + pkg.Version
+	}
+	return pkg.Version
+}
+
+// applyConstraintBounds sets the lowest and highest versions allowed by each dependency's constraint
+func applyConstraintBounds(dependencies []*domain.Dependency) {
+	for _, dep := range dependencies {
+		dep.MinVersion, dep.MaxVersion = version.Bounds(strings.TrimSpace(dep.Constraint))
+	}
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// applyGoModDirectives rewrites go.mod dependencies to what the build actually uses.
+// Replaced modules keep their import path but report the replacement version and target,
+// requirements on excluded versions are marked with a "!=" constraint so they surface as mismatches.
+func applyGoModDirectives(content []byte, dependencies []*domain.Dependency) []*domain.Dependency {
+	modFile, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return dependencies
+	}
+
+	for _, dep := range dependencies {
+		for _, rep := range modFile.Replace {
+			if rep.Old.Path != dep.Name || (rep.Old.Version != "" && rep.Old.Version != dep.Version) {
+				continue
+			}
+			dep.ReplacedBy = strings.TrimSpace(rep.New.Path + " " + rep.New.Version)
+			if rep.New.Version != "" {
+				dep.Version = rep.New.Version
+				dep.Constraint = rep.New.Version
+			}
+			break
+		}
+
+		for _, exclude := range modFile.Exclude {
+			if exclude.Mod.Path == dep.Name && exclude.Mod.Version == dep.Version {
+				dep.Constraint = "!=" + exclude.Mod.Version
+				break
+			}
+		}
+	}
+
+	return dependencies
+}
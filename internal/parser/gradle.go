@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+	"regexp"
+	"strings"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// gradleConfigurations matches the dependency configurations of Java, Kotlin and Android builds,
+// including source set and variant prefixes such as integrationTestImplementation or debugApi.
+// The configuration is the first group of the notations.
+const gradleConfigurations = `(\w*(?:[iI]mplementation|[aA]pi|[cC]ompileOnly|[rR]untimeOnly|[cC]ompile|[rR]untime|` +
+	`[aA]nnotationProcessor|kapt|ksp))`
+
+//nolint:gochecknoglobals // compiled once
+var (
+	// gradleStringNotation matches `implementation 'group:name:version'`, `api("group:name:version")`
+	// and platforms `implementation(platform("group:name:version"))`, classifiers and @ext are dropped
+	gradleStringNotation = regexp.MustCompile(`(?m)^\s*` + gradleConfigurations +
+		`\s*\(?\s*(?:(?:platform|enforcedPlatform)\s*\(\s*)?["']([^"':\s]+):([^"':\s]+)(?::([^"':@]+))?[^"']*["']`)
+	// gradleMapNotation matches `implementation group: 'g', name: 'n', version: 'v'` and the Kotlin named arguments
+	gradleMapNotation = regexp.MustCompile(`(?m)^\s*` + gradleConfigurations +
+		`\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["']` +
+		`(?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	// gradleProperty matches version variables: `def v = '1.0'`, `val v = "1.0"`, `ext.v = '1.0'` and ext { v = '1.0' }
+	gradleProperty = regexp.MustCompile(`(?m)^\s*(?:def\s+|val\s+|var\s+|(?:project\.)?ext\.)?(\w+)\s*=\s*` +
+		`["']([^"'$]+)["']\s*$`)
+	// gradleInterpolation matches "$v", "${v}" and "${project.v}" in Groovy and Kotlin strings
+	gradleInterpolation = regexp.MustCompile(`\$\{?(?:\w+\.)*(\w+)\}?`)
+)
+
+// gradleDeclaredPackages extracts the dependencies declared in a build.gradle or build.gradle.kts on a best-effort
+// basis: Gradle builds are programs, so only literal coordinates and versions from simple variables are understood.
+// Dependencies without a version (managed by a platform or a plugin) or with an unresolved version are skipped,
+// as are version catalog references (libs.foo); gradle.lockfile lists the resolved versions of those.
+func gradleDeclaredPackages(content []byte) []ftypes.Package {
+	properties := make(map[string]string)
+	for _, match := range gradleProperty.FindAllSubmatch(content, -1) {
+		properties[string(match[1])] = string(match[2])
+	}
+
+	var packages []ftypes.Package
+	seen := make(map[string]bool)
+	for _, notation := range []*regexp.Regexp{gradleStringNotation, gradleMapNotation} {
+		for _, match := range notation.FindAllSubmatch(content, -1) {
+			name := string(match[2]) + ":" + string(match[3])
+			version, ok := resolveGradleVersion(string(match[4]), properties)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			packages = append(packages, ftypes.Package{Name: name, Version: version})
+		}
+	}
+	return packages
+}
+
+// gradleScopes returns the scopes of dependencies only declared in test configurations (testImplementation,
+// androidTestImplementation, ...) or annotation processor configurations
+func gradleScopes(content []byte) map[string]string {
+	runtime := make(map[string]bool)
+	scopes := make(map[string]string)
+	for _, notation := range []*regexp.Regexp{gradleStringNotation, gradleMapNotation} {
+		for _, match := range notation.FindAllSubmatch(content, -1) {
+			name := string(match[2]) + ":" + string(match[3])
+			configuration := strings.ToLower(string(match[1]))
+			switch {
+			case strings.Contains(configuration, "test"):
+				scopes[name] = domain.ScopeTest
+			case strings.Contains(configuration, "annotationprocessor"), strings.HasPrefix(configuration, "kapt"),
+				strings.HasPrefix(configuration, "ksp"):
+				scopes[name] = domain.ScopeDev
+			default:
+				runtime[name] = true
+			}
+		}
+	}
+	for name := range runtime {
+		delete(scopes, name)
+	}
+	return scopes
+}
+
+// resolveGradleVersion substitutes version variables, reporting false for missing or unresolved versions
+func resolveGradleVersion(version string, properties map[string]string) (string, bool) {
+	resolved := true
+	version = gradleInterpolation.ReplaceAllStringFunc(version, func(reference string) string {
+		name := gradleInterpolation.FindStringSubmatch(reference)[1]
+		value, ok := properties[name]
+		if !ok {
+			resolved = false
+		}
+		return value
+	})
+	version = strings.TrimSpace(version)
+	return version, resolved && version != ""
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ParserNone declares a custom manifest that is detected as a project but contributes no dependencies
+const ParserNone = "none"
+
+// builtinParsers lists the manifest file names each language parser understands
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var builtinParsers = map[string][]string{
+	"go": {"go.mod", "go.sum", "go.work"},
+	"nodejs": {
+		"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "pnpm-workspace.yaml",
+	},
+	"java":   {"pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile"},
+	"python": {"requirements.txt", "Pipfile", "Pipfile.lock", "poetry.lock", "uv.lock", "pyproject.toml"},
+	"rust":   {"Cargo.toml", "Cargo.lock"},
+	"ruby":   {"Gemfile.lock", "Gemfile"},
+	"dotnet": {"*.csproj", "packages.lock.json", "packages.config", "Directory.Packages.props"},
+}
+
+// builtinGlobs lists the built-in parser file names that match variable file names
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var builtinGlobs = []string{"*.csproj"}
+
+// lockfileFallbacks maps lockfiles to the sibling manifest that declares the same dependencies
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var lockfileFallbacks = map[string]string{
+	"package-lock.json":   "package.json",
+	"npm-shrinkwrap.json": "package.json",
+	"yarn.lock":           "package.json",
+	"pnpm-lock.yaml":      "package.json",
+	"poetry.lock":         "pyproject.toml",
+	"uv.lock":             "pyproject.toml",
+	"Cargo.lock":          "Cargo.toml",
+}
+
+// WithFileAliases registers custom manifest file names, each parsed by the given built-in parser file name
+func (p *Parser) WithFileAliases(aliases map[string]string) *Parser {
+	for fileName, parserFile := range aliases {
+		p.aliases[fileName] = parserFile
+	}
+	return p
+}
+
+// WithMavenRepositories sets the remote repositories used to fetch parent POMs and BOMs,
+// an empty list disables remote fetching
+func (p *Parser) WithMavenRepositories(urls []string) *Parser {
+	p.mavenRepositories = urls
+	p.mavenOffline = len(urls) == 0
+	return p
+}
+
+// ResolveParserFile returns the built-in parser file name to use for a custom manifest.
+// A declared parser must be a built-in file name of the language (or "none"),
+// otherwise the closest built-in parser is chosen by shared name prefix and extension.
+func ResolveParserFile(language, fileName, declared string) (string, error) {
+	candidates, ok := builtinParsers[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported language: %s", language)
+	}
+
+	if declared != "" {
+		if declared == ParserNone {
+			return ParserNone, nil
+		}
+		for _, candidate := range candidates {
+			if candidate == declared {
+				return declared, nil
+			}
+		}
+		return "", fmt.Errorf("parser %q is not a %s parser (supported: %s, %s)",
+			declared, language, strings.Join(candidates, ", "), ParserNone)
+	}
+
+	best, bestScore := candidates[0], -1
+	for _, candidate := range candidates {
+		if score := similarity(fileName, candidate); score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best, nil
+}
+
+// similarity scores how close two file names are: shared prefix length plus a bonus for the same extension
+func similarity(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	score := 0
+	for score < len(a) && score < len(b) && a[score] == b[score] {
+		score++
+	}
+	if path.Ext(a) != "" && path.Ext(a) == path.Ext(b) {
+		score += len(path.Ext(a))
+	}
+	return score
+}
+
+// Fallback returns the sibling manifest to use when the lockfile at filePath cannot be parsed,
+// "" for manifests and lockfiles without one
+func (p *Parser) Fallback(filePath string) string {
+	manifest, ok := lockfileFallbacks[p.getFileName(filePath)]
+	if !ok {
+		return ""
+	}
+	return path.Join(path.Dir(filePath), manifest)
+}
+
+// Capability reports whether a dependency file is parsed, intentionally ignored or unsupported
+func (p *Parser) Capability(filePath string) (domain.FileCapability, string) {
+	switch fileName := p.getFileName(filePath); fileName {
+	case "go.sum":
+		return domain.FileIgnored, "go.sum only contains checksums, dependencies come from go.mod"
+	case "Gemfile":
+		return domain.FileIgnored, "Gemfile is Ruby code, dependencies come from Gemfile.lock"
+	case "go.work", "pnpm-workspace.yaml":
+		return domain.FileIgnored, fileName + " is a workspace descriptor, dependencies come from member manifests"
+	case ParserNone:
+		return domain.FileIgnored, "manifest is mapped to parser \"none\""
+	default:
+		if p.CanParse(filePath) {
+			return domain.FileParsed, ""
+		}
+		return domain.FileUnsupported, "no parser is available for " + path.Base(filePath)
+	}
+}
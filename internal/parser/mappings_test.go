@@ -0,0 +1,135 @@
+package parser_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/parser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParserFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		language string
+		fileName string
+		declared string
+		expected string
+	}{
+		{"python", "requirements-dev.txt", "", "requirements.txt"},
+		{"python", "Pipfile.ci", "", "Pipfile.lock"}, // Pipfile and Pipfile.lock share the pipenv parser
+		{"python", "Pipfile-ci", "", "Pipfile"},
+		{"nodejs", "package.base.json", "", "package.json"},
+		{"go", "Gopkg.lock", "none", parser.ParserNone},
+		{"python", "constraints.in", "requirements.txt", "requirements.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			t.Parallel()
+			resolved, err := parser.ResolveParserFile(tt.language, tt.fileName, tt.declared)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolved)
+		})
+	}
+
+	_, err := parser.ResolveParserFile("python", "deps.txt", "go.mod")
+	require.Error(t, err)
+
+	_, err = parser.ResolveParserFile("haskell", "stack.yaml", "")
+	require.Error(t, err)
+}
+
+func TestParser_WithFileAliases(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser().WithFileAliases(map[string]string{
+		"requirements-dev.txt": "requirements.txt",
+		"Gopkg.lock":           parser.ParserNone,
+	})
+	ctx := context.Background()
+
+	assert.True(t, p.CanParse("tools/requirements-dev.txt"))
+
+	deps, err := p.ParseFile(ctx, &domain.DependencyFile{
+		Path:     "tools/requirements-dev.txt",
+		Language: "python",
+		Content:  []byte("pytest==8.3.2\n"),
+	})
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	assert.Equal(t, "pytest", deps[0].Name)
+
+	deps, err = p.ParseFile(ctx, &domain.DependencyFile{
+		Path:     "Gopkg.lock",
+		Language: "go",
+		Content:  []byte("[[projects]]\n"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestParser_Capability(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser().WithFileAliases(map[string]string{"Gopkg.lock": parser.ParserNone})
+
+	tests := []struct {
+		path     string
+		expected domain.FileCapability
+	}{
+		{"go.mod", domain.FileParsed},
+		{"backend/requirements.txt", domain.FileParsed},
+		{"go.sum", domain.FileIgnored},
+		{"go.work", domain.FileIgnored},
+		{"Gopkg.lock", domain.FileIgnored},
+		{"Gemfile", domain.FileIgnored},
+		{"api/Gemfile.lock", domain.FileParsed},
+		{"build.gradle", domain.FileParsed},
+		{"app/build.gradle.kts", domain.FileParsed},
+		{"gradle.lockfile", domain.FileParsed},
+		{"setup.py", domain.FileUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			capability, reason := p.Capability(tt.path)
+			assert.Equal(t, tt.expected, capability)
+			if tt.expected != domain.FileParsed {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestParser_Fallback(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser().WithFileAliases(map[string]string{"npm-lock.json": "package-lock.json"})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"package-lock.json", "package.json"},
+		{"web/yarn.lock", "web/package.json"},
+		{"services/api/poetry.lock", "services/api/pyproject.toml"},
+		{"uv.lock", "pyproject.toml"},
+		{"web/npm-lock.json", "web/package.json"},
+		{"crates/cli/Cargo.lock", "crates/cli/Cargo.toml"},
+		{"package.json", ""},
+		{"go.mod", ""},
+		{"requirements.txt", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, p.Fallback(tt.path))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pypiSeparators matches runs of characters PyPI treats as equivalent in package names (PEP 503)
+var pypiSeparators = regexp.MustCompile(`[-_.]+`) //nolint:gochecknoglobals // compiled once
+
+// declaredName returns the name as written when normalization changes it, "" when it is already canonical
+func declaredName(ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+	if NormalizeName(ecosystem, name) == name {
+		return ""
+	}
+	return name
+}
+
+// NormalizeName returns the canonical form of a package name in the given ecosystem so that
+// spellings of the same package ("Django" and "django", "Foo_Bar" and "foo-bar") dedup to one column.
+// Names are only folded where the ecosystem itself treats the spellings as one package.
+func NormalizeName(ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+
+	switch ecosystem {
+	case "pip":
+		return pypiSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	case "npm":
+		// Scopes are case-insensitive, legacy unscoped names may still contain capitals
+		if scope, pkg, scoped := strings.Cut(name, "/"); scoped && strings.HasPrefix(scope, "@") {
+			return strings.ToLower(scope) + "/" + strings.ToLower(pkg)
+		}
+		return name
+	case "nuget":
+		// NuGet package IDs are case-insensitive
+		return strings.ToLower(name)
+	default:
+		// Go module paths and Maven coordinates are case-sensitive, a Maven repository serves
+		// com.zaxxer:HikariCP under com/zaxxer/HikariCP only
+		return name
+	}
+}
@@ -0,0 +1,54 @@
+package parser_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/parser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ecosystem string
+		name      string
+		expected  string
+	}{
+		{"pip", "Django", "django"},
+		{"pip", "Foo_Bar.baz", "foo-bar-baz"},
+		{"pip", "zope__interface", "zope-interface"},
+		{"npm", "@Babel/Core", "@babel/core"},
+		{"npm", "JSONStream", "JSONStream"},
+		{"npm", " lodash ", "lodash"},
+		{"maven", "com.zaxxer:HikariCP", "com.zaxxer:HikariCP"},
+		{"nuget", "Newtonsoft.Json", "newtonsoft.json"},
+		{"go-modules", "github.com/BurntSushi/toml", "github.com/BurntSushi/toml"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, parser.NormalizeName(tt.ecosystem, tt.name), "%s %s", tt.ecosystem, tt.name)
+	}
+}
+
+func TestParseFile_NormalizesNames(t *testing.T) {
+	t.Parallel()
+	p := parser.NewParser()
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "requirements.txt",
+		Language: "python",
+		Content:  []byte("Django==4.2.0\nTyping_Extensions==4.8.0\n"),
+	})
+
+	require.NoError(t, err)
+	names := make(map[string]string, len(deps))
+	for _, dep := range deps {
+		names[dep.Name] = dep.LookupName()
+	}
+	assert.Equal(t, map[string]string{"django": "Django", "typing-extensions": "Typing_Extensions"}, names,
+		"registries are queried with the declared spelling")
+}
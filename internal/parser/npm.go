@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// npmDeclaredPackages lists package.json dependencies with their raw specifiers as versions,
+// applyNpmSpecifiers later resolves aliases, git and local specifiers
+func npmDeclaredPackages(dependencyMaps ...map[string]string) []ftypes.Package {
+	var packages []ftypes.Package
+	for _, dependencies := range dependencyMaps {
+		names := make([]string, 0, len(dependencies))
+		for name := range dependencies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			packages = append(packages, ftypes.Package{Name: name, Version: dependencies[name]})
+		}
+	}
+	return packages
+}
+
+// npmScopes returns the scopes of package.json devDependencies and optionalDependencies,
+// packages also listed in dependencies are runtime dependencies
+func npmScopes(content []byte) map[string]string {
+	var manifest struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil
+	}
+
+	scopes := make(map[string]string)
+	for name := range manifest.DevDependencies {
+		if _, runtime := manifest.Dependencies[name]; !runtime {
+			scopes[name] = domain.ScopeDev
+		}
+	}
+	for name := range manifest.OptionalDependencies {
+		scopes[name] = domain.ScopeOptional
+	}
+	return scopes
+}
+
+// applyNpmSpecifiers rewrites npm dependencies declared through aliases ("npm:bar@^2"), git URLs or local paths
+// ("file:", "link:", "workspace:") to the real package and version, recording the raw specifier as the source
+func applyNpmSpecifiers(dependencies []*domain.Dependency) []*domain.Dependency {
+	for _, dep := range dependencies {
+		spec := strings.TrimSpace(dep.Version)
+
+		switch {
+		case strings.HasPrefix(spec, "npm:"):
+			name, version := splitNpmAlias(strings.TrimPrefix(spec, "npm:"))
+			dep.Name = NormalizeName("npm", name)
+			dep.DeclaredName = declaredName("npm", name)
+			dep.Version = version
+		case isNpmLocalSpec(spec):
+			_, version, _ := strings.Cut(spec, "workspace:")
+			dep.Version = version
+		case isNpmGitSpec(spec):
+			_, committish, _ := strings.Cut(spec, "#")
+			dep.Version = strings.TrimPrefix(committish, "semver:")
+		default:
+			continue
+		}
+
+		dep.Source = spec
+		dep.Constraint = dep.Version
+	}
+
+	return dependencies
+}
+
+// splitNpmAlias splits "bar@^2" or "@scope/bar@^2" into the package name and version range
+func splitNpmAlias(target string) (string, string) {
+	at := strings.LastIndex(target, "@")
+	if at <= 0 {
+		return target, ""
+	}
+	return target[:at], target[at+1:]
+}
+
+// isNpmLocalSpec reports whether a specifier points into the local file system or workspace
+func isNpmLocalSpec(spec string) bool {
+	for _, prefix := range []string{"file:", "link:", "workspace:", "./", "../", "/", "~/"} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNpmGitSpec reports whether a specifier is a git URL, a hosted git shorthand or a remote tarball
+func isNpmGitSpec(spec string) bool {
+	for _, prefix := range []string{
+		"git+", "git:", "git@", "ssh://", "http://", "https://", "github:", "gitlab:", "bitbucket:", "gist:",
+	} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+
+	// GitHub shorthand "user/repo" or "user/repo#ref"
+	owner, _, found := strings.Cut(spec, "/")
+	return found && owner != "" && !strings.ContainsAny(owner, "@:^~<>=* ")
+}
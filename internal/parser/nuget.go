@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// csprojPackageReference is a <PackageReference> of an SDK-style project file,
+// the version is an attribute or a child element
+type csprojPackageReference struct {
+	Include         string `xml:"Include,attr"`
+	Update          string `xml:"Update,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionOverride string `xml:"VersionOverride,attr"`
+	VersionElement  string `xml:"Version"`
+}
+
+// csprojPackages lists the PackageReference entries of a .csproj file. Trivy has no project file parser.
+// References without a version (central package management) are skipped, their versions are declared
+// in Directory.Packages.props, as are versions from MSBuild properties that cannot be resolved here.
+func csprojPackages(content []byte) ([]ftypes.Package, error) {
+	var project struct {
+		ItemGroups []struct {
+			PackageReferences []csprojPackageReference `xml:"PackageReference"`
+		} `xml:"ItemGroup"`
+	}
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return nil, fmt.Errorf("failed to decode project file: %w", err)
+	}
+
+	var packages []ftypes.Package
+	seen := make(map[string]bool)
+	for _, group := range project.ItemGroups {
+		for _, reference := range group.PackageReferences {
+			name := strings.TrimSpace(reference.Include)
+			if name == "" {
+				name = strings.TrimSpace(reference.Update)
+			}
+			version := strings.TrimSpace(firstNonEmpty(
+				reference.VersionOverride, reference.Version, reference.VersionElement))
+			if name == "" || version == "" || isMSBuildProperty(version) || seen[name] {
+				continue
+			}
+			seen[name] = true
+			packages = append(packages, ftypes.Package{Name: name, Version: version})
+		}
+	}
+	return packages, nil
+}
+
+// isMSBuildProperty reports whether a value references an MSBuild property such as $(SerilogVersion)
+func isMSBuildProperty(value string) bool {
+	return strings.Contains(value, "$(")
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -5,32 +5,66 @@ import (
 	"context"
 	"di-matrix-cli/internal/domain"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/golang/mod"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/gradle/lockfile"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/java/pom"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/npm"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/packagejson"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/pnpm"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/nodejs/yarn"
+	nugetconfig "github.com/aquasecurity/trivy/pkg/dependency/parser/nuget/config"
+	nugetlock "github.com/aquasecurity/trivy/pkg/dependency/parser/nuget/lock"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/nuget/packagesprops"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/pip"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/pipenv"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/poetry"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/pyproject"
 	"github.com/aquasecurity/trivy/pkg/dependency/parser/python/uv"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/ruby/bundler"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/rust/cargo"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	xio "github.com/aquasecurity/trivy/pkg/x/io"
 )
 
 // Parser handles dependency file parsing using Trivy
-type Parser struct{}
+type Parser struct {
+	aliases map[string]string // Custom manifest filename -> built-in parser filename
+
+	mavenRepositories []string // Remote repositories for parent POMs and BOMs, Maven Central when unset
+	mavenOffline      bool     // Never fetch parent POMs and BOMs remotely
+
+	results *parseCache // Parse results keyed by file content
+}
 
 // NewParser creates a new dependency parser
 func NewParser() *Parser {
-	return &Parser{}
+	return &Parser{aliases: map[string]string{}, results: newParseCache()}
 }
 
-// ParseFile parses a dependency file and extracts dependencies
+// ParseFile parses a dependency file and extracts dependencies.
+// Files with the same content are parsed once, failures are never cached.
 func (p *Parser) ParseFile(ctx context.Context, file *domain.DependencyFile) ([]*domain.Dependency, error) {
+	key := p.cacheKey(file)
+	if dependencies, ok := p.results.get(key); ok {
+		return dependencies, nil
+	}
+
+	dependencies, err := p.parse(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	p.results.put(key, dependencies)
+	return dependencies, nil
+}
+
+// parse extracts the dependencies of a file with the Trivy parser of its language
+func (p *Parser) parse(ctx context.Context, file *domain.DependencyFile) ([]*domain.Dependency, error) {
 	// Create a reader from the file content
 	reader, err := xio.NewReadSeekerAt(bytes.NewReader(file.Content))
 	if err != nil {
@@ -40,15 +74,25 @@ func (p *Parser) ParseFile(ctx context.Context, file *domain.DependencyFile) ([]
 	var trivyPackages []ftypes.Package
 	var trivyDeps []ftypes.Dependency
 
+	if p.getFileName(file.Path) == ParserNone {
+		return []*domain.Dependency{}, nil
+	}
+
 	switch file.Language {
 	case "go":
 		trivyPackages, trivyDeps, err = p.parseGoFileWithTrivy(reader, file.Path)
 	case "nodejs":
 		trivyPackages, trivyDeps, err = p.parseNodeJSFileWithTrivy(reader, file.Path)
 	case "java":
-		trivyPackages, trivyDeps, err = p.parseJavaFileWithTrivy(reader, file.Path)
+		trivyPackages, trivyDeps, err = p.parseJavaFileWithTrivy(reader, file.Content, file.Path)
 	case "python":
-		trivyPackages, trivyDeps, err = p.parsePythonFileWithTrivy(reader, file.Path)
+		trivyPackages, trivyDeps, err = p.parsePythonFileWithTrivy(reader, file.Content, file.Path)
+	case "rust":
+		trivyPackages, trivyDeps, err = p.parseRustFileWithTrivy(reader, file.Content, file.Path)
+	case "ruby":
+		trivyPackages, trivyDeps, err = p.parseRubyFileWithTrivy(reader, file.Path)
+	case "dotnet":
+		trivyPackages, trivyDeps, err = p.parseDotNetFileWithTrivy(reader, file.Content, file.Path)
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", file.Language)
 	}
@@ -59,38 +103,93 @@ func (p *Parser) ParseFile(ctx context.Context, file *domain.DependencyFile) ([]
 
 	// Convert Trivy packages to domain dependencies
 	var dependencies []*domain.Dependency
+	ecosystem := p.getEcosystem(file.Language)
+	parents := dependencyParents(ecosystem, trivyPackages, trivyDeps)
+	fileName := p.getFileName(file.Path)
+	scopes := declaredScopes(fileName, file.Content)
+	constraints := declaredConstraints(ecosystem, fileName, file.Content)
 	for i := range trivyPackages {
 		pkg := &trivyPackages[i]
 		dependencies = append(dependencies, &domain.Dependency{
-			Name:          pkg.Name,
+			Name:          NormalizeName(ecosystem, pkg.Name),
+			DeclaredName:  declaredName(ecosystem, pkg.Name),
 			Version:       pkg.Version,
-			LatestVersion: pkg.Version, // TODO: Fetch actual latest version from package registry
-			Constraint:    p.extractConstraint(pkg),
-			MinVersion:    p.extractMinVersion(pkg),
-			MaxVersion:    p.extractMaxVersion(pkg),
+			LatestVersion: pkg.Version, // Replaced by the registry lookup when the package is found
+			Constraint:    dependencyConstraint(ecosystem, fileName, pkg, constraints),
 			IsInternal:    p.isInternalDependency(pkg.Name),
-			Ecosystem:     p.getEcosystem(file.Language),
+			Ecosystem:     ecosystem,
+			Direct:        pkg.Relationship != ftypes.RelationshipIndirect && !pkg.Indirect,
+			Parents:       parents[pkg.ID],
+			Scope:         dependencyScope(pkg, scopes),
 		})
 	}
 
-	// Log dependencies for debugging (we don't use them in the domain model yet)
-	_ = trivyDeps
+	// Report what the Go build actually uses
+	if file.Language == "go" && fileName == "go.mod" {
+		dependencies = applyGoModDirectives(file.Content, dependencies)
+	}
+
+	// Resolve npm aliases and record git and local sources
+	if file.Language == "nodejs" {
+		dependencies = applyNpmSpecifiers(dependencies)
+	}
+
+	// Record git, path and alternative registry sources of crates
+	if file.Language == "rust" {
+		applyCargoSources(trivyPackages, dependencies)
+	}
+
+	applyConstraintBounds(dependencies)
 
 	return dependencies, nil
 }
 
+// dependencyParents returns the names of the packages depending on each package, keyed by package ID.
+// Edges come from the dependency graph of lockfiles and from the packages themselves,
+// the project's own root and workspace packages are not parents.
+func dependencyParents(
+	ecosystem string,
+	packages []ftypes.Package,
+	graph []ftypes.Dependency,
+) map[string][]string {
+	names := make(map[string]string, len(packages))
+	for i := range packages {
+		pkg := &packages[i]
+		if pkg.ID != "" && pkg.Relationship != ftypes.RelationshipRoot && pkg.Relationship != ftypes.RelationshipWorkspace {
+			names[pkg.ID] = NormalizeName(ecosystem, pkg.Name)
+		}
+	}
+
+	parents := make(map[string][]string)
+	link := func(parentID string, childIDs []string) {
+		parent, ok := names[parentID]
+		if !ok {
+			return
+		}
+		for _, childID := range childIDs {
+			if !slices.Contains(parents[childID], parent) {
+				parents[childID] = append(parents[childID], parent)
+			}
+		}
+	}
+	for _, edge := range graph {
+		link(edge.ID, edge.DependsOn)
+	}
+	for i := range packages {
+		link(packages[i].ID, packages[i].DependsOn)
+	}
+	return parents
+}
+
 // CanParse checks if this parser can handle the given file type
 func (p *Parser) CanParse(filePath string) bool {
 	fileName := p.getFileName(filePath)
 
-	supportedFiles := map[string][]string{
-		"go":     {"go.mod", "go.sum"},
-		"nodejs": {"package.json", "package-lock.json", "yarn.lock"},
-		"java":   {"pom.xml"},
-		"python": {"requirements.txt", "Pipfile", "poetry.lock", "uv.lock", "pyproject.toml"},
+	if _, aliased := p.aliases[fileName]; aliased {
+		return true
 	}
 
-	for _, files := range supportedFiles {
+	for _, files := range builtinParsers {
 		for _, file := range files {
 			if fileName == file {
 				return true
@@ -115,8 +214,9 @@ func (p *Parser) parseGoFileWithTrivy(
 			return nil, nil, fmt.Errorf("go.mod parser error: %w", err)
 		}
 		return packages, deps, nil
-	case "go.sum":
-		// go.sum files don't contain dependency information, they contain checksums
+	case "go.sum", "go.work":
+		// go.sum files don't contain dependency information, they contain checksums,
+		// and go.work only lists workspace modules which are parsed from their own go.mod
 		// Return empty results instead of an error
 		return []ftypes.Package{}, []ftypes.Dependency{}, nil
 	default:
@@ -132,8 +232,9 @@ func (p *Parser) parseNodeJSFileWithTrivy(
 	fileName = p.getFileName(fileName)
 
 	switch fileName {
-	case "package-lock.json":
-		// package-lock.json is more important - contains exact versions of all dependencies
+	case "package-lock.json", "npm-shrinkwrap.json":
+		// package-lock.json is more important - contains exact versions of all dependencies,
+		// npm-shrinkwrap.json is the same format published with the package
 		parser := npm.NewParser()
 		return parser.Parse(reader)
 	case "package.json":
@@ -143,39 +244,76 @@ func (p *Parser) parseNodeJSFileWithTrivy(
 		if err != nil {
 			return nil, nil, err
 		}
-		// Trivy's packagejson parser returns a single package
-		// The dependencies are stored in the Package's dependencies field
-		var packages []ftypes.Package
-		if pkg.Name != "" {
-			packages = append(packages, pkg.Package)
-		}
-		return packages, nil, nil
+		// Trivy's packagejson parser returns the project itself with the declared dependencies attached
+		return npmDeclaredPackages(pkg.Dependencies, pkg.OptionalDependencies, pkg.DevDependencies), nil, nil
 	case "yarn.lock":
 		parser := yarn.NewParser()
 		packages, deps, _, err := parser.Parse(reader)
 		return packages, deps, err
+	case "pnpm-lock.yaml":
+		parser := pnpm.NewParser()
+		return parser.Parse(reader)
+	case "pnpm-workspace.yaml":
+		// Workspace descriptor only, members are parsed from their own manifests
+		return []ftypes.Package{}, []ftypes.Dependency{}, nil
 	default:
 		return nil, nil, fmt.Errorf("unsupported Node.js file: %s", fileName)
 	}
 }
 
-// parseJavaFileWithTrivy parses Java dependencies using Trivy's Java parser
+// parseJavaFileWithTrivy parses Java dependencies using Trivy's Maven and Gradle parsers
 func (p *Parser) parseJavaFileWithTrivy(
 	reader xio.ReadSeekerAt,
+	content []byte,
 	fileName string,
 ) ([]ftypes.Package, []ftypes.Dependency, error) {
 	fileName = p.getFileName(fileName)
 
-	if fileName == "pom.xml" {
-		parser := pom.NewParser("") // Use default options
+	switch fileName {
+	case "pom.xml":
+		packages, deps, err := p.parsePOM(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Trivy leaves out test scoped and optional dependencies
+		return append(packages, pomScopedPackages(content)...), deps, nil
+	case "gradle.lockfile":
+		parser := lockfile.NewParser()
 		return parser.Parse(reader)
+	case "build.gradle", "build.gradle.kts":
+		// Trivy only parses Gradle lockfiles
+		return gradleDeclaredPackages(content), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported Java file: %s", fileName)
+	}
+}
+
+// parsePOM parses a pom.xml with Trivy. Trivy looks up parent POMs next to the file on the local disk,
+// so it is pointed at an empty directory to never pick up unrelated files from the working directory.
+func (p *Parser) parsePOM(reader xio.ReadSeekerAt) ([]ftypes.Package, []ftypes.Dependency, error) {
+	dir, err := os.MkdirTemp("", "di-matrix-pom-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create pom.xml workspace: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	filePath := filepath.Join(dir, "pom.xml")
+	var parser *pom.Parser
+	switch {
+	case p.mavenOffline:
+		parser = pom.NewParser(filePath, pom.WithOffline(true))
+	case len(p.mavenRepositories) > 0:
+		parser = pom.NewParser(filePath, pom.WithReleaseRemoteRepos(p.mavenRepositories))
+	default:
+		parser = pom.NewParser(filePath)
 	}
-	return nil, nil, fmt.Errorf("unsupported Java file: %s", fileName)
+	return parser.Parse(reader)
 }
 
 // parsePythonFileWithTrivy parses Python dependencies using Trivy's Python parsers
 func (p *Parser) parsePythonFileWithTrivy(
 	reader xio.ReadSeekerAt,
+	content []byte,
 	fileName string,
 ) ([]ftypes.Package, []ftypes.Dependency, error) {
 	fileName = p.getFileName(fileName)
@@ -184,7 +322,7 @@ func (p *Parser) parsePythonFileWithTrivy(
 	case "requirements.txt":
 		parser := pip.NewParser(false)
 		return parser.Parse(reader)
-	case "Pipfile":
+	case "Pipfile", "Pipfile.lock":
 		parser := pipenv.NewParser()
 		return parser.Parse(reader)
 	case "poetry.lock":
@@ -207,40 +345,106 @@ func (p *Parser) parsePythonFileWithTrivy(
 		mainDeps := pyprojectData.MainDeps()
 		for _, depName := range mainDeps.Items() {
 			packages = append(packages, ftypes.Package{
-				Name:    depName,
-				Version: "", // pyproject.toml doesn't contain exact versions
+				Name:    requirementName(depName), // Trivy keeps "~" of compatible release clauses ("numpy~")
+				Version: "",                       // pyproject.toml doesn't contain exact versions
 			})
 		}
 
+		// Extras and dependency groups, which Trivy leaves out
+		packages = append(packages, pyprojectScopedPackages(pyprojectScopes(content))...)
+
 		return packages, nil, nil
 	default:
 		return nil, nil, fmt.Errorf("unsupported Python file: %s", fileName)
 	}
 }
 
-// Helper methods
+// parseRustFileWithTrivy parses Rust dependencies, Cargo.lock with Trivy's cargo parser
+func (p *Parser) parseRustFileWithTrivy(
+	reader xio.ReadSeekerAt,
+	content []byte,
+	fileName string,
+) ([]ftypes.Package, []ftypes.Dependency, error) {
+	fileName = p.getFileName(fileName)
 
-func (p *Parser) getFileName(filePath string) string {
-	parts := strings.Split(filePath, "/")
-	return parts[len(parts)-1]
+	switch fileName {
+	case "Cargo.lock":
+		parser := cargo.NewParser()
+		packages, deps, err := parser.Parse(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		packages, err = cargoLockPackages(content, packages)
+		return packages, deps, err
+	case "Cargo.toml":
+		packages, err := cargoDeclaredPackages(content)
+		return packages, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported Rust file: %s", fileName)
+	}
 }
 
-func (p *Parser) extractConstraint(pkg *ftypes.Package) string {
-	// For now, use version as constraint
-	// In a more sophisticated implementation, we could parse the original constraint
-	return pkg.Version
+// parseRubyFileWithTrivy parses Ruby dependencies using Trivy's Bundler parser
+func (p *Parser) parseRubyFileWithTrivy(
+	reader xio.ReadSeekerAt,
+	fileName string,
+) ([]ftypes.Package, []ftypes.Dependency, error) {
+	fileName = p.getFileName(fileName)
+
+	switch fileName {
+	case "Gemfile.lock":
+		parser := bundler.NewParser()
+		return parser.Parse(reader)
+	case "Gemfile":
+		// Gemfile is Ruby code, the resolved gems come from Gemfile.lock
+		return []ftypes.Package{}, []ftypes.Dependency{}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported Ruby file: %s", fileName)
+	}
 }
 
-func (p *Parser) extractMinVersion(pkg *ftypes.Package) string {
-	// For now, use version as min version
-	// In a more sophisticated implementation, we could parse version ranges
-	return pkg.Version
+// parseDotNetFileWithTrivy parses .NET dependencies using Trivy's NuGet parsers
+func (p *Parser) parseDotNetFileWithTrivy(
+	reader xio.ReadSeekerAt,
+	content []byte,
+	fileName string,
+) ([]ftypes.Package, []ftypes.Dependency, error) {
+	fileName = p.getFileName(fileName)
+
+	switch fileName {
+	case "packages.lock.json":
+		parser := nugetlock.NewParser()
+		return parser.Parse(reader)
+	case "packages.config":
+		parser := nugetconfig.NewParser()
+		return parser.Parse(reader)
+	case "Directory.Packages.props":
+		parser := packagesprops.NewParser()
+		return parser.Parse(reader)
+	case "*.csproj":
+		packages, err := csprojPackages(content)
+		return packages, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported .NET file: %s", fileName)
+	}
 }
 
-func (p *Parser) extractMaxVersion(pkg *ftypes.Package) string {
-	// For now, return empty
-	// In a more sophisticated implementation, we could parse version ranges
-	return ""
+// Helper methods
+
+// getFileName returns the base file name, resolved to the built-in parser file name for custom manifests
+// and to the built-in glob for variable file names (Billing.csproj is "*.csproj")
+func (p *Parser) getFileName(filePath string) string {
+	parts := strings.Split(filePath, "/")
+	fileName := parts[len(parts)-1]
+	if alias, ok := p.aliases[fileName]; ok {
+		return alias
+	}
+	for _, glob := range builtinGlobs {
+		if matched, err := path.Match(glob, fileName); err == nil && matched {
+			return glob
+		}
+	}
+	return fileName
 }
 
 func (p *Parser) isInternalDependency(name string) bool {
@@ -259,6 +463,12 @@ func (p *Parser) getEcosystem(language string) string {
 		return "maven"
 	case "python":
 		return "pip"
+	case "rust":
+		return "cargo"
+	case "ruby":
+		return "bundler"
+	case "dotnet":
+		return "nuget"
 	default:
 		return language
 	}
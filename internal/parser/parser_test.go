@@ -55,6 +55,55 @@ require (
 	}
 }
 
+func TestParser_ParseFile_GoModDirectives(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	goModContent := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/company/lib v0.3.0
+	github.com/pkg/errors v0.9.0
+	go.uber.org/zap v1.27.0
+)
+
+replace github.com/gin-gonic/gin => gitlab.company.com/forks/gin v1.9.1-fork.1
+
+replace github.com/company/lib => ../lib
+
+exclude github.com/pkg/errors v0.9.0
+`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "go.mod",
+		Language: "go",
+		Content:  []byte(goModContent),
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range deps {
+		byName[dep.Name] = dep
+	}
+
+	require.Contains(t, byName, "github.com/gin-gonic/gin")
+	assert.Equal(t, "v1.9.1-fork.1", byName["github.com/gin-gonic/gin"].Version)
+	assert.Equal(t, "gitlab.company.com/forks/gin v1.9.1-fork.1", byName["github.com/gin-gonic/gin"].ReplacedBy)
+
+	require.Contains(t, byName, "github.com/company/lib", "Local replacements stay in the report")
+	assert.Equal(t, "v0.3.0", byName["github.com/company/lib"].Version)
+	assert.Equal(t, "../lib", byName["github.com/company/lib"].ReplacedBy)
+
+	require.Contains(t, byName, "github.com/pkg/errors")
+	assert.Equal(t, "!=v0.9.0", byName["github.com/pkg/errors"].Constraint)
+
+	require.Contains(t, byName, "go.uber.org/zap")
+	assert.Empty(t, byName["go.uber.org/zap"].ReplacedBy)
+}
+
 func TestParser_ParseFile_PackageJson(t *testing.T) {
 	t.Parallel()
 
@@ -83,12 +132,67 @@ func TestParser_ParseFile_PackageJson(t *testing.T) {
 
 	deps, err := p.ParseFile(ctx, file)
 	require.NoError(t, err)
-	// package.json parser only returns the project itself, not individual dependencies
-	require.Len(t, deps, 1)
-	assert.Equal(t, "test-project", deps[0].Name)
-	assert.Equal(t, "1.0.0", deps[0].Version)
-	assert.Equal(t, "npm", deps[0].Ecosystem)
-	assert.False(t, deps[0].IsInternal)
+	// package.json yields the declared dependencies with their ranges
+	require.Len(t, deps, 3)
+	assert.Equal(t, "lodash", deps[0].Name)
+	assert.Equal(t, "4.17.21", deps[0].Version)
+	assert.Equal(t, "react", deps[1].Name)
+	assert.Equal(t, "^17.0.2", deps[1].Constraint)
+	assert.Equal(t, "jest", deps[2].Name)
+	for _, dep := range deps {
+		assert.Equal(t, "npm", dep.Ecosystem)
+		assert.False(t, dep.IsInternal)
+		assert.Empty(t, dep.Source)
+	}
+}
+
+func TestParser_ParseFile_PackageJsonSpecifiers(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	packageJSONContent := `{
+	"name": "web",
+	"dependencies": {
+		"foo": "npm:bar@^2.1.0",
+		"scoped": "npm:@Company/ui@1.4.0",
+		"lib": "git+ssh://git@gitlab.company.com:team/lib.git#semver:^1.2.0",
+		"tool": "company/tool#v3.0.0",
+		"shared": "file:../shared",
+		"utils": "workspace:^1.0.0"
+	}
+}`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "package.json",
+		Language: "nodejs",
+		Content:  []byte(packageJSONContent),
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range deps {
+		byName[dep.Name] = dep
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		source  string
+	}{
+		{"bar", "^2.1.0", "npm:bar@^2.1.0"},
+		{"@company/ui", "1.4.0", "npm:@Company/ui@1.4.0"},
+		{"lib", "^1.2.0", "git+ssh://git@gitlab.company.com:team/lib.git#semver:^1.2.0"},
+		{"tool", "v3.0.0", "company/tool#v3.0.0"},
+		{"shared", "", "file:../shared"},
+		{"utils", "^1.0.0", "workspace:^1.0.0"},
+	}
+
+	require.Len(t, byName, len(tests))
+	for _, tt := range tests {
+		require.Contains(t, byName, tt.name)
+		assert.Equal(t, tt.version, byName[tt.name].Version, tt.name)
+		assert.Equal(t, tt.source, byName[tt.name].Source, tt.name)
+	}
 }
 
 func TestParser_ParseFile_PackageLockJson(t *testing.T) {
@@ -153,6 +257,87 @@ func TestParser_ParseFile_PackageLockJson(t *testing.T) {
 	}
 }
 
+func TestParser_ParseFile_PnpmLock(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	ctx := context.Background()
+
+	pnpmLockContent := `lockfileVersion: '6.0'
+
+dependencies:
+  lodash:
+    specifier: ^4.17.0
+    version: 4.17.21
+
+packages:
+  /lodash@4.17.21:
+    resolution: {integrity: sha512-v2kDEe57lecTulaDIuNTPy3Ry4gLGJ6Z1O3vE1krgXZNrsQ+LFTGHVxVjcXPs17LhbZVGedAJv8XZ1tvj5FvSg==}
+    dev: false
+`
+
+	file := &domain.DependencyFile{
+		Path:         "web/pnpm-lock.yaml",
+		Language:     "nodejs",
+		Content:      []byte(pnpmLockContent),
+		LastModified: time.Now(),
+	}
+
+	deps, err := p.ParseFile(ctx, file)
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	assert.Equal(t, "lodash", deps[0].Name)
+	assert.Equal(t, "4.17.21", deps[0].Version)
+	assert.Equal(t, "npm", deps[0].Ecosystem)
+}
+
+func TestParser_ParseFile_TransitiveDependencies(t *testing.T) {
+	t.Parallel()
+
+	packageLockContent := `{
+	"name": "web",
+	"version": "1.0.0",
+	"lockfileVersion": 3,
+	"packages": {
+		"": {
+			"name": "web",
+			"version": "1.0.0",
+			"dependencies": {"express": "^4.18.0"}
+		},
+		"node_modules/express": {
+			"version": "4.18.2",
+			"dependencies": {"body-parser": "1.20.1", "qs": "6.11.0"}
+		},
+		"node_modules/body-parser": {
+			"version": "1.20.1",
+			"dependencies": {"qs": "6.11.0"}
+		},
+		"node_modules/qs": {
+			"version": "6.11.0"
+		}
+	}
+}`
+
+	deps, err := parser.NewParser().ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "package-lock.json",
+		Language: "nodejs",
+		Content:  []byte(packageLockContent),
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range deps {
+		byName[dep.Name] = dep
+	}
+	require.Len(t, byName, 3)
+	assert.True(t, byName["express"].Direct)
+	assert.Empty(t, byName["express"].Parents, "the project itself is not a parent")
+	assert.False(t, byName["body-parser"].Direct)
+	assert.Equal(t, []string{"express"}, byName["body-parser"].Parents)
+	assert.False(t, byName["qs"].Direct)
+	assert.ElementsMatch(t, []string{"express", "body-parser"}, byName["qs"].Parents)
+}
+
 func TestParser_ParseFile_PomXml(t *testing.T) {
 	t.Parallel()
 
@@ -199,7 +384,7 @@ func TestParser_ParseFile_PomXml(t *testing.T) {
 		depNames[i] = dep.Name
 	}
 
-	// At minimum, we should get spring-core (test scope dependencies might be excluded)
+	// At minimum, we should get spring-core, test scope dependencies are listed with their scope
 	expectedDeps := []string{
 		"org.springframework:spring-core",
 	}
@@ -283,12 +468,26 @@ func TestParser_CanParse(t *testing.T) {
 		"package.json",
 		"package-lock.json",
 		"yarn.lock",
+		"pnpm-lock.yaml",
+		"npm-shrinkwrap.json",
 		"pom.xml",
+		"build.gradle",
+		"build.gradle.kts",
+		"gradle.lockfile",
 		"requirements.txt",
 		"Pipfile",
+		"Pipfile.lock",
 		"poetry.lock",
 		"uv.lock",
 		"pyproject.toml",
+		"Cargo.toml",
+		"Cargo.lock",
+		"Gemfile",
+		"Gemfile.lock",
+		"src/Billing.Api/Billing.Api.csproj",
+		"packages.lock.json",
+		"packages.config",
+		"Directory.Packages.props",
 	}
 
 	for _, file := range supportedFiles {
@@ -325,3 +524,579 @@ func TestParser_ParseFile_EmptyContent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, deps)
 }
+
+func TestParser_ParseFile_CargoToml(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	cargoTomlContent := `[package]
+name = "billing"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio = "1.38"
+json = { package = "serde_json", version = "1.0.120" }
+ledger = { git = "https://gitlab.company.com/team/ledger.git", tag = "v2.1.0" }
+shared = { path = "../shared" }
+anyhow = { workspace = true }
+
+[dev-dependencies]
+tokio = { version = "1.38", features = ["test-util"] }
+proptest = "*"
+
+[target.'cfg(unix)'.dependencies]
+nix = "0.29"
+
+[workspace.dependencies]
+log = "0.4"
+`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "billing/Cargo.toml",
+		Language: "rust",
+		Content:  []byte(cargoTomlContent),
+	})
+	require.NoError(t, err)
+
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range deps {
+		assert.Equal(t, "cargo", dep.Ecosystem)
+		byName[dep.Name] = dep
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		source  string
+	}{
+		{"serde", "1.0", ""},
+		{"tokio", "1.38", ""},
+		{"serde_json", "1.0.120", ""},
+		{"ledger", "v2.1.0", "git+https://gitlab.company.com/team/ledger.git"},
+		{"shared", "", "file:../shared"},
+		{"proptest", "*", ""},
+		{"nix", "0.29", ""},
+		{"log", "0.4", ""},
+	}
+
+	// Workspace inherited dependencies are declared by the workspace root, duplicates are listed once
+	require.Len(t, deps, len(tests))
+	for _, tt := range tests {
+		require.Contains(t, byName, tt.name)
+		assert.Equal(t, tt.version, byName[tt.name].Version, tt.name)
+		assert.Equal(t, tt.source, byName[tt.name].Source, tt.name)
+	}
+}
+
+func TestParser_ParseFile_CargoLock(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	cargoLockContent := `version = 3
+
+[[package]]
+name = "billing"
+version = "0.1.0"
+dependencies = [
+ "ledger",
+ "serde",
+]
+
+[[package]]
+name = "ledger"
+version = "2.1.0"
+source = "git+https://gitlab.company.com/team/ledger.git?tag=v2.1.0#4f1c2d3"
+
+[[package]]
+name = "serde"
+version = "1.0.203"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "7253ab4de971e72fb7be983802300c30b5a7f0c2e56fab8abfc6a214307c0094"
+`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "Cargo.lock",
+		Language: "rust",
+		Content:  []byte(cargoLockContent),
+	})
+	require.NoError(t, err)
+
+	// The workspace crate itself has no source and is not a dependency
+	require.Len(t, deps, 2)
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range deps {
+		assert.Equal(t, "cargo", dep.Ecosystem)
+		byName[dep.Name] = dep
+	}
+
+	require.Contains(t, byName, "serde")
+	assert.Equal(t, "1.0.203", byName["serde"].Version)
+	assert.Empty(t, byName["serde"].Source)
+
+	require.Contains(t, byName, "ledger")
+	assert.Equal(t, "2.1.0", byName["ledger"].Version)
+	assert.Equal(t, "git+https://gitlab.company.com/team/ledger.git?tag=v2.1.0#4f1c2d3", byName["ledger"].Source)
+}
+
+func TestParser_ParseFile_GemfileLock(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	gemfileLockContent := `GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.16.6-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.8.0)
+    rack (3.1.3)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri
+  rack (~> 3.1)
+
+BUNDLED WITH
+   2.5.11
+`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "Gemfile.lock",
+		Language: "ruby",
+		Content:  []byte(gemfileLockContent),
+	})
+	require.NoError(t, err)
+
+	versions := make(map[string]string)
+	for _, dep := range deps {
+		assert.Equal(t, "bundler", dep.Ecosystem)
+		versions[dep.Name] = dep.Version
+	}
+	// Platform suffixes are dropped from versions
+	assert.Equal(t, map[string]string{"nokogiri": "1.16.6", "racc": "1.8.0", "rack": "3.1.3"}, versions)
+
+	deps, err = p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "Gemfile",
+		Language: "ruby",
+		Content:  []byte("source 'https://rubygems.org'\n\ngem 'rack', '~> 3.1'\n"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestParser_ParseFile_Csproj(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	csprojContent := `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageReference Include="Serilog">
+      <Version>4.0.0</Version>
+    </PackageReference>
+    <PackageReference Include="Polly" VersionOverride="8.4.1" />
+    <PackageReference Include="Dapper" />
+    <PackageReference Include="Npgsql" Version="$(NpgsqlVersion)" />
+  </ItemGroup>
+  <ItemGroup Condition="'$(Configuration)' == 'Debug'">
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+  </ItemGroup>
+</Project>`
+
+	deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+		Path:     "src/Billing.Api/Billing.Api.csproj",
+		Language: "dotnet",
+		Content:  []byte(csprojContent),
+	})
+	require.NoError(t, err)
+
+	versions := make(map[string]string)
+	for _, dep := range deps {
+		assert.Equal(t, "nuget", dep.Ecosystem)
+		versions[dep.Name] = dep.Version
+	}
+	// Centrally managed and property versions are not resolvable from the project file
+	assert.Equal(t, map[string]string{"newtonsoft.json": "13.0.3", "serilog": "4.0.0", "polly": "8.4.1"}, versions)
+}
+
+func TestParser_ParseFile_NuGet(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	tests := []struct {
+		path     string
+		content  string
+		expected map[string]string
+	}{
+		{
+			path: "packages.lock.json",
+			content: `{
+  "version": 1,
+  "dependencies": {
+    "net8.0": {
+      "Serilog": {"type": "Direct", "requested": "[4.0.0, )", "resolved": "4.0.0"},
+      "Billing.Domain": {"type": "Project"}
+    }
+  }
+}`,
+			expected: map[string]string{"serilog": "4.0.0"},
+		},
+		{
+			path: "legacy/packages.config",
+			content: `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="EntityFramework" version="6.4.4" targetFramework="net48" />
+</packages>`,
+			expected: map[string]string{"entityframework": "6.4.4"},
+		},
+		{
+			path: "Directory.Packages.props",
+			content: `<Project>
+  <ItemGroup>
+    <PackageVersion Include="Dapper" Version="2.1.35" />
+  </ItemGroup>
+</Project>`,
+			expected: map[string]string{"dapper": "2.1.35"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+				Path:     tt.path,
+				Language: "dotnet",
+				Content:  []byte(tt.content),
+			})
+			require.NoError(t, err)
+
+			versions := make(map[string]string)
+			for _, dep := range deps {
+				versions[dep.Name] = dep.Version
+			}
+			assert.Equal(t, tt.expected, versions)
+		})
+	}
+}
+
+func TestParser_ParseFile_BuildGradle(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	tests := []struct {
+		path     string
+		content  string
+		expected map[string]string
+	}{
+		{
+			path: "build.gradle",
+			content: `ext {
+    jacksonVersion = '2.17.1'
+}
+def guavaVersion = "33.2.1-jre"
+
+dependencies {
+    implementation platform('org.springframework.boot:spring-boot-dependencies:3.3.0')
+    implementation 'org.springframework.boot:spring-boot-starter-web'
+    implementation "com.fasterxml.jackson.core:jackson-databind:${jacksonVersion}"
+    implementation("com.google.guava:guava:$guavaVersion")
+    api group: 'org.apache.commons', name: 'commons-lang3', version: '3.14.0'
+    compileOnly 'org.projectlombok:lombok:1.18.32'
+    testImplementation 'org.junit.jupiter:junit-jupiter:5.+'
+    runtimeOnly "org.postgresql:postgresql:$postgresVersion"
+    implementation project(':core')
+    implementation libs.okhttp
+    // implementation 'commented:out:1.0'
+}`,
+			expected: map[string]string{
+				"org.springframework.boot:spring-boot-dependencies": "3.3.0",
+				"com.fasterxml.jackson.core:jackson-databind":       "2.17.1",
+				"com.google.guava:guava":                            "33.2.1-jre",
+				"org.apache.commons:commons-lang3":                  "3.14.0",
+				"org.projectlombok:lombok":                          "1.18.32",
+				"org.junit.jupiter:junit-jupiter":                   "5.+",
+			},
+		},
+		{
+			path: "app/build.gradle.kts",
+			content: `val ktorVersion = "2.3.11"
+
+dependencies {
+    implementation(platform("io.ktor:ktor-bom:$ktorVersion"))
+    implementation("io.ktor:ktor-server-core-jvm:$ktorVersion")
+    implementation(group = "ch.qos.logback", name = "logback-classic", version = "1.5.6")
+    debugImplementation("com.squareup.leakcanary:leakcanary-android:2.14")
+}`,
+			expected: map[string]string{
+				"io.ktor:ktor-bom":                           "2.3.11",
+				"io.ktor:ktor-server-core-jvm":               "2.3.11",
+				"ch.qos.logback:logback-classic":             "1.5.6",
+				"com.squareup.leakcanary:leakcanary-android": "2.14",
+			},
+		},
+		{
+			path: "gradle.lockfile",
+			content: `# This is a Gradle generated file for dependency locking.
+com.google.guava:guava:33.2.1-jre=compileClasspath,runtimeClasspath
+org.junit.jupiter:junit-jupiter:5.10.2=testCompileClasspath
+empty=annotationProcessor
+`,
+			expected: map[string]string{
+				"com.google.guava:guava":          "33.2.1-jre",
+				"org.junit.jupiter:junit-jupiter": "5.10.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+				Path:     tt.path,
+				Language: "java",
+				Content:  []byte(tt.content),
+			})
+			require.NoError(t, err)
+
+			versions := make(map[string]string)
+			for _, dep := range deps {
+				assert.Equal(t, "maven", dep.Ecosystem)
+				versions[dep.Name] = dep.Version
+			}
+			assert.Equal(t, tt.expected, versions)
+		})
+	}
+}
+
+func TestParser_ParseFile_DependencyScopes(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	tests := []struct {
+		path     string
+		language string
+		content  string
+		expected map[string]string
+	}{
+		{
+			path:     "package.json",
+			language: "nodejs",
+			content: `{
+	"dependencies": {"react": "^18.2.0", "typescript": "^5.4.0"},
+	"devDependencies": {"jest": "^29.0.0", "typescript": "^5.4.0"},
+	"optionalDependencies": {"fsevents": "^2.3.0"}
+}`,
+			expected: map[string]string{
+				"react": "runtime", "typescript": "runtime", "jest": "dev", "fsevents": "optional",
+			},
+		},
+		{
+			path:     "package-lock.json",
+			language: "nodejs",
+			content: `{
+	"name": "web",
+	"lockfileVersion": 3,
+	"packages": {
+		"": {"name": "web", "dependencies": {"react": "^18.2.0"}, "devDependencies": {"jest": "^29.0.0"}},
+		"node_modules/react": {"version": "18.2.0"},
+		"node_modules/jest": {"version": "29.7.0", "dev": true}
+	}
+}`,
+			expected: map[string]string{"react": "runtime", "jest": "dev"},
+		},
+		{
+			path:     "pom.xml",
+			language: "java",
+			content: `<project xmlns="http://maven.apache.org/POM/4.0.0">
+	<groupId>com.example</groupId>
+	<artifactId>billing</artifactId>
+	<version>1.0.0</version>
+	<properties>
+		<junit.version>5.10.2</junit.version>
+	</properties>
+	<dependencies>
+		<dependency>
+			<groupId>org.slf4j</groupId>
+			<artifactId>slf4j-api</artifactId>
+			<version>2.0.13</version>
+		</dependency>
+		<dependency>
+			<groupId>org.junit.jupiter</groupId>
+			<artifactId>junit-jupiter</artifactId>
+			<version>${junit.version}</version>
+			<scope>test</scope>
+		</dependency>
+		<dependency>
+			<groupId>com.h2database</groupId>
+			<artifactId>h2</artifactId>
+			<version>2.2.224</version>
+			<optional>true</optional>
+		</dependency>
+	</dependencies>
+</project>`,
+			expected: map[string]string{
+				"com.example:billing": "runtime", "org.slf4j:slf4j-api": "runtime",
+				"org.junit.jupiter:junit-jupiter": "test", "com.h2database:h2": "optional",
+			},
+		},
+		{
+			path:     "build.gradle",
+			language: "java",
+			content: `dependencies {
+    implementation 'com.google.guava:guava:33.2.1-jre'
+    annotationProcessor 'org.projectlombok:lombok:1.18.32'
+    testImplementation 'org.junit.jupiter:junit-jupiter:5.10.2'
+    androidTestImplementation 'androidx.test:runner:1.5.2'
+}`,
+			expected: map[string]string{
+				"com.google.guava:guava": "runtime", "org.projectlombok:lombok": "dev",
+				"org.junit.jupiter:junit-jupiter": "test", "androidx.test:runner": "test",
+			},
+		},
+		{
+			path:     "pyproject.toml",
+			language: "python",
+			content: `[project]
+name = "billing"
+dependencies = ["requests>=2.31"]
+
+[project.optional-dependencies]
+socks = ["PySocks>=1.7; python_version > '3.8'", "requests[socks]"]
+
+[dependency-groups]
+test = ["pytest>=8", {include-group = "lint"}]
+lint = ["ruff"]
+`,
+			expected: map[string]string{"requests": "runtime", "pysocks": "optional", "pytest": "dev", "ruff": "dev"},
+		},
+		{
+			path:     "Cargo.toml",
+			language: "rust",
+			content: `[dependencies]
+serde = "1.0"
+
+[dev-dependencies]
+criterion = "0.5"
+serde = "1.0"
+
+[build-dependencies]
+cc = "1.0"
+`,
+			expected: map[string]string{"serde": "runtime", "criterion": "dev", "cc": "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+				Path:     tt.path,
+				Language: tt.language,
+				Content:  []byte(tt.content),
+			})
+			require.NoError(t, err)
+
+			scopes := make(map[string]string)
+			for _, dep := range deps {
+				scopes[dep.Name] = dep.Scope
+			}
+			assert.Equal(t, tt.expected, scopes)
+		})
+	}
+}
+
+func TestParser_ParseFile_Constraints(t *testing.T) {
+	t.Parallel()
+
+	p := parser.NewParser()
+	tests := []struct {
+		path     string
+		language string
+		content  string
+		expected map[string][3]string // constraint, min version, max version
+	}{
+		{
+			path:     "package.json",
+			language: "nodejs",
+			content:  `{"dependencies": {"react": "^18.2.0", "lodash": "4.17.21", "express": ">=4.18.0 <5", "left-pad": "*"}}`,
+			expected: map[string][3]string{
+				"react":    {"^18.2.0", "18.2.0", "19.0.0"},
+				"lodash":   {"4.17.21", "4.17.21", "4.17.21"},
+				"express":  {">=4.18.0 <5", "4.18.0", "5"},
+				"left-pad": {"*", "", ""},
+			},
+		},
+		{
+			path:     "pyproject.toml",
+			language: "python",
+			content: `[project]
+name = "billing"
+dependencies = ["requests (>=2,<3)", "numpy~=1.21", "attrs"]
+
+[dependency-groups]
+test = ["pytest==8.2.*"]
+`,
+			expected: map[string][3]string{
+				"requests": {">=2,<3", "2", "3"},
+				"numpy":    {"~=1.21", "1.21", "2.0.0"},
+				"attrs":    {"", "", ""},
+				"pytest":   {"==8.2.*", "8.2", "8.3.0"},
+			},
+		},
+		{
+			path:     "Cargo.toml",
+			language: "rust",
+			content: `[dependencies]
+serde = "1.0"
+tokio = { version = "=1.38.0" }
+rand = "0.8.5"
+`,
+			expected: map[string][3]string{
+				"serde": {"^1.0", "1.0", "2.0.0"},
+				"tokio": {"=1.38.0", "1.38.0", "1.38.0"},
+				"rand":  {"^0.8.5", "0.8.5", "0.9.0"},
+			},
+		},
+		{
+			path:     "go.mod",
+			language: "go",
+			content: `module example.com/app
+
+go 1.22
+
+require github.com/gin-gonic/gin v1.9.1
+`,
+			expected: map[string][3]string{
+				"example.com/app":          {"", "", ""},
+				"github.com/gin-gonic/gin": {"v1.9.1", "v1.9.1", "v1.9.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			deps, err := p.ParseFile(context.Background(), &domain.DependencyFile{
+				Path:     tt.path,
+				Language: tt.language,
+				Content:  []byte(tt.content),
+			})
+			require.NoError(t, err)
+
+			constraints := make(map[string][3]string)
+			for _, dep := range deps {
+				constraints[dep.Name] = [3]string{dep.Constraint, dep.MinVersion, dep.MaxVersion}
+			}
+			assert.Equal(t, tt.expected, constraints)
+		})
+	}
+}
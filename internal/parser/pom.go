@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"encoding/xml"
+	"regexp"
+	"strings"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// pomPropertyReference matches ${name} references in pom.xml values
+//
+//nolint:gochecknoglobals // compiled once
+var pomPropertyReference = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// pomManifest is the part of a pom.xml declaring dependencies
+type pomManifest struct {
+	Version string `xml:"version"`
+	Parent  struct {
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Dependencies []struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Scope      string `xml:"scope"`
+		Optional   string `xml:"optional"`
+	} `xml:"dependencies>dependency"`
+}
+
+// pomScopedPackages lists the test scoped and optional dependencies of a pom.xml, which Trivy leaves out.
+// Only versions written in the pom.xml itself or in its properties are understood, dependencies with
+// versions managed by a parent POM or a BOM are skipped.
+func pomScopedPackages(content []byte) []ftypes.Package {
+	manifest, ok := decodePOM(content)
+	if !ok {
+		return nil
+	}
+
+	projectVersion := manifest.Version
+	if projectVersion == "" {
+		projectVersion = manifest.Parent.Version
+	}
+	properties := map[string]string{"project.version": projectVersion, "project.parent.version": manifest.Parent.Version}
+	for _, entry := range manifest.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+
+	var packages []ftypes.Package
+	for _, dep := range manifest.Dependencies {
+		if pomScope(dep.Scope, dep.Optional) == domain.ScopeRuntime {
+			continue
+		}
+		version, resolved := strings.TrimSpace(dep.Version), true
+		version = pomPropertyReference.ReplaceAllStringFunc(version, func(reference string) string {
+			value, ok := properties[pomPropertyReference.FindStringSubmatch(reference)[1]]
+			resolved = resolved && ok && value != ""
+			return value
+		})
+		if !resolved || version == "" {
+			continue
+		}
+		name := strings.TrimSpace(dep.GroupID) + ":" + strings.TrimSpace(dep.ArtifactID)
+		packages = append(packages, ftypes.Package{Name: name, Version: version})
+	}
+	return packages
+}
+
+// pomScopes returns the scopes of the test scoped and optional dependencies of a pom.xml
+func pomScopes(content []byte) map[string]string {
+	manifest, ok := decodePOM(content)
+	if !ok {
+		return nil
+	}
+
+	scopes := make(map[string]string)
+	for _, dep := range manifest.Dependencies {
+		if scope := pomScope(dep.Scope, dep.Optional); scope != domain.ScopeRuntime {
+			scopes[strings.TrimSpace(dep.GroupID)+":"+strings.TrimSpace(dep.ArtifactID)] = scope
+		}
+	}
+	return scopes
+}
+
+// pomScope maps a Maven scope to a dependency scope, runtime for the scopes Trivy handles itself
+func pomScope(scope, optional string) string {
+	switch {
+	case strings.TrimSpace(optional) == "true":
+		return domain.ScopeOptional
+	case strings.TrimSpace(scope) == "test":
+		return domain.ScopeTest
+	default:
+		return domain.ScopeRuntime
+	}
+}
+
+// decodePOM decodes the dependencies of a pom.xml, reporting false for malformed files
+func decodePOM(content []byte) (pomManifest, bool) {
+	var manifest pomManifest
+	if err := xml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return pomManifest{}, false
+	}
+	return manifest, true
+}
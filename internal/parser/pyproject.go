@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// pyprojectManifest is the part of a pyproject.toml declaring dependencies outside the main ones
+type pyprojectManifest struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"` // Extras
+	} `toml:"project"`
+	DependencyGroups map[string][]any `toml:"dependency-groups"` // PEP 735, entries are requirements or includes
+	Tool             struct {
+		Poetry struct {
+			Dependencies map[string]any `toml:"dependencies"`
+			Groups       map[string]struct {
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// pyprojectScopes returns the scopes of packages only declared as extras (optional) or in dependency groups
+// and Poetry groups (dev), keyed by the name they are declared with
+func pyprojectScopes(content []byte) map[string]string {
+	var manifest pyprojectManifest
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return nil
+	}
+
+	runtime := make(map[string]bool)
+	for _, requirement := range manifest.Project.Dependencies {
+		runtime[NormalizeName("pip", requirementName(requirement))] = true
+	}
+	for name := range manifest.Tool.Poetry.Dependencies {
+		runtime[NormalizeName("pip", name)] = true
+	}
+
+	scopes := make(map[string]string)
+	add := func(name, scope string) {
+		if _, declared := scopes[name]; !declared && !runtime[NormalizeName("pip", name)] {
+			scopes[name] = scope
+		}
+	}
+	for _, requirements := range manifest.Project.OptionalDependencies {
+		for _, requirement := range requirements {
+			add(requirementName(requirement), domain.ScopeOptional)
+		}
+	}
+	for _, entries := range manifest.DependencyGroups {
+		for _, entry := range entries {
+			if requirement, ok := entry.(string); ok {
+				add(requirementName(requirement), domain.ScopeDev)
+			}
+		}
+	}
+	for _, group := range manifest.Tool.Poetry.Groups {
+		for name := range group.Dependencies {
+			add(name, domain.ScopeDev)
+		}
+	}
+	return scopes
+}
+
+// pyprojectConstraints returns the version specifiers of the requirements and Poetry dependencies of a
+// pyproject.toml, keyed by normalized package name. Requirements without a specifier are left out.
+func pyprojectConstraints(ecosystem string, content []byte) map[string]string {
+	var manifest pyprojectManifest
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return nil
+	}
+
+	constraints := make(map[string]string)
+	addRequirement := func(requirement string) {
+		if specifier := requirementSpecifier(requirement); specifier != "" {
+			constraints[NormalizeName(ecosystem, requirementName(requirement))] = specifier
+		}
+	}
+	addPoetry := func(dependencies map[string]any) {
+		for name, value := range dependencies {
+			if specifier := poetryVersion(value); specifier != "" {
+				constraints[NormalizeName(ecosystem, name)] = specifier
+			}
+		}
+	}
+
+	for _, requirements := range manifest.Project.OptionalDependencies {
+		for _, requirement := range requirements {
+			addRequirement(requirement)
+		}
+	}
+	for _, entries := range manifest.DependencyGroups {
+		for _, entry := range entries {
+			if requirement, ok := entry.(string); ok {
+				addRequirement(requirement)
+			}
+		}
+	}
+	for _, group := range manifest.Tool.Poetry.Groups {
+		addPoetry(group.Dependencies)
+	}
+	// Main dependencies last, they win over the same package in extras and groups
+	addPoetry(manifest.Tool.Poetry.Dependencies)
+	for _, requirement := range manifest.Project.Dependencies {
+		addRequirement(requirement)
+	}
+	return constraints
+}
+
+// poetryVersion returns the version constraint of a Poetry dependency, written as a string or as a table
+// with a version key
+func poetryVersion(value any) string {
+	switch dependency := value.(type) {
+	case string:
+		return strings.TrimSpace(dependency)
+	case map[string]any:
+		version, _ := dependency["version"].(string)
+		return strings.TrimSpace(version)
+	default:
+		return ""
+	}
+}
+
+// pyprojectScopedPackages lists the packages of pyprojectScopes without versions, like the main dependencies
+func pyprojectScopedPackages(scopes map[string]string) []ftypes.Package {
+	names := make([]string, 0, len(scopes))
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	packages := make([]ftypes.Package, 0, len(names))
+	for _, name := range names {
+		packages = append(packages, ftypes.Package{Name: name})
+	}
+	return packages
+}
+
+// requirementName returns the package name of a PEP 508 requirement such as
+// "requests[socks] >= 2.31; python_version > '3.8'"
+func requirementName(requirement string) string {
+	fields := strings.Fields(strings.NewReplacer(">", " ", "<", " ", "=", " ", "!", " ", "~", " ", "(", " ", "[", " ",
+		";", " ", "@", " ").Replace(requirement))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// requirementSpecifier returns the version specifier of a PEP 508 requirement, ">= 2.31" for
+// "requests[socks] (>= 2.31); python_version > '3.8'", or "" when it has none or points at a URL
+func requirementSpecifier(requirement string) string {
+	requirement, _, _ = strings.Cut(requirement, ";")
+	if strings.Contains(requirement, "@") {
+		return ""
+	}
+
+	specifier := strings.TrimSpace(requirement)
+	specifier = strings.TrimSpace(strings.TrimPrefix(specifier, requirementName(specifier)))
+	if strings.HasPrefix(specifier, "[") {
+		_, specifier, _ = strings.Cut(specifier, "]")
+	}
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(specifier), "()"))
+}
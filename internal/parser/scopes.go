@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"di-matrix-cli/internal/domain"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// declaredScopes returns the scopes of the dependencies a manifest declares outside its runtime dependencies,
+// keyed by package name. Trivy's lockfile parsers flag development packages themselves.
+func declaredScopes(fileName string, content []byte) map[string]string {
+	switch fileName {
+	case "package.json":
+		return npmScopes(content)
+	case "pom.xml":
+		return pomScopes(content)
+	case "build.gradle", "build.gradle.kts":
+		return gradleScopes(content)
+	case "pyproject.toml":
+		return pyprojectScopes(content)
+	case "Cargo.toml":
+		return cargoScopes(content)
+	default:
+		return nil
+	}
+}
+
+// dependencyScope returns the scope of a package: the one its manifest declares,
+// dev for development packages of lockfiles and runtime otherwise
+func dependencyScope(pkg *ftypes.Package, scopes map[string]string) string {
+	if scope, ok := scopes[pkg.Name]; ok {
+		return scope
+	}
+	if pkg.Dev {
+		return domain.ScopeDev
+	}
+	return domain.ScopeRuntime
+}
@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/version"
+	"fmt"
+	"strings"
+)
+
+const (
+	// RuleDenied is reported for dependencies matching an entry of the denylist
+	RuleDenied = "dependency.denied"
+	// RuleNotAllowed is reported for external dependencies matching no entry of the allowlist
+	RuleNotAllowed = "dependency.not_allowed"
+)
+
+// ListEntry is a denylist or allowlist entry: a dependency name pattern, optionally restricted to a version range
+type ListEntry struct {
+	Entry      string // As configured, "lodash@<4.17.21"
+	Constraint string // Version range, empty for every version
+	names      *exclude.Matcher
+}
+
+// ParseListEntry parses "name" or "name@range". Names are globs ("github.com/acme/*") or regular expressions
+// enclosed in slashes, as dependency exclusions; the range uses the constraint syntax of the ecosystems
+// ("<4.17.21", ">=2.0, <2.17.1"). The leading "@" of npm scopes is part of the name.
+func ParseListEntry(entry string) (ListEntry, error) {
+	entry = strings.TrimSpace(entry)
+	name, constraint := entry, ""
+	at := strings.LastIndex(entry, "@")
+	ranged := at > 0 && !strings.HasSuffix(entry, "/")
+	if ranged {
+		name, constraint = strings.TrimSpace(entry[:at]), strings.TrimSpace(entry[at+1:])
+	}
+	if name == "" {
+		return ListEntry{}, fmt.Errorf("invalid entry %q: missing dependency name", entry)
+	}
+	if ranged && version.ParseConstraint(constraint) == nil {
+		return ListEntry{}, fmt.Errorf("invalid entry %q: %q is not a version range", entry, constraint)
+	}
+
+	names, err := exclude.Compile([]string{name})
+	if err != nil {
+		return ListEntry{}, fmt.Errorf("invalid entry %q: %w", entry, err)
+	}
+	return ListEntry{Entry: entry, Constraint: constraint, names: names}, nil
+}
+
+// Matches reports whether the dependency has a matching name and a version within the range. Dependencies
+// without a resolved version only match entries covering every version.
+func (e ListEntry) Matches(dep *domain.Dependency) bool {
+	if !e.names.Matches(dep.Name) {
+		return false
+	}
+	return e.Constraint == "" || version.Satisfies(dep.Version, e.Constraint)
+}
+
+// ListCheck enforces the dependency denylist and allowlist
+type ListCheck struct {
+	denylist  []ListEntry
+	allowlist []ListEntry
+}
+
+// NewListCheck creates a list policy check. Every dependency matching a denylist entry is a violation; with an
+// allowlist, every external dependency matching none of its entries is one too.
+func NewListCheck(denylist, allowlist []string) (*ListCheck, error) {
+	check := &ListCheck{}
+	for _, list := range []struct {
+		entries []string
+		target  *[]ListEntry
+		name    string
+	}{
+		{denylist, &check.denylist, "denylist"},
+		{allowlist, &check.allowlist, "allowlist"},
+	} {
+		for _, raw := range list.entries {
+			entry, err := ParseListEntry(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", list.name, err)
+			}
+			*list.target = append(*list.target, entry)
+		}
+	}
+	return check, nil
+}
+
+// Name returns the rule family enforced by this check
+func (c *ListCheck) Name() string {
+	return "lists"
+}
+
+// Evaluate returns violations for denied dependencies and external dependencies missing from the allowlist
+func (c *ListCheck) Evaluate(ctx context.Context, projects []*domain.Project) []domain.PolicyViolation {
+	var violations []domain.PolicyViolation
+
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if entry, ok := firstMatch(c.denylist, dep); ok {
+				violations = append(violations, domain.PolicyViolation{
+					Rule:       RuleDenied,
+					ProjectID:  project.ID,
+					Dependency: dep.Name,
+					Message:    fmt.Sprintf("%s is denied by %q", describe(dep), entry.Entry),
+				})
+				continue
+			}
+
+			if len(c.allowlist) == 0 || dep.IsInternal {
+				continue
+			}
+			if _, ok := firstMatch(c.allowlist, dep); !ok {
+				violations = append(violations, domain.PolicyViolation{
+					Rule:       RuleNotAllowed,
+					ProjectID:  project.ID,
+					Dependency: dep.Name,
+					Message:    fmt.Sprintf("%s is not on the allowlist", describe(dep)),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// firstMatch returns the first entry matching the dependency
+func firstMatch(entries []ListEntry, dep *domain.Dependency) (ListEntry, bool) {
+	for _, entry := range entries {
+		if entry.Matches(dep) {
+			return entry, true
+		}
+	}
+	return ListEntry{}, false
+}
+
+// describe names the dependency with its version, when resolved
+func describe(dep *domain.Dependency) string {
+	if dep.Version == "" {
+		return dep.Name
+	}
+	return dep.Name + " " + dep.Version
+}
@@ -0,0 +1,83 @@
+package policy_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/policy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		entry      string
+		dependency domain.Dependency
+		expected   bool
+	}{
+		{"lodash", domain.Dependency{Name: "lodash", Version: "4.17.21"}, true},
+		{"lodash@<4.17.21", domain.Dependency{Name: "lodash", Version: "4.17.20"}, true},
+		{"lodash@<4.17.21", domain.Dependency{Name: "lodash", Version: "4.17.21"}, false},
+		{"lodash@<4.17.21", domain.Dependency{Name: "lodash"}, false},
+		{"@types/*", domain.Dependency{Name: "@types/node", Version: "20.1.0"}, true},
+		{"@types/node@>=20", domain.Dependency{Name: "@types/node", Version: "20.1.0"}, true},
+		{"org.apache.logging.log4j:log4j-core@>=2.0, <2.17.1",
+			domain.Dependency{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"}, true},
+		{"/^github\\.com/pkg/.*$/", domain.Dependency{Name: "github.com/pkg/errors", Version: "v0.9.1"}, true},
+		{"/^github\\.com/pkg/.*$/@<0.9", domain.Dependency{Name: "github.com/pkg/errors", Version: "v0.9.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			t.Parallel()
+			entry, err := policy.ParseListEntry(tt.entry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, entry.Matches(&tt.dependency))
+		})
+	}
+
+	for _, invalid := range []string{"", "lodash@", "lodash@not a range", "[abc"} {
+		_, err := policy.ParseListEntry(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestListCheck_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	projects := []*domain.Project{
+		{
+			ID:   "web",
+			Name: "web",
+			Dependencies: []*domain.Dependency{
+				{Name: "lodash", Version: "4.17.20"},
+				{Name: "react", Version: "18.2.0"},
+				{Name: "left-pad", Version: "1.3.0"},
+				{Name: "@acme/ui", Version: "2.0.0", IsInternal: true},
+			},
+		},
+	}
+
+	_, err := policy.NewListCheck([]string{"lodash@nope"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "denylist")
+
+	check, err := policy.NewListCheck([]string{"lodash@<4.17.21"}, []string{"lodash", "react@^18"})
+	require.NoError(t, err)
+	violations := check.Evaluate(context.Background(), projects)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, policy.RuleDenied, violations[0].Rule, "denied even though allowed")
+	assert.Equal(t, "lodash", violations[0].Dependency)
+	assert.Equal(t, "lodash 4.17.20 is denied by \"lodash@<4.17.21\"", violations[0].Message)
+	assert.Equal(t, policy.RuleNotAllowed, violations[1].Rule)
+	assert.Equal(t, "left-pad", violations[1].Dependency, "internal dependencies need no allowlist entry")
+	assert.Equal(t, "web", violations[1].ProjectID)
+
+	denyOnly, err := policy.NewListCheck([]string{"left-pad"}, nil)
+	require.NoError(t, err)
+	assert.Len(t, denyOnly.Evaluate(context.Background(), projects), 1, "without allowlist everything else is allowed")
+}
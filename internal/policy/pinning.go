@@ -0,0 +1,158 @@
+package policy
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// RuleFloating is reported for dependencies declared without a reproducible version
+	RuleFloating = "pinning.floating"
+	// RuleMissingLockfile is reported for projects whose ecosystem requires a lockfile but has none
+	RuleMissingLockfile = "pinning.lockfile"
+)
+
+// lockfiles lists, per language, the files that lock resolved versions, all of them collected by the scanner.
+// Languages missing from this map pin versions in the manifest itself (go.mod, pom.xml).
+var lockfiles = map[string][]string{ //nolint:gochecknoglobals // Read-only lookup table
+	"nodejs": {"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "npm-shrinkwrap.json"},
+	"python": {"poetry.lock", "uv.lock", "Pipfile.lock"},
+	"rust":   {"Cargo.lock"},
+	"ruby":   {"Gemfile.lock"},
+}
+
+// floatingKeywords are version specifiers that always resolve to whatever is newest
+var floatingKeywords = map[string]bool{ //nolint:gochecknoglobals // Read-only lookup table
+	"":        true,
+	"*":       true,
+	"x":       true,
+	"latest":  true,
+	"next":    true,
+	"release": true,
+	// Gradle dynamic versions
+	"+":                  true,
+	"latest.release":     true,
+	"latest.integration": true,
+}
+
+// IsFloatingConstraint reports whether a declared version constraint is not reproducible:
+// empty, a "latest"-style keyword, a wildcard, or a range without an upper bound
+func IsFloatingConstraint(constraint string) bool {
+	c := strings.ToLower(strings.TrimSpace(constraint))
+	if floatingKeywords[c] {
+		return true
+	}
+
+	// Gradle prefix versions such as "1.+"
+	if strings.HasSuffix(c, ".+") {
+		return true
+	}
+
+	// Maven open-ended ranges such as "[1.0,)"
+	if strings.HasSuffix(c, ",)") || strings.HasSuffix(c, ",]") {
+		return true
+	}
+
+	// Comparator ranges such as ">=1.2" with no "<" upper bound
+	if strings.Contains(c, ">") && !strings.Contains(c, "<") {
+		return true
+	}
+
+	return false
+}
+
+// RequiresLockfile reports whether projects of the given language need a lockfile to be reproducible
+func RequiresLockfile(language string) bool {
+	_, ok := lockfiles[language]
+	return ok
+}
+
+// HasLockfile reports whether the project contains a lockfile for its language
+func HasLockfile(project *domain.Project) bool {
+	names, ok := lockfiles[project.Language]
+	if !ok {
+		return true
+	}
+
+	for _, file := range project.DependencyFiles {
+		base := filepath.Base(file.Path)
+		for _, name := range names {
+			if base == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AnnotatePinning marks floating dependencies and lockfile presence on the project
+func AnnotatePinning(project *domain.Project) {
+	project.HasLockfile = HasLockfile(project)
+	for _, dep := range project.Dependencies {
+		dep.IsFloating = fromRegistry(project, dep) && IsFloatingConstraint(dep.Constraint)
+	}
+}
+
+// fromRegistry reports whether the dependency is resolved from a package registry by its constraint.
+// The project's own module entry (go.mod lists it without a version) and packages installed from a git URL,
+// a local path or a replace directive carry no registry constraint.
+func fromRegistry(project *domain.Project, dep *domain.Dependency) bool {
+	if dep.Source != "" || dep.ReplacedBy != "" {
+		return false
+	}
+	return dep.Version != "" || dep.Name != project.ModuleName
+}
+
+// PinningCheck enforces the reproducible builds policy on annotated projects
+type PinningCheck struct {
+	RequireLockfile bool
+	ForbidFloating  bool
+}
+
+// NewPinningCheck creates a pinning policy check
+func NewPinningCheck(requireLockfile, forbidFloating bool) *PinningCheck {
+	return &PinningCheck{
+		RequireLockfile: requireLockfile,
+		ForbidFloating:  forbidFloating,
+	}
+}
+
+// Name returns the rule family enforced by this check
+func (c *PinningCheck) Name() string {
+	return "pinning"
+}
+
+// Evaluate returns violations for floating dependencies and missing lockfiles
+func (c *PinningCheck) Evaluate(ctx context.Context, projects []*domain.Project) []domain.PolicyViolation {
+	var violations []domain.PolicyViolation
+
+	for _, project := range projects {
+		if c.RequireLockfile && !project.HasLockfile {
+			violations = append(violations, domain.PolicyViolation{
+				Rule:      RuleMissingLockfile,
+				ProjectID: project.ID,
+				Message:   fmt.Sprintf("project %s has no %s lockfile", project.Name, project.Language),
+			})
+		}
+
+		if !c.ForbidFloating {
+			continue
+		}
+		for _, dep := range project.Dependencies {
+			if dep.IsFloating {
+				violations = append(violations, domain.PolicyViolation{
+					Rule:       RuleFloating,
+					ProjectID:  project.ID,
+					Dependency: dep.Name,
+					Message:    fmt.Sprintf("%s is declared with floating constraint %q", dep.Name, dep.Constraint),
+				})
+			}
+		}
+	}
+
+	return violations
+}
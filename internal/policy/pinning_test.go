@@ -0,0 +1,157 @@
+package policy_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/policy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFloatingConstraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		expected   bool
+	}{
+		{"", true},
+		{"*", true},
+		{"latest", true},
+		{"LATEST", true},
+		{">=1.2.0", true},
+		{"[1.0,)", true},
+		{"1.+", true},
+		{"latest.release", true},
+		{"1.2.3", false},
+		{"v1.9.1", false},
+		{"^1.2.3", false},
+		{"~=1.21", false},
+		{">=2,<3", false},
+		{"[1.0,2.0)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, policy.IsFloatingConstraint(tt.constraint))
+		})
+	}
+}
+
+func TestHasLockfile(t *testing.T) {
+	t.Parallel()
+
+	withLock := &domain.Project{
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "frontend/package.json"},
+			{Path: "frontend/package-lock.json"},
+		},
+	}
+	withoutLock := &domain.Project{
+		Language:        "python",
+		DependencyFiles: []*domain.DependencyFile{{Path: "requirements.txt"}},
+	}
+	withPnpmLock := &domain.Project{
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "web/package.json"},
+			{Path: "web/pnpm-lock.yaml"},
+		},
+	}
+	withPipfileLock := &domain.Project{
+		Language:        "python",
+		DependencyFiles: []*domain.DependencyFile{{Path: "Pipfile"}, {Path: "Pipfile.lock"}},
+	}
+	selfPinned := &domain.Project{
+		Language:        "go",
+		DependencyFiles: []*domain.DependencyFile{{Path: "go.mod"}},
+	}
+
+	assert.True(t, policy.HasLockfile(withLock))
+	assert.True(t, policy.HasLockfile(withPnpmLock))
+	assert.True(t, policy.HasLockfile(withPipfileLock))
+	assert.False(t, policy.HasLockfile(withoutLock))
+	assert.True(t, policy.HasLockfile(selfPinned))
+	assert.True(t, policy.RequiresLockfile("python"))
+	assert.False(t, policy.RequiresLockfile("go"))
+}
+
+func TestPinningCheck_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	project := &domain.Project{
+		ID:              "repo-1-root-python",
+		Name:            "repo Python",
+		Language:        "python",
+		DependencyFiles: []*domain.DependencyFile{{Path: "requirements.txt"}},
+		Dependencies: []*domain.Dependency{
+			{Name: "requests", Constraint: "2.31.0"},
+			{Name: "django", Constraint: ">=4.0"},
+		},
+	}
+	policy.AnnotatePinning(project)
+
+	assert.False(t, project.HasLockfile)
+	assert.False(t, project.Dependencies[0].IsFloating)
+	assert.True(t, project.Dependencies[1].IsFloating)
+
+	t.Run("report only", func(t *testing.T) {
+		t.Parallel()
+		check := policy.NewPinningCheck(false, false)
+		assert.Empty(t, check.Evaluate(context.Background(), []*domain.Project{project}))
+	})
+
+	t.Run("enforced", func(t *testing.T) {
+		t.Parallel()
+		check := policy.NewPinningCheck(true, true)
+		violations := check.Evaluate(context.Background(), []*domain.Project{project})
+
+		require.Len(t, violations, 2)
+		assert.Equal(t, "pinning", check.Name())
+		assert.Equal(t, policy.RuleMissingLockfile, violations[0].Rule)
+		assert.Equal(t, policy.RuleFloating, violations[1].Rule)
+		assert.Equal(t, "django", violations[1].Dependency)
+	})
+}
+
+func TestAnnotatePinning_NonRegistryDependencies(t *testing.T) {
+	t.Parallel()
+
+	goMod := &domain.DependencyFile{
+		Path:     "go.mod",
+		Language: "go",
+		Content:  []byte("module example.com/app\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.9.1\n"),
+	}
+	deps, err := parser.NewParser().ParseFile(context.Background(), goMod)
+	require.NoError(t, err)
+
+	pinned := &domain.Project{
+		Language:        "go",
+		ModuleName:      "example.com/app",
+		DependencyFiles: []*domain.DependencyFile{goMod},
+		Dependencies:    deps,
+	}
+	policy.AnnotatePinning(pinned)
+
+	for _, dep := range pinned.Dependencies {
+		assert.False(t, dep.IsFloating, dep.Name)
+	}
+	assert.Empty(t, policy.NewPinningCheck(true, true).Evaluate(context.Background(), []*domain.Project{pinned}))
+
+	local := &domain.Project{
+		Language: "nodejs",
+		Dependencies: []*domain.Dependency{
+			{Name: "shared", Source: "file:../shared"},
+			{Name: "left-pad", Constraint: "*"},
+		},
+	}
+	policy.AnnotatePinning(local)
+
+	assert.False(t, local.Dependencies[0].IsFloating)
+	assert.True(t, local.Dependencies[1].IsFloating)
+}
@@ -0,0 +1,166 @@
+package progress
+
+import (
+	"di-matrix-cli/internal/domain"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// barWidth is the number of cells of the bar
+const barWidth = 24
+
+// redrawInterval throttles redraws while units finish faster than a terminal can show them
+const redrawInterval = 100 * time.Millisecond
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+// stages are the analysis stages in the order they run
+var stages = []string{ //nolint:gochecknoglobals // Read-only lookup table
+	domain.StageDiscover, domain.StageScan, domain.StageParse, domain.StageEnrich, domain.StageReport,
+}
+
+// units names what the units of each stage count
+var units = map[string]string{ //nolint:gochecknoglobals // Read-only lookup table
+	domain.StageDiscover: "repositories",
+	domain.StageScan:     "repositories",
+	domain.StageParse:    "projects",
+	domain.StageEnrich:   "lookups",
+	domain.StageReport:   "reports",
+}
+
+// Bar renders the running stage of an analysis as a single self-updating terminal line with its count and ETA,
+// and leaves one line with the count and duration of every finished stage
+type Bar struct {
+	mu       sync.Mutex
+	out      io.Writer
+	now      func() time.Time
+	stage    string
+	total    int
+	done     int
+	started  time.Time
+	lastDraw time.Time
+	drawn    bool // The bar line is on screen
+}
+
+// New creates a progress bar drawn on out, typically a terminal's stderr
+func New(out io.Writer) *Bar {
+	return &Bar{out: out, now: time.Now}
+}
+
+// WithClock replaces the clock ETAs and durations are measured with, for tests
+func (b *Bar) WithClock(now func() time.Time) *Bar {
+	b.now = now
+	return b
+}
+
+// StartStage starts a stage of total units, finishing the previous one
+func (b *Bar) StartStage(stage string, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finishStage()
+	b.stage, b.total, b.done = stage, total, 0
+	b.started = b.now()
+	b.draw()
+}
+
+// Advance records n more finished units of the current stage
+func (b *Bar) Advance(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stage == "" {
+		return
+	}
+	b.done = min(b.done+n, b.total)
+	if b.done == b.total || b.now().Sub(b.lastDraw) >= redrawInterval {
+		b.draw()
+	}
+}
+
+// Finish finishes the last stage
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finishStage()
+}
+
+// Wrap returns a writer for output sharing the terminal with the bar, typically logs: the bar line is erased
+// before every write and drawn again below it
+func (b *Bar) Wrap(w io.Writer) io.Writer {
+	return &wrapped{bar: b, w: w}
+}
+
+type wrapped struct {
+	bar *Bar
+	w   io.Writer
+}
+
+func (w *wrapped) Write(p []byte) (int, error) {
+	w.bar.mu.Lock()
+	defer w.bar.mu.Unlock()
+
+	if w.bar.drawn {
+		_, _ = io.WriteString(w.bar.out, clearLine)
+		w.bar.drawn = false
+	}
+	n, err := w.w.Write(p)
+	if w.bar.stage != "" {
+		w.bar.draw()
+	}
+	return n, err
+}
+
+// finishStage replaces the bar of the current stage with its summary line, the caller holds the lock
+func (b *Bar) finishStage() {
+	if b.stage == "" {
+		return
+	}
+
+	elapsed := b.now().Sub(b.started)
+	_, _ = fmt.Fprintf(b.out, "%s%s %-8s %d/%d %s in %s\n", clearLine, b.position(), b.stage,
+		b.done, b.total, units[b.stage], formatDuration(elapsed))
+	b.stage, b.drawn = "", false
+}
+
+// draw redraws the bar line of the current stage, the caller holds the lock
+func (b *Bar) draw() {
+	filled := barWidth
+	if b.total > 0 {
+		filled = b.done * barWidth / b.total
+	}
+
+	_, _ = fmt.Fprintf(b.out, "%s%s %-8s [%s%s] %d/%d %s  %s", clearLine, b.position(), b.stage,
+		strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), b.done, b.total, units[b.stage], b.eta())
+	b.drawn = true
+	b.lastDraw = b.now()
+}
+
+// position numbers the current stage among all stages, e.g. "[2/5]"
+func (b *Bar) position() string {
+	return fmt.Sprintf("[%d/%d]", slices.Index(stages, b.stage)+1, len(stages))
+}
+
+// eta extrapolates the remaining time of the current stage from the pace of its finished units
+func (b *Bar) eta() string {
+	if b.done == 0 || b.done >= b.total {
+		return "ETA --"
+	}
+	elapsed := b.now().Sub(b.started)
+	remaining := time.Duration(float64(elapsed) * float64(b.total-b.done) / float64(b.done))
+	return "ETA " + formatDuration(remaining)
+}
+
+// formatDuration rounds durations for display: tenths of seconds below a minute, seconds above
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,57 @@
+package progress_test
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/progress"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock advanced by the test
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestBar_Stages(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	bar := progress.New(&out).WithClock(clock.Now)
+
+	bar.StartStage(domain.StageScan, 4)
+	clock.now = clock.now.Add(2 * time.Second)
+	bar.Advance(1)
+	assert.Contains(t, out.String(), "[2/5] scan     [██████░░░░░░░░░░░░░░░░░░] 1/4 repositories  ETA 6s",
+		"The remaining units are extrapolated from the pace of the finished ones")
+
+	bar.Advance(10)
+	bar.StartStage(domain.StageParse, 2)
+	assert.Contains(t, out.String(), "[2/5] scan     4/4 repositories in 2s", "A finished stage leaves a summary line")
+
+	bar.Finish()
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "[3/5] parse    0/2 projects in")
+}
+
+func TestBar_Wrap(t *testing.T) {
+	t.Parallel()
+
+	var out, logs bytes.Buffer
+	bar := progress.New(&out)
+	bar.StartStage(domain.StageDiscover, 1)
+
+	_, err := bar.Wrap(&logs).Write([]byte("log line\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "log line\n", logs.String())
+	assert.Equal(t, 3, strings.Count(out.String(), "\r\033[K"), "The bar is erased before the log line and drawn again")
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/gitea"
+	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/local"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Names of the built-in source providers, as set by the provider config key
+const (
+	GitLab = config.GitLabProvider
+	Gitea  = config.GiteaProvider
+	Local  = config.LocalProvider
+)
+
+// Factory creates a source provider from the configuration
+type Factory func(cfg *config.Config, logger *zap.Logger) (domain.SourceProvider, error)
+
+// Registry maps provider names to their factories, new backends register here
+// without changes to the use cases
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Builtin returns a registry of the providers shipped with the CLI
+func Builtin() *Registry {
+	return NewRegistry().
+		Register(GitLab, newGitLab).
+		Register(Gitea, newGitea).
+		Register(Local, newLocal)
+}
+
+// Register adds a provider, replacing an earlier one of the same name
+func (r *Registry) Register(name string, factory Factory) *Registry {
+	r.factories[name] = factory
+	return r
+}
+
+// Names returns the registered provider names in order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the provider selected by the configuration, GitLab when none is selected
+func (r *Registry) New(cfg *config.Config, logger *zap.Logger) (domain.SourceProvider, error) {
+	name := cfg.Provider
+	if name == "" {
+		name = GitLab
+	}
+
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider '%s'. Supported providers: %s", name, strings.Join(r.Names(), ", "))
+	}
+	return factory(cfg, logger)
+}
+
+// newGitLab creates a GitLab client with the configured retries and group filters, routing repositories
+// to further instances when any are configured. Tokens are read from their files or commands here, so
+// commands that never talk to GitLab do not run them.
+func newGitLab(cfg *config.Config, logger *zap.Logger) (domain.SourceProvider, error) {
+	token, err := cfg.GitLab.ResolveToken()
+	if err != nil {
+		return nil, err
+	}
+	client, err := newGitLabClient(cfg, cfg.GitLab.BaseURL, token, cfg.GitLab.TokenType, "", logger)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.GitLab.Instances) == 0 {
+		return client, nil
+	}
+
+	others := make([]*gitlab.Client, 0, len(cfg.GitLab.Instances))
+	for i, instance := range cfg.GitLab.Instances {
+		token, err := cfg.GitLab.InstanceToken(i)
+		if err != nil {
+			return nil, err
+		}
+		other, err := newGitLabClient(cfg, instance.BaseURL, token, instance.TokenType, instance.BaseURL, logger)
+		if err != nil {
+			return nil, err
+		}
+		others = append(others, other)
+	}
+	return gitlab.NewInstances(client, others...), nil
+}
+
+// newGitLabClient creates the client of one GitLab instance. Project IDs are unique per instance only,
+// so the cache of a further instance lives in a subdirectory named after its base URL.
+func newGitLabClient(
+	cfg *config.Config,
+	baseURL, token, tokenType, cacheScope string,
+	logger *zap.Logger,
+) (*gitlab.Client, error) {
+	client, err := gitlab.NewClient(baseURL, token, gitlab.TokenType(tokenType), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Cache.GitLab {
+		dir := cfg.Cache.Directory()
+		if cacheScope != "" {
+			dir = filepath.Join(dir, "instances", cacheDirName(cacheScope))
+		}
+		client.WithCache(cache.New(dir))
+	}
+
+	return client.WithRetryPolicies(gitlab.RetryPolicies{
+		Metadata: cfg.Retry.Metadata.Policy(),
+		Tree:     cfg.Retry.Tree.Policy(),
+		Content:  cfg.Retry.Content.Policy(),
+	}).WithGroupFilters(groupFilters(cfg.Repositories)).
+		WithRefs(repositoryRefs(cfg.Repositories)).
+		WithRef(cfg.GitLab.Ref).
+		WithArchivedSkipped(cfg.GitLab.SkipArchived).
+		WithForksIncluded(cfg.GitLab.IncludeForks).
+		WithMaxConcurrentRequests(cfg.Concurrency.MaxConcurrentRequests).
+		WithPaginationWorkers(cfg.Concurrency.PaginationWorkers), nil
+}
+
+// cacheDirName turns a base URL into a directory name, "https://gitlab.example.com/git" is "gitlab.example.com_git"
+func cacheDirName(baseURL string) string {
+	name := baseURL
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+		name = parsed.Host + strings.TrimSuffix(parsed.Path, "/")
+	}
+	return strings.NewReplacer("/", "_", ":", "_").Replace(name)
+}
+
+// newGitea creates a Gitea or Forgejo client with the configured retries and refs
+func newGitea(cfg *config.Config, logger *zap.Logger) (domain.SourceProvider, error) {
+	token, err := cfg.Gitea.ResolveToken()
+	if err != nil {
+		return nil, err
+	}
+	return gitea.NewClient(cfg.Gitea.BaseURL, token, logger).
+		WithRetryPolicy(cfg.Retry.Metadata.Policy()).
+		WithRefs(repositoryRefs(cfg.Repositories)).
+		WithRef(cfg.Gitea.Ref).
+		WithArchivedSkipped(cfg.Gitea.SkipArchived).
+		WithForksIncluded(cfg.Gitea.IncludeForks), nil
+}
+
+// newLocal creates a provider reading repositories from directories on disk
+func newLocal(_ *config.Config, logger *zap.Logger) (domain.SourceProvider, error) {
+	return local.NewProvider(logger), nil
+}
+
+// groupFilters collects the subgroup settings of group entries keyed by their URL
+func groupFilters(repositories []config.RepositoryConfig) map[string]gitlab.GroupFilter {
+	filters := make(map[string]gitlab.GroupFilter)
+	for _, repo := range repositories {
+		if repo.URL != "" && repo.HasGroupFilter() {
+			filters[repo.URL] = gitlab.GroupFilter{
+				MaxDepth:    repo.SubgroupDepth,
+				Include:     repo.IncludeSubgroups,
+				Exclude:     repo.ExcludeSubgroups,
+				Topics:      repo.Topics,
+				NamePattern: repo.NamePattern,
+			}
+		}
+	}
+	return filters
+}
+
+// repositoryRefs collects the branches of repository and group entries keyed by their URL
+func repositoryRefs(repositories []config.RepositoryConfig) map[string]string {
+	refs := make(map[string]string)
+	for _, repo := range repositories {
+		if repo.URL != "" && repo.Branch != "" {
+			refs[repo.URL] = repo.Branch
+		}
+	}
+	return refs
+}
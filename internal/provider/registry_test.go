@@ -0,0 +1,86 @@
+package provider_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/gitea"
+	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/local"
+	"di-matrix-cli/internal/provider"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	registry := provider.Builtin()
+	assert.Equal(t, []string{"gitea", "gitlab", "local"}, registry.Names())
+
+	// GitLab is the default provider
+	gitlabProvider, err := registry.New(&config.Config{
+		GitLab: config.GitLabConfig{BaseURL: "https://gitlab.example.com", Token: "token"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &gitlab.Client{}, gitlabProvider)
+
+	// Further instances are routed to by repository URL
+	instancesProvider, err := registry.New(&config.Config{
+		GitLab: config.GitLabConfig{
+			BaseURL:   "https://gitlab.example.com",
+			Token:     "token",
+			Instances: []config.GitLabInstanceConfig{{BaseURL: "https://gitlab.com", Token: "other-token"}},
+		},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &gitlab.Instances{}, instancesProvider)
+
+	giteaProvider, err := registry.New(&config.Config{
+		Provider: provider.Gitea,
+		Gitea:    config.GiteaConfig{BaseURL: "https://codeberg.org", Token: "token"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &gitea.Client{}, giteaProvider)
+
+	localProvider, err := registry.New(&config.Config{Provider: provider.Local}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &local.Provider{}, localProvider)
+
+	_, err = registry.New(&config.Config{Provider: "svn"}, zap.NewNop())
+	assert.ErrorContains(t, err, "Supported providers: gitea, gitlab, local")
+}
+
+// staticProvider serves a fixed repository list
+type staticProvider struct {
+	repositories []*domain.Repository
+}
+
+func (p *staticProvider) CheckPermissions(context.Context) error { return nil }
+
+func (p *staticProvider) GetRepositoriesList(context.Context, string) ([]*domain.Repository, error) {
+	return p.repositories, nil
+}
+
+func (p *staticProvider) GetFilesList(context.Context, string) ([]string, error) { return nil, nil }
+
+func (p *staticProvider) GetFileContent(context.Context, string, string) ([]byte, error) {
+	return nil, nil
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	static := &staticProvider{repositories: []*domain.Repository{{ID: 1, Name: "fixture"}}}
+	registry := provider.Builtin().Register("static", func(*config.Config, *zap.Logger) (domain.SourceProvider, error) {
+		return static, nil
+	})
+	assert.Equal(t, []string{"gitea", "gitlab", "local", "static"}, registry.Names())
+
+	created, err := registry.New(&config.Config{Provider: "static"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, static, created)
+}
@@ -0,0 +1,259 @@
+package registry
+
+import (
+	"context"
+	"di-matrix-cli/internal/retry"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// ErrNotFound is returned for packages the registry does not know, e.g. private packages
+var ErrNotFound = errors.New("package not found")
+
+// Client looks up the latest release of a package in the registry of one ecosystem
+type Client interface {
+	// returns the latest released version of the package, ErrNotFound when the registry does not have it
+	Latest(ctx context.Context, name string) (string, error)
+}
+
+// Release is what a registry knows about the releases of a package
+type Release struct {
+	Latest    string
+	Withdrawn map[string]string // Deprecated or yanked versions with the reason given upstream
+}
+
+// ReleaseClient is a Client of a registry that also withdraws releases, npm deprecations or PyPI yanks
+type ReleaseClient interface {
+	Client
+	// returns the latest version and the withdrawn versions of the package, ErrNotFound when the registry does not have it
+	Release(ctx context.Context, name string) (Release, error)
+}
+
+// httpClient fetches registry documents under a retry policy
+type httpClient struct {
+	client *http.Client
+	policy retry.Policy
+}
+
+// isTransient reports whether a failed request may succeed when retried, unknown packages are final
+func isTransient(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return false
+	}
+	return retry.IsTransientHTTP(err)
+}
+
+// get downloads url, retrying transient failures
+func (c *httpClient) get(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	var content []byte
+	err := c.policy.Do(ctx, isTransient, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+			return ErrNotFound
+		case resp.StatusCode != http.StatusOK:
+			return &retry.StatusError{Status: resp.StatusCode, URL: url}
+		}
+		content, err = io.ReadAll(resp.Body)
+		return err
+	})
+	return content, err
+}
+
+// GoProxy looks up Go modules through the module proxy protocol
+type GoProxy struct {
+	baseURL string
+	http    httpClient
+}
+
+// NewGoProxy creates a client of a Go module proxy such as https://proxy.golang.org
+func NewGoProxy(baseURL string, policy retry.Policy) *GoProxy {
+	return &GoProxy{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient{client: &http.Client{}, policy: policy}}
+}
+
+// Latest returns the version of the module's @latest endpoint
+func (p *GoProxy) Latest(ctx context.Context, name string) (string, error) {
+	escaped, err := module.EscapePath(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %s: %w", name, err)
+	}
+
+	content, err := p.http.get(ctx, p.baseURL+"/"+escaped+"/@latest", nil)
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(content, &info); err != nil {
+		return "", fmt.Errorf("invalid @latest response for %s: %w", name, err)
+	}
+	return info.Version, nil
+}
+
+// NPM looks up packages in an npm registry
+type NPM struct {
+	baseURL string
+	http    httpClient
+}
+
+// NewNPM creates a client of an npm registry such as https://registry.npmjs.org
+func NewNPM(baseURL string, policy retry.Policy) *NPM {
+	return &NPM{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient{client: &http.Client{}, policy: policy}}
+}
+
+// Latest returns the "latest" dist-tag of the package
+func (n *NPM) Latest(ctx context.Context, name string) (string, error) {
+	release, err := n.Release(ctx, name)
+	return release.Latest, err
+}
+
+// Release returns the "latest" dist-tag of the package and its deprecated versions with the deprecation message
+func (n *NPM) Release(ctx context.Context, name string) (Release, error) {
+	// The abbreviated metadata document is a fraction of the full one and keeps deprecation messages
+	header := http.Header{"Accept": {"application/vnd.npm.install-v1+json"}}
+	content, err := n.http.get(ctx, n.baseURL+"/"+strings.Replace(name, "/", "%2F", 1), header)
+	if err != nil {
+		return Release{}, err
+	}
+	var metadata struct {
+		DistTags map[string]string `json:"dist-tags"`
+		Versions map[string]struct {
+			Deprecated string `json:"deprecated"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return Release{}, fmt.Errorf("invalid metadata for %s: %w", name, err)
+	}
+
+	release := Release{Latest: metadata.DistTags["latest"]}
+	for packageVersion, info := range metadata.Versions {
+		if info.Deprecated == "" {
+			continue
+		}
+		if release.Withdrawn == nil {
+			release.Withdrawn = make(map[string]string)
+		}
+		release.Withdrawn[packageVersion] = "deprecated: " + info.Deprecated
+	}
+	return release, nil
+}
+
+// PyPI looks up packages through the PyPI JSON API
+type PyPI struct {
+	baseURL string
+	http    httpClient
+}
+
+// NewPyPI creates a client of a PyPI-compatible index such as https://pypi.org
+func NewPyPI(baseURL string, policy retry.Policy) *PyPI {
+	return &PyPI{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient{client: &http.Client{}, policy: policy}}
+}
+
+// Latest returns the version of the project's JSON document, the latest non pre-release
+func (p *PyPI) Latest(ctx context.Context, name string) (string, error) {
+	release, err := p.Release(ctx, name)
+	return release.Latest, err
+}
+
+// Release returns the version of the project's JSON document and its yanked releases with the yank reason.
+// A release is yanked when all its files are.
+func (p *PyPI) Release(ctx context.Context, name string) (Release, error) {
+	content, err := p.http.get(ctx, p.baseURL+"/pypi/"+url.PathEscape(name)+"/json", nil)
+	if err != nil {
+		return Release{}, err
+	}
+	var project struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Releases map[string][]struct {
+			Yanked       bool   `json:"yanked"`
+			YankedReason string `json:"yanked_reason"`
+		} `json:"releases"`
+	}
+	if err := json.Unmarshal(content, &project); err != nil {
+		return Release{}, fmt.Errorf("invalid project document for %s: %w", name, err)
+	}
+
+	release := Release{Latest: project.Info.Version}
+	for packageVersion, files := range project.Releases {
+		yanked, reason := len(files) > 0, ""
+		for _, file := range files {
+			yanked = yanked && file.Yanked
+			if file.YankedReason != "" {
+				reason = file.YankedReason
+			}
+		}
+		if !yanked {
+			continue
+		}
+		if release.Withdrawn == nil {
+			release.Withdrawn = make(map[string]string)
+		}
+		release.Withdrawn[packageVersion] = strings.TrimSuffix("yanked: "+reason, ": ")
+	}
+	return release, nil
+}
+
+// Maven looks up artifacts in Maven repositories, the first repository that has the artifact wins
+type Maven struct {
+	urls []string
+	http httpClient
+}
+
+// NewMaven creates a client of Maven repositories such as https://repo.maven.apache.org/maven2
+func NewMaven(urls []string, policy retry.Policy) *Maven {
+	return &Maven{urls: urls, http: httpClient{client: &http.Client{}, policy: policy}}
+}
+
+// Latest returns the release version of the artifact's maven-metadata.xml, name is "groupid:artifactid"
+func (m *Maven) Latest(ctx context.Context, name string) (string, error) {
+	groupID, artifactID, ok := strings.Cut(name, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid Maven coordinates %s", name)
+	}
+	metadataPath := strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID + "/maven-metadata.xml"
+
+	err := ErrNotFound
+	for _, repositoryURL := range m.urls {
+		var content []byte
+		content, err = m.http.get(ctx, strings.TrimSuffix(repositoryURL, "/")+"/"+metadataPath, nil)
+		if err != nil {
+			continue
+		}
+		var metadata struct {
+			Release string `xml:"versioning>release"`
+			Latest  string `xml:"versioning>latest"`
+		}
+		if err := xml.Unmarshal(content, &metadata); err != nil {
+			return "", fmt.Errorf("invalid metadata for %s: %w", name, err)
+		}
+		if metadata.Release != "" {
+			return metadata.Release, nil
+		}
+		return metadata.Latest, nil
+	}
+	return "", err
+}
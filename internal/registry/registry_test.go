@@ -0,0 +1,290 @@
+package registry_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/registry"
+	"di-matrix-cli/internal/retry"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClients_Latest(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!burnt!sushi/toml/@latest", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v1.5.0","Time":"2025-03-13T00:00:00Z"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/@types%2Fnode" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "application/vnd.npm.install-v1+json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"name":"@types/node","dist-tags":{"latest":"22.7.4","next":"23.0.0-rc.1"}}`))
+	})
+	mux.HandleFunc("/pypi/requests/json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"info":{"name":"requests","version":"2.32.3"}}`))
+	})
+	mux.HandleFunc("/maven2/org/slf4j/slf4j-api/maven-metadata.xml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<metadata><versioning><latest>2.1.0-alpha1</latest><release>2.0.16</release>` +
+			`</versioning></metadata>`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	policy := retry.Policy{}
+	tests := []struct {
+		name     string
+		client   registry.Client
+		pkg      string
+		expected string
+	}{
+		{"go proxy escapes upper case", registry.NewGoProxy(server.URL, policy), "github.com/BurntSushi/toml", "v1.5.0"},
+		{"npm scoped package", registry.NewNPM(server.URL+"/", policy), "@types/node", "22.7.4"},
+		{"pypi", registry.NewPyPI(server.URL, policy), "requests", "2.32.3"},
+		{"maven release", registry.NewMaven([]string{server.URL + "/empty", server.URL + "/maven2"}, policy),
+			"org.slf4j:slf4j-api", "2.0.16"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			latest, err := tt.client.Latest(context.Background(), tt.pkg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, latest)
+		})
+	}
+}
+
+func TestClients_NotFound(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := registry.NewPyPI(server.URL, retry.Policy{Retries: 2}).Latest(context.Background(), "private-package")
+	assert.True(t, errors.Is(err, registry.ErrNotFound))
+
+	_, err = registry.NewMaven([]string{server.URL}, retry.Policy{}).Latest(context.Background(), "com.company:lib")
+	assert.True(t, errors.Is(err, registry.ErrNotFound))
+}
+
+// fakeClient serves latest versions from a map and counts lookups
+type fakeClient struct {
+	versions map[string]string
+	fail     bool
+	calls    atomic.Int32
+}
+
+func (c *fakeClient) Latest(_ context.Context, name string) (string, error) {
+	c.calls.Add(1)
+	if c.fail {
+		return "", errors.New("registry unavailable")
+	}
+	version, ok := c.versions[name]
+	if !ok {
+		return "", registry.ErrNotFound
+	}
+	return version, nil
+}
+
+func npmProjects() []*domain.Project {
+	return []*domain.Project{
+		{ID: "web", Dependencies: []*domain.Dependency{
+			{Name: "react", Version: "18.2.0", LatestVersion: "18.2.0", Ecosystem: "npm"},
+			{Name: "@company/ui", Version: "1.0.0", LatestVersion: "1.0.0", Ecosystem: "npm", IsInternal: true},
+			{Name: "forked", Version: "1.0.0", LatestVersion: "1.0.0", Ecosystem: "npm",
+				Source: "git+https://github.com/company/forked.git"},
+		}},
+		{ID: "admin", Dependencies: []*domain.Dependency{
+			{Name: "react", Version: "17.0.2", LatestVersion: "17.0.2", Ecosystem: "npm"},
+			{Name: "left-pad", Version: "1.3.0", LatestVersion: "1.3.0", Ecosystem: "npm"},
+			{Name: "requests", Version: "2.0.0", LatestVersion: "2.0.0", Ecosystem: "pip"},
+		}},
+	}
+}
+
+func TestResolver_ResolveLatestVersions(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{versions: map[string]string{"react": "19.1.0", "@company/ui": "9.9.9", "forked": "9.9.9"}}
+	projects := npmProjects()
+
+	resolved := registry.NewResolver(zap.NewNop()).
+		WithClient("npm", client).
+		WithConcurrency(2).
+		ResolveLatestVersions(context.Background(), projects)
+
+	assert.Equal(t, 2, resolved)
+	assert.Equal(t, "19.1.0", projects[0].Dependencies[0].LatestVersion)
+	assert.Equal(t, "19.1.0", projects[1].Dependencies[0].LatestVersion)
+	assert.Equal(t, "1.0.0", projects[0].Dependencies[1].LatestVersion, "internal dependencies are not looked up")
+	assert.Equal(t, "1.0.0", projects[0].Dependencies[2].LatestVersion, "git dependencies are not looked up")
+	assert.Equal(t, "1.3.0", projects[1].Dependencies[1].LatestVersion, "unknown packages keep the version in use")
+	assert.Equal(t, "2.0.0", projects[1].Dependencies[2].LatestVersion, "ecosystems without a client are skipped")
+	assert.Equal(t, int32(2), client.calls.Load(), "each package is looked up once")
+}
+
+func TestResolver_DeclaredNames(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{versions: map[string]string{"Django": "5.1.0"}}
+	projects := []*domain.Project{{ID: "api", Dependencies: []*domain.Dependency{
+		{Name: "django", DeclaredName: "Django", Version: "4.2.0", LatestVersion: "4.2.0", Ecosystem: "pip"},
+	}}}
+
+	resolved := registry.NewResolver(zap.NewNop()).
+		WithClient("pip", client).
+		ResolveLatestVersions(context.Background(), projects)
+
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, "5.1.0", projects[0].Dependencies[0].LatestVersion, "the registry gets the declared name")
+}
+
+func TestResolver_Cache(t *testing.T) {
+	t.Parallel()
+
+	store := cache.New(t.TempDir())
+	online := &fakeClient{versions: map[string]string{"react": "19.1.0"}}
+	registry.NewResolver(zap.NewNop()).
+		WithClient("npm", online).
+		WithCache(store, time.Hour).
+		ResolveLatestVersions(context.Background(), npmProjects())
+	require.Equal(t, int32(2), online.calls.Load())
+
+	t.Run("fresh entries are reused", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeClient{}
+		projects := npmProjects()
+		registry.NewResolver(zap.NewNop()).
+			WithClient("npm", client).
+			WithCache(store, time.Hour).
+			ResolveLatestVersions(context.Background(), projects)
+
+		assert.Equal(t, int32(0), client.calls.Load(), "found and not found packages are both cached")
+		assert.Equal(t, "19.1.0", projects[0].Dependencies[0].LatestVersion)
+	})
+
+	t.Run("expired entries are a fallback for failed lookups", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeClient{fail: true}
+		projects := npmProjects()
+		registry.NewResolver(zap.NewNop()).
+			WithClient("npm", client).
+			WithCache(store, 0).
+			ResolveLatestVersions(context.Background(), projects)
+
+		assert.Equal(t, int32(2), client.calls.Load())
+		assert.Equal(t, "19.1.0", projects[0].Dependencies[0].LatestVersion)
+	})
+}
+
+func TestResolver_Offline(t *testing.T) {
+	t.Parallel()
+
+	store := cache.New(t.TempDir())
+	registry.NewResolver(zap.NewNop()).
+		WithClient("npm", &fakeClient{versions: map[string]string{"react": "19.1.0"}}).
+		WithCache(store, time.Hour).
+		ResolveLatestVersions(context.Background(), npmProjects()[:1])
+
+	client := &fakeClient{}
+	projects := npmProjects()
+	registry.NewResolver(zap.NewNop()).
+		WithClient("npm", client).
+		WithCache(store, 0).
+		WithOffline(true).
+		ResolveLatestVersions(context.Background(), projects)
+
+	assert.Equal(t, int32(0), client.calls.Load(), "offline mode never calls the registry")
+	assert.Equal(t, "19.1.0", projects[1].Dependencies[0].LatestVersion, "cached versions of any age are used")
+	assert.False(t, projects[1].Dependencies[0].Stale)
+	assert.True(t, projects[1].Dependencies[1].Stale, "packages missing from the cache are stale")
+	assert.Equal(t, "1.3.0", projects[1].Dependencies[1].LatestVersion)
+}
+
+func TestClients_Release(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/request", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"dist-tags":{"latest":"2.88.2"},"versions":{` +
+			`"2.88.0":{"deprecated":"request has been deprecated"},"2.88.2":{"deprecated":"request has been deprecated"},` +
+			`"2.87.0":{}}}`))
+	})
+	mux.HandleFunc("/pypi/urllib3/json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"info":{"version":"2.2.3"},"releases":{` +
+			`"2.2.0":[{"yanked":true,"yanked_reason":"broken wheel"},{"yanked":true,"yanked_reason":""}],` +
+			`"2.1.0":[{"yanked":true},{"yanked":false}],` +
+			`"2.0.0":[{"yanked":true}],` +
+			`"1.0.0":[]}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	npm, err := registry.NewNPM(server.URL, retry.Policy{}).Release(context.Background(), "request")
+	require.NoError(t, err)
+	assert.Equal(t, "2.88.2", npm.Latest)
+	assert.Equal(t, map[string]string{
+		"2.88.0": "deprecated: request has been deprecated",
+		"2.88.2": "deprecated: request has been deprecated",
+	}, npm.Withdrawn)
+
+	pypi, err := registry.NewPyPI(server.URL, retry.Policy{}).Release(context.Background(), "urllib3")
+	require.NoError(t, err)
+	assert.Equal(t, "2.2.3", pypi.Latest)
+	assert.Equal(t, map[string]string{"2.2.0": "yanked: broken wheel", "2.0.0": "yanked"}, pypi.Withdrawn,
+		"releases with a file left are not yanked")
+}
+
+// fakeReleaseClient also withdraws versions
+type fakeReleaseClient struct {
+	fakeClient
+	withdrawn map[string]string
+}
+
+func (c *fakeReleaseClient) Release(ctx context.Context, name string) (registry.Release, error) {
+	latest, err := c.Latest(ctx, name)
+	return registry.Release{Latest: latest, Withdrawn: c.withdrawn}, err
+}
+
+func TestResolver_WithdrawnVersions(t *testing.T) {
+	t.Parallel()
+
+	store := cache.New(t.TempDir())
+	client := &fakeReleaseClient{
+		fakeClient: fakeClient{versions: map[string]string{"react": "19.1.0", "left-pad": "1.3.0"}},
+		withdrawn:  map[string]string{"17.0.2": "deprecated: upgrade to 18", "1.3.0": "deprecated: use String.padStart"},
+	}
+	projects := npmProjects()
+	registry.NewResolver(zap.NewNop()).
+		WithClient("npm", client).
+		WithCache(store, time.Hour).
+		ResolveLatestVersions(context.Background(), projects)
+
+	assert.False(t, projects[0].Dependencies[0].IsDeprecated, "react 18.2.0 is not withdrawn")
+	assert.True(t, projects[1].Dependencies[0].IsDeprecated)
+	assert.Equal(t, "deprecated: upgrade to 18", projects[1].Dependencies[0].Deprecation)
+	assert.True(t, projects[1].Dependencies[1].IsDeprecated)
+
+	offline := npmProjects()
+	registry.NewResolver(zap.NewNop()).
+		WithClient("npm", &fakeClient{}).
+		WithCache(store, time.Hour).
+		WithOffline(true).
+		ResolveLatestVersions(context.Background(), offline)
+	assert.Equal(t, "deprecated: upgrade to 18", offline[1].Dependencies[0].Deprecation, "withdrawn versions are cached")
+}
@@ -0,0 +1,223 @@
+package registry
+
+import (
+	"context"
+	"di-matrix-cli/internal/cache"
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTTL is how long a looked-up latest version is reused before the registry is asked again
+	DefaultTTL = 24 * time.Hour
+	// DefaultWorkers is the number of concurrent registry lookups
+	DefaultWorkers = 8
+)
+
+// cacheNamespace groups latest versions in the on-disk cache
+const cacheNamespace = "registry"
+
+// entry is a cached lookup, an empty version records a package the registry does not have
+type entry struct {
+	Version   string            `json:"version"`
+	Withdrawn map[string]string `json:"withdrawn,omitempty"` // Deprecated or yanked versions with the reason
+	FetchedAt time.Time         `json:"fetched_at"`
+}
+
+// result is the outcome of looking up one package
+type result struct {
+	version   string
+	withdrawn map[string]string
+	stale     bool // Offline mode and missing from the cache
+}
+
+// Resolver sets the latest released version of dependencies from their package registries and marks versions
+// the registry withdrew as deprecated. Every package is looked up once per run, results are kept in the on-disk cache for later and offline runs.
+type Resolver struct {
+	logger  *zap.Logger
+	clients map[string]Client // Ecosystem -> registry client
+	store   *cache.Store
+	ttl     time.Duration
+	offline bool
+	workers int
+}
+
+// NewResolver creates a resolver without registries, ecosystems are added with WithClient
+func NewResolver(logger *zap.Logger) *Resolver {
+	return &Resolver{
+		logger:  logger,
+		clients: make(map[string]Client),
+		ttl:     DefaultTTL,
+		workers: DefaultWorkers,
+	}
+}
+
+// WithClient looks up the dependencies of an ecosystem ("go-modules", "npm", "pip", "maven") with client
+func (r *Resolver) WithClient(ecosystem string, client Client) *Resolver {
+	r.clients[ecosystem] = client
+	return r
+}
+
+// WithCache keeps looked-up versions in store for ttl, offline runs use cached versions of any age
+func (r *Resolver) WithCache(store *cache.Store, ttl time.Duration) *Resolver {
+	r.store = store
+	r.ttl = ttl
+	return r
+}
+
+// WithOffline serves latest versions from the cache only, dependencies missing from it are marked stale
+func (r *Resolver) WithOffline(offline bool) *Resolver {
+	r.offline = offline
+	return r
+}
+
+// WithConcurrency sets the number of concurrent registry lookups
+func (r *Resolver) WithConcurrency(workers int) *Resolver {
+	if workers > 0 {
+		r.workers = workers
+	}
+	return r
+}
+
+// ResolveLatestVersions sets the latest version of external registry dependencies and returns how many were set.
+// Dependencies on a version deprecated or yanked upstream are marked deprecated with the reason. Internal dependencies and dependencies from git or local sources are not in public registries and are skipped.
+func (r *Resolver) ResolveLatestVersions(ctx context.Context, projects []*domain.Project) int {
+	type key struct{ ecosystem, name string }
+
+	var keys []key
+	seen := make(map[key]bool)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			k := key{dep.Ecosystem, dep.LookupName()}
+			if !r.resolvable(dep) || seen[k] {
+				continue
+			}
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	results := make(map[key]result, len(keys))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, r.workers)
+	for _, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			res := r.lookup(ctx, k.ecosystem, k.name)
+			mu.Lock()
+			results[k] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	resolved, deprecated, stale := 0, 0, 0
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			res, ok := results[key{dep.Ecosystem, dep.LookupName()}]
+			if !ok || !r.resolvable(dep) {
+				continue
+			}
+			if res.stale {
+				dep.Stale = true
+				stale++
+				continue
+			}
+			if res.version != "" {
+				dep.LatestVersion = res.version
+				resolved++
+			}
+			if reason, withdrawn := res.withdrawn[dep.Version]; withdrawn {
+				dep.IsDeprecated = true
+				dep.Deprecation = reason
+				deprecated++
+			}
+		}
+	}
+
+	r.logger.Info("Resolved latest versions from package registries",
+		zap.Int("package_count", len(keys)),
+		zap.Int("dependency_count", resolved),
+		zap.Int("deprecated_count", deprecated))
+	if stale > 0 {
+		r.logger.Warn("Offline mode left latest versions unresolved, packages are missing from the cache",
+			zap.Int("dependency_count", stale))
+	}
+	return resolved
+}
+
+// resolvable reports whether the dependency can be looked up in a configured public registry
+func (r *Resolver) resolvable(dep *domain.Dependency) bool {
+	_, ok := r.clients[dep.Ecosystem]
+	return ok && !dep.IsInternal && dep.Source == "" && dep.Name != ""
+}
+
+// lookup returns the latest version from the cache or the registry.
+// A failed lookup falls back to an expired cache entry, an outdated latest version beats none.
+func (r *Resolver) lookup(ctx context.Context, ecosystem, name string) result {
+	cacheKey := ecosystem + ":" + name
+	cached, hit := r.cached(cacheKey)
+	if hit && (r.offline || time.Since(cached.FetchedAt) < r.ttl) {
+		return result{version: cached.Version, withdrawn: cached.Withdrawn}
+	}
+	if r.offline {
+		return result{stale: true}
+	}
+
+	release, err := r.release(ctx, r.clients[ecosystem], name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		r.logger.Debug("Failed to look up latest version",
+			zap.String("ecosystem", ecosystem),
+			zap.String("package", name),
+			zap.Error(err))
+		return result{version: cached.Version, withdrawn: cached.Withdrawn}
+	}
+
+	if r.store != nil {
+		fetched := entry{Version: release.Latest, Withdrawn: release.Withdrawn, FetchedAt: time.Now()}
+		if data, err := json.Marshal(fetched); err == nil {
+			// A failed write only costs a lookup next time
+			_ = r.store.Put(cacheNamespace, cacheKey, data)
+		}
+	}
+	return result{version: release.Latest, withdrawn: release.Withdrawn}
+}
+
+// release looks up the package with client, withdrawn releases are only known to registries that withdraw them
+func (r *Resolver) release(ctx context.Context, client Client, name string) (Release, error) {
+	if releases, ok := client.(ReleaseClient); ok {
+		return releases.Release(ctx, name)
+	}
+	latest, err := client.Latest(ctx, name)
+	return Release{Latest: latest}, err
+}
+
+// cached returns the on-disk cache entry of key
+func (r *Resolver) cached(key string) (entry, bool) {
+	if r.store == nil {
+		return entry{}, false
+	}
+	data, ok := r.store.Get(cacheNamespace, key)
+	if !ok {
+		return entry{}, false
+	}
+	var cached entry
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return entry{}, false
+	}
+	return cached, true
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"sort"
+)
+
+// Drift is a dependency used at more than one version across the portfolio
+type Drift struct {
+	Name      string         `json:"name"`
+	Ecosystem string         `json:"ecosystem"`
+	Internal  bool           `json:"internal"`
+	Spread    int            `json:"spread"`   // Distinct versions in use
+	Versions  []DriftVersion `json:"versions"` // Highest version first
+}
+
+// DriftVersion is a version of a drifting dependency and the projects using it
+type DriftVersion struct {
+	Version  string   `json:"version"`
+	Projects []string `json:"projects"` // Project IDs
+}
+
+// NewDrift lists the dependencies projects use at more than one version, the widest spread first.
+// Equivalent spellings ("v1.2" and "1.2.0") are one version, dependencies without a version are left out.
+func NewDrift(projects []*domain.Project) []Drift {
+	type key struct{ ecosystem, name string }
+
+	var keys []key
+	drifts := make(map[key]*Drift)
+	indexes := make(map[key]map[string]int) // Canonical version -> index in Versions
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Version == "" {
+				continue
+			}
+			k := key{dep.Ecosystem, dep.Name}
+			drift, ok := drifts[k]
+			if !ok {
+				drift = &Drift{Name: dep.Name, Ecosystem: dep.Ecosystem, Internal: dep.IsInternal}
+				drifts[k] = drift
+				indexes[k] = make(map[string]int)
+				keys = append(keys, k)
+			}
+
+			canonical := version.Canonical(dep.Version)
+			index, seen := indexes[k][canonical]
+			if !seen {
+				index = len(drift.Versions)
+				indexes[k][canonical] = index
+				drift.Versions = append(drift.Versions, DriftVersion{Version: dep.Version})
+			}
+			used := &drift.Versions[index]
+			// A project listing the dependency more than once uses it once
+			if n := len(used.Projects); n == 0 || used.Projects[n-1] != project.ID {
+				used.Projects = append(used.Projects, project.ID)
+			}
+		}
+	}
+
+	result := []Drift{}
+	for _, k := range keys {
+		drift := drifts[k]
+		if len(drift.Versions) < 2 {
+			continue
+		}
+		drift.Spread = len(drift.Versions)
+		sort.SliceStable(drift.Versions, func(i, j int) bool {
+			return version.Compare(drift.Versions[i].Version, drift.Versions[j].Version) > 0
+		})
+		result = append(result, *drift)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Spread != result[j].Spread {
+			return result[i].Spread > result[j].Spread
+		}
+		if projects := projectCount(result[i]) - projectCount(result[j]); projects != 0 {
+			return projects > 0
+		}
+		if result[i].Ecosystem != result[j].Ecosystem {
+			return result[i].Ecosystem < result[j].Ecosystem
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// projectCount returns how many projects use the dependency, whatever the version
+func projectCount(drift Drift) int {
+	count := 0
+	for _, used := range drift.Versions {
+		count += len(used.Projects)
+	}
+	return count
+}
@@ -0,0 +1,419 @@
+package report
+
+import (
+	"di-matrix-cli/internal/domain"
+	_ "embed"
+	"math"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON report format described by report.schema.json.
+// Minor versions only add optional fields. Removing, renaming or retyping a field requires a new major version.
+const SchemaVersion = "1.11"
+
+// SchemaMajor is the major version of SchemaVersion, readers reject reports with a newer major version
+const SchemaMajor = "1"
+
+//go:embed report.schema.json
+var schema []byte
+
+// Schema returns the published JSON Schema of the report
+func Schema() []byte {
+	return schema
+}
+
+// Report is the JSON report. Its shape is a compatibility contract with downstream consumers
+// and deliberately decoupled from the domain model.
+type Report struct {
+	SchemaVersion string    `json:"schema_version"`
+	Title         string    `json:"title"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Incomplete    string    `json:"incomplete,omitempty"` // Why the analysis stopped early, absent for complete reports (1.1)
+	Summary       Summary   `json:"summary"`
+	Projects      []Project `json:"projects"`
+
+	// Team notes about dependencies keyed by dependency name, absent without an annotations file (1.3)
+	Annotations map[string]Annotation `json:"annotations,omitempty"`
+
+	// Share of each repository's dependency files that were analyzed (1.4)
+	Coverage []Coverage `json:"coverage,omitempty"`
+
+	// Dependencies used at more than one version across the projects, the widest spread first (1.10)
+	Drift []Drift `json:"drift,omitempty"`
+
+	// Repositories, projects and files the analysis failed on, and files it analyzed only partly (1.11)
+	Errors   []Issue `json:"errors,omitempty"`
+	Warnings []Issue `json:"warnings,omitempty"`
+}
+
+// Summary holds portfolio-wide statistics
+type Summary struct {
+	TotalProjects           int            `json:"total_projects"`
+	TotalDependencies       int            `json:"total_dependencies"`
+	InternalExternal        Split          `json:"internal_external"`
+	FloatingDependencies    int            `json:"floating_dependencies"`
+	ProjectsWithoutLockfile int            `json:"projects_without_lockfile"`
+	AverageHealth           float64        `json:"average_health"`
+	Languages               map[string]int `json:"languages"`  // Projects per language
+	Ecosystems              map[string]int `json:"ecosystems"` // Dependencies per ecosystem
+
+	// Dependencies with known vulnerabilities, absent unless vulnerabilities were scanned for (1.6)
+	VulnerableDependencies int `json:"vulnerable_dependencies,omitempty"`
+
+	// Dependencies on a version withdrawn upstream or a release cycle past its end of life (1.9)
+	DeprecatedDependencies int `json:"deprecated_dependencies,omitempty"`
+	EndOfLifeDependencies  int `json:"end_of_life_dependencies,omitempty"`
+}
+
+// Split counts internal and external dependencies
+type Split struct {
+	Internal int `json:"internal"`
+	External int `json:"external"`
+}
+
+// Project is one analyzed project
+type Project struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Repository      Repository       `json:"repository"`
+	Path            string           `json:"path"`
+	Language        string           `json:"language"`
+	ModuleName      string           `json:"module_name,omitempty"`
+	Service         string           `json:"service,omitempty"`
+	ParentID        string           `json:"parent_id,omitempty"`
+	Members         []string         `json:"members,omitempty"`
+	HasLockfile     bool             `json:"has_lockfile"`
+	Health          *Health          `json:"health,omitempty"`
+	DependencyFiles []DependencyFile `json:"dependency_files"`
+	Dependencies    []Dependency     `json:"dependencies"`
+	Warnings        []Warning        `json:"warnings,omitempty"`
+}
+
+// Repository is the GitLab repository of a project
+type Repository struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	DefaultBranch string `json:"default_branch"`
+	Ref           string `json:"ref,omitempty"` // Branch or tag analyzed instead of the default branch
+	WebURL        string `json:"web_url"`
+}
+
+// Health is the project health score and the penalty ratio of each component
+type Health struct {
+	Score           float64 `json:"score"`
+	Drift           float64 `json:"drift"`
+	Vulnerabilities float64 `json:"vulnerabilities"`
+	Deprecated      float64 `json:"deprecated"`
+	Pinning         float64 `json:"pinning"`
+	Lockfile        float64 `json:"lockfile"`
+}
+
+// DependencyFile is a manifest or lockfile the project was parsed from
+type DependencyFile struct {
+	Path     string `json:"path"`
+	Language string `json:"language"`
+}
+
+// Dependency is one package used by a project
+type Dependency struct {
+	Name               string   `json:"name"`
+	Version            string   `json:"version"`
+	LatestVersion      string   `json:"latest_version"`
+	Constraint         string   `json:"constraint"`
+	MinVersion         string   `json:"min_version"`
+	MaxVersion         string   `json:"max_version"`
+	Ecosystem          string   `json:"ecosystem"`
+	IsInternal         bool     `json:"is_internal"`
+	IsFloating         bool     `json:"is_floating"`
+	IsDeprecated       bool     `json:"is_deprecated"`
+	VulnCount          int      `json:"vuln_count"`
+	VersioningScheme   string   `json:"versioning_scheme,omitempty"`
+	ConflictVersions   []string `json:"conflict_versions,omitempty"`
+	ConstraintMismatch bool     `json:"constraint_mismatch,omitempty"`
+	DeclaredConstraint string   `json:"declared_constraint,omitempty"`
+	Source             string   `json:"source,omitempty"`
+	ReplacedBy         string   `json:"replaced_by,omitempty"`
+	Stale              bool     `json:"stale,omitempty"`
+
+	// Pulled in by another dependency rather than declared by the project, and what pulls it in (1.7)
+	Transitive bool     `json:"transitive,omitempty"`
+	Parents    []string `json:"parents,omitempty"`
+
+	// What the dependency is needed for: runtime, dev, test or optional (1.8)
+	Scope string `json:"scope,omitempty"`
+
+	// Why the version was deprecated or yanked upstream, and the end of support of its release cycle (1.9)
+	Deprecation string `json:"deprecation,omitempty"`
+	IsEndOfLife bool   `json:"is_end_of_life,omitempty"`
+	EndOfLife   string `json:"end_of_life,omitempty"`
+
+	// Known advisories affecting the version, absent unless vulnerabilities were scanned for (1.6)
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// Vulnerability is a published security advisory from OSV.dev
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Severity string   `json:"severity"`
+	Summary  string   `json:"summary,omitempty"`
+}
+
+// Annotation is team knowledge about a dependency: a note, its owner and a recommended replacement
+type Annotation struct {
+	Note        string `json:"note,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Coverage is the share of a repository's dependency files that were analyzed
+type Coverage struct {
+	Repository string        `json:"repository"`
+	URL        string        `json:"url"`
+	Analyzed   int           `json:"analyzed"`
+	Excluded   int           `json:"excluded"` // Skipped on purpose by the scan limits, not counted as gaps
+	Percent    float64       `json:"percent"`
+	Gaps       []CoverageGap `json:"gaps,omitempty"`
+}
+
+// CoverageGap is a dependency file that was not analyzed and why
+type CoverageGap struct {
+	File    string `json:"file"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+// Issue is a repository, project or file the analysis failed on or analyzed only partly
+type Issue struct {
+	Repository string `json:"repository"`
+	URL        string `json:"url"`
+	Project    string `json:"project,omitempty"` // "." for the repository root
+	File       string `json:"file,omitempty"`
+	Stage      string `json:"stage"`
+	Message    string `json:"message"`
+}
+
+// Warning is a detected file that yielded no dependencies
+type Warning struct {
+	File       string `json:"file"`
+	Capability string `json:"capability"`
+	Message    string `json:"message"`
+	Fallback   string `json:"fallback,omitempty"` // Manifest whose declared dependencies replaced the lockfile (1.2)
+}
+
+// New builds the report of projects
+func New(title string, projects []*domain.Project, generatedAt time.Time) *Report {
+	converted := make([]Project, 0, len(projects))
+	for _, project := range projects {
+		converted = append(converted, newProject(project))
+	}
+
+	return &Report{
+		SchemaVersion: SchemaVersion,
+		Title:         title,
+		GeneratedAt:   generatedAt.UTC(),
+		Summary:       newSummary(projects),
+		Projects:      converted,
+		Drift:         NewDrift(projects),
+	}
+}
+
+// Annotate attaches the annotation of a dependency name to the report
+func (r *Report) Annotate(name string, annotation domain.Annotation) {
+	if r.Annotations == nil {
+		r.Annotations = make(map[string]Annotation)
+	}
+	r.Annotations[name] = Annotation{
+		Note:        annotation.Note,
+		Owner:       annotation.Owner,
+		Replacement: annotation.Replacement,
+	}
+}
+
+// WithCoverage attaches the coverage of the analyzed repositories to the report
+func (r *Report) WithCoverage(coverage []domain.RepositoryCoverage) *Report {
+	r.Coverage = nil
+	for _, repository := range coverage {
+		converted := Coverage{
+			Repository: repository.Repository.Name,
+			URL:        repository.Repository.URL,
+			Analyzed:   repository.Analyzed,
+			Excluded:   repository.Excluded,
+			Percent:    math.Round(repository.Percent()*10) / 10,
+		}
+		for _, gap := range repository.Gaps {
+			converted.Gaps = append(converted.Gaps, CoverageGap{
+				File:    gap.File,
+				Reason:  string(gap.Reason),
+				Message: gap.Message,
+			})
+		}
+		r.Coverage = append(r.Coverage, converted)
+	}
+	return r
+}
+
+// WithIssues attaches the errors and warnings of the analysis to the report
+func (r *Report) WithIssues(errors, warnings []domain.AnalysisIssue) *Report {
+	r.Errors, r.Warnings = newIssues(errors), newIssues(warnings)
+	return r
+}
+
+func newIssues(issues []domain.AnalysisIssue) []Issue {
+	var converted []Issue
+	for _, issue := range issues {
+		converted = append(converted, Issue{
+			Repository: issue.Repository.Name,
+			URL:        issue.Repository.URL,
+			Project:    issue.Project,
+			File:       issue.File,
+			Stage:      issue.Stage,
+			Message:    issue.Message,
+		})
+	}
+	return converted
+}
+
+func newSummary(projects []*domain.Project) Summary {
+	summary := Summary{
+		TotalProjects: len(projects),
+		Languages:     make(map[string]int),
+		Ecosystems:    make(map[string]int),
+	}
+
+	healthTotal, scored := 0.0, 0
+	for _, project := range projects {
+		if project.Language != "" {
+			summary.Languages[project.Language]++
+		}
+		if !project.HasLockfile {
+			summary.ProjectsWithoutLockfile++
+		}
+		if project.Health != nil {
+			healthTotal += project.Health.Score
+			scored++
+		}
+
+		for _, dep := range project.Dependencies {
+			summary.TotalDependencies++
+			if dep.IsInternal {
+				summary.InternalExternal.Internal++
+			} else {
+				summary.InternalExternal.External++
+			}
+			if dep.IsFloating {
+				summary.FloatingDependencies++
+			}
+			if dep.Ecosystem != "" {
+				summary.Ecosystems[dep.Ecosystem]++
+			}
+			if len(dep.Vulnerabilities) > 0 {
+				summary.VulnerableDependencies++
+			}
+			if dep.IsDeprecated {
+				summary.DeprecatedDependencies++
+			}
+			if dep.IsEndOfLife {
+				summary.EndOfLifeDependencies++
+			}
+		}
+	}
+
+	if scored > 0 {
+		summary.AverageHealth = math.Round(healthTotal/float64(scored)*10) / 10
+	}
+	return summary
+}
+
+func newProject(project *domain.Project) Project {
+	converted := Project{
+		ID:   project.ID,
+		Name: project.Name,
+		Repository: Repository{
+			ID:            project.Repository.ID,
+			Name:          project.Repository.Name,
+			URL:           project.Repository.URL,
+			DefaultBranch: project.Repository.DefaultBranch,
+			Ref:           project.Repository.Ref,
+			WebURL:        project.Repository.WebURL,
+		},
+		Path:            project.Path,
+		Language:        project.Language,
+		ModuleName:      project.ModuleName,
+		Service:         project.Service,
+		ParentID:        project.ParentID,
+		Members:         project.Members,
+		HasLockfile:     project.HasLockfile,
+		DependencyFiles: make([]DependencyFile, 0, len(project.DependencyFiles)),
+		Dependencies:    make([]Dependency, 0, len(project.Dependencies)),
+	}
+
+	if project.Health != nil {
+		converted.Health = &Health{
+			Score:           project.Health.Score,
+			Drift:           project.Health.Drift,
+			Vulnerabilities: project.Health.Vulnerabilities,
+			Deprecated:      project.Health.Deprecated,
+			Pinning:         project.Health.Pinning,
+			Lockfile:        project.Health.Lockfile,
+		}
+	}
+
+	for _, file := range project.DependencyFiles {
+		converted.DependencyFiles = append(converted.DependencyFiles, DependencyFile{
+			Path:     file.Path,
+			Language: file.Language,
+		})
+	}
+
+	for _, dep := range project.Dependencies {
+		var vulnerabilities []Vulnerability
+		for _, vulnerability := range dep.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:       vulnerability.ID,
+				Aliases:  vulnerability.Aliases,
+				Severity: vulnerability.Severity,
+				Summary:  vulnerability.Summary,
+			})
+		}
+		converted.Dependencies = append(converted.Dependencies, Dependency{
+			Name:               dep.Name,
+			Version:            dep.Version,
+			LatestVersion:      dep.LatestVersion,
+			Constraint:         dep.Constraint,
+			MinVersion:         dep.MinVersion,
+			MaxVersion:         dep.MaxVersion,
+			Ecosystem:          dep.Ecosystem,
+			IsInternal:         dep.IsInternal,
+			IsFloating:         dep.IsFloating,
+			IsDeprecated:       dep.IsDeprecated,
+			VulnCount:          dep.VulnCount,
+			VersioningScheme:   dep.VersioningScheme,
+			ConflictVersions:   dep.ConflictVersions,
+			ConstraintMismatch: dep.ConstraintMismatch,
+			DeclaredConstraint: dep.DeclaredConstraint,
+			Source:             dep.Source,
+			ReplacedBy:         dep.ReplacedBy,
+			Stale:              dep.Stale,
+			Transitive:         !dep.Direct,
+			Parents:            dep.Parents,
+			Scope:              dep.Scope,
+			Deprecation:        dep.Deprecation,
+			IsEndOfLife:        dep.IsEndOfLife,
+			EndOfLife:          dep.EndOfLife,
+			Vulnerabilities:    vulnerabilities,
+		})
+	}
+
+	for _, warning := range project.Warnings {
+		converted.Warnings = append(converted.Warnings, Warning{
+			File:       warning.File,
+			Capability: string(warning.Capability),
+			Message:    warning.Message,
+			Fallback:   warning.Fallback,
+		})
+	}
+
+	return converted
+}
@@ -0,0 +1,229 @@
+package report_test
+
+import (
+	"di-matrix-cli/internal/diff"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/report"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonSchema is the subset of JSON Schema used by report.schema.json
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+}
+
+func loadSchema(t *testing.T) *jsonSchema {
+	t.Helper()
+
+	var schema jsonSchema
+	require.NoError(t, json.Unmarshal(report.Schema(), &schema))
+	return &schema
+}
+
+// assertMatchesSchema checks that the JSON fields of typ are exactly the schema properties
+// and that fields without omitempty are required
+func assertMatchesSchema(t *testing.T, root, schema *jsonSchema, typ reflect.Type, where string) {
+	t.Helper()
+
+	if schema.Ref != "" {
+		schema = root.Defs[strings.TrimPrefix(schema.Ref, "#/$defs/")]
+		require.NotNil(t, schema, "%s: unknown $ref", where)
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() { //nolint:exhaustive // Scalars need no structural check
+	case reflect.Slice:
+		require.NotNil(t, schema.Items, "%s: array without items", where)
+		assertMatchesSchema(t, root, schema.Items, typ.Elem(), where+"[]")
+	case reflect.Map:
+		require.NotNil(t, schema.AdditionalProperties, "%s: map without additionalProperties", where)
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			return
+		}
+
+		var fields, required []string
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			fields = append(fields, name)
+			if options != "omitempty" {
+				required = append(required, name)
+			}
+
+			property := schema.Properties[name]
+			require.NotNil(t, property, "%s.%s is missing from the schema", where, name)
+			assertMatchesSchema(t, root, property, field.Type, where+"."+name)
+		}
+
+		properties := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			properties = append(properties, name)
+		}
+		sort.Strings(fields)
+		sort.Strings(properties)
+		sort.Strings(required)
+		sort.Strings(schema.Required)
+		assert.Equal(t, properties, fields, "%s: schema properties differ from the report fields", where)
+		assert.Equal(t, schema.Required, required, "%s: required schema properties differ", where)
+	}
+}
+
+func TestSchema_MatchesReport(t *testing.T) {
+	t.Parallel()
+
+	schema := loadSchema(t)
+	assertMatchesSchema(t, schema, schema, reflect.TypeOf(report.Report{}), "report")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	projects := []*domain.Project{
+		{
+			ID:              "repo-1-root-go",
+			Repository:      domain.Repository{ID: 1, Name: "api", WebURL: "https://gitlab.com/group/api"},
+			Language:        "go",
+			HasLockfile:     true,
+			Health:          &domain.HealthScore{Score: 80},
+			DependencyFiles: []*domain.DependencyFile{{Path: "go.mod", Language: "go", Content: []byte("module api")}},
+			Dependencies: []*domain.Dependency{
+				{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go-modules", VulnCount: 1,
+					Vulnerabilities: []domain.Vulnerability{{ID: "GO-2023-1737", Severity: domain.SeverityUnknown}}},
+				{Name: "gitlab.company.com/lib", Version: "latest", Ecosystem: "go-modules", IsInternal: true, IsFloating: true},
+			},
+		},
+		{ID: "repo-2-root-python", Language: "python", Health: &domain.HealthScore{Score: 65}},
+	}
+
+	generatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := report.New("Matrix", projects, generatedAt)
+
+	assert.Equal(t, report.SchemaVersion, result.SchemaVersion)
+	assert.True(t, strings.HasPrefix(result.SchemaVersion, report.SchemaMajor+"."))
+	assert.Equal(t, generatedAt, result.GeneratedAt)
+	assert.Equal(t, 2, result.Summary.TotalProjects)
+	assert.Equal(t, 2, result.Summary.TotalDependencies)
+	assert.Equal(t, report.Split{Internal: 1, External: 1}, result.Summary.InternalExternal)
+	assert.Equal(t, 1, result.Summary.FloatingDependencies)
+	assert.Equal(t, 1, result.Summary.ProjectsWithoutLockfile)
+	assert.InDelta(t, 72.5, result.Summary.AverageHealth, 0.01)
+	assert.Equal(t, map[string]int{"go": 1, "python": 1}, result.Summary.Languages)
+	assert.Equal(t, map[string]int{"go-modules": 2}, result.Summary.Ecosystems)
+	assert.Equal(t, 1, result.Summary.VulnerableDependencies)
+	assert.Equal(t, []report.Vulnerability{{ID: "GO-2023-1737", Severity: "unknown"}},
+		result.Projects[0].Dependencies[0].Vulnerabilities)
+
+	// Empty lists are written as [] rather than null
+	require.Len(t, result.Projects, 2)
+	assert.NotNil(t, result.Projects[1].Dependencies)
+	assert.NotNil(t, result.Projects[1].DependencyFiles)
+
+	// File contents stay out of the report
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "content")
+}
+
+func TestReport_WithIssues(t *testing.T) {
+	t.Parallel()
+
+	repository := domain.Repository{Name: "api", URL: "https://gitlab.com/group/api"}
+	errors := []domain.AnalysisIssue{
+		{Repository: repository, Project: ".", File: "go.mod", Stage: domain.StageParse, Message: "go.mod: bad"},
+	}
+	result := report.New("Matrix", nil, time.Now()).WithIssues(errors, nil)
+
+	assert.Equal(t, []report.Issue{{
+		Repository: "api",
+		URL:        "https://gitlab.com/group/api",
+		Project:    ".",
+		File:       "go.mod",
+		Stage:      "parse",
+		Message:    "go.mod: bad",
+	}}, result.Errors)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "warnings", "A run without warnings omits them")
+}
+
+func TestNew_LoadableAsBaseline(t *testing.T) {
+	t.Parallel()
+
+	projects := []*domain.Project{
+		{ID: "api", Dependencies: []*domain.Dependency{{Name: "gin", Version: "v1.9.0"}}},
+	}
+	encoded, err := json.Marshal(report.New("Matrix", projects, time.Now()))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, encoded, 0o600))
+
+	loaded, err := diff.LoadReport(path)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "api", loaded[0].ID)
+	assert.Equal(t, "v1.9.0", loaded[0].Dependencies[0].Version)
+}
+
+func TestNewDrift(t *testing.T) {
+	t.Parallel()
+
+	projects := []*domain.Project{
+		{ID: "api", Dependencies: []*domain.Dependency{
+			{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go-modules"},
+			{Name: "github.com/google/uuid", Version: "v1.6.0", Ecosystem: "go-modules"},
+			{Name: "gitlab.company.com/platform/auth", Version: "v2.0.0", Ecosystem: "go-modules", IsInternal: true},
+		}},
+		{ID: "worker", Dependencies: []*domain.Dependency{
+			{Name: "github.com/gin-gonic/gin", Version: "v1.8.0", Ecosystem: "go-modules"},
+			{Name: "github.com/google/uuid", Version: "v1.6.0", Ecosystem: "go-modules"},
+			{Name: "gitlab.company.com/platform/auth", Version: "v1.4.0", Ecosystem: "go-modules", IsInternal: true},
+		}},
+		{ID: "admin", Dependencies: []*domain.Dependency{
+			{Name: "github.com/gin-gonic/gin", Version: "v1.10.0", Ecosystem: "go-modules"},
+			{Name: "github.com/gin-gonic/gin", Version: "1.9.1", Ecosystem: "go-modules"},
+			{Name: "gitlab.company.com/platform/auth", Version: "v2.0", Ecosystem: "go-modules", IsInternal: true},
+		}},
+	}
+
+	drift := report.NewDrift(projects)
+
+	require.Len(t, drift, 2, "dependencies at a single version do not drift")
+	assert.Equal(t, report.Drift{
+		Name:      "github.com/gin-gonic/gin",
+		Ecosystem: "go-modules",
+		Spread:    3,
+		Versions: []report.DriftVersion{
+			{Version: "v1.10.0", Projects: []string{"admin"}},
+			{Version: "v1.9.1", Projects: []string{"api", "admin"}},
+			{Version: "v1.8.0", Projects: []string{"worker"}},
+		},
+	}, drift[0], "the widest spread comes first, equivalent spellings are one version")
+	assert.Equal(t, "gitlab.company.com/platform/auth", drift[1].Name)
+	assert.True(t, drift[1].Internal)
+	assert.Equal(t, 2, drift[1].Spread)
+	assert.Equal(t, []string{"api", "admin"}, drift[1].Versions[0].Projects)
+
+	assert.Empty(t, report.NewDrift(projects[:1]))
+}
@@ -0,0 +1,31 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is an unexpected HTTP status answered to a request
+type StatusError struct {
+	Status int
+	URL    string
+	Body   string // Error message of the server, if it sent one
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status %d for %s", e.Status, e.URL)
+	}
+	return fmt.Sprintf("unexpected status %d for %s: %s", e.Status, e.URL, e.Body)
+}
+
+// IsTransientHTTP reports whether a failed HTTP request may succeed when retried: network errors, timeouts,
+// rate limiting (429) and server errors (5xx) may, other statuses are final
+func IsTransientHTTP(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Status == http.StatusTooManyRequests || status.Status >= http.StatusInternalServerError
+	}
+	return true
+}
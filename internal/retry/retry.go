@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Policy controls how an operation is retried
+type Policy struct {
+	Retries int           // Attempts after the first one, 0 disables retries
+	Backoff time.Duration // Wait before the first retry, doubled for every further retry
+	Timeout time.Duration // Bound of a single attempt, 0 = no bound beyond the caller's context
+}
+
+// Do runs operation until it succeeds, returns an error retryable rejects, or the retries are used up.
+// Each attempt gets its own context bounded by the policy timeout. A cancelled parent context is never retried.
+func (p Policy) Do(ctx context.Context, retryable func(error) bool, operation func(ctx context.Context) error) error {
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		err := p.attempt(ctx, operation)
+		if err == nil || attempt >= p.Retries || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (p Policy) attempt(ctx context.Context, operation func(ctx context.Context) error) error {
+	if p.Timeout <= 0 {
+		return operation(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	return operation(attemptCtx)
+}
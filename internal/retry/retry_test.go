@@ -0,0 +1,115 @@
+package retry_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/retry"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errFlaky     = errors.New("flaky")
+	errForbidden = errors.New("forbidden")
+)
+
+func retryFlaky(err error) bool {
+	return errors.Is(err, errFlaky) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func TestPolicy_Do(t *testing.T) {
+	t.Parallel()
+
+	policy := retry.Policy{Retries: 3, Backoff: time.Millisecond}
+
+	t.Run("retries until success", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		err := policy.Do(context.Background(), retryFlaky, func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errFlaky
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		err := policy.Do(context.Background(), retryFlaky, func(ctx context.Context) error {
+			attempts++
+			return errFlaky
+		})
+		require.ErrorIs(t, err, errFlaky)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("does not retry rejected errors", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		err := policy.Do(context.Background(), retryFlaky, func(ctx context.Context) error {
+			attempts++
+			return errForbidden
+		})
+		require.ErrorIs(t, err, errForbidden)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestPolicy_Do_Timeout(t *testing.T) {
+	t.Parallel()
+
+	policy := retry.Policy{Retries: 1, Backoff: time.Millisecond, Timeout: 10 * time.Millisecond}
+	attempts := 0
+	err := policy.Do(context.Background(), retryFlaky, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done() // The first attempt hangs until its timeout
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestPolicy_Do_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := retry.Policy{Retries: 5, Backoff: time.Hour}
+	attempts := 0
+	err := policy.Do(ctx, retryFlaky, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errFlaky
+	})
+
+	require.ErrorIs(t, err, errFlaky)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestIsTransientHTTP(t *testing.T) {
+	t.Parallel()
+
+	statusErr := func(status int) error {
+		return fmt.Errorf("failed to get package: %w", &retry.StatusError{Status: status, URL: "https://example.com"})
+	}
+	assert.True(t, retry.IsTransientHTTP(statusErr(http.StatusTooManyRequests)))
+	assert.True(t, retry.IsTransientHTTP(statusErr(http.StatusBadGateway)))
+	assert.True(t, retry.IsTransientHTTP(errors.New("connection reset")), "network errors are retried")
+	assert.False(t, retry.IsTransientHTTP(statusErr(http.StatusForbidden)))
+	assert.False(t, retry.IsTransientHTTP(statusErr(http.StatusNotFound)))
+
+	assert.Equal(t, "unexpected status 401 for https://example.com: token is invalid",
+		(&retry.StatusError{Status: 401, URL: "https://example.com", Body: "token is invalid"}).Error())
+}
@@ -0,0 +1,334 @@
+// Package sarif builds SARIF 2.1.0 logs of vulnerable and outdated dependencies and policy violations, for
+// GitLab and GitHub code scanning and other SARIF dashboards.
+package sarif
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Version is the SARIF version of the logs
+const Version = "2.1.0"
+
+// Schema is the JSON Schema of SARIF 2.1.0 logs
+const Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// ToolName names the tool in the logs
+const ToolName = "di-matrix-cli"
+
+// RuleOutdated is reported for dependencies behind the latest release of their registry
+const RuleOutdated = "dependency.outdated"
+
+// Result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// fingerprintKey names the partial fingerprint dashboards track results across runs by
+const fingerprintKey = "dependencyMatrix/v1"
+
+// Log is a SARIF log
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is the results of one analysis
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool and its rules
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the analysis tool
+type Driver struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a kind of finding: an advisory, outdated dependencies or a policy rule
+type Rule struct {
+	ID                   string         `json:"id"`
+	ShortDescription     Message        `json:"shortDescription"`
+	HelpURI              string         `json:"helpUri,omitempty"`
+	DefaultConfiguration Configuration  `json:"defaultConfiguration"`
+	Properties           RuleProperties `json:"properties"`
+}
+
+// Configuration is the default configuration of a rule
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// RuleProperties are the tags and, for advisories, the severity score code scanning ranks rules by
+type RuleProperties struct {
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity,omitempty"` // "8.0", CVSS-like 0.0 to 10.0
+}
+
+// Message is a plain text message
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is a finding in a dependency file of a project
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          ResultProperties  `json:"properties"`
+}
+
+// ResultProperties identify the project and dependency of a result across a multi-repository analysis
+type ResultProperties struct {
+	Repository string `json:"repository"`
+	Project    string `json:"project"` // Project ID
+	Dependency string `json:"dependency,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+// Location is where a result was found
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a line of a file in the repository
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation is a file path relative to the repository root
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a line of a file, starting at 1
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// securitySeverities scores severities the way code scanning ranks them
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var securitySeverities = map[string]string{
+	domain.SeverityCritical: "9.5",
+	domain.SeverityHigh:     "8.0",
+	domain.SeverityMedium:   "5.5",
+	domain.SeverityLow:      "2.0",
+}
+
+// builder collects rules and results of a run
+type builder struct {
+	run   Run
+	rules map[string]int // Rule ID -> index in run.Tool.Driver.Rules
+}
+
+// New builds a log of the vulnerable and outdated dependencies of projects and of the policy violations.
+// Results are located on the line of the first dependency file of the project naming the dependency.
+func New(projects []*domain.Project, violations []domain.PolicyViolation) *Log {
+	b := &builder{
+		run: Run{
+			Tool:    Tool{Driver: Driver{Name: ToolName, Rules: []Rule{}}},
+			Results: []Result{},
+		},
+		rules: make(map[string]int),
+	}
+
+	byID := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
+		for _, dep := range project.Dependencies {
+			for _, vulnerability := range dep.Vulnerabilities {
+				b.addVulnerability(project, dep, vulnerability)
+			}
+			if version.IsOutdated(dep.Version, dep.LatestVersion) {
+				b.addOutdated(project, dep)
+			}
+		}
+	}
+	for _, violation := range violations {
+		if project, ok := byID[violation.ProjectID]; ok {
+			b.addViolation(project, violation)
+		}
+	}
+
+	return &Log{Schema: Schema, Version: Version, Runs: []Run{b.run}}
+}
+
+func (b *builder) addVulnerability(project *domain.Project, dep *domain.Dependency, advisory domain.Vulnerability) {
+	description := advisory.Summary
+	if description == "" {
+		description = "Known vulnerability " + advisory.ID
+	}
+	index := b.rule(Rule{
+		ID:                   advisory.ID,
+		ShortDescription:     Message{Text: description},
+		HelpURI:              "https://osv.dev/vulnerability/" + advisory.ID,
+		DefaultConfiguration: Configuration{Level: vulnerabilityLevel(advisory.Severity)},
+		Properties: RuleProperties{
+			Tags:             []string{"security", "vulnerability", dep.Ecosystem},
+			SecuritySeverity: securitySeverities[advisory.Severity],
+		},
+	})
+	message := fmt.Sprintf("%s %s is affected by %s", dep.Name, dep.Version, advisory.CVE())
+	if advisory.Summary != "" {
+		message += ": " + advisory.Summary
+	}
+	b.result(index, vulnerabilityLevel(advisory.Severity), message, project, dep.Name, dep.Version)
+}
+
+func (b *builder) addOutdated(project *domain.Project, dep *domain.Dependency) {
+	index := b.rule(Rule{
+		ID:                   RuleOutdated,
+		ShortDescription:     Message{Text: "Dependency behind the latest release of its registry"},
+		DefaultConfiguration: Configuration{Level: LevelNote},
+		Properties:           RuleProperties{Tags: []string{"maintainability", "dependencies"}},
+	})
+	message := fmt.Sprintf("%s %s is outdated, the latest release is %s", dep.Name, dep.Version, dep.LatestVersion)
+	b.result(index, LevelNote, message, project, dep.Name, dep.Version)
+}
+
+func (b *builder) addViolation(project *domain.Project, violation domain.PolicyViolation) {
+	index := b.rule(Rule{
+		ID:                   violation.Rule,
+		ShortDescription:     Message{Text: "Dependency policy " + violation.Rule},
+		DefaultConfiguration: Configuration{Level: LevelError},
+		Properties:           RuleProperties{Tags: []string{"policy", "dependencies"}},
+	})
+	dependencyVersion := ""
+	for _, dep := range project.Dependencies {
+		if dep.Name == violation.Dependency {
+			dependencyVersion = dep.Version
+			break
+		}
+	}
+	b.result(index, LevelError, violation.Message, project, violation.Dependency, dependencyVersion)
+}
+
+// rule returns the index of the rule, adding it on first use
+func (b *builder) rule(rule Rule) int {
+	if index, ok := b.rules[rule.ID]; ok {
+		return index
+	}
+	index := len(b.run.Tool.Driver.Rules)
+	b.rules[rule.ID] = index
+	b.run.Tool.Driver.Rules = append(b.run.Tool.Driver.Rules, rule)
+	return index
+}
+
+// result adds a finding of the rule at index about a dependency of the project, empty for project-level rules
+func (b *builder) result(index int, level, message string, project *domain.Project, dependency, depVersion string) {
+	ruleID := b.run.Tool.Driver.Rules[index].ID
+	path, line := locate(project, dependency)
+	fingerprint := sha256.Sum256([]byte(strings.Join([]string{ruleID, project.ID, dependency, depVersion}, "\x00")))
+	b.run.Results = append(b.run.Results, Result{
+		RuleID:    ruleID,
+		RuleIndex: index,
+		Level:     level,
+		Message:   Message{Text: message},
+		Locations: []Location{{PhysicalLocation: PhysicalLocation{
+			ArtifactLocation: ArtifactLocation{URI: path},
+			Region:           Region{StartLine: line},
+		}}},
+		PartialFingerprints: map[string]string{fingerprintKey: hex.EncodeToString(fingerprint[:16])},
+		Properties: ResultProperties{
+			Repository: project.Repository.Name,
+			Project:    project.ID,
+			Dependency: dependency,
+			Version:    depVersion,
+		},
+	})
+}
+
+// locate returns the dependency file of the project naming the dependency and the line naming it. Without such
+// a file it is the first line of the first dependency file, or the project directory.
+func locate(project *domain.Project, dependency string) (string, int) {
+	files := make([]*domain.DependencyFile, len(project.DependencyFiles))
+	copy(files, project.DependencyFiles)
+	// Manifests before lockfiles of the same directory: shorter names first, "package.json" before its lockfile
+	sort.SliceStable(files, func(i, j int) bool { return len(files[i].Path) < len(files[j].Path) })
+
+	if dependency != "" {
+		// Maven coordinates are split across elements, the artifact ID is on a line of its own
+		names := []string{dependency}
+		if _, artifact, ok := strings.Cut(dependency, ":"); ok {
+			names = append(names, artifact)
+		}
+		for _, name := range names {
+			for _, file := range files {
+				if line := lineOf(file.Content, name); line > 0 {
+					return file.Path, line
+				}
+			}
+		}
+	}
+	if len(files) > 0 {
+		return files[0].Path, 1
+	}
+	if project.Path != "" {
+		return strings.TrimSuffix(project.Path, "/") + "/", 1
+	}
+	return ".", 1
+}
+
+// lineOf returns the first line of content naming the dependency, 0 when none does. Names are matched
+// regardless of case as registries such as PyPI are case-insensitive.
+func lineOf(content []byte, name string) int {
+	lower := bytes.ToLower([]byte(name))
+	for index, line := range bytes.Split(content, []byte("\n")) {
+		if containsName(bytes.ToLower(line), lower) {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+// containsName reports whether the line contains the name on its own, not as part of a longer name
+func containsName(line, name []byte) bool {
+	for offset := 0; ; {
+		at := bytes.Index(line[offset:], name)
+		if at < 0 {
+			return false
+		}
+		start, end := offset+at, offset+at+len(name)
+		if (start == 0 || !isNameByte(line[start-1])) && (end == len(line) || !isNameByte(line[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// isNameByte reports whether b can be part of a package name
+func isNameByte(b byte) bool {
+	return b == '-' || b == '_' || b == '.' || b == '/' || b == '@' ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// vulnerabilityLevel maps an advisory severity to a result level
+func vulnerabilityLevel(severity string) string {
+	switch severity {
+	case domain.SeverityCritical, domain.SeverityHigh:
+		return LevelError
+	case domain.SeverityMedium:
+		return LevelWarning
+	default:
+		return LevelNote
+	}
+}
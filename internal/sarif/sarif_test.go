@@ -0,0 +1,104 @@
+package sarif_test
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/sarif"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sarifProjects() []*domain.Project {
+	return []*domain.Project{
+		{
+			ID:         "web",
+			Repository: domain.Repository{Name: "web"},
+			Path:       "frontend",
+			DependencyFiles: []*domain.DependencyFile{
+				{Path: "frontend/package-lock.json", Content: []byte("{\n  \"lodash\": {}\n}")},
+				{Path: "frontend/package.json", Content: []byte("{\n  \"dependencies\": {\n    \"lodash-es\": \"4\",\n" +
+					"    \"lodash\": \"^4.17.0\"\n  }\n}")},
+			},
+			Dependencies: []*domain.Dependency{
+				{
+					Name: "lodash", Version: "4.17.20", LatestVersion: "4.17.21", Ecosystem: "npm",
+					Vulnerabilities: []domain.Vulnerability{{
+						ID: "GHSA-35jh-r3h4-6jhm", Aliases: []string{"CVE-2021-23337"}, Severity: domain.SeverityHigh,
+						Summary: "Command Injection in lodash",
+					}},
+				},
+				{Name: "express", Version: "4.18.2", LatestVersion: "4.18.2", Ecosystem: "npm"},
+			},
+		},
+		{
+			ID:         "api",
+			Repository: domain.Repository{Name: "api"},
+			DependencyFiles: []*domain.DependencyFile{
+				{Path: "pom.xml", Content: []byte("<dependency>\n  <groupId>org.apache.logging.log4j</groupId>\n" +
+					"  <artifactId>log4j-core</artifactId>\n</dependency>")},
+			},
+			Dependencies: []*domain.Dependency{
+				{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "maven", IsFloating: true},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	violations := []domain.PolicyViolation{
+		{Rule: "pinning.floating", ProjectID: "api", Dependency: "org.apache.logging.log4j:log4j-core",
+			Message: "log4j-core uses a floating constraint"},
+		{Rule: "lockfile.missing", ProjectID: "api", Message: "No lockfile"},
+		{Rule: "dependency.denied", ProjectID: "unknown", Dependency: "left-pad", Message: "not reported"},
+	}
+	log := sarif.New(sarifProjects(), violations)
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "di-matrix-cli", run.Tool.Driver.Name)
+	require.Len(t, run.Results, 4, "vulnerability, outdated and violations of reported projects")
+
+	vulnerable := run.Results[0]
+	assert.Equal(t, "GHSA-35jh-r3h4-6jhm", vulnerable.RuleID)
+	assert.Equal(t, sarif.LevelError, vulnerable.Level)
+	assert.Contains(t, vulnerable.Message.Text, "CVE-2021-23337")
+	location := vulnerable.Locations[0].PhysicalLocation
+	assert.Equal(t, "frontend/package.json", location.ArtifactLocation.URI, "manifests before lockfiles")
+	assert.Equal(t, 4, location.Region.StartLine, "the line naming the dependency, not a longer name")
+	rule := run.Tool.Driver.Rules[vulnerable.RuleIndex]
+	assert.Equal(t, "8.0", rule.Properties.SecuritySeverity)
+	assert.Equal(t, "https://osv.dev/vulnerability/GHSA-35jh-r3h4-6jhm", rule.HelpURI)
+
+	outdated := run.Results[1]
+	assert.Equal(t, sarif.RuleOutdated, outdated.RuleID)
+	assert.Equal(t, sarif.LevelNote, outdated.Level)
+	assert.Equal(t, "web", outdated.Properties.Project)
+	assert.NotEqual(t, vulnerable.PartialFingerprints, outdated.PartialFingerprints)
+
+	floating := run.Results[2]
+	assert.Equal(t, "pinning.floating", floating.RuleID)
+	assert.Equal(t, "2.14.1", floating.Properties.Version)
+	assert.Equal(t, 3, floating.Locations[0].PhysicalLocation.Region.StartLine, "Maven artifacts are located by ID")
+
+	lockfile := run.Results[3]
+	assert.Equal(t, "pom.xml", lockfile.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, lockfile.Locations[0].PhysicalLocation.Region.StartLine, "project-level rules")
+
+	content, err := json.Marshal(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"$schema":"https://json.schemastore.org/sarif-2.1.0.json"`)
+}
+
+func TestNew_Empty(t *testing.T) {
+	t.Parallel()
+
+	content, err := json.Marshal(sarif.New(nil, nil))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"rules":[]`)
+	assert.Contains(t, string(content), `"results":[]`, "an empty log still has a run")
+}
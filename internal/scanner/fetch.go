@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// defaultFileFetcherWorkers is the number of concurrent file content requests per repository
+const defaultFileFetcherWorkers = 8
+
+// WithFileFetcherWorkers sets the number of concurrent file content requests per repository
+func (s *Scanner) WithFileFetcherWorkers(workers int) *Scanner {
+	if workers > 0 {
+		s.fileFetcherWorkers = workers
+	}
+	return s
+}
+
+// fetchFileContents downloads dependency file contents with a bounded worker pool.
+// Files that fail to download are logged and left out of the result.
+func (s *Scanner) fetchFileContents(ctx context.Context, repoURL string, files []string) map[string][]byte {
+	contents := make(map[string][]byte, len(files))
+	var mu sync.Mutex
+
+	workers := s.fileFetcherWorkers
+	if len(files) < workers {
+		workers = len(files)
+	}
+
+	fileChan := make(chan string, len(files))
+	for _, file := range files {
+		fileChan <- file
+	}
+	close(fileChan)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for file := range fileChan {
+				if ctx.Err() != nil {
+					return
+				}
+
+				content, err := s.provider.GetFileContent(ctx, repoURL, file)
+				if err != nil {
+					s.logger.Error("Failed to get file content",
+						zap.String("file", file),
+						zap.Error(err))
+					continue
+				}
+
+				mu.Lock()
+				contents[file] = content
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return contents
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	// goModuleRegex matches the module directive of a go.mod file
+	goModuleRegex = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+	// pyprojectNameRegex matches the first name key of pyproject.toml ([project] or [tool.poetry])
+	pyprojectNameRegex = regexp.MustCompile(`(?m)^name\s*=\s*["']([^"']+)["']`)
+)
+
+// moduleName extracts the module/package name declared by the project's manifests
+func moduleName(files []*domain.DependencyFile) string {
+	for _, file := range files {
+		var name string
+		switch base := filepath.Base(file.Path); base {
+		case "go.mod":
+			if match := goModuleRegex.FindSubmatch(file.Content); match != nil {
+				name = string(match[1])
+			}
+		case "package.json":
+			var manifest struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(file.Content, &manifest); err == nil {
+				name = manifest.Name
+			}
+		case "pom.xml":
+			var pom struct {
+				ArtifactID string `xml:"artifactId"`
+			}
+			if err := xml.Unmarshal(file.Content, &pom); err == nil {
+				name = pom.ArtifactID
+			}
+		case "pyproject.toml":
+			if match := pyprojectNameRegex.FindSubmatch(file.Content); match != nil {
+				name = string(match[1])
+			}
+		case "Cargo.toml":
+			var manifest struct {
+				Package struct {
+					Name string `toml:"name"`
+				} `toml:"package"`
+			}
+			if _, err := toml.NewDecoder(bytes.NewReader(file.Content)).Decode(&manifest); err == nil {
+				name = manifest.Package.Name
+			}
+		default:
+			// .NET projects are named after their project file, e.g. Billing.Api.csproj
+			if project, ok := strings.CutSuffix(base, ".csproj"); ok {
+				name = project
+			}
+		}
+
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// assignModuleIdentities rebuilds IDs and names from module names so they survive directory renames.
+// Projects without a module name, or sharing one with another project of the same language, keep path-based IDs.
+func assignModuleIdentities(repo *domain.Repository, projects []*domain.Project) {
+	counts := make(map[string]int)
+	for _, project := range projects {
+		if project.ModuleName != "" {
+			counts[project.Language+":"+project.ModuleName]++
+		}
+	}
+
+	for _, project := range projects {
+		if project.ModuleName == "" || counts[project.Language+":"+project.ModuleName] > 1 {
+			// Service names are still more recognizable than directory paths
+			if project.Service != "" {
+				project.Name = fmt.Sprintf("%s %s (%s)", repo.Name, CapitalizeFirst(project.Language), project.Service)
+			}
+			continue
+		}
+		project.ID = fmt.Sprintf("repo-%d-%s-%s", repo.ID, project.Language, project.ModuleName)
+		project.Name = fmt.Sprintf("%s %s (%s)", repo.Name, CapitalizeFirst(project.Language), project.ModuleName)
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+)
+
+// VendoredDirs are committed dependency trees and build outputs skipped by default
+//
+//nolint:gochecknoglobals // Read-only default list
+var VendoredDirs = []string{"vendor", "node_modules", ".venv", "dist", "bower_components"}
+
+// WithVendoredDirsSkipped toggles the default exclusion of VendoredDirs
+func (s *Scanner) WithVendoredDirsSkipped(skip bool) *Scanner {
+	s.skipVendored = skip
+	return s
+}
+
+// isExcluded reports whether a dependency file is deeper than the configured maximum depth
+// or lives inside an ignored or vendored directory
+func (s *Scanner) isExcluded(filePath string) bool {
+	dir := path.Dir(strings.TrimPrefix(filePath, "/"))
+	if dir == "." {
+		return false
+	}
+
+	segments := strings.Split(dir, "/")
+	if s.maxDepth > 0 && len(segments) > s.maxDepth {
+		return true
+	}
+
+	for _, glob := range s.ignoreDirs {
+		if matchesIgnoredDir(segments, strings.Trim(glob, "/")) {
+			return true
+		}
+	}
+
+	if s.skipVendored {
+		for _, dir := range VendoredDirs {
+			if matchesIgnoredDir(segments, dir) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesIgnoredDir matches a glob against a directory split into segments.
+// Globs without a slash (e.g. "node_modules", "test*") match any single directory name,
+// globs with a slash (e.g. "docs/examples", "services/*/testdata") match a leading part of the path.
+func matchesIgnoredDir(segments []string, glob string) bool {
+	if glob == "" {
+		return false
+	}
+
+	if !strings.Contains(glob, "/") {
+		for _, segment := range segments {
+			if matched, err := path.Match(glob, segment); err == nil && matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	globDepth := strings.Count(glob, "/") + 1
+	if globDepth > len(segments) {
+		return false
+	}
+	prefix := strings.Join(segments[:globDepth], "/")
+	matched, err := path.Match(glob, prefix)
+	return err == nil && matched
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+)
+
+// PathFilter restricts the dependency files scanned in the repositories of a repository or group entry
+type PathFilter struct {
+	// Subdirectory globs matched against a leading part of the path ("services/api", "services/*"),
+	// empty scans the whole repository
+	Include []string
+	// Directory globs skipped like the ignored directories ("examples", "docs/samples")
+	Exclude []string
+}
+
+// WithPathFilters restricts scanning per repository or group entry keyed by URL,
+// a group entry's filter applies to every repository below it
+func (s *Scanner) WithPathFilters(filters map[string]PathFilter) *Scanner {
+	s.pathFilters = make(map[string]PathFilter, len(filters))
+	for entryURL, filter := range filters {
+		s.pathFilters[normalizeEntryURL(entryURL)] = filter
+	}
+	return s
+}
+
+// pathFilterFor returns the path filter of the closest entry at or above a repository URL
+func (s *Scanner) pathFilterFor(repoURL string) PathFilter {
+	if len(s.pathFilters) == 0 {
+		return PathFilter{}
+	}
+
+	// The repository entry wins over the entries of the groups above it
+	entry := normalizeEntryURL(repoURL)
+	for {
+		if filter, ok := s.pathFilters[entry]; ok {
+			return filter
+		}
+		parent := strings.LastIndex(entry, "/")
+		if parent <= 0 || strings.HasSuffix(entry[:parent], "/") {
+			return PathFilter{}
+		}
+		entry = entry[:parent]
+	}
+}
+
+// normalizeEntryURL drops trailing slashes and the ".git" suffix of clone URLs
+func normalizeEntryURL(entryURL string) string {
+	return strings.TrimSuffix(strings.TrimRight(strings.TrimSpace(entryURL), "/"), ".git")
+}
+
+// excludes reports whether a dependency file lies outside the included subdirectories or inside an excluded one
+func (f PathFilter) excludes(filePath string) bool {
+	var segments []string
+	if dir := path.Dir(strings.TrimPrefix(filePath, "/")); dir != "." {
+		segments = strings.Split(dir, "/")
+	}
+
+	for _, glob := range f.Exclude {
+		if matchesIgnoredDir(segments, strings.Trim(glob, "/")) {
+			return true
+		}
+	}
+
+	if len(f.Include) == 0 {
+		return false
+	}
+	for _, glob := range f.Include {
+		if matchesIncludedDir(segments, strings.Trim(glob, "/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesIncludedDir reports whether a directory split into segments is inside the subdirectory glob,
+// "" and "." include the whole repository
+func matchesIncludedDir(segments []string, glob string) bool {
+	if glob == "" || glob == "." {
+		return true
+	}
+
+	globDepth := strings.Count(glob, "/") + 1
+	if globDepth > len(segments) {
+		return false
+	}
+	matched, err := path.Match(glob, strings.Join(segments[:globDepth], "/"))
+	return err == nil && matched
+}
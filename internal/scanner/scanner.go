@@ -5,7 +5,9 @@ import (
 	"di-matrix-cli/internal/domain"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -14,18 +16,46 @@ import (
 
 // Scanner finds dependency files in repositories and detects projects
 type Scanner struct {
-	gitlabClient domain.GitlabClient
-	logger       *zap.Logger
+	provider       domain.SourceProvider
+	logger         *zap.Logger
+	maxDepth       int                   // Maximum directory depth of dependency files, 0 means unlimited
+	ignoreDirs     []string              // Directory globs whose dependency files are skipped
+	skipVendored   bool                  // Skip VendoredDirs in addition to ignoreDirs
+	pathFilters    map[string]PathFilter // Repository or group entry URL -> scanned subdirectories
+	manifests      map[string]string     // Custom manifest filename -> language
+	detectServices bool                  // Group manifests under Dockerfile/compose service directories
+
+	fileFetcherWorkers int // Concurrent file content requests per repository
+
+	skippedMu sync.Mutex
+	skipped   map[string][]domain.SkippedFile // Repository URL -> dependency files left out of its projects
 }
 
 // NewScanner creates a new file scanner
-func NewScanner(gitlabClient domain.GitlabClient, logger *zap.Logger) *Scanner {
+func NewScanner(provider domain.SourceProvider, logger *zap.Logger) *Scanner {
 	return &Scanner{
-		gitlabClient: gitlabClient,
-		logger:       logger,
+		provider:           provider,
+		logger:             logger,
+		skipVendored:       true,
+		fileFetcherWorkers: defaultFileFetcherWorkers,
+		skipped:            make(map[string][]domain.SkippedFile),
 	}
 }
 
+// WithScanLimits limits the directory depth of scanned dependency files (0 means unlimited)
+// and skips files inside directories matching the ignore globs
+func (s *Scanner) WithScanLimits(maxDepth int, ignoreDirs []string) *Scanner {
+	s.maxDepth = maxDepth
+	s.ignoreDirs = ignoreDirs
+	return s
+}
+
+// WithManifestMappings extends the built-in filename to language table with custom manifests
+func (s *Scanner) WithManifestMappings(manifests map[string]string) *Scanner {
+	s.manifests = manifests
+	return s
+}
+
 // CapitalizeFirst capitalizes the first letter of a string
 func CapitalizeFirst(s string) string {
 	if s == "" {
@@ -43,25 +73,52 @@ func (s *Scanner) DetectProjects(ctx context.Context, repo *domain.Repository) (
 		zap.String("repo_url", repo.URL))
 
 	// Get all files in the repository
-	files, err := s.gitlabClient.GetFilesList(ctx, repo.URL)
+	files, err := s.provider.GetFilesList(ctx, repo.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get files list for repository %s: %w", repo.Name, err)
 	}
 
 	// Filter for dependency files
-	dependencyFiles := s.filterDependencyFiles(files)
+	dependencyFiles, excluded := s.filterDependencyFiles(files, s.pathFilterFor(repo.URL))
+	skipped := make([]domain.SkippedFile, 0, len(excluded))
+	for _, file := range excluded {
+		skipped = append(skipped, s.skippedFile(file, domain.GapExcluded))
+	}
 	if len(dependencyFiles) == 0 {
+		s.recordSkipped(repo.URL, skipped)
 		s.logger.Info("No dependency files found in repository", zap.String("repo_name", repo.Name))
 		return []*domain.Project{}, nil
 	}
 
-	// Group dependency files by project (language + path)
-	projectGroups := s.groupDependencyFilesByProject(dependencyFiles)
+	// Detect service boundaries from Dockerfiles and compose files
+	var serviceRoots map[string]string
+	if s.detectServices {
+		serviceRoots = s.detectServiceRoots(ctx, repo.URL, files)
+	}
+
+	// Fetch all dependency file contents concurrently
+	contents := s.fetchFileContents(ctx, repo.URL, dependencyFiles)
+
+	// Group dependency files by project (language + path), modules used by go.work files are projects of their own
+	projectGroups := s.groupDependencyFilesByProject(dependencyFiles, serviceRoots, goWorkspaceModules(contents))
+	if ctx.Err() == nil {
+		for _, file := range dependencyFiles {
+			if _, fetched := contents[file]; !fetched {
+				skipped = append(skipped, s.skippedFile(file, domain.GapUnavailable))
+			}
+		}
+	}
+	s.recordSkipped(repo.URL, skipped)
 
 	// Create projects from groups
 	var projects []*domain.Project
 	for _, group := range projectGroups {
-		project, err := s.createProjectFromGroup(ctx, repo, group)
+		// Workspace modules kept apart from their service still belong to it
+		service := ""
+		if serviceRoot, ok := serviceRootFor(group.path, serviceRoots); ok {
+			service = serviceRoots[serviceRoot]
+		}
+		project, err := s.createProjectFromGroup(repo, group, contents, service)
 		if err != nil {
 			s.logger.Error("Failed to create project from group",
 				zap.String("repo_name", repo.Name),
@@ -74,6 +131,12 @@ func (s *Scanner) DetectProjects(ctx context.Context, repo *domain.Repository) (
 		}
 	}
 
+	// Prefer stable module-based identities over directory paths
+	assignModuleIdentities(repo, projects)
+
+	// Group workspace members under their workspace root
+	s.linkWorkspaceMembers(projects)
+
 	s.logger.Info("Detected projects in repository",
 		zap.String("repo_name", repo.Name),
 		zap.Int("project_count", len(projects)))
@@ -81,25 +144,79 @@ func (s *Scanner) DetectProjects(ctx context.Context, repo *domain.Repository) (
 	return projects, nil
 }
 
-// filterDependencyFiles filters the file list to only include dependency files
-func (s *Scanner) filterDependencyFiles(files []string) []string {
+// ListDependencyFiles lists the dependency files DetectProjects would download from a repository, only the file
+// tree is requested. Files outside the scan limits or the paths of the repository are returned as excluded.
+func (s *Scanner) ListDependencyFiles(
+	ctx context.Context,
+	repo *domain.Repository,
+) ([]*domain.DependencyFile, []domain.SkippedFile, error) {
+	files, err := s.provider.GetFilesList(ctx, repo.URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get files list for repository %s: %w", repo.Name, err)
+	}
+
+	dependencyFiles, excluded := s.filterDependencyFiles(files, s.pathFilterFor(repo.URL))
+	listed := make([]*domain.DependencyFile, 0, len(dependencyFiles))
+	for _, file := range dependencyFiles {
+		listed = append(listed, &domain.DependencyFile{Path: file, Language: s.DetectLanguageFromFile(file)})
+	}
+	skipped := make([]domain.SkippedFile, 0, len(excluded))
+	for _, file := range excluded {
+		skipped = append(skipped, s.skippedFile(file, domain.GapExcluded))
+	}
+	return listed, skipped, nil
+}
+
+// filterDependencyFiles filters the file list to only include dependency files,
+// dependency files outside the scan limits or the paths of the repository are returned separately
+func (s *Scanner) filterDependencyFiles(files []string, paths PathFilter) ([]string, []string) {
 	var dependencyFiles []string
+	var excluded []string
 	supportedTypes := s.SupportedFileTypes()
 
-	// Create a map for O(1) lookup instead of nested loops
+	// Create a map for O(1) lookup instead of nested loops, globs such as "*.csproj" are matched one by one
 	supportedMap := make(map[string]bool)
+	var supportedGlobs []string
 	for _, fileType := range supportedTypes {
+		if strings.Contains(fileType, "*") {
+			supportedGlobs = append(supportedGlobs, fileType)
+			continue
+		}
 		supportedMap[fileType] = true
 	}
 
 	for _, file := range files {
 		fileName := filepath.Base(file)
-		if supportedMap[fileName] {
-			dependencyFiles = append(dependencyFiles, file)
+		if !supportedMap[fileName] && !matchesAnyGlob(supportedGlobs, fileName) {
+			continue
+		}
+		if s.isExcluded(file) || paths.excludes(file) {
+			s.logger.Debug("Skipping dependency file outside scan limits", zap.String("file", file))
+			excluded = append(excluded, file)
+			continue
 		}
+		dependencyFiles = append(dependencyFiles, file)
 	}
 
-	return dependencyFiles
+	return dependencyFiles, excluded
+}
+
+// SkippedFiles returns the dependency files of a repository that the last DetectProjects call
+// saw but left out of every project: excluded by the scan limits or not downloadable
+func (s *Scanner) SkippedFiles(repoURL string) []domain.SkippedFile {
+	s.skippedMu.Lock()
+	defer s.skippedMu.Unlock()
+	return s.skipped[repoURL]
+}
+
+func (s *Scanner) recordSkipped(repoURL string, skipped []domain.SkippedFile) {
+	s.skippedMu.Lock()
+	defer s.skippedMu.Unlock()
+	s.skipped[repoURL] = skipped
+}
+
+func (s *Scanner) skippedFile(file string, reason domain.CoverageGapReason) domain.SkippedFile {
+	return domain.SkippedFile{File: file, Language: s.DetectLanguageFromFile(file), Reason: reason}
 }
 
 // dependencyFileGroup represents a group of dependency files that belong to the same project
@@ -109,13 +226,22 @@ type dependencyFileGroup struct {
 	files    []string
 }
 
-// groupDependencyFilesByProject groups dependency files by their project (language + path)
-func (s *Scanner) groupDependencyFilesByProject(dependencyFiles []string) []dependencyFileGroup {
+// groupDependencyFilesByProject groups dependency files by their project (language + path).
+// Files below a service directory are grouped under the service directory, except for Go workspace modules.
+func (s *Scanner) groupDependencyFilesByProject(
+	dependencyFiles []string,
+	serviceRoots map[string]string,
+	workspaceModules map[string]bool,
+) []dependencyFileGroup {
 	projectMap := make(map[string]*dependencyFileGroup)
 
 	for _, file := range dependencyFiles {
 		language := s.DetectLanguageFromFile(file)
 		projectPath := s.ExtractProjectPath(file)
+		workspaceModule := language == "go" && workspaceModules[projectPath]
+		if serviceRoot, ok := serviceRootFor(projectPath, serviceRoots); ok && !workspaceModule {
+			projectPath = serviceRoot
+		}
 		groupKey := fmt.Sprintf("%s:%s", language, projectPath)
 
 		if group, exists := projectMap[groupKey]; exists {
@@ -138,20 +264,47 @@ func (s *Scanner) groupDependencyFilesByProject(dependencyFiles []string) []depe
 	return groups
 }
 
+// matchesAnyGlob reports whether a file name matches one of the file type globs
+func matchesAnyGlob(globs []string, fileName string) bool {
+	for _, glob := range globs {
+		if matched, err := filepath.Match(glob, fileName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // DetectLanguageFromFile detects the programming language from a dependency file
 func (s *Scanner) DetectLanguageFromFile(filePath string) string {
 	fileName := strings.ToLower(filepath.Base(filePath))
 
+	for manifest, language := range s.manifests {
+		if strings.ToLower(manifest) == fileName {
+			return language
+		}
+	}
+
 	switch fileName {
-	case "go.mod", "go.sum":
+	case "go.mod", "go.sum", "go.work":
 		return "go"
-	case "package.json", "package-lock.json", "yarn.lock":
+	case "package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml",
+		"pnpm-workspace.yaml":
 		return "nodejs"
-	case "pom.xml", "build.gradle", "gradle.lockfile":
+	case "pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile":
 		return "java"
-	case "requirements.txt", "pipfile", "poetry.lock", "uv.lock", "setup.py":
+	case "requirements.txt", "pipfile", "pipfile.lock", "poetry.lock", "uv.lock", "setup.py", "pyproject.toml":
 		return "python"
+	case "cargo.toml", "cargo.lock":
+		return "rust"
+	case "gemfile", "gemfile.lock":
+		return "ruby"
+	case "packages.lock.json", "packages.config", "directory.packages.props":
+		return "dotnet"
 	default:
+		// Project files are named after the project
+		if strings.HasSuffix(fileName, ".csproj") {
+			return "dotnet"
+		}
 		return "unknown"
 	}
 }
@@ -171,9 +324,10 @@ func (s *Scanner) ExtractProjectPath(filePath string) string {
 
 // createProjectFromGroup creates a Project from a dependency file group
 func (s *Scanner) createProjectFromGroup(
-	ctx context.Context,
 	repo *domain.Repository,
 	group dependencyFileGroup,
+	contents map[string][]byte,
+	service string,
 ) (*domain.Project, error) {
 	// Generate project ID
 	projectID := fmt.Sprintf("repo-%d-%s-%s", repo.ID, group.path, group.language)
@@ -190,11 +344,8 @@ func (s *Scanner) createProjectFromGroup(
 	// Create dependency files with content
 	var dependencyFiles []*domain.DependencyFile
 	for _, file := range group.files {
-		content, err := s.gitlabClient.GetFileContent(ctx, repo.URL, file)
-		if err != nil {
-			s.logger.Error("Failed to get file content",
-				zap.String("file", file),
-				zap.Error(err))
+		content, fetched := contents[file]
+		if !fetched {
 			continue
 		}
 
@@ -212,6 +363,8 @@ func (s *Scanner) createProjectFromGroup(
 		Repository:      *repo,
 		Path:            group.path,
 		Language:        group.language,
+		ModuleName:      moduleName(dependencyFiles),
+		Service:         service,
 		DependencyFiles: dependencyFiles,
 		Dependencies:    []*domain.Dependency{}, // Will be populated by parser
 	}
@@ -219,12 +372,23 @@ func (s *Scanner) createProjectFromGroup(
 	return project, nil
 }
 
-// SupportedFileTypes returns the file types we can scan for
+// SupportedFileTypes returns the file types we can scan for, file names or globs such as "*.csproj"
 func (s *Scanner) SupportedFileTypes() []string {
-	return []string{
-		"go.mod", "go.sum",
-		"package.json", "package-lock.json", "yarn.lock",
-		"pom.xml", "build.gradle", "gradle.lockfile",
-		"requirements.txt", "Pipfile", "poetry.lock", "uv.lock", "setup.py",
+	fileTypes := []string{
+		"go.mod", "go.sum", "go.work",
+		"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "pnpm-workspace.yaml",
+		"pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile",
+		"requirements.txt", "Pipfile", "Pipfile.lock", "poetry.lock", "uv.lock", "setup.py", "pyproject.toml",
+		"Cargo.toml", "Cargo.lock",
+		"Gemfile", "Gemfile.lock",
+		"*.csproj", "packages.lock.json", "packages.config", "Directory.Packages.props",
 	}
+
+	custom := make([]string, 0, len(s.manifests))
+	for manifest := range s.manifests {
+		custom = append(custom, manifest)
+	}
+	sort.Strings(custom)
+
+	return append(fileTypes, custom...)
 }
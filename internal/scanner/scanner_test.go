@@ -4,7 +4,9 @@ import (
 	"context"
 	"di-matrix-cli/internal/domain"
 	"di-matrix-cli/internal/scanner"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -12,7 +14,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// MockGitlabClient is a mock implementation of the GitlabClient interface
+// MockGitlabClient is a mock implementation of the SourceProvider interface
 type MockGitlabClient struct {
 	mock.Mock
 }
@@ -90,15 +92,15 @@ func TestDetectProjects_Success(t *testing.T) {
 	// Check Go project in root
 	goProject := findProjectByLanguage(projects, "go", "")
 	assert.NotNil(t, goProject)
-	assert.Equal(t, "repo-123-root-go", goProject.ID)
-	assert.Equal(t, "test-repo Go", goProject.Name)
+	assert.Equal(t, "repo-123-go-test", goProject.ID)
+	assert.Equal(t, "test-repo Go (test)", goProject.Name)
 	assert.Empty(t, goProject.Path)
 	assert.Len(t, goProject.DependencyFiles, 1)
 
 	// Check Go project in backend
 	backendGoProject := findProjectByLanguage(projects, "go", "backend")
 	assert.NotNil(t, backendGoProject)
-	assert.Equal(t, "repo-123-backend-go", backendGoProject.ID)
+	assert.Equal(t, "repo-123-go-backend", backendGoProject.ID)
 	assert.Equal(t, "test-repo Go (backend)", backendGoProject.Name)
 	assert.Equal(t, "backend", backendGoProject.Path)
 	assert.Len(t, backendGoProject.DependencyFiles, 1)
@@ -106,7 +108,7 @@ func TestDetectProjects_Success(t *testing.T) {
 	// Check Node.js project in frontend
 	nodejsProject := findProjectByLanguage(projects, "nodejs", "frontend")
 	assert.NotNil(t, nodejsProject)
-	assert.Equal(t, "repo-123-frontend-nodejs", nodejsProject.ID)
+	assert.Equal(t, "repo-123-nodejs-frontend", nodejsProject.ID)
 	assert.Equal(t, "test-repo Nodejs (frontend)", nodejsProject.Name)
 	assert.Equal(t, "frontend", nodejsProject.Path)
 	assert.Len(t, nodejsProject.DependencyFiles, 2)
@@ -214,6 +216,11 @@ func TestDetectProjects_GetFileContentError(t *testing.T) {
 	assert.NotNil(t, nodejsProject)
 	assert.Len(t, nodejsProject.DependencyFiles, 1)
 
+	// The file that failed to download is accounted for
+	assert.Equal(t, []domain.SkippedFile{
+		{File: "go.mod", Language: "go", Reason: domain.GapUnavailable},
+	}, s.SkippedFiles(repo.URL))
+
 	mockClient.AssertExpectations(t)
 }
 
@@ -291,10 +298,13 @@ func TestSupportedFileTypes(t *testing.T) {
 	fileTypes := s.SupportedFileTypes()
 
 	expectedTypes := []string{
-		"go.mod", "go.sum",
-		"package.json", "package-lock.json", "yarn.lock",
-		"pom.xml", "build.gradle", "gradle.lockfile",
-		"requirements.txt", "Pipfile", "poetry.lock", "uv.lock", "setup.py",
+		"go.mod", "go.sum", "go.work",
+		"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "pnpm-workspace.yaml",
+		"pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile",
+		"requirements.txt", "Pipfile", "Pipfile.lock", "poetry.lock", "uv.lock", "setup.py", "pyproject.toml",
+		"Cargo.toml", "Cargo.lock",
+		"Gemfile", "Gemfile.lock",
+		"*.csproj", "packages.lock.json", "packages.config", "Directory.Packages.props",
 	}
 
 	assert.ElementsMatch(t, expectedTypes, fileTypes)
@@ -314,14 +324,29 @@ func TestDetectLanguageFromFile(t *testing.T) {
 		{"package.json", "nodejs"},
 		{"package-lock.json", "nodejs"},
 		{"yarn.lock", "nodejs"},
+		{"pnpm-lock.yaml", "nodejs"},
+		{"npm-shrinkwrap.json", "nodejs"},
 		{"pom.xml", "java"},
 		{"build.gradle", "java"},
+		{"build.gradle.kts", "java"},
 		{"gradle.lockfile", "java"},
 		{"requirements.txt", "python"},
 		{"Pipfile", "python"},
+		{"Pipfile.lock", "python"},
 		{"poetry.lock", "python"},
 		{"uv.lock", "python"},
 		{"setup.py", "python"},
+		{"pyproject.toml", "python"},
+		{"go.work", "go"},
+		{"pnpm-workspace.yaml", "nodejs"},
+		{"Cargo.toml", "rust"},
+		{"Cargo.lock", "rust"},
+		{"Gemfile", "ruby"},
+		{"Gemfile.lock", "ruby"},
+		{"Billing.Api.csproj", "dotnet"},
+		{"packages.lock.json", "dotnet"},
+		{"packages.config", "dotnet"},
+		{"Directory.Packages.props", "dotnet"},
 		{"unknown.txt", "unknown"},
 		{"README.md", "unknown"},
 	}
@@ -391,6 +416,72 @@ func TestCapitalizeFirst(t *testing.T) {
 	}
 }
 
+func TestDetectProjects_Workspaces(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop())
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 7, Name: "mono", URL: "https://gitlab.com/test/mono"}
+
+	files := []string{
+		"package.json",
+		"yarn.lock",
+		"packages/ui/package.json",
+		"packages/api/package.json",
+		"tools/cli/package.json",
+		"go.work",
+		"go.mod",
+		"services/auth/go.mod",
+		"java/pom.xml",
+		"java/core/pom.xml",
+		"rust/Cargo.toml",
+		"rust/Cargo.lock",
+		"rust/crates/parser/Cargo.toml",
+	}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	contents := map[string]string{
+		"package.json":              `{"name": "mono", "workspaces": {"packages": ["packages/*"]}}`,
+		"yarn.lock":                 "",
+		"packages/ui/package.json":  `{"name": "@mono/ui"}`,
+		"packages/api/package.json": `{"name": "@mono/api"}`,
+		"tools/cli/package.json":    `{"name": "cli"}`,
+		"go.work":                   "go 1.25\n\nuse (\n\t.\n\t./services/auth\n)\n",
+		"go.mod":                    "module example.com/mono",
+		"services/auth/go.mod":      "module example.com/mono/auth",
+		"java/pom.xml":              `<project><modules><module>core</module></modules></project>`,
+		"java/core/pom.xml":         `<project><artifactId>core</artifactId></project>`,
+		"rust/Cargo.toml":           "[workspace]\nmembers = [\"crates/*\"]\n",
+		"rust/Cargo.lock":           "version = 3\n",
+		"rust/crates/parser/Cargo.toml": "[package]\nname = \"mono-parser\"\n\n" +
+			"[dependencies]\nserde = { workspace = true }\n",
+	}
+	for file, content := range contents {
+		mockClient.On("GetFileContent", ctx, repo.URL, file).Return([]byte(content), nil)
+	}
+
+	projects, err := s.DetectProjects(ctx, repo)
+	require.NoError(t, err)
+
+	nodeRoot := findProjectByLanguage(projects, "nodejs", "")
+	require.NotNil(t, nodeRoot)
+	assert.ElementsMatch(t, []string{"repo-7-nodejs-@mono/ui", "repo-7-nodejs-@mono/api"}, nodeRoot.Members)
+	assert.Equal(t, nodeRoot.ID, findProjectByLanguage(projects, "nodejs", "packages/ui").ParentID)
+	assert.Empty(t, findProjectByLanguage(projects, "nodejs", "tools/cli").ParentID)
+
+	goRoot := findProjectByLanguage(projects, "go", "")
+	require.NotNil(t, goRoot)
+	assert.Equal(t, []string{"repo-7-go-example.com/mono/auth"}, goRoot.Members)
+
+	javaRoot := findProjectByLanguage(projects, "java", "java")
+	require.NotNil(t, javaRoot)
+	assert.Equal(t, javaRoot.ID, findProjectByLanguage(projects, "java", "java/core").ParentID)
+
+	rustRoot := findProjectByLanguage(projects, "rust", "rust")
+	require.NotNil(t, rustRoot)
+	assert.Equal(t, []string{"repo-7-rust-mono-parser"}, rustRoot.Members)
+}
+
 // Helper function to find a project by language and path
 func findProjectByLanguage(projects []*domain.Project, language, path string) *domain.Project {
 	for _, project := range projects {
@@ -400,3 +491,353 @@ func findProjectByLanguage(projects []*domain.Project, language, path string) *d
 	}
 	return nil
 }
+
+func TestDetectProjects_ScanLimits(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).
+		WithScanLimits(2, []string{"node_modules", "test*", "services/*/testdata"})
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 9, Name: "big", URL: "https://gitlab.com/test/big"}
+
+	files := []string{
+		"go.mod",
+		"services/api/go.mod",
+		"services/api/testdata/go.mod",
+		"services/api/internal/tool/go.mod",
+		"web/node_modules/left-pad/package.json",
+		"web/package.json",
+		"tests/package.json",
+	}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	for _, file := range []string{"go.mod", "services/api/go.mod", "web/package.json"} {
+		mockClient.On("GetFileContent", ctx, repo.URL, file).Return([]byte("{}"), nil)
+	}
+
+	projects, err := s.DetectProjects(ctx, repo)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, project := range projects {
+		paths = append(paths, project.Path)
+	}
+	assert.ElementsMatch(t, []string{"", "services/api", "web"}, paths)
+
+	var excluded []string
+	for _, skipped := range s.SkippedFiles(repo.URL) {
+		assert.Equal(t, domain.GapExcluded, skipped.Reason)
+		excluded = append(excluded, skipped.File)
+	}
+	assert.ElementsMatch(t, []string{
+		"services/api/testdata/go.mod",
+		"services/api/internal/tool/go.mod",
+		"web/node_modules/left-pad/package.json",
+		"tests/package.json",
+	}, excluded)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListDependencyFiles(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).WithScanLimits(0, []string{"node_modules"})
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 9, Name: "big", URL: "https://gitlab.com/test/big"}
+
+	mockClient.On("GetFilesList", ctx, repo.URL).Return([]string{
+		"go.mod",
+		"README.md",
+		"web/package.json",
+		"web/node_modules/left-pad/package.json",
+	}, nil)
+
+	files, excluded, err := s.ListDependencyFiles(ctx, repo)
+	require.NoError(t, err)
+
+	languages := make(map[string]string)
+	for _, file := range files {
+		assert.Nil(t, file.Content)
+		languages[file.Path] = file.Language
+	}
+	assert.Equal(t, map[string]string{"go.mod": "go", "web/package.json": "nodejs"}, languages)
+	require.Len(t, excluded, 1)
+	assert.Equal(t, "web/node_modules/left-pad/package.json", excluded[0].File)
+	assert.Equal(t, domain.GapExcluded, excluded[0].Reason)
+	mockClient.AssertNotCalled(t, "GetFileContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDetectProjects_PathFilters(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).WithPathFilters(map[string]scanner.PathFilter{
+		"https://gitlab.com/acme/":          {Exclude: []string{"examples/"}},
+		"https://gitlab.com/acme/mono.git":  {Include: []string{"services/*", "web"}, Exclude: []string{"services/legacy"}},
+		"https://gitlab.com/other/projects": {Include: []string{"cmd"}},
+	})
+
+	ctx := context.Background()
+	files := []string{
+		"go.mod",
+		"services/api/go.mod",
+		"services/legacy/go.mod",
+		"web/package.json",
+		"web/examples/package.json",
+		"tools/go.mod",
+	}
+	detect := func(repo *domain.Repository) ([]string, []string) {
+		t.Helper()
+		mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+		mockClient.On("GetFileContent", ctx, repo.URL, mock.Anything).Return([]byte("{}"), nil)
+
+		projects, err := s.DetectProjects(ctx, repo)
+		require.NoError(t, err)
+		var paths, excluded []string
+		for _, project := range projects {
+			paths = append(paths, project.Path)
+		}
+		for _, skipped := range s.SkippedFiles(repo.URL) {
+			excluded = append(excluded, skipped.File)
+		}
+		return paths, excluded
+	}
+
+	// The repository entry wins over its group
+	paths, excluded := detect(&domain.Repository{ID: 1, Name: "mono", URL: "https://gitlab.com/acme/mono"})
+	assert.ElementsMatch(t, []string{"services/api", "web", "web/examples"}, paths)
+	assert.ElementsMatch(t, []string{"go.mod", "services/legacy/go.mod", "tools/go.mod"}, excluded)
+
+	// Repositories below a group entry use its filter
+	paths, excluded = detect(&domain.Repository{ID: 2, Name: "app", URL: "https://gitlab.com/acme/team/app"})
+	assert.ElementsMatch(t, []string{"", "services/api", "services/legacy", "web", "tools"}, paths)
+	assert.Equal(t, []string{"web/examples/package.json"}, excluded)
+
+	// Unrelated repositories are scanned entirely
+	paths, excluded = detect(&domain.Repository{ID: 3, Name: "lib", URL: "https://gitlab.com/other/lib"})
+	assert.Len(t, paths, 6)
+	assert.Empty(t, excluded)
+}
+
+func TestDetectLanguageFromFile_ManifestMappings(t *testing.T) {
+	t.Parallel()
+	s := scanner.NewScanner(&MockGitlabClient{}, zap.NewNop()).
+		WithManifestMappings(map[string]string{"requirements-dev.txt": "python", "Gopkg.lock": "go"})
+
+	assert.Equal(t, "python", s.DetectLanguageFromFile("tools/requirements-dev.txt"))
+	assert.Equal(t, "go", s.DetectLanguageFromFile("Gopkg.lock"))
+	assert.Equal(t, "nodejs", s.DetectLanguageFromFile("package.json"))
+	assert.Contains(t, s.SupportedFileTypes(), "Gopkg.lock")
+	assert.Contains(t, s.SupportedFileTypes(), "requirements-dev.txt")
+}
+
+func TestDetectProjects_ModuleIdentities(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop())
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 11, Name: "platform", URL: "https://gitlab.com/test/platform"}
+
+	files := []string{
+		"billing/pom.xml", "web/package.json", "web-old/package.json", "tools/pyproject.toml",
+		"payments/src/Payments.Api.csproj", "payments/src/Payments.Api.csproj.user",
+	}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "billing/pom.xml").
+		Return([]byte(`<project><parent><artifactId>root</artifactId></parent><artifactId>billing-api</artifactId></project>`), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "web/package.json").Return([]byte(`{"name": "web"}`), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "web-old/package.json").Return([]byte(`{"name": "web"}`), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "tools/pyproject.toml").
+		Return([]byte("[tool.poetry]\nname = \"platform-tools\"\n"), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "payments/src/Payments.Api.csproj").
+		Return([]byte(`<Project Sdk="Microsoft.NET.Sdk.Web"></Project>`), nil)
+
+	projects, err := s.DetectProjects(ctx, repo)
+	require.NoError(t, err)
+
+	billing := findProjectByLanguage(projects, "java", "billing")
+	require.NotNil(t, billing)
+	assert.Equal(t, "billing-api", billing.ModuleName)
+	assert.Equal(t, "repo-11-java-billing-api", billing.ID)
+	assert.Equal(t, "platform Java (billing-api)", billing.Name)
+
+	tools := findProjectByLanguage(projects, "python", "tools")
+	require.NotNil(t, tools)
+	assert.Equal(t, "repo-11-python-platform-tools", tools.ID)
+
+	// Project files of any name are detected, .NET projects are named after them
+	payments := findProjectByLanguage(projects, "dotnet", "payments/src")
+	require.NotNil(t, payments)
+	assert.Equal(t, "repo-11-dotnet-Payments.Api", payments.ID)
+	require.Len(t, payments.DependencyFiles, 1)
+
+	// Duplicate module names keep path-based IDs
+	assert.Equal(t, "repo-11-web-nodejs", findProjectByLanguage(projects, "nodejs", "web").ID)
+	assert.Equal(t, "repo-11-web-old-nodejs", findProjectByLanguage(projects, "nodejs", "web-old").ID)
+}
+
+func TestDetectProjects_ConcurrentFetchHonorsWorkerLimit(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).WithFileFetcherWorkers(2)
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 12, Name: "many", URL: "https://gitlab.com/test/many"}
+
+	var inFlight, maxInFlight atomic.Int32
+	files := []string{"a/go.mod", "b/go.mod", "c/go.mod", "d/go.mod", "e/go.mod", "f/go.mod"}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, mock.Anything).
+		Run(func(mock.Arguments) {
+			current := inFlight.Add(1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		}).
+		Return([]byte(""), nil)
+
+	projects, err := s.DetectProjects(ctx, repo)
+
+	require.NoError(t, err)
+	assert.Len(t, projects, 6)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	mockClient.AssertNumberOfCalls(t, "GetFileContent", 6)
+}
+
+func TestDetectProjects_SkipsVendoredDirs(t *testing.T) {
+	t.Parallel()
+
+	files := []string{
+		"go.mod",
+		"vendor/github.com/pkg/errors/go.mod",
+		"web/node_modules/react/package.json",
+		"web/package.json",
+		"api/.venv/lib/requirements.txt",
+		"web/dist/package.json",
+		"web/bower_components/jquery/package.json",
+	}
+
+	tests := []struct {
+		name     string
+		skip     bool
+		expected int
+	}{
+		{"default skips vendored", true, 2},
+		{"override keeps vendored", false, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockClient := &MockGitlabClient{}
+			s := scanner.NewScanner(mockClient, zap.NewNop())
+			if !tt.skip {
+				s.WithVendoredDirsSkipped(false)
+			}
+
+			ctx := context.Background()
+			repo := &domain.Repository{ID: 13, Name: "vendored", URL: "https://gitlab.com/test/vendored"}
+			mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+			mockClient.On("GetFileContent", ctx, repo.URL, mock.Anything).Return([]byte(""), nil)
+
+			projects, err := s.DetectProjects(ctx, repo)
+
+			require.NoError(t, err)
+			assert.Len(t, projects, tt.expected)
+		})
+	}
+}
+
+func TestDetectProjects_ServiceDetection(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).WithServiceDetection(true)
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 14, Name: "poly", URL: "https://gitlab.com/test/poly"}
+
+	files := []string{
+		"Dockerfile",
+		"docker-compose.yml",
+		"services/billing/Dockerfile",
+		"services/billing/cmd/worker/go.mod",
+		"services/billing/cmd/api/go.mod",
+		"frontend/app/package.json",
+		"tools/requirements.txt",
+	}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "docker-compose.yml").Return([]byte(`
+services:
+  web:
+    build:
+      context: ./frontend
+  db:
+    image: postgres:16
+`), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, mock.Anything).Return([]byte(""), nil)
+
+	projects, err := s.DetectProjects(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, projects, 3)
+
+	billing := findProjectByLanguage(projects, "go", "services/billing")
+	require.NotNil(t, billing)
+	assert.Equal(t, "billing", billing.Service)
+	assert.Len(t, billing.DependencyFiles, 2)
+	assert.Equal(t, "poly Go (billing)", billing.Name)
+
+	web := findProjectByLanguage(projects, "nodejs", "frontend")
+	require.NotNil(t, web)
+	assert.Equal(t, "web", web.Service)
+
+	tools := findProjectByLanguage(projects, "python", "tools")
+	require.NotNil(t, tools)
+	assert.Empty(t, tools.Service)
+}
+
+func TestDetectProjects_GoWorkspaceModulesInServices(t *testing.T) {
+	t.Parallel()
+	mockClient := &MockGitlabClient{}
+	s := scanner.NewScanner(mockClient, zap.NewNop()).WithServiceDetection(true)
+
+	ctx := context.Background()
+	repo := &domain.Repository{ID: 15, Name: "billing", URL: "https://gitlab.com/test/billing"}
+
+	files := []string{
+		"services/billing/Dockerfile",
+		"services/billing/go.work",
+		"services/billing/cmd/worker/go.mod",
+		"services/billing/cmd/api/go.mod",
+		"services/billing/tools/go.mod",
+	}
+	mockClient.On("GetFilesList", ctx, repo.URL).Return(files, nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "services/billing/go.work").
+		Return([]byte("go 1.25\n\nuse ./cmd/worker\nuse ./cmd/api\n"), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "services/billing/cmd/worker/go.mod").
+		Return([]byte("module example.com/billing/worker"), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "services/billing/cmd/api/go.mod").
+		Return([]byte("module example.com/billing/api"), nil)
+	mockClient.On("GetFileContent", ctx, repo.URL, "services/billing/tools/go.mod").
+		Return([]byte("module example.com/billing/tools"), nil)
+
+	projects, err := s.DetectProjects(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, projects, 3, "modules used by go.work are not merged into the service project")
+
+	service := findProjectByLanguage(projects, "go", "services/billing")
+	require.NotNil(t, service)
+	assert.Len(t, service.DependencyFiles, 2, "go.work and the module outside the workspace")
+	assert.ElementsMatch(t, []string{"repo-15-go-example.com/billing/worker", "repo-15-go-example.com/billing/api"},
+		service.Members)
+
+	worker := findProjectByLanguage(projects, "go", "services/billing/cmd/worker")
+	require.NotNil(t, worker)
+	assert.Equal(t, service.ID, worker.ParentID)
+	assert.Equal(t, "billing", worker.Service)
+}
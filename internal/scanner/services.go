@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// composeFileNames are the docker compose file names recognized as service definitions
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var composeFileNames = map[string]bool{
+	"docker-compose.yml":  true,
+	"docker-compose.yaml": true,
+	"compose.yml":         true,
+	"compose.yaml":        true,
+}
+
+// WithServiceDetection treats directories holding a Dockerfile or a compose build context
+// as project boundaries, grouping manifests in their subdirectories into the service project
+func (s *Scanner) WithServiceDetection(enabled bool) *Scanner {
+	s.detectServices = enabled
+	return s
+}
+
+// detectServiceRoots maps service directories to service names from Dockerfiles and compose files.
+// The repository root is never a service boundary, it would swallow every project.
+func (s *Scanner) detectServiceRoots(ctx context.Context, repoURL string, files []string) map[string]string {
+	roots := make(map[string]string)
+
+	for _, file := range files {
+		if s.isExcluded(file) {
+			continue
+		}
+
+		dir := s.ExtractProjectPath(file)
+		fileName := path.Base(file)
+
+		switch {
+		case fileName == "Dockerfile" || strings.HasPrefix(fileName, "Dockerfile."):
+			if dir != "" {
+				if _, exists := roots[dir]; !exists {
+					roots[dir] = path.Base(dir)
+				}
+			}
+		case composeFileNames[fileName]:
+			content, err := s.provider.GetFileContent(ctx, repoURL, file)
+			if err != nil {
+				s.logger.Warn("Failed to get compose file content", zap.String("file", file), zap.Error(err))
+				continue
+			}
+			for name, buildContext := range composeBuildContexts(content) {
+				serviceDir := path.Clean(path.Join(dir, buildContext))
+				if serviceDir != "." && !strings.HasPrefix(serviceDir, "..") {
+					// Compose service names are more meaningful than directory names
+					roots[serviceDir] = name
+				}
+			}
+		}
+	}
+
+	return roots
+}
+
+// composeBuildContexts returns the build context directory of each compose service
+func composeBuildContexts(content []byte) map[string]string {
+	var compose struct {
+		Services map[string]struct {
+			Build yaml.Node `yaml:"build"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		return nil
+	}
+
+	contexts := make(map[string]string)
+	for name, service := range compose.Services {
+		switch service.Build.Kind {
+		case yaml.ScalarNode:
+			contexts[name] = service.Build.Value
+		case yaml.MappingNode:
+			var build struct {
+				Context string `yaml:"context"`
+			}
+			if err := service.Build.Decode(&build); err == nil && build.Context != "" {
+				contexts[name] = build.Context
+			}
+		}
+	}
+	return contexts
+}
+
+// serviceRootFor returns the closest service directory enclosing projectPath
+func serviceRootFor(projectPath string, roots map[string]string) (string, bool) {
+	for dir := projectPath; dir != "" && dir != "."; dir = path.Dir(dir) {
+		if _, ok := roots[dir]; ok {
+			return dir, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"encoding/xml"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"go.uber.org/zap"
+)
+
+// poetryPathDependencyRegex matches Poetry path dependencies such as `lib = {path = "libs/lib", develop = true}`
+var poetryPathDependencyRegex = regexp.MustCompile(`path\s*=\s*"([^"]+)"`)
+
+// linkWorkspaceMembers reads workspace descriptors (npm/yarn/pnpm workspaces, go.work,
+// Maven <modules>, Poetry path dependencies, Cargo workspaces) and links member projects to their workspace root
+func (s *Scanner) linkWorkspaceMembers(projects []*domain.Project) {
+	for _, root := range projects {
+		memberGlobs := workspaceMemberGlobs(root)
+		if len(memberGlobs) == 0 {
+			continue
+		}
+
+		for _, member := range projects {
+			if member == root || member.Language != root.Language || member.ParentID != "" {
+				continue
+			}
+			if matchesWorkspaceMember(root.Path, member.Path, memberGlobs) {
+				member.ParentID = root.ID
+				root.Members = append(root.Members, member.ID)
+			}
+		}
+
+		if len(root.Members) > 0 {
+			s.logger.Debug("Detected workspace",
+				zap.String("project_id", root.ID),
+				zap.Strings("members", root.Members))
+		}
+	}
+}
+
+// workspaceMemberGlobs returns the member directory globs declared by the project's workspace descriptors
+func workspaceMemberGlobs(project *domain.Project) []string {
+	var globs []string
+	for _, file := range project.DependencyFiles {
+		switch filepath.Base(file.Path) {
+		case "package.json":
+			globs = append(globs, npmWorkspaces(file.Content)...)
+		case "pnpm-workspace.yaml":
+			globs = append(globs, pnpmWorkspaces(file.Content)...)
+		case "go.work":
+			globs = append(globs, goWorkUses(file.Content)...)
+		case "pom.xml":
+			globs = append(globs, mavenModules(file.Content)...)
+		case "pyproject.toml":
+			globs = append(globs, poetryPathDependencies(file.Content)...)
+		case "Cargo.toml":
+			globs = append(globs, cargoWorkspaceMembers(file.Content)...)
+		}
+	}
+	return globs
+}
+
+// npmWorkspaces extracts the "workspaces" field in both array and {"packages": [...]} forms
+func npmWorkspaces(content []byte) []string {
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil || len(manifest.Workspaces) == 0 {
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(manifest.Workspaces, &list); err == nil {
+		return list
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+
+	return nil
+}
+
+// pnpmWorkspaces extracts the "packages" list of pnpm-workspace.yaml
+func pnpmWorkspaces(content []byte) []string {
+	var globs []string
+	inPackages := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "packages:"):
+			inPackages = true
+		case inPackages && strings.HasPrefix(trimmed, "-"):
+			glob := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")), `"'`)
+			if !strings.HasPrefix(glob, "!") {
+				globs = append(globs, glob)
+			}
+		case trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(line, " "):
+			inPackages = false
+		}
+	}
+
+	return globs
+}
+
+// goWorkUses extracts module directories from `use` directives of a go.work file
+func goWorkUses(content []byte) []string {
+	var dirs []string
+	inBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "use (":
+			inBlock = true
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "" && !strings.HasPrefix(line, "//"):
+			dirs = append(dirs, strings.Fields(line)[0])
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, strings.Fields(strings.TrimPrefix(line, "use "))[0])
+		}
+	}
+
+	return dirs
+}
+
+// goWorkspaceModules returns the project paths of the module directories used by the go.work files
+// among the fetched dependency files, the repository root is ""
+func goWorkspaceModules(contents map[string][]byte) map[string]bool {
+	modules := make(map[string]bool)
+	for file, content := range contents {
+		if filepath.Base(file) != "go.work" {
+			continue
+		}
+		for _, dir := range goWorkUses(content) {
+			module := path.Clean(path.Join(path.Dir(file), dir))
+			if module == "." {
+				module = ""
+			}
+			modules[module] = true
+		}
+	}
+	return modules
+}
+
+// mavenModules extracts <modules><module> entries of a parent pom.xml
+func mavenModules(content []byte) []string {
+	var pom struct {
+		Modules []string `xml:"modules>module"`
+	}
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil
+	}
+	return pom.Modules
+}
+
+// poetryPathDependencies extracts local path dependencies declared in pyproject.toml
+func poetryPathDependencies(content []byte) []string {
+	var dirs []string
+	for _, match := range poetryPathDependencyRegex.FindAllSubmatch(content, -1) {
+		dirs = append(dirs, string(match[1]))
+	}
+	return dirs
+}
+
+// cargoWorkspaceMembers extracts the [workspace] members globs of a Cargo.toml
+func cargoWorkspaceMembers(content []byte) []string {
+	var manifest struct {
+		Workspace struct {
+			Members []string `toml:"members"`
+		} `toml:"workspace"`
+	}
+	if _, err := toml.NewDecoder(bytes.NewReader(content)).Decode(&manifest); err != nil {
+		return nil
+	}
+	return manifest.Workspace.Members
+}
+
+// matchesWorkspaceMember checks whether memberPath is covered by one of the globs relative to rootPath
+func matchesWorkspaceMember(rootPath, memberPath string, globs []string) bool {
+	for _, glob := range globs {
+		pattern := path.Clean(path.Join(rootPath, strings.TrimPrefix(glob, "./")))
+		if pattern == "." {
+			continue
+		}
+
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if strings.HasPrefix(memberPath, prefix+"/") {
+				return true
+			}
+			continue
+		}
+
+		if matched, err := path.Match(pattern, memberPath); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
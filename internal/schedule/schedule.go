@@ -0,0 +1,180 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the shorthands accepted in place of the five fields
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var descriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// field is the set of values a schedule field allows
+type field map[int]bool
+
+// Schedule is a cron expression: minute, hour, day of month, month and day of week
+// ("0 6 * * 1-5" runs at 06:00 on weekdays), or a shorthand such as "@daily" or "@every 6h"
+type Schedule struct {
+	expression string
+	every      time.Duration // Fixed interval of "@every" schedules
+	minutes    field
+	hours      field
+	days       field
+	months     field
+	weekdays   field
+	anyDay     bool // Day of month is "*", only the day of week restricts days
+	anyWeekday bool // Day of week is "*", only the day of month restricts days
+}
+
+// Parse parses a cron expression with the standard five fields, each a "*", a value, a range ("1-5"),
+// a step ("*/15", "0-30/10") or a comma-separated list of them. Days of week run from 0 (Sunday) to 7 (Sunday).
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if interval, ok := strings.CutPrefix(expression, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || every < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: @every needs a duration of at least 1m", expression)
+		}
+		return &Schedule{expression: expression, every: every}, nil
+	}
+
+	fields := strings.Fields(expression)
+	if descriptor, ok := descriptors[expression]; ok {
+		fields = strings.Fields(descriptor)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day month weekday), got %d",
+			expression, len(fields))
+	}
+
+	schedule := &Schedule{
+		expression: expression,
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	for _, parse := range []struct {
+		target  *field
+		value   string
+		name    string
+		lowest  int
+		highest int
+	}{
+		{&schedule.minutes, fields[0], "minute", 0, 59},
+		{&schedule.hours, fields[1], "hour", 0, 23},
+		{&schedule.days, fields[2], "day of month", 1, 31},
+		{&schedule.months, fields[3], "month", 1, 12},
+		{&schedule.weekdays, fields[4], "day of week", 0, 7},
+	} {
+		if *parse.target, err = parseField(parse.value, parse.lowest, parse.highest); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %s: %w", expression, parse.name, err)
+		}
+	}
+	if schedule.weekdays[7] {
+		schedule.weekdays[0] = true
+	}
+	if schedule.Next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("invalid schedule %q: it never runs", expression)
+	}
+
+	return schedule, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps between lowest and highest
+func parseField(value string, lowest, highest int) (field, error) {
+	allowed := make(field)
+	for _, part := range strings.Split(value, ",") {
+		span, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepText)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = parsed
+		}
+
+		from, to := lowest, highest
+		if span != "*" {
+			start, end, isRange := strings.Cut(span, "-")
+			var err error
+			if from, err = strconv.Atoi(start); err != nil {
+				return nil, fmt.Errorf("invalid value %q", span)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(end); err != nil {
+					return nil, fmt.Errorf("invalid value %q", span)
+				}
+			} else if hasStep {
+				to = highest
+			}
+		}
+		if from < lowest || to > highest || from > to {
+			return nil, fmt.Errorf("%q is outside %d-%d", span, lowest, highest)
+		}
+
+		for v := from; v <= to; v += step {
+			allowed[v] = true
+		}
+	}
+	return allowed, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expression
+}
+
+// Next returns the first time after t the schedule fires, in the location of t
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every).Truncate(time.Second)
+	}
+
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	// Every combination repeats within a few years, leap days included
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		switch {
+		case !s.months[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !s.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !s.hours[next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !s.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule for days: when both the day of month and the day of week are restricted,
+// a day matching either runs
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day, weekday := s.days[t.Day()], s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,69 @@
+package schedule_test
+
+import (
+	"di-matrix-cli/internal/schedule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Friday 16 October 2026, 10:30
+	now := time.Date(2026, time.October, 16, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"0 6 * * *", time.Date(2026, time.October, 17, 6, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.October, 16, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2026, time.October, 17, 10, 30, 0, 0, time.UTC)},
+		{"0 6 * * 1-5", time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC)},
+		{"0 6 * * 7", time.Date(2026, time.October, 18, 6, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9,17 * * *", time.Date(2026, time.October, 16, 17, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either one runs
+		{"0 0 20 * 1", time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", time.Date(2026, time.October, 16, 16, 30, 15, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := schedule.Parse(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed.Next(now))
+			assert.Equal(t, tt.expression, parsed.String())
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expression := range []string{
+		"",
+		"0 6 * *",
+		"60 * * * *",
+		"0 24 * * *",
+		"0 0 0 * *",
+		"* * * 13 *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+		"@every 10s",
+		"@every soon",
+		"@sometimes",
+	} {
+		_, err := schedule.Parse(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyLayout names archived reports after the time their analysis finished, so names sort chronologically
+const historyLayout = "20060102T150405Z"
+
+// Archived is a report kept in the history
+type Archived struct {
+	ID         string    `json:"id"`
+	FinishedAt time.Time `json:"finished_at"`
+	HTML       string    `json:"html"` // URL of the HTML report
+	JSON       string    `json:"json"` // URL of the JSON report
+}
+
+// WithHistory keeps the reports of the last keep analyses in dir, next to the latest one (0 keeps none)
+func (s *Server) WithHistory(dir string, keep int) *Server {
+	s.history = dir
+	s.keep = keep
+	return s
+}
+
+// archive copies the reports of the analysis finished at finished into the history and drops the oldest ones
+func (s *Server) archive(finished time.Time) error {
+	if s.history == "" || s.keep == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.history, 0o750); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	id := finished.UTC().Format(historyLayout)
+	if err := copyFile(s.htmlPath, filepath.Join(s.history, id+".html")); err != nil {
+		return err
+	}
+	if err := copyFile(s.jsonPath, filepath.Join(s.history, id+".json")); err != nil {
+		return err
+	}
+
+	archived, err := s.archived()
+	if err != nil {
+		return err
+	}
+	for _, old := range archived[min(s.keep, len(archived)):] {
+		for _, extension := range []string{".html", ".json"} {
+			if err := os.Remove(filepath.Join(s.history, old.ID+extension)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove archived report: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// archived lists the reports in the history, newest first
+func (s *Server) archived() ([]Archived, error) {
+	if s.history == "" {
+		return []Archived{}, nil
+	}
+	entries, err := os.ReadDir(s.history)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list archived reports: %w", err)
+	}
+
+	archived := []Archived{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		finished, err := time.Parse(historyLayout, id)
+		if err != nil {
+			continue
+		}
+		archived = append(archived, Archived{
+			ID:         id,
+			FinishedAt: finished,
+			HTML:       "/reports/" + id + ".html",
+			JSON:       "/reports/" + id + ".json",
+		})
+	}
+	sort.Slice(archived, func(i, j int) bool { return archived[i].ID > archived[j].ID })
+	return archived, nil
+}
+
+func (s *Server) serveReports(w http.ResponseWriter, r *http.Request) {
+	archived, err := s.archived()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, archived)
+}
+
+func (s *Server) serveArchived(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	id := strings.TrimSuffix(strings.TrimSuffix(file, ".html"), ".json")
+	if _, err := time.Parse(historyLayout, id); err != nil || s.history == "" {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, filepath.Join(s.history, file))
+}
+
+// copyFile copies the file at from to to
+func copyFile(from, to string) error {
+	source, err := os.Open(from) //nolint:gosec // Report paths are set by the server
+	if err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+	defer source.Close()
+
+	target, err := os.Create(to) //nolint:gosec // Archive paths are set by the server
+	if err != nil {
+		return fmt.Errorf("failed to archive report: %w", err)
+	}
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return fmt.Errorf("failed to archive report: %w", err)
+	}
+	if err := target.Close(); err != nil {
+		return fmt.Errorf("failed to archive report: %w", err)
+	}
+	return nil
+}
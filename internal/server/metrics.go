@@ -0,0 +1,57 @@
+package server
+
+import (
+	"di-matrix-cli/internal/metrics"
+	"net/http"
+)
+
+// Record keeps the measurements of the latest analysis for /metrics
+func (s *Server) Record(analysis metrics.Analysis) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.measured = &analysis
+}
+
+// families returns the metric families of the server followed by those of the latest analysis
+func (s *Server) families() []metrics.Family {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	gauge := func(name, help string, value float64) metrics.Family {
+		return metrics.Family{Name: name, Help: help, Type: metrics.Gauge, Samples: []metrics.Sample{{Value: value}}}
+	}
+	running := 0.0
+	if s.status.Running {
+		running = 1
+	}
+	families := []metrics.Family{
+		gauge("di_matrix_analysis_running", "Whether an analysis is running.", running),
+		{
+			Name:    "di_matrix_analyses_total",
+			Help:    "Analyses finished since the server started.",
+			Type:    metrics.Counter,
+			Samples: []metrics.Sample{{Value: float64(s.status.Analyses)}},
+		},
+	}
+	if s.status.Analyses > 0 {
+		succeeded := 0.0
+		if s.status.LastError == "" {
+			succeeded = 1
+		}
+		families = append(families, gauge("di_matrix_last_analysis_success",
+			"Whether the last analysis succeeded.", succeeded))
+	}
+	if s.status.NextRun != nil {
+		families = append(families, gauge("di_matrix_next_analysis_timestamp_seconds",
+			"Time of the next scheduled analysis.", float64(s.status.NextRun.Unix())))
+	}
+	if s.measured != nil {
+		families = append(families, s.measured.Families()...)
+	}
+	return families
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_ = metrics.Write(w, s.families()...)
+}
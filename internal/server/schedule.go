@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Schedule tells when the next analysis runs, such as a cron expression
+type Schedule interface {
+	// Next returns the first time after t the analysis runs, the zero time when it never runs again
+	Next(t time.Time) time.Time
+	// String describes the schedule in the status
+	String() string
+}
+
+// RunSchedule reruns the analysis whenever schedule fires until ctx is cancelled. Runs falling while an analysis
+// is still in progress are skipped rather than queued.
+func (s *Server) RunSchedule(ctx context.Context, schedule Schedule) {
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		s.mu.Lock()
+		s.status.Schedule = schedule.String()
+		s.status.NextRun = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.Trigger() {
+			s.logger.Info("Skipping scheduled analysis, the previous one is still running",
+				zap.String("schedule", schedule.String()))
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"di-matrix-cli/internal/metrics"
+	"di-matrix-cli/internal/report"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Analyzer runs an analysis writing the HTML and JSON reports the server serves. Reports written by analyses
+// that fail afterwards, for instance on policy violations, are served all the same.
+type Analyzer func(ctx context.Context) error
+
+// Status describes the analyses run by the server
+type Status struct {
+	Running      bool       `json:"running"`
+	LastStarted  *time.Time `json:"last_started,omitempty"`
+	LastFinished *time.Time `json:"last_finished,omitempty"`
+	LastError    string     `json:"last_error,omitempty"` // Error of the last analysis, absent when it succeeded
+	Analyses     int        `json:"analyses"`             // Analyses finished since the server started
+	Schedule     string     `json:"schedule,omitempty"`   // Cron expression rerunning the analysis
+	NextRun      *time.Time `json:"next_run,omitempty"`   // Next scheduled analysis
+}
+
+// Server exposes the latest reports over HTTP and reruns the analysis on demand
+type Server struct {
+	ctx      context.Context // Cancels running analyses when the server stops
+	analyze  Analyzer
+	htmlPath string
+	jsonPath string
+	history  string // Directory keeping the reports of earlier analyses, empty keeps none
+	keep     int    // Analyses kept in the history
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	report   *report.Report    // Latest JSON report, nil until one was written
+	measured *metrics.Analysis // Measurements of the latest analysis, nil until one was recorded
+	status   Status
+	running  sync.WaitGroup
+}
+
+// New creates a server for the reports analyze writes to htmlPath and jsonPath
+func New(ctx context.Context, analyze Analyzer, htmlPath, jsonPath string, logger *zap.Logger) *Server {
+	return &Server{ctx: ctx, analyze: analyze, htmlPath: htmlPath, jsonPath: jsonPath, logger: logger}
+}
+
+// Load reads the JSON report written by an earlier analysis, so it is served before the next one finishes
+func (s *Server) Load() error {
+	content, err := os.ReadFile(s.jsonPath)
+	if err != nil {
+		return fmt.Errorf("failed to read report %s: %w", s.jsonPath, err)
+	}
+
+	var latest report.Report
+	if err := json.Unmarshal(content, &latest); err != nil {
+		return fmt.Errorf("failed to parse report %s: %w", s.jsonPath, err)
+	}
+
+	s.mu.Lock()
+	s.report = &latest
+	s.mu.Unlock()
+	return nil
+}
+
+// Trigger starts an analysis in the background and reports whether it did, only one analysis runs at a time
+func (s *Server) Trigger() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return false
+	}
+
+	started := time.Now().UTC()
+	s.status.Running = true
+	s.status.LastStarted = &started
+	s.running.Add(1)
+	go s.run()
+	return true
+}
+
+// Wait blocks until the running analysis, if any, finished
+func (s *Server) Wait() {
+	s.running.Wait()
+}
+
+// Status returns the state of the analyses
+func (s *Server) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// run performs one analysis and loads the report it wrote
+func (s *Server) run() {
+	defer s.running.Done()
+
+	s.logger.Info("Starting analysis")
+	err := s.analyze(s.ctx)
+	if err != nil {
+		s.logger.Warn("Analysis finished with an error", zap.Error(err))
+	}
+	finished := time.Now().UTC()
+	if loadErr := s.Load(); loadErr != nil {
+		s.logger.Error("Failed to load the report of the analysis", zap.Error(loadErr))
+		err = errors.Join(err, loadErr)
+	} else if archiveErr := s.archive(finished); archiveErr != nil {
+		// The latest report is served all the same
+		s.logger.Warn("Failed to keep the report in the history", zap.Error(archiveErr))
+	}
+
+	s.mu.Lock()
+	s.status.Running = false
+	s.status.LastFinished = &finished
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+	s.status.Analyses++
+	s.mu.Unlock()
+	s.logger.Info("Analysis finished")
+}
+
+// latest returns the latest report, nil until one was written
+func (s *Server) latest() *report.Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report
+}
+
+// Handler serves the HTML report at /, the REST API under /api and Prometheus metrics at /metrics
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveHTML)
+	mux.HandleFunc("GET /api/summary", s.serveSummary)
+	mux.HandleFunc("GET /api/projects", s.serveProjects)
+	mux.HandleFunc("GET /api/matrix", s.serveMatrix)
+	mux.HandleFunc("POST /api/analyze", s.serveAnalyze)
+	mux.HandleFunc("GET /api/reports", s.serveReports)
+	mux.HandleFunc("GET /reports/{file}", s.serveArchived)
+	mux.HandleFunc("GET /metrics", s.serveMetrics)
+	return mux
+}
+
+func (s *Server) serveHTML(w http.ResponseWriter, r *http.Request) {
+	if s.latest() == nil {
+		http.Error(w, "No report yet, the first analysis is still running", http.StatusServiceUnavailable)
+		return
+	}
+	http.ServeFile(w, r, s.htmlPath)
+}
+
+// summary is the response of /api/summary
+type summary struct {
+	Title       string          `json:"title,omitempty"`
+	GeneratedAt *time.Time      `json:"generated_at,omitempty"`
+	Incomplete  string          `json:"incomplete,omitempty"`
+	Summary     *report.Summary `json:"summary,omitempty"` // Absent until the first report was written
+	Status      Status          `json:"status"`
+}
+
+func (s *Server) serveSummary(w http.ResponseWriter, r *http.Request) {
+	response := summary{Status: s.Status()}
+	if latest := s.latest(); latest != nil {
+		response.Title = latest.Title
+		response.GeneratedAt = &latest.GeneratedAt
+		response.Incomplete = latest.Incomplete
+		response.Summary = &latest.Summary
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) serveProjects(w http.ResponseWriter, r *http.Request) {
+	latest := s.latest()
+	if latest == nil {
+		writeError(w, http.StatusServiceUnavailable, "no report yet, the first analysis is still running")
+		return
+	}
+	writeJSON(w, http.StatusOK, latest.Projects)
+}
+
+func (s *Server) serveMatrix(w http.ResponseWriter, r *http.Request) {
+	latest := s.latest()
+	if latest == nil {
+		writeError(w, http.StatusServiceUnavailable, "no report yet, the first analysis is still running")
+		return
+	}
+	writeJSON(w, http.StatusOK, NewMatrix(latest.Projects))
+}
+
+func (s *Server) serveAnalyze(w http.ResponseWriter, r *http.Request) {
+	if !s.Trigger() {
+		writeError(w, http.StatusConflict, "an analysis is already running")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, s.Status())
+}
+
+// MatrixProject is a column of the dependency matrix
+type MatrixProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// MatrixDependency is a row of the dependency matrix
+type MatrixDependency struct {
+	Name      string            `json:"name"`
+	Ecosystem string            `json:"ecosystem"`
+	Internal  bool              `json:"internal"`
+	Versions  map[string]string `json:"versions"` // Version used by each project, keyed by project ID
+}
+
+// Matrix is the version every project uses of every dependency
+type Matrix struct {
+	Projects     []MatrixProject    `json:"projects"`
+	Dependencies []MatrixDependency `json:"dependencies"` // Sorted by ecosystem and name
+}
+
+// NewMatrix builds the dependency matrix of report projects
+func NewMatrix(projects []report.Project) Matrix {
+	matrix := Matrix{Projects: make([]MatrixProject, 0, len(projects)), Dependencies: []MatrixDependency{}}
+	rows := make(map[string]int)
+	for _, project := range projects {
+		matrix.Projects = append(matrix.Projects, MatrixProject{ID: project.ID, Name: project.Name})
+		for _, dep := range project.Dependencies {
+			key := dep.Ecosystem + "\x00" + dep.Name
+			row, ok := rows[key]
+			if !ok {
+				row = len(matrix.Dependencies)
+				rows[key] = row
+				matrix.Dependencies = append(matrix.Dependencies, MatrixDependency{
+					Name:      dep.Name,
+					Ecosystem: dep.Ecosystem,
+					Internal:  dep.IsInternal,
+					Versions:  make(map[string]string),
+				})
+			}
+			matrix.Dependencies[row].Versions[project.ID] = dep.Version
+		}
+	}
+
+	sort.Slice(matrix.Dependencies, func(i, j int) bool {
+		if matrix.Dependencies[i].Ecosystem != matrix.Dependencies[j].Ecosystem {
+			return matrix.Dependencies[i].Ecosystem < matrix.Dependencies[j].Ecosystem
+		}
+		return matrix.Dependencies[i].Name < matrix.Dependencies[j].Name
+	})
+	return matrix
+}
+
+// writeJSON writes value as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+// writeError writes an error response with a JSON body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,263 @@
+package server_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/metrics"
+	"di-matrix-cli/internal/report"
+	"di-matrix-cli/internal/server"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// writeReports returns an analyzer writing an HTML page and a JSON report of projects
+func writeReports(t *testing.T, htmlPath, jsonPath string, projects []*domain.Project, err error) server.Analyzer {
+	t.Helper()
+	return func(ctx context.Context) error {
+		content, encodeErr := json.Marshal(report.New("Matrix", projects, time.Now()))
+		if encodeErr != nil {
+			return encodeErr
+		}
+		if writeErr := os.WriteFile(jsonPath, content, 0o600); writeErr != nil {
+			return writeErr
+		}
+		if writeErr := os.WriteFile(htmlPath, []byte("<html>matrix</html>"), 0o600); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+}
+
+func get(t *testing.T, url string, into any) int {
+	t.Helper()
+	response, err := http.Get(url) //nolint:noctx // Test request
+	require.NoError(t, err)
+	defer response.Body.Close()
+	if into != nil {
+		require.NoError(t, json.NewDecoder(response.Body).Decode(into))
+	}
+	return response.StatusCode
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	htmlPath, jsonPath := filepath.Join(dir, "index.html"), filepath.Join(dir, "report.json")
+	projects := []*domain.Project{
+		{ID: "api", Name: "api Go", Language: "go", Dependencies: []*domain.Dependency{
+			{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go", Direct: true},
+			{Name: "gitlab.com/acme/auth", Version: "v1.2.0", Ecosystem: "go", IsInternal: true, Direct: true},
+		}},
+		{ID: "worker", Name: "worker Go", Language: "go", Dependencies: []*domain.Dependency{
+			{Name: "github.com/gin-gonic/gin", Version: "v1.8.0", Ecosystem: "go", Direct: true},
+		}},
+	}
+	srv := server.New(context.Background(),
+		writeReports(t, htmlPath, jsonPath, projects, errors.New("2 policy violations found")),
+		htmlPath, jsonPath, zap.NewNop())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	// Nothing to serve before the first analysis
+	var failure map[string]string
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, ts.URL+"/api/projects", &failure))
+	assert.NotEmpty(t, failure["error"])
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, ts.URL+"/", nil))
+
+	response, err := http.Post(ts.URL+"/api/analyze", "", nil) //nolint:noctx // Test request
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	srv.Wait()
+
+	var summary struct {
+		Title   string         `json:"title"`
+		Summary report.Summary `json:"summary"`
+		Status  server.Status  `json:"status"`
+	}
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/api/summary", &summary))
+	assert.Equal(t, "Matrix", summary.Title)
+	assert.Equal(t, 2, summary.Summary.TotalProjects)
+	assert.False(t, summary.Status.Running)
+	assert.Equal(t, 1, summary.Status.Analyses)
+	assert.Equal(t, "2 policy violations found", summary.Status.LastError, "reports of failed analyses are served")
+
+	var served []report.Project
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/api/projects", &served))
+	require.Len(t, served, 2)
+	assert.Equal(t, "api", served[0].ID)
+
+	var matrix server.Matrix
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/api/matrix", &matrix))
+	assert.Equal(t, []server.MatrixProject{{ID: "api", Name: "api Go"}, {ID: "worker", Name: "worker Go"}},
+		matrix.Projects)
+	require.Len(t, matrix.Dependencies, 2)
+	assert.Equal(t, "github.com/gin-gonic/gin", matrix.Dependencies[0].Name)
+	assert.Equal(t, map[string]string{"api": "v1.9.1", "worker": "v1.8.0"}, matrix.Dependencies[0].Versions)
+	assert.True(t, matrix.Dependencies[1].Internal)
+
+	page, err := http.Get(ts.URL + "/") //nolint:noctx // Test request
+	require.NoError(t, err)
+	body, err := io.ReadAll(page.Body)
+	_ = page.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "<html>matrix</html>", string(body))
+}
+
+func TestServer_Trigger(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	htmlPath, jsonPath := filepath.Join(dir, "index.html"), filepath.Join(dir, "report.json")
+	release := make(chan struct{})
+	analyze := writeReports(t, htmlPath, jsonPath, nil, nil)
+	srv := server.New(context.Background(), func(ctx context.Context) error {
+		<-release
+		return analyze(ctx)
+	}, htmlPath, jsonPath, zap.NewNop())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	require.True(t, srv.Trigger())
+	assert.True(t, srv.Status().Running)
+
+	response, err := http.Post(ts.URL+"/api/analyze", "", nil) //nolint:noctx // Test request
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusConflict, response.StatusCode, "one analysis at a time")
+
+	close(release)
+	srv.Wait()
+	assert.Empty(t, srv.Status().LastError)
+	assert.True(t, srv.Trigger(), "a finished analysis can be rerun")
+	srv.Wait()
+	assert.Equal(t, 2, srv.Status().Analyses)
+}
+
+func TestServer_History(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	htmlPath, jsonPath := filepath.Join(dir, "index.html"), filepath.Join(dir, "report.json")
+	history := filepath.Join(dir, "history")
+	srv := server.New(context.Background(), writeReports(t, htmlPath, jsonPath, nil, nil), htmlPath, jsonPath,
+		zap.NewNop()).WithHistory(history, 2)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var archived []server.Archived
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/api/reports", &archived))
+	assert.Empty(t, archived)
+
+	// Archived reports are named after the second their analysis finished
+	for range 3 {
+		require.True(t, srv.Trigger())
+		srv.Wait()
+		time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	}
+
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+"/api/reports", &archived))
+	require.Len(t, archived, 2, "only the last reports are kept")
+	assert.True(t, archived[0].FinishedAt.After(archived[1].FinishedAt), "newest first")
+	entries, err := os.ReadDir(history)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	var served report.Report
+	assert.Equal(t, http.StatusOK, get(t, ts.URL+archived[1].JSON, &served))
+	assert.Equal(t, "Matrix", served.Title)
+	page, err := http.Get(ts.URL + archived[0].HTML) //nolint:noctx // Test request
+	require.NoError(t, err)
+	_ = page.Body.Close()
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+
+	assert.Equal(t, http.StatusNotFound, get(t, ts.URL+"/reports/report.json", nil))
+	assert.Equal(t, http.StatusNotFound, get(t, ts.URL+"/reports/20000101T000000Z.json", nil))
+}
+
+// everyTick fires the schedule shortly after every call
+type everyTick struct{}
+
+func (everyTick) Next(t time.Time) time.Time { return t.Add(10 * time.Millisecond) }
+func (everyTick) String() string             { return "@every 10ms" }
+
+func TestServer_RunSchedule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	htmlPath, jsonPath := filepath.Join(dir, "index.html"), filepath.Join(dir, "report.json")
+	analyze := writeReports(t, htmlPath, jsonPath, nil, nil)
+	var runs atomic.Int32
+	srv := server.New(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return analyze(ctx)
+	}, htmlPath, jsonPath, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.RunSchedule(ctx, everyTick{})
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, 5*time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+	srv.Wait()
+
+	status := srv.Status()
+	assert.Equal(t, "@every 10ms", status.Schedule)
+	assert.NotNil(t, status.NextRun)
+	assert.GreaterOrEqual(t, status.Analyses, 2)
+}
+
+func TestServer_Metrics(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	htmlPath, jsonPath := filepath.Join(dir, "index.html"), filepath.Join(dir, "report.json")
+	analyze := writeReports(t, htmlPath, jsonPath, nil, errors.New("policy violations found"))
+	var srv *server.Server
+	srv = server.New(context.Background(), func(ctx context.Context) error {
+		srv.Record(metrics.Analysis{Language: "go", Dependencies: 12, Outdated: 3})
+		return analyze(ctx)
+	}, htmlPath, jsonPath, zap.NewNop())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	scrape := func() string {
+		t.Helper()
+		response, err := http.Get(ts.URL + "/metrics") //nolint:noctx // Test request
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, metrics.ContentType, response.Header.Get("Content-Type"))
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	before := scrape()
+	assert.Contains(t, before, "di_matrix_analyses_total 0\n")
+	assert.NotContains(t, before, "di_matrix_dependencies", "nothing measured before the first analysis")
+
+	require.True(t, srv.Trigger())
+	srv.Wait()
+	after := scrape()
+	assert.Contains(t, after, "# TYPE di_matrix_analyses_total counter\ndi_matrix_analyses_total 1\n")
+	assert.Contains(t, after, "di_matrix_last_analysis_success 0\n")
+	assert.Contains(t, after, "di_matrix_dependencies{language=\"go\"} 12\n")
+	assert.Contains(t, after, "di_matrix_outdated_dependencies{language=\"go\"} 3\n")
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"di-matrix-cli/internal/domain"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// formatVersion is bumped whenever the state layout changes, older state files are rejected
+const formatVersion = 1
+
+// State records the commit every repository was last analyzed at, with its projects, so the next
+// incremental run only analyzes the repositories that changed since
+type State struct {
+	Version      int                    `json:"version"`
+	Language     string                 `json:"language"`
+	Fingerprint  string                 `json:"fingerprint"` // Settings the projects were analyzed with
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Repositories map[string]*Repository `json:"repositories"` // Keyed by repository URL
+}
+
+// Repository is the last analysis of a repository
+type Repository struct {
+	Commit   string            `json:"commit"` // SHA of the analyzed ref
+	Projects []*domain.Project `json:"projects"`
+}
+
+// New creates an empty state for analyses of language with the settings summarized by fingerprint
+func New(language, fingerprint string) *State {
+	return &State{
+		Version:      formatVersion,
+		Language:     language,
+		Fingerprint:  fingerprint,
+		Repositories: make(map[string]*Repository),
+	}
+}
+
+// Load reads a state written by Save
+func Load(path string) (*State, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // Path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state %s: %w", path, err)
+	}
+
+	var state State
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	if state.Version != formatVersion {
+		return nil, fmt.Errorf("state %s has unsupported version %d", path, state.Version)
+	}
+	if state.Repositories == nil {
+		state.Repositories = make(map[string]*Repository)
+	}
+
+	return &state, nil
+}
+
+// Save writes the state atomically so an interrupted write never leaves a corrupt file
+func (s *State) Save(path string) error {
+	s.UpdatedAt = time.Now().UTC()
+
+	content, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".state-*")
+	if err != nil {
+		return fmt.Errorf("failed to create state: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+
+	return nil
+}
+
+// Matches reports whether the state was recorded by analyses of language with the same settings,
+// projects recorded with other settings can not be reused
+func (s *State) Matches(language, fingerprint string) bool {
+	return s.Language == language && s.Fingerprint == fingerprint
+}
+
+// Unchanged returns the projects recorded for the repository at url when it was analyzed at commit
+func (s *State) Unchanged(url, commit string) ([]*domain.Project, bool) {
+	recorded, ok := s.Repositories[url]
+	if !ok || commit == "" || recorded.Commit != commit {
+		return nil, false
+	}
+	return recorded.Projects, true
+}
+
+// Record sets the commit the repository at url was analyzed at, dropping the projects of earlier analyses
+func (s *State) Record(url, commit string) *Repository {
+	recorded := &Repository{Commit: commit}
+	s.Repositories[url] = recorded
+	return recorded
+}
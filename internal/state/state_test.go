@@ -0,0 +1,61 @@
+package state_test
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/state"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "state.json")
+	saved := state.New("go", "settings")
+	saved.Record("https://gitlab.com/group/api", "4b825dc6").Projects = []*domain.Project{{
+		ID:           "repo-1-root-go",
+		Repository:   domain.Repository{URL: "https://gitlab.com/group/api"},
+		Dependencies: []*domain.Dependency{{Name: "github.com/gin-gonic/gin", Version: "v1.9.1"}},
+	}}
+	require.NoError(t, saved.Save(path))
+
+	loaded, err := state.Load(path)
+	require.NoError(t, err)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+	assert.True(t, loaded.Matches("go", "settings"))
+	assert.False(t, loaded.Matches("go", "other settings"))
+	assert.False(t, loaded.Matches("python", "settings"))
+
+	projects, ok := loaded.Unchanged("https://gitlab.com/group/api", "4b825dc6")
+	require.True(t, ok)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "v1.9.1", projects[0].Dependencies[0].Version)
+
+	_, ok = loaded.Unchanged("https://gitlab.com/group/api", "e69de29b")
+	assert.False(t, ok, "a new commit is analyzed again")
+	_, ok = loaded.Unchanged("https://gitlab.com/group/api", "")
+	assert.False(t, ok, "an unresolved commit is analyzed again")
+	_, ok = loaded.Unchanged("https://gitlab.com/group/web", "4b825dc6")
+	assert.False(t, ok)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	unsupported := filepath.Join(dir, "unsupported.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte(`{"version": 1,`), 0o600))
+	require.NoError(t, os.WriteFile(unsupported, []byte(`{"version": 99, "language": "go"}`), 0o600))
+
+	_, err := state.Load(filepath.Join(dir, "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = state.Load(corrupt)
+	require.Error(t, err)
+	_, err = state.Load(unsupported)
+	require.ErrorContains(t, err, "unsupported version 99")
+}
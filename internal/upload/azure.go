@@ -0,0 +1,58 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// azureVersion is the Blob Storage REST API version requests are made with
+const azureVersion = "2021-08-06"
+
+// azureStore puts blobs into an Azure Blob Storage container, authorized by a shared access signature
+type azureStore struct {
+	client    *http.Client
+	container string
+	endpoint  string // Blob service of the storage account
+	sas       string // Shared access signature query string, without the leading "?"
+}
+
+// newAzureStore reads the storage account and shared access signature from the variables of the Azure CLI,
+// AZURE_STORAGE_BLOB_ENDPOINT addresses other blob services such as Azurite
+func newAzureStore(container string, client *http.Client, getenv func(string) string) (*azureStore, error) {
+	store := &azureStore{
+		client:    client,
+		container: container,
+		endpoint:  strings.TrimRight(getenv("AZURE_STORAGE_BLOB_ENDPOINT"), "/"),
+		sas:       strings.TrimPrefix(getenv("AZURE_STORAGE_SAS_TOKEN"), "?"),
+	}
+	if store.endpoint == "" {
+		account := getenv("AZURE_STORAGE_ACCOUNT")
+		if account == "" {
+			return nil, fmt.Errorf("uploading to Azure Blob Storage requires AZURE_STORAGE_ACCOUNT")
+		}
+		store.endpoint = "https://" + account + ".blob.core.windows.net"
+	}
+	if store.sas == "" {
+		return nil, fmt.Errorf("uploading to Azure Blob Storage requires AZURE_STORAGE_SAS_TOKEN")
+	}
+	return store, nil
+}
+
+func (s *azureStore) URL(key string) string {
+	return s.endpoint + "/" + s.container + "/" + escapePath(key)
+}
+
+func (s *azureStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.URL(key)+"?"+s.sas, bytes.NewReader(body))
+	if err != nil {
+		// The error would quote the shared access signature
+		return fmt.Errorf("failed to create upload request for %s", s.URL(key))
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	request.Header.Set("X-Ms-Version", azureVersion)
+	return send(s.client, request)
+}
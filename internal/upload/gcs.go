@@ -0,0 +1,53 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// gcsStore puts objects into a Google Cloud Storage bucket through its XML API
+type gcsStore struct {
+	client   *http.Client
+	bucket   string
+	endpoint string
+	token    string // OAuth 2.0 access token, as printed by "gcloud auth print-access-token"
+}
+
+// newGCSStore reads the access token from GOOGLE_OAUTH_ACCESS_TOKEN and an emulator address from
+// STORAGE_EMULATOR_HOST
+func newGCSStore(bucket string, client *http.Client, getenv func(string) string) (*gcsStore, error) {
+	store := &gcsStore{
+		client:   client,
+		bucket:   bucket,
+		endpoint: "https://storage.googleapis.com",
+		token:    getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+	}
+	if emulator := getenv("STORAGE_EMULATOR_HOST"); emulator != "" {
+		store.endpoint = strings.TrimRight(emulator, "/")
+		if !strings.Contains(store.endpoint, "://") {
+			store.endpoint = "http://" + store.endpoint
+		}
+	} else if store.token == "" {
+		return nil, fmt.Errorf("uploading to Google Cloud Storage requires GOOGLE_OAUTH_ACCESS_TOKEN")
+	}
+	return store, nil
+}
+
+func (s *gcsStore) URL(key string) string {
+	return s.endpoint + "/" + s.bucket + "/" + escapePath(key)
+}
+
+func (s *gcsStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.URL(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	if s.token != "" {
+		request.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return send(s.client, request)
+}
@@ -0,0 +1,148 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials sign S3 requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Temporary credentials only
+}
+
+// s3Store puts objects into an Amazon S3 bucket, or a bucket of an S3-compatible store such as MinIO
+type s3Store struct {
+	client      *http.Client
+	bucket      string
+	region      string
+	endpoint    string // S3-compatible store addressed with path-style URLs, empty for Amazon S3
+	credentials Credentials
+}
+
+// newS3Store reads the credentials, region and endpoint from the variables of the AWS CLI
+func newS3Store(bucket string, client *http.Client, getenv func(string) string) (*s3Store, error) {
+	store := &s3Store{
+		client: client,
+		bucket: bucket,
+		credentials: Credentials{
+			AccessKeyID:     getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+	if store.credentials.AccessKeyID == "" || store.credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("uploading to S3 requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if store.region == "" {
+			store.region = getenv(name)
+		}
+	}
+	if store.region == "" {
+		store.region = "us-east-1"
+	}
+	for _, name := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if store.endpoint == "" {
+			store.endpoint = strings.TrimRight(getenv(name), "/")
+		}
+	}
+	return store, nil
+}
+
+func (s *s3Store) URL(key string) string {
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s.bucket + "/" + escapePath(key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, escapePath(key))
+}
+
+func (s *s3Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.URL(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	payload := sha256.Sum256(body)
+	Sign(request, hex.EncodeToString(payload[:]), s.credentials, s.region, time.Now())
+	return send(s.client, request)
+}
+
+// Sign signs an S3 request in region with AWS Signature Version 4, the way S3-compatible stores verify it too.
+// Every header already set is signed, payloadHash is the hex SHA-256 of the body.
+func Sign(request *http.Request, payloadHash string, credentials Credentials, region string, at time.Time) {
+	at = at.UTC()
+	request.Header.Set("X-Amz-Date", at.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for name, value := range request.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(value, ","))
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQuery(request.URL.Query()),
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := at.Format("20060102") + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + at.Format("20060102T150405Z") + "\n" + scope + "\n" +
+		hex.EncodeToString(canonicalHash[:])
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{at.Format("20060102"), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, then value
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, escape(name, false)+"="+escape(value, false))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,141 @@
+// Package upload archives reports to object storage: Amazon S3 and S3-compatible stores, Google Cloud Storage and
+// Azure Blob Storage. Stores are reached over their HTTP APIs and authenticated from the environment variables their
+// command line tools read.
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Schemes of upload URLs
+const (
+	SchemeS3    = "s3"     // s3://bucket/prefix
+	SchemeGCS   = "gs"     // gs://bucket/prefix
+	SchemeAzure = "azblob" // azblob://container/prefix
+)
+
+// timestampLayout is the layout of the timestamp keys start with, sortable and free of characters keys escape
+const timestampLayout = "20060102T150405Z"
+
+// Target is an upload URL: a bucket, or Azure container, and the key prefix reports are uploaded under
+type Target struct {
+	Scheme string
+	Bucket string
+	Prefix string // Without leading or trailing slashes, empty for the bucket root
+}
+
+// Parse parses an upload URL such as "s3://bucket/reports"
+func Parse(raw string) (Target, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid upload URL %q: %w", raw, err)
+	}
+	switch parsed.Scheme {
+	case SchemeS3, SchemeGCS, SchemeAzure:
+	default:
+		return Target{}, fmt.Errorf("invalid upload URL %q: the scheme must be one of: s3, gs, azblob", raw)
+	}
+	if parsed.Host == "" {
+		return Target{}, fmt.Errorf("invalid upload URL %q: no bucket", raw)
+	}
+	return Target{Scheme: parsed.Scheme, Bucket: parsed.Host, Prefix: strings.Trim(parsed.Path, "/")}, nil
+}
+
+// String returns the upload URL of the target
+func (t Target) String() string {
+	return t.Scheme + "://" + path.Join(t.Bucket, t.Prefix)
+}
+
+// Key returns the key of a report file uploaded at a time: the files of one run share a timestamp below the prefix
+func (t Target) Key(at time.Time, name string) string {
+	return path.Join(t.Prefix, at.UTC().Format(timestampLayout), name)
+}
+
+// Store puts objects into a bucket
+type Store interface {
+	// Put creates or replaces the object at key
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	// URL returns the address of the object at key
+	URL(key string) string
+}
+
+// NewStore returns the store of the target, with credentials read by getenv
+func NewStore(target Target, client *http.Client, getenv func(string) string) (Store, error) {
+	switch target.Scheme {
+	case SchemeS3:
+		return newS3Store(target.Bucket, client, getenv)
+	case SchemeGCS:
+		return newGCSStore(target.Bucket, client, getenv)
+	case SchemeAzure:
+		return newAzureStore(target.Bucket, client, getenv)
+	default:
+		return nil, fmt.Errorf("unsupported upload scheme %q", target.Scheme)
+	}
+}
+
+// ContentType returns the media type of a report file by its extension
+func ContentType(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".sarif":
+		return "application/sarif+json"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	case ".dot", ".mmd":
+		return "text/plain; charset=utf-8"
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(file)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// send sends a request and reports answers other than 2xx as errors
+func send(client *http.Client, request *http.Request) error {
+	// Query strings carry Azure SAS tokens, they stay out of errors
+	location := request.URL.Scheme + "://" + request.URL.Host + request.URL.EscapedPath()
+	response, err := client.Do(request)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to upload %s: %w", location, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to upload %s: the store answered %s", location, response.Status)
+	}
+	return nil
+}
+
+// escapePath escapes every character of an object key but unreserved ones and slashes, as SigV4 and Azure expect
+func escapePath(key string) string {
+	return escape(key, true)
+}
+
+// escape percent-encodes the characters of value outside the RFC 3986 unreserved set, slashes too unless keepSlash
+func escape(value string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		unreserved := (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~'
+		if unreserved || (keepSlash && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
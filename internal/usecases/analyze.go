@@ -2,13 +2,24 @@ package usecases
 
 import (
 	"context"
+	"di-matrix-cli/internal/checkpoint"
 	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/health"
+	"di-matrix-cli/internal/policy"
+	"di-matrix-cli/internal/state"
+	"di-matrix-cli/internal/version"
+	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 const (
+	// Default number of repositories scanned concurrently
+	defaultRepositoryWorkers = 10
 	// Default number of workers for concurrent project processing
 	defaultProjectWorkers = 5
 	// Default number of workers for concurrent dependency file processing per project
@@ -17,28 +28,72 @@ const (
 
 // AnalyzeResponse represents the result of the analysis
 type AnalyzeResponse struct {
-	TotalProjects     int `json:"total_projects"`
-	TotalDependencies int `json:"total_dependencies"`
-	InternalCount     int `json:"internal_count"`
-	ExternalCount     int `json:"external_count"`
+	RepositoryCount         int                      `json:"repository_count"`
+	FailedRepositories      int                      `json:"failed_repositories"` // Project detection failed
+	FailedProjects          int                      `json:"failed_projects"`     // Dependency processing failed
+	TotalProjects           int                      `json:"total_projects"`
+	TotalDependencies       int                      `json:"total_dependencies"`
+	InternalCount           int                      `json:"internal_count"`
+	ExternalCount           int                      `json:"external_count"`
+	OutdatedCount           int                      `json:"outdated_count"` // Behind the highest known version
+	FloatingCount           int                      `json:"floating_count"`
+	ConflictCount           int                      `json:"conflict_count"`
+	ConstraintMismatchCount int                      `json:"constraint_mismatch_count"`
+	ProjectsWithoutLockfile int                      `json:"projects_without_lockfile"`
+	WarningCount            int                      `json:"warning_count"`
+	VulnerableCount         int                      `json:"vulnerable_count"` // Dependencies with known vulnerabilities
+	StaleCount              int                      `json:"stale_count"`      // Offline mode cache misses
+	FallbackCount           int                      `json:"fallback_count"`   // Unparsable lockfiles replaced by their manifest
+	Coverage                float64                  `json:"coverage"`         // Percent of dependency files in scope analyzed
+	Ecosystems              map[string]int           `json:"ecosystems"`       // Dependencies per ecosystem
+	Violations              []domain.PolicyViolation `json:"violations"`
+	Errors                  []domain.AnalysisIssue   `json:"errors"`                 // Repositories, projects and files that failed
+	Warnings                []domain.AnalysisIssue   `json:"warnings"`               // Files only partly understood
+	ResumedRepositories     int                      `json:"resumed_repositories"`   // Taken from the checkpoint
+	UnchangedRepositories   int                      `json:"unchanged_repositories"` // Taken from the incremental state
+	Interrupted             bool                     `json:"interrupted"`            // Cancelled before every repository was analyzed
+	PendingRepositories     int                      `json:"pending_repositories"`   // Left for a resumed run
+	Checkpoint              *checkpoint.Checkpoint   `json:"-"`                      // Set when interrupted
+	State                   *state.State             `json:"-"`                      // Set by incremental analyses
+	Projects                []*domain.Project        `json:"-"`                      // Reported projects, for integrations
 }
 
 // AnalyzeUseCase orchestrates the dependency analysis workflow
 type AnalyzeUseCase struct {
-	gitlabClient domain.GitlabClient
-	scanner      domain.RepositoryScanner
-	parser       domain.DependencyParser
-	classifier   domain.DependencyClassifier
-	generator    domain.ReportGenerator
-	logger       *zap.Logger
-	ctx          context.Context
-	classifierMu sync.Mutex // Mutex to protect classifier access (testify mocks are not thread-safe)
+	provider           domain.SourceProvider
+	scanner            domain.RepositoryScanner
+	parser             domain.DependencyParser
+	classifier         domain.DependencyClassifier
+	generator          domain.ReportGenerator
+	policyChecks       []domain.PolicyCheck
+	healthScorer       *health.Scorer
+	prereleases        version.PrereleasePolicy
+	versions           domain.ManagedVersionResolver
+	latest             domain.LatestVersionResolver
+	endOfLife          domain.EndOfLifeChecker
+	vulns              domain.VulnerabilityScanner
+	formats            []string         // Report formats written from the analysis
+	includeDev         bool             // Keep dev and test dependencies, only runtime and optional ones are analyzed otherwise
+	excluded           *exclude.Matcher // Dependencies left out of the analysis by name
+	submodules         bool             // Analyze submodules hosted on the same GitLab as separate repositories
+	checkpoint         *checkpoint.Checkpoint
+	checkpointPath     string        // Where the checkpoint is saved while the analysis runs, never when empty
+	checkpointInterval time.Duration // Minimum time between two checkpoint saves
+	previous           *state.State  // Incremental analysis: commits and projects recorded by the previous run
+	progress           domain.ProgressReporter
+	repositoryWorkers  int           // Repositories scanned concurrently
+	projectWorkers     int           // Projects processed concurrently
+	fileWorkers        int           // Dependency files parsed concurrently per project
+	repositoryTimeout  time.Duration // Scan time of a single repository, 0 means no limit
+	logger             *zap.Logger
+	ctx                context.Context
+	classifierMu       sync.Mutex // Mutex to protect classifier access (testify mocks are not thread-safe)
 }
 
 // NewAnalyzeUseCase creates a new analyze use case with dependency injection
 func NewAnalyzeUseCase(
 	ctx context.Context,
-	gitlabClient domain.GitlabClient,
+	provider domain.SourceProvider,
 	scanner domain.RepositoryScanner,
 	parser domain.DependencyParser,
 	classifier domain.DependencyClassifier,
@@ -46,94 +101,192 @@ func NewAnalyzeUseCase(
 	logger *zap.Logger,
 ) *AnalyzeUseCase {
 	return &AnalyzeUseCase{
-		gitlabClient: gitlabClient,
-		scanner:      scanner,
-		parser:       parser,
-		classifier:   classifier,
-		generator:    generator,
-		logger:       logger,
-		ctx:          ctx,
+		provider:          provider,
+		scanner:           scanner,
+		parser:            parser,
+		classifier:        classifier,
+		generator:         generator,
+		healthScorer:      health.NewScorer(health.DefaultWeights()),
+		formats:           []string{domain.FormatHTML},
+		progress:          noProgress{},
+		logger:            logger,
+		repositoryWorkers: defaultRepositoryWorkers,
+		projectWorkers:    defaultProjectWorkers,
+		fileWorkers:       defaultDependencyFileWorkers,
+		ctx:               ctx,
 	}
 }
 
-// Execute runs the main dependency analysis workflow
-func (uc *AnalyzeUseCase) Execute(repositoryURLs []string, targetLanguage string) (*AnalyzeResponse, error) {
-	uc.logger.Info("Starting dependency analysis workflow", zap.String("target_language", targetLanguage))
+// WithProgress reports the progress of every analysis stage to reporter
+func (uc *AnalyzeUseCase) WithProgress(reporter domain.ProgressReporter) *AnalyzeUseCase {
+	uc.progress = reporter
+	return uc
+}
 
-	// Step 1: Get repositories from URLs (with concurrency)
-	var repositories []*domain.Repository
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+// WithRepositoryWorkers sets the number of repositories scanned concurrently
+func (uc *AnalyzeUseCase) WithRepositoryWorkers(workers int) *AnalyzeUseCase {
+	if workers > 0 {
+		uc.repositoryWorkers = workers
+	}
+	return uc
+}
 
-	// Channel to collect errors
-	errChan := make(chan error, len(repositoryURLs))
+// WithRepositoryTimeout bounds the scan of every repository, repositories running out of time are recorded
+// as failed and the analysis continues with the others
+func (uc *AnalyzeUseCase) WithRepositoryTimeout(timeout time.Duration) *AnalyzeUseCase {
+	uc.repositoryTimeout = timeout
+	return uc
+}
 
-	for _, repoURL := range repositoryURLs {
-		wg.Add(1)
-		go func(repoURL string) {
-			defer wg.Done()
+// WithProjectWorkers sets the number of projects whose dependency files are parsed concurrently
+func (uc *AnalyzeUseCase) WithProjectWorkers(workers int) *AnalyzeUseCase {
+	if workers > 0 {
+		uc.projectWorkers = workers
+	}
+	return uc
+}
 
-			repos, err := uc.gitlabClient.GetRepositoriesList(uc.ctx, repoURL)
-			if err != nil {
-				errChan <- err
-				return
-			}
+// WithDependencyFileWorkers sets the number of dependency files parsed concurrently within a project
+func (uc *AnalyzeUseCase) WithDependencyFileWorkers(workers int) *AnalyzeUseCase {
+	if workers > 0 {
+		uc.fileWorkers = workers
+	}
+	return uc
+}
+
+// WithPolicyChecks registers policy hooks evaluated after dependencies are parsed
+func (uc *AnalyzeUseCase) WithPolicyChecks(checks ...domain.PolicyCheck) *AnalyzeUseCase {
+	uc.policyChecks = append(uc.policyChecks, checks...)
+	return uc
+}
+
+// WithHealthWeights overrides the weights used to compute per-project health scores
+func (uc *AnalyzeUseCase) WithHealthWeights(weights health.Weights) *AnalyzeUseCase {
+	uc.healthScorer = health.NewScorer(weights).WithPrereleasePolicy(uc.prereleases)
+	return uc
+}
+
+// WithPrereleasePolicy controls whether pre-release versions may count as the highest version when scoring drift
+func (uc *AnalyzeUseCase) WithPrereleasePolicy(policy version.PrereleasePolicy) *AnalyzeUseCase {
+	uc.prereleases = policy
+	uc.healthScorer.WithPrereleasePolicy(policy)
+	return uc
+}
+
+// WithManagedVersionResolver fills versions managed outside the declaring manifest after parsing
+func (uc *AnalyzeUseCase) WithManagedVersionResolver(resolver domain.ManagedVersionResolver) *AnalyzeUseCase {
+	uc.versions = resolver
+	return uc
+}
 
-			mu.Lock()
-			repositories = append(repositories, repos...)
-			mu.Unlock()
-		}(repoURL)
+// WithLatestVersionResolver looks up the latest releases of dependencies in package registries after parsing
+func (uc *AnalyzeUseCase) WithLatestVersionResolver(resolver domain.LatestVersionResolver) *AnalyzeUseCase {
+	uc.latest = resolver
+	return uc
+}
+
+// WithEndOfLifeChecker marks dependencies on release cycles that no longer receive support after parsing
+func (uc *AnalyzeUseCase) WithEndOfLifeChecker(checker domain.EndOfLifeChecker) *AnalyzeUseCase {
+	uc.endOfLife = checker
+	return uc
+}
+
+// WithVulnerabilityScanner looks up known vulnerabilities of the resolved dependency versions
+func (uc *AnalyzeUseCase) WithVulnerabilityScanner(scanner domain.VulnerabilityScanner) *AnalyzeUseCase {
+	uc.vulns = scanner
+	return uc
+}
+
+// WithOutputFormats sets the reports generated from the analysis (html, csv, json, xlsx, dot, mermaid, sarif),
+// HTML by default
+func (uc *AnalyzeUseCase) WithOutputFormats(formats ...string) *AnalyzeUseCase {
+	if len(formats) > 0 {
+		uc.formats = formats
 	}
+	return uc
+}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
+// WithoutReports analyzes without generating any report, the caller presents the analyzed projects itself
+func (uc *AnalyzeUseCase) WithoutReports() *AnalyzeUseCase {
+	uc.formats = nil
+	return uc
+}
 
-	// Check for errors
-	for err := range errChan {
-		if err != nil {
-			return nil, err
-		}
+// WithDevDependencies keeps dev and test dependencies in the analysis, they are dropped after parsing by default
+func (uc *AnalyzeUseCase) WithDevDependencies(include bool) *AnalyzeUseCase {
+	uc.includeDev = include
+	return uc
+}
+
+// WithExcludedDependencies drops dependencies whose name matches the patterns after parsing,
+// they are left out of the reports and counts
+func (uc *AnalyzeUseCase) WithExcludedDependencies(excluded *exclude.Matcher) *AnalyzeUseCase {
+	uc.excluded = excluded
+	return uc
+}
+
+// WithCheckpoint resumes an interrupted analysis: repositories the checkpoint completed are not analyzed again,
+// their projects are taken from the checkpoint
+func (uc *AnalyzeUseCase) WithCheckpoint(resume *checkpoint.Checkpoint) *AnalyzeUseCase {
+	uc.checkpoint = resume
+	return uc
+}
+
+// WithPeriodicCheckpoint saves a checkpoint to path at most every interval while the analysis runs and once every
+// project is processed, so a killed or crashed run can be resumed with WithCheckpoint
+func (uc *AnalyzeUseCase) WithPeriodicCheckpoint(path string, interval time.Duration) *AnalyzeUseCase {
+	uc.checkpointPath = path
+	uc.checkpointInterval = interval
+	return uc
+}
+
+// Execute runs the main dependency analysis workflow for projects of targetLanguage ("" means every language)
+func (uc *AnalyzeUseCase) Execute(repositoryURLs []string, targetLanguage string) (*AnalyzeResponse, error) {
+	uc.logger.Info("Starting dependency analysis workflow", zap.String("target_language", targetLanguage))
+
+	defer uc.progress.Finish()
+
+	// Step 1: Get repositories from URLs (with concurrency)
+	uc.progress.StartStage(domain.StageDiscover, len(repositoryURLs))
+	repositories, err := fetchRepositories(uc.ctx, uc.provider, repositoryURLs, uc.progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.submodules {
+		repositories = resolveSubmodules(uc.ctx, uc.provider, uc.logger, repositories)
 	}
 
 	for _, repo := range repositories {
 		uc.logger.Info("Found repository", zap.String("name", repo.Name), zap.String("url", repo.URL))
 	}
 
-	// Step 2: Transform repositories to projects (with concurrency)
-	var allProjects []*domain.Project
-	var projectsMu sync.Mutex
-	var projectsWg sync.WaitGroup
-
-	for _, repo := range repositories {
-		projectsWg.Add(1)
-		go func(repository *domain.Repository) {
-			defer projectsWg.Done()
-
-			projects, err := uc.scanner.DetectProjects(uc.ctx, repository)
-			if err != nil {
-				uc.logger.Error("Failed to detect projects in repository",
-					zap.String("repo_name", repository.Name),
-					zap.Error(err))
-				return
-			}
+	// Skip repositories completed by the interrupted run being resumed
+	allRepositories := repositories
+	repositories, resumed, resumedScans := uc.skipCompleted(repositories, targetLanguage)
+	resumedCount := len(allRepositories) - len(repositories)
+	tracker := uc.newCheckpointTracker(targetLanguage, resumed)
 
-			projectsMu.Lock()
-			allProjects = append(allProjects, projects...)
-			projectsMu.Unlock()
-		}(repo)
-	}
+	// Skip repositories whose analyzed commit did not change since the previous incremental run
+	commits := uc.resolveCommits(repositories)
+	repositories, unchanged := uc.skipUnchanged(repositories, commits)
+	unchangedCount := len(allRepositories) - resumedCount - len(repositories)
+	resumed = append(resumed, unchanged...)
 
-	// Wait for all project detection goroutines to complete
-	projectsWg.Wait()
+	// Step 2: Transform repositories to projects (with concurrency)
+	uc.progress.StartStage(domain.StageScan, len(repositories))
+	scanner := &checkpointScanner{RepositoryScanner: uc.scanner, detected: resumedScans, tracker: tracker}
+	detected := detectProjects(uc.ctx, scanner, uc.logger, repositories, uc.repositoryWorkers, uc.repositoryTimeout,
+		uc.progress)
+	allProjects := detected.projects
 
 	uc.logger.Info("Detected projects across all repositories",
 		zap.Int("total_projects", len(allProjects)))
 
-	// Filter projects by target language
+	// Filter projects by target language, every known language when none is targeted
 	var filteredProjects []*domain.Project
 	for _, project := range allProjects {
-		if project.Language == targetLanguage {
+		if matchesLanguage(project, targetLanguage) {
 			filteredProjects = append(filteredProjects, project)
 		}
 	}
@@ -153,53 +306,329 @@ func (uc *AnalyzeUseCase) Execute(repositoryURLs []string, targetLanguage string
 	}
 
 	// Step 3: Parse dependency files and classify dependencies (with concurrency)
-	totalDependencies, internalCount, externalCount, err := uc.processProjectsConcurrently(filteredProjects)
-	if err != nil {
-		uc.logger.Error("Failed to process projects concurrently", zap.Error(err))
-		return nil, err
+	uc.progress.StartStage(domain.StageParse, len(filteredProjects))
+	processed := uc.processProjectsConcurrently(filteredProjects, tracker)
+	tracker.flush()
+
+	// Projects the workers never reached are left to a resumed run, resumed and unchanged projects join the analyzed ones
+	var completedProjects []*domain.Project
+	for _, project := range filteredProjects {
+		if !processed.skipped[project] {
+			completedProjects = append(completedProjects, project)
+		}
+	}
+	filteredProjects = append(resumed, completedProjects...)
+	totalDependencies, internalCount, externalCount := processed.dependencies, processed.internal, processed.external
+	for _, project := range resumed {
+		totalDependencies += len(project.Dependencies)
+		for _, dep := range project.Dependencies {
+			if dep.IsInternal {
+				internalCount++
+			} else {
+				externalCount++
+			}
+		}
 	}
 
-	// Step 4: Generate HTML report with filtered results
-	uc.logger.Info("Generating HTML report", zap.Int("projects_count", len(filteredProjects)))
-	if err := uc.generator.GenerateHTML(uc.ctx, filteredProjects); err != nil {
-		uc.logger.Error("Failed to generate HTML report", zap.Error(err))
-		return nil, err
+	// A cancelled analysis (Ctrl-C or timeout) still reports what it finished and records where to resume
+	var interrupted *checkpoint.Checkpoint
+	if uc.ctx.Err() != nil {
+		interrupted = tracker.checkpoint()
+		pending := len(allRepositories) - len(interrupted.Repositories)
+		uc.logger.Warn("Analysis interrupted, reporting completed repositories only",
+			zap.Int("completed_repositories", len(interrupted.Repositories)),
+			zap.Int("pending_repositories", pending))
+		if marker, ok := uc.generator.(domain.IncompleteReportMarker); ok {
+			marker.MarkIncomplete(fmt.Sprintf("the analysis was interrupted, %d of %d repositories were not analyzed",
+				pending, len(allRepositories)))
+		}
+	}
+
+	// Requirements between modules of a go.work workspace are served by the local modules
+	if resolved := resolveGoWorkspaceModules(filteredProjects); resolved > 0 {
+		uc.logger.Debug("Resolved requirements on Go workspace modules", zap.Int("requirements", resolved))
+	}
+
+	uc.progress.StartStage(domain.StageEnrich, uc.enrichmentSteps())
+
+	// Fill versions managed by parent manifests and imported BOMs
+	if uc.versions != nil {
+		uc.versions.ResolveManagedVersions(uc.ctx, filteredProjects)
+		uc.progress.Advance(1)
+	}
+
+	// Compare against the latest releases rather than only the versions in use
+	if uc.latest != nil {
+		uc.latest.ResolveLatestVersions(uc.ctx, filteredProjects)
+		uc.progress.Advance(1)
+	}
+	if uc.endOfLife != nil {
+		uc.endOfLife.CheckEndOfLife(uc.ctx, filteredProjects)
+		uc.progress.Advance(1)
+	}
+
+	// Record versioning schemes once all versions are final
+	annotateVersioningSchemes(filteredProjects)
+
+	// Annotate pinning compliance before policies and the report consume it
+	floatingCount, withoutLockfile := uc.annotatePinning(filteredProjects)
+
+	// Flag packages resolved to several versions inside one project
+	// and resolved versions that fall outside their declared constraints
+	conflictCount, mismatchCount := 0, 0
+	for _, project := range filteredProjects {
+		conflictCount += annotateVersionConflicts(project)
+		mismatchCount += annotateConstraintMismatches(project)
+	}
+
+	// Look up advisories of exact versions, floating dependencies are known by now
+	vulnerableCount := 0
+	if uc.vulns != nil {
+		vulnerableCount = uc.vulns.ScanVulnerabilities(uc.ctx, filteredProjects)
+		uc.progress.Advance(1)
+	}
+
+	// Every repository is parsed when the analysis is cancelled during the lookups, the report is written
+	// without the lookups that did not finish
+	if interrupted == nil && uc.ctx.Err() != nil {
+		interrupted = tracker.checkpoint()
+		uc.logger.Warn("Analysis interrupted during registry and advisory lookups, reporting partial lookups")
+		if marker, ok := uc.generator.(domain.IncompleteReportMarker); ok {
+			marker.MarkIncomplete("the analysis was interrupted during registry and advisory lookups, " +
+				"latest versions, end-of-life dates and vulnerabilities may be missing")
+		}
+	}
+
+	// Compute per-project health scores once all annotations are in place
+	uc.healthScorer.ScoreProjects(filteredProjects)
+
+	// Evaluate policy hooks
+	violations := uc.evaluatePolicies(filteredProjects)
+	if recorder, ok := uc.generator.(domain.ViolationRecorder); ok {
+		recorder.RecordViolations(violations)
+	}
+
+	// Account for the dependency files that did not make it into the report
+	coverage := uc.repositoryCoverage(allRepositories, allProjects, filteredProjects, targetLanguage)
+	if recorder, ok := uc.generator.(domain.CoverageRecorder); ok {
+		recorder.RecordCoverage(coverage)
+	}
+	runErrors, runWarnings := runIssues(detected, processed, filteredProjects, coverage)
+	if recorder, ok := uc.generator.(domain.IssueRecorder); ok {
+		recorder.RecordIssues(runErrors, runWarnings)
 	}
-	uc.logger.Info("HTML report generated successfully")
 
-	// Step 5: Save report to output file (handled by generator)
+	// Step 4: Generate the reports of every configured format with filtered results
+	uc.progress.StartStage(domain.StageReport, len(uc.formats))
+	for _, format := range uc.formats {
+		uc.logger.Info("Generating report", zap.String("format", format), zap.Int("projects_count", len(filteredProjects)))
+		if err := uc.generateReport(format, filteredProjects); err != nil {
+			uc.logger.Error("Failed to generate report", zap.String("format", format), zap.Error(err))
+			return nil, err
+		}
+		uc.progress.Advance(1)
+	}
+	uc.logger.Info("Reports generated successfully", zap.Strings("formats", uc.formats))
 
 	// Calculate response metrics
 	response := &AnalyzeResponse{
-		TotalProjects:     len(filteredProjects),
-		TotalDependencies: totalDependencies,
-		InternalCount:     internalCount,
-		ExternalCount:     externalCount,
+		RepositoryCount:         len(allRepositories),
+		FailedRepositories:      detected.failed,
+		FailedProjects:          len(processed.failed),
+		TotalProjects:           len(filteredProjects),
+		TotalDependencies:       totalDependencies,
+		InternalCount:           internalCount,
+		ExternalCount:           externalCount,
+		OutdatedCount:           countOutdated(filteredProjects, uc.prereleases),
+		FloatingCount:           floatingCount,
+		ConflictCount:           conflictCount,
+		ConstraintMismatchCount: mismatchCount,
+		ProjectsWithoutLockfile: withoutLockfile,
+		WarningCount:            countWarnings(filteredProjects),
+		VulnerableCount:         vulnerableCount,
+		StaleCount:              countStale(filteredProjects),
+		FallbackCount:           countFallbacks(filteredProjects),
+		Coverage:                overallCoverage(coverage),
+		Ecosystems:              countEcosystems(filteredProjects),
+		Violations:              violations,
+		Errors:                  runErrors,
+		Warnings:                runWarnings,
+		ResumedRepositories:     resumedCount,
+		UnchangedRepositories:   unchangedCount,
+		State:                   uc.nextState(commits, detected, processed, filteredProjects),
+		Projects:                filteredProjects,
+	}
+	if interrupted != nil {
+		response.Interrupted = true
+		response.PendingRepositories = len(allRepositories) - len(interrupted.Repositories)
+		response.Checkpoint = interrupted
 	}
 
 	uc.logger.Info("Dependency analysis completed",
+		zap.Int("repositories", response.RepositoryCount),
+		zap.Int("failed_repositories", response.FailedRepositories),
+		zap.Int("failed_projects", response.FailedProjects),
 		zap.Int("total_projects", response.TotalProjects),
 		zap.Int("total_dependencies", response.TotalDependencies),
 		zap.Int("internal_count", response.InternalCount),
-		zap.Int("external_count", response.ExternalCount))
+		zap.Int("external_count", response.ExternalCount),
+		zap.Int("outdated_count", response.OutdatedCount),
+		zap.Int("floating_count", response.FloatingCount),
+		zap.Int("conflict_count", response.ConflictCount),
+		zap.Int("constraint_mismatch_count", response.ConstraintMismatchCount),
+		zap.Int("projects_without_lockfile", response.ProjectsWithoutLockfile),
+		zap.Int("file_warnings", response.WarningCount),
+		zap.Int("vulnerable_dependencies", response.VulnerableCount),
+		zap.Int("stale_dependencies", response.StaleCount),
+		zap.Float64("coverage", response.Coverage),
+		zap.Int("policy_violations", len(response.Violations)),
+		zap.Int("errors", len(response.Errors)),
+		zap.Bool("interrupted", response.Interrupted))
 
 	return response, nil
 }
 
-// processProjectsConcurrently processes all projects concurrently using worker pools
-func (uc *AnalyzeUseCase) processProjectsConcurrently(projects []*domain.Project) (int, int, int, error) {
+// enrichmentSteps counts the configured lookups run after parsing
+func (uc *AnalyzeUseCase) enrichmentSteps() int {
+	steps := 0
+	for _, configured := range []bool{uc.versions != nil, uc.latest != nil, uc.endOfLife != nil, uc.vulns != nil} {
+		if configured {
+			steps++
+		}
+	}
+	return steps
+}
+
+// generateReport writes the report of a single format
+func (uc *AnalyzeUseCase) generateReport(format string, projects []*domain.Project) error {
+	switch format {
+	case domain.FormatHTML:
+		return uc.generator.GenerateHTML(uc.ctx, projects)
+	case domain.FormatCSV:
+		return uc.generator.GenerateCSV(uc.ctx, projects)
+	case domain.FormatJSON:
+		return uc.generator.GenerateJSON(uc.ctx, projects)
+	case domain.FormatXLSX:
+		return uc.generator.GenerateXLSX(uc.ctx, projects)
+	case domain.FormatDOT:
+		return uc.generator.GenerateDOT(uc.ctx, projects)
+	case domain.FormatMermaid:
+		return uc.generator.GenerateMermaid(uc.ctx, projects)
+	case domain.FormatSARIF:
+		return uc.generator.GenerateSARIF(uc.ctx, projects)
+	case domain.FormatMarkdown:
+		return uc.generator.GenerateMarkdown(uc.ctx, projects)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// skipCompleted drops repositories the resumed checkpoint completed and returns their checkpointed projects,
+// and the detected projects of the repositories it only scanned. Checkpoints of another language are ignored.
+func (uc *AnalyzeUseCase) skipCompleted(
+	repositories []*domain.Repository,
+	targetLanguage string,
+) ([]*domain.Repository, []*domain.Project, map[string][]*domain.Project) {
+	if uc.checkpoint == nil {
+		return repositories, nil, nil
+	}
+	if uc.checkpoint.Language != targetLanguage {
+		uc.logger.Warn("Ignoring checkpoint of another language",
+			zap.String("checkpoint_language", uc.checkpoint.Language),
+			zap.String("target_language", targetLanguage))
+		return repositories, nil, nil
+	}
+
+	var pending []*domain.Repository
+	skipped := make(map[string]bool)
+	for _, repo := range repositories {
+		if uc.checkpoint.Completed(repo.URL) {
+			skipped[repo.URL] = true
+			continue
+		}
+		pending = append(pending, repo)
+	}
+
+	// Repositories no longer configured are dropped together with their projects
+	var resumed []*domain.Project
+	for _, project := range uc.checkpoint.Projects {
+		if skipped[project.Repository.URL] {
+			resumed = append(resumed, project)
+		}
+	}
+
+	detected := uc.checkpoint.Detected()
+
+	uc.logger.Info("Resuming interrupted analysis",
+		zap.Int("completed_repositories", len(skipped)),
+		zap.Int("scanned_repositories", len(detected)),
+		zap.Int("pending_repositories", len(pending)),
+		zap.Int("resumed_projects", len(resumed)))
+
+	return pending, resumed, detected
+}
+
+// annotatePinning marks floating dependencies and lockfile presence on every project
+func (uc *AnalyzeUseCase) annotatePinning(projects []*domain.Project) (int, int) {
+	var floatingCount int
+	var withoutLockfile int
+
+	for _, project := range projects {
+		policy.AnnotatePinning(project)
+		if !project.HasLockfile {
+			withoutLockfile++
+		}
+		for _, dep := range project.Dependencies {
+			if dep.IsFloating {
+				floatingCount++
+			}
+		}
+	}
+
+	return floatingCount, withoutLockfile
+}
+
+// evaluatePolicies runs all registered policy hooks and collects their violations
+func (uc *AnalyzeUseCase) evaluatePolicies(projects []*domain.Project) []domain.PolicyViolation {
+	var violations []domain.PolicyViolation
+	for _, check := range uc.policyChecks {
+		found := check.Evaluate(uc.ctx, projects)
+		if len(found) > 0 {
+			uc.logger.Warn("Policy violations found",
+				zap.String("policy", check.Name()),
+				zap.Int("violations", len(found)))
+		}
+		violations = append(violations, found...)
+	}
+	return violations
+}
+
+// processing is the outcome of dependency processing across projects
+type processing struct {
+	dependencies int
+	internal     int
+	external     int
+	failed       map[*domain.Project]error // Projects that failed to process and why
+	skipped      map[*domain.Project]bool  // Projects not processed because the analysis was cancelled
+}
+
+// processProjectsConcurrently processes all projects concurrently using worker pools.
+// Once the context is cancelled, workers finish their current project and skip the rest.
+func (uc *AnalyzeUseCase) processProjectsConcurrently(projects []*domain.Project, tracker *checkpointTracker) *processing {
 	uc.logger.Info("Starting concurrent project processing",
 		zap.Int("total_projects", len(projects)),
-		zap.Int("project_workers", defaultProjectWorkers))
+		zap.Int("project_workers", uc.projectWorkers))
 
 	// Shared counters with mutex protection
 	var totalDependencies int
 	var internalCount int
 	var externalCount int
+	skipped := make(map[*domain.Project]bool)
 	var mu sync.Mutex
 
 	// Error collection
 	var errors []error
+	failed := make(map[*domain.Project]error)
 	var errorMu sync.Mutex
 
 	// Create project processing channel
@@ -207,22 +636,32 @@ func (uc *AnalyzeUseCase) processProjectsConcurrently(projects []*domain.Project
 
 	// Start worker goroutines
 	var wg sync.WaitGroup
-	for i := 0; i < defaultProjectWorkers; i++ {
+	for i := 0; i < uc.projectWorkers; i++ {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
 			uc.logger.Debug("Started project worker", zap.Int("worker_id", workerID))
 
 			for project := range projectChan {
+				if uc.ctx.Err() != nil {
+					mu.Lock()
+					skipped[project] = true
+					mu.Unlock()
+					continue
+				}
+
 				uc.logger.Debug("Processing project in worker",
 					zap.Int("worker_id", workerID),
 					zap.String("project_id", project.ID),
 					zap.String("project_name", project.Name))
 
 				projectDeps, projectInternal, projectExternal, err := uc.processProject(project)
+				tracker.process(project)
+				uc.progress.Advance(1)
 				if err != nil {
 					errorMu.Lock()
 					errors = append(errors, err)
+					failed[project] = err
 					errorMu.Unlock()
 					uc.logger.Error("Failed to process project",
 						zap.String("project_id", project.ID),
@@ -271,9 +710,16 @@ func (uc *AnalyzeUseCase) processProjectsConcurrently(projects []*domain.Project
 		zap.Int("total_dependencies", totalDependencies),
 		zap.Int("internal_count", internalCount),
 		zap.Int("external_count", externalCount),
-		zap.Int("errors", len(errors)))
-
-	return totalDependencies, internalCount, externalCount, nil
+		zap.Int("errors", len(errors)),
+		zap.Int("skipped", len(skipped)))
+
+	return &processing{
+		dependencies: totalDependencies,
+		internal:     internalCount,
+		external:     externalCount,
+		failed:       failed,
+		skipped:      skipped,
+	}
 }
 
 // processProject processes a single project's dependency files concurrently
@@ -283,14 +729,12 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 		zap.String("project_name", project.Name),
 		zap.Int("dependency_files", len(project.DependencyFiles)))
 
-	// Shared project-level data
-	var projectDependencies []*domain.Dependency
+	// Shared project-level data, keyed by dependency file path
+	fileDependencies := make(map[string][]*domain.Dependency)
 	var projectMu sync.Mutex
-	var projectInternal int
-	var projectExternal int
 
-	// Error collection for this project
-	var projectErrors []error
+	// Error collection for this project, keyed by dependency file path
+	projectErrors := make(map[string]error)
 	var projectErrorMu sync.Mutex
 
 	// Create dependency file processing channel
@@ -298,7 +742,7 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 
 	// Start worker goroutines for dependency files
 	var fileWg sync.WaitGroup
-	workers := defaultDependencyFileWorkers
+	workers := uc.fileWorkers
 	if len(project.DependencyFiles) < workers {
 		workers = len(project.DependencyFiles)
 	}
@@ -317,7 +761,7 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 				dependencies, err := uc.parser.ParseFile(uc.ctx, dependencyFile)
 				if err != nil {
 					projectErrorMu.Lock()
-					projectErrors = append(projectErrors, err)
+					projectErrors[dependencyFile.Path] = err
 					projectErrorMu.Unlock()
 					uc.logger.Error("Failed to parse dependency file",
 						zap.String("file_path", dependencyFile.Path),
@@ -326,16 +770,16 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 					continue
 				}
 
+				dependencies = uc.scopeDependencies(dependencies)
+
 				// Classify dependencies with mutex protection (testify mocks are not thread-safe)
 				uc.classifierMu.Lock()
-				classifiedDeps, internalCount, externalCount := uc.classifyDependenciesConcurrently(dependencies)
+				classifiedDeps, _, _ := uc.classifyDependenciesConcurrently(dependencies)
 				uc.classifierMu.Unlock()
 
 				// Update project-level data
 				projectMu.Lock()
-				projectDependencies = append(projectDependencies, classifiedDeps...)
-				projectInternal += internalCount
-				projectExternal += externalCount
+				fileDependencies[dependencyFile.Path] = classifiedDeps
 				projectMu.Unlock()
 
 				uc.logger.Debug("Parsed dependencies from file",
@@ -354,8 +798,28 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 	// Wait for all file workers to complete
 	fileWg.Wait()
 
-	// Update project with parsed dependencies
+	// Update project with parsed dependencies, one entry per package declared in a manifest and locked
+	projectDependencies, reconciled := uc.reconcileLockfiles(project, fileDependencies)
+	projectDependencies, duplicates := deduplicateDependencies(projectDependencies)
 	project.Dependencies = projectDependencies
+	if reconciled > 0 || duplicates > 0 {
+		uc.logger.Debug("Merged dependencies listed more than once",
+			zap.String("project_id", project.ID),
+			zap.Int("declared_and_locked", reconciled),
+			zap.Int("duplicates", duplicates))
+	}
+
+	var projectInternal, projectExternal int
+	for _, dep := range projectDependencies {
+		if dep.IsInternal {
+			projectInternal++
+		} else {
+			projectExternal++
+		}
+	}
+
+	// Surface detected files that yielded no dependencies
+	project.Warnings = uc.fileWarnings(project, projectErrors)
 
 	// Log project errors but don't fail the entire project
 	if len(projectErrors) > 0 {
@@ -374,6 +838,17 @@ func (uc *AnalyzeUseCase) processProject(project *domain.Project) (int, int, int
 	return len(projectDependencies), projectInternal, projectExternal, nil
 }
 
+// scopeDependencies drops excluded dependencies, and dev and test dependencies unless they are included
+// in the analysis. Parse results are shared between files with the same content, so the filtered slice is a copy.
+func (uc *AnalyzeUseCase) scopeDependencies(dependencies []*domain.Dependency) []*domain.Dependency {
+	if uc.includeDev && uc.excluded.Empty() {
+		return dependencies
+	}
+	return slices.DeleteFunc(slices.Clone(dependencies), func(dep *domain.Dependency) bool {
+		return (!uc.includeDev && dep.IsDevelopment()) || uc.excluded.Matches(dep.Name)
+	})
+}
+
 // classifyDependenciesConcurrently classifies dependencies as internal or external concurrently
 func (uc *AnalyzeUseCase) classifyDependenciesConcurrently(
 	dependencies []*domain.Dependency,
@@ -2,9 +2,19 @@ package usecases_test
 
 import (
 	"context"
+	"di-matrix-cli/internal/checkpoint"
 	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/exclude"
+	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/policy"
+	"di-matrix-cli/internal/state"
 	"di-matrix-cli/internal/usecases"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -101,6 +111,40 @@ func (m *MockReportGenerator) GenerateJSON(ctx context.Context, projects []*doma
 	return args.Error(0)
 }
 
+func (m *MockReportGenerator) GenerateXLSX(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateDOT(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateMermaid(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateSARIF(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+func (m *MockReportGenerator) GenerateMarkdown(ctx context.Context, projects []*domain.Project) error {
+	args := m.Called(ctx, projects)
+	return args.Error(0)
+}
+
+// MockIncompleteReportGenerator is a report generator that can flag partial reports
+type MockIncompleteReportGenerator struct {
+	MockReportGenerator
+}
+
+func (m *MockIncompleteReportGenerator) MarkIncomplete(reason string) {
+	m.Called(reason)
+}
+
 func TestNewAnalyzeUseCase(t *testing.T) {
 	t.Parallel()
 
@@ -242,10 +286,11 @@ func TestExecute_Success(t *testing.T) {
 	}
 
 	dependency1 := &domain.Dependency{
-		Name:       "github.com/gin-gonic/gin",
-		Version:    "v1.9.0",
-		Ecosystem:  "go-modules",
-		IsInternal: false,
+		Name:          "github.com/gin-gonic/gin",
+		Version:       "v1.9.0",
+		LatestVersion: "v1.10.0",
+		Ecosystem:     "go-modules",
+		IsInternal:    false,
 	}
 
 	// Mock GitLab client to return repositories
@@ -294,6 +339,8 @@ func TestExecute_Success(t *testing.T) {
 	assert.Equal(t, 1, response.TotalDependencies)
 	assert.Equal(t, 0, response.InternalCount)
 	assert.Equal(t, 1, response.ExternalCount)
+	assert.Equal(t, 1, response.OutdatedCount, "behind the latest release")
+	assert.Equal(t, map[string]int{"go-modules": 1}, response.Ecosystems)
 
 	// Verify all mocks were called
 	mockGitlabClient.AssertExpectations(t)
@@ -397,6 +444,8 @@ func TestExecute_ScannerError(t *testing.T) {
 	assert.NotNil(t, response)
 	assert.Equal(t, 0, response.TotalProjects)
 	assert.Equal(t, 0, response.TotalDependencies)
+	assert.Equal(t, 1, response.RepositoryCount)
+	assert.Equal(t, 1, response.FailedRepositories)
 
 	// Verify mocks were called
 	mockGitlabClient.AssertExpectations(t)
@@ -490,3 +539,1261 @@ func TestExecute_GeneratorError(t *testing.T) {
 	mockClassifier.AssertExpectations(t)
 	mockGenerator.AssertExpectations(t)
 }
+
+func TestExecute_OutputFormats(t *testing.T) {
+	t.Parallel()
+
+	newUseCase := func(generator *MockReportGenerator) *usecases.AnalyzeUseCase {
+		mockGitlabClient := &MockGitlabClient{}
+		mockScanner := &MockRepositoryScanner{}
+		repo := &domain.Repository{ID: 1, Name: "repo", URL: "https://gitlab.com/test/repo"}
+		mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+		mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{}, nil)
+
+		return usecases.NewAnalyzeUseCase(
+			context.Background(),
+			mockGitlabClient,
+			mockScanner,
+			&MockDependencyParser{},
+			&MockDependencyClassifier{},
+			generator,
+			zap.NewNop(),
+		)
+	}
+
+	t.Run("every selected format is generated", func(t *testing.T) {
+		t.Parallel()
+		mockGenerator := &MockReportGenerator{}
+		mockGenerator.On("GenerateCSV", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+		mockGenerator.On("GenerateJSON", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+		_, err := newUseCase(mockGenerator).
+			WithOutputFormats(domain.FormatCSV, domain.FormatJSON).
+			Execute([]string{"https://gitlab.com/test/repo"}, "python")
+
+		require.NoError(t, err)
+		mockGenerator.AssertExpectations(t)
+		mockGenerator.AssertNotCalled(t, "GenerateHTML", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown formats fail", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newUseCase(&MockReportGenerator{}).
+			WithOutputFormats("pdf").
+			Execute([]string{"https://gitlab.com/test/repo"}, "python")
+
+		assert.ErrorContains(t, err, `unsupported report format "pdf"`)
+	})
+}
+
+func TestExecute_DevDependencies(t *testing.T) {
+	t.Parallel()
+
+	analyze := func(t *testing.T, includeDev bool, excluded ...string) []string {
+		t.Helper()
+		matcher, err := exclude.Compile(excluded)
+		require.NoError(t, err)
+
+		mockGitlabClient := &MockGitlabClient{}
+		mockScanner := &MockRepositoryScanner{}
+		mockParser := &MockDependencyParser{}
+		mockClassifier := &MockDependencyClassifier{}
+		mockGenerator := &MockReportGenerator{}
+
+		repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+		project := &domain.Project{
+			ID:       "repo-1-root-nodejs",
+			Name:     "web Nodejs",
+			Language: "nodejs",
+			DependencyFiles: []*domain.DependencyFile{
+				{Path: "package.json", Language: "nodejs", Content: []byte("{}")},
+			},
+		}
+		mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+		mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{project}, nil)
+		mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[0]).Return([]*domain.Dependency{
+			{Name: "react", Version: "18.2.0", Ecosystem: "npm", Scope: domain.ScopeRuntime},
+			{Name: "jest", Version: "29.7.0", Ecosystem: "npm", Scope: domain.ScopeDev},
+			{Name: "msw", Version: "2.3.0", Ecosystem: "npm", Scope: domain.ScopeTest},
+			{Name: "fsevents", Version: "2.3.3", Ecosystem: "npm", Scope: domain.ScopeOptional},
+		}, nil)
+		mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+		mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+		_, err = usecases.NewAnalyzeUseCase(
+			context.Background(),
+			mockGitlabClient,
+			mockScanner,
+			mockParser,
+			mockClassifier,
+			mockGenerator,
+			zap.NewNop(),
+		).WithDevDependencies(includeDev).WithExcludedDependencies(matcher).Execute([]string{repo.URL}, "nodejs")
+		require.NoError(t, err)
+
+		var names []string
+		for _, dep := range project.Dependencies {
+			names = append(names, dep.Name)
+		}
+		return names
+	}
+
+	t.Run("dev and test dependencies are dropped by default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"react", "fsevents"}, analyze(t, false))
+	})
+
+	t.Run("included on request", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"react", "jest", "msw", "fsevents"}, analyze(t, true))
+	})
+
+	t.Run("excluded names are dropped whatever their scope", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"react", "msw"}, analyze(t, true, "fs*", "/^je/"))
+		assert.Equal(t, []string{"react"}, analyze(t, false, "fsevents"))
+	})
+}
+
+func TestExecute_PinningPolicy(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "py-repo", URL: "https://gitlab.com/test/py"}
+	project := &domain.Project{
+		ID:       "repo-1-root-python",
+		Name:     "py-repo Python",
+		Language: "python",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "requirements.txt", Language: "python", Content: []byte("requests")},
+		},
+	}
+	pinned := &domain.Dependency{Name: "django", Version: "4.2.0", Constraint: "4.2.0", Ecosystem: "pip"}
+	floating := &domain.Dependency{Name: "requests", Constraint: "", Ecosystem: "pip"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{project}, nil)
+	mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[0]).
+		Return([]*domain.Dependency{pinned, floating}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithPolicyChecks(policy.NewPinningCheck(true, true))
+
+	response, err := useCase.Execute([]string{repo.URL}, "python")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.FloatingCount)
+	assert.Equal(t, 1, response.ProjectsWithoutLockfile)
+	require.Len(t, response.Violations, 2)
+	assert.Equal(t, policy.RuleMissingLockfile, response.Violations[0].Rule)
+	assert.Equal(t, "requests", response.Violations[1].Dependency)
+	assert.True(t, floating.IsFloating)
+	assert.False(t, pinned.IsFloating)
+	require.NotNil(t, project.Health)
+	assert.Less(t, project.Health.Score, 100.0)
+}
+
+func TestExecute_VersionConflicts(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	project := &domain.Project{
+		ID:       "repo-1-root-nodejs",
+		Name:     "web Nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "package-lock.json", Language: "nodejs", Content: []byte("{}")},
+		},
+	}
+	lodashNew := &domain.Dependency{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"}
+	lodashOld := &domain.Dependency{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}
+	react := &domain.Dependency{Name: "react", Version: "18.2.0", Ecosystem: "npm"}
+	reactPrefixed := &domain.Dependency{Name: "react", Version: "v18.2", Ecosystem: "npm"}
+	qsNew := &domain.Dependency{Name: "qs", Version: "6.10.0", Ecosystem: "npm"}
+	qsOld := &domain.Dependency{Name: "qs", Version: "6.9.0", Ecosystem: "npm"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{project}, nil)
+	mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[0]).
+		Return([]*domain.Dependency{lodashNew, lodashOld, react, reactPrefixed, qsNew, qsOld}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "nodejs")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.ConflictCount)
+	assert.Equal(t, []string{"4.17.20", "4.17.21"}, lodashNew.ConflictVersions)
+	assert.Equal(t, []string{"4.17.20", "4.17.21"}, lodashOld.ConflictVersions)
+	assert.Empty(t, react.ConflictVersions, "Equivalent spellings are one version")
+	assert.Equal(t, []string{"6.9.0", "6.10.0"}, qsNew.ConflictVersions, "Versions are ordered by precedence")
+}
+
+func TestExecute_ConstraintMismatches(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	project := &domain.Project{
+		ID:       "repo-1-root-nodejs",
+		Name:     "web Nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "package.json", Language: "nodejs", Content: []byte("{}")},
+			{Path: "package-lock.json", Language: "nodejs", Content: []byte("{}")},
+		},
+	}
+	declaredExpress := &domain.Dependency{Name: "express", Version: "^5.0.0", Constraint: "^5.0.0", Ecosystem: "npm"}
+	declaredReact := &domain.Dependency{Name: "react", Version: "^18.0.0", Constraint: "^18.0.0", Ecosystem: "npm"}
+	declaredLodash := &domain.Dependency{Name: "lodash", Version: "^4.17.0", Constraint: "^4.17.0", Ecosystem: "npm"}
+	lockedExpress := &domain.Dependency{
+		Name: "express", Version: "4.18.2", Constraint: "4.18.2", Ecosystem: "npm", Direct: true,
+	}
+	lockedReact := &domain.Dependency{
+		Name: "react", Version: "18.2.0", Constraint: "18.2.0", Ecosystem: "npm", Direct: true,
+	}
+	lockedLodash := &domain.Dependency{
+		Name: "lodash", Version: "4.17.21", Constraint: "4.17.21", Ecosystem: "npm", Direct: true,
+	}
+	// node_modules/foo/node_modules/lodash, resolved for foo's own constraint
+	nestedLodash := &domain.Dependency{
+		Name: "lodash", Version: "3.10.1", Constraint: "3.10.1", Ecosystem: "npm", Parents: []string{"foo"},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{project}, nil)
+	mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[0]).
+		Return([]*domain.Dependency{declaredExpress, declaredReact, declaredLodash}, nil)
+	mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[1]).
+		Return([]*domain.Dependency{lockedExpress, lockedReact, lockedLodash, nestedLodash}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "nodejs")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.ConstraintMismatchCount)
+	assert.True(t, lockedExpress.ConstraintMismatch)
+	assert.Equal(t, "^5.0.0", lockedExpress.DeclaredConstraint)
+	assert.False(t, lockedReact.ConstraintMismatch)
+	assert.False(t, declaredExpress.ConstraintMismatch, "Ranges themselves are never flagged")
+	assert.False(t, lockedLodash.ConstraintMismatch)
+	assert.False(t, nestedLodash.ConstraintMismatch, "Nested copies are not checked against the project's ranges")
+}
+
+func TestExecute_VersioningSchemes(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/test/api"}
+	project := &domain.Project{
+		ID:       "repo-1-root-python",
+		Name:     "api Python",
+		Language: "python",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "requirements.txt", Language: "python", Content: []byte("pytz")},
+		},
+	}
+	pytz := &domain.Dependency{Name: "pytz", Version: "2024.1", Ecosystem: "pip"}
+	pytzRange := &domain.Dependency{Name: "pytz", Version: ">=2023.3", Ecosystem: "pip"}
+	requests := &domain.Dependency{Name: "requests", Version: "2.31.0", Ecosystem: "pip"}
+	latest := &domain.Dependency{Name: "internal-tool", Version: "latest", Ecosystem: "pip"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{project}, nil)
+	mockParser.On("ParseFile", mock.Anything, project.DependencyFiles[0]).
+		Return([]*domain.Dependency{pytz, pytzRange, requests, latest}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	_, err := useCase.Execute([]string{repo.URL}, "python")
+
+	require.NoError(t, err)
+	assert.Equal(t, "calver", pytz.VersioningScheme)
+	assert.Equal(t, "calver", pytzRange.VersioningScheme, "Ranges take the scheme of the package")
+	assert.Equal(t, "semver", requests.VersioningScheme)
+	assert.Empty(t, latest.VersioningScheme)
+}
+
+func TestExecute_FileWarnings(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "svc", URL: "https://gitlab.com/test/svc"}
+	sumOnly := &domain.Project{
+		ID:       "repo-1-legacy-go",
+		Language: "go",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "legacy/go.sum", Language: "go", Content: []byte("")},
+		},
+	}
+	module := &domain.Project{
+		ID:       "repo-1-root-go",
+		Language: "go",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/svc\n\nrequire go.uber.org/zap v1.27.0\n")},
+			{Path: "go.sum", Language: "go", Content: []byte("")},
+			{Path: "Gopkg.lock", Language: "go", Content: []byte("")},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{sumOnly, module}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "go")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.WarningCount)
+
+	require.Len(t, sumOnly.Warnings, 1)
+	assert.Equal(t, domain.FileIgnored, sumOnly.Warnings[0].Capability)
+
+	require.Len(t, module.Warnings, 1)
+	assert.Equal(t, "Gopkg.lock", module.Warnings[0].File)
+	assert.Equal(t, domain.FileUnsupported, module.Warnings[0].Capability)
+}
+
+func TestExecute_GoWorkspaceModules(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "mono", URL: "https://gitlab.com/test/mono"}
+	root := &domain.Project{
+		ID:         "repo-1-go-example.com/mono",
+		Language:   "go",
+		ModuleName: "example.com/mono",
+		Members:    []string{"repo-1-go-example.com/mono/auth", "repo-1-go-example.com/mono/billing"},
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.work", Language: "go", Content: []byte("go 1.25\n\nuse (\n\t.\n\t./services/auth\n\t./services/billing\n)\n")},
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/mono\n\nrequire example.com/mono/auth v0.1.0\n")},
+		},
+	}
+	auth := &domain.Project{
+		ID:         "repo-1-go-example.com/mono/auth",
+		Path:       "services/auth",
+		Language:   "go",
+		ModuleName: "example.com/mono/auth",
+		ParentID:   root.ID,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "services/auth/go.mod", Language: "go", Content: []byte("module example.com/mono/auth\n\n" +
+				"require go.uber.org/zap v1.27.0\n")},
+		},
+	}
+	billing := &domain.Project{
+		ID:         "repo-1-go-example.com/mono/billing",
+		Path:       "services/billing",
+		Language:   "go",
+		ModuleName: "example.com/mono/billing",
+		ParentID:   root.ID,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "services/billing/go.mod", Language: "go", Content: []byte("module example.com/mono/billing\n\n" +
+				"require (\n\texample.com/mono/auth v0.0.0-00010101000000-000000000000\n\texample.com/mono v0.1.0\n)\n\n" +
+				"replace example.com/mono => ../../\n")},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{root, auth, billing}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	_, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).Execute([]string{repo.URL}, "go")
+	require.NoError(t, err)
+
+	replacements := make(map[string]string)
+	for _, project := range []*domain.Project{root, auth, billing} {
+		for _, dep := range project.Dependencies {
+			if dep.Name != project.ModuleName {
+				replacements[project.ModuleName+" -> "+dep.Name] = dep.ReplacedBy
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"example.com/mono -> example.com/mono/auth":         "./services/auth",
+		"example.com/mono/auth -> go.uber.org/zap":          "",
+		"example.com/mono/billing -> example.com/mono/auth": "../auth",
+		"example.com/mono/billing -> example.com/mono":      "../../",
+	}, replacements, "go.mod replacements win over the workspace")
+}
+
+func TestExecute_LockfileFallback(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	web := &domain.Project{
+		ID:       "repo-1-web-python",
+		Language: "python",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "web/pyproject.toml", Language: "python", Content: []byte("[project]\ndependencies = [\"requests>=2.31\"]\n")},
+			{Path: "web/poetry.lock", Language: "python", Content: []byte("[[package]\nname = ")},
+		},
+	}
+	lockOnly := &domain.Project{
+		ID:       "repo-1-admin-python",
+		Language: "python",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "admin/poetry.lock", Language: "python", Content: []byte("[[package]\nname = ")},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{web, lockOnly}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "python")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.FallbackCount)
+
+	require.Len(t, web.Dependencies, 1, "The declared dependencies of pyproject.toml are kept")
+	assert.Equal(t, "requests", web.Dependencies[0].Name)
+	require.Len(t, web.Warnings, 1)
+	assert.Equal(t, "web/poetry.lock", web.Warnings[0].File)
+	assert.Equal(t, "web/pyproject.toml", web.Warnings[0].Fallback)
+
+	require.Len(t, lockOnly.Warnings, 1)
+	assert.Empty(t, lockOnly.Warnings[0].Fallback, "Without a pyproject.toml there is nothing to fall back to")
+}
+
+func TestExecute_PackageLockFallback(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	web := &domain.Project{
+		ID:       "repo-1-web-nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "web/package.json", Language: "nodejs", Content: []byte(`{"dependencies": {"express": "^4.18.2"}}`)},
+			{Path: "web/package-lock.json", Language: "nodejs", Content: []byte(`{"lockfileVersion": 3, "packages": `)},
+		},
+	}
+	lockOnly := &domain.Project{
+		ID:       "repo-1-admin-nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "admin/package-lock.json", Language: "nodejs", Content: []byte(`{"lockfileVersion": 3, "packages": `)},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{web, lockOnly}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "nodejs")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.FallbackCount)
+
+	require.Len(t, web.Dependencies, 1, "The declared dependencies of package.json are kept")
+	assert.Equal(t, "express", web.Dependencies[0].Name)
+	require.Len(t, web.Warnings, 1)
+	assert.Equal(t, "web/package-lock.json", web.Warnings[0].File)
+	assert.Equal(t, "web/package.json", web.Warnings[0].Fallback)
+
+	require.Len(t, lockOnly.Warnings, 1)
+	assert.Empty(t, lockOnly.Warnings[0].Fallback, "Without a package.json there is nothing to fall back to")
+}
+
+func TestExecute_LockfileReconciliation(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	web := &domain.Project{
+		ID:       "repo-1-web-nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "web/package.json", Language: "nodejs", Content: []byte(`{
+	"dependencies": {"react": "^18.2.0", "express": "^5.0.0", "left-pad": "^1.3.0"}
+}`)},
+			{Path: "web/package-lock.json", Language: "nodejs", Content: []byte(`{
+	"name": "web",
+	"lockfileVersion": 3,
+	"packages": {
+		"": {"name": "web", "dependencies": {"react": "^18.2.0", "express": "^5.0.0"}},
+		"node_modules/react": {"version": "18.2.0", "dependencies": {"loose-envify": "^1.1.0"}},
+		"node_modules/loose-envify": {"version": "1.4.0"},
+		"node_modules/express": {"version": "4.18.2"}
+	}
+}`)},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{web}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).Execute([]string{repo.URL}, "nodejs")
+	require.NoError(t, err)
+
+	byName := make(map[string]*domain.Dependency)
+	for _, dep := range web.Dependencies {
+		require.NotContains(t, byName, dep.Name, "declared and locked packages are one entry")
+		byName[dep.Name] = dep
+	}
+	require.Len(t, byName, 4)
+	assert.Equal(t, 4, response.TotalDependencies)
+
+	react := byName["react"]
+	assert.Equal(t, "18.2.0", react.Version)
+	assert.Equal(t, "^18.2.0", react.Constraint)
+	assert.Equal(t, "19.0.0", react.MaxVersion)
+	assert.False(t, react.ConstraintMismatch)
+
+	express := byName["express"]
+	assert.Equal(t, "4.18.2", express.Version)
+	assert.True(t, express.ConstraintMismatch, "the stale lockfile does not satisfy the declared constraint")
+	assert.Equal(t, "^5.0.0", express.DeclaredConstraint)
+	assert.Equal(t, 1, response.ConstraintMismatchCount)
+
+	assert.Equal(t, "1.4.0", byName["loose-envify"].Constraint, "transitive packages keep their locked version")
+	assert.False(t, byName["loose-envify"].Direct)
+	assert.Equal(t, "^1.3.0", byName["left-pad"].Version, "packages missing from the lockfile keep their declaration")
+}
+
+func TestExecute_DeduplicatesDependencies(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "web", URL: "https://gitlab.com/test/web"}
+	web := &domain.Project{
+		ID:       "repo-1-web-nodejs",
+		Language: "nodejs",
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "web/package-lock.json", Language: "nodejs", Content: []byte(`{
+	"name": "web",
+	"lockfileVersion": 3,
+	"packages": {
+		"": {"name": "web", "dependencies": {"debug": "^4.3.4", "send": "^0.18.0"}},
+		"node_modules/debug": {"version": "4.3.4", "dependencies": {"ms": "2.1.2"}},
+		"node_modules/ms": {"version": "2.1.2"},
+		"node_modules/send": {"version": "0.18.0", "dependencies": {"ms": "2.1.3"}},
+		"node_modules/send/node_modules/ms": {"version": "2.1.3"},
+		"node_modules/send/node_modules/debug": {"version": "4.3.4"}
+	}
+}`)},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{web}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).Execute([]string{repo.URL}, "nodejs")
+	require.NoError(t, err)
+
+	versions := make(map[string][]string)
+	for _, dep := range web.Dependencies {
+		versions[dep.Name] = append(versions[dep.Name], dep.Version)
+	}
+	assert.Equal(t, []string{"4.3.4"}, versions["debug"], "the nested copy at the same version is merged")
+	assert.ElementsMatch(t, []string{"2.1.2", "2.1.3"}, versions["ms"])
+	assert.Equal(t, 4, response.TotalDependencies)
+	assert.Equal(t, 1, response.ConflictCount)
+	for _, dep := range web.Dependencies {
+		if dep.Name == "debug" {
+			assert.True(t, dep.Direct, "a merged entry is direct when any copy is")
+		}
+	}
+}
+
+// MockSkippedFileScanner is a scanner mock that also reports skipped dependency files
+type MockSkippedFileScanner struct {
+	MockRepositoryScanner
+	skipped map[string][]domain.SkippedFile
+}
+
+func (m *MockSkippedFileScanner) SkippedFiles(repoURL string) []domain.SkippedFile {
+	return m.skipped[repoURL]
+}
+
+// MockCoverageReportGenerator is a report generator that records repository coverage
+type MockCoverageReportGenerator struct {
+	MockReportGenerator
+	coverage []domain.RepositoryCoverage
+}
+
+func (m *MockCoverageReportGenerator) RecordCoverage(coverage []domain.RepositoryCoverage) {
+	m.coverage = coverage
+}
+
+func TestExecute_Coverage(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockCoverageReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "svc", URL: "https://gitlab.com/test/svc"}
+	mockScanner := &MockSkippedFileScanner{skipped: map[string][]domain.SkippedFile{
+		repo.URL: {
+			{File: "vendor/example.com/lib/go.mod", Language: "go", Reason: domain.GapExcluded},
+			{File: "tools/go.mod", Language: "go", Reason: domain.GapUnavailable},
+			{File: "web/node_modules/left-pad/package.json", Language: "nodejs", Reason: domain.GapExcluded},
+		},
+	}}
+	module := &domain.Project{
+		ID:         "repo-1-root-go",
+		Language:   "go",
+		Repository: *repo,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/svc\n\nrequire go.uber.org/zap v1.27.0\n")},
+			{Path: "go.sum", Language: "go", Content: []byte("")},
+		},
+	}
+	broken := &domain.Project{
+		ID:         "repo-1-legacy-go",
+		Language:   "go",
+		Repository: *repo,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "legacy/go.mod", Language: "go", Content: []byte("module\nrequire (\n")},
+		},
+	}
+	unknown := &domain.Project{
+		ID:              "repo-1-root-unknown",
+		Language:        "unknown",
+		Repository:      *repo,
+		DependencyFiles: []*domain.DependencyFile{{Path: "deps.lock", Language: "unknown"}},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, repo.URL).Return([]*domain.Repository{repo}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{module, broken, unknown}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{repo.URL}, "go")
+
+	require.NoError(t, err)
+	assert.InDelta(t, 40.0, response.Coverage, 0.001)
+
+	require.Len(t, mockGenerator.coverage, 1)
+	coverage := mockGenerator.coverage[0]
+	assert.Equal(t, "svc", coverage.Repository.Name)
+	assert.Equal(t, 2, coverage.Analyzed, "go.sum is understood even though it carries no dependencies")
+	assert.Equal(t, 1, coverage.Excluded, "Files of other languages are out of scope")
+
+	reasons := make(map[string]domain.CoverageGapReason)
+	for _, gap := range coverage.Gaps {
+		reasons[gap.File] = gap.Reason
+	}
+	assert.Equal(t, map[string]domain.CoverageGapReason{
+		"legacy/go.mod": domain.GapUnparsed,
+		"deps.lock":     domain.GapUnknownLanguage,
+		"tools/go.mod":  domain.GapUnavailable,
+	}, reasons)
+}
+
+func TestExecute_Issues(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	repo := &domain.Repository{ID: 1, Name: "svc", URL: "https://gitlab.com/test/svc"}
+	unreachable := &domain.Repository{ID: 2, Name: "legacy", URL: "https://gitlab.com/test/legacy"}
+	mockScanner := &MockSkippedFileScanner{skipped: map[string][]domain.SkippedFile{
+		repo.URL: {{File: "tools/go.mod", Language: "go", Reason: domain.GapUnavailable}},
+	}}
+	module := &domain.Project{
+		ID:         "repo-1-root-go",
+		Language:   "go",
+		Repository: *repo,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/svc\n\nrequire go.uber.org/zap v1.27.0\n")},
+			{Path: "Gopkg.lock", Language: "go", Content: []byte("")},
+		},
+	}
+	broken := &domain.Project{
+		ID:         "repo-1-legacy-go",
+		Language:   "go",
+		Path:       "legacy",
+		Repository: *repo,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "legacy/go.mod", Language: "go", Content: []byte("module\nrequire (\n")},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, mock.Anything).Return([]*domain.Repository{repo, unreachable}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, repo).Return([]*domain.Project{module, broken}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, unreachable).
+		Return([]*domain.Project(nil), errors.New("403 Forbidden"))
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.AnythingOfType("[]*domain.Project")).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	)
+
+	response, err := useCase.Execute([]string{"https://gitlab.com/test"}, "go")
+
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 3)
+	assert.Equal(t, "legacy", response.Errors[0].Repository.Name)
+	assert.Equal(t, domain.StageScan, response.Errors[0].Stage)
+	assert.Contains(t, response.Errors[0].Message, "403 Forbidden")
+	assert.Equal(t, "tools/go.mod", response.Errors[1].File)
+	assert.Equal(t, "legacy", response.Errors[2].Project)
+	assert.Equal(t, "legacy/go.mod", response.Errors[2].File)
+	assert.Equal(t, domain.StageParse, response.Errors[2].Stage)
+
+	require.Len(t, response.Warnings, 1)
+	assert.Equal(t, "Gopkg.lock", response.Warnings[0].File)
+	assert.Equal(t, ".", response.Warnings[0].Project)
+}
+
+// stallingScanner never finishes scanning the stalled repository before its context ends
+type stallingScanner struct {
+	stalled string
+}
+
+func (s *stallingScanner) DetectProjects(ctx context.Context, repo *domain.Repository) ([]*domain.Project, error) {
+	if repo.URL == s.stalled {
+		<-ctx.Done()
+		return nil, fmt.Errorf("failed to list repository tree: %w", ctx.Err())
+	}
+	return []*domain.Project{{ID: "repo-1-root-go", Language: "go", Repository: *repo}}, nil
+}
+
+func TestExecute_RepositoryTimeout(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/group/api"}
+	monorepo := &domain.Repository{ID: 2, Name: "monorepo", URL: "https://gitlab.com/group/monorepo"}
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/group").
+		Return([]*domain.Repository{api, monorepo}, nil)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		&stallingScanner{stalled: monorepo.URL},
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithRepositoryTimeout(20*time.Millisecond).Execute([]string{"https://gitlab.com/group"}, "go")
+
+	require.NoError(t, err)
+	assert.False(t, response.Interrupted, "A repository timeout does not stop the analysis")
+	assert.Equal(t, 1, response.FailedRepositories)
+	assert.Equal(t, 1, response.TotalProjects)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "monorepo", response.Errors[0].Repository.Name)
+	assert.Contains(t, response.Errors[0].Message, "scan exceeded the repository timeout of 20ms")
+}
+
+// MockSubmoduleGitlabClient is a GitLab client mock that also lists submodules
+type MockSubmoduleGitlabClient struct {
+	MockGitlabClient
+}
+
+func (m *MockSubmoduleGitlabClient) GetSubmodules(ctx context.Context, repoURL string) ([]domain.Submodule, error) {
+	args := m.Called(ctx, repoURL)
+	return args.Get(0).([]domain.Submodule), args.Error(1)
+}
+
+func TestExecute_ResolvesSubmodules(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockSubmoduleGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	app := &domain.Repository{ID: 1, Name: "app", URL: "https://gitlab.com/group/app"}
+	shared := &domain.Repository{ID: 2, Name: "shared", URL: "https://gitlab.com/group/shared"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, app.URL).Return([]*domain.Repository{app}, nil)
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, shared.URL).Return([]*domain.Repository{shared}, nil)
+	mockGitlabClient.On("GetSubmodules", mock.Anything, app.URL).
+		Return([]domain.Submodule{{Path: "libs/shared", URL: shared.URL}}, nil)
+	// The shared library points back at the app, which must not be analyzed twice
+	mockGitlabClient.On("GetSubmodules", mock.Anything, shared.URL).
+		Return([]domain.Submodule{{Path: "app", URL: app.URL}}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, mock.Anything).Return([]*domain.Project{}, nil)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithSubmoduleResolution(true)
+
+	_, err := useCase.Execute([]string{app.URL}, "go")
+
+	require.NoError(t, err)
+	mockScanner.AssertCalled(t, "DetectProjects", mock.Anything, app)
+	mockScanner.AssertCalled(t, "DetectProjects", mock.Anything, shared)
+	mockScanner.AssertNumberOfCalls(t, "DetectProjects", 2)
+}
+
+func TestExecute_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockIncompleteReportGenerator{}
+
+	docs := &domain.Repository{ID: 1, Name: "docs", URL: "https://gitlab.com/group/docs"}
+	api := &domain.Repository{ID: 2, Name: "api", URL: "https://gitlab.com/group/api"}
+	docsProject := &domain.Project{ID: "repo-1-root-python", Language: "python", Repository: *docs}
+	apiProject := &domain.Project{
+		ID:              "repo-2-root-go",
+		Language:        "go",
+		Repository:      *api,
+		DependencyFiles: []*domain.DependencyFile{{Path: "go.mod", Language: "go"}},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/group").
+		Return([]*domain.Repository{docs, api}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, docs).Return([]*domain.Project{docsProject}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, api).Return([]*domain.Project{apiProject}, nil)
+	mockGenerator.On("MarkIncomplete", "the analysis was interrupted, 1 of 2 repositories were not analyzed").Return()
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	// Cancelled before dependency processing, e.g. by Ctrl-C
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := usecases.NewAnalyzeUseCase(
+		ctx,
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).Execute([]string{"https://gitlab.com/group"}, "go")
+
+	require.NoError(t, err)
+	assert.True(t, response.Interrupted)
+	assert.Equal(t, 1, response.PendingRepositories)
+	assert.Zero(t, response.TotalProjects)
+	mockParser.AssertNotCalled(t, "ParseFile", mock.Anything, mock.Anything)
+	mockGenerator.AssertCalled(t, "MarkIncomplete", mock.Anything)
+	mockGenerator.AssertCalled(t, "GenerateHTML", mock.Anything, []*domain.Project(nil))
+
+	// The repository without go projects is complete, the one whose project was never processed is not
+	require.NotNil(t, response.Checkpoint)
+	assert.Equal(t, "go", response.Checkpoint.Language)
+	assert.Equal(t, []string{docs.URL}, response.Checkpoint.Repositories)
+	assert.Empty(t, response.Checkpoint.Projects)
+	assert.Equal(t, []string{api.URL}, response.Checkpoint.Scanned, "The scanned repository is not scanned again")
+	require.Len(t, response.Checkpoint.ScannedProjects, 1)
+	assert.Equal(t, "go.mod", response.Checkpoint.ScannedProjects[0].DependencyFiles[0].Path)
+}
+
+// cancellingResolver cancels the analysis while latest versions are looked up, e.g. by Ctrl-C
+type cancellingResolver struct {
+	cancel context.CancelFunc
+}
+
+func (r *cancellingResolver) ResolveLatestVersions(_ context.Context, _ []*domain.Project) int {
+	r.cancel()
+	return 0
+}
+
+func TestExecute_InterruptedDuringLookups(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockIncompleteReportGenerator{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/group/api"}
+	project := &domain.Project{
+		ID:         "repo-1-root-go",
+		Language:   "go",
+		Repository: *api,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/api\n\nrequire go.uber.org/zap v1.27.0\n")},
+		},
+	}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, api.URL).Return([]*domain.Repository{api}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, api).Return([]*domain.Project{project}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("MarkIncomplete", mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "interrupted during registry and advisory lookups")
+	})).Return()
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	response, err := usecases.NewAnalyzeUseCase(
+		ctx,
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithLatestVersionResolver(&cancellingResolver{cancel: cancel}).Execute([]string{api.URL}, "go")
+
+	require.NoError(t, err)
+	assert.True(t, response.Interrupted)
+	assert.Zero(t, response.PendingRepositories, "Every repository was parsed")
+	assert.Equal(t, 1, response.TotalProjects)
+	mockGenerator.AssertCalled(t, "GenerateHTML", mock.Anything, []*domain.Project{project})
+
+	// A resumed run only repeats the lookups
+	require.NotNil(t, response.Checkpoint)
+	assert.Equal(t, []string{api.URL}, response.Checkpoint.Repositories)
+}
+
+func TestExecute_ResumesCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	done := &domain.Repository{ID: 1, Name: "done", URL: "https://gitlab.com/group/done"}
+	pending := &domain.Repository{ID: 2, Name: "pending", URL: "https://gitlab.com/group/pending"}
+	removed := &domain.Repository{ID: 3, Name: "removed", URL: "https://gitlab.com/group/removed"}
+	resumed := &domain.Project{
+		ID:           "repo-1-root-go",
+		Language:     "go",
+		Repository:   *done,
+		Dependencies: []*domain.Dependency{{Name: "gitlab.com/group/lib", Version: "v1.0.0", IsInternal: true}},
+	}
+	stale := &domain.Project{ID: "repo-3-root-go", Language: "go", Repository: *removed}
+	resume := checkpoint.New("go")
+	resume.Repositories = []string{done.URL, removed.URL}
+	resume.Projects = []*domain.Project{resumed, stale}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/group").
+		Return([]*domain.Repository{done, pending}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, pending).Return([]*domain.Project{}, nil)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithCheckpoint(resume).Execute([]string{"https://gitlab.com/group"}, "go")
+
+	require.NoError(t, err)
+	assert.False(t, response.Interrupted)
+	assert.Equal(t, 2, response.RepositoryCount)
+	assert.Equal(t, 1, response.ResumedRepositories)
+	assert.Equal(t, 1, response.TotalProjects)
+	assert.Equal(t, 1, response.InternalCount)
+	mockScanner.AssertNotCalled(t, "DetectProjects", mock.Anything, done)
+	mockGenerator.AssertCalled(t, "GenerateHTML", mock.Anything, []*domain.Project{resumed})
+}
+
+func TestExecute_ResumesScannedRepositories(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/group/api"}
+	detected := &domain.Project{
+		ID:         "repo-1-root-go",
+		Language:   "go",
+		Repository: *api,
+		DependencyFiles: []*domain.DependencyFile{
+			{Path: "go.mod", Language: "go", Content: []byte("module example.com/api\n\nrequire go.uber.org/zap v1.27.0\n")},
+		},
+	}
+	resume := checkpoint.New("go")
+	resume.Scanned = []string{api.URL}
+	resume.ScannedProjects = []*domain.Project{detected}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/group").
+		Return([]*domain.Repository{api}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		parser.NewParser(),
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithCheckpoint(resume).WithPeriodicCheckpoint(path, time.Hour).Execute([]string{"https://gitlab.com/group"}, "go")
+
+	require.NoError(t, err)
+	assert.Zero(t, response.ResumedRepositories, "Scanned repositories are still processed")
+	assert.NotZero(t, response.TotalDependencies)
+	mockScanner.AssertNotCalled(t, "DetectProjects", mock.Anything, mock.Anything)
+
+	// Once every project is processed the saved checkpoint completes the repository
+	saved, err := checkpoint.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{api.URL}, saved.Repositories)
+	assert.Empty(t, saved.Scanned)
+	require.Len(t, saved.Projects, 1)
+	assert.Len(t, saved.Projects[0].Dependencies, response.TotalDependencies)
+}
+
+type MockRevisionGitlabClient struct {
+	MockGitlabClient
+}
+
+func (m *MockRevisionGitlabClient) HeadCommit(ctx context.Context, repoURL string) (string, error) {
+	args := m.Called(ctx, repoURL)
+	return args.String(0), args.Error(1)
+}
+
+func TestExecute_IncrementalState(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockRevisionGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+	mockParser := &MockDependencyParser{}
+	mockClassifier := &MockDependencyClassifier{}
+	mockGenerator := &MockReportGenerator{}
+
+	same := &domain.Repository{ID: 1, Name: "same", URL: "https://gitlab.com/group/same"}
+	moved := &domain.Repository{ID: 2, Name: "moved", URL: "https://gitlab.com/group/moved"}
+	unresolved := &domain.Repository{ID: 3, Name: "unresolved", URL: "https://gitlab.com/group/unresolved"}
+	recorded := &domain.Project{
+		ID:           "repo-1-root-go",
+		Language:     "go",
+		Repository:   *same,
+		Dependencies: []*domain.Dependency{{Name: "gitlab.com/group/lib", Version: "v1.0.0", IsInternal: true}},
+	}
+	changed := &domain.Project{
+		ID:              "repo-2-root-go",
+		Language:        "go",
+		Repository:      *moved,
+		DependencyFiles: []*domain.DependencyFile{{Path: "go.mod", Language: "go", Content: []byte("module moved")}},
+	}
+	previous := state.New("go", "settings")
+	previous.Record(same.URL, "aaa").Projects = []*domain.Project{recorded}
+	previous.Record(moved.URL, "bbb").Projects = []*domain.Project{{ID: "repo-2-root-go", Repository: *moved}}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/group").
+		Return([]*domain.Repository{same, moved, unresolved}, nil)
+	mockGitlabClient.On("HeadCommit", mock.Anything, same.URL).Return("aaa", nil)
+	mockGitlabClient.On("HeadCommit", mock.Anything, moved.URL).Return("ccc", nil)
+	mockGitlabClient.On("HeadCommit", mock.Anything, unresolved.URL).Return("", errors.New("404 Not Found"))
+	mockScanner.On("DetectProjects", mock.Anything, moved).Return([]*domain.Project{changed}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, unresolved).Return([]*domain.Project{}, nil)
+	mockParser.On("ParseFile", mock.Anything, changed.DependencyFiles[0]).Return([]*domain.Dependency{
+		{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: "go", Direct: true},
+	}, nil)
+	mockClassifier.On("IsInternal", mock.Anything, mock.Anything).Return(false)
+	mockGenerator.On("GenerateHTML", mock.Anything, mock.Anything).Return(nil)
+
+	response, err := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		mockGitlabClient,
+		mockScanner,
+		mockParser,
+		mockClassifier,
+		mockGenerator,
+		zap.NewNop(),
+	).WithIncrementalState(previous).Execute([]string{"https://gitlab.com/group"}, "go")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.RepositoryCount)
+	assert.Equal(t, 1, response.UnchangedRepositories)
+	assert.Equal(t, 0, response.ResumedRepositories)
+	assert.Equal(t, 2, response.TotalProjects)
+	assert.Equal(t, 1, response.InternalCount)
+	assert.Equal(t, 1, response.ExternalCount)
+	mockScanner.AssertNotCalled(t, "DetectProjects", mock.Anything, same)
+	mockGenerator.AssertCalled(t, "GenerateHTML", mock.Anything, []*domain.Project{recorded, changed})
+
+	require.NotNil(t, response.State)
+	assert.True(t, response.State.Matches("go", "settings"))
+	projects, ok := response.State.Unchanged(same.URL, "aaa")
+	require.True(t, ok, "unchanged repositories stay recorded")
+	assert.Equal(t, []*domain.Project{recorded}, projects)
+	projects, ok = response.State.Unchanged(moved.URL, "ccc")
+	require.True(t, ok, "analyzed repositories are recorded at their new commit")
+	assert.Equal(t, []*domain.Project{changed}, projects)
+	assert.NotContains(t, response.State.Repositories, unresolved.URL)
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"context"
+	"di-matrix-cli/internal/checkpoint"
+	"di-matrix-cli/internal/domain"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkpointTracker follows the repositories of a running analysis so an interrupted, killed or crashed run can
+// be resumed: a repository is scanned once its projects are detected and completed once every project of the
+// target language is processed. With a path set the checkpoint is saved periodically.
+type checkpointTracker struct {
+	mu        sync.Mutex
+	language  string
+	completed map[string]bool
+	projects  []*domain.Project            // Projects of the completed repositories
+	scanned   map[string][]*domain.Project // Detected projects of the repositories not completed yet
+	pending   map[string]int               // Projects of the scanned repositories left to process
+	processed map[string][]*domain.Project // Processed projects of the scanned repositories
+	path      string
+	interval  time.Duration
+	lastSave  time.Time
+	logger    *zap.Logger
+}
+
+// newCheckpointTracker starts tracking an analysis of language, with the completed repositories of the resumed
+// checkpoint and their projects
+func (uc *AnalyzeUseCase) newCheckpointTracker(language string, resumed []*domain.Project) *checkpointTracker {
+	tracker := &checkpointTracker{
+		language:  language,
+		completed: make(map[string]bool),
+		projects:  resumed,
+		scanned:   make(map[string][]*domain.Project),
+		pending:   make(map[string]int),
+		processed: make(map[string][]*domain.Project),
+		path:      uc.checkpointPath,
+		interval:  uc.checkpointInterval,
+		lastSave:  time.Now(),
+		logger:    uc.logger,
+	}
+	if uc.checkpoint != nil && uc.checkpoint.Language == language {
+		for _, url := range uc.checkpoint.Repositories {
+			tracker.completed[url] = true
+		}
+	}
+	return tracker
+}
+
+// scan records the projects detected in a repository, it is completed right away without projects to process
+func (t *checkpointTracker) scan(repository *domain.Repository, projects []*domain.Project) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Workers fill the dependencies of the detected projects while checkpoints are written, shallow copies
+	// keep the detected state
+	var detected []*domain.Project
+	for _, project := range projects {
+		if matchesLanguage(project, t.language) {
+			snapshot := *project
+			detected = append(detected, &snapshot)
+		}
+	}
+
+	if len(detected) == 0 {
+		t.completed[repository.URL] = true
+	} else {
+		t.scanned[repository.URL] = detected
+		t.pending[repository.URL] = len(detected)
+	}
+	t.save(false)
+}
+
+// process records a processed project, successfully or not, its repository is completed with its last project
+func (t *checkpointTracker) process(project *domain.Project) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	url := project.Repository.URL
+	if _, ok := t.scanned[url]; !ok {
+		return
+	}
+	t.processed[url] = append(t.processed[url], project)
+	t.pending[url]--
+	if t.pending[url] == 0 {
+		t.completed[url] = true
+		t.projects = append(t.projects, t.processed[url]...)
+		delete(t.scanned, url)
+		delete(t.pending, url)
+		delete(t.processed, url)
+	}
+	t.save(false)
+}
+
+// flush saves the checkpoint regardless of the interval
+func (t *checkpointTracker) flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.save(true)
+}
+
+// checkpoint returns the completed and scanned repositories with their projects
+func (t *checkpointTracker) checkpoint() *checkpoint.Checkpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.build()
+}
+
+// build assembles the checkpoint, the caller holds the lock
+func (t *checkpointTracker) build() *checkpoint.Checkpoint {
+	result := checkpoint.New(t.language)
+	for url := range t.completed {
+		result.Repositories = append(result.Repositories, url)
+	}
+	sort.Strings(result.Repositories)
+	for _, project := range t.projects {
+		if t.completed[project.Repository.URL] {
+			result.Projects = append(result.Projects, project)
+		}
+	}
+	sort.SliceStable(result.Projects, func(i, j int) bool { return result.Projects[i].ID < result.Projects[j].ID })
+
+	for url, projects := range t.scanned {
+		result.Scanned = append(result.Scanned, url)
+		result.ScannedProjects = append(result.ScannedProjects, projects...)
+	}
+	sort.Strings(result.Scanned)
+	sort.SliceStable(result.ScannedProjects, func(i, j int) bool {
+		return result.ScannedProjects[i].ID < result.ScannedProjects[j].ID
+	})
+	return result
+}
+
+// save writes the checkpoint once the interval elapsed since the last save, or right away when forced.
+// The caller holds the lock.
+func (t *checkpointTracker) save(force bool) {
+	if t.path == "" || (!force && time.Since(t.lastSave) < t.interval) {
+		return
+	}
+
+	result := t.build()
+	if err := result.Save(t.path); err != nil {
+		t.logger.Warn("Failed to save checkpoint", zap.String("path", t.path), zap.Error(err))
+		return
+	}
+	t.lastSave = time.Now()
+	t.logger.Debug("Saved checkpoint",
+		zap.String("path", t.path),
+		zap.Int("completed_repositories", len(result.Repositories)),
+		zap.Int("scanned_repositories", len(result.Scanned)))
+}
+
+// checkpointScanner records detected projects in the checkpoint and serves the projects a resumed checkpoint
+// detected without scanning their repositories again
+type checkpointScanner struct {
+	domain.RepositoryScanner
+	detected map[string][]*domain.Project // Projects detected by the resumed run by repository URL
+	tracker  *checkpointTracker
+}
+
+func (s *checkpointScanner) DetectProjects(ctx context.Context, repo *domain.Repository) ([]*domain.Project, error) {
+	projects, ok := s.detected[repo.URL]
+	if !ok {
+		var err error
+		if projects, err = s.RepositoryScanner.DetectProjects(ctx, repo); err != nil {
+			return nil, err
+		}
+	}
+
+	s.tracker.scan(repo, projects)
+	return projects, nil
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"slices"
+)
+
+// annotateVersionConflicts flags packages that a single project resolves to more than one version,
+// e.g. nested npm duplicates or Maven convergence issues, and returns the number of conflicting packages.
+// Equivalent spellings such as "v1.2" and "1.2.0" are one version.
+func annotateVersionConflicts(project *domain.Project) int {
+	versionsByName := make(map[string]map[string]bool)
+	for _, dep := range project.Dependencies {
+		// Declared ranges, e.g. from package.json, are not resolved versions
+		if dep.Version == "" || isVersionRange(dep.Version) {
+			continue
+		}
+		if versionsByName[dep.Name] == nil {
+			versionsByName[dep.Name] = make(map[string]bool)
+		}
+		versionsByName[dep.Name][version.Canonical(dep.Version)] = true
+	}
+
+	conflicts := make(map[string][]string)
+	for name, versions := range versionsByName {
+		if len(versions) < 2 {
+			continue
+		}
+		list := make([]string, 0, len(versions))
+		for resolved := range versions {
+			list = append(list, resolved)
+		}
+		// "4.17.9" before "4.17.10"
+		slices.SortFunc(list, version.Compare)
+		conflicts[name] = list
+	}
+
+	for _, dep := range project.Dependencies {
+		dep.ConflictVersions = conflicts[dep.Name]
+	}
+
+	return len(conflicts)
+}
+
+// deduplicateDependencies merges entries of the same package at the same version, e.g. a package listed by
+// several dependency files of the project or installed twice in a lockfile, and returns the merged dependencies
+// with the number of dropped duplicates. A merged entry is direct and runtime when any of its copies is,
+// and keeps the first declared range.
+func deduplicateDependencies(dependencies []*domain.Dependency) ([]*domain.Dependency, int) {
+	firstByKey := make(map[string]*domain.Dependency, len(dependencies))
+	deduplicated := make([]*domain.Dependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		key := dep.Ecosystem + "\x00" + dep.Name + "\x00" + version.Canonical(dep.Version)
+		first, seen := firstByKey[key]
+		if !seen {
+			firstByKey[key] = dep
+			deduplicated = append(deduplicated, dep)
+			continue
+		}
+
+		first.Direct = first.Direct || dep.Direct
+		if dep.DependencyScope() == domain.ScopeRuntime || (first.IsDevelopment() && !dep.IsDevelopment()) {
+			first.Scope = dep.Scope
+		}
+		for _, parent := range dep.Parents {
+			if !slices.Contains(first.Parents, parent) {
+				first.Parents = append(first.Parents, parent)
+			}
+		}
+		if first.Constraint == first.Version && dep.Constraint != dep.Version {
+			first.Constraint, first.MinVersion, first.MaxVersion = dep.Constraint, dep.MinVersion, dep.MaxVersion
+		}
+	}
+	return deduplicated, len(dependencies) - len(deduplicated)
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+)
+
+// annotateConstraintMismatches flags dependencies whose resolved version does not satisfy a constraint
+// declared for the same package in the project (usually a stale lockfile) and returns the number of flagged entries.
+// An entry carrying a declared range is checked against that range, other entries only when they are direct:
+// nested copies resolved for other dependencies, e.g. node_modules/foo/node_modules/lodash, follow their
+// parents' constraints rather than the project's.
+func annotateConstraintMismatches(project *domain.Project) int {
+	constraintsByName := make(map[string][]string)
+	for _, dep := range project.Dependencies {
+		if constraint := declaredConstraint(dep); constraint != "" {
+			constraintsByName[dep.Name] = append(constraintsByName[dep.Name], constraint)
+		}
+	}
+
+	mismatches := 0
+	for _, dep := range project.Dependencies {
+		dep.ConstraintMismatch = false
+		if version.Parse(dep.Version) == nil {
+			continue
+		}
+		constraints := constraintsByName[dep.Name]
+		if own := declaredConstraint(dep); own != "" {
+			constraints = []string{own}
+		} else if !dep.Direct {
+			continue
+		}
+		for _, constraint := range constraints {
+			if !version.Satisfies(dep.Version, constraint) {
+				dep.ConstraintMismatch = true
+				dep.DeclaredConstraint = constraint
+				mismatches++
+				break
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// declaredConstraint returns the version range a dependency declares, or "" for exact pins and unparsable values
+func declaredConstraint(dep *domain.Dependency) string {
+	if dep.Constraint == "" || dep.Constraint == dep.Version {
+		if !isVersionRange(dep.Version) {
+			return ""
+		}
+		return dep.Version
+	}
+	if version.ParseConstraint(dep.Constraint) == nil {
+		return ""
+	}
+	return dep.Constraint
+}
+
+// isVersionRange reports whether a version field holds a range such as "^1.2.0" rather than a resolved version
+func isVersionRange(value string) bool {
+	return version.Parse(value) == nil && version.ParseConstraint(value) != nil
+}
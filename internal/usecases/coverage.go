@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"math"
+)
+
+// unknownLanguage is the project language the scanner assigns to files no language claims
+const unknownLanguage = "unknown"
+
+// matchesLanguage reports whether the project is analyzed for targetLanguage, "" matches every known language
+func matchesLanguage(project *domain.Project, targetLanguage string) bool {
+	return project.Language == targetLanguage || (targetLanguage == "" && project.Language != unknownLanguage)
+}
+
+// repositoryCoverage accounts for the dependency files of each repository: the files of the reported projects,
+// files of no known language and the files the scanner skipped. Files of other languages are out of scope,
+// unless no language is targeted.
+func (uc *AnalyzeUseCase) repositoryCoverage(
+	repositories []*domain.Repository,
+	detectedProjects []*domain.Project,
+	reportProjects []*domain.Project,
+	targetLanguage string,
+) []domain.RepositoryCoverage {
+	byURL := make(map[string]*domain.RepositoryCoverage, len(repositories))
+	for _, repo := range repositories {
+		byURL[repo.URL] = &domain.RepositoryCoverage{Repository: *repo}
+	}
+
+	for _, project := range reportProjects {
+		coverage, ok := byURL[project.Repository.URL]
+		if !ok {
+			continue
+		}
+		gaps := projectGaps(project)
+		coverage.Analyzed += len(project.DependencyFiles) - len(gaps)
+		coverage.Gaps = append(coverage.Gaps, gaps...)
+	}
+
+	for _, project := range detectedProjects {
+		coverage, ok := byURL[project.Repository.URL]
+		if !ok || project.Language != unknownLanguage || targetLanguage == unknownLanguage {
+			continue
+		}
+		for _, file := range project.DependencyFiles {
+			coverage.Gaps = append(coverage.Gaps, domain.CoverageGap{File: file.Path, Reason: domain.GapUnknownLanguage})
+		}
+	}
+
+	if reporter, ok := uc.scanner.(domain.SkippedFileReporter); ok {
+		for url, coverage := range byURL {
+			for _, file := range reporter.SkippedFiles(url) {
+				if targetLanguage != "" && file.Language != targetLanguage && file.Language != unknownLanguage {
+					continue
+				}
+				if file.Reason == domain.GapExcluded {
+					coverage.Excluded++
+					continue
+				}
+				coverage.Gaps = append(coverage.Gaps, domain.CoverageGap{File: file.File, Reason: file.Reason})
+			}
+		}
+	}
+
+	// Repositories without a single dependency file in scope say nothing about coverage
+	result := make([]domain.RepositoryCoverage, 0, len(repositories))
+	for _, repo := range repositories {
+		coverage := byURL[repo.URL]
+		if coverage.Analyzed > 0 || len(coverage.Gaps) > 0 || coverage.Excluded > 0 {
+			result = append(result, *coverage)
+		}
+	}
+	return result
+}
+
+// projectGaps lists the dependency files of a project that the parser did not understand
+func projectGaps(project *domain.Project) []domain.CoverageGap {
+	var gaps []domain.CoverageGap
+	for _, warning := range project.Warnings {
+		switch warning.Capability {
+		case domain.FileUnsupported:
+			gaps = append(gaps, domain.CoverageGap{
+				File:    warning.File,
+				Reason:  domain.GapUnsupported,
+				Message: warning.Message,
+			})
+		case domain.FileParsed:
+			gaps = append(gaps, domain.CoverageGap{
+				File:    warning.File,
+				Reason:  domain.GapUnparsed,
+				Message: warning.Message,
+			})
+		case domain.FileIgnored:
+			// Understood, the file just carries no dependencies
+		}
+	}
+	return gaps
+}
+
+// overallCoverage returns the analyzed percentage of all dependency files in scope, rounded to one decimal
+func overallCoverage(coverage []domain.RepositoryCoverage) float64 {
+	analyzed, total := 0, 0
+	for _, repository := range coverage {
+		analyzed += repository.Analyzed
+		total += repository.Analyzed + len(repository.Gaps)
+	}
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(analyzed)*1000/float64(total)) / 10
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DiscoverResponse represents the result of project discovery
+type DiscoverResponse struct {
+	Projects           []*domain.Project
+	RepositoryCount    int
+	FailedRepositories int // Repositories whose project detection failed
+}
+
+// DiscoverUseCase runs project detection only, without parsing dependencies or generating reports
+type DiscoverUseCase struct {
+	provider   domain.SourceProvider
+	scanner    domain.RepositoryScanner
+	submodules bool
+	workers    int           // Repositories scanned concurrently
+	timeout    time.Duration // Scan time of a single repository, 0 means no limit
+	logger     *zap.Logger
+	ctx        context.Context
+}
+
+// NewDiscoverUseCase creates a new discover use case with dependency injection
+func NewDiscoverUseCase(
+	ctx context.Context,
+	provider domain.SourceProvider,
+	scanner domain.RepositoryScanner,
+	logger *zap.Logger,
+) *DiscoverUseCase {
+	return &DiscoverUseCase{
+		provider: provider,
+		scanner:  scanner,
+		workers:  defaultRepositoryWorkers,
+		logger:   logger,
+		ctx:      ctx,
+	}
+}
+
+// WithSubmoduleResolution discovers projects of git submodules as repositories of their own
+func (uc *DiscoverUseCase) WithSubmoduleResolution(enabled bool) *DiscoverUseCase {
+	uc.submodules = enabled
+	return uc
+}
+
+// WithRepositoryWorkers sets the number of repositories scanned concurrently
+func (uc *DiscoverUseCase) WithRepositoryWorkers(workers int) *DiscoverUseCase {
+	if workers > 0 {
+		uc.workers = workers
+	}
+	return uc
+}
+
+// WithRepositoryTimeout bounds the scan of every repository, repositories running out of time count as failed
+func (uc *DiscoverUseCase) WithRepositoryTimeout(timeout time.Duration) *DiscoverUseCase {
+	uc.timeout = timeout
+	return uc
+}
+
+// Execute detects projects in all repositories, optionally limited to one language ("" means all).
+// Projects are sorted by repository name, path and language.
+func (uc *DiscoverUseCase) Execute(repositoryURLs []string, targetLanguage string) (*DiscoverResponse, error) {
+	repositories, err := fetchRepositories(uc.ctx, uc.provider, repositoryURLs, noProgress{})
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.submodules {
+		repositories = resolveSubmodules(uc.ctx, uc.provider, uc.logger, repositories)
+	}
+
+	detected := detectProjects(uc.ctx, uc.scanner, uc.logger, repositories, uc.workers, uc.timeout, noProgress{})
+	failed := detected.failed + detected.interrupted
+
+	var projects []*domain.Project
+	for _, project := range detected.projects {
+		if targetLanguage == "" || project.Language == targetLanguage {
+			projects = append(projects, project)
+		}
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Repository.Name != projects[j].Repository.Name {
+			return projects[i].Repository.Name < projects[j].Repository.Name
+		}
+		if projects[i].Path != projects[j].Path {
+			return projects[i].Path < projects[j].Path
+		}
+		return projects[i].Language < projects[j].Language
+	})
+
+	uc.logger.Info("Project discovery completed",
+		zap.Int("repositories", len(repositories)),
+		zap.Int("failed_repositories", failed),
+		zap.Int("projects", len(projects)))
+
+	return &DiscoverResponse{
+		Projects:           projects,
+		RepositoryCount:    len(repositories),
+		FailedRepositories: failed,
+	}, nil
+}
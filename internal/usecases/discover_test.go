@@ -0,0 +1,139 @@
+package usecases_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/usecases"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDiscoverUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/test/api"}
+	web := &domain.Repository{ID: 2, Name: "web", URL: "https://gitlab.com/test/web"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test").
+		Return([]*domain.Repository{web, api}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, api).Return([]*domain.Project{
+		{ID: "api-tools", Repository: *api, Path: "tools", Language: "python"},
+		{ID: "api-root", Repository: *api, Path: "", Language: "go"},
+	}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, web).Return([]*domain.Project{
+		{ID: "web-root", Repository: *web, Path: "", Language: "nodejs"},
+	}, nil)
+
+	useCase := usecases.NewDiscoverUseCase(context.Background(), mockGitlabClient, mockScanner, zap.NewNop())
+
+	response, err := useCase.Execute([]string{"https://gitlab.com/test"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.RepositoryCount)
+	assert.Zero(t, response.FailedRepositories)
+	projects := response.Projects
+	require.Len(t, projects, 3)
+	assert.Equal(t, "api-root", projects[0].ID)
+	assert.Equal(t, "api-tools", projects[1].ID)
+	assert.Equal(t, "web-root", projects[2].ID)
+
+	response, err = useCase.Execute([]string{"https://gitlab.com/test"}, "python")
+	require.NoError(t, err)
+	projects = response.Projects
+	require.Len(t, projects, 1)
+	assert.Equal(t, "api-tools", projects[0].ID)
+}
+
+func TestDiscoverUseCase_Execute_DetectionFailure(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockScanner := &MockRepositoryScanner{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/test/api"}
+	web := &domain.Repository{ID: 2, Name: "web", URL: "https://gitlab.com/test/web"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test").
+		Return([]*domain.Repository{web, api}, nil)
+	mockScanner.On("DetectProjects", mock.Anything, api).Return([]*domain.Project(nil), errors.New("timeout"))
+	mockScanner.On("DetectProjects", mock.Anything, web).Return([]*domain.Project{
+		{ID: "web-root", Repository: *web, Path: "", Language: "nodejs"},
+	}, nil)
+
+	useCase := usecases.NewDiscoverUseCase(context.Background(), mockGitlabClient, mockScanner, zap.NewNop())
+
+	// Failed repositories are counted, the others are still listed
+	response, err := useCase.Execute([]string{"https://gitlab.com/test"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.RepositoryCount)
+	assert.Equal(t, 1, response.FailedRepositories)
+	require.Len(t, response.Projects, 1)
+	assert.Equal(t, "web-root", response.Projects[0].ID)
+}
+
+func TestDiscoverUseCase_Execute_GitLabError(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, mock.Anything).
+		Return([]*domain.Repository{}, errors.New("unauthorized"))
+
+	useCase := usecases.NewDiscoverUseCase(
+		context.Background(), mockGitlabClient, &MockRepositoryScanner{}, zap.NewNop(),
+	)
+
+	_, err := useCase.Execute([]string{"https://gitlab.com/test"}, "")
+	require.Error(t, err)
+}
+
+// concurrencyScanner records how many repositories are scanned at the same time
+type concurrencyScanner struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (s *concurrencyScanner) DetectProjects(_ context.Context, _ *domain.Repository) ([]*domain.Project, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return nil, nil
+}
+
+func TestDiscoverUseCase_Execute_RepositoryWorkers(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	scanner := &concurrencyScanner{}
+
+	var repositories []*domain.Repository
+	for i := range 8 {
+		repositories = append(repositories, &domain.Repository{ID: i, URL: fmt.Sprintf("https://gitlab.com/test/%d", i)})
+	}
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test").Return(repositories, nil)
+
+	response, err := usecases.NewDiscoverUseCase(context.Background(), mockGitlabClient, scanner, zap.NewNop()).
+		WithRepositoryWorkers(2).
+		Execute([]string{"https://gitlab.com/test"}, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 8, response.RepositoryCount)
+	assert.LessOrEqual(t, scanner.peak, 2)
+}
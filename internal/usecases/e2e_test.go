@@ -0,0 +1,126 @@
+package usecases_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/classifier"
+	"di-matrix-cli/internal/diff"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/fakegitlab"
+	"di-matrix-cli/internal/generator"
+	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/local"
+	"di-matrix-cli/internal/parser"
+	"di-matrix-cli/internal/scanner"
+	"di-matrix-cli/internal/usecases"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAnalyzeUseCase_EndToEnd(t *testing.T) {
+	t.Parallel()
+
+	server := fakegitlab.New("token", fakegitlab.Demo()...)
+	defer server.Close()
+
+	client, err := gitlab.NewClient(server.URL(), "token", gitlab.PrivateToken, zap.NewNop())
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	htmlPath := filepath.Join(dir, "matrix.html")
+	jsonPath := filepath.Join(dir, "matrix.json")
+
+	ctx := context.Background()
+	useCase := usecases.NewAnalyzeUseCase(
+		ctx,
+		client,
+		scanner.NewScanner(client, zap.NewNop()),
+		parser.NewParser(),
+		classifier.NewClassifier(fakegitlab.DemoInternalPatterns),
+		generator.NewGenerator(htmlPath).WithJSONOutput(jsonPath),
+		zap.NewNop(),
+	).WithOutputFormats(domain.FormatHTML, domain.FormatJSON)
+
+	response, err := useCase.Execute([]string{server.RepositoryURL(fakegitlab.DemoGroup)}, "nodejs")
+	require.NoError(t, err)
+
+	assert.Equal(t, len(fakegitlab.Demo()), response.RepositoryCount)
+	assert.Zero(t, response.FailedRepositories)
+	assert.Equal(t, 3, response.TotalProjects, "billing web, storefront and admin panel")
+	assert.Equal(t, 1, response.ProjectsWithoutLockfile)
+	assert.Positive(t, response.InternalCount)
+
+	html, err := os.ReadFile(htmlPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "@acme/ui")
+	assert.Contains(t, string(html), server.RepositoryURL("acme/web/storefront"))
+
+	projects, err := diff.LoadReport(jsonPath)
+	require.NoError(t, err)
+	// Lockfiles resolve the shared internal library to drifting versions
+	versions := make(map[string][]string)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Name == "@acme/ui" {
+				versions[project.Repository.Name] = append(versions[project.Repository.Name], dep.Version)
+			}
+		}
+	}
+	assert.Contains(t, versions["billing-service"], "2.1.0")
+	assert.Contains(t, versions["storefront"], "2.3.0")
+}
+
+func TestAnalyzeUseCase_LocalEndToEnd(t *testing.T) {
+	t.Parallel()
+
+	// A monorepo checkout as CI jobs have it, analyzed without GitLab
+	workspace := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":           "ref: refs/heads/main\n",
+		"go.mod":              "module example.com/platform\n\ngo 1.22\n\nrequire go.uber.org/zap v1.27.0\n",
+		"tools/lint/go.mod":   "module example.com/platform/tools/lint\n\ngo 1.22\n\nrequire go.uber.org/zap v1.26.0\n",
+		"web/package.json":    `{"name": "web", "dependencies": {"react": "^18.2.0"}}`,
+		".git/objects/go.mod": "module example.com/not-a-project\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(workspace, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	provider := local.NewProvider(zap.NewNop())
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "matrix.json")
+
+	useCase := usecases.NewAnalyzeUseCase(
+		context.Background(),
+		provider,
+		scanner.NewScanner(provider, zap.NewNop()),
+		parser.NewParser(),
+		classifier.NewClassifier(nil),
+		generator.NewGenerator(filepath.Join(dir, "matrix.html")).WithJSONOutput(jsonPath),
+		zap.NewNop(),
+	).WithOutputFormats(domain.FormatHTML, domain.FormatJSON)
+
+	response, err := useCase.Execute([]string{workspace}, "go")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.RepositoryCount)
+	assert.Equal(t, 2, response.TotalProjects, "root module and lint tool, git metadata is not scanned")
+
+	projects, err := diff.LoadReport(jsonPath)
+	require.NoError(t, err)
+	versions := make(map[string]string)
+	for _, project := range projects {
+		assert.Equal(t, "main", project.Repository.DefaultBranch)
+		for _, dep := range project.Dependencies {
+			if dep.Name == "go.uber.org/zap" {
+				versions[project.Path] = dep.Version
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{"": "v1.27.0", "tools/lint": "v1.26.0"}, versions)
+}
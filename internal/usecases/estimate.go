@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EstimateResponse sizes an analysis from the repository trees, without downloading any file
+type EstimateResponse struct {
+	Repositories       int            `json:"repositories"`
+	FailedRepositories int            `json:"failed_repositories"` // Repositories whose tree could not be listed
+	DependencyFiles    int            `json:"dependency_files"`    // Files the analysis downloads, every language
+	AnalyzedFiles      int            `json:"analyzed_files"`      // Dependency files of the analyzed language
+	ExcludedFiles      int            `json:"excluded_files"`      // Left out by the scan limits and path filters
+	Languages          map[string]int `json:"languages"`           // Dependency files by language
+	ListingCalls       int            `json:"listing_calls"`       // API calls listing groups, projects and trees
+	ContentCalls       int            `json:"content_calls"`       // API calls downloading dependency files
+	EstimatedAPICalls  int            `json:"estimated_api_calls"` // Source API calls of the analysis, retries excluded
+}
+
+// EstimateUseCase expands groups and lists the repository trees to estimate the cost of an analysis
+type EstimateUseCase struct {
+	provider   domain.SourceProvider
+	lister     domain.DependencyFileLister
+	submodules bool
+	workers    int           // Repositories listed concurrently
+	timeout    time.Duration // Listing time of a single repository, 0 means no limit
+	logger     *zap.Logger
+	ctx        context.Context
+}
+
+// NewEstimateUseCase creates a new estimate use case with dependency injection
+func NewEstimateUseCase(
+	ctx context.Context,
+	provider domain.SourceProvider,
+	lister domain.DependencyFileLister,
+	logger *zap.Logger,
+) *EstimateUseCase {
+	return &EstimateUseCase{
+		provider: provider,
+		lister:   lister,
+		workers:  defaultRepositoryWorkers,
+		logger:   logger,
+		ctx:      ctx,
+	}
+}
+
+// WithSubmoduleResolution counts git submodules as repositories of their own
+func (uc *EstimateUseCase) WithSubmoduleResolution(enabled bool) *EstimateUseCase {
+	uc.submodules = enabled
+	return uc
+}
+
+// WithRepositoryWorkers sets the number of repositories listed concurrently
+func (uc *EstimateUseCase) WithRepositoryWorkers(workers int) *EstimateUseCase {
+	if workers > 0 {
+		uc.workers = workers
+	}
+	return uc
+}
+
+// WithRepositoryTimeout bounds the listing of every repository, repositories running out of time count as failed
+func (uc *EstimateUseCase) WithRepositoryTimeout(timeout time.Duration) *EstimateUseCase {
+	uc.timeout = timeout
+	return uc
+}
+
+// Execute lists the dependency files of every repository, AnalyzedFiles counts the ones of targetLanguage
+// ("" means every language). Listing calls are measured when the provider counts its API calls, one call per
+// entry and repository is assumed otherwise; every dependency file is one content call unless the provider
+// reports what a download costs.
+func (uc *EstimateUseCase) Execute(repositoryURLs []string, targetLanguage string) (*EstimateResponse, error) {
+	repositories, err := fetchRepositories(uc.ctx, uc.provider, repositoryURLs, noProgress{})
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.submodules {
+		repositories = resolveSubmodules(uc.ctx, uc.provider, uc.logger, repositories)
+	}
+
+	response := &EstimateResponse{Repositories: len(repositories), Languages: make(map[string]int)}
+	coster, _ := uc.provider.(domain.FileContentCoster)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, uc.workers)
+
+	for _, repo := range repositories {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(repository *domain.Repository) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			files, excluded, err := uc.listRepository(repository)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				uc.logger.Error("Failed to list dependency files of repository",
+					zap.String("repo_name", repository.Name),
+					zap.Error(err))
+				response.FailedRepositories++
+				return
+			}
+			response.DependencyFiles += len(files)
+			response.ExcludedFiles += len(excluded)
+			callsPerFile := 1
+			if coster != nil {
+				callsPerFile = coster.FileContentCalls(repository.URL)
+			}
+			response.ContentCalls += callsPerFile * len(files)
+			for _, file := range files {
+				response.Languages[file.Language]++
+				if file.Language == targetLanguage || (targetLanguage == "" && file.Language != unknownLanguage) {
+					response.AnalyzedFiles++
+				}
+			}
+		}(repo)
+	}
+	wg.Wait()
+
+	if counter, ok := uc.provider.(domain.APICallCounter); ok {
+		for _, calls := range counter.APICalls() {
+			response.ListingCalls += calls
+		}
+	} else {
+		response.ListingCalls = len(repositoryURLs) + len(repositories)
+	}
+	response.EstimatedAPICalls = response.ListingCalls + response.ContentCalls
+
+	return response, nil
+}
+
+// listRepository lists the dependency files of a repository within the repository timeout
+func (uc *EstimateUseCase) listRepository(
+	repository *domain.Repository,
+) ([]*domain.DependencyFile, []domain.SkippedFile, error) {
+	ctx := uc.ctx
+	if uc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.timeout)
+		defer cancel()
+	}
+	return uc.lister.ListDependencyFiles(ctx, repository)
+}
@@ -0,0 +1,111 @@
+package usecases_test
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/usecases"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockDependencyFileLister is a mock implementation of the DependencyFileLister interface
+type MockDependencyFileLister struct {
+	mock.Mock
+}
+
+func (m *MockDependencyFileLister) ListDependencyFiles(
+	ctx context.Context,
+	repo *domain.Repository,
+) ([]*domain.DependencyFile, []domain.SkippedFile, error) {
+	args := m.Called(ctx, repo)
+	return args.Get(0).([]*domain.DependencyFile), args.Get(1).([]domain.SkippedFile), args.Error(2)
+}
+
+func TestEstimateUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockLister := &MockDependencyFileLister{}
+
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/test/api"}
+	web := &domain.Repository{ID: 2, Name: "web", URL: "https://gitlab.com/test/web"}
+	legacy := &domain.Repository{ID: 3, Name: "legacy", URL: "https://gitlab.com/legacy"}
+
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test").
+		Return([]*domain.Repository{web, api}, nil)
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/legacy").
+		Return([]*domain.Repository{legacy}, nil)
+	mockLister.On("ListDependencyFiles", mock.Anything, api).Return([]*domain.DependencyFile{
+		{Path: "go.mod", Language: "go"},
+		{Path: "tools/requirements.txt", Language: "python"},
+	}, []domain.SkippedFile{{File: "vendor/go.mod", Reason: domain.GapExcluded}}, nil)
+	mockLister.On("ListDependencyFiles", mock.Anything, web).Return([]*domain.DependencyFile{
+		{Path: "package.json", Language: "nodejs"},
+		{Path: "backend/go.mod", Language: "go"},
+	}, []domain.SkippedFile(nil), nil)
+	mockLister.On("ListDependencyFiles", mock.Anything, legacy).
+		Return([]*domain.DependencyFile(nil), []domain.SkippedFile(nil), errors.New("timeout"))
+
+	response, err := usecases.NewEstimateUseCase(context.Background(), mockGitlabClient, mockLister, zap.NewNop()).
+		Execute([]string{"https://gitlab.com/test", "https://gitlab.com/legacy"}, "go")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, response.Repositories)
+	assert.Equal(t, 1, response.FailedRepositories)
+	assert.Equal(t, 4, response.DependencyFiles)
+	assert.Equal(t, 2, response.AnalyzedFiles)
+	assert.Equal(t, 1, response.ExcludedFiles)
+	assert.Equal(t, map[string]int{"go": 2, "nodejs": 1, "python": 1}, response.Languages)
+
+	// Without an API call counter every entry and repository is assumed to be one listing call
+	assert.Equal(t, 5, response.ListingCalls)
+	assert.Equal(t, 4, response.ContentCalls)
+	assert.Equal(t, 9, response.EstimatedAPICalls)
+	mockLister.AssertExpectations(t)
+}
+
+// costlyDownloads is a provider whose file downloads take two API requests, like GitLab without its cache
+type costlyDownloads struct {
+	*MockGitlabClient
+}
+
+func (costlyDownloads) FileContentCalls(string) int { return 2 }
+
+func TestEstimateUseCase_Execute_FileContentCost(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockLister := &MockDependencyFileLister{}
+	api := &domain.Repository{ID: 1, Name: "api", URL: "https://gitlab.com/test/api"}
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test/api").
+		Return([]*domain.Repository{api}, nil)
+	mockLister.On("ListDependencyFiles", mock.Anything, api).Return([]*domain.DependencyFile{
+		{Path: "go.mod", Language: "go"},
+		{Path: "go.sum", Language: "go"},
+	}, []domain.SkippedFile(nil), nil)
+
+	response, err := usecases.NewEstimateUseCase(context.Background(), costlyDownloads{mockGitlabClient},
+		mockLister, zap.NewNop()).Execute([]string{"https://gitlab.com/test/api"}, "go")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, response.DependencyFiles)
+	assert.Equal(t, 4, response.ContentCalls, "the provider reports two requests per download")
+	assert.Equal(t, response.ListingCalls+4, response.EstimatedAPICalls)
+}
+
+func TestEstimateUseCase_Execute_FetchError(t *testing.T) {
+	t.Parallel()
+
+	mockGitlabClient := &MockGitlabClient{}
+	mockGitlabClient.On("GetRepositoriesList", mock.Anything, "https://gitlab.com/test").
+		Return([]*domain.Repository(nil), errors.New("unauthorized"))
+
+	_, err := usecases.NewEstimateUseCase(context.Background(), mockGitlabClient, &MockDependencyFileLister{},
+		zap.NewNop()).Execute([]string{"https://gitlab.com/test"}, "go")
+	require.Error(t, err)
+}
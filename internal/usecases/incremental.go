@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/state"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Maximum number of repositories whose commit is resolved at the same time
+const commitResolveWorkers = 8
+
+// WithIncrementalState only analyzes repositories whose analyzed ref moved since the run that recorded previous,
+// the projects of unchanged repositories are taken from it. The response carries the state of this run.
+// previous must match the analyzed language and settings, an empty state analyzes every repository.
+func (uc *AnalyzeUseCase) WithIncrementalState(previous *state.State) *AnalyzeUseCase {
+	uc.previous = previous
+	return uc
+}
+
+// resolveCommits looks up the commit every repository is analyzed at, keyed by repository URL.
+// Repositories whose commit can not be resolved are left out, they are analyzed and not recorded.
+func (uc *AnalyzeUseCase) resolveCommits(repositories []*domain.Repository) map[string]string {
+	if uc.previous == nil {
+		return nil
+	}
+	resolver, ok := uc.provider.(domain.RevisionResolver)
+	if !ok {
+		uc.logger.Warn("Source provider can not resolve commits, analyzing every repository")
+		return nil
+	}
+
+	commits := make(map[string]string, len(repositories))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, commitResolveWorkers)
+	for _, repo := range repositories {
+		wg.Add(1)
+		go func(repo *domain.Repository) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			commit, err := resolver.HeadCommit(uc.ctx, repo.URL)
+			if err != nil {
+				uc.logger.Warn("Failed to resolve analyzed commit, analyzing repository",
+					zap.String("repository", repo.URL),
+					zap.Error(err))
+				return
+			}
+			mu.Lock()
+			commits[repo.URL] = commit
+			mu.Unlock()
+		}(repo)
+	}
+	wg.Wait()
+	return commits
+}
+
+// skipUnchanged drops repositories analyzed at the same commit by the previous run and returns their recorded projects
+func (uc *AnalyzeUseCase) skipUnchanged(
+	repositories []*domain.Repository,
+	commits map[string]string,
+) ([]*domain.Repository, []*domain.Project) {
+	if uc.previous == nil {
+		return repositories, nil
+	}
+
+	var pending []*domain.Repository
+	var unchanged []*domain.Project
+	for _, repo := range repositories {
+		projects, ok := uc.previous.Unchanged(repo.URL, commits[repo.URL])
+		if !ok {
+			pending = append(pending, repo)
+			continue
+		}
+		unchanged = append(unchanged, projects...)
+	}
+
+	uc.logger.Info("Skipping repositories unchanged since the previous analysis",
+		zap.Int("unchanged_repositories", len(repositories)-len(pending)),
+		zap.Int("changed_repositories", len(pending)),
+		zap.Int("reused_projects", len(unchanged)))
+
+	return pending, unchanged
+}
+
+// nextState records the commits of the unchanged repositories and of the repositories fully analyzed in this run:
+// detected, and with every project of the target language processed without error
+func (uc *AnalyzeUseCase) nextState(
+	commits map[string]string,
+	detected *detection,
+	processed *processing,
+	projects []*domain.Project,
+) *state.State {
+	if uc.previous == nil {
+		return nil
+	}
+
+	incomplete := make(map[string]bool)
+	for project := range processed.skipped {
+		incomplete[project.Repository.URL] = true
+	}
+	for project := range processed.failed {
+		incomplete[project.Repository.URL] = true
+	}
+
+	next := state.New(uc.previous.Language, uc.previous.Fingerprint)
+	for url, commit := range commits {
+		_, unchanged := uc.previous.Unchanged(url, commit)
+		if unchanged || (detected.scanned[url] && !incomplete[url]) {
+			next.Record(url, commit)
+		}
+	}
+	for _, project := range projects {
+		if recorded, ok := next.Repositories[project.Repository.URL]; ok {
+			recorded.Projects = append(recorded.Projects, project)
+		}
+	}
+	return next
+}
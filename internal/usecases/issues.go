@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"cmp"
+	"di-matrix-cli/internal/domain"
+	"slices"
+)
+
+// runIssues lists what the analysis failed on: repositories whose projects could not be detected, projects that
+// could not be processed and dependency files that could not be downloaded or parsed. Warnings are the dependency
+// files that yielded no dependencies for another reason and lockfiles replaced by their manifest.
+func runIssues(
+	detected *detection,
+	processed *processing,
+	projects []*domain.Project,
+	coverage []domain.RepositoryCoverage,
+) ([]domain.AnalysisIssue, []domain.AnalysisIssue) {
+	errs := slices.Clone(detected.errors)
+	var warnings []domain.AnalysisIssue
+
+	for project, err := range processed.failed {
+		errs = append(errs, domain.AnalysisIssue{
+			Repository: project.Repository,
+			Project:    projectDir(project),
+			Stage:      domain.StageParse,
+			Message:    err.Error(),
+		})
+	}
+
+	for _, project := range projects {
+		for _, warning := range project.Warnings {
+			issue := domain.AnalysisIssue{
+				Repository: project.Repository,
+				Project:    projectDir(project),
+				File:       warning.File,
+				Stage:      domain.StageParse,
+				Message:    warning.Message,
+			}
+			switch {
+			case warning.Fallback != "":
+				issue.Message += "; declared dependencies of " + warning.Fallback + " are used instead"
+				warnings = append(warnings, issue)
+			case warning.Capability == domain.FileParsed:
+				errs = append(errs, issue)
+			default:
+				warnings = append(warnings, issue)
+			}
+		}
+	}
+
+	for _, repository := range coverage {
+		for _, gap := range repository.Gaps {
+			if gap.Reason == domain.GapUnavailable {
+				errs = append(errs, domain.AnalysisIssue{
+					Repository: repository.Repository,
+					File:       gap.File,
+					Stage:      domain.StageScan,
+					Message:    "the file content could not be downloaded",
+				})
+			}
+		}
+	}
+
+	sortIssues(errs)
+	sortIssues(warnings)
+	return errs, warnings
+}
+
+// projectDir names the directory of a project, "." for the repository root
+func projectDir(project *domain.Project) string {
+	if project.Path == "" {
+		return "."
+	}
+	return project.Path
+}
+
+// sortIssues orders issues by repository, project and file
+func sortIssues(issues []domain.AnalysisIssue) {
+	slices.SortFunc(issues, func(a, b domain.AnalysisIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Repository.URL, b.Repository.URL),
+			cmp.Compare(a.Project, b.Project),
+			cmp.Compare(a.File, b.File),
+		)
+	})
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+	"slices"
+)
+
+// countOutdated counts dependencies behind the highest version of the package known to the analysis: the latest
+// release or a newer version used by another project, as highlighted in the matrix
+func countOutdated(projects []*domain.Project, policy version.PrereleasePolicy) int {
+	candidates := make(map[string][]string)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Version != "" {
+				candidates[dep.Name] = append(candidates[dep.Name], dep.Version)
+			}
+			if dep.LatestVersion != "" && !slices.Contains(candidates[dep.Name], dep.LatestVersion) {
+				candidates[dep.Name] = append(candidates[dep.Name], dep.LatestVersion)
+			}
+		}
+	}
+
+	count := 0
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if version.IsOutdated(dep.Version, version.MaxFor(candidates[dep.Name], dep.Version, policy)) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// countEcosystems counts dependencies per ecosystem
+func countEcosystems(projects []*domain.Project) map[string]int {
+	ecosystems := make(map[string]int)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Ecosystem != "" {
+				ecosystems[dep.Ecosystem]++
+			}
+		}
+	}
+	return ecosystems
+}
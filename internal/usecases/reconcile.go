@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+)
+
+// reconcileLockfiles merges the dependencies a manifest declares into the versions its sibling lockfile
+// resolves (package.json and package-lock.json, pyproject.toml and poetry.lock), so each package is one entry
+// carrying both the declared constraint and the resolved version. Declared packages missing from the lockfile
+// keep their manifest entry. Dependencies are returned in dependency file order with the number of merged entries.
+func (uc *AnalyzeUseCase) reconcileLockfiles(
+	project *domain.Project,
+	fileDependencies map[string][]*domain.Dependency,
+) ([]*domain.Dependency, int) {
+	merged := make(map[*domain.Dependency]bool)
+	if resolver, ok := uc.parser.(domain.FallbackResolver); ok {
+		for _, file := range project.DependencyFiles {
+			manifest := resolver.Fallback(file.Path)
+			if manifest == "" {
+				continue
+			}
+			for _, declared := range mergeDeclarations(fileDependencies[manifest], fileDependencies[file.Path]) {
+				merged[declared] = true
+			}
+		}
+	}
+
+	var dependencies []*domain.Dependency
+	for _, file := range project.DependencyFiles {
+		for _, dep := range fileDependencies[file.Path] {
+			if !merged[dep] {
+				dependencies = append(dependencies, dep)
+			}
+		}
+	}
+	return dependencies, len(merged)
+}
+
+// mergeDeclarations copies the constraint, bounds and scope of declared dependencies onto the resolved
+// entries of the same package and returns the declarations that found one. Direct entries are preferred,
+// so nested copies of a package resolved for other dependencies keep their own constraints.
+func mergeDeclarations(declared, resolved []*domain.Dependency) []*domain.Dependency {
+	byName := make(map[string][]*domain.Dependency)
+	for _, dep := range resolved {
+		byName[dep.Name] = append(byName[dep.Name], dep)
+	}
+
+	var merged []*domain.Dependency
+	for _, declaration := range declared {
+		entries := byName[declaration.Name]
+		if len(entries) == 0 {
+			continue
+		}
+
+		var direct []*domain.Dependency
+		for _, entry := range entries {
+			if entry.Direct {
+				direct = append(direct, entry)
+			}
+		}
+		if len(direct) > 0 {
+			entries = direct
+		}
+
+		for _, entry := range entries {
+			entry.Constraint = declaration.Constraint
+			entry.MinVersion = declaration.MinVersion
+			entry.MaxVersion = declaration.MaxVersion
+			entry.Scope = declaration.Scope
+			entry.Direct = true
+			if entry.Source == "" {
+				entry.Source = declaration.Source
+			}
+		}
+		merged = append(merged, declaration)
+	}
+	return merged
+}
@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fetchRepositories resolves repository and group URLs to repositories concurrently
+func fetchRepositories(
+	ctx context.Context,
+	provider domain.SourceProvider,
+	repositoryURLs []string,
+	progress domain.ProgressReporter,
+) ([]*domain.Repository, error) {
+	var repositories []*domain.Repository
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	// Channel to collect errors
+	errChan := make(chan error, len(repositoryURLs))
+
+	for _, repoURL := range repositoryURLs {
+		wg.Add(1)
+		go func(repoURL string) {
+			defer wg.Done()
+			defer progress.Advance(1)
+
+			repos, err := provider.GetRepositoriesList(ctx, repoURL)
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			mu.Lock()
+			repositories = append(repositories, repos...)
+			mu.Unlock()
+		}(repoURL)
+	}
+
+	// Wait for all goroutines to complete
+	wg.Wait()
+	close(errChan)
+
+	// Check for errors
+	for err := range errChan {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return repositories, nil
+}
+
+// detection is the outcome of project detection across repositories
+type detection struct {
+	projects    []*domain.Project
+	failed      int                    // Repositories whose detection failed
+	errors      []domain.AnalysisIssue // Why detection failed, one issue per failed repository
+	interrupted int                    // Repositories left unscanned because the analysis was cancelled
+	scanned     map[string]bool        // URLs of repositories whose detection completed
+}
+
+// detectProjects runs project detection on up to workers repositories concurrently, each bounded by timeout
+// unless it is 0. Repositories that fail detection or run out of time are logged, skipped and counted,
+// failures caused by a cancelled context count as interrupted rather than failed.
+func detectProjects(
+	ctx context.Context,
+	scanner domain.RepositoryScanner,
+	logger *zap.Logger,
+	repositories []*domain.Repository,
+	workers int,
+	timeout time.Duration,
+	progress domain.ProgressReporter,
+) *detection {
+	result := &detection{scanned: make(map[string]bool, len(repositories))}
+	var projectsMu sync.Mutex
+	var projectsWg sync.WaitGroup
+	slots := make(chan struct{}, workers)
+
+	for _, repo := range repositories {
+		projectsWg.Add(1)
+		slots <- struct{}{}
+		go func(repository *domain.Repository) {
+			defer projectsWg.Done()
+			defer func() { <-slots }()
+			defer progress.Advance(1)
+
+			projects, err := detectRepository(ctx, scanner, repository, timeout)
+
+			projectsMu.Lock()
+			defer projectsMu.Unlock()
+			switch {
+			case err != nil && ctx.Err() != nil:
+				result.interrupted++
+			case err != nil:
+				logger.Error("Failed to detect projects in repository",
+					zap.String("repo_name", repository.Name),
+					zap.Error(err))
+				result.failed++
+				result.errors = append(result.errors, domain.AnalysisIssue{
+					Repository: *repository,
+					Stage:      domain.StageScan,
+					Message:    err.Error(),
+				})
+			default:
+				result.projects = append(result.projects, projects...)
+				result.scanned[repository.URL] = true
+			}
+		}(repo)
+	}
+
+	// Wait for all project detection goroutines to complete
+	projectsWg.Wait()
+
+	return result
+}
+
+// detectRepository detects the projects of a repository within timeout, 0 means no limit
+func detectRepository(
+	ctx context.Context,
+	scanner domain.RepositoryScanner,
+	repository *domain.Repository,
+	timeout time.Duration,
+) ([]*domain.Project, error) {
+	if timeout <= 0 {
+		return scanner.DetectProjects(ctx, repository)
+	}
+
+	repositoryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	projects, err := scanner.DetectProjects(repositoryCtx, repository)
+	if err != nil && ctx.Err() == nil && errors.Is(repositoryCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("scan exceeded the repository timeout of %s: %w", timeout, err)
+	}
+	return projects, err
+}
+
+// noProgress drops progress reports when no reporter is configured
+type noProgress struct{}
+
+func (noProgress) StartStage(string, int) {}
+
+func (noProgress) Advance(int) {}
+
+func (noProgress) Finish() {}
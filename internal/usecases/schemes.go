@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"di-matrix-cli/internal/version"
+)
+
+// annotateVersioningSchemes records how each package numbers its releases (semver, calver or other).
+// Entries without a concrete version, e.g. declared ranges, take the scheme seen for the package elsewhere.
+func annotateVersioningSchemes(projects []*domain.Project) {
+	schemes := make(map[string]version.Scheme)
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			scheme := version.DetectScheme(dep.Version)
+			if scheme == "" {
+				continue
+			}
+			// Non-semver evidence wins, a package seen as "2.0" and "2024.1" is calendar versioned
+			if existing, seen := schemes[dep.Name]; !seen || existing == version.SchemeSemver {
+				schemes[dep.Name] = scheme
+			}
+		}
+	}
+
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			dep.VersioningScheme = string(schemes[dep.Name])
+		}
+	}
+}
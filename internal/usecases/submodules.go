@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"di-matrix-cli/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// WithSubmoduleResolution analyzes git submodules as repositories of their own
+func (uc *AnalyzeUseCase) WithSubmoduleResolution(enabled bool) *AnalyzeUseCase {
+	uc.submodules = enabled
+	return uc
+}
+
+// resolveSubmodules appends the repositories referenced as submodules, following nested submodules.
+// Repositories already in the list are not added twice.
+func resolveSubmodules(
+	ctx context.Context,
+	provider domain.SourceProvider,
+	logger *zap.Logger,
+	repositories []*domain.Repository,
+) []*domain.Repository {
+	resolver, ok := provider.(domain.SubmoduleResolver)
+	if !ok {
+		logger.Warn("Source provider cannot list submodules, skipping submodule resolution")
+		return repositories
+	}
+
+	seen := make(map[int]bool, len(repositories))
+	for _, repo := range repositories {
+		seen[repo.ID] = true
+	}
+
+	for i := 0; i < len(repositories); i++ {
+		submodules, err := resolver.GetSubmodules(ctx, repositories[i].URL)
+		if err != nil {
+			logger.Warn("Failed to list submodules",
+				zap.String("repo_name", repositories[i].Name),
+				zap.Error(err))
+			continue
+		}
+
+		for _, submodule := range submodules {
+			repos, err := provider.GetRepositoriesList(ctx, submodule.URL)
+			if err != nil {
+				logger.Warn("Failed to resolve submodule repository",
+					zap.String("repo_name", repositories[i].Name),
+					zap.String("submodule_path", submodule.Path),
+					zap.String("submodule_url", submodule.URL),
+					zap.Error(err))
+				continue
+			}
+
+			for _, repo := range repos {
+				if seen[repo.ID] {
+					continue
+				}
+				seen[repo.ID] = true
+				repositories = append(repositories, repo)
+				logger.Info("Resolved submodule repository",
+					zap.String("parent", repositories[i].Name),
+					zap.String("submodule_path", submodule.Path),
+					zap.String("repo_name", repo.Name))
+			}
+		}
+	}
+
+	return repositories
+}
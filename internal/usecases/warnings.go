@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// fileWarnings lists the project's dependency files that produced no dependencies:
+// unsupported files, files that failed to parse, and intentionally ignored files
+// when nothing else in the project was parsed
+func (uc *AnalyzeUseCase) fileWarnings(project *domain.Project, parseErrors map[string]error) []domain.FileWarning {
+	reporter, ok := uc.parser.(domain.CapabilityReporter)
+	fallbacks := uc.fallbacks(project, parseErrors)
+
+	var warnings []domain.FileWarning
+	var ignored []domain.FileWarning
+	hasParsedFile := false
+
+	for _, file := range project.DependencyFiles {
+		capability, reason := domain.FileParsed, ""
+		if ok {
+			capability, reason = reporter.Capability(file.Path)
+		}
+
+		switch {
+		case capability == domain.FileUnsupported:
+			warnings = append(warnings, domain.FileWarning{File: file.Path, Capability: capability, Message: reason})
+		case capability == domain.FileIgnored:
+			ignored = append(ignored, domain.FileWarning{File: file.Path, Capability: capability, Message: reason})
+		case parseErrors[file.Path] != nil:
+			warnings = append(warnings, domain.FileWarning{
+				File:       file.Path,
+				Capability: capability,
+				Message:    parseErrors[file.Path].Error(),
+				Fallback:   fallbacks[file.Path],
+			})
+		default:
+			hasParsedFile = true
+		}
+	}
+
+	if !hasParsedFile {
+		warnings = append(warnings, ignored...)
+	}
+
+	for _, warning := range warnings {
+		uc.logger.Warn("Dependency file yielded no dependencies",
+			zap.String("project_id", project.ID),
+			zap.String("file", warning.File),
+			zap.String("capability", string(warning.Capability)),
+			zap.String("reason", warning.Message),
+			zap.String("fallback", warning.Fallback))
+	}
+
+	return warnings
+}
+
+// fallbacks maps each lockfile that failed to parse to its sibling manifest when that manifest was parsed,
+// so the project keeps its declared dependencies instead of losing them
+func (uc *AnalyzeUseCase) fallbacks(project *domain.Project, parseErrors map[string]error) map[string]string {
+	resolver, ok := uc.parser.(domain.FallbackResolver)
+	if !ok || len(parseErrors) == 0 {
+		return nil
+	}
+
+	parsed := make(map[string]bool, len(project.DependencyFiles))
+	for _, file := range project.DependencyFiles {
+		parsed[file.Path] = parseErrors[file.Path] == nil
+	}
+
+	fallbacks := make(map[string]string)
+	for filePath := range parseErrors {
+		if manifest := resolver.Fallback(filePath); manifest != "" && parsed[manifest] {
+			fallbacks[filePath] = manifest
+		}
+	}
+	return fallbacks
+}
+
+// countFallbacks counts lockfiles whose dependencies were replaced by the sibling manifest's declarations
+func countFallbacks(projects []*domain.Project) int {
+	count := 0
+	for _, project := range projects {
+		for _, warning := range project.Warnings {
+			if warning.Fallback != "" {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func countWarnings(projects []*domain.Project) int {
+	count := 0
+	for _, project := range projects {
+		count += len(project.Warnings)
+	}
+	return count
+}
+
+// countStale counts dependencies that offline mode could not enrich
+func countStale(projects []*domain.Project) int {
+	count := 0
+	for _, project := range projects {
+		for _, dep := range project.Dependencies {
+			if dep.Stale {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"di-matrix-cli/internal/domain"
+	"path/filepath"
+	"strings"
+)
+
+// resolveGoWorkspaceModules points requirements on modules of the same go.work workspace at the module directory,
+// which is what the go command builds, so a module used by the workspace and required by a member's go.mod is not
+// reported as a second, external version of it. Requirements already replaced in go.mod keep their replacement.
+// It returns the number of resolved requirements.
+func resolveGoWorkspaceModules(projects []*domain.Project) int {
+	byID := make(map[string]*domain.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
+	}
+
+	resolved := 0
+	for _, root := range projects {
+		if root.Language != "go" || len(root.Members) == 0 {
+			continue
+		}
+
+		workspace := []*domain.Project{root}
+		for _, id := range root.Members {
+			if member, ok := byID[id]; ok {
+				workspace = append(workspace, member)
+			}
+		}
+		modules := make(map[string]*domain.Project)
+		for _, project := range workspace {
+			if project.ModuleName != "" {
+				modules[project.ModuleName] = project
+			}
+		}
+
+		for _, project := range workspace {
+			for _, dep := range project.Dependencies {
+				module, ok := modules[dep.Name]
+				if !ok || module == project || dep.ReplacedBy != "" {
+					continue
+				}
+				dep.ReplacedBy = moduleDirectory(project.Path, module.Path)
+				resolved++
+			}
+		}
+	}
+	return resolved
+}
+
+// moduleDirectory returns the directory of a workspace module relative to the requiring project,
+// written like the target of a go.mod replace directive ("../auth", "./tools")
+func moduleDirectory(from, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash("./"+from), filepath.FromSlash("./"+to))
+	if err != nil {
+		return to
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "..") {
+		rel = "./" + rel
+	}
+	return rel
+}
@@ -0,0 +1,216 @@
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// constraintOperators lists the comparison operators of the supported constraint syntaxes, longest first
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var constraintOperators = []string{"===", "==", "~=", "~>", ">=", "<=", "!=", "^", "~", ">", "<", "="}
+
+// operatorSpacing matches the blanks between an operator and its version, as in ">= 2.31" or "~> 2.1"
+//
+//nolint:gochecknoglobals // compiled once
+var operatorSpacing = regexp.MustCompile(`([<>=!~^]+)\s+`)
+
+// compatibleRelease matches Python compatible release clauses such as "~=1.21"
+//
+//nolint:gochecknoglobals // compiled once
+var compatibleRelease = regexp.MustCompile(`~=\s*([^\s,]+)`)
+
+// Bounds returns the lowest version a constraint allows and its upper bound, "" for an open side.
+// The upper bound of a range is the first version it excludes ("^1.2.3" allows 1.2.3 up to 2.0.0)
+// or the version of a "<=" comparator. A pinned version is both bounds, so a constraint pins
+// a single version exactly when both bounds are set and equal.
+//
+// Supported syntaxes are npm and Cargo ("^1.2.3", "~1.2", "1.2.x", ">=1.2.3 <2.0.0 || 3.0.0"),
+// Python ("~=1.21", ">=2,<3", "==1.2.*"), RubyGems ("~> 2.1"), Gradle ("1.+") and
+// Maven and NuGet intervals ("[1.0,2.0)", "(,1.5]", "[1.0]").
+func Bounds(constraint string) (string, string) {
+	constraint = strings.TrimSpace(constraint)
+	if strings.HasPrefix(constraint, "[") || strings.HasPrefix(constraint, "(") {
+		return intervalBounds(constraint)
+	}
+
+	alternatives := strings.Split(constraint, "||")
+	lower, upper := comparatorSetBounds(alternatives[0])
+	for _, alternative := range alternatives[1:] {
+		altLower, altUpper := comparatorSetBounds(alternative)
+		if lower != "" && (altLower == "" || Compare(altLower, lower) < 0) {
+			lower = altLower
+		}
+		if upper != "" && (altUpper == "" || Compare(altUpper, upper) > 0) {
+			upper = altUpper
+		}
+	}
+	return lower, upper
+}
+
+// intervalBounds returns the bounds of Maven and NuGet version intervals, spanning all of them
+// when several are listed ("[1.0,2.0),[3.0,4.0)")
+func intervalBounds(constraint string) (string, string) {
+	inner := strings.Trim(constraint, "[]()")
+	if !strings.Contains(inner, ",") {
+		inner = strings.TrimSpace(inner)
+		return inner, inner
+	}
+	lower := strings.TrimSpace(inner[:strings.Index(inner, ",")])
+	upper := strings.TrimSpace(inner[strings.LastIndex(inner, ",")+1:])
+	return lower, upper
+}
+
+// comparatorSetBounds returns the bounds of comparators that all apply, such as ">=2, <3" or ">=1.2.3 <2.0.0"
+func comparatorSetBounds(set string) (string, string) {
+	fields := strings.Fields(strings.ReplaceAll(operatorSpacing.ReplaceAllString(set, "$1"), ",", " "))
+
+	// npm hyphen ranges include both ends
+	if len(fields) == 3 && fields[1] == "-" {
+		return fields[0], fields[2]
+	}
+
+	var lower, upper string
+	for _, field := range fields {
+		fieldLower, fieldUpper := comparatorBounds(field)
+		if fieldLower != "" && (lower == "" || Compare(fieldLower, lower) > 0) {
+			lower = fieldLower
+		}
+		if fieldUpper != "" && (upper == "" || Compare(fieldUpper, upper) < 0) {
+			upper = fieldUpper
+		}
+	}
+	return lower, upper
+}
+
+// comparatorBounds returns the bounds of a single comparator such as "^1.2.3", "<2" or "1.2.*"
+func comparatorBounds(comparator string) (string, string) {
+	operator := ""
+	for _, candidate := range constraintOperators {
+		if strings.HasPrefix(comparator, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	version := strings.TrimPrefix(comparator, operator)
+
+	if prefix, ok := wildcardPrefix(version); ok {
+		if prefix == "" || (operator != "" && operator != "=" && operator != "==") {
+			return "", ""
+		}
+		return prefix, bumpSegment(prefix, len(numericSegments(prefix))-1)
+	}
+	if DetectScheme(version) == "" {
+		return "", ""
+	}
+
+	switch operator {
+	case "", "=", "==", "===":
+		return version, version
+	case "^":
+		return version, caretUpperBound(version)
+	case "~":
+		return version, tildeUpperBound(version)
+	case "~>", "~=":
+		return version, compatibleUpperBound(version)
+	case ">=", ">":
+		return version, ""
+	case "<", "<=":
+		return "", version
+	default:
+		// "!=" only excludes a single version
+		return "", ""
+	}
+}
+
+// compatibleUpperBound returns the first version outside a Python compatible release or RubyGems
+// pessimistic constraint on a version: "~=1.21" and "~> 1.21" stay below 2.0.0, "~=1.4.5" below 1.5.0
+func compatibleUpperBound(version string) string {
+	return bumpSegment(version, max(len(numericSegments(version))-2, 0))
+}
+
+// caretUpperBound returns the first version outside a caret range, which allows changes that do not
+// modify the left-most non-zero segment: "^1.2.3" stays below 2.0.0, "^0.2.3" below 0.3.0
+func caretUpperBound(version string) string {
+	segments := numericSegments(version)
+	significant := min(len(segments), 3) - 1
+	for i := range significant {
+		if segments[i] != 0 {
+			significant = i
+			break
+		}
+	}
+	return bumpSegment(version, significant)
+}
+
+// tildeUpperBound returns the first version outside a tilde range, which allows patch changes when
+// a minor version is given and minor changes otherwise: "~1.2.3" stays below 1.3.0, "~1" below 2.0.0
+func tildeUpperBound(version string) string {
+	if len(numericSegments(version)) >= 2 {
+		return bumpSegment(version, 1)
+	}
+	return bumpSegment(version, 0)
+}
+
+// bumpSegment increments the numeric segment at index and zeroes the following ones, keeping at least
+// three segments and the "v" prefix: bumping segment 1 of "v1.2.3-rc.1" gives "v1.3.0"
+func bumpSegment(version string, index int) string {
+	segments := numericSegments(version)
+	if index < 0 || index >= len(segments) {
+		return ""
+	}
+
+	bumped := make([]string, max(index+1, 3))
+	for i := range bumped {
+		switch {
+		case i < index:
+			bumped[i] = strconv.Itoa(segments[i])
+		case i == index:
+			bumped[i] = strconv.Itoa(segments[i] + 1)
+		default:
+			bumped[i] = "0"
+		}
+	}
+
+	prefix := ""
+	if strings.HasPrefix(strings.TrimSpace(version), "v") {
+		prefix = "v"
+	}
+	return prefix + strings.Join(bumped, ".")
+}
+
+// numericSegments returns the leading numeric segments of a version, ignoring pre-release and build suffixes
+func numericSegments(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if end := strings.IndexAny(version, "-+"); end >= 0 {
+		version = version[:end]
+	}
+
+	var segments []int
+	for _, segment := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(segment)
+		if err != nil {
+			break
+		}
+		segments = append(segments, number)
+	}
+	return segments
+}
+
+// wildcardPrefix strips wildcard segments such as ".*", ".x" or ".+" from a version and reports whether
+// there were any; a bare wildcard leaves an empty prefix
+func wildcardPrefix(version string) (string, bool) {
+	prefix, found := version, false
+	for {
+		switch {
+		case prefix == "*" || prefix == "x" || prefix == "X" || prefix == "+":
+			return "", true
+		case strings.HasSuffix(prefix, ".*") || strings.HasSuffix(prefix, ".x") ||
+			strings.HasSuffix(prefix, ".X") || strings.HasSuffix(prefix, ".+"):
+			prefix, found = prefix[:len(prefix)-2], true
+		default:
+			return prefix, found
+		}
+	}
+}
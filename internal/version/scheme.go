@@ -0,0 +1,189 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scheme names how a package numbers its releases
+type Scheme string
+
+const (
+	// SchemeSemver covers semantic versions and their lenient forms, e.g. "1.9.1", "v2.0.0-rc.1", "1.2"
+	SchemeSemver Scheme = "semver"
+	// SchemeCalVer covers calendar and date versions, e.g. "2024.1", "2023.10.2", "2023-10-17", "20231017"
+	SchemeCalVer Scheme = "calver"
+	// SchemeOther covers numeric versions outside semver, e.g. "1.2.3.4", "5.4.2.Final", "2.0.0.post1"
+	SchemeOther Scheme = "other"
+)
+
+// Calendar years accepted as the leading segment of a calendar version
+const (
+	minCalVerYear = 1990
+	maxCalVerYear = 2099
+)
+
+// qualifierRanks orders textual version segments: pre-releases sort below releases, releases below
+// unknown suffixes such as Python post-releases or Maven service packs
+var qualifierRanks = map[string]int{ //nolint:gochecknoglobals // lookup table
+	"dev": -6, "snapshot": -6,
+	"alpha": -5, "a": -5,
+	"beta": -4, "b": -4,
+	"milestone": -3, "m": -3,
+	"rc": -2, "cr": -2, "c": -2, "pre": -2, "preview": -2,
+	"": 0, "final": 0, "ga": 0, "release": 0,
+}
+
+const (
+	// unknownQualifierRank sorts unknown suffixes such as "post" or "sp" above releases
+	unknownQualifierRank = 1
+	// numericRank sorts numeric segments above every qualifier
+	numericRank = 10
+)
+
+// DetectScheme reports the versioning scheme of a version, or "" for values that are not versions
+// (ranges, tags such as "latest", git references)
+func DetectScheme(version string) Scheme {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" || !unicode.IsDigit(rune(version[0])) {
+		return ""
+	}
+
+	if isCalVer(version) {
+		return SchemeCalVer
+	}
+	if Parse(version) != nil {
+		return SchemeSemver
+	}
+	return SchemeOther
+}
+
+// isCalVer reports whether the leading number of a version is a year, optionally followed by month and day
+func isCalVer(version string) bool {
+	end := strings.IndexFunc(version, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(version)
+	}
+	lead := version[:end]
+
+	switch len(lead) {
+	case 4: // "2024.1", "2023-10-17"
+		return isYear(lead)
+	case 6, 8: // "202310", "20231017"
+		month, err := strconv.Atoi(lead[4:6])
+		return err == nil && isYear(lead[:4]) && month >= 1 && month <= 12
+	default:
+		return false
+	}
+}
+
+func isYear(value string) bool {
+	year, err := strconv.Atoi(value)
+	return err == nil && year >= minCalVerYear && year <= maxCalVerYear
+}
+
+// compareNatural compares versions segment by segment, numbers numerically and
+// textual qualifiers by their release stage ("1.0rc1" < "1.0" = "1.0.Final" < "1.0.post1" < "1.0.1")
+func compareNatural(v1, v2 string) int {
+	tokens1 := tokenize(v1)
+	tokens2 := tokenize(v2)
+
+	for i := 0; i < len(tokens1) || i < len(tokens2); i++ {
+		var token1, token2 string
+		if i < len(tokens1) {
+			token1 = tokens1[i]
+		}
+		if i < len(tokens2) {
+			token2 = tokens2[i]
+		}
+		if result := compareTokens(token1, token2); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// tokenize splits a version into runs of digits and letters, dropping separators
+func tokenize(version string) []string {
+	version = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(version), "v"))
+
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range version {
+		switch {
+		case !unicode.IsDigit(r) && !unicode.IsLetter(r):
+			flush()
+		case current.Len() > 0 && unicode.IsDigit(r) != isDigits(current.String()):
+			flush()
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func compareTokens(token1, token2 string) int {
+	rank1, rank2 := tokenRank(token1), tokenRank(token2)
+	switch {
+	case rank1 != rank2:
+		return compareInts(rank1, rank2)
+	case rank1 == numericRank:
+		return compareNumbers(token1, token2)
+	case rank1 != unknownQualifierRank:
+		// Qualifiers of one stage are equivalent, "5.4.2.Final" is "5.4.2" and "1.0b1" is "1.0beta1"
+		return 0
+	default:
+		return strings.Compare(token1, token2)
+	}
+}
+
+func tokenRank(token string) int {
+	if token != "" && isDigits(token) {
+		return numericRank
+	}
+	if rank, ok := qualifierRanks[token]; ok {
+		return rank
+	}
+	return unknownQualifierRank
+}
+
+// compareNumbers compares digit strings of any length
+func compareNumbers(number1, number2 string) int {
+	number1 = strings.TrimLeft(number1, "0")
+	number2 = strings.TrimLeft(number2, "0")
+	if len(number1) != len(number2) {
+		return compareInts(len(number1), len(number2))
+	}
+	return strings.Compare(number1, number2)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return value != ""
+}
@@ -0,0 +1,180 @@
+package version
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Parse parses a version string leniently (e.g. "1.2", "v1", "1.2.3-beta.1+build.5").
+// It returns nil for empty strings, ranges and other non-version values.
+func Parse(version string) *semver.Version {
+	if version == "" {
+		return nil
+	}
+
+	parsed, err := semver.NewVersion(strings.TrimSpace(version))
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+// Canonical returns the normalized spelling of a version so that equivalent versions compare equal as strings:
+// "v1.2" and "1.2.0" both become "1.2.0". Values that are not semantic versions are only trimmed.
+func Canonical(version string) string {
+	version = strings.TrimSpace(version)
+	if DetectScheme(version) == SchemeSemver {
+		return Parse(version).String()
+	}
+	return version
+}
+
+// ParseConstraint parses a version constraint such as "^1.2.0", "~> 2.1", ">=1.0, <2.0", "==2.31.0" or "~=1.21".
+// It returns nil when the value is not a valid constraint.
+func ParseConstraint(constraint string) *semver.Constraints {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return nil
+	}
+
+	// Python pins use "==", the library expects "="
+	constraint = strings.ReplaceAll(constraint, "==", "=")
+	// and compatible releases "~=1.21" are spelled out as ">=1.21, <2.0.0"
+	constraint = compatibleRelease.ReplaceAllStringFunc(constraint, func(clause string) string {
+		version := compatibleRelease.FindStringSubmatch(clause)[1]
+		return ">=" + version + ", <" + compatibleUpperBound(version)
+	})
+
+	parsed, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+// Compare compares two versions and returns:
+// -1 if v1 < v2
+// 0 if v1 == v2
+// 1 if v1 > v2
+// Semantic versions use semver precedence, calendar and other numeric versions compare segment by segment.
+// Values that are not versions sort before versions; two such values compare as strings.
+func Compare(v1, v2 string) int {
+	scheme1 := DetectScheme(v1)
+	scheme2 := DetectScheme(v2)
+
+	switch {
+	case scheme1 == SchemeSemver && scheme2 == SchemeSemver:
+		return Parse(v1).Compare(Parse(v2))
+	case scheme1 != "" && scheme2 != "":
+		return compareNatural(v1, v2)
+	case scheme1 != "":
+		return 1
+	case scheme2 != "":
+		return -1
+	default:
+		return strings.Compare(v1, v2)
+	}
+}
+
+// Max finds the maximum version among all versions of a dependency
+func Max(versions []string) string {
+	if len(versions) == 0 {
+		return ""
+	}
+
+	maxVersion := versions[0]
+	for _, version := range versions[1:] {
+		if Compare(version, maxVersion) > 0 {
+			maxVersion = version
+		}
+	}
+
+	return maxVersion
+}
+
+// PrereleasePolicy controls whether pre-release versions may count as the maximum version of a dependency
+type PrereleasePolicy string
+
+const (
+	// PrereleaseNever compares against the highest stable version, unless no stable version exists
+	PrereleaseNever PrereleasePolicy = "never"
+	// PrereleaseInUse lets pre-releases count only for projects that are on a pre-release themselves
+	PrereleaseInUse PrereleasePolicy = "in_use"
+	// PrereleaseAlways orders pre-releases by plain semver precedence
+	PrereleaseAlways PrereleasePolicy = "always"
+)
+
+// IsExact reports whether a version is a single release rather than a range or a wildcard
+func IsExact(version string) bool {
+	return version != "" && !strings.ContainsAny(version, "^~<>=*,| []()") && !strings.HasSuffix(version, ".x")
+}
+
+// IsPrerelease reports whether a version carries a pre-release suffix, e.g. "2.0.0-rc.1" or "2.0.0b1"
+func IsPrerelease(version string) bool {
+	switch DetectScheme(version) {
+	case SchemeSemver:
+		return Parse(version).Prerelease() != ""
+	case SchemeCalVer, SchemeOther:
+		for _, token := range tokenize(version) {
+			if tokenRank(token) < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MaxFor finds the maximum version a project currently on current is compared against under the policy.
+// An empty policy behaves like PrereleaseAlways.
+func MaxFor(versions []string, current string, policy PrereleasePolicy) string {
+	switch policy {
+	case PrereleaseNever:
+	case PrereleaseInUse:
+		if IsPrerelease(current) {
+			return Max(versions)
+		}
+	default:
+		return Max(versions)
+	}
+
+	stable := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if !IsPrerelease(version) {
+			stable = append(stable, version)
+		}
+	}
+	if len(stable) == 0 {
+		return Max(versions)
+	}
+	return Max(stable)
+}
+
+// Satisfies reports whether a version satisfies a constraint.
+// It returns false when either value cannot be parsed.
+func Satisfies(version, constraint string) bool {
+	parsed := Parse(version)
+	constraints := ParseConstraint(constraint)
+	if parsed == nil || constraints == nil {
+		return false
+	}
+	return constraints.Check(parsed)
+}
+
+// IsOutdated reports whether current is behind latest.
+// When current is a range rather than a version, it is outdated only if the range excludes latest.
+func IsOutdated(current, latest string) bool {
+	if current == "" || DetectScheme(latest) == "" {
+		return false
+	}
+
+	if DetectScheme(current) != "" {
+		return Compare(current, latest) < 0
+	}
+
+	if ParseConstraint(current) != nil {
+		return !Satisfies(latest, current)
+	}
+
+	return false
+}
@@ -0,0 +1,219 @@
+package version_test
+
+import (
+	"di-matrix-cli/internal/version"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	info := version.Parse("v1.2.3-beta.1+build.5")
+	require.NotNil(t, info)
+	assert.Equal(t, uint64(1), info.Major())
+	assert.Equal(t, uint64(2), info.Minor())
+	assert.Equal(t, uint64(3), info.Patch())
+	assert.Equal(t, "beta.1", info.Prerelease())
+	assert.Equal(t, "build.5", info.Metadata())
+
+	twoSegments := version.Parse("1.2")
+	require.NotNil(t, twoSegments)
+	assert.Equal(t, "1.2.0", twoSegments.String())
+
+	assert.Nil(t, version.Parse(""))
+	assert.Nil(t, version.Parse("^1.2.0"))
+	assert.Nil(t, version.Parse("latest"))
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		v1, v2   string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.4", -1},
+		{"2.0.0", "1.9.9", 1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.10", "1.9.5", 1},
+		{"1.2", "1.2.0", 0},
+		{"^1.2.0", "1.0.0", -1},
+		{"latest", "next", -1},
+		{"2024.1", "2023.3", 1},
+		{"2024.2", "2024.10", -1},
+		{"2023-10-17", "2023-09-30", 1},
+		{"20231017", "20240101", -1},
+		{"1.2.3.10", "1.2.3.9", 1},
+		{"5.4.2.Final", "5.4.2", 0},
+		{"2.0.0.post1", "2.0.0", 1},
+		{"2.0.0rc1", "2.0.0", -1},
+		{"2.0.0b1", "2.0.0rc1", -1},
+		{"1.2.3.4", "1.2.3", 1},
+		{"1.0b1", "1.0.beta.1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.v1+"_"+tt.v2, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, version.Compare(tt.v1, tt.v2))
+		})
+	}
+}
+
+func TestMax(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, version.Max(nil))
+	assert.Equal(t, "1.10.0", version.Max([]string{"1.2.0", "1.10.0", "1.9.5"}))
+	assert.Equal(t, "1.9", version.Max([]string{"^2.0.0", "1.9", "1.8.0"}), "Ranges never win over versions")
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1.2.3", version.Canonical("v1.2.3"))
+	assert.Equal(t, "1.2.0", version.Canonical("1.2"))
+	assert.Equal(t, "2.0.0-rc.1", version.Canonical(" v2.0.0-rc.1 "))
+	assert.Equal(t, "2024.1", version.Canonical("2024.1"), "Calendar versions keep their spelling")
+	assert.Equal(t, "^1.2.0", version.Canonical("^1.2.0"))
+}
+
+func TestDetectScheme(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version  string
+		expected version.Scheme
+	}{
+		{"1.9.1", version.SchemeSemver},
+		{"v2.0.0-rc.1", version.SchemeSemver},
+		{"1.2", version.SchemeSemver},
+		{"2024.1", version.SchemeCalVer},
+		{"2023.10.2", version.SchemeCalVer},
+		{"2023-10-17", version.SchemeCalVer},
+		{"20231017", version.SchemeCalVer},
+		{"1.2.3.4", version.SchemeOther},
+		{"5.4.2.Final", version.SchemeOther},
+		{"2.0.0.post1", version.SchemeOther},
+		{"^1.2.0", ""},
+		{"latest", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, version.DetectScheme(tt.version), tt.version)
+	}
+}
+
+func TestIsOutdated_NonSemver(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, version.IsOutdated("2023.3", "2024.1"))
+	assert.False(t, version.IsOutdated("2024.10", "2024.2"), "Calendar segments compare numerically")
+	assert.True(t, version.IsOutdated("5.4.1.Final", "5.4.2.Final"))
+	assert.False(t, version.IsPrerelease("2023-10-17"), "Date versions are not pre-releases")
+	assert.True(t, version.IsPrerelease("2.0.0b1"))
+}
+
+func TestIsExact(t *testing.T) {
+	t.Parallel()
+
+	for _, exact := range []string{"1.2.3", "v0.14.0", "2.0.0-rc.1", "2023.3"} {
+		assert.True(t, version.IsExact(exact), exact)
+	}
+	for _, notExact := range []string{"", "^4.18.0", "~> 2.1", ">=1.0, <2.0", "1.x", "*", "[1.0,2.0)", "1 || 2"} {
+		assert.False(t, version.IsExact(notExact), notExact)
+	}
+}
+
+func TestMaxFor(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.9.0", "2.0.0-rc.1", "1.10.0"}
+
+	assert.Equal(t, "2.0.0-rc.1", version.MaxFor(versions, "1.9.0", version.PrereleaseAlways))
+	assert.Equal(t, "2.0.0-rc.1", version.MaxFor(versions, "1.9.0", ""), "Empty policy orders by semver")
+	assert.Equal(t, "1.10.0", version.MaxFor(versions, "2.0.0-rc.1", version.PrereleaseNever))
+	assert.Equal(t, "1.10.0", version.MaxFor(versions, "1.9.0", version.PrereleaseInUse))
+	assert.Equal(t, "2.0.0-rc.1", version.MaxFor(versions, "2.0.0-rc.1", version.PrereleaseInUse))
+	assert.Equal(t, "3.0.0-beta", version.MaxFor([]string{"3.0.0-alpha", "3.0.0-beta"}, "", version.PrereleaseNever),
+		"Pre-releases count when no stable version exists")
+}
+
+func TestSatisfies(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, version.Satisfies("4.17.21", "^4.17.0"))
+	assert.False(t, version.Satisfies("5.0.0", "^4.17.0"))
+	assert.True(t, version.Satisfies("2.31.0", "==2.31.0"))
+	assert.True(t, version.Satisfies("1.5.0", ">=1.0, <2.0"))
+	assert.False(t, version.Satisfies("1.5.0", "not a range"))
+}
+
+func TestIsOutdated(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, version.IsOutdated("1.2.0", "1.3.0"))
+	assert.False(t, version.IsOutdated("1.3.0", "1.3.0"))
+	assert.False(t, version.IsOutdated("^1.2.0", "1.3.0"), "Range already allows the latest version")
+	assert.True(t, version.IsOutdated("~1.2.0", "1.3.0"))
+	assert.False(t, version.IsOutdated("", "1.3.0"))
+	assert.False(t, version.IsOutdated("1.2.0", "latest"))
+}
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		lower      string
+		upper      string
+	}{
+		{"1.2.3", "1.2.3", "1.2.3"},
+		{"v1.9.1", "v1.9.1", "v1.9.1"},
+		{"==2.31.0", "2.31.0", "2.31.0"},
+		{"^1.2.3", "1.2.3", "2.0.0"},
+		{"^0.2.3", "0.2.3", "0.3.0"},
+		{"^0.0.3", "0.0.3", "0.0.4"},
+		{"~1.2.3", "1.2.3", "1.3.0"},
+		{"~1", "1", "2.0.0"},
+		{"~> 2.1", "2.1", "3.0.0"},
+		{"~=1.4.5", "1.4.5", "1.5.0"},
+		{">=2,<3", "2", "3"},
+		{">= 1.2.3 <= 1.9.0", "1.2.3", "1.9.0"},
+		{">=1.2", "1.2", ""},
+		{"<2.0", "", "2.0"},
+		{"1.2.x", "1.2", "1.3.0"},
+		{"1.+", "1", "2.0.0"},
+		{"1.2.3 - 2.3.4", "1.2.3", "2.3.4"},
+		{"^1.2.0 || ^2.0.0", "1.2.0", "3.0.0"},
+		{"^1.2.0 || >=3", "1.2.0", ""},
+		{"[1.0,2.0)", "1.0", "2.0"},
+		{"(,1.5]", "", "1.5"},
+		{"[1.0]", "1.0", "1.0"},
+		{"!=1.2.3", "", ""},
+		{"*", "", ""},
+		{"latest", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		lower, upper := version.Bounds(tt.constraint)
+		assert.Equal(t, tt.lower, lower, "lower bound of %q", tt.constraint)
+		assert.Equal(t, tt.upper, upper, "upper bound of %q", tt.constraint)
+	}
+}
+
+func TestSatisfies_CompatibleRelease(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, version.Satisfies("1.26.4", "~=1.21"))
+	assert.False(t, version.Satisfies("2.0.0", "~=1.21"))
+	assert.True(t, version.Satisfies("1.4.9", "~=1.4.5"))
+	assert.False(t, version.Satisfies("1.5.0", "~=1.4.5"))
+}
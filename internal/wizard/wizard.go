@@ -0,0 +1,364 @@
+package wizard
+
+import (
+	"bufio"
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/gitlab"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaseURL is offered when neither a flag nor GITLAB_BASE_URL provides the GitLab URL
+const DefaultBaseURL = "https://gitlab.com"
+
+// maxConnectAttempts bounds how often credentials are asked for before giving up
+const maxConnectAttempts = 3
+
+// ErrAuthentication is returned when no entered credentials passed the permission check
+var ErrAuthentication = errors.New("GitLab rejected the credentials")
+
+// Browser lists what a validated token can access
+type Browser interface {
+	CheckPermissions(ctx context.Context) error
+	ListLocations(ctx context.Context, search string) ([]gitlab.Location, error)
+}
+
+// Connector creates a Browser for the entered GitLab URL and token
+type Connector func(baseURL, token string) (Browser, error)
+
+// Defaults pre-fill prompts, typically from GITLAB_BASE_URL and GITLAB_TOKEN
+type Defaults struct {
+	BaseURL string
+	Token   string
+}
+
+// Result holds the answers needed to write a configuration
+type Result struct {
+	BaseURL      string
+	Token        string
+	StoreToken   bool // Write the token to the file instead of relying on GITLAB_TOKEN
+	Repositories []string
+	Placeholder  bool // Repositories is an example to edit, no internal namespaces are derived from it
+}
+
+// Wizard asks for GitLab credentials and repositories on a line-oriented terminal
+type Wizard struct {
+	in      *bufio.Reader
+	out     io.Writer
+	connect Connector
+}
+
+// New creates a wizard reading answers from in and writing prompts to out
+func New(in io.Reader, out io.Writer, connect Connector) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out, connect: connect}
+}
+
+// Run walks through credentials, validation and repository selection
+func (w *Wizard) Run(ctx context.Context, defaults Defaults) (*Result, error) {
+	if defaults.BaseURL == "" {
+		defaults.BaseURL = DefaultBaseURL
+	}
+
+	result, browser, err := w.askCredentials(ctx, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	repositories, err := w.askRepositories(ctx, browser)
+	if err != nil {
+		return nil, err
+	}
+	result.Repositories = repositories
+
+	// Tokens in files leak through backups and commits, prefer the environment
+	storeToken, err := w.confirm("Store the token in the config file? Otherwise GITLAB_TOKEN is required", false)
+	if err != nil {
+		return nil, err
+	}
+	result.StoreToken = storeToken
+
+	return result, nil
+}
+
+// askCredentials prompts for the GitLab URL and token until the permission check passes
+func (w *Wizard) askCredentials(ctx context.Context, defaults Defaults) (*Result, Browser, error) {
+	for attempt := 1; attempt <= maxConnectAttempts; attempt++ {
+		baseURL, err := w.ask("GitLab URL", defaults.BaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tokenLabel := "GitLab token"
+		if defaults.Token != "" {
+			tokenLabel = "GitLab token (empty keeps GITLAB_TOKEN)"
+		}
+		token, err := w.askSecret(tokenLabel, defaults.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+		if token == "" {
+			_, _ = fmt.Fprintln(w.out, "A token is required.")
+			continue
+		}
+
+		browser, err := w.connect(baseURL, token)
+		if err == nil {
+			err = browser.CheckPermissions(ctx)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(w.out, "Could not verify the token: %v\n", err)
+			defaults.BaseURL = baseURL
+			continue
+		}
+
+		_, _ = fmt.Fprintln(w.out, "Token verified.")
+		return &Result{BaseURL: baseURL, Token: token}, browser, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w after %d attempts", ErrAuthentication, maxConnectAttempts)
+}
+
+// Discover returns the top-level groups the token can access, or its projects when it is a member of no group
+func Discover(ctx context.Context, browser Browser) ([]string, error) {
+	locations, err := browser.ListLocations(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var groups, projects []string
+	for _, location := range locations {
+		switch {
+		case location.Kind == gitlab.LocationGroup && !strings.Contains(location.Path, "/"):
+			groups = append(groups, location.URL)
+		case location.Kind == gitlab.LocationProject:
+			projects = append(projects, location.URL)
+		}
+	}
+	if len(groups) > 0 {
+		return groups, nil
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("the token can access no groups or projects")
+	}
+	return projects, nil
+}
+
+// askRepositories lists accessible groups and projects and lets the user pick some of them
+func (w *Wizard) askRepositories(ctx context.Context, browser Browser) ([]string, error) {
+	for {
+		search, err := w.ask("Filter groups and projects (empty lists all)", "")
+		if err != nil {
+			return nil, err
+		}
+
+		locations, err := browser.ListLocations(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		if len(locations) == 0 {
+			_, _ = fmt.Fprintln(w.out, "Nothing found, try another filter.")
+			continue
+		}
+
+		for i, location := range locations {
+			_, _ = fmt.Fprintf(w.out, "%4d) %-7s  %s\n", i+1, location.Kind, location.Path)
+		}
+
+		answer, err := w.ask("Select entries (e.g. 1,3,5-7; empty searches again)", "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			continue
+		}
+
+		indexes, err := ParseSelection(answer, len(locations))
+		if err != nil {
+			_, _ = fmt.Fprintf(w.out, "Invalid selection: %v\n", err)
+			continue
+		}
+
+		repositories := make([]string, 0, len(indexes))
+		for _, index := range indexes {
+			repositories = append(repositories, locations[index].URL)
+		}
+		return repositories, nil
+	}
+}
+
+// ask prints a prompt and returns the trimmed answer or the default when it is empty
+func (w *Wizard) ask(label, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		_, _ = fmt.Fprintf(w.out, "%s [%s]: ", label, defaultValue)
+	} else {
+		_, _ = fmt.Fprintf(w.out, "%s: ", label)
+	}
+	return w.readAnswer(defaultValue)
+}
+
+// askSecret is like ask but never echoes the default value
+func (w *Wizard) askSecret(label, defaultValue string) (string, error) {
+	_, _ = fmt.Fprintf(w.out, "%s: ", label)
+	return w.readAnswer(defaultValue)
+}
+
+func (w *Wizard) readAnswer(defaultValue string) (string, error) {
+	line, err := w.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes/no question
+func (w *Wizard) confirm(label string, defaultValue bool) (bool, error) {
+	hint := "y/N"
+	if defaultValue {
+		hint = "Y/n"
+	}
+	answer, err := w.ask(fmt.Sprintf("%s (%s)", label, hint), "")
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "":
+		return defaultValue, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// ParseSelection parses 1-based numbers and ranges such as "1,3,5-7" into unique 0-based indexes in input order
+func ParseSelection(answer string, count int) ([]int, error) {
+	var indexes []int
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", part)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+				return nil, fmt.Errorf("%q is not a range", part)
+			}
+		}
+		if from < 1 || to > count || from > to {
+			return nil, fmt.Errorf("%q is outside 1-%d", part, count)
+		}
+
+		for number := from; number <= to; number++ {
+			if !seen[number] {
+				seen[number] = true
+				indexes = append(indexes, number-1)
+			}
+		}
+	}
+
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("nothing selected")
+	}
+	return indexes, nil
+}
+
+// fileConfig is the subset of the configuration the wizard writes, everything else keeps its default
+type fileConfig struct {
+	GitLab struct {
+		BaseURL string `yaml:"base_url"`
+		Token   string `yaml:"token,omitempty"`
+	} `yaml:"gitlab"`
+	Repositories []config.RepositoryConfig `yaml:"repositories"`
+	Internal     struct {
+		Domains  []string `yaml:"domains"`
+		Patterns []string `yaml:"patterns"`
+	} `yaml:"internal"`
+}
+
+// Namespaces returns the top-level GitLab namespaces of the repository URLs in first-seen order.
+// URLs on other hosts than baseURL are left out.
+func Namespaces(baseURL string, repositories []string) []string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	basePath := strings.Trim(base.Path, "/")
+
+	var namespaces []string
+	for _, repositoryURL := range repositories {
+		parsed, err := url.Parse(repositoryURL)
+		if err != nil || !strings.EqualFold(parsed.Host, base.Host) {
+			continue
+		}
+		path := strings.Trim(parsed.Path, "/")
+		if basePath != "" {
+			path = strings.TrimPrefix(strings.TrimPrefix(path, basePath), "/")
+		}
+		namespace, _, _ := strings.Cut(path, "/")
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// Render returns the YAML configuration for the wizard answers
+func Render(result *Result) ([]byte, error) {
+	var file fileConfig
+	file.GitLab.BaseURL = result.BaseURL
+	if result.StoreToken {
+		file.GitLab.Token = result.Token
+	}
+	for _, repositoryURL := range result.Repositories {
+		file.Repositories = append(file.Repositories, config.RepositoryConfig{URL: repositoryURL})
+	}
+
+	// Go modules and git dependencies hosted in the namespaces, and npm packages scoped to them, are internal
+	file.Internal.Domains = []string{}
+	file.Internal.Patterns = []string{}
+	host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(result.BaseURL, "https://"), "http://"), "/")
+	var namespaces []string
+	if !result.Placeholder {
+		namespaces = Namespaces(result.BaseURL, result.Repositories)
+	}
+	for _, namespace := range namespaces {
+		file.Internal.Domains = append(file.Internal.Domains, host+"/"+namespace)
+		file.Internal.Patterns = append(file.Internal.Patterns, "@"+namespace+"/")
+	}
+
+	content, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render configuration: %w", err)
+	}
+
+	header := "# Generated by di-matrix-cli init, see config.example.yaml for all settings\n"
+	if len(file.Internal.Domains) > 0 {
+		header += "# internal.domains and internal.patterns are derived from the repository namespaces\n"
+	}
+	if !result.StoreToken {
+		header += "# gitlab.token is read from the GITLAB_TOKEN environment variable\n"
+	}
+	return append([]byte(header), content...), nil
+}
@@ -0,0 +1,187 @@
+package wizard_test
+
+import (
+	"bytes"
+	"context"
+	"di-matrix-cli/internal/config"
+	"di-matrix-cli/internal/gitlab"
+	"di-matrix-cli/internal/wizard"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBrowser struct {
+	token    string
+	searches []string
+}
+
+func (b *fakeBrowser) CheckPermissions(ctx context.Context) error {
+	if b.token != "good-token" {
+		return errors.New("401 Unauthorized")
+	}
+	return nil
+}
+
+func (b *fakeBrowser) ListLocations(ctx context.Context, search string) ([]gitlab.Location, error) {
+	b.searches = append(b.searches, search)
+	if search == "none" {
+		return nil, nil
+	}
+	return []gitlab.Location{
+		{Kind: gitlab.LocationGroup, Path: "platform", URL: "https://gitlab.example.com/platform"},
+		{Kind: gitlab.LocationProject, Path: "web/app", URL: "https://gitlab.example.com/web/app"},
+		{Kind: gitlab.LocationProject, Path: "web/api", URL: "https://gitlab.example.com/web/api"},
+	}, nil
+}
+
+func TestWizard_Run(t *testing.T) {
+	t.Parallel()
+
+	browser := &fakeBrowser{}
+	var connectedTo []string
+	connect := func(baseURL, token string) (wizard.Browser, error) {
+		connectedTo = append(connectedTo, baseURL)
+		browser.token = token
+		return browser, nil
+	}
+
+	answers := strings.Join([]string{
+		"https://gitlab.example.com", "bad-token", // rejected, asked again
+		"", "good-token", // URL keeps the previous answer
+		"none",    // empty result, asked again
+		"",        // list everything
+		"1,9",     // out of range, asked again
+		"", "3,1", // list again and pick
+		"", // do not store the token
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	result, err := wizard.New(strings.NewReader(answers), &out, connect).
+		Run(context.Background(), wizard.Defaults{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://gitlab.example.com", "https://gitlab.example.com"}, connectedTo)
+	assert.Equal(t, "https://gitlab.example.com", result.BaseURL)
+	assert.Equal(t, "good-token", result.Token)
+	assert.False(t, result.StoreToken)
+	assert.Equal(t, []string{"https://gitlab.example.com/web/api", "https://gitlab.example.com/platform"},
+		result.Repositories)
+	assert.Equal(t, []string{"none", "", ""}, browser.searches)
+	assert.Contains(t, out.String(), "Could not verify the token")
+	assert.Contains(t, out.String(), "Invalid selection")
+}
+
+func TestWizard_Run_AuthenticationFailure(t *testing.T) {
+	t.Parallel()
+
+	connect := func(baseURL, token string) (wizard.Browser, error) {
+		return &fakeBrowser{token: token}, nil
+	}
+
+	// Every attempt keeps the default URL and token from the environment
+	answers := strings.Repeat("\n\n", 3)
+	_, err := wizard.New(strings.NewReader(answers), &bytes.Buffer{}, connect).
+		Run(context.Background(), wizard.Defaults{Token: "expired-token"})
+	require.ErrorIs(t, err, wizard.ErrAuthentication)
+}
+
+func TestParseSelection(t *testing.T) {
+	t.Parallel()
+
+	indexes, err := wizard.ParseSelection("3, 1-2,2", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 0, 1}, indexes)
+
+	for _, invalid := range []string{"", "0", "6", "a", "2-x", "4-2", ","} {
+		_, err := wizard.ParseSelection(invalid, 5)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	result := &wizard.Result{
+		BaseURL:      "https://gitlab.example.com",
+		Token:        "secret",
+		Repositories: []string{"https://gitlab.example.com/platform"},
+	}
+
+	content, err := wizard.Render(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+	assert.Contains(t, string(content), "GITLAB_TOKEN")
+
+	result.StoreToken = true
+	content, err = wizard.Render(result)
+	require.NoError(t, err)
+
+	// The written file loads with every other setting at its default
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.example.com/platform", cfg.Repositories[0].URL)
+	assert.Equal(t, []string{"gitlab.example.com/platform"}, cfg.Internal.Domains)
+	assert.Equal(t, []string{"@platform/"}, cfg.Internal.Patterns)
+	assert.True(t, cfg.Scanner.SkipVendored)
+	assert.Equal(t, "dependency-matrix.html", cfg.Output.HTMLFile)
+}
+
+func TestRender_Placeholder(t *testing.T) {
+	t.Parallel()
+
+	content, err := wizard.Render(&wizard.Result{
+		BaseURL:      "https://gitlab.example.com",
+		Token:        "secret",
+		StoreToken:   true,
+		Repositories: []string{"https://gitlab.example.com/your-group/your-repo"},
+		Placeholder:  true,
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "derived from the repository namespaces")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Internal.Domains, "the example repository names no internal namespace")
+	assert.Empty(t, cfg.Internal.Patterns)
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	repositories, err := wizard.Discover(context.Background(), &fakeBrowser{token: "good-token"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://gitlab.example.com/platform"}, repositories, "Groups are preferred over projects")
+
+	_, err = wizard.Discover(context.Background(), emptyBrowser{})
+	require.Error(t, err)
+}
+
+type emptyBrowser struct{}
+
+func (emptyBrowser) CheckPermissions(ctx context.Context) error { return nil }
+
+func (emptyBrowser) ListLocations(ctx context.Context, search string) ([]gitlab.Location, error) {
+	return nil, nil
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Parallel()
+
+	namespaces := wizard.Namespaces("https://example.com/gitlab/", []string{
+		"https://example.com/gitlab/platform/auth",
+		"https://example.com/gitlab/web",
+		"https://example.com/gitlab/platform",
+		"https://github.com/other/repo",
+	})
+	assert.Equal(t, []string{"platform", "web"}, namespaces)
+}